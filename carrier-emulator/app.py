@@ -93,11 +93,29 @@ def admin_reset():
     return {"status": "ok"}
 
 
+class SeedV1Place(BaseModel):
+    country: str | None = None
+    region: str | None = None
+    city: str | None = None
+    postal_code: str | None = None
+    lat: float | None = None
+    lon: float | None = None
+
+
 class SeedV1Step(BaseModel):
     status: str
     statusRaw: str
     location: str | None = None
     message: str | None = None
+    place: SeedV1Place | None = None
+
+
+class SeedV1Shipment(BaseModel):
+    weightGrams: int | None = None
+    origin: str | None = None
+    destination: str | None = None
+    recipientCity: str | None = None
+    serviceType: str | None = None
 
 
 class SeedV1Item(BaseModel):
@@ -106,6 +124,7 @@ class SeedV1Item(BaseModel):
     stepSeconds: int = 30
     progressProb: float = Field(0.25, ge=0.0, le=1.0)
     steps: list[SeedV1Step]
+    shipment: SeedV1Shipment | None = None
 
 
 class SeedV1Request(BaseModel):
@@ -127,6 +146,7 @@ def admin_seed_v1(req: SeedV1Request):
             "step_index": 0,
             "steps": [s.model_dump() for s in it.steps],
             "events": [],
+            "shipment": it.shipment.model_dump() if it.shipment else _default_shipment_for(it.carrier),
         }
     return {"status": "ok", "count": len(req.items)}
 
@@ -138,9 +158,13 @@ def maybe_delay_and_fail(delay_ms: int, fail_rate: float):
         raise HTTPException(status_code=503, detail="emulator: random failure")
 
 
+# Track24 отдаёт местное время места операции без пояса; все места эмулятора — в МСК (UTC+3, без перехода на летнее).
+MSK = timezone(timedelta(hours=3))
+
+
 def format_track24_dt(dt: datetime) -> str:
     # Track24 пример: "02.07.2014 19:16:00"
-    return dt.astimezone(timezone.utc).strftime("%d.%m.%Y %H:%M:%S")
+    return dt.astimezone(MSK).strftime("%d.%m.%Y %H:%M:%S")
 
 
 def _minute_key(carrier: str, now: datetime) -> str:
@@ -160,15 +184,17 @@ def _enforce_carrier_limit(carrier: str, now: datetime):
 def _default_steps_for(carrier: str) -> list[dict[str, Any]]:
     if carrier == "CDEK":
         return [
-            {"status": "IN_TRANSIT", "statusRaw": "CDEK: accepted", "location": "Moscow", "message": "Accepted"},
+            {"status": "IN_TRANSIT", "statusRaw": "CDEK: accepted", "location": "Moscow", "message": "Accepted",
+             "place": {"country": "RU", "city": "Moscow", "postal_code": "101000"}},
             {"status": "IN_TRANSIT", "statusRaw": "CDEK: in transit", "location": "Sorting center", "message": "In transit"},
-            {"status": "DELIVERED", "statusRaw": "CDEK: delivered", "location": "Destination", "message": "Delivered"},
+            {"status": "DELIVERED", "statusRaw": "CDEK: delivered", "location": "Destination", "message": "Delivered",
+             "place": {"country": "RU", "city": "Kazan", "postal_code": "420021"}},
         ]
     if carrier == "POST_RU":
         return [
-            {"status": "IN_TRANSIT", "statusRaw": "POST_RU: accepted", "location": "Москва", "message": "Принято"},
+            {"status": "IN_TRANSIT", "statusRaw": "POST_RU: accepted", "location": "Москва 101000", "message": "Принято"},
             {"status": "IN_TRANSIT", "statusRaw": "POST_RU: processing", "location": "СЦ", "message": "Обработка"},
-            {"status": "DELIVERED", "statusRaw": "POST_RU: delivered", "location": "Отделение", "message": "Вручено"},
+            {"status": "DELIVERED", "statusRaw": "POST_RU: delivered", "location": "Отделение 420021, Казань", "message": "Вручено"},
         ]
     return [
         {"status": "IN_TRANSIT", "statusRaw": "Accepted", "location": "Emulator", "message": "Accepted"},
@@ -176,6 +202,14 @@ def _default_steps_for(carrier: str) -> list[dict[str, Any]]:
     ]
 
 
+def _default_shipment_for(carrier: str) -> dict[str, Any]:
+    if carrier == "CDEK":
+        return {"weightGrams": 1200, "origin": "Moscow", "destination": "Kazan", "recipientCity": "Kazan", "serviceType": "door-to-door"}
+    if carrier == "POST_RU":
+        return {"weightGrams": 350, "origin": "Москва", "destination": "Казань", "recipientCity": "Казань", "serviceType": "Посылка 1 класса"}
+    return {}
+
+
 @app.get("/v1/tracking/{carrier}/{track_number}")
 def v1_tracking(
     carrier: str,
@@ -209,6 +243,7 @@ def v1_tracking(
             "step_index": 0,
             "steps": _default_steps_for(carrier),
             "events": [],
+            "shipment": _default_shipment_for(carrier),
         }
         TRACK_STATE[key] = st
 
@@ -236,6 +271,7 @@ def v1_tracking(
                     "location": step.get("location"),
                     "message": step.get("message"),
                     "payload": {"carrier": carrier},
+                    "place": step.get("place"),
                 }
             )
 
@@ -250,10 +286,11 @@ def v1_tracking(
                 "location": cur.get("location"),
                 "message": cur.get("message"),
                 "payload": {"carrier": carrier},
+                "place": cur.get("place"),
             }
         )
 
-    return {
+    resp = {
         "carrier": carrier,
         "track_number": track_number,
         "status": cur["status"],
@@ -261,6 +298,25 @@ def v1_tracking(
         "status_at": now.isoformat(),
         "events": st["events"],
     }
+    # Сведения об отправлении (как у реальных API: вес, откуда/куда, тариф)
+    ship = st.get("shipment") or {}
+    for src, dst in (
+        ("weightGrams", "weight_grams"),
+        ("origin", "origin"),
+        ("destination", "destination"),
+        ("recipientCity", "recipient_city"),
+        ("serviceType", "service_type"),
+    ):
+        if ship.get(src) is not None:
+            resp[dst] = ship[src]
+    # ETA-подсказка для планировщика: оставшиеся шаги * step_seconds от последнего продвижения
+    if cur["status"] != "DELIVERED":
+        remaining = len(steps) - 1 - idx
+        step_seconds = int(st.get("step_seconds") or 0)
+        if step_seconds > 0:
+            eta = st["last_advance_at"] + timedelta(seconds=remaining * step_seconds)
+            resp["estimated_delivery"] = eta.isoformat()
+    return resp
 
 
 @app.get("/tracking.json.php")
@@ -296,15 +352,15 @@ def track24_tracking(
                 {
                     "operationAttribute": "Принято в отделении связи",
                     "operationType": "Прием",
-                    "operationPlacePostalCode": "000000",
-                    "operationPlaceName": "Emulator City",
+                    "operationPlacePostalCode": "101000",
+                    "operationPlaceName": "Москва",
                     "source": "emulator",
                 },
                 {
                     "operationAttribute": "В пути",
                     "operationType": "Перевозка",
-                    "operationPlacePostalCode": "000000",
-                    "operationPlaceName": "Emulator City",
+                    "operationPlacePostalCode": "420300",
+                    "operationPlaceName": "Казань МСЦ",
                     "source": "emulator",
                 },
             ],
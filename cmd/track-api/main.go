@@ -2,15 +2,32 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/BearBump/TrackBox/internal/app"
+	"github.com/BearBump/TrackBox/internal/app/trackapi"
 )
 
+// track-api оставлен для Dockerfile/старых скриптов; то же самое — `trackbox api`.
 func main() {
-	app := mustBootstrapTrackAPI()
-	defer app.Close()
+	cfg, _, err := app.LoadConfig("")
+	if err != nil {
+		panic(fmt.Sprintf("ошибка парсинга конфига, %v", err))
+	}
 
-	if err := app.Run(); err != nil && err != context.Canceled {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	a, err := trackapi.New(ctx, cfg, trackapi.Options{SwaggerPath: os.Getenv("swaggerPath")})
+	if err != nil {
 		panic(err)
 	}
-}
-
+	defer a.Close()
 
+	if err := a.Run(ctx); err != nil && err != context.Canceled {
+		panic(err)
+	}
+}
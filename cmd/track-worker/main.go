@@ -7,11 +7,13 @@ import (
 	"os/signal"
 	"syscall"
 
-	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/app"
+	"github.com/BearBump/TrackBox/internal/app/trackworker"
 )
 
+// track-worker оставлен для Dockerfile/старых скриптов; то же самое — `trackbox worker`.
 func main() {
-	cfg, err := config.LoadConfig(os.Getenv("configPath"))
+	cfg, cfgPath, err := app.LoadConfig("")
 	if err != nil {
 		panic(fmt.Sprintf("ошибка парсинга конфига, %v", err))
 	}
@@ -19,9 +21,8 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	if err := RunTrackWorker(ctx, cfg, defaultWorkerFactories()); err != nil && err != context.Canceled {
+	opts := trackworker.Options{SwaggerPath: os.Getenv("swaggerPath"), ConfigPath: cfgPath}
+	if err := trackworker.Run(ctx, cfg, opts); err != nil && err != context.Canceled {
 		panic(err)
 	}
 }
-
-
@@ -0,0 +1,151 @@
+// trackbox — единый бинарник TrackBox: сервисы (api, worker) и служебные команды
+// (migrate, import, export, replay, reparse, inspect, trigger, config validate).
+//
+// Приоритет настроек: флаги > переменные окружения (TRACKBOX_*, configPath, swaggerPath) > файл конфига.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/app"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string, stdout, stderr io.Writer) error
+}
+
+var commands = []command{
+	{"api", "run track-api (gRPC + HTTP gateway + Kafka consumer)", runAPI},
+	{"worker", "run track-worker (poller + ops HTTP + internal gRPC)", runWorker},
+	{"migrate", "apply the database schema and exit", runMigrate},
+	{"import", "create trackings from a CSV/JSONL file", runImport},
+	{"export", "dump trackings as JSONL/CSV", runExport},
+	{"replay", "republish stored tracking state to Kafka", runReplay},
+	{"reparse", "re-run response parsing on archived carrier responses and publish corrections", runReparse},
+	{"inspect tracking", "print a tracking with its events as JSON", runInspectTracking},
+	{"trigger", "wake up the worker poller (optionally re-queue trackings first)", runTrigger},
+	{"config validate", "load and validate the config, print the effective values", runConfigValidate},
+}
+
+// errUsage — неверный вызов; usage уже напечатан.
+var errUsage = errors.New("usage")
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	cancel()
+
+	switch {
+	case err == nil, errors.Is(err, context.Canceled):
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, "trackbox:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	cmd, rest, ok := findCommand(args)
+	if !ok {
+		if len(args) > 0 && args[0] != "help" && args[0] != "-h" && args[0] != "--help" {
+			fmt.Fprintf(stderr, "unknown command %q\n\n", strings.Join(args, " "))
+		}
+		usage(stderr)
+		return errUsage
+	}
+	return cmd.run(ctx, rest, stdout, stderr)
+}
+
+// findCommand поддерживает и двухсловные команды ("inspect tracking", "config validate").
+func findCommand(args []string) (command, []string, bool) {
+	for _, c := range commands {
+		words := strings.Fields(c.name)
+		if len(args) < len(words) {
+			continue
+		}
+		if strings.Join(args[:len(words)], " ") == c.name {
+			return c, args[len(words):], true
+		}
+	}
+	return command{}, nil, false
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: trackbox <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-18s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "trackbox <command> -h" for command flags.`)
+}
+
+// cmdFlags — FlagSet команды с общим флагом -config и флагами, перекрывающими значения конфига.
+type cmdFlags struct {
+	*flag.FlagSet
+	configPath string
+	overrides  map[string]func(*config.Config)
+}
+
+func newFlags(name string, stderr io.Writer) *cmdFlags {
+	fs := flag.NewFlagSet("trackbox "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	f := &cmdFlags{FlagSet: fs, overrides: map[string]func(*config.Config){}}
+	fs.StringVar(&f.configPath, "config", "", "path to config file (default: $configPath)")
+	return f
+}
+
+// stringOverride регистрирует строковый флаг, который при явном указании заменяет поле конфига.
+func (f *cmdFlags) stringOverride(name, usage string, field func(*config.Config) *string) {
+	v := f.String(name, "", usage)
+	f.overrides[name] = func(c *config.Config) { *field(c) = *v }
+}
+
+// intOverride — то же для целых значений.
+func (f *cmdFlags) intOverride(name, usage string, field func(*config.Config) *int) {
+	v := f.Int(name, 0, usage)
+	f.overrides[name] = func(c *config.Config) { *field(c) = *v }
+}
+
+// applyOverrides применяет только явно заданные флаги (flag.Visit), значения флагов по умолчанию конфиг не трогают.
+func (f *cmdFlags) applyOverrides(c *config.Config) {
+	f.Visit(func(fl *flag.Flag) {
+		if apply, ok := f.overrides[fl.Name]; ok {
+			apply(c)
+		}
+	})
+}
+
+// loadConfig читает конфиг (env-переопределения применяет сам config), затем флаги, и валидирует результат.
+func (f *cmdFlags) loadConfig() (*config.Config, string, error) {
+	cfg, path, err := app.LoadConfig(f.configPath)
+	if err != nil {
+		return nil, path, err
+	}
+	f.applyOverrides(cfg)
+	if err := cfg.Validate(); err != nil {
+		return nil, path, fmt.Errorf("invalid config after flag overrides: %w", err)
+	}
+	return cfg, path, nil
+}
+
+// envOr — значение по умолчанию для флага из переменной окружения.
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
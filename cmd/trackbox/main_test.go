@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/app/trackworker"
+	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(body), 0o600))
+	return p
+}
+
+func TestRun_UnknownCommandPrintsUsage(t *testing.T) {
+	var out, errOut bytes.Buffer
+	err := run(context.Background(), []string{"inspect", "carrier"}, &out, &errOut)
+	require.ErrorIs(t, err, errUsage)
+	require.Contains(t, errOut.String(), `unknown command "inspect carrier"`)
+	require.Contains(t, errOut.String(), "config validate")
+
+	errOut.Reset()
+	require.ErrorIs(t, run(context.Background(), nil, &out, &errOut), errUsage)
+	require.NotContains(t, errOut.String(), "unknown command")
+}
+
+func TestFindCommand_TwoWords(t *testing.T) {
+	c, rest, ok := findCommand([]string{"inspect", "tracking", "-events", "5", "42"})
+	require.True(t, ok)
+	require.Equal(t, "inspect tracking", c.name)
+	require.Equal(t, []string{"-events", "5", "42"}, rest)
+
+	_, _, ok = findCommand([]string{"config"})
+	require.False(t, ok)
+}
+
+func TestConfigValidate_FlagBeatsEnvBeatsFile(t *testing.T) {
+	path := writeConfig(t, `
+trackbox:
+  worker_batch_size: 10
+  worker_concurrency: 3
+database:
+  password: "secret"
+`)
+	t.Setenv("configPath", "/does/not/exist.yaml")
+	t.Setenv("TRACKBOX_WORKER_BATCH_SIZE", "20")
+	t.Setenv("TRACKBOX_WORKER_CONCURRENCY", "4")
+
+	f := newFlags("worker", &bytes.Buffer{})
+	f.intOverride("batch-size", "", func(c *config.Config) *int { return &c.TrackBox.WorkerBatchSize })
+	f.intOverride("concurrency", "", func(c *config.Config) *int { return &c.TrackBox.WorkerConcurrency })
+	require.NoError(t, f.Parse([]string{"-config", path, "-batch-size", "30"}))
+
+	cfg, gotPath, err := f.loadConfig()
+	require.NoError(t, err)
+	require.Equal(t, path, gotPath)
+	require.Equal(t, 30, cfg.TrackBox.WorkerBatchSize)  // flag
+	require.Equal(t, 4, cfg.TrackBox.WorkerConcurrency) // env, flag not given
+}
+
+func TestConfigValidate_Command(t *testing.T) {
+	path := writeConfig(t, `
+database:
+  password: "secret"
+trackbox:
+  worker_batch_size: 10
+`)
+	var out bytes.Buffer
+	require.NoError(t, run(context.Background(), []string{"config", "validate", "-config", path, "-print"}, &out, &bytes.Buffer{}))
+	require.Contains(t, out.String(), path+": ok")
+	require.Contains(t, out.String(), "worker_batch_size: 10")
+	require.NotContains(t, out.String(), "secret")
+
+	bad := writeConfig(t, "trackbox:\n  worker_planner: magic\n")
+	err := run(context.Background(), []string{"config", "validate", "-config", bad}, &out, &bytes.Buffer{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "worker_planner")
+}
+
+func TestConfigValidate_InvalidFlagOverride(t *testing.T) {
+	path := writeConfig(t, "")
+	f := newFlags("worker", &bytes.Buffer{})
+	f.intOverride("batch-size", "", func(c *config.Config) *int { return &c.TrackBox.WorkerBatchSize })
+	require.NoError(t, f.Parse([]string{"-config", path, "-batch-size", "-1"}))
+	_, _, err := f.loadConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "flag overrides")
+}
+
+func TestReplayMessage(t *testing.T) {
+	checked := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	payload := `{"a":1}`
+	tr := &models.Tracking{ID: 5, Status: models.TrackingStatusInTransit, StatusRaw: "RAW", LastCheckedAt: &checked, NextCheckAt: checked.Add(time.Hour)}
+	msg := replayMessage(tr, []*models.TrackingEvent{{Status: models.TrackingStatusInTransit, StatusRaw: "RAW", EventTime: checked, PayloadJSON: &payload}})
+
+	require.Equal(t, uint64(5), msg.TrackingID)
+	require.Equal(t, checked, msg.CheckedAt)
+	require.Equal(t, checked.Add(time.Hour), msg.NextCheckAt)
+	require.Nil(t, msg.Error)
+	require.Len(t, msg.Events, 1)
+	require.JSONEq(t, payload, string(msg.Events[0].Payload))
+}
+
+func TestReparseMessage(t *testing.T) {
+	checked := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	next := checked.Add(2 * time.Hour)
+	rp := trackworker.RebuildPoller(&config.Config{})
+	body := []byte(`{"status":"ok","data":{"events":[
+  {"operationDateTime":"01.01.2026 10:00:00","operationAttribute":"Принято","operationPlaceName":"Москва"},
+  {"operationDateTime":"02.01.2026 12:00:00","operationAttribute":"Вручение адресату","operationPlaceName":"Казань"}
+]}}`)
+	c := &models.TrackingCheck{TrackingID: 5, CheckedAt: checked, Backend: models.CarrierBackendTrack24}
+
+	// Последняя проверка упала на разборе, теперь ответ разбирается: трек получает статус.
+	tr := &models.Tracking{ID: 5, CarrierCode: "POST_RU", Status: models.TrackingStatusUnknown, CheckFailCount: 1, NextCheckAt: next}
+	msg, changed, err := reparseMessage(rp, tr, c, body)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, checked, msg.CheckedAt)
+	require.Equal(t, next, msg.NextCheckAt)
+	require.Equal(t, models.TrackingStatusDelivered, msg.Status)
+	require.Equal(t, messages.KindStatusChanged, msg.Kind)
+	require.Len(t, msg.Events, 2)
+
+	// Тот же результат, что уже сохранён, — публиковать нечего.
+	tr = &models.Tracking{ID: 5, CarrierCode: "POST_RU", Status: msg.Status, StatusRaw: msg.StatusRaw, Fingerprint: msg.Fingerprint}
+	_, changed, err = reparseMessage(rp, tr, c, body)
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	_, _, err = reparseMessage(rp, tr, c, []byte(`{"status":"error"}`))
+	require.Error(t, err)
+	_, _, err = reparseMessage(rp, tr, &models.TrackingCheck{Backend: models.CarrierBackendFake}, body)
+	require.ErrorContains(t, err, `no parser for backend "fake"`)
+}
+
+func TestParseIDs(t *testing.T) {
+	ids, err := parseIDs(" 1, 2 ,3")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2, 3}, ids)
+
+	ids, err = parseIDs("")
+	require.NoError(t, err)
+	require.Nil(t, ids)
+
+	_, err = parseIDs("1,x")
+	require.Error(t, err)
+	_, err = parseIDs("0")
+	require.Error(t, err)
+}
+
+func TestWorkerBaseURL(t *testing.T) {
+	require.Equal(t, "http://localhost:8082", workerBaseURL(":8082"))
+	require.Equal(t, "http://localhost:8082", workerBaseURL("0.0.0.0:8082"))
+	require.Equal(t, "http://worker:8082", workerBaseURL("worker:8082"))
+}
+
+func TestTrigger_PostsToWorker(t *testing.T) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = r.Method == http.MethodPost && r.URL.Path == "/trigger"
+		_, _ = w.Write([]byte(`{"triggered":true}`))
+	}))
+	defer srv.Close()
+
+	path := writeConfig(t, "")
+	var out bytes.Buffer
+	require.NoError(t, run(context.Background(), []string{"trigger", "-config", path, "-worker-url", srv.URL + "/"}, &out, &bytes.Buffer{}))
+	require.True(t, called)
+	require.Contains(t, out.String(), `"triggered":true`)
+}
+
+func TestImport_DryRunNeedsNoDatabase(t *testing.T) {
+	in := filepath.Join(t.TempDir(), "trackings.jsonl")
+	require.NoError(t, os.WriteFile(in, []byte(`{"carrier_code":"CDEK","track_number":"1000000001"}`+"\n"+`{"carrier_code":"CDEK"}`+"\n"), 0o600))
+
+	var out bytes.Buffer
+	require.NoError(t, run(context.Background(), []string{"import", "-dry-run", in}, &out, &bytes.Buffer{}))
+	require.Equal(t, "line 2: track_number is required\n2 rows, 1 invalid (dry run)\n", out.String())
+
+	err := run(context.Background(), []string{"import"}, &out, &bytes.Buffer{})
+	require.ErrorIs(t, err, errUsage)
+	err = run(context.Background(), []string{"import", "-status", "1", in}, &out, &bytes.Buffer{})
+	require.ErrorIs(t, err, errUsage)
+}
@@ -0,0 +1,515 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/app"
+	"github.com/BearBump/TrackBox/internal/broker/kafka"
+	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/cache/rediscache"
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/services/bulk"
+	"github.com/BearBump/TrackBox/internal/services/carriers"
+	"github.com/BearBump/TrackBox/internal/services/trackings"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"go.yaml.in/yaml/v4"
+)
+
+// scanPageSize — размер страницы ScanTrackings для replay.
+const scanPageSize = 1000
+
+func runMigrate(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	f := newFlags("migrate", stderr)
+	wait := f.Duration("wait", 0, "keep retrying the database connection for this long")
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+	cfg, _, err := f.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Схема применяется при подключении (pgtracking.New), отдельный шаг нужен для деплоя до старта сервисов.
+	st, err := app.OpenStorage(ctx, cfg, *wait)
+	if err != nil {
+		return err
+	}
+	st.Close()
+	fmt.Fprintf(stdout, "schema is up to date (%s/%s)\n", cfg.Database.Host, cfg.Database.DBName)
+	return nil
+}
+
+func runImport(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	f := newFlags("import", stderr)
+	format := f.String("format", "", "csv|jsonl (default: by file extension, csv for stdin)")
+	batch := f.Int("batch", 0, "trackings per insert (default: 500)")
+	dryRun := f.Bool("dry-run", false, "parse and validate the file without writing")
+	detach := f.Bool("detach", false, "only upload the job; track-api processes it in the background")
+	resume := f.Uint64("resume", 0, "re-queue a FAILED job and continue it from where it stopped")
+	status := f.Uint64("status", 0, "print job progress and row errors")
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+	if (*resume == 0 && *status == 0) != (f.NArg() == 1) {
+		fmt.Fprintln(stderr, "usage: trackbox import [flags] <file|->\n       trackbox import -resume <job id>\n       trackbox import -status <job id>")
+		return errUsage
+	}
+
+	var in io.Reader
+	var fmtName string
+	if f.NArg() == 1 {
+		path := f.Arg(0)
+		var err error
+		if fmtName, err = bulk.DetectFormat(*format, path, bulk.FormatCSV); err != nil {
+			return err
+		}
+		in = os.Stdin
+		if path != "-" {
+			fh, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer fh.Close()
+			in = fh
+		}
+	}
+	if *dryRun {
+		if in == nil {
+			return fmt.Errorf("-dry-run needs a file")
+		}
+		total, bad, err := bulk.ValidateImport(in, fmtName)
+		if err != nil {
+			return err
+		}
+		printRowErrors(stdout, bad, len(bad))
+		fmt.Fprintf(stdout, "%d rows, %d invalid (dry run)\n", total, len(bad))
+		return nil
+	}
+
+	cfg, _, err := f.loadConfig()
+	if err != nil {
+		return err
+	}
+	st, err := app.OpenStorage(ctx, cfg, 0)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+	// Номера проверяются по справочнику перевозчиков из БД (как в track-api).
+	carrierSvc := carriers.New(st)
+	if err := carrierSvc.Refresh(ctx); err != nil {
+		return fmt.Errorf("load carriers: %w", err)
+	}
+	// Треки создаются через trackings.Service: созданные сбрасываются из кэша текущего состояния track-api
+	// (и из кэшей реплик, если задан current_status_l1), иначе запрошенный до импорта номер ещё какое-то время не находится.
+	trackingsSvc := trackings.New(st, rediscache.New(app.RedisAddr(cfg)), 0).WithCarriers(carrierSvc.Registry)
+	if l := cfg.TrackBox.CurrentStatusL1; l != nil {
+		inv := rediscache.NewInvalidations(app.RedisAddr(cfg), l.InvalidationChannel)
+		defer func() { _ = inv.Close() }()
+		trackingsSvc.WithInvalidator(inv)
+	}
+	svc := bulk.New(st, bulk.Config{BatchSize: *batch}).WithCarriers(carrierSvc.Registry).WithTrackings(trackingsSvc)
+
+	var job *models.ImportJob
+	switch {
+	case *status != 0:
+		if job, err = svc.GetImportJob(ctx, *status); err != nil {
+			return err
+		}
+	case *resume != 0:
+		if job, err = svc.ResumeImport(ctx, *resume); err != nil {
+			return fmt.Errorf("resume job %d: %w", *resume, err)
+		}
+	default:
+		if job, err = svc.StartImport(ctx, in, fmtName, f.Arg(0)); err != nil {
+			return err
+		}
+		fmt.Fprintf(stderr, "job %d: uploaded %d rows (%d invalid)\n", job.ID, job.TotalRows, job.InvalidRows)
+	}
+
+	if *status == 0 && !*detach {
+		if job, err = svc.ProcessJob(ctx, job.ID); err != nil {
+			return fmt.Errorf("%w (continue with: trackbox import -resume %d, or let track-api pick it up)", err, job.ID)
+		}
+	}
+	if job.InvalidRows > 0 {
+		bad, err := svc.ListImportErrors(ctx, job.ID, 20, 0)
+		if err != nil {
+			return err
+		}
+		printRowErrors(stdout, bad, int(job.InvalidRows))
+	}
+	fmt.Fprintf(stdout, "job %d: %s, %d/%d rows processed, %d imported, %d invalid\n",
+		job.ID, job.Status, job.ProcessedRows, job.TotalRows, job.ImportedRows, job.InvalidRows)
+	return nil
+}
+
+// printRowErrors печатает до 20 строк с ошибками; total — сколько их всего.
+func printRowErrors(w io.Writer, rows []models.ImportRow, total int) {
+	shown := min(len(rows), 20)
+	for _, r := range rows[:shown] {
+		fmt.Fprintln(w, bulk.RowError(r))
+	}
+	if total > shown {
+		fmt.Fprintf(w, "... and %d more\n", total-shown)
+	}
+}
+
+func runExport(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	f := newFlags("export", stderr)
+	format := f.String("format", "", "jsonl|csv (default: by -out extension, jsonl for stdout)")
+	out := f.String("out", "-", "output file, - for stdout")
+	carrierCode := f.String("carrier", "", "only this carrier")
+	status := f.String("status", "", "only this normalized status")
+	updatedSince := f.String("updated-since", "", "only trackings updated at or after this RFC3339 time")
+	tags := f.String("tags", "", "only trackings having all these comma-separated tags")
+	externalID := f.String("external-id", "", "only trackings with this external id")
+	events := f.Bool("events", false, "include tracking events")
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+	opts := bulk.ExportOptions{
+		Filter: pgtracking.TrackingFilter{CarrierCode: *carrierCode, Status: *status, ExternalID: *externalID},
+		Events: *events,
+	}
+	for _, t := range strings.Split(*tags, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			opts.Filter.Tags = append(opts.Filter.Tags, t)
+		}
+	}
+	var err error
+	if opts.Format, err = bulk.DetectFormat(*format, *out, bulk.FormatJSONL); err != nil {
+		return err
+	}
+	if *updatedSince != "" {
+		ts, err := time.Parse(time.RFC3339, *updatedSince)
+		if err != nil {
+			return fmt.Errorf("-updated-since: %w", err)
+		}
+		opts.Filter.UpdatedSince = &ts
+	}
+
+	cfg, _, err := f.loadConfig()
+	if err != nil {
+		return err
+	}
+	st, err := app.OpenStorage(ctx, cfg, 0)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+
+	w := stdout
+	if *out != "-" {
+		fh, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		w = fh
+	}
+	n, err := bulk.New(st, bulk.Config{}).Export(ctx, w, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "exported %d trackings\n", n)
+	return nil
+}
+
+// replay нужен, когда track-api пропустил обновления (упал consumer, чистили Redis):
+// текущее состояние из БД публикуется в Kafka как обычный TrackingUpdated.
+// Повтор безопасен: события дедуплицируются при записи, статус совпадает с сохранённым.
+func runReplay(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	f := newFlags("replay", stderr)
+	ids := f.String("ids", "", "comma-separated tracking ids (default: all matching -carrier/-status)")
+	carrierCode := f.String("carrier", "", "only this carrier")
+	status := f.String("status", "", "only this normalized status")
+	maxEvents := f.Int("events", 100, "max events per tracking to include")
+	dryRun := f.Bool("dry-run", false, "print messages instead of publishing")
+	f.stringOverride("topic", "Kafka topic (kafka.tracking_updated_topic_name)", func(c *config.Config) *string { return &c.Kafka.TrackingUpdatedTopicName })
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+	idList, err := parseIDs(*ids)
+	if err != nil {
+		return err
+	}
+
+	cfg, _, err := f.loadConfig()
+	if err != nil {
+		return err
+	}
+	st, err := app.OpenStorage(ctx, cfg, 0)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+
+	var producer *kafka.Producer
+	if !*dryRun {
+		producer = kafka.NewProducer(app.KafkaBrokers(cfg))
+	}
+	topic := cfg.Kafka.TrackingUpdatedTopicName
+
+	published, skipped := 0, 0
+	replay := func(t *models.Tracking) error {
+		if t.LastCheckedAt == nil || t.CheckFailCount > 0 {
+			// Проверок не было — публиковать нечего; последняя проверка с ошибкой —
+			// повтор как успешной обнулил бы check_fail_count, а как ошибочной — увеличил бы.
+			skipped++
+			return nil
+		}
+		evs, err := st.ListTrackingEvents(ctx, t.ID, pgtracking.EventFilter{}, *maxEvents, 0)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(replayMessage(t, evs))
+		if err != nil {
+			return err
+		}
+		if *dryRun {
+			fmt.Fprintln(stdout, string(b))
+		} else if err := producer.Publish(ctx, topic, []byte(strconv.FormatUint(t.ID, 10)), b); err != nil {
+			return err
+		}
+		published++
+		return nil
+	}
+
+	if len(idList) > 0 {
+		ts, err := st.GetTrackingsByIDs(ctx, idList)
+		if err != nil {
+			return err
+		}
+		for _, t := range ts {
+			if err := replay(t); err != nil {
+				return err
+			}
+		}
+	} else if err := scanTrackings(ctx, st, pgtracking.TrackingFilter{CarrierCode: *carrierCode, Status: *status}, replay); err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "replayed %d trackings to %s, skipped %d (never checked or failing)\n", published, topic, skipped)
+	return nil
+}
+
+// replayMessage собирает TrackingUpdated из сохранённого состояния трека.
+func replayMessage(t *models.Tracking, evs []*models.TrackingEvent) messages.TrackingUpdated {
+	msg := messages.TrackingUpdated{
+		TrackingID:  t.ID,
+		Status:      t.Status,
+		StatusRaw:   t.StatusRaw,
+		StatusAt:    t.StatusAt,
+		NextCheckAt: t.NextCheckAt,
+	}
+	if t.LastCheckedAt != nil {
+		msg.CheckedAt = *t.LastCheckedAt
+	}
+	for _, e := range evs {
+		var payload json.RawMessage
+		if e.PayloadJSON != nil && *e.PayloadJSON != "" {
+			payload = json.RawMessage(*e.PayloadJSON)
+		}
+		msg.Events = append(msg.Events, messages.TrackingEvent{
+			Status:    e.Status,
+			StatusRaw: e.StatusRaw,
+			EventTime: e.EventTime,
+			Location:  e.Location,
+			Message:   e.Message,
+			Payload:   payload,
+		})
+	}
+	return msg
+}
+
+func runInspectTracking(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	f := newFlags("inspect tracking", stderr)
+	maxEvents := f.Int("events", 50, "max events to print")
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+	if f.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: trackbox inspect tracking [flags] <id>")
+		return errUsage
+	}
+	id, err := strconv.ParseUint(f.Arg(0), 10, 64)
+	if err != nil || id == 0 {
+		return fmt.Errorf("invalid tracking id %q", f.Arg(0))
+	}
+
+	cfg, _, err := f.loadConfig()
+	if err != nil {
+		return err
+	}
+	st, err := app.OpenStorage(ctx, cfg, 0)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+
+	ts, err := st.GetTrackingsByIDs(ctx, []uint64{id})
+	if err != nil {
+		return err
+	}
+	if len(ts) == 0 {
+		return fmt.Errorf("tracking %d not found", id)
+	}
+	evs, err := st.ListTrackingEvents(ctx, id, pgtracking.EventFilter{}, *maxEvents, 0)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Tracking *models.Tracking
+		Events   []*models.TrackingEvent
+	}{ts[0], evs})
+}
+
+func runTrigger(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	f := newFlags("trigger", stderr)
+	workerURL := f.String("worker-url", "", "track-worker ops HTTP base URL (default: from trackbox.worker_http_addr)")
+	ids := f.String("ids", "", "comma-separated tracking ids to re-queue (next_check_at = now) before triggering")
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+	idList, err := parseIDs(*ids)
+	if err != nil {
+		return err
+	}
+
+	cfg, _, err := f.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(idList) > 0 {
+		st, err := app.OpenStorage(ctx, cfg, 0)
+		if err != nil {
+			return err
+		}
+		for _, id := range idList {
+			if err := st.RefreshTracking(ctx, id); err != nil {
+				st.Close()
+				return err
+			}
+		}
+		st.Close()
+		fmt.Fprintf(stdout, "re-queued %d trackings\n", len(idList))
+	}
+
+	base := *workerURL
+	if base == "" {
+		base = workerBaseURL(cfg.TrackBox.WorkerHTTPAddr)
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, strings.TrimRight(base, "/")+"/trigger", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("trigger worker: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("trigger worker: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	fmt.Fprintln(stdout, strings.TrimSpace(string(body)))
+	return nil
+}
+
+// workerBaseURL превращает адрес прослушивания (":8082", "0.0.0.0:8082") в URL для локального вызова.
+func workerBaseURL(listenAddr string) string {
+	host, port, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		return "http://" + listenAddr
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+func runConfigValidate(_ context.Context, args []string, stdout, stderr io.Writer) error {
+	f := newFlags("config validate", stderr)
+	show := f.Bool("print", false, "print the effective config (defaults, env and secrets applied; secrets masked)")
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+	cfg, path, err := f.loadConfig()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s: ok\n", path)
+	if !*show {
+		return nil
+	}
+
+	masked := *cfg
+	if masked.Database.Password != "" {
+		masked.Database.Password = "***"
+	}
+	if masked.TrackBox.CarrierEmulatorAPIKey != "" {
+		masked.TrackBox.CarrierEmulatorAPIKey = "***"
+	}
+	if a := masked.TrackBox.ResponseArchive; a != nil && a.S3.SecretKey != "" {
+		ac := *a
+		ac.S3.SecretKey = "***"
+		masked.TrackBox.ResponseArchive = &ac
+	}
+	b, err := yaml.Marshal(&masked)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(b)
+	return err
+}
+
+func parseIDs(s string) ([]uint64, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var out []uint64
+	for _, part := range strings.Split(s, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid tracking id %q", part)
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// scanTrackings обходит все подходящие треки постранично по id.
+func scanTrackings(ctx context.Context, st *pgtracking.Storage, filter pgtracking.TrackingFilter, fn func(*models.Tracking) error) error {
+	var after uint64
+	for {
+		page, err := st.ScanTrackings(ctx, filter, after, scanPageSize)
+		if err != nil {
+			return err
+		}
+		for _, t := range page {
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
+		if len(page) < scanPageSize {
+			return nil
+		}
+		after = page[len(page)-1].ID
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/app"
+	"github.com/BearBump/TrackBox/internal/app/trackworker"
+	"github.com/BearBump/TrackBox/internal/broker/kafka"
+	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier/emulatorv1"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier/track24http"
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/services/poller"
+	"github.com/BearBump/TrackBox/internal/storage/archive"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+)
+
+// reparseParsers — разбор архивных ответов по бэкенду из журнала проверок (tracking_checks.backend).
+var reparseParsers = map[string]carrier.Parser{
+	models.CarrierBackendEmulatorV1: emulatorv1.Parse,
+	models.CarrierBackendTrack24:    track24http.Parse,
+}
+
+// reparse нужен после исправления разбора ответов перевозчика: последний сохранённый ответ трека
+// разбирается текущим кодом и, если результат отличается от сохранённого, публикуется в Kafka как
+// TrackingUpdated с тем же checked_at. Берётся только последняя проверка трека — исправление более старой
+// перетёрлось бы следующими проверками. Без -apply ничего не публикуется.
+func runReparse(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	f := newFlags("reparse", stderr)
+	ids := f.String("ids", "", "comma-separated tracking ids (default: all matching -carrier/-status)")
+	carrierCode := f.String("carrier", "", "only this carrier")
+	status := f.String("status", "", "only this normalized status")
+	failing := f.Bool("failing", false, "only trackings whose last check failed (e.g. after a decoding fix)")
+	apply := f.Bool("apply", false, "publish corrected updates (default: print them)")
+	f.stringOverride("topic", "Kafka topic (kafka.tracking_updated_topic_name)", func(c *config.Config) *string { return &c.Kafka.TrackingUpdatedTopicName })
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+	idList, err := parseIDs(*ids)
+	if err != nil {
+		return err
+	}
+
+	cfg, _, err := f.loadConfig()
+	if err != nil {
+		return err
+	}
+	arch, err := app.OpenArchive(cfg)
+	if err != nil {
+		return err
+	}
+	if arch == nil {
+		return fmt.Errorf("trackbox.response_archive is not configured: there are no archived responses to reparse")
+	}
+	st, err := app.OpenStorage(ctx, cfg, 0)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+
+	var producer *kafka.Producer
+	if *apply {
+		producer = kafka.NewProducer(app.KafkaBrokers(cfg))
+	}
+	topic := cfg.Kafka.TrackingUpdatedTopicName
+	rp := trackworker.RebuildPoller(cfg)
+
+	var stats struct{ changed, unchanged, skipped, failed int }
+	reparse := func(t *models.Tracking) error {
+		if *failing && t.CheckFailCount == 0 {
+			return nil
+		}
+		c, err := lastCheck(ctx, st, t)
+		if err != nil {
+			return err
+		}
+		if c == nil || c.ResponseRef == "" {
+			stats.skipped++
+			return nil
+		}
+		body, err := arch.Load(ctx, c.ResponseRef)
+		if errors.Is(err, archive.ErrNotFound) {
+			stats.skipped++
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		msg, changed, err := reparseMessage(rp, t, c, body)
+		if err != nil {
+			fmt.Fprintf(stderr, "tracking %d: %s: %v\n", t.ID, c.ResponseRef, err)
+			stats.failed++
+			return nil
+		}
+		if !changed {
+			stats.unchanged++
+			return nil
+		}
+		stats.changed++
+		b, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		if !*apply {
+			fmt.Fprintln(stdout, string(b))
+			return nil
+		}
+		return producer.Publish(ctx, topic, []byte(strconv.FormatUint(t.ID, 10)), b)
+	}
+
+	if len(idList) > 0 {
+		ts, err := st.GetTrackingsByIDs(ctx, idList)
+		if err != nil {
+			return err
+		}
+		for _, t := range ts {
+			if err := reparse(t); err != nil {
+				return err
+			}
+		}
+	} else if err := scanTrackings(ctx, st, pgtracking.TrackingFilter{CarrierCode: *carrierCode, Status: *status}, reparse); err != nil {
+		return err
+	}
+
+	verb := "would publish"
+	if *apply {
+		verb = "published to " + topic
+	}
+	fmt.Fprintf(stderr, "%d changed (%s), %d unchanged, %d still failing, %d skipped (no archived response)\n",
+		stats.changed, verb, stats.unchanged, stats.failed, stats.skipped)
+	return nil
+}
+
+// lastCheck — запись журнала о последней проверке трека; nil — проверок не было или запись уже удалена.
+func lastCheck(ctx context.Context, st *pgtracking.Storage, t *models.Tracking) (*models.TrackingCheck, error) {
+	if t.LastCheckedAt == nil {
+		return nil, nil
+	}
+	checks, err := st.ListTrackingChecks(ctx, t.ID, false, time.Time{}, 1)
+	if err != nil || len(checks) == 0 || !checks[0].CheckedAt.Equal(*t.LastCheckedAt) {
+		return nil, err
+	}
+	return checks[0], nil
+}
+
+// reparseMessage разбирает архивный ответ текущим кодом и собирает исправленный TrackingUpdated.
+// changed — результат расходится с сохранённым треком (или проверка числилась неудачной, а ответ теперь разбирается).
+// Расписание трека не меняется: next_check_at берётся сохранённый.
+func reparseMessage(rp *poller.Poller, t *models.Tracking, c *models.TrackingCheck, body []byte) (messages.TrackingUpdated, bool, error) {
+	parse, ok := reparseParsers[c.Backend]
+	if !ok {
+		return messages.TrackingUpdated{}, false, fmt.Errorf("no parser for backend %q", c.Backend)
+	}
+	res, err := parse(body, c.CheckedAt)
+	if err != nil {
+		return messages.TrackingUpdated{}, false, err
+	}
+	msg := rp.Rebuild(t, res, c.CheckedAt)
+	msg.NextCheckAt = t.NextCheckAt
+	return msg, msg.Kind != messages.KindCheckedNoChange || t.CheckFailCount > 0, nil
+}
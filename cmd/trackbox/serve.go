@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/app/trackapi"
+	"github.com/BearBump/TrackBox/internal/app/trackworker"
+)
+
+func runAPI(ctx context.Context, args []string, _, stderr io.Writer) error {
+	f := newFlags("api", stderr)
+	swagger := f.String("swagger", envOr("swaggerPath", ""), "path to swagger.json (default: $swaggerPath)")
+	f.stringOverride("grpc-addr", "gRPC listen address (trackbox.grpc_addr)", func(c *config.Config) *string { return &c.TrackBox.GRPCAddr })
+	f.stringOverride("http-addr", "HTTP gateway listen address (trackbox.http_addr)", func(c *config.Config) *string { return &c.TrackBox.HTTPAddr })
+	f.stringOverride("worker-grpc-target", "track-worker gRPC target for CheckTrackingNow (trackbox.worker_grpc_target)", func(c *config.Config) *string { return &c.TrackBox.WorkerGRPCTarget })
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, _, err := f.loadConfig()
+	if err != nil {
+		return err
+	}
+	a, err := trackapi.New(ctx, cfg, trackapi.Options{SwaggerPath: *swagger})
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+	return a.Run(ctx)
+}
+
+func runWorker(ctx context.Context, args []string, _, stderr io.Writer) error {
+	f := newFlags("worker", stderr)
+	swagger := f.String("swagger", envOr("swaggerPath", ""), "path to swagger.json (default: $swaggerPath)")
+	noReload := f.Bool("no-reload", false, "disable config hot reload (SIGHUP / file change)")
+	f.stringOverride("http-addr", "ops HTTP listen address (trackbox.worker_http_addr)", func(c *config.Config) *string { return &c.TrackBox.WorkerHTTPAddr })
+	f.stringOverride("grpc-addr", "internal gRPC listen address (trackbox.worker_grpc_addr)", func(c *config.Config) *string { return &c.TrackBox.WorkerGRPCAddr })
+	f.stringOverride("planner", "scheduling strategy: static|history (trackbox.worker_planner)", func(c *config.Config) *string { return &c.TrackBox.WorkerPlanner })
+	f.intOverride("batch-size", "trackings claimed per poll (trackbox.worker_batch_size)", func(c *config.Config) *int { return &c.TrackBox.WorkerBatchSize })
+	f.intOverride("concurrency", "parallel carrier requests (trackbox.worker_concurrency)", func(c *config.Config) *int { return &c.TrackBox.WorkerConcurrency })
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, path, err := f.loadConfig()
+	if err != nil {
+		return err
+	}
+	opts := trackworker.Options{SwaggerPath: *swagger, Override: f.applyOverrides}
+	if !*noReload {
+		opts.ConfigPath = path
+	}
+	return trackworker.Run(ctx, cfg, opts)
+}
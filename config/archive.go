@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// ResponseArchiveConfig — архив сырых ответов перевозчиков (internal/storage/archive), его пишет track-worker,
+// читает trackbox reparse.
+//
+//	response_archive:
+//	  backend: "s3"                     # localfs | s3
+//	  dir: "./var/responses"            # для localfs
+//	  s3:
+//	    endpoint: "http://minio:9000"
+//	    bucket: "trackbox-responses"
+//	    access_key: "trackbox"
+//	    secret_key_file: "secrets/s3_secret_key"
+type ResponseArchiveConfig struct {
+	Backend string   `yaml:"backend"`
+	Dir     string   `yaml:"dir"`
+	S3      S3Config `yaml:"s3"`
+}
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint"`
+	Bucket    string `yaml:"bucket"`
+	Region    string `yaml:"region"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	// SecretKeyFile — путь к файлу с секретом; взаимоисключающе с secret_key.
+	SecretKeyFile string `yaml:"secret_key_file"`
+}
+
+// Validate проверяет блок response_archive (после значений по умолчанию).
+func (a *ResponseArchiveConfig) Validate() error {
+	if a == nil {
+		return nil
+	}
+	const key = "trackbox.response_archive"
+	var errs []error
+	switch a.Backend {
+	case "localfs":
+		if a.Dir == "" {
+			errs = append(errs, errors.New(key+".dir: required for localfs"))
+		}
+	case "s3":
+		if u, err := url.Parse(a.S3.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, errors.New(key+".s3.endpoint: want an absolute URL like http://minio:9000"))
+		}
+		if a.S3.Bucket == "" {
+			errs = append(errs, errors.New(key+".s3.bucket: required"))
+		}
+		if a.S3.AccessKey == "" || a.S3.SecretKey == "" {
+			errs = append(errs, errors.New(key+".s3: access_key and secret_key are required"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("%s.backend: unknown backend %q (want localfs|s3)", key, a.Backend))
+	}
+	return errors.Join(errs...)
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 
 	"go.yaml.in/yaml/v4"
 )
@@ -19,6 +23,8 @@ type DatabaseConfig struct {
 	Port     int    `yaml:"port"`
 	Username string `yaml:"username"`
 	Password string `yaml:"password"`
+	// PasswordFile — путь к файлу с паролем (Docker/K8s secrets); взаимоисключающе с password.
+	PasswordFile string `yaml:"password_file"`
 	DBName   string `yaml:"name"`
 	SSLMode  string `yaml:"ssl_mode"`
 }
@@ -27,6 +33,8 @@ type KafkaConfig struct {
 	Host                       string `yaml:"host"`
 	Port                       int    `yaml:"port"`
 	TrackingUpdatedTopicName   string `yaml:"tracking_updated_topic_name"`
+	// Нарушения SLA (открытие/закрытие алертов), см. trackbox.sla.
+	TrackingAlertTopicName     string `yaml:"tracking_alert_topic_name"`
 }
 
 type RedisConfig struct {
@@ -39,19 +47,37 @@ type TrackBoxConfig struct {
 	HTTPAddr          string `yaml:"http_addr"`
 	KafkaConsumerGroup string `yaml:"kafka_consumer_group"`
 	CurrentStatusTTLSeconds int `yaml:"current_status_ttl_seconds"`
+	// Сколько кэш помнит, что трека с таким id нет (защита БД от запросов несуществующих id).
+	CurrentStatusNegativeTTLSeconds int `yaml:"current_status_negative_ttl_seconds"`
+	// Кэш текущего состояния в памяти track-api перед Redis (см. l1cache.go); nil — только Redis.
+	CurrentStatusL1 *L1CacheConfig `yaml:"current_status_l1"`
+
+	// CheckTrackingNow: адрес внутреннего gRPC track-worker и сколько ждать его ответа.
+	WorkerGRPCTarget       string `yaml:"worker_grpc_target"`
+	CheckNowTimeoutSeconds int    `yaml:"check_now_timeout_seconds"`
 
 	WorkerPollIntervalSeconds int `yaml:"worker_poll_interval_seconds"`
 	WorkerBatchSize           int `yaml:"worker_batch_size"`
 	WorkerConcurrency         int `yaml:"worker_concurrency"`
 	WorkerLeaseSeconds        int `yaml:"worker_lease_seconds"`
 	WorkerRateLimitPerMinute  int `yaml:"worker_rate_limit_per_minute"`
+	// Устаревшие лимиты по перевозчикам: используются, только если у перевозчика в таблице carriers
+	// не задан rate_limit_per_minute.
 	WorkerRateLimitCDEKPerMinute   int `yaml:"worker_rate_limit_cdek_per_minute"`
 	WorkerRateLimitPostRuPerMinute int `yaml:"worker_rate_limit_post_ru_per_minute"`
+	// Как часто track-api и track-worker перечитывают справочник перевозчиков (таблица carriers).
+	CarriersRefreshSeconds int `yaml:"carriers_refresh_seconds"`
+	// Сколько дней хранить журнал проверок (tracking_checks); старые дни удаляет track-api.
+	CheckLogRetentionDays int `yaml:"check_log_retention_days"`
+	// Сроки хранения событий треков (см. retention.go); nil — события хранятся бессрочно.
+	EventRetention *EventRetentionConfig `yaml:"event_retention"`
 
 	WorkerHTTPAddr string `yaml:"worker_http_addr"`
+	WorkerGRPCAddr string `yaml:"worker_grpc_addr"`
 
 	// Worker scheduling (optional). If not set, defaults are "prod-like" minutes/hours:
 	// IN_TRANSIT: 30..120 minutes, UNKNOWN: 90 minutes, backoff: 5/15/30/60 minutes.
+	// Legacy flat keys: ignored when the structured `scheduling` block is set.
 	WorkerNextCheckInTransitMinSeconds int `yaml:"worker_next_check_in_transit_min_seconds"`
 	WorkerNextCheckInTransitMaxSeconds int `yaml:"worker_next_check_in_transit_max_seconds"`
 	WorkerNextCheckUnknownSeconds      int `yaml:"worker_next_check_unknown_seconds"`
@@ -60,23 +86,79 @@ type TrackBoxConfig struct {
 	WorkerBackoff3Seconds              int `yaml:"worker_backoff_3_seconds"`
 	WorkerBackoff4Seconds              int `yaml:"worker_backoff_4_seconds"`
 
+	// Structured per-carrier/per-status scheduling policies (see scheduling.go).
+	Scheduling *SchedulingConfig `yaml:"scheduling"`
+
+	// Scheduling algorithm: "static" (default, fixed delays above) or "history"
+	// (predicts the next status change from tracking_events cadence, business hours and ETA).
+	WorkerPlanner                     string `yaml:"worker_planner"`
+	WorkerPlannerMinDelaySeconds      int    `yaml:"worker_planner_min_delay_seconds"`
+	WorkerPlannerMaxDelaySeconds      int    `yaml:"worker_planner_max_delay_seconds"`
+	WorkerPlannerRefreshSeconds       int    `yaml:"worker_planner_refresh_seconds"`
+	CarrierBusinessHours map[string]BusinessHoursConfig `yaml:"carrier_business_hours"`
+	// Часовые пояса местного времени в ответах перевозчиков (Track24 и т.п. отдают время без пояса).
+	CarrierTimezones map[string]CarrierTimezoneConfig `yaml:"carrier_timezones"`
+	// SLA доставки (см. sla.go); nil — алерты не считаются.
+	SLA *SLAConfig `yaml:"sla"`
+	// Архив сырых ответов перевозчиков (см. archive.go); nil — ответы не сохраняются.
+	ResponseArchive *ResponseArchiveConfig `yaml:"response_archive"`
+
 	CarrierEmulatorBaseURL string `yaml:"carrier_emulator_base_url"`
-	CarrierEmulatorMode    string `yaml:"carrier_emulator_mode"` // "track24" | "gdeposylka"
+	CarrierEmulatorMode    string `yaml:"carrier_emulator_mode"` // "v1" | "track24" | "fake"
 	CarrierEmulatorAPIKey  string `yaml:"carrier_emulator_api_key"`
+	CarrierEmulatorAPIKeyFile string `yaml:"carrier_emulator_api_key_file"`
 	CarrierEmulatorDomain  string `yaml:"carrier_emulator_domain"`
 }
 
+// BusinessHoursConfig — рабочие часы перевозчика в его часовом поясе, например {timezone: "Europe/Moscow", start_hour: 9, end_hour: 21}.
+type BusinessHoursConfig struct {
+	Timezone  string `yaml:"timezone"`
+	StartHour int    `yaml:"start_hour"`
+	EndHour   int    `yaml:"end_hour"`
+}
+
+// CarrierTimezoneConfig — пояс местного времени перевозчика, например {timezone: "Europe/Moscow"}.
+// by_location (по умолчанию true) — сначала пояс места события по справочнику (индекс/город/регион),
+// timezone — если место неизвестно (пусто — UTC).
+type CarrierTimezoneConfig struct {
+	Timezone   string `yaml:"timezone"`
+	ByLocation *bool  `yaml:"by_location"`
+}
+
+// LoadConfig читает конфиг: ${ENV} подстановки → YAML (неизвестные ключи — ошибка) → TRACKBOX_* overrides →
+// секреты из *_file → значения по умолчанию → валидация.
 func LoadConfig(filename string) (*Config, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
+	return parse(data, filepath.Dir(filename), os.LookupEnv)
+}
 
-	var config Config
-	err = yaml.Unmarshal(data, &config)
+func parse(data []byte, baseDir string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	data, err := interpolateEnv(data, lookupEnv)
 	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
 		return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
 	}
 
+	if err := applyEnvOverrides(&config, lookupEnv); err != nil {
+		return nil, err
+	}
+	if err := resolveSecrets(&config, baseDir); err != nil {
+		return nil, err
+	}
+	config.ApplyDefaults()
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return &config, nil
 }
@@ -30,6 +30,7 @@ trackbox:
   http_addr: ":8080"
   kafka_consumer_group: "track-api"
   current_status_ttl_seconds: 600
+  current_status_negative_ttl_seconds: 30
 `), 0o600))
 
 	cfg, err := LoadConfig(p)
@@ -40,4 +41,274 @@ trackbox:
 	require.Equal(t, ":8080", cfg.TrackBox.HTTPAddr)
 }
 
+func TestLoadConfig_Scheduling(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "cfg.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  scheduling:
+    default:
+      jitter: "none"
+      statuses:
+        IN_TRANSIT: { min_seconds: 1800, max_seconds: 7200 }
+      backoff: { initial_seconds: 300, multiplier: 2, max_seconds: 3600, max_attempts: 10, give_up_seconds: 86400 }
+    carriers:
+      POST_RU:
+        statuses:
+          IN_TRANSIT: { min_seconds: 3600 }
+`), 0o600))
+
+	cfg, err := LoadConfig(p)
+	require.NoError(t, err)
+	sc := cfg.TrackBox.Scheduling
+	require.NotNil(t, sc)
+	require.Equal(t, "none", sc.Default.Jitter)
+	require.Equal(t, 7200, sc.Default.Statuses["IN_TRANSIT"].MaxSeconds)
+	require.NotNil(t, sc.Default.Backoff.MaxAttempts)
+	require.Equal(t, 10, *sc.Default.Backoff.MaxAttempts)
+	require.Equal(t, 3600, sc.Carriers["POST_RU"].Statuses["IN_TRANSIT"].MinSeconds)
+}
+
+func TestSchedulingConfig_Validate(t *testing.T) {
+	var nilCfg *SchedulingConfig
+	require.NoError(t, nilCfg.Validate())
+
+	cases := map[string]struct {
+		cfg SchedulingConfig
+		err string
+	}{
+		"bad jitter": {
+			cfg: SchedulingConfig{Default: SchedulePolicyConfig{Jitter: "gauss"}},
+			err: "scheduling.default.jitter",
+		},
+		"unknown status": {
+			cfg: SchedulingConfig{Carriers: map[string]SchedulePolicyConfig{
+				"CDEK": {Statuses: map[string]DelayRangeConfig{"LOST": {MinSeconds: 1}}},
+			}},
+			err: "scheduling.carriers.CDEK.statuses.LOST",
+		},
+		"max below min": {
+			cfg: SchedulingConfig{Default: SchedulePolicyConfig{
+				Statuses: map[string]DelayRangeConfig{"IN_TRANSIT": {MinSeconds: 60, MaxSeconds: 30}},
+			}},
+			err: "scheduling.default.statuses.IN_TRANSIT.max_seconds",
+		},
+		"zero min": {
+			cfg: SchedulingConfig{Default: SchedulePolicyConfig{
+				Statuses: map[string]DelayRangeConfig{"UNKNOWN": {}},
+			}},
+			err: "scheduling.default.statuses.UNKNOWN.min_seconds",
+		},
+		"multiplier below one": {
+			cfg: SchedulingConfig{Default: SchedulePolicyConfig{Backoff: &BackoffConfig{Multiplier: 0.5}}},
+			err: "scheduling.default.backoff.multiplier",
+		},
+		"cap below initial": {
+			cfg: SchedulingConfig{Carriers: map[string]SchedulePolicyConfig{
+				"POST_RU": {Backoff: &BackoffConfig{InitialSeconds: 600, MaxSeconds: 60}},
+			}},
+			err: "scheduling.carriers.POST_RU.backoff.max_seconds",
+		},
+		"negative attempts": {
+			cfg: SchedulingConfig{Default: SchedulePolicyConfig{Backoff: &BackoffConfig{MaxAttempts: intPtr(-1)}}},
+			err: "scheduling.default.backoff",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestLoadConfig_InvalidScheduling(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "cfg.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  scheduling:
+    default:
+      jitter: "random"
+`), 0o600))
+
+	_, err := LoadConfig(p)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "scheduling.default.jitter")
+}
+
+func TestLoadConfig_CarrierTimezones(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "cfg.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  carrier_timezones:
+    POST_RU: { timezone: "Europe/Moscow" }
+    DHL: { timezone: "Europe/Berlin", by_location: false }
+`), 0o600))
+
+	cfg, err := LoadConfig(p)
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Moscow", cfg.TrackBox.CarrierTimezones["POST_RU"].Timezone)
+	require.Nil(t, cfg.TrackBox.CarrierTimezones["POST_RU"].ByLocation)
+	require.False(t, *cfg.TrackBox.CarrierTimezones["DHL"].ByLocation)
+
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  carrier_timezones:
+    CDEK: { timezone: "Mars/Olympus" }
+`), 0o600))
+	_, err = LoadConfig(p)
+	require.ErrorContains(t, err, "trackbox.carrier_timezones.CDEK.timezone")
+}
+
+func TestParseSchedulePolicy(t *testing.T) {
+	p, err := ParseSchedulePolicy([]byte(`{"jitter":"none","statuses":{"IN_TRANSIT":{"min_seconds":600}}}`), "carriers.DHL")
+	require.NoError(t, err)
+	require.Equal(t, "none", p.Jitter)
+	require.Equal(t, 600, p.Statuses["IN_TRANSIT"].MinSeconds)
+
+	_, err = ParseSchedulePolicy([]byte(`{"statuses":{"LOST":{"min_seconds":1}}}`), "carriers.DHL")
+	require.ErrorContains(t, err, "carriers.DHL.statuses.LOST: unknown status")
+
+	_, err = ParseSchedulePolicy([]byte(`{"jiter":"none"}`), "carriers.DHL")
+	require.ErrorContains(t, err, "carriers.DHL")
+
+	// явный 0 отличается от незаданного значения
+	p, err = ParseSchedulePolicy([]byte(`{"backoff":{"max_attempts":0}}`), "carriers.DHL")
+	require.NoError(t, err)
+	require.NotNil(t, p.Backoff.MaxAttempts)
+	require.Equal(t, 0, *p.Backoff.MaxAttempts)
+	p, err = ParseSchedulePolicy([]byte(`{"backoff":{"initial_seconds":60}}`), "carriers.DHL")
+	require.NoError(t, err)
+	require.Nil(t, p.Backoff.MaxAttempts)
+}
+
+func intPtr(n int) *int { return &n }
+
+func TestLoadConfig_SLA(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "cfg.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  sla:
+    rules:
+      - { name: "default", max_silence_seconds: 432000 }
+      - { name: "post-ru", carrier: "POST_RU", max_transit_seconds: 2592000 }
+`), 0o600))
+
+	cfg, err := LoadConfig(p)
+	require.NoError(t, err)
+	require.Equal(t, "tracking.alert", cfg.Kafka.TrackingAlertTopicName)
+	require.Equal(t, 300, cfg.TrackBox.SLA.EvaluateIntervalSeconds)
+	require.Len(t, cfg.TrackBox.SLA.Rules, 2)
+	require.Equal(t, "POST_RU", cfg.TrackBox.SLA.Rules[1].Carrier)
+
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  sla:
+    rules:
+      - { name: "a", max_silence_seconds: -1 }
+      - { name: "a" }
+`), 0o600))
+	_, err = LoadConfig(p)
+	require.ErrorContains(t, err, "trackbox.sla.rules[0]: limits must be >= 0")
+	require.ErrorContains(t, err, `trackbox.sla.rules[1].name: duplicate rule "a"`)
+}
+
+func TestLoadConfig_CurrentStatusL1(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "cfg.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  current_status_l1:
+    max_entries: 500
+`), 0o600))
+
+	cfg, err := LoadConfig(p)
+	require.NoError(t, err)
+	require.Equal(t, &L1CacheConfig{MaxEntries: 500, TTLSeconds: 5, InvalidationChannel: "trackbox:cache:invalidate"}, cfg.TrackBox.CurrentStatusL1)
+
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  current_status_l1:
+    max_entries: -1
+    ttl_seconds: -5
+`), 0o600))
+	_, err = LoadConfig(p)
+	require.ErrorContains(t, err, "trackbox.current_status_l1.max_entries: must be > 0")
+	require.ErrorContains(t, err, "trackbox.current_status_l1.ttl_seconds: must be > 0")
+}
+
+func TestLoadConfig_ResponseArchive(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "s3_secret"), []byte("minio-secret\n"), 0o600))
+	p := filepath.Join(dir, "cfg.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  response_archive:
+    backend: s3
+    s3:
+      endpoint: "http://minio:9000"
+      bucket: "responses"
+      access_key: "trackbox"
+      secret_key_file: "s3_secret"
+`), 0o600))
 
+	cfg, err := LoadConfig(p)
+	require.NoError(t, err)
+	a := cfg.TrackBox.ResponseArchive
+	require.Equal(t, "minio-secret", a.S3.SecretKey)
+	require.Equal(t, "us-east-1", a.S3.Region)
+
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  response_archive:
+    s3:
+      endpoint: "minio:9000"
+`), 0o600))
+	_, err = LoadConfig(p)
+	require.ErrorContains(t, err, "trackbox.response_archive.dir: required for localfs")
+
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  response_archive:
+    backend: s3
+    s3:
+      endpoint: "minio:9000"
+`), 0o600))
+	_, err = LoadConfig(p)
+	require.ErrorContains(t, err, "trackbox.response_archive.s3.endpoint")
+	require.ErrorContains(t, err, "trackbox.response_archive.s3.bucket: required")
+}
+
+func TestLoadConfig_EventRetention(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "cfg.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  event_retention:
+    max_age_days: 730
+    statuses:
+      DELIVERED: 365
+`), 0o600))
+
+	cfg, err := LoadConfig(p)
+	require.NoError(t, err)
+	require.Equal(t, 730, cfg.TrackBox.EventRetention.MaxAgeDays)
+	require.Equal(t, map[string]int{"DELIVERED": 365}, cfg.TrackBox.EventRetention.Statuses)
+
+	require.NoError(t, os.WriteFile(p, []byte(`
+trackbox:
+  event_retention:
+    max_age_days: 30
+    statuses:
+      LOST: 30
+      DELIVERED: -5
+`), 0o600))
+	_, err = LoadConfig(p)
+	require.ErrorContains(t, err, "trackbox.event_retention.max_age_days: must be 0 or >= 62")
+	require.ErrorContains(t, err, "trackbox.event_retention.statuses.LOST: unknown status")
+	require.ErrorContains(t, err, "trackbox.event_retention.statuses.DELIVERED: must be >= 0")
+}
@@ -0,0 +1,63 @@
+package config
+
+// ApplyDefaults заполняет незаданные (нулевые) значения. Единственное место, где живут значения по умолчанию:
+// track-api и track-worker читают конфиг как есть.
+func (c *Config) ApplyDefaults() {
+	setString(&c.Database.Host, "localhost")
+	setInt(&c.Database.Port, 5432)
+	setString(&c.Database.SSLMode, "disable")
+
+	setString(&c.Kafka.Host, "localhost")
+	setInt(&c.Kafka.Port, 9092)
+	setString(&c.Kafka.TrackingUpdatedTopicName, "tracking.updated")
+	setString(&c.Kafka.TrackingAlertTopicName, "tracking.alert")
+
+	setString(&c.Redis.Host, "localhost")
+	setInt(&c.Redis.Port, 6379)
+
+	t := &c.TrackBox
+	setString(&t.GRPCAddr, ":50051")
+	setString(&t.HTTPAddr, ":8080")
+	setString(&t.KafkaConsumerGroup, "track-api")
+	setInt(&t.CurrentStatusTTLSeconds, 600)
+	setInt(&t.CurrentStatusNegativeTTLSeconds, 30)
+	setInt(&t.CheckNowTimeoutSeconds, 10)
+	setInt(&t.CarriersRefreshSeconds, 30)
+	setInt(&t.CheckLogRetentionDays, 30)
+
+	setInt(&t.WorkerPollIntervalSeconds, 2)
+	setInt(&t.WorkerBatchSize, 100)
+	setInt(&t.WorkerConcurrency, 10)
+	setInt(&t.WorkerLeaseSeconds, 120)
+	setInt(&t.WorkerRateLimitPerMinute, 120)
+	setString(&t.WorkerHTTPAddr, ":8082")
+	setString(&t.WorkerGRPCAddr, ":50052")
+
+	setString(&t.WorkerPlanner, "static")
+	setInt(&t.WorkerPlannerRefreshSeconds, 600)
+
+	if l := t.CurrentStatusL1; l != nil {
+		setInt(&l.MaxEntries, 10000)
+		setInt(&l.TTLSeconds, 5)
+		setString(&l.InvalidationChannel, "trackbox:cache:invalidate")
+	}
+	if t.SLA != nil {
+		setInt(&t.SLA.EvaluateIntervalSeconds, 300)
+	}
+	if a := t.ResponseArchive; a != nil {
+		setString(&a.Backend, "localfs")
+		setString(&a.S3.Region, "us-east-1")
+	}
+}
+
+func setString(v *string, def string) {
+	if *v == "" {
+		*v = def
+	}
+}
+
+func setInt(v *int, def int) {
+	if *v == 0 {
+		*v = def
+	}
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// EnvPrefix — префикс переменных окружения, переопределяющих ключи конфига:
+// секция trackbox → TRACKBOX_<KEY> (TRACKBOX_WORKER_BATCH_SIZE), остальные → TRACKBOX_<SECTION>_<KEY>
+// (TRACKBOX_DATABASE_PASSWORD). Переопределяются только скалярные ключи.
+const EnvPrefix = "TRACKBOX_"
+
+// ${VAR} или ${VAR:-default}. Голый $VAR не трогаем, чтобы не ломать пароли с '$'.
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// interpolateEnv подставляет переменные только в значения (и ключи) YAML, но не в комментарии:
+// закомментированный ${VAR} не должен требовать переменную. Документ разбирается в дерево узлов,
+// подстановка идёт по скалярам, результат снова сериализуется и дальше декодируется как обычно.
+func interpolateEnv(data []byte, lookupEnv func(string) (string, bool)) ([]byte, error) {
+	if !envRef.Match(data) {
+		return data, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+	}
+
+	var missing []string
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		if n.Kind == yaml.ScalarNode && envRef.MatchString(n.Value) {
+			n.Value = envRef.ReplaceAllStringFunc(n.Value, func(m string) string {
+				sub := envRef.FindStringSubmatch(m)
+				if v, ok := lookupEnv(sub[1]); ok {
+					return v
+				}
+				if sub[2] != "" {
+					return sub[3]
+				}
+				missing = append(missing, sub[1])
+				return m
+			})
+			// тип plain-скаляра без явного тега определяется по подставленному значению (port: ${PORT} — число)
+			if n.Style&(yaml.TaggedStyle|yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+				n.Tag = ""
+			}
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(&doc)
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("config references unset env vars: %s", strings.Join(missing, ", "))
+	}
+	return yaml.Marshal(&doc)
+}
+
+func applyEnvOverrides(c *Config, lookupEnv func(string) (string, bool)) error {
+	root := reflect.ValueOf(c).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := yamlName(root.Type().Field(i))
+		prefix := EnvPrefix + strings.ToUpper(section) + "_"
+		if section == "trackbox" {
+			prefix = EnvPrefix
+		}
+
+		sv := root.Field(i)
+		for j := 0; j < sv.NumField(); j++ {
+			key := yamlName(sv.Type().Field(j))
+			if key == "" {
+				continue
+			}
+			name := prefix + strings.ToUpper(key)
+			raw, ok := lookupEnv(name)
+			if !ok {
+				continue
+			}
+			if err := setScalar(sv.Field(j), raw); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func yamlName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func setScalar(v reflect.Value, raw string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("invalid bool %q", raw)
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("cannot be set from env (structured key)")
+	}
+	return nil
+}
+
+// ResolveSecretRef читает секрет по ссылке "env:NAME" (переменная окружения) или "file:/path" (файл,
+// перевод строки в конце отбрасывается). Так в БД хранится ссылка на ключ, а не сам ключ.
+func ResolveSecretRef(ref string) (string, error) {
+	kind, target, ok := strings.Cut(ref, ":")
+	if !ok || target == "" {
+		return "", fmt.Errorf("secret ref %q: want env:NAME or file:/path", ref)
+	}
+	switch kind {
+	case "env":
+		v, ok := os.LookupEnv(target)
+		if !ok {
+			return "", fmt.Errorf("secret ref %q: env var is not set", ref)
+		}
+		return v, nil
+	case "file":
+		b, err := os.ReadFile(target)
+		if err != nil {
+			return "", fmt.Errorf("secret ref %q: %w", ref, err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	default:
+		return "", fmt.Errorf("secret ref %q: unknown kind %q (want env|file)", ref, kind)
+	}
+}
+
+// ValidateSecretRef проверяет только форму ссылки (сам секрет может быть доступен лишь worker'у).
+func ValidateSecretRef(ref string) error {
+	kind, target, ok := strings.Cut(ref, ":")
+	if !ok || target == "" || (kind != "env" && kind != "file") {
+		return fmt.Errorf("secret ref %q: want env:NAME or file:/path", ref)
+	}
+	return nil
+}
+
+// resolveSecrets подставляет значения из *_file. Относительные пути считаются от каталога конфига.
+func resolveSecrets(c *Config, baseDir string) error {
+	type secret struct {
+		key   string
+		value *string
+		file  string
+	}
+	secrets := []secret{
+		{"database.password", &c.Database.Password, c.Database.PasswordFile},
+		{"trackbox.carrier_emulator_api_key", &c.TrackBox.CarrierEmulatorAPIKey, c.TrackBox.CarrierEmulatorAPIKeyFile},
+	}
+	if a := c.TrackBox.ResponseArchive; a != nil {
+		secrets = append(secrets, secret{"trackbox.response_archive.s3.secret_key", &a.S3.SecretKey, a.S3.SecretKeyFile})
+	}
+	for _, s := range secrets {
+		if s.file == "" {
+			continue
+		}
+		if *s.value != "" {
+			return fmt.Errorf("%s and %s_file are both set", s.key, s.key)
+		}
+		path := s.file
+		if !filepath.IsAbs(path) && baseDir != "" {
+			path = filepath.Join(baseDir, path)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%s_file: %w", s.key, err)
+		}
+		*s.value = strings.TrimRight(string(b), "\r\n")
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func envMap(m map[string]string) func(string) (string, bool) {
+	return func(k string) (string, bool) {
+		v, ok := m[k]
+		return v, ok
+	}
+}
+
+func TestParse_DefaultsAppliedOnEmptyConfig(t *testing.T) {
+	cfg, err := parse(nil, "", envMap(nil))
+	require.NoError(t, err)
+	require.Equal(t, 5432, cfg.Database.Port)
+	require.Equal(t, "disable", cfg.Database.SSLMode)
+	require.Equal(t, "tracking.updated", cfg.Kafka.TrackingUpdatedTopicName)
+	require.Equal(t, ":50051", cfg.TrackBox.GRPCAddr)
+	require.Equal(t, 100, cfg.TrackBox.WorkerBatchSize)
+	require.Equal(t, "static", cfg.TrackBox.WorkerPlanner)
+	require.Empty(t, cfg.TrackBox.WorkerGRPCTarget)
+}
+
+func TestParse_UnknownKeyRejected(t *testing.T) {
+	_, err := parse([]byte(`
+trackbox:
+  worker_bacth_size: 10
+`), "", envMap(nil))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "worker_bacth_size")
+}
+
+func TestParse_EnvInterpolation(t *testing.T) {
+	data := []byte(`
+database:
+  host: "${DB_HOST}"
+  password: "${DB_PASSWORD:-dev}"
+  username: "pa$$word-like"
+`)
+	cfg, err := parse(data, "", envMap(map[string]string{"DB_HOST": "pg"}))
+	require.NoError(t, err)
+	require.Equal(t, "pg", cfg.Database.Host)
+	require.Equal(t, "dev", cfg.Database.Password)
+	require.Equal(t, "pa$$word-like", cfg.Database.Username)
+
+	_, err = parse(data, "", envMap(nil))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "DB_HOST")
+}
+
+func TestParse_EnvInterpolationSkipsComments(t *testing.T) {
+	data := []byte(`
+# host: ${OLD_DB_HOST}
+database:
+  host: ${DB_HOST} # было ${LEGACY_HOST}
+  port: ${DB_PORT:-6432}
+  # password: ${DB_PASSWORD}
+  password: "${DB_PASSWORD:-p#ss: 1}"
+`)
+	cfg, err := parse(data, "", envMap(map[string]string{"DB_HOST": "pg"}))
+	require.NoError(t, err)
+	require.Equal(t, "pg", cfg.Database.Host)
+	require.Equal(t, 6432, cfg.Database.Port)
+	require.Equal(t, "p#ss: 1", cfg.Database.Password)
+}
+
+func TestParse_EnvOverrides(t *testing.T) {
+	data := []byte(`
+database:
+  password: "from-file"
+trackbox:
+  worker_batch_size: 10
+`)
+	cfg, err := parse(data, "", envMap(map[string]string{
+		"TRACKBOX_DATABASE_PASSWORD":     "from-env",
+		"TRACKBOX_WORKER_BATCH_SIZE":     "250",
+		"TRACKBOX_KAFKA_PORT":            "19092",
+		"TRACKBOX_CARRIER_EMULATOR_MODE": "track24",
+	}))
+	require.NoError(t, err)
+	require.Equal(t, "from-env", cfg.Database.Password)
+	require.Equal(t, 250, cfg.TrackBox.WorkerBatchSize)
+	require.Equal(t, 19092, cfg.Kafka.Port)
+	require.Equal(t, "track24", cfg.TrackBox.CarrierEmulatorMode)
+
+	_, err = parse(data, "", envMap(map[string]string{"TRACKBOX_WORKER_BATCH_SIZE": "lots"}))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "TRACKBOX_WORKER_BATCH_SIZE")
+
+	_, err = parse(data, "", envMap(map[string]string{"TRACKBOX_SCHEDULING": "x"}))
+	require.Error(t, err)
+}
+
+func TestParse_SecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db_password"), []byte("s3cret\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api_key"), []byte("key-1"), 0o600))
+
+	cfg, err := parse([]byte(`
+database:
+  password_file: "db_password"
+trackbox:
+  carrier_emulator_api_key_file: "`+filepath.Join(dir, "api_key")+`"
+`), dir, envMap(nil))
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", cfg.Database.Password)
+	require.Equal(t, "key-1", cfg.TrackBox.CarrierEmulatorAPIKey)
+
+	_, err = parse([]byte(`
+database:
+  password: "inline"
+  password_file: "db_password"
+`), dir, envMap(nil))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "both set")
+
+	_, err = parse([]byte(`
+database:
+  password_file: "missing"
+`), dir, envMap(nil))
+	require.Error(t, err)
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	_, err := parse([]byte(`
+redis:
+  port: 70000
+trackbox:
+  worker_planner: "magic"
+  worker_rate_limit_per_minute: -1
+  carrier_business_hours:
+    CDEK: { timezone: "Mars/Olympus", start_hour: 20, end_hour: 8 }
+`), "", envMap(nil))
+	require.Error(t, err)
+	for _, want := range []string{
+		"redis.port",
+		"trackbox.worker_planner",
+		"trackbox.worker_rate_limit_per_minute",
+		"trackbox.carrier_business_hours.CDEK: need",
+		"trackbox.carrier_business_hours.CDEK.timezone",
+	} {
+		require.Contains(t, err.Error(), want)
+	}
+}
+
+func TestResolveSecretRef(t *testing.T) {
+	t.Setenv("TB_TEST_CARRIER_KEY", "k1")
+	v, err := ResolveSecretRef("env:TB_TEST_CARRIER_KEY")
+	require.NoError(t, err)
+	require.Equal(t, "k1", v)
+
+	path := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(path, []byte("k2\n"), 0o600))
+	v, err = ResolveSecretRef("file:" + path)
+	require.NoError(t, err)
+	require.Equal(t, "k2", v)
+
+	_, err = ResolveSecretRef("env:TB_TEST_MISSING_KEY")
+	require.ErrorContains(t, err, "not set")
+	require.Error(t, ValidateSecretRef("vault:x"))
+	require.Error(t, ValidateSecretRef("env:"))
+	require.NoError(t, ValidateSecretRef("file:/run/secrets/key"))
+}
@@ -0,0 +1,33 @@
+package config
+
+import "errors"
+
+// L1CacheConfig — кэш текущего состояния треков в памяти track-api, перед Redis.
+//
+//	current_status_l1:
+//	  max_entries: 10000                             # сколько треков держать в памяти (вытесняются давно не читанные)
+//	  ttl_seconds: 5                                 # сколько запись живёт в памяти
+//	  invalidation_channel: "trackbox:cache:invalidate"
+//
+// Реплики track-api сообщают друг другу об изменённых треках через Redis pub/sub (invalidation_channel),
+// ttl_seconds ограничивает расхождение, если сообщение потерялось. nil (нет блока) — без кэша в памяти.
+type L1CacheConfig struct {
+	MaxEntries          int    `yaml:"max_entries"`
+	TTLSeconds          int    `yaml:"ttl_seconds"`
+	InvalidationChannel string `yaml:"invalidation_channel"`
+}
+
+// Validate проверяет блок current_status_l1 и возвращает все ошибки с путём до поля.
+func (l *L1CacheConfig) Validate() error {
+	if l == nil {
+		return nil
+	}
+	var errs []error
+	if l.MaxEntries <= 0 {
+		errs = append(errs, errors.New("trackbox.current_status_l1.max_entries: must be > 0"))
+	}
+	if l.TTLSeconds <= 0 {
+		errs = append(errs, errors.New("trackbox.current_status_l1.ttl_seconds: must be > 0"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// EventRetentionConfig — сроки хранения событий треков (tracking_events, секции по месяцам).
+//
+//	event_retention:
+//	  max_age_days: 730          # события старше удаляются целыми месячными секциями
+//	  statuses:
+//	    DELIVERED: 365           # события доставленных треков — через год после доставки
+//	  detach_only: false         # true — старые секции отсоединяются, а не удаляются
+//
+// 0 (или отсутствие блока) — без ограничения. Применяет track-api раз в час.
+type EventRetentionConfig struct {
+	MaxAgeDays int            `yaml:"max_age_days"`
+	Statuses   map[string]int `yaml:"statuses"`
+	DetachOnly bool           `yaml:"detach_only"`
+}
+
+const minEventMaxAgeDays = 62
+
+// Validate проверяет блок event_retention и возвращает все ошибки с путём до поля.
+func (r *EventRetentionConfig) Validate() error {
+	if r == nil {
+		return nil
+	}
+	var errs []error
+	switch {
+	case r.MaxAgeDays < 0:
+		errs = append(errs, errors.New("trackbox.event_retention.max_age_days: must be >= 0"))
+	case r.MaxAgeDays > 0 && r.MaxAgeDays < minEventMaxAgeDays:
+		// Секции событий месячные и держатся с прошлого месяца: меньший срок удалял бы их сразу после создания.
+		errs = append(errs, fmt.Errorf("trackbox.event_retention.max_age_days: must be 0 or >= %d", minEventMaxAgeDays))
+	}
+	for _, st := range sortedKeys(r.Statuses) {
+		key := "trackbox.event_retention.statuses." + st
+		if !knownStatuses[st] {
+			errs = append(errs, fmt.Errorf("%s: unknown status", key))
+		}
+		if r.Statuses[st] < 0 {
+			errs = append(errs, fmt.Errorf("%s: must be >= 0", key))
+		}
+	}
+	return errors.Join(errs...)
+}
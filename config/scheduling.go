@@ -0,0 +1,130 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"go.yaml.in/yaml/v4"
+)
+
+// SchedulingConfig — политики планирования проверок: default + переопределения по перевозчикам.
+//
+//	scheduling:
+//	  default:
+//	    jitter: "uniform"
+//	    statuses:
+//	      IN_TRANSIT: { min_seconds: 1800, max_seconds: 7200 }
+//	    backoff: { initial_seconds: 300, multiplier: 2, max_seconds: 3600, max_attempts: 10, give_up_seconds: 86400 }
+//	  carriers:
+//	    POST_RU:
+//	      statuses:
+//	        IN_TRANSIT: { min_seconds: 3600, max_seconds: 14400 }
+//
+// Всё, что не задано у перевозчика, берётся из default; что не задано в default — из встроенных значений.
+type SchedulingConfig struct {
+	Default  SchedulePolicyConfig            `yaml:"default"`
+	Carriers map[string]SchedulePolicyConfig `yaml:"carriers"`
+}
+
+type SchedulePolicyConfig struct {
+	// Statuses — диапазон задержки до следующей проверки по нормализованному статусу.
+	Statuses map[string]DelayRangeConfig `yaml:"statuses"`
+	// Jitter: "uniform" (случайно в [min,max], для backoff — в [d/2,d]) | "none" (min / точный backoff).
+	Jitter  string         `yaml:"jitter"`
+	Backoff *BackoffConfig `yaml:"backoff"`
+}
+
+type DelayRangeConfig struct {
+	MinSeconds int `yaml:"min_seconds"`
+	MaxSeconds int `yaml:"max_seconds"` // 0 = равен min_seconds
+}
+
+// BackoffConfig — экспоненциальный backoff после ошибок: initial * multiplier^(n-1), не больше max.
+// После max_attempts ошибок подряд трек «паркуется» на give_up_seconds. max_attempts: 0 — без ограничения
+// (в том числе поверх лимита из default), не задан — наследуется.
+type BackoffConfig struct {
+	InitialSeconds int     `yaml:"initial_seconds"`
+	Multiplier     float64 `yaml:"multiplier"`
+	MaxSeconds     int     `yaml:"max_seconds"`
+	MaxAttempts    *int    `yaml:"max_attempts"`
+	GiveUpSeconds  int     `yaml:"give_up_seconds"`
+}
+
+var knownStatuses = map[string]bool{
+	models.TrackingStatusUnknown:   true,
+	models.TrackingStatusInTransit: true,
+	models.TrackingStatusDelivered: true,
+}
+
+// Validate проверяет блок scheduling целиком и возвращает первую ошибку с путём до поля.
+func (s *SchedulingConfig) Validate() error {
+	if s == nil {
+		return nil
+	}
+	if err := s.Default.validate("scheduling.default"); err != nil {
+		return err
+	}
+	for _, c := range sortedKeys(s.Carriers) {
+		if c == "" {
+			return fmt.Errorf("scheduling.carriers: empty carrier code")
+		}
+		if err := s.Carriers[c].validate("scheduling.carriers." + c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseSchedulePolicy разбирает политику одного перевозчика в формате scheduling.carriers.<code>
+// (YAML или JSON — например, из таблицы carriers) и проверяет её; path — префикс для ошибок.
+func ParseSchedulePolicy(data []byte, path string) (SchedulePolicyConfig, error) {
+	var p SchedulePolicyConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return SchedulePolicyConfig{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := p.validate(path); err != nil {
+		return SchedulePolicyConfig{}, err
+	}
+	return p, nil
+}
+
+func (p SchedulePolicyConfig) validate(path string) error {
+	switch p.Jitter {
+	case "", "none", "uniform":
+	default:
+		return fmt.Errorf("%s.jitter: unknown mode %q (want none|uniform)", path, p.Jitter)
+	}
+
+	for _, st := range sortedKeys(p.Statuses) {
+		r := p.Statuses[st]
+		sp := path + ".statuses." + st
+		if !knownStatuses[st] {
+			return fmt.Errorf("%s: unknown status (want UNKNOWN|IN_TRANSIT|DELIVERED)", sp)
+		}
+		if r.MinSeconds <= 0 {
+			return fmt.Errorf("%s.min_seconds: must be > 0", sp)
+		}
+		if r.MaxSeconds != 0 && r.MaxSeconds < r.MinSeconds {
+			return fmt.Errorf("%s.max_seconds: must be >= min_seconds", sp)
+		}
+	}
+
+	if b := p.Backoff; b != nil {
+		bp := path + ".backoff"
+		if b.InitialSeconds < 0 || b.MaxSeconds < 0 || (b.MaxAttempts != nil && *b.MaxAttempts < 0) || b.GiveUpSeconds < 0 {
+			return fmt.Errorf("%s: values must be >= 0", bp)
+		}
+		if b.Multiplier != 0 && b.Multiplier < 1 {
+			return fmt.Errorf("%s.multiplier: must be >= 1", bp)
+		}
+		if b.InitialSeconds > 0 && b.MaxSeconds > 0 && b.MaxSeconds < b.InitialSeconds {
+			return fmt.Errorf("%s.max_seconds: must be >= initial_seconds", bp)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SLAConfig — правила SLA доставки и как часто track-worker их проверяет.
+//
+//	sla:
+//	  evaluate_interval_seconds: 300
+//	  rules:
+//	    - { name: "default", max_silence_seconds: 432000, max_transit_seconds: 2592000 }
+//	    - { name: "post-ru-msk", carrier: "POST_RU", destination: "Москва", max_silence_seconds: 259200 }
+//
+// Для трека берётся самое конкретное подходящее правило (carrier, origin, destination — пусто значит «любой»).
+// 0 в лимите — проверка выключена.
+type SLAConfig struct {
+	EvaluateIntervalSeconds int             `yaml:"evaluate_interval_seconds"`
+	Rules                   []SLARuleConfig `yaml:"rules"`
+}
+
+type SLARuleConfig struct {
+	Name        string `yaml:"name"`
+	Carrier     string `yaml:"carrier"`
+	Origin      string `yaml:"origin"`
+	Destination string `yaml:"destination"`
+	// MaxSilenceSeconds — сколько можно жить без новых событий (от последнего события, без событий — от создания трека).
+	MaxSilenceSeconds int `yaml:"max_silence_seconds"`
+	// MaxTransitSeconds — сколько можно быть в пути (от первого события, без событий — от создания трека).
+	MaxTransitSeconds int `yaml:"max_transit_seconds"`
+	// MaxETADelaySeconds — насколько трек может опоздать относительно ETA перевозчика.
+	MaxETADelaySeconds int `yaml:"max_eta_delay_seconds"`
+}
+
+// Validate проверяет блок sla целиком и возвращает все ошибки с путём до поля.
+func (s *SLAConfig) Validate() error {
+	if s == nil {
+		return nil
+	}
+	var errs []error
+	if s.EvaluateIntervalSeconds <= 0 {
+		errs = append(errs, errors.New("trackbox.sla.evaluate_interval_seconds: must be > 0"))
+	}
+	names := make(map[string]bool, len(s.Rules))
+	for i, r := range s.Rules {
+		key := fmt.Sprintf("trackbox.sla.rules[%d]", i)
+		if r.Name == "" {
+			errs = append(errs, fmt.Errorf("%s.name: required", key))
+		} else if names[r.Name] {
+			errs = append(errs, fmt.Errorf("%s.name: duplicate rule %q", key, r.Name))
+		}
+		names[r.Name] = true
+		if r.MaxSilenceSeconds < 0 || r.MaxTransitSeconds < 0 || r.MaxETADelaySeconds < 0 {
+			errs = append(errs, fmt.Errorf("%s: limits must be >= 0", key))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Validate проверяет конфиг после подстановки значений по умолчанию и возвращает все найденные ошибки.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	port := func(key string, v int) {
+		if v <= 0 || v > 65535 {
+			add("%s: port must be in 1..65535, got %d", key, v)
+		}
+	}
+	port("database.port", c.Database.Port)
+	port("kafka.port", c.Kafka.Port)
+	port("redis.port", c.Redis.Port)
+
+	t := c.TrackBox
+	positive := map[string]int{
+		"trackbox.current_status_ttl_seconds":          t.CurrentStatusTTLSeconds,
+		"trackbox.current_status_negative_ttl_seconds": t.CurrentStatusNegativeTTLSeconds,
+		"trackbox.check_now_timeout_seconds":           t.CheckNowTimeoutSeconds,
+		"trackbox.carriers_refresh_seconds":            t.CarriersRefreshSeconds,
+		"trackbox.check_log_retention_days":            t.CheckLogRetentionDays,
+		"trackbox.worker_poll_interval_seconds":        t.WorkerPollIntervalSeconds,
+		"trackbox.worker_batch_size":                   t.WorkerBatchSize,
+		"trackbox.worker_concurrency":                  t.WorkerConcurrency,
+		"trackbox.worker_lease_seconds":                t.WorkerLeaseSeconds,
+		"trackbox.worker_planner_refresh_seconds":      t.WorkerPlannerRefreshSeconds,
+	}
+	for _, key := range sortedKeys(positive) {
+		if positive[key] <= 0 {
+			add("%s: must be > 0", key)
+		}
+	}
+	nonNegative := map[string]int{
+		"trackbox.worker_rate_limit_per_minute":             t.WorkerRateLimitPerMinute,
+		"trackbox.worker_rate_limit_cdek_per_minute":        t.WorkerRateLimitCDEKPerMinute,
+		"trackbox.worker_rate_limit_post_ru_per_minute":     t.WorkerRateLimitPostRuPerMinute,
+		"trackbox.worker_next_check_in_transit_min_seconds": t.WorkerNextCheckInTransitMinSeconds,
+		"trackbox.worker_next_check_in_transit_max_seconds": t.WorkerNextCheckInTransitMaxSeconds,
+		"trackbox.worker_next_check_unknown_seconds":        t.WorkerNextCheckUnknownSeconds,
+		"trackbox.worker_backoff_1_seconds":                 t.WorkerBackoff1Seconds,
+		"trackbox.worker_backoff_2_seconds":                 t.WorkerBackoff2Seconds,
+		"trackbox.worker_backoff_3_seconds":                 t.WorkerBackoff3Seconds,
+		"trackbox.worker_backoff_4_seconds":                 t.WorkerBackoff4Seconds,
+		"trackbox.worker_planner_min_delay_seconds":         t.WorkerPlannerMinDelaySeconds,
+		"trackbox.worker_planner_max_delay_seconds":         t.WorkerPlannerMaxDelaySeconds,
+	}
+	for _, key := range sortedKeys(nonNegative) {
+		if nonNegative[key] < 0 {
+			add("%s: must be >= 0", key)
+		}
+	}
+	if t.WorkerPlannerMaxDelaySeconds > 0 && t.WorkerPlannerMaxDelaySeconds < t.WorkerPlannerMinDelaySeconds {
+		add("trackbox.worker_planner_max_delay_seconds: must be >= worker_planner_min_delay_seconds")
+	}
+
+	switch t.WorkerPlanner {
+	case "static", "history":
+	default:
+		add("trackbox.worker_planner: unknown planner %q (want static|history)", t.WorkerPlanner)
+	}
+	switch t.CarrierEmulatorMode {
+	case "", "v1", "track24", "fake":
+	default:
+		add("trackbox.carrier_emulator_mode: unknown mode %q (want v1|track24|fake)", t.CarrierEmulatorMode)
+	}
+
+	for _, code := range sortedKeys(t.CarrierBusinessHours) {
+		bh := t.CarrierBusinessHours[code]
+		key := "trackbox.carrier_business_hours." + code
+		if bh.StartHour < 0 || bh.EndHour > 24 || bh.StartHour >= bh.EndHour {
+			add("%s: need 0 <= start_hour < end_hour <= 24", key)
+		}
+		if bh.Timezone != "" {
+			if _, err := time.LoadLocation(bh.Timezone); err != nil {
+				add("%s.timezone: %v", key, err)
+			}
+		}
+	}
+
+	for _, code := range sortedKeys(t.CarrierTimezones) {
+		if tz := t.CarrierTimezones[code].Timezone; tz != "" {
+			if _, err := time.LoadLocation(tz); err != nil {
+				add("trackbox.carrier_timezones.%s.timezone: %v", code, err)
+			}
+		}
+	}
+
+	if err := t.Scheduling.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := t.CurrentStatusL1.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := t.SLA.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := t.ResponseArchive.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := t.EventRetention.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Watch перечитывает конфиг по SIGHUP и при изменении файла (mtime/size опрашиваются раз в interval)
+// и передаёт новый конфиг в onReload. Невалидный конфиг логируется и пропускается — сервис работает со старым.
+// Блокируется до отмены ctx.
+func Watch(ctx context.Context, path string, interval time.Duration, onReload func(*Config)) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	last, _ := fileVersion(path)
+	reload := func(reason string) {
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			slog.Error("config reload failed, keeping previous config", "path", path, "reason", reason, "error", err.Error())
+			return
+		}
+		slog.Info("config reloaded", "path", path, "reason", reason)
+		onReload(cfg)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			last, _ = fileVersion(path)
+			reload("sighup")
+		case <-t.C:
+			v, err := fileVersion(path)
+			if err != nil || v == last {
+				continue
+			}
+			last = v
+			reload("file changed")
+		}
+	}
+}
+
+type version struct {
+	modTime time.Time
+	size    int64
+}
+
+func fileVersion(path string) (version, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return version{}, err
+	}
+	return version{modTime: fi.ModTime(), size: fi.Size()}, nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch_ReloadsOnFileChange(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "cfg.yaml")
+	require.NoError(t, os.WriteFile(p, []byte("trackbox:\n  worker_batch_size: 10\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	got := make(chan *Config, 4)
+	go Watch(ctx, p, 10*time.Millisecond, func(c *Config) { got <- c })
+	time.Sleep(30 * time.Millisecond)
+
+	// Невалидный конфиг пропускается.
+	require.NoError(t, os.WriteFile(p, []byte("trackbox:\n  worker_batch_size: -5\n  x: 1\n"), 0o600))
+	time.Sleep(50 * time.Millisecond)
+	require.Len(t, got, 0)
+
+	require.NoError(t, os.WriteFile(p, []byte("trackbox:\n  worker_batch_size: 20\n"), 0o600))
+	select {
+	case c := <-got:
+		require.Equal(t, 20, c.TrackBox.WorkerBatchSize)
+	case <-time.After(2 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
@@ -10,10 +10,12 @@ import requests
 
 
 def gen_post_ru() -> str:
-    # Упрощённый UPU S10: 2 буквы + 9 цифр + RU
+    # UPU S10: 2 буквы + 8 цифр + контрольная цифра + RU (track-api проверяет контрольную цифру)
     prefix = random.choice(string.ascii_uppercase) + random.choice(string.ascii_uppercase)
-    digits = "".join(random.choice(string.digits) for _ in range(9))
-    return f"{prefix}{digits}RU"
+    digits = "".join(random.choice(string.digits) for _ in range(8))
+    check = 11 - sum(int(d) * w for d, w in zip(digits, (8, 6, 4, 2, 3, 5, 9, 7))) % 11
+    check = {10: 0, 11: 5}.get(check, check)
+    return f"{prefix}{digits}{check}RU"
 
 
 def gen_cdek() -> str:
@@ -0,0 +1,65 @@
+package alerts_api
+
+import (
+	"context"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/pb/alerts_api"
+	"github.com/BearBump/TrackBox/internal/services/sla"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type AlertsAPI struct {
+	alerts_api.UnimplementedAlertsServiceServer
+	svc *sla.Service
+}
+
+func New(svc *sla.Service) *AlertsAPI {
+	return &AlertsAPI{svc: svc}
+}
+
+func (a *AlertsAPI) ListAlerts(ctx context.Context, req *alerts_api.ListAlertsRequest) (*alerts_api.ListAlertsResponse, error) {
+	f := models.AlertFilter{
+		State:       req.GetState(),
+		TrackingID:  req.GetTrackingId(),
+		Kind:        req.GetKind(),
+		CarrierCode: req.GetCarrierCode(),
+	}
+	list, next, err := a.svc.ListAlerts(ctx, f, req.GetPageToken(), int(req.GetPageSize()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := &alerts_api.ListAlertsResponse{Alerts: make([]*alerts_api.Alert, 0, len(list)), NextPageToken: next}
+	for _, al := range list {
+		out.Alerts = append(out.Alerts, toPBAlert(al))
+	}
+	return out, nil
+}
+
+func toStatus(err error) error {
+	if errors.Is(err, sla.ErrInvalidArgument) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
+
+func toPBAlert(a *models.Alert) *alerts_api.Alert {
+	out := &alerts_api.Alert{
+		Id:          a.ID,
+		TrackingId:  a.TrackingID,
+		CarrierCode: a.CarrierCode,
+		TrackNumber: a.TrackNumber,
+		Kind:        a.Kind,
+		State:       a.State(),
+		Rule:        a.Rule,
+		Details:     a.Details,
+		OpenedAt:    timestamppb.New(a.OpenedAt),
+	}
+	if a.ResolvedAt != nil {
+		out.ResolvedAt = timestamppb.New(*a.ResolvedAt)
+	}
+	return out
+}
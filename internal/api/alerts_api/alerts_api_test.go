@@ -0,0 +1,49 @@
+package alerts_api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/pb/alerts_api"
+	"github.com/BearBump/TrackBox/internal/services/sla"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeRepo — только чтение списка; оценку SLA проверяют тесты пакета sla.
+type fakeRepo struct {
+	sla.Repository
+	alerts []*models.Alert
+	filter models.AlertFilter
+}
+
+func (r *fakeRepo) ListAlerts(_ context.Context, f models.AlertFilter, _ uint64, _ int) ([]*models.Alert, error) {
+	r.filter = f
+	return r.alerts, nil
+}
+
+func TestListAlerts(t *testing.T) {
+	opened := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	resolved := opened.Add(time.Hour)
+	repo := &fakeRepo{alerts: []*models.Alert{
+		{ID: 2, TrackingID: 7, CarrierCode: "CDEK", TrackNumber: "A1", Kind: models.AlertKindNoEvents, Rule: "default", OpenedAt: opened},
+		{ID: 1, TrackingID: 7, CarrierCode: "CDEK", TrackNumber: "A1", Kind: models.AlertKindETAMissed, Rule: "default", OpenedAt: opened, ResolvedAt: &resolved},
+	}}
+	api := New(sla.New(repo))
+
+	resp, err := api.ListAlerts(context.Background(), &alerts_api.ListAlertsRequest{TrackingId: 7, CarrierCode: "cdek"})
+	require.NoError(t, err)
+	require.Equal(t, models.AlertFilter{TrackingID: 7, CarrierCode: "CDEK"}, repo.filter)
+	require.Len(t, resp.GetAlerts(), 2)
+	require.Equal(t, "OPEN", resp.GetAlerts()[0].GetState())
+	require.Nil(t, resp.GetAlerts()[0].GetResolvedAt())
+	require.Equal(t, "RESOLVED", resp.GetAlerts()[1].GetState())
+	require.Equal(t, resolved, resp.GetAlerts()[1].GetResolvedAt().AsTime())
+	require.Empty(t, resp.GetNextPageToken())
+
+	_, err = api.ListAlerts(context.Background(), &alerts_api.ListAlertsRequest{Kind: "LOST"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
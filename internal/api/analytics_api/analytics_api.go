@@ -0,0 +1,108 @@
+package analytics_api
+
+import (
+	"context"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/pb/analytics_api"
+	"github.com/BearBump/TrackBox/internal/services/analytics"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type AnalyticsAPI struct {
+	analytics_api.UnimplementedAnalyticsServiceServer
+	svc *analytics.Service
+}
+
+func New(svc *analytics.Service) *AnalyticsAPI {
+	return &AnalyticsAPI{svc: svc}
+}
+
+func (a *AnalyticsAPI) GetStatusDistribution(ctx context.Context, req *analytics_api.AnalyticsRequest) (*analytics_api.StatusDistributionResponse, error) {
+	current, buckets, err := a.svc.StatusDistribution(ctx, query(req.GetCarrierCode(), req.GetFrom(), req.GetTo(), req.GetBucket()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := &analytics_api.StatusDistributionResponse{
+		Current: make([]*analytics_api.StatusCount, 0, len(current)),
+		Buckets: make([]*analytics_api.StatusBucket, 0, len(buckets)),
+	}
+	for _, c := range current {
+		out.Current = append(out.Current, &analytics_api.StatusCount{CarrierCode: c.CarrierCode, Status: c.Status, Trackings: c.Trackings})
+	}
+	for _, b := range buckets {
+		out.Buckets = append(out.Buckets, &analytics_api.StatusBucket{
+			Start:       timestamppb.New(b.Start),
+			CarrierCode: b.CarrierCode,
+			Entered:     b.Entered,
+		})
+	}
+	return out, nil
+}
+
+func (a *AnalyticsAPI) GetTransitTimes(ctx context.Context, req *analytics_api.TransitTimesRequest) (*analytics_api.TransitTimesResponse, error) {
+	buckets, err := a.svc.TransitTimes(ctx, query(req.GetCarrierCode(), req.GetFrom(), req.GetTo(), req.GetBucket()), req.GetPercentiles())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := &analytics_api.TransitTimesResponse{Buckets: make([]*analytics_api.TransitBucket, 0, len(buckets))}
+	for _, b := range buckets {
+		pb := &analytics_api.TransitBucket{
+			Start:       timestamppb.New(b.Start),
+			CarrierCode: b.CarrierCode,
+			Deliveries:  b.Deliveries,
+			Percentiles: make([]*analytics_api.TransitPercentile, 0, len(b.Percentiles)),
+		}
+		for _, p := range b.Percentiles {
+			pb.Percentiles = append(pb.Percentiles, &analytics_api.TransitPercentile{Percentile: p.Percentile, Value: durationpb.New(p.Value)})
+		}
+		out.Buckets = append(out.Buckets, pb)
+	}
+	return out, nil
+}
+
+func (a *AnalyticsAPI) GetCheckStats(ctx context.Context, req *analytics_api.AnalyticsRequest) (*analytics_api.CheckStatsResponse, error) {
+	buckets, err := a.svc.CheckStats(ctx, query(req.GetCarrierCode(), req.GetFrom(), req.GetTo(), req.GetBucket()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := &analytics_api.CheckStatsResponse{Buckets: make([]*analytics_api.CheckBucket, 0, len(buckets))}
+	for _, b := range buckets {
+		out.Buckets = append(out.Buckets, toPBCheckBucket(b))
+	}
+	return out, nil
+}
+
+func query(carrierCode string, from, to *timestamppb.Timestamp, bucket string) analytics.Query {
+	q := analytics.Query{CarrierCode: carrierCode, Bucket: bucket}
+	if from != nil {
+		q.From = from.AsTime()
+	}
+	if to != nil {
+		q.To = to.AsTime()
+	}
+	return q
+}
+
+func toPBCheckBucket(b *models.CheckBucket) *analytics_api.CheckBucket {
+	return &analytics_api.CheckBucket{
+		Start:         timestamppb.New(b.Start),
+		CarrierCode:   b.CarrierCode,
+		Checks:        b.Checks,
+		FailedChecks:  b.FailedChecks,
+		FailureRate:   b.FailureRate,
+		StatusChanges: b.StatusChanges,
+		Delivered:     b.Delivered,
+	}
+}
+
+func toStatus(err error) error {
+	if errors.Is(err, analytics.ErrInvalidArgument) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package analytics_api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/pb/analytics_api"
+	"github.com/BearBump/TrackBox/internal/services/analytics"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type fakeRepo struct {
+	from, to time.Time
+}
+
+func (r *fakeRepo) StatusCounts(context.Context, string) ([]models.StatusCount, error) {
+	return []models.StatusCount{{CarrierCode: "CDEK", Status: models.TrackingStatusDelivered, Trackings: 4}}, nil
+}
+
+func (r *fakeRepo) DailyStats(_ context.Context, _ string, from, to time.Time) ([]models.CarrierDayStats, error) {
+	r.from, r.to = from, to
+	return []models.CarrierDayStats{{Day: from, CarrierCode: "CDEK", Checks: 4, FailedChecks: 1}}, nil
+}
+
+func (r *fakeRepo) StatusEntries(_ context.Context, _ string, from, _ time.Time) ([]models.StatusEntries, error) {
+	return []models.StatusEntries{{Day: from, CarrierCode: "CDEK", Status: models.TrackingStatusDelivered, Entered: 4}}, nil
+}
+
+func (r *fakeRepo) TransitHistogram(_ context.Context, _ string, from, _ time.Time) ([]models.TransitHistogramRow, error) {
+	return []models.TransitHistogramRow{{Day: from, CarrierCode: "CDEK", Bucket: models.TransitBucketIndex(25 * time.Hour), Deliveries: 2}}, nil
+}
+
+func TestAnalyticsAPI(t *testing.T) {
+	from := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 6)
+	repo := &fakeRepo{}
+	api := New(analytics.New(repo))
+	ctx := context.Background()
+
+	checks, err := api.GetCheckStats(ctx, &analytics_api.AnalyticsRequest{From: timestamppb.New(from), To: timestamppb.New(to)})
+	require.NoError(t, err)
+	require.Equal(t, from, repo.from)
+	require.Equal(t, to, repo.to)
+	require.Len(t, checks.GetBuckets(), 1)
+	require.Equal(t, 0.25, checks.GetBuckets()[0].GetFailureRate())
+
+	dist, err := api.GetStatusDistribution(ctx, &analytics_api.AnalyticsRequest{From: timestamppb.New(from), To: timestamppb.New(to), Bucket: "WEEK"})
+	require.NoError(t, err)
+	require.EqualValues(t, 4, dist.GetCurrent()[0].GetTrackings())
+	require.Equal(t, map[string]int64{models.TrackingStatusDelivered: 4}, dist.GetBuckets()[0].GetEntered())
+
+	transit, err := api.GetTransitTimes(ctx, &analytics_api.TransitTimesRequest{From: timestamppb.New(from), To: timestamppb.New(to), Percentiles: []float64{50}})
+	require.NoError(t, err)
+	p := transit.GetBuckets()[0].GetPercentiles()[0]
+	require.Equal(t, 50.0, p.GetPercentile())
+	require.Equal(t, 30*time.Hour, p.GetValue().AsDuration())
+
+	_, err = api.GetCheckStats(ctx, &analytics_api.AnalyticsRequest{Bucket: "YEAR"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,245 @@
+// Package bulk_api — HTTP-ручки массового импорта/экспорта (потоковые тела не ложатся на grpc-gateway).
+package bulk_api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/services/bulk"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxUploadBytes — предел размера загружаемого файла.
+const maxUploadBytes = 1 << 30
+
+type BulkAPI struct {
+	svc *bulk.Service
+}
+
+func New(svc *bulk.Service) *BulkAPI {
+	return &BulkAPI{svc: svc}
+}
+
+// Register добавляет ручки в роутер gateway (до монтирования grpc-gateway на "/").
+func (a *BulkAPI) Register(r chi.Router) {
+	r.Post("/imports", a.startImport)
+	r.Get("/imports/{id}", a.getImport)
+	r.Get("/imports/{id}/errors", a.listImportErrors)
+	r.Post("/imports/{id}/resume", a.resumeImport)
+	r.Get("/trackings/export", a.export)
+}
+
+type importJobView struct {
+	ID            uint64     `json:"id"`
+	Status        string     `json:"status"`
+	Format        string     `json:"format"`
+	Source        string     `json:"source,omitempty"`
+	TotalRows     int64      `json:"totalRows"`
+	InvalidRows   int64      `json:"invalidRows"`
+	ProcessedRows int64      `json:"processedRows"`
+	ImportedRows  int64      `json:"importedRows"`
+	Progress      float64    `json:"progress"` // 0..1
+	LastError     *string    `json:"lastError,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	FinishedAt    *time.Time `json:"finishedAt,omitempty"`
+}
+
+func jobView(j *models.ImportJob) importJobView {
+	v := importJobView{
+		ID:            j.ID,
+		Status:        j.Status,
+		Format:        j.Format,
+		Source:        j.Source,
+		TotalRows:     j.TotalRows,
+		InvalidRows:   j.InvalidRows,
+		ProcessedRows: j.ProcessedRows,
+		ImportedRows:  j.ImportedRows,
+		LastError:     j.LastError,
+		CreatedAt:     j.CreatedAt,
+		UpdatedAt:     j.UpdatedAt,
+		FinishedAt:    j.FinishedAt,
+	}
+	if j.TotalRows > 0 {
+		v.Progress = float64(j.ProcessedRows) / float64(j.TotalRows)
+	}
+	if j.Status == models.ImportJobDone {
+		v.Progress = 1
+	}
+	return v
+}
+
+type importErrorView struct {
+	Line        int64  `json:"line"`
+	CarrierCode string `json:"carrierCode,omitempty"`
+	TrackNumber string `json:"trackNumber,omitempty"`
+	Error       string `json:"error"`
+}
+
+// startImport: тело запроса — файл целиком; формат из ?format= или Content-Type.
+func (a *BulkAPI) startImport(w http.ResponseWriter, r *http.Request) {
+	format, err := bulk.DetectFormat(r.URL.Query().Get("format"), "", formatFromContentType(r.Header.Get("Content-Type")))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	body := http.MaxBytesReader(w, r.Body, maxUploadBytes)
+	job, err := a.svc.StartImport(r.Context(), body, format, r.URL.Query().Get("source"))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, err)
+			return
+		}
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	w.Header().Set("Location", "/imports/"+strconv.FormatUint(job.ID, 10))
+	writeJSON(w, http.StatusAccepted, jobView(job))
+}
+
+func (a *BulkAPI) getImport(w http.ResponseWriter, r *http.Request) {
+	id, ok := jobID(w, r)
+	if !ok {
+		return
+	}
+	job, err := a.svc.GetImportJob(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, jobView(job))
+}
+
+func (a *BulkAPI) listImportErrors(w http.ResponseWriter, r *http.Request) {
+	id, ok := jobID(w, r)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	rows, err := a.svc.ListImportErrors(r.Context(), id, limit, offset)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	out := make([]importErrorView, 0, len(rows))
+	for _, row := range rows {
+		v := importErrorView{Line: row.Line, CarrierCode: row.CarrierCode, TrackNumber: row.TrackNumber}
+		if row.Error != nil {
+			v.Error = *row.Error
+		}
+		out = append(out, v)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"errors": out})
+}
+
+func (a *BulkAPI) resumeImport(w http.ResponseWriter, r *http.Request) {
+	id, ok := jobID(w, r)
+	if !ok {
+		return
+	}
+	job, err := a.svc.ResumeImport(r.Context(), id)
+	if errors.Is(err, bulk.ErrJobNotFound) {
+		writeError(w, http.StatusConflict, errors.New("no failed import job with this id"))
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, jobView(job))
+}
+
+// export: ?format=jsonl|csv&carrier=&status=&tag=&external_id=&updated_since=RFC3339&events=true
+// (tag можно повторять — нужны все).
+func (a *BulkAPI) export(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	format, err := bulk.DetectFormat(q.Get("format"), "", bulk.FormatJSONL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	opts := bulk.ExportOptions{
+		Format: format,
+		Filter: pgtracking.TrackingFilter{
+			CarrierCode: q.Get("carrier"),
+			Status:      q.Get("status"),
+			Tags:        q["tag"],
+			ExternalID:  q.Get("external_id"),
+		},
+	}
+	if v := q.Get("updated_since"); v != "" {
+		ts, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("updated_since must be RFC3339"))
+			return
+		}
+		opts.Filter.UpdatedSince = &ts
+	}
+	if v := q.Get("events"); v != "" {
+		if opts.Events, err = strconv.ParseBool(v); err != nil {
+			writeError(w, http.StatusBadRequest, errors.New("events must be a boolean"))
+			return
+		}
+	}
+
+	if format == bulk.FormatCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="trackings.`+format+`"`)
+	n, err := a.svc.Export(r.Context(), w, opts)
+	if err != nil {
+		// Заголовки уже отправлены — остаётся оборвать поток и залогировать.
+		slog.Error("export failed", "exported", n, "error", err.Error())
+	}
+}
+
+func formatFromContentType(ct string) string {
+	mt, _, _ := mime.ParseMediaType(ct)
+	switch mt {
+	case "text/csv":
+		return bulk.FormatCSV
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return bulk.FormatJSONL
+	default:
+		return bulk.FormatCSV
+	}
+}
+
+func jobID(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("invalid import job id"))
+		return 0, false
+	}
+	return id, true
+}
+
+func writeServiceError(w http.ResponseWriter, err error) {
+	if errors.Is(err, bulk.ErrJobNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	slog.Error("bulk api", "error", err.Error())
+	writeError(w, http.StatusInternalServerError, errors.New("internal error"))
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
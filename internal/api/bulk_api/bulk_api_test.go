@@ -0,0 +1,149 @@
+package bulk_api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/services/bulk"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo реализует только то, что нужно ручкам; остальные методы паникуют (nil-интерфейс).
+type fakeRepo struct {
+	bulk.Repository
+
+	job    *models.ImportJob
+	rows   []models.ImportRow
+	filter pgtracking.TrackingFilter
+}
+
+func (r *fakeRepo) CreateImportJob(_ context.Context, format, source string) (*models.ImportJob, error) {
+	r.job = &models.ImportJob{ID: 7, Status: models.ImportJobUploading, Format: format, Source: source}
+	return r.job, nil
+}
+
+func (r *fakeRepo) AddImportRows(_ context.Context, _ uint64, rows []models.ImportRow) error {
+	r.rows = append(r.rows, rows...)
+	return nil
+}
+
+func (r *fakeRepo) FinishImportUpload(_ context.Context, _ uint64, total, invalid int64) error {
+	r.job.Status, r.job.TotalRows, r.job.InvalidRows = models.ImportJobQueued, total, invalid
+	return nil
+}
+
+func (r *fakeRepo) GetImportJob(_ context.Context, id uint64) (*models.ImportJob, error) {
+	if r.job == nil || r.job.ID != id {
+		return nil, bulk.ErrJobNotFound
+	}
+	return r.job, nil
+}
+
+func (r *fakeRepo) ListImportErrors(context.Context, uint64, int, int) ([]models.ImportRow, error) {
+	var out []models.ImportRow
+	for _, row := range r.rows {
+		if row.Error != nil {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) RetryImportJob(context.Context, uint64) (*models.ImportJob, error) {
+	return nil, bulk.ErrJobNotFound
+}
+
+func (r *fakeRepo) ScanTrackings(_ context.Context, f pgtracking.TrackingFilter, afterID uint64, _ int) ([]*models.Tracking, error) {
+	r.filter = f
+	if afterID > 0 {
+		return nil, nil
+	}
+	return []*models.Tracking{{ID: 1, CarrierCode: "CDEK", TrackNumber: "A1", Status: models.TrackingStatusInTransit}}, nil
+}
+
+func newServer(repo *fakeRepo) *httptest.Server {
+	r := chi.NewRouter()
+	New(bulk.New(repo, bulk.Config{})).Register(r)
+	return httptest.NewServer(r)
+}
+
+func TestImportFlow(t *testing.T) {
+	repo := &fakeRepo{}
+	srv := newServer(repo)
+	defer srv.Close()
+
+	body := `{"carrier_code":"CDEK","track_number":"1000000001"}` + "\n" + `{"carrier_code":"CDEK"}` + "\n"
+	resp, err := http.Post(srv.URL+"/imports?source=shop.jsonl", "application/x-ndjson", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	require.Equal(t, "/imports/7", resp.Header.Get("Location"))
+
+	var job importJobView
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+	require.Equal(t, models.ImportJobQueued, job.Status)
+	require.Equal(t, bulk.FormatJSONL, job.Format)
+	require.Equal(t, "shop.jsonl", job.Source)
+	require.EqualValues(t, 2, job.TotalRows)
+	require.EqualValues(t, 1, job.InvalidRows)
+
+	repo.job.ProcessedRows = 1
+	resp, err = http.Get(srv.URL + "/imports/7")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+	require.InDelta(t, 0.5, job.Progress, 1e-9)
+
+	resp, err = http.Get(srv.URL + "/imports/7/errors")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var errs struct{ Errors []importErrorView }
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&errs))
+	require.Equal(t, []importErrorView{{Line: 2, CarrierCode: "CDEK", Error: "track_number is required"}}, errs.Errors)
+
+	resp, err = http.Get(srv.URL + "/imports/8")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Post(srv.URL+"/imports/7/resume", "", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp, err = http.Post(srv.URL+"/imports?format=xml", "text/plain", strings.NewReader("x"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestExport(t *testing.T) {
+	repo := &fakeRepo{}
+	srv := newServer(repo)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/trackings/export?format=csv&carrier=CDEK&status=IN_TRANSIT&updated_since=2026-01-01T00:00:00Z")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	b, _ := io.ReadAll(resp.Body)
+	require.Contains(t, string(b), "\n1,CDEK,A1,IN_TRANSIT,")
+	require.Equal(t, "CDEK", repo.filter.CarrierCode)
+	require.Equal(t, "IN_TRANSIT", repo.filter.Status)
+	require.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), repo.filter.UpdatedSince.UTC())
+
+	resp, err = http.Get(srv.URL + "/trackings/export?updated_since=yesterday")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,139 @@
+package carriers_api
+
+import (
+	"context"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/pb/carriers_api"
+	"github.com/BearBump/TrackBox/internal/services/carriers"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type CarriersAPI struct {
+	carriers_api.UnimplementedCarriersServiceServer
+	svc *carriers.Service
+}
+
+func New(svc *carriers.Service) *CarriersAPI {
+	return &CarriersAPI{svc: svc}
+}
+
+func (a *CarriersAPI) ListCarriers(ctx context.Context, _ *carriers_api.ListCarriersRequest) (*carriers_api.ListCarriersResponse, error) {
+	list, err := a.svc.List(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := &carriers_api.ListCarriersResponse{Carriers: make([]*carriers_api.Carrier, 0, len(list))}
+	for _, c := range list {
+		out.Carriers = append(out.Carriers, toPBCarrier(c))
+	}
+	return out, nil
+}
+
+func (a *CarriersAPI) CreateCarrier(ctx context.Context, req *carriers_api.CreateCarrierRequest) (*carriers_api.Carrier, error) {
+	if req.GetCarrier() == nil {
+		return nil, status.Error(codes.InvalidArgument, "carrier is required")
+	}
+	c, err := a.svc.Create(ctx, fromPBCarrier(req.GetCarrier()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBCarrier(c), nil
+}
+
+func (a *CarriersAPI) UpdateCarrier(ctx context.Context, req *carriers_api.UpdateCarrierRequest) (*carriers_api.Carrier, error) {
+	if req.GetCarrier() == nil {
+		return nil, status.Error(codes.InvalidArgument, "carrier is required")
+	}
+	in := fromPBCarrier(req.GetCarrier())
+	in.Code = req.GetCode()
+	fields := req.GetUpdateMask().GetPaths()
+	if len(fields) == 0 {
+		fields = populatedFields(req.GetCarrier())
+	}
+	c, err := a.svc.Update(ctx, in, fields)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBCarrier(c), nil
+}
+
+func (a *CarriersAPI) DisableCarrier(ctx context.Context, req *carriers_api.DisableCarrierRequest) (*carriers_api.Carrier, error) {
+	c, err := a.svc.Disable(ctx, req.GetCode())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBCarrier(c), nil
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, carriers.ErrInvalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, carriers.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, carriers.ErrExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return err
+	}
+}
+
+// populatedFields — неявная маска обновления: поля, заданные в запросе (ненулевые; enabled — только true).
+func populatedFields(c *carriers_api.Carrier) []string {
+	var out []string
+	if c.GetDisplayName() != "" {
+		out = append(out, models.CarrierFieldDisplayName)
+	}
+	if c.GetBackend() != "" {
+		out = append(out, models.CarrierFieldBackend)
+	}
+	if c.GetCredentialsRef() != "" {
+		out = append(out, models.CarrierFieldCredentialsRef)
+	}
+	if c.GetRateLimitPerMinute() != 0 {
+		out = append(out, models.CarrierFieldRateLimit)
+	}
+	if c.GetSchedulingJson() != "" {
+		out = append(out, models.CarrierFieldScheduling)
+	}
+	if c.GetEnabled() {
+		out = append(out, models.CarrierFieldEnabled)
+	}
+	return out
+}
+
+func fromPBCarrier(c *carriers_api.Carrier) models.Carrier {
+	out := models.Carrier{
+		Code:               c.GetCode(),
+		DisplayName:        c.GetDisplayName(),
+		Backend:            c.GetBackend(),
+		CredentialsRef:     c.GetCredentialsRef(),
+		RateLimitPerMinute: c.GetRateLimitPerMinute(),
+		Enabled:            c.GetEnabled(),
+	}
+	if s := c.GetSchedulingJson(); s != "" {
+		out.SchedulingJSON = &s
+	}
+	return out
+}
+
+func toPBCarrier(c *models.Carrier) *carriers_api.Carrier {
+	out := &carriers_api.Carrier{
+		Code:               c.Code,
+		DisplayName:        c.DisplayName,
+		Backend:            c.Backend,
+		CredentialsRef:     c.CredentialsRef,
+		RateLimitPerMinute: c.RateLimitPerMinute,
+		Enabled:            c.Enabled,
+		CreatedAt:          timestamppb.New(c.CreatedAt),
+		UpdatedAt:          timestamppb.New(c.UpdatedAt),
+	}
+	if c.SchedulingJSON != nil {
+		out.SchedulingJson = *c.SchedulingJSON
+	}
+	return out
+}
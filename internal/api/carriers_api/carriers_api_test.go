@@ -0,0 +1,157 @@
+package carriers_api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/pb/carriers_api"
+	"github.com/BearBump/TrackBox/internal/services/carriers"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+type fakeRepo struct {
+	byCode map[string]models.Carrier
+}
+
+func (r *fakeRepo) ListCarriers(context.Context) ([]*models.Carrier, error) {
+	out := make([]*models.Carrier, 0, len(r.byCode))
+	for _, c := range r.byCode {
+		c := c
+		out = append(out, &c)
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) GetCarrier(_ context.Context, code string) (*models.Carrier, error) {
+	c, ok := r.byCode[code]
+	if !ok {
+		return nil, carriers.ErrNotFound
+	}
+	return &c, nil
+}
+
+func (r *fakeRepo) CreateCarrier(_ context.Context, c models.Carrier) (*models.Carrier, error) {
+	if _, ok := r.byCode[c.Code]; ok {
+		return nil, carriers.ErrExists
+	}
+	r.byCode[c.Code] = c
+	return &c, nil
+}
+
+func (r *fakeRepo) UpdateCarrier(_ context.Context, c models.Carrier, fields []string) (*models.Carrier, error) {
+	cur, ok := r.byCode[c.Code]
+	if !ok {
+		return nil, carriers.ErrNotFound
+	}
+	for _, f := range fields {
+		switch f {
+		case models.CarrierFieldDisplayName:
+			cur.DisplayName = c.DisplayName
+		case models.CarrierFieldBackend:
+			cur.Backend = c.Backend
+		case models.CarrierFieldCredentialsRef:
+			cur.CredentialsRef = c.CredentialsRef
+		case models.CarrierFieldRateLimit:
+			cur.RateLimitPerMinute = c.RateLimitPerMinute
+		case models.CarrierFieldScheduling:
+			cur.SchedulingJSON = c.SchedulingJSON
+		case models.CarrierFieldEnabled:
+			cur.Enabled = c.Enabled
+		}
+	}
+	r.byCode[c.Code] = cur
+	return &cur, nil
+}
+
+func (r *fakeRepo) SetCarrierEnabled(_ context.Context, code string, enabled bool) (*models.Carrier, error) {
+	c, ok := r.byCode[code]
+	if !ok {
+		return nil, carriers.ErrNotFound
+	}
+	c.Enabled = enabled
+	r.byCode[code] = c
+	return &c, nil
+}
+
+func TestCarriersAPI_Flow(t *testing.T) {
+	ctx := context.Background()
+	api := New(carriers.New(&fakeRepo{byCode: map[string]models.Carrier{}}))
+
+	c, err := api.CreateCarrier(ctx, &carriers_api.CreateCarrierRequest{Carrier: &carriers_api.Carrier{
+		Code:               "dhl",
+		DisplayName:        "DHL",
+		Backend:            "track24",
+		CredentialsRef:     "env:DHL_API_KEY",
+		RateLimitPerMinute: 30,
+		SchedulingJson:     `{"jitter":"none"}`,
+		Enabled:            true,
+	}})
+	require.NoError(t, err)
+	require.Equal(t, "DHL", c.GetCode())
+	require.Equal(t, `{"jitter":"none"}`, c.GetSchedulingJson())
+
+	_, err = api.CreateCarrier(ctx, &carriers_api.CreateCarrierRequest{Carrier: &carriers_api.Carrier{Code: "DHL", DisplayName: "DHL"}})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = api.CreateCarrier(ctx, &carriers_api.CreateCarrierRequest{Carrier: &carriers_api.Carrier{Code: "UPS"}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// без маски меняются только переданные поля: enabled и политика не сбрасываются
+	c, err = api.UpdateCarrier(ctx, &carriers_api.UpdateCarrierRequest{Code: "DHL", Carrier: &carriers_api.Carrier{DisplayName: "DHL Express"}})
+	require.NoError(t, err)
+	require.Equal(t, "DHL Express", c.GetDisplayName())
+	require.True(t, c.GetEnabled())
+	require.Equal(t, `{"jitter":"none"}`, c.GetSchedulingJson())
+	require.EqualValues(t, 30, c.GetRateLimitPerMinute())
+
+	// маска позволяет сбросить поле в пустое значение
+	c, err = api.UpdateCarrier(ctx, &carriers_api.UpdateCarrierRequest{
+		Code:       "DHL",
+		Carrier:    &carriers_api.Carrier{},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"scheduling_json", "rate_limit_per_minute"}},
+	})
+	require.NoError(t, err)
+	require.Empty(t, c.GetSchedulingJson())
+	require.Zero(t, c.GetRateLimitPerMinute())
+	require.Equal(t, "DHL Express", c.GetDisplayName())
+	require.True(t, c.GetEnabled())
+
+	_, err = api.UpdateCarrier(ctx, &carriers_api.UpdateCarrierRequest{
+		Code:       "DHL",
+		Carrier:    &carriers_api.Carrier{Code: "UPS"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"code"}},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = api.UpdateCarrier(ctx, &carriers_api.UpdateCarrierRequest{Code: "DHL", Carrier: &carriers_api.Carrier{}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = api.UpdateCarrier(ctx, &carriers_api.UpdateCarrierRequest{
+		Code:       "DHL",
+		Carrier:    &carriers_api.Carrier{},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"display_name"}},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err), "display_name is required when masked")
+
+	c, err = api.UpdateCarrier(ctx, &carriers_api.UpdateCarrierRequest{
+		Code:       "DHL",
+		Carrier:    &carriers_api.Carrier{},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"enabled"}},
+	})
+	require.NoError(t, err)
+	require.False(t, c.GetEnabled())
+	require.Equal(t, "DHL Express", c.GetDisplayName())
+
+	c, err = api.DisableCarrier(ctx, &carriers_api.DisableCarrierRequest{Code: "DHL"})
+	require.NoError(t, err)
+	require.False(t, c.GetEnabled())
+
+	_, err = api.DisableCarrier(ctx, &carriers_api.DisableCarrierRequest{Code: "UPS"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	list, err := api.ListCarriers(ctx, &carriers_api.ListCarriersRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetCarriers(), 1)
+}
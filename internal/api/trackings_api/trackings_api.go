@@ -2,11 +2,19 @@ package trackings_api
 
 import (
 	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/BearBump/TrackBox/internal/models"
 	pb_models "github.com/BearBump/TrackBox/internal/pb/models"
 	"github.com/BearBump/TrackBox/internal/pb/trackings_api"
 	"github.com/BearBump/TrackBox/internal/services/trackings"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -23,10 +31,7 @@ func New(svc *trackings.Service) *TrackingsAPI {
 func (a *TrackingsAPI) CreateTrackings(ctx context.Context, req *trackings_api.CreateTrackingsRequest) (*trackings_api.CreateTrackingsResponse, error) {
 	in := make([]models.TrackingCreateInput, 0, len(req.GetItems()))
 	for _, it := range req.GetItems() {
-		in = append(in, models.TrackingCreateInput{
-			CarrierCode: it.GetCarrierCode(),
-			TrackNumber: it.GetTrackNumber(),
-		})
+		in = append(in, fromPBCreateInput(it))
 	}
 	ts, err := a.svc.CreateTrackings(ctx, in)
 	if err != nil {
@@ -35,6 +40,98 @@ func (a *TrackingsAPI) CreateTrackings(ctx context.Context, req *trackings_api.C
 	return &trackings_api.CreateTrackingsResponse{Trackings: toPBTrackings(ts)}, nil
 }
 
+// StreamCreateTrackings читает поток пачками по trackings.BulkChunkSize и на каждую пачку сразу
+// отвечает её результатами (счётчики — с начала потока), так что ни память, ни размер ответа
+// не растут с размером потока.
+func (a *TrackingsAPI) StreamCreateTrackings(stream grpc.BidiStreamingServer[pb_models.TrackingCreateInput, trackings_api.StreamCreateTrackingsResponse]) error {
+	ctx := stream.Context()
+	var index, created, existed, invalid uint64
+	chunk := make([]models.TrackingCreateInput, 0, trackings.BulkChunkSize)
+
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		res, err := a.svc.CreateTrackingsBulk(ctx, chunk)
+		if err != nil {
+			return err
+		}
+		results := make([]*trackings_api.CreateTrackingResult, 0, len(res))
+		for _, r := range res {
+			item := &trackings_api.CreateTrackingResult{Index: index, Reason: r.Reason}
+			index++
+			if r.Tracking != nil {
+				item.TrackingId = r.Tracking.ID
+			}
+			switch r.Status {
+			case trackings.ItemCreated:
+				item.Status = trackings_api.CreateTrackingResult_CREATED
+				created++
+			case trackings.ItemExisted:
+				item.Status = trackings_api.CreateTrackingResult_EXISTED
+				existed++
+			default:
+				item.Status = trackings_api.CreateTrackingResult_INVALID
+				invalid++
+			}
+			results = append(results, item)
+		}
+		chunk = chunk[:0]
+		return stream.Send(&trackings_api.StreamCreateTrackingsResponse{
+			Results: results,
+			Created: created,
+			Existed: existed,
+			Invalid: invalid,
+		})
+	}
+
+	for {
+		in, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		chunk = append(chunk, fromPBCreateInput(in))
+		if len(chunk) == trackings.BulkChunkSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+func (a *TrackingsAPI) ListTrackings(ctx context.Context, req *trackings_api.ListTrackingsRequest) (*trackings_api.ListTrackingsResponse, error) {
+	f := pgtracking.TrackingFilter{
+		CarrierCode: req.GetCarrierCode(),
+		Status:      req.GetStatus(),
+		Tags:        req.GetTags(),
+		ExternalID:  req.GetExternalId(),
+		Metadata:    req.GetMetadataJson(),
+	}
+	ts, next, err := a.svc.ListTrackings(ctx, f, req.GetPageToken(), int(req.GetPageSize()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &trackings_api.ListTrackingsResponse{Trackings: toPBTrackings(ts), NextPageToken: next}, nil
+}
+
+func (a *TrackingsAPI) UpdateTracking(ctx context.Context, req *trackings_api.UpdateTrackingRequest) (*pb_models.Tracking, error) {
+	t, err := a.svc.UpdateTracking(ctx, req.GetTrackingId(), models.TrackingPatch{
+		Metadata:   req.MetadataJson,
+		ExternalID: req.ExternalId,
+		ClearTags:  req.GetClearTags(),
+		AddTags:    req.GetAddTags(),
+		RemoveTags: req.GetRemoveTags(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBTrackings([]*models.Tracking{t})[0], nil
+}
+
 func (a *TrackingsAPI) GetTrackingsByIds(ctx context.Context, req *trackings_api.GetTrackingsByIdsRequest) (*trackings_api.GetTrackingsByIdsResponse, error) {
 	ts, err := a.svc.GetTrackingsByIDs(ctx, req.GetIds())
 	if err != nil {
@@ -44,12 +141,240 @@ func (a *TrackingsAPI) GetTrackingsByIds(ctx context.Context, req *trackings_api
 }
 
 func (a *TrackingsAPI) ListTrackingEvents(ctx context.Context, req *trackings_api.ListTrackingEventsRequest) (*trackings_api.ListTrackingEventsResponse, error) {
-	evs, err := a.svc.ListTrackingEvents(ctx, req.GetTrackingId(), int(req.GetLimit()), int(req.GetOffset()))
+	q := trackings.EventsQuery{
+		Filter:    pgtracking.EventFilter{Country: req.GetCountry(), Region: req.GetRegion()},
+		PageSize:  int(req.GetPageSize()),
+		PageToken: req.GetPageToken(),
+		Offset:    int(req.GetOffset()),
+		WithTotal: req.GetIncludeTotalCount(),
+	}
+	if q.PageSize == 0 {
+		q.PageSize = int(req.GetLimit())
+	}
+	if req.GetFrom() != nil {
+		q.Filter.From = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		q.Filter.To = req.GetTo().AsTime()
+	}
+	switch strings.ToUpper(req.GetOrder()) {
+	case "", "DESC":
+	case "ASC":
+		q.Ascending = true
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown order %q (want ASC|DESC)", req.GetOrder())
+	}
+
+	page, err := a.svc.ListTrackingEventsPage(ctx, req.GetTrackingId(), q)
 	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &trackings_api.ListTrackingEventsResponse{
+		Events:        toPBEvents(page.Events),
+		NextPageToken: page.NextPageToken,
+		TotalCount:    page.TotalCount,
+	}, nil
+}
+
+func (a *TrackingsAPI) BatchListTrackingEvents(ctx context.Context, req *trackings_api.BatchListTrackingEventsRequest) (*trackings_api.BatchListTrackingEventsResponse, error) {
+	var since time.Time
+	if req.GetSince() != nil {
+		since = req.GetSince().AsTime()
+	}
+	res, err := a.svc.BatchListTrackingEvents(ctx, req.GetTrackingIds(), int(req.GetLimitPerTracking()), since)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := &trackings_api.BatchListTrackingEventsResponse{Trackings: make([]*trackings_api.TrackingEvents, 0, len(res))}
+	for _, r := range res {
+		out.Trackings = append(out.Trackings, &trackings_api.TrackingEvents{TrackingId: r.TrackingID, Events: toPBEvents(r.Events)})
+	}
+	return out, nil
+}
+
+func (a *TrackingsAPI) GetTrackingTimeline(ctx context.Context, req *trackings_api.GetTrackingTimelineRequest) (*trackings_api.GetTrackingTimelineResponse, error) {
+	tl, err := a.svc.GetTrackingTimeline(ctx, req.GetTrackingId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := &pb_models.TrackingTimeline{
+		TrackingId:     tl.TrackingID,
+		CurrentStage:   tl.CurrentStage,
+		ElapsedSeconds: int64(tl.Elapsed.Seconds()),
+	}
+	for _, m := range tl.Milestones {
+		out.Milestones = append(out.Milestones, &pb_models.Milestone{
+			Stage:                m.Stage,
+			Reached:              m.Reached,
+			ReachedAt:            toPBTime(m.ReachedAt),
+			EventId:              m.EventID,
+			SincePreviousSeconds: int64(m.SincePrevious.Seconds()),
+		})
+	}
+	for _, e := range tl.Entries {
+		out.Entries = append(out.Entries, &pb_models.TimelineEntry{
+			Stage:     e.Stage,
+			Status:    e.Status,
+			StatusRaw: e.StatusRaw,
+			Location:  derefString(e.Location),
+			Message:   derefString(e.Message),
+			Place:     toPBPlace(e.Place),
+			FirstAt:   timestamppb.New(e.FirstAt),
+			LastAt:    timestamppb.New(e.LastAt),
+			EventIds:  e.EventIDs,
+		})
+	}
+	return &trackings_api.GetTrackingTimelineResponse{Timeline: out}, nil
+}
+
+func (a *TrackingsAPI) ListEtaHistory(ctx context.Context, req *trackings_api.ListEtaHistoryRequest) (*trackings_api.ListEtaHistoryResponse, error) {
+	changes, err := a.svc.ListETAHistory(ctx, req.GetTrackingId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := &trackings_api.ListEtaHistoryResponse{Changes: make([]*pb_models.EtaChange, 0, len(changes))}
+	for _, c := range changes {
+		out.Changes = append(out.Changes, &pb_models.EtaChange{
+			EstimatedDelivery:         timestamppb.New(c.EstimatedDelivery),
+			PreviousEstimatedDelivery: toPBTime(c.Previous),
+			ObservedAt:                timestamppb.New(c.ObservedAt),
+			SlipSeconds:               int64(c.Slip().Seconds()),
+		})
+	}
+	return out, nil
+}
+
+func (a *TrackingsAPI) ListEventRevisions(ctx context.Context, req *trackings_api.ListEventRevisionsRequest) (*trackings_api.ListEventRevisionsResponse, error) {
+	revs, err := a.svc.ListEventRevisions(ctx, req.GetTrackingId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := &trackings_api.ListEventRevisionsResponse{Revisions: make([]*pb_models.EventRevision, 0, len(revs))}
+	for _, r := range revs {
+		out.Revisions = append(out.Revisions, &pb_models.EventRevision{
+			EventId:    r.EventID,
+			Kind:       r.Kind,
+			Previous:   toPBEvents([]*models.TrackingEvent{r.Previous})[0],
+			ObservedAt: timestamppb.New(r.ObservedAt),
+		})
+	}
+	return out, nil
+}
+
+func (a *TrackingsAPI) ListTrackingChecks(ctx context.Context, req *trackings_api.ListTrackingChecksRequest) (*trackings_api.ListTrackingChecksResponse, error) {
+	checks, next, err := a.svc.ListTrackingChecks(ctx, req.GetTrackingId(), req.GetFailedOnly(), req.GetPageToken(), int(req.GetPageSize()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := &trackings_api.ListTrackingChecksResponse{Checks: make([]*pb_models.TrackingCheck, 0, len(checks)), NextPageToken: next}
+	for _, c := range checks {
+		pc := &pb_models.TrackingCheck{
+			TrackingId:   c.TrackingID,
+			CheckedAt:    timestamppb.New(c.CheckedAt),
+			Kind:         c.Kind,
+			Status:       c.Status,
+			ErrorClass:   c.ErrorClass,
+			Worker:       c.Worker,
+			Backend:      c.Backend,
+			LatencyMs:    c.LatencyMS,
+			HttpStatus:   int32(c.HTTPStatus),
+			EventsCount:  int32(c.EventsCount),
+			ResponseHash: c.ResponseHash,
+			ResponseRef:  c.ResponseRef,
+		}
+		if c.Error != nil {
+			pc.Error = *c.Error
+		}
+		out.Checks = append(out.Checks, pc)
+	}
+	return out, nil
+}
+
+func (a *TrackingsAPI) DeleteTracking(ctx context.Context, req *trackings_api.DeleteTrackingRequest) (*emptypb.Empty, error) {
+	if err := a.svc.DeleteTracking(ctx, req.GetTrackingId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *TrackingsAPI) ListChanges(ctx context.Context, req *trackings_api.ListChangesRequest) (*trackings_api.ListChangesResponse, error) {
+	page, err := a.svc.ListChanges(ctx, req.GetCursor(), int(req.GetLimit()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out := &trackings_api.ListChangesResponse{
+		Changes:    make([]*pb_models.Change, 0, len(page.Changes)),
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}
+	for _, c := range page.Changes {
+		pc := &pb_models.Change{Seq: c.Seq}
+		switch {
+		case c.Tracking != nil:
+			pc.Entity = &pb_models.Change_Tracking{Tracking: toPBTrackings([]*models.Tracking{c.Tracking})[0]}
+		case c.Event != nil:
+			pc.Entity = &pb_models.Change_Event{Event: toPBEvents([]*models.TrackingEvent{c.Event})[0]}
+		case c.Deleted != nil:
+			pc.Entity = &pb_models.Change_Deleted{Deleted: &pb_models.TrackingTombstone{
+				TrackingId:  c.Deleted.TrackingID,
+				CarrierCode: c.Deleted.CarrierCode,
+				TrackNumber: c.Deleted.TrackNumber,
+				ExternalId:  derefString(c.Deleted.ExternalID),
+				DeletedAt:   timestamppb.New(c.Deleted.DeletedAt),
+			}}
+		}
+		out.Changes = append(out.Changes, pc)
+	}
+	return out, nil
+}
+
+func (a *TrackingsAPI) RefreshTracking(ctx context.Context, req *trackings_api.RefreshTrackingRequest) (*emptypb.Empty, error) {
+	if err := a.svc.RefreshTracking(ctx, req.GetTrackingId()); err != nil {
 		return nil, err
 	}
+	return &emptypb.Empty{}, nil
+}
+
+func (a *TrackingsAPI) CheckTrackingNow(ctx context.Context, req *trackings_api.CheckTrackingNowRequest) (*trackings_api.CheckTrackingNowResponse, error) {
+	res, err := a.svc.CheckTrackingNow(ctx, req.GetTrackingId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &trackings_api.CheckTrackingNowResponse{
+		Tracking:  toPBTrackings([]*models.Tracking{res.Tracking})[0],
+		NewEvents: toPBEvents(res.NewEvents),
+		Queued:    res.Queued,
+	}, nil
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, trackings.ErrInvalidArgument):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, trackings.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return err
+	}
+}
+
+func fromPBCreateInput(in *pb_models.TrackingCreateInput) models.TrackingCreateInput {
+	return models.TrackingCreateInput{
+		CarrierCode: in.GetCarrierCode(),
+		TrackNumber: in.GetTrackNumber(),
+		Metadata:    optString(in.GetMetadataJson()),
+		Tags:        in.GetTags(),
+		ExternalID:  optString(in.GetExternalId()),
+	}
+}
+
+func toPBEvents(evs []*models.TrackingEvent) []*pb_models.TrackingEvent {
 	out := make([]*pb_models.TrackingEvent, 0, len(evs))
 	for _, e := range evs {
+		var createdAt *timestamppb.Timestamp
+		if !e.CreatedAt.IsZero() {
+			createdAt = timestamppb.New(e.CreatedAt)
+		}
 		out = append(out, &pb_models.TrackingEvent{
 			Id:         e.ID,
 			TrackingId: e.TrackingID,
@@ -59,17 +384,15 @@ func (a *TrackingsAPI) ListTrackingEvents(ctx context.Context, req *trackings_ap
 			Location:   derefString(e.Location),
 			Message:    derefString(e.Message),
 			PayloadJson: derefString(e.PayloadJSON),
-			CreatedAt:  timestamppb.New(e.CreatedAt),
+			CreatedAt:  createdAt,
+			Place:      toPBPlace(e.Place),
+			EventTimeRaw: derefString(e.EventTimeRaw),
+			TimeInferred: e.TimeInferred,
+			Revision:     e.Revision,
+			RemovedAt:    toPBTime(e.RemovedAt),
 		})
 	}
-	return &trackings_api.ListTrackingEventsResponse{Events: out}, nil
-}
-
-func (a *TrackingsAPI) RefreshTracking(ctx context.Context, req *trackings_api.RefreshTrackingRequest) (*emptypb.Empty, error) {
-	if err := a.svc.RefreshTracking(ctx, req.GetTrackingId()); err != nil {
-		return nil, err
-	}
-	return &emptypb.Empty{}, nil
+	return out
 }
 
 func toPBTrackings(ts []*models.Tracking) []*pb_models.Tracking {
@@ -96,11 +419,57 @@ func toPBTrackings(ts []*models.Tracking) []*pb_models.Tracking {
 			LastError:     derefString(t.LastError),
 			CreatedAt:     timestamppb.New(t.CreatedAt),
 			UpdatedAt:     timestamppb.New(t.UpdatedAt),
+			MetadataJson:  derefString(t.Metadata),
+			Tags:          t.Tags,
+			ExternalId:    derefString(t.ExternalID),
+			Shipment:      toPBShipment(t.Shipment),
 		})
 	}
 	return out
 }
 
+func toPBPlace(l *models.Location) *pb_models.EventLocation {
+	if l.IsEmpty() {
+		return nil
+	}
+	return &pb_models.EventLocation{
+		Country:    l.Country,
+		Region:     l.Region,
+		City:       l.City,
+		PostalCode: l.PostalCode,
+		Lat:        l.Lat,
+		Lon:        l.Lon,
+	}
+}
+
+func toPBShipment(d *models.ShipmentDetails) *pb_models.ShipmentDetails {
+	if d == nil {
+		return nil
+	}
+	return &pb_models.ShipmentDetails{
+		EstimatedDelivery: toPBTime(d.EstimatedDelivery),
+		WeightGrams:       d.WeightGrams,
+		Origin:            d.Origin,
+		Destination:       d.Destination,
+		RecipientCity:     d.RecipientCity,
+		ServiceType:       d.ServiceType,
+	}
+}
+
+func toPBTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func optString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
 func derefString(s *string) string {
 	if s == nil {
 		return ""
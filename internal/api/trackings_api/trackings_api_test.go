@@ -2,6 +2,9 @@ package trackings_api
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,24 +14,102 @@ import (
 	"github.com/BearBump/TrackBox/internal/services/trackings"
 	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type repo struct {
 	created []*models.Tracking
 	events  []*models.TrackingEvent
+
+	bulkCalls int
+
+	patch       models.TrackingPatch
+	filter      pgtracking.TrackingFilter
+	eventFilter pgtracking.EventFilter
+	eventsAsc   bool
+	eta         []*models.ETAChange
+	revisions   []*models.EventRevision
+	checks      []*models.TrackingCheck
+	failedOnly  bool
+	changes     []*models.Change
 }
 
 func (r *repo) CreateOrGetTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]*models.Tracking, error) {
 	return r.created, nil
 }
+// BulkCreateTrackings: треки с номером на "9" считаются существующими.
+func (r *repo) BulkCreateTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]pgtracking.CreateResult, error) {
+	r.bulkCalls++
+	out := make([]pgtracking.CreateResult, 0, len(items))
+	for i, it := range items {
+		t := &models.Tracking{ID: uint64(r.bulkCalls*100_000 + i + 1), CarrierCode: it.CarrierCode, TrackNumber: it.TrackNumber}
+		out = append(out, pgtracking.CreateResult{Tracking: t, Created: !strings.HasPrefix(it.TrackNumber, "9")})
+	}
+	return out, nil
+}
 func (r *repo) GetTrackingsByIDs(ctx context.Context, ids []uint64) ([]*models.Tracking, error) {
 	return r.created, nil
 }
-func (r *repo) ListTrackingEvents(ctx context.Context, trackingID uint64, limit, offset int) ([]*models.TrackingEvent, error) {
+func (r *repo) ListTrackingEvents(ctx context.Context, trackingID uint64, f pgtracking.EventFilter, limit, offset int) ([]*models.TrackingEvent, error) {
+	r.eventFilter = f
 	return r.events, nil
 }
+func (r *repo) ListEventKeys(ctx context.Context, trackingID uint64) ([]string, error) {
+	keys := make([]string, 0, len(r.events))
+	for _, e := range r.events {
+		keys = append(keys, e.DedupKey())
+	}
+	return keys, nil
+}
+func (r *repo) ListTrackingEventsPage(ctx context.Context, trackingID uint64, f pgtracking.EventFilter, asc bool, after *pgtracking.EventCursor, limit int) ([]*models.TrackingEvent, error) {
+	r.eventFilter, r.eventsAsc = f, asc
+	return r.events, nil
+}
+func (r *repo) DeleteTracking(ctx context.Context, id uint64) error {
+	if id != 1 {
+		return pgtracking.ErrTrackingNotFound
+	}
+	return nil
+}
+func (r *repo) ListChanges(ctx context.Context, after pgtracking.ChangeCursor, limit int) ([]*models.Change, error) {
+	return r.changes, nil
+}
+// ListLatestEvents: все события r.events — у первого трека.
+func (r *repo) ListLatestEvents(ctx context.Context, trackingIDs []uint64, limit int, since time.Time) (map[uint64][]*models.TrackingEvent, error) {
+	return map[uint64][]*models.TrackingEvent{trackingIDs[0]: r.events}, nil
+}
+func (r *repo) CountTrackingEvents(ctx context.Context, trackingID uint64, f pgtracking.EventFilter) (int64, error) {
+	return int64(len(r.events)), nil
+}
 func (r *repo) RefreshTracking(ctx context.Context, trackingID uint64) error { return nil }
 func (r *repo) ApplyTrackingUpdate(ctx context.Context, upd pgtracking.TrackingUpdate) error { return nil }
+func (r *repo) UpdateTracking(ctx context.Context, id uint64, p models.TrackingPatch) (*models.Tracking, error) {
+	r.patch = p
+	for _, t := range r.created {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, pgtracking.ErrTrackingNotFound
+}
+func (r *repo) ScanTrackings(ctx context.Context, f pgtracking.TrackingFilter, afterID uint64, limit int) ([]*models.Tracking, error) {
+	r.filter = f
+	return r.created, nil
+}
+func (r *repo) ListETAHistory(ctx context.Context, trackingID uint64, limit int) ([]*models.ETAChange, error) {
+	return r.eta, nil
+}
+func (r *repo) ListEventRevisions(ctx context.Context, trackingID uint64, limit int) ([]*models.EventRevision, error) {
+	return r.revisions, nil
+}
+
+func (r *repo) ListTrackingChecks(ctx context.Context, trackingID uint64, failedOnly bool, before time.Time, limit int) ([]*models.TrackingCheck, error) {
+	r.failedOnly = failedOnly
+	return r.checks, nil
+}
 
 func TestTrackingsAPI_Flow(t *testing.T) {
 	now := time.Now().UTC()
@@ -57,7 +138,7 @@ func TestTrackingsAPI_Flow(t *testing.T) {
 	api := New(svc)
 
 	created, err := api.CreateTrackings(context.Background(), &trackings_api.CreateTrackingsRequest{
-		Items: []*pb_models.TrackingCreateInput{{CarrierCode: "CDEK", TrackNumber: "A1"}},
+		Items: []*pb_models.TrackingCreateInput{{CarrierCode: "CDEK", TrackNumber: "1234567890"}},
 	})
 	require.NoError(t, err)
 	require.Len(t, created.Trackings, 1)
@@ -77,6 +158,128 @@ func TestTrackingsAPI_Flow(t *testing.T) {
 
 	_, err = api.RefreshTracking(context.Background(), &trackings_api.RefreshTrackingRequest{TrackingId: 1})
 	require.NoError(t, err)
+
+	// без воркера check-now деградирует до refresh
+	checked, err := api.CheckTrackingNow(context.Background(), &trackings_api.CheckTrackingNowRequest{TrackingId: 1})
+	require.NoError(t, err)
+	require.True(t, checked.Queued)
+	require.Equal(t, uint64(1), checked.Tracking.Id)
+
+	r.created = nil
+	_, err = api.CheckTrackingNow(context.Background(), &trackings_api.CheckTrackingNowRequest{TrackingId: 2})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = api.CheckTrackingNow(context.Background(), &trackings_api.CheckTrackingNowRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTrackingsAPI_Attributes(t *testing.T) {
+	meta, ext := `{"shop":"x"}`, "ORD-1"
+	r := &repo{created: []*models.Tracking{{ID: 1, CarrierCode: "CDEK", TrackNumber: "1234567890", Metadata: &meta, Tags: []string{"vip"}, ExternalID: &ext}}}
+	api := New(trackings.New(r, nil, 0))
+
+	empty := ""
+	upd, err := api.UpdateTracking(context.Background(), &trackings_api.UpdateTrackingRequest{
+		TrackingId: 1,
+		ExternalId: &empty,
+		AddTags:    []string{"b2b"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, meta, upd.MetadataJson)
+	require.Equal(t, []string{"vip"}, upd.Tags)
+	require.Equal(t, "ORD-1", upd.ExternalId)
+	require.Nil(t, r.patch.Metadata) // не задано — не трогаем
+	require.Equal(t, "", *r.patch.ExternalID)
+	require.Equal(t, []string{"b2b"}, r.patch.AddTags)
+
+	_, err = api.UpdateTracking(context.Background(), &trackings_api.UpdateTrackingRequest{TrackingId: 2})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	bad := "[1]"
+	_, err = api.UpdateTracking(context.Background(), &trackings_api.UpdateTrackingRequest{TrackingId: 1, MetadataJson: &bad})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	list, err := api.ListTrackings(context.Background(), &trackings_api.ListTrackingsRequest{Tags: []string{"vip"}, ExternalId: "ORD-1"})
+	require.NoError(t, err)
+	require.Len(t, list.Trackings, 1)
+	require.Empty(t, list.NextPageToken)
+	require.Equal(t, []string{"vip"}, r.filter.Tags)
+	require.Equal(t, "ORD-1", r.filter.ExternalID)
+
+	_, err = api.ListTrackings(context.Background(), &trackings_api.ListTrackingsRequest{MetadataJson: "x"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTrackingsAPI_Shipment(t *testing.T) {
+	eta := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	prev := eta.Add(-24 * time.Hour)
+	r := &repo{
+		created: []*models.Tracking{{ID: 1, Shipment: &models.ShipmentDetails{EstimatedDelivery: &eta, WeightGrams: 350, RecipientCity: "Казань"}}},
+		eta: []*models.ETAChange{
+			{TrackingID: 1, EstimatedDelivery: prev, ObservedAt: prev.Add(-48 * time.Hour)},
+			{TrackingID: 1, EstimatedDelivery: eta, Previous: &prev, ObservedAt: prev},
+		},
+	}
+	api := New(trackings.New(r, nil, 0))
+
+	byIDs, err := api.GetTrackingsByIds(context.Background(), &trackings_api.GetTrackingsByIdsRequest{Ids: []uint64{1}})
+	require.NoError(t, err)
+	sd := byIDs.Trackings[0].Shipment
+	require.Equal(t, eta, sd.EstimatedDelivery.AsTime())
+	require.EqualValues(t, 350, sd.WeightGrams)
+	require.Equal(t, "Казань", sd.RecipientCity)
+
+	hist, err := api.ListEtaHistory(context.Background(), &trackings_api.ListEtaHistoryRequest{TrackingId: 1})
+	require.NoError(t, err)
+	require.Len(t, hist.Changes, 2)
+	require.Nil(t, hist.Changes[0].PreviousEstimatedDelivery)
+	require.Zero(t, hist.Changes[0].SlipSeconds)
+	require.EqualValues(t, 24*3600, hist.Changes[1].SlipSeconds)
+
+	_, err = api.ListEtaHistory(context.Background(), &trackings_api.ListEtaHistoryRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTrackingsAPI_ListTrackingChecks(t *testing.T) {
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	boom := "carrier emulator http 502"
+	r := &repo{checks: []*models.TrackingCheck{
+		{TrackingID: 1, CheckedAt: at, Kind: "check_failed", Error: &boom, ErrorClass: "HTTP", Worker: "w1", Backend: "emulator_v1", LatencyMS: 120, HTTPStatus: 502},
+	}}
+	api := New(trackings.New(r, nil, 0))
+
+	resp, err := api.ListTrackingChecks(context.Background(), &trackings_api.ListTrackingChecksRequest{TrackingId: 1, FailedOnly: true})
+	require.NoError(t, err)
+	require.True(t, r.failedOnly)
+	require.Len(t, resp.Checks, 1)
+	c := resp.Checks[0]
+	require.Equal(t, at, c.CheckedAt.AsTime())
+	require.Equal(t, boom, c.Error)
+	require.Equal(t, "HTTP", c.ErrorClass)
+	require.EqualValues(t, 502, c.HttpStatus)
+	require.EqualValues(t, 120, c.LatencyMs)
+	require.Empty(t, resp.NextPageToken)
+
+	_, err = api.ListTrackingChecks(context.Background(), &trackings_api.ListTrackingChecksRequest{TrackingId: 1, PageToken: "bad"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTrackingsAPI_EventPlace(t *testing.T) {
+	lat, lon := 55.79, 49.1
+	r := &repo{events: []*models.TrackingEvent{
+		{ID: 1, TrackingID: 1, Place: &models.Location{Country: "RU", Region: "Республика Татарстан", City: "Казань", PostalCode: "420300", Lat: &lat, Lon: &lon}},
+		{ID: 2, TrackingID: 1},
+	}}
+	api := New(trackings.New(r, nil, 0))
+
+	resp, err := api.ListTrackingEvents(context.Background(), &trackings_api.ListTrackingEventsRequest{TrackingId: 1, Country: "ru", Region: " Республика Татарстан "})
+	require.NoError(t, err)
+	require.Equal(t, pgtracking.EventFilter{Country: "ru", Region: "Республика Татарстан"}, r.eventFilter)
+	require.Len(t, resp.Events, 2)
+	pl := resp.Events[0].Place
+	require.Equal(t, "Казань", pl.City)
+	require.Equal(t, "420300", pl.PostalCode)
+	require.Equal(t, 55.79, pl.GetLat())
+	require.Equal(t, 49.1, pl.GetLon())
+	require.Nil(t, resp.Events[1].Place)
 }
 
 func TestDerefString(t *testing.T) {
@@ -86,3 +289,179 @@ func TestDerefString(t *testing.T) {
 }
 
 
+
+// createStream — двунаправленный поток в памяти.
+type createStream struct {
+	grpc.ServerStream
+
+	items []*pb_models.TrackingCreateInput
+	sent  []*trackings_api.StreamCreateTrackingsResponse
+}
+
+func (s *createStream) Context() context.Context { return context.Background() }
+
+func (s *createStream) Recv() (*pb_models.TrackingCreateInput, error) {
+	if len(s.items) == 0 {
+		return nil, io.EOF
+	}
+	it := s.items[0]
+	s.items = s.items[1:]
+	return it, nil
+}
+
+func (s *createStream) Send(resp *trackings_api.StreamCreateTrackingsResponse) error {
+	s.sent = append(s.sent, resp)
+	return nil
+}
+
+func TestStreamCreateTrackings_PerItemAcrossChunks(t *testing.T) {
+	r := &repo{}
+	api := New(trackings.New(r, nil, 0))
+
+	st := &createStream{}
+	for i := 0; i < trackings.BulkChunkSize+1; i++ {
+		st.items = append(st.items, &pb_models.TrackingCreateInput{CarrierCode: "CDEK", TrackNumber: fmt.Sprintf("10000%05d", i)})
+	}
+	st.items[1] = &pb_models.TrackingCreateInput{CarrierCode: "CDEK"}
+	st.items[2] = &pb_models.TrackingCreateInput{CarrierCode: "CDEK", TrackNumber: "9000000001"}
+
+	require.NoError(t, api.StreamCreateTrackings(st))
+	require.Equal(t, 2, r.bulkCalls)
+
+	// ответ на каждую пачку, счётчики — нарастающим итогом
+	require.Len(t, st.sent, 2)
+	first, second := st.sent[0], st.sent[1]
+	require.Len(t, first.Results, trackings.BulkChunkSize)
+	require.EqualValues(t, trackings.BulkChunkSize-2, first.Created)
+	require.EqualValues(t, 1, first.Existed)
+	require.EqualValues(t, 1, first.Invalid)
+
+	require.Equal(t, trackings_api.CreateTrackingResult_CREATED, first.Results[0].Status)
+	require.NotZero(t, first.Results[0].TrackingId)
+	require.Equal(t, trackings_api.CreateTrackingResult_INVALID, first.Results[1].Status)
+	require.Equal(t, "trackNumber is required", first.Results[1].Reason)
+	require.Zero(t, first.Results[1].TrackingId)
+	require.Equal(t, trackings_api.CreateTrackingResult_EXISTED, first.Results[2].Status)
+
+	require.Len(t, second.Results, 1)
+	require.EqualValues(t, trackings.BulkChunkSize, second.Results[0].Index)
+	require.Equal(t, trackings_api.CreateTrackingResult_CREATED, second.Results[0].Status)
+	require.EqualValues(t, trackings.BulkChunkSize-1, second.Created)
+	require.EqualValues(t, 1, second.Existed)
+	require.EqualValues(t, 1, second.Invalid)
+}
+
+func TestStreamCreateTrackings_EmptyStream(t *testing.T) {
+	r := &repo{}
+	api := New(trackings.New(r, nil, 0))
+
+	st := &createStream{}
+	require.NoError(t, api.StreamCreateTrackings(st))
+	require.Empty(t, st.sent)
+	require.Zero(t, r.bulkCalls)
+}
+
+func TestTrackingsAPI_Timeline(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	loc := "Москва"
+	r := &repo{
+		created: []*models.Tracking{{ID: 1}},
+		events: []*models.TrackingEvent{
+			{ID: 2, TrackingID: 1, Status: models.TrackingStatusDelivered, StatusRaw: "Вручено", EventTime: t0.Add(26 * time.Hour)},
+			{ID: 1, TrackingID: 1, Status: models.TrackingStatusInTransit, StatusRaw: "Принято", EventTime: t0, Location: &loc},
+		},
+	}
+	api := New(trackings.New(r, nil, 0))
+
+	resp, err := api.GetTrackingTimeline(context.Background(), &trackings_api.GetTrackingTimelineRequest{TrackingId: 1})
+	require.NoError(t, err)
+	tl := resp.Timeline
+	require.Equal(t, models.MilestoneDelivered, tl.CurrentStage)
+	require.EqualValues(t, 26*3600, tl.ElapsedSeconds)
+	require.Len(t, tl.Milestones, 5)
+	require.Equal(t, t0, tl.Milestones[0].ReachedAt.AsTime())
+	require.Nil(t, tl.Milestones[1].ReachedAt)
+	require.True(t, tl.Milestones[1].Reached)
+	require.EqualValues(t, 26*3600, tl.Milestones[4].SincePreviousSeconds)
+	require.Len(t, tl.Entries, 2)
+	require.Equal(t, "Москва", tl.Entries[0].Location)
+	require.Equal(t, []uint64{2}, tl.Entries[1].EventIds)
+
+	_, err = api.GetTrackingTimeline(context.Background(), &trackings_api.GetTrackingTimelineRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	r.created = nil
+	_, err = api.GetTrackingTimeline(context.Background(), &trackings_api.GetTrackingTimelineRequest{TrackingId: 1})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestTrackingsAPI_ListTrackingEvents_Page(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	r := &repo{events: []*models.TrackingEvent{{ID: 1, TrackingID: 1, EventTime: from}}}
+	api := New(trackings.New(r, nil, 0))
+
+	resp, err := api.ListTrackingEvents(context.Background(), &trackings_api.ListTrackingEventsRequest{
+		TrackingId: 1, Order: "asc", From: timestamppb.New(from), IncludeTotalCount: true,
+	})
+	require.NoError(t, err)
+	require.True(t, r.eventsAsc)
+	require.Equal(t, from, r.eventFilter.From)
+	require.True(t, r.eventFilter.To.IsZero())
+	require.Len(t, resp.Events, 1)
+	require.Empty(t, resp.NextPageToken)
+	require.EqualValues(t, 1, resp.GetTotalCount())
+
+	_, err = api.ListTrackingEvents(context.Background(), &trackings_api.ListTrackingEventsRequest{TrackingId: 1, Order: "newest"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = api.ListTrackingEvents(context.Background(), &trackings_api.ListTrackingEventsRequest{TrackingId: 1, PageToken: "bad"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTrackingsAPI_BatchListTrackingEvents(t *testing.T) {
+	r := &repo{events: []*models.TrackingEvent{{ID: 1, TrackingID: 5, Status: "IN_TRANSIT"}}}
+	api := New(trackings.New(r, nil, 0))
+
+	resp, err := api.BatchListTrackingEvents(context.Background(), &trackings_api.BatchListTrackingEventsRequest{
+		TrackingIds: []uint64{5, 6}, LimitPerTracking: 3, Since: timestamppb.New(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Trackings, 2)
+	require.Equal(t, uint64(5), resp.Trackings[0].TrackingId)
+	require.Len(t, resp.Trackings[0].Events, 1)
+	require.Equal(t, uint64(6), resp.Trackings[1].TrackingId)
+	require.Empty(t, resp.Trackings[1].Events)
+
+	_, err = api.BatchListTrackingEvents(context.Background(), &trackings_api.BatchListTrackingEventsRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTrackingsAPI_DeleteTracking(t *testing.T) {
+	api := New(trackings.New(&repo{}, nil, 0))
+
+	_, err := api.DeleteTracking(context.Background(), &trackings_api.DeleteTrackingRequest{TrackingId: 1})
+	require.NoError(t, err)
+	_, err = api.DeleteTracking(context.Background(), &trackings_api.DeleteTrackingRequest{TrackingId: 2})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestTrackingsAPI_ListChanges(t *testing.T) {
+	removedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	r := &repo{changes: []*models.Change{
+		{Seq: 5, Kind: models.ChangeTracking, Tracking: &models.Tracking{ID: 1, CarrierCode: "CDEK"}},
+		{Seq: 5, Kind: models.ChangeEvent, Event: &models.TrackingEvent{ID: 10, TrackingID: 1, RemovedAt: &removedAt}},
+		{Seq: 6, Kind: models.ChangeDeleted, Deleted: &models.TrackingTombstone{TrackingID: 2, CarrierCode: "CDEK", TrackNumber: "X"}},
+	}}
+	api := New(trackings.New(r, nil, 0))
+
+	resp, err := api.ListChanges(context.Background(), &trackings_api.ListChangesRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Changes, 3)
+	require.Equal(t, "CDEK", resp.Changes[0].GetTracking().GetCarrierCode())
+	require.Equal(t, removedAt, resp.Changes[1].GetEvent().GetRemovedAt().AsTime())
+	require.Equal(t, "X", resp.Changes[2].GetDeleted().GetTrackNumber())
+	require.Equal(t, int64(6), resp.Changes[2].GetSeq())
+	require.Equal(t, "6.3.2", resp.NextCursor)
+	require.False(t, resp.HasMore)
+
+	_, err = api.ListChanges(context.Background(), &trackings_api.ListChangesRequest{Cursor: "bad"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,137 @@
+package worker_api
+
+import (
+	"context"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/models"
+	pb_models "github.com/BearBump/TrackBox/internal/pb/models"
+	"github.com/BearBump/TrackBox/internal/pb/worker_api"
+	"github.com/BearBump/TrackBox/internal/services/poller"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Checker interface {
+	CheckNow(ctx context.Context, tr *models.Tracking) (messages.TrackingUpdated, error)
+}
+
+type WorkerAPI struct {
+	worker_api.UnimplementedWorkerServiceServer
+	checker Checker
+}
+
+func New(checker Checker) *WorkerAPI {
+	return &WorkerAPI{checker: checker}
+}
+
+func (a *WorkerAPI) CheckTracking(ctx context.Context, req *worker_api.CheckTrackingRequest) (*worker_api.CheckTrackingResponse, error) {
+	t := req.GetTracking()
+	if t.GetId() == 0 || t.GetCarrierCode() == "" || t.GetTrackNumber() == "" {
+		return nil, status.Error(codes.InvalidArgument, "tracking id, carrierCode and trackNumber are required")
+	}
+
+	msg, err := a.checker.CheckNow(ctx, &models.Tracking{
+		ID:             t.GetId(),
+		CarrierCode:    t.GetCarrierCode(),
+		TrackNumber:    t.GetTrackNumber(),
+		Status:         t.GetStatus(),
+		StatusRaw:      t.GetStatusRaw(),
+		CheckFailCount: t.GetCheckFailCount(),
+		CreatedAt:      fromPBTime(t.GetCreatedAt()),
+		Fingerprint:    req.GetFingerprint(),
+		Metadata:       optString(t.GetMetadataJson()),
+		Tags:           t.GetTags(),
+		ExternalID:     optString(t.GetExternalId()),
+	})
+	if errors.Is(err, poller.ErrRateLimited) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := &worker_api.CheckTrackingResponse{
+		CheckedAt:   timestamppb.New(msg.CheckedAt),
+		Status:      msg.Status,
+		StatusRaw:   msg.StatusRaw,
+		StatusAt:    toPBTime(msg.StatusAt),
+		NextCheckAt: timestamppb.New(msg.NextCheckAt),
+		Kind:        msg.Kind,
+		Fingerprint: msg.Fingerprint,
+	}
+	if msg.Error != nil {
+		out.Error = *msg.Error
+	}
+	if sd := msg.Shipment; sd != nil {
+		out.Shipment = &pb_models.ShipmentDetails{
+			EstimatedDelivery: toPBTime(sd.EstimatedDelivery),
+			WeightGrams:       sd.WeightGrams,
+			Origin:            sd.Origin,
+			Destination:       sd.Destination,
+			RecipientCity:     sd.RecipientCity,
+			ServiceType:       sd.ServiceType,
+		}
+	}
+	for _, e := range msg.Events {
+		out.Events = append(out.Events, &pb_models.TrackingEvent{
+			TrackingId:   msg.TrackingID,
+			Status:       e.Status,
+			StatusRaw:    e.StatusRaw,
+			EventTime:    timestamppb.New(e.EventTime),
+			Location:     derefString(e.Location),
+			Message:      derefString(e.Message),
+			PayloadJson:  string(e.Payload),
+			Place:        toPBPlace(e.Place),
+			EventTimeRaw: derefString(e.EventTimeRaw),
+			TimeInferred: e.TimeInferred,
+		})
+	}
+	return out, nil
+}
+
+func toPBPlace(p *messages.Place) *pb_models.EventLocation {
+	if p == nil {
+		return nil
+	}
+	return &pb_models.EventLocation{
+		Country:    p.Country,
+		Region:     p.Region,
+		City:       p.City,
+		PostalCode: p.PostalCode,
+		Lat:        p.Lat,
+		Lon:        p.Lon,
+	}
+}
+
+// fromPBTime — нулевое время для nil (AsTime у nil дало бы 1970-01-01).
+func fromPBTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func toPBTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func optString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
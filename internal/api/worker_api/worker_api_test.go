@@ -0,0 +1,101 @@
+package worker_api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/models"
+	pb_models "github.com/BearBump/TrackBox/internal/pb/models"
+	"github.com/BearBump/TrackBox/internal/pb/worker_api"
+	"github.com/BearBump/TrackBox/internal/services/poller"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type checker struct {
+	got *models.Tracking
+	msg messages.TrackingUpdated
+	err error
+}
+
+func (c *checker) CheckNow(ctx context.Context, tr *models.Tracking) (messages.TrackingUpdated, error) {
+	c.got = tr
+	return c.msg, c.err
+}
+
+func TestWorkerAPI_CheckTracking(t *testing.T) {
+	now := time.Now().UTC()
+	loc, raw := "Moscow", "01.01.2025 10:00"
+	c := &checker{msg: messages.TrackingUpdated{
+		TrackingID:  3,
+		CheckedAt:   now,
+		Status:      "IN_TRANSIT",
+		StatusRaw:   "RAW",
+		StatusAt:    &now,
+		NextCheckAt: now.Add(time.Minute),
+		Events: []messages.TrackingEvent{
+			{Status: "IN_TRANSIT", StatusRaw: "RAW", EventTime: now, Location: &loc, Payload: []byte(`{"x":1}`),
+				Place: &messages.Place{Country: "RU", Region: "Москва", PostalCode: "101000"}, EventTimeRaw: &raw, TimeInferred: true},
+		},
+		Shipment:    &messages.Shipment{EstimatedDelivery: &now, Destination: "Kazan"},
+		Kind:        messages.KindStatusChanged,
+		Fingerprint: "fp2",
+	}}
+	api := New(c)
+
+	resp, err := api.CheckTracking(context.Background(), &worker_api.CheckTrackingRequest{
+		Tracking: &pb_models.Tracking{Id: 3, CarrierCode: "CDEK", TrackNumber: "A1", CheckFailCount: 2, CreatedAt: timestamppb.New(now),
+			MetadataJson: `{"a":1}`, Tags: []string{"vip"}, ExternalId: "ORD-1"},
+		Fingerprint: "fp1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "IN_TRANSIT", resp.Status)
+	require.Equal(t, messages.KindStatusChanged, resp.Kind)
+	require.Equal(t, "fp2", resp.Fingerprint)
+	require.Equal(t, "fp1", c.got.Fingerprint)
+	require.Equal(t, now.Unix(), resp.StatusAt.AsTime().Unix())
+	require.Empty(t, resp.Error)
+	require.Len(t, resp.Events, 1)
+	require.Equal(t, "Moscow", resp.Events[0].Location)
+	require.Equal(t, `{"x":1}`, resp.Events[0].PayloadJson)
+	require.Equal(t, "Москва", resp.Events[0].Place.Region)
+	require.Equal(t, "101000", resp.Events[0].Place.PostalCode)
+	require.Nil(t, resp.Events[0].Place.Lat)
+	require.Equal(t, raw, resp.Events[0].EventTimeRaw)
+	require.True(t, resp.Events[0].TimeInferred)
+	require.Equal(t, "Kazan", resp.Shipment.Destination)
+	require.Equal(t, now.Unix(), resp.Shipment.EstimatedDelivery.AsTime().Unix())
+	require.Equal(t, int32(2), c.got.CheckFailCount)
+	require.True(t, now.Equal(c.got.CreatedAt))
+	require.Equal(t, `{"a":1}`, *c.got.Metadata)
+	require.Equal(t, []string{"vip"}, c.got.Tags)
+	require.Equal(t, "ORD-1", *c.got.ExternalID)
+
+	_, err = api.CheckTracking(context.Background(), &worker_api.CheckTrackingRequest{
+		Tracking: &pb_models.Tracking{Id: 3, CarrierCode: "CDEK", TrackNumber: "A1"},
+	})
+	require.NoError(t, err)
+	require.True(t, c.got.CreatedAt.IsZero())
+	require.Nil(t, c.got.Metadata)
+	require.Nil(t, c.got.ExternalID)
+}
+
+func TestWorkerAPI_CheckTracking_Errors(t *testing.T) {
+	api := New(&checker{err: poller.ErrRateLimited})
+
+	_, err := api.CheckTracking(context.Background(), &worker_api.CheckTrackingRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	req := &worker_api.CheckTrackingRequest{Tracking: &pb_models.Tracking{Id: 1, CarrierCode: "C", TrackNumber: "N"}}
+	_, err = api.CheckTracking(context.Background(), req)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	want := errors.New("kafka down")
+	_, err = New(&checker{err: want}).CheckTracking(context.Background(), req)
+	require.ErrorIs(t, err, want)
+}
@@ -0,0 +1,103 @@
+// Package app — общий bootstrap для track-api, track-worker и CLI trackbox:
+// путь к конфигу, подключения к Postgres/Kafka/Redis, архив ответов перевозчиков.
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/storage/archive"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+)
+
+// ConfigPath выбирает путь к конфигу: явный (флаг) важнее переменной окружения configPath.
+func ConfigPath(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if p := os.Getenv("configPath"); p != "" {
+		return p, nil
+	}
+	return "", fmt.Errorf("config path is required (-config flag or configPath env var)")
+}
+
+// LoadConfig = ConfigPath + config.LoadConfig.
+func LoadConfig(flagValue string) (*config.Config, string, error) {
+	path, err := ConfigPath(flagValue)
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return nil, path, err
+	}
+	return cfg, path, nil
+}
+
+func PostgresConnString(cfg *config.Config) string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
+		cfg.Database.Username, cfg.Database.Password, cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName, cfg.Database.SSLMode)
+}
+
+func KafkaBrokers(cfg *config.Config) []string {
+	return []string{fmt.Sprintf("%s:%d", cfg.Kafka.Host, cfg.Kafka.Port)}
+}
+
+func RedisAddr(cfg *config.Config) string {
+	return fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port)
+}
+
+// OpenStorage подключается к Postgres (и применяет схему), повторяя попытки до wait —
+// после docker compose up база поднимается не сразу.
+func OpenStorage(ctx context.Context, cfg *config.Config, wait time.Duration) (*pgtracking.Storage, error) {
+	connString := PostgresConnString(cfg)
+	deadline := time.Now().Add(wait)
+	var lastErr error
+	for {
+		st, err := pgtracking.New(connString)
+		if err == nil {
+			return st, nil
+		}
+		lastErr = err
+		if !time.Now().Before(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(1 * time.Second):
+		}
+	}
+	return nil, fmt.Errorf("postgres is not ready after %s: %w", wait, lastErr)
+}
+
+// OpenArchive — архив сырых ответов по trackbox.response_archive; nil, если архив не настроен.
+func OpenArchive(cfg *config.Config) (*archive.Archive, error) {
+	ac := cfg.TrackBox.ResponseArchive
+	if ac == nil {
+		return nil, nil
+	}
+	switch ac.Backend {
+	case "s3":
+		store, err := archive.NewS3(archive.S3Config{
+			Endpoint:  ac.S3.Endpoint,
+			Bucket:    ac.S3.Bucket,
+			Region:    ac.S3.Region,
+			AccessKey: ac.S3.AccessKey,
+			SecretKey: ac.S3.SecretKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("response archive: %w", err)
+		}
+		return archive.New(store), nil
+	default:
+		store, err := archive.NewLocalFS(ac.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("response archive: %w", err)
+		}
+		return archive.New(store), nil
+	}
+}
@@ -0,0 +1,152 @@
+// Package trackapi — track-api: gRPC + HTTP gateway + Kafka consumer обновлений.
+package trackapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/app"
+	"github.com/BearBump/TrackBox/internal/broker/kafka"
+	"github.com/BearBump/TrackBox/internal/cache"
+	"github.com/BearBump/TrackBox/internal/cache/memcache"
+	"github.com/BearBump/TrackBox/internal/cache/rediscache"
+	"github.com/BearBump/TrackBox/internal/cache/tiered"
+	"github.com/BearBump/TrackBox/internal/integrations/worker"
+	"github.com/BearBump/TrackBox/internal/services/analytics"
+	"github.com/BearBump/TrackBox/internal/services/bulk"
+	"github.com/BearBump/TrackBox/internal/services/carriers"
+	"github.com/BearBump/TrackBox/internal/services/sla"
+	"github.com/BearBump/TrackBox/internal/services/trackings"
+)
+
+// Options — то, что не лежит в конфиге.
+type Options struct {
+	SwaggerPath string
+}
+
+type App struct {
+	opts          trackAPIOpts
+	svc           *trackings.Service
+	bulk          *bulk.Service
+	carriers      *carriers.Service
+	alerts        *sla.Service
+	analytics     *analytics.Service
+	checkLog      checkLogMaintainer
+	events        eventsMaintainer
+	l1            *tiered.Cache
+	invalidations *rediscache.Invalidations
+	consumer      *kafka.Consumer
+	worker        *worker.Client
+	closeDB       func()
+}
+
+// New поднимает зависимости track-api. Значения по умолчанию уже подставлены config.LoadConfig.
+func New(ctx context.Context, cfg *config.Config, o Options) (*App, error) {
+	if o.SwaggerPath == "" {
+		return nil, fmt.Errorf("swagger path is required (-swagger flag or swaggerPath env var)")
+	}
+
+	st, err := app.OpenStorage(ctx, cfg, 60*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	// Справочник перевозчиков из БД; при ошибке чтения работаем со встроенным, RunRefresh догонит.
+	carrierSvc := carriers.New(st)
+	if err := carrierSvc.Refresh(ctx); err != nil {
+		slog.Warn("carriers load failed, using builtin registry", "error", err.Error())
+	}
+
+	// Кэш текущего состояния: Redis, перед ним (если задан current_status_l1) — память реплики.
+	var (
+		current       cache.BytesCache = rediscache.New(app.RedisAddr(cfg))
+		l1            *tiered.Cache
+		invalidations *rediscache.Invalidations
+	)
+	if l := cfg.TrackBox.CurrentStatusL1; l != nil {
+		l1 = tiered.New(memcache.New(l.MaxEntries, time.Duration(l.TTLSeconds)*time.Second), current)
+		invalidations = rediscache.NewInvalidations(app.RedisAddr(cfg), l.InvalidationChannel)
+		current = l1
+	}
+	svc := trackings.New(st, current, time.Duration(cfg.TrackBox.CurrentStatusTTLSeconds)*time.Second).
+		WithNegativeTTL(time.Duration(cfg.TrackBox.CurrentStatusNegativeTTLSeconds) * time.Second).
+		WithCarriers(carrierSvc.Registry)
+	if invalidations != nil {
+		svc.WithInvalidator(invalidations)
+	}
+
+	// CheckTrackingNow: без адреса воркера RPC просто ставит трек в очередь (как refresh).
+	var wc *worker.Client
+	if cfg.TrackBox.WorkerGRPCTarget != "" {
+		wc, err = worker.New(cfg.TrackBox.WorkerGRPCTarget)
+		if err != nil {
+			st.Close()
+			return nil, fmt.Errorf("worker client: %w", err)
+		}
+		checkTimeout := time.Duration(cfg.TrackBox.CheckNowTimeoutSeconds) * time.Second
+		svc.WithChecker(wc, checkTimeout)
+	}
+
+	topic := cfg.Kafka.TrackingUpdatedTopicName
+	consumerGroup := cfg.TrackBox.KafkaConsumerGroup
+	consumer := kafka.NewConsumer(app.KafkaBrokers(cfg), topic, consumerGroup)
+
+	return &App{
+		opts: trackAPIOpts{
+			grpcAddr:      cfg.TrackBox.GRPCAddr,
+			httpAddr:      cfg.TrackBox.HTTPAddr,
+			grpcDialAddr:  cfg.TrackBox.GRPCAddr,
+			swaggerPath:   o.SwaggerPath,
+			topic:         topic,
+			consumerGroup: consumerGroup,
+
+			carriersRefresh:   time.Duration(cfg.TrackBox.CarriersRefreshSeconds) * time.Second,
+			checkLogRetention: time.Duration(cfg.TrackBox.CheckLogRetentionDays) * 24 * time.Hour,
+			eventRetention:    eventRetention(cfg.TrackBox.EventRetention),
+		},
+		svc:           svc,
+		bulk:          bulk.New(st, bulk.DefaultConfig()).WithCarriers(carrierSvc.Registry).WithTrackings(svc),
+		carriers:      carrierSvc,
+		alerts:        sla.New(st),
+		analytics:     analytics.New(st),
+		checkLog:      st,
+		events:        st,
+		l1:            l1,
+		invalidations: invalidations,
+		consumer:      consumer,
+		worker:        wc,
+		closeDB:       st.Close,
+	}, nil
+}
+
+func (a *App) Close() {
+	if a.consumer != nil {
+		_ = a.consumer.Close()
+	}
+	if a.worker != nil {
+		_ = a.worker.Close()
+	}
+	if a.invalidations != nil {
+		_ = a.invalidations.Close()
+	}
+	if a.closeDB != nil {
+		a.closeDB()
+	}
+}
+
+// Run блокируется до отмены ctx или ошибки одного из серверов.
+func (a *App) Run(ctx context.Context) error {
+	if a.checkLog != nil {
+		go runCheckLogMaintenance(ctx, a.checkLog, a.opts.checkLogRetention, time.Hour)
+	}
+	if a.events != nil {
+		go runEventsMaintenance(ctx, a.events, a.opts.eventRetention, time.Hour)
+	}
+	if a.invalidations != nil {
+		go runCacheInvalidations(ctx, a.invalidations, a.l1, a.svc.CacheChanged, 5*time.Second)
+	}
+	return runTrackAPI(ctx, a.opts, a.svc, a.bulk, a.carriers, a.alerts, a.analytics, a.consumer)
+}
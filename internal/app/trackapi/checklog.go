@@ -0,0 +1,33 @@
+package trackapi
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// checkLogMaintainer — обслуживание журнала проверок (pgtracking.Storage).
+type checkLogMaintainer interface {
+	MaintainCheckLog(ctx context.Context, now time.Time, retention time.Duration) (int, error)
+}
+
+// runCheckLogMaintenance сразу и затем раз в every готовит секции журнала проверок вперёд
+// и удаляет дни старше retention. Ошибки только логируются: следующий проход повторит.
+func runCheckLogMaintenance(ctx context.Context, m checkLogMaintainer, retention, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		dropped, err := m.MaintainCheckLog(ctx, time.Now().UTC(), retention)
+		switch {
+		case err != nil:
+			slog.Error("check log maintenance", "error", err.Error())
+		case dropped > 0:
+			slog.Info("check log: dropped old partitions", "partitions", dropped)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package trackapi
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCheckLog struct {
+	calls     atomic.Int32
+	retention atomic.Int64
+}
+
+func (f *fakeCheckLog) MaintainCheckLog(_ context.Context, _ time.Time, retention time.Duration) (int, error) {
+	f.retention.Store(int64(retention))
+	if f.calls.Add(1) == 1 {
+		return 0, errors.New("db is down")
+	}
+	return 1, nil
+}
+
+func TestRunCheckLogMaintenance(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &fakeCheckLog{}
+	done := make(chan struct{})
+	go func() {
+		runCheckLogMaintenance(ctx, f, 48*time.Hour, 10*time.Millisecond)
+		close(done)
+	}()
+
+	// ошибка первого прохода не останавливает обслуживание
+	require.Eventually(t, func() bool { return f.calls.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, int64(48*time.Hour), f.retention.Load())
+	cancel()
+	<-done
+}
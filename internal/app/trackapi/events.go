@@ -0,0 +1,54 @@
+package trackapi
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+)
+
+// eventsMaintainer — обслуживание секций событий треков (pgtracking.Storage).
+type eventsMaintainer interface {
+	MaintainEvents(ctx context.Context, now time.Time, r pgtracking.EventRetention) (pgtracking.EventMaintenance, error)
+}
+
+// eventRetention переводит trackbox.event_retention в сроки хранения; nil — только подготовка секций.
+func eventRetention(c *config.EventRetentionConfig) pgtracking.EventRetention {
+	var r pgtracking.EventRetention
+	if c == nil {
+		return r
+	}
+	r.MaxAge = time.Duration(c.MaxAgeDays) * 24 * time.Hour
+	r.DetachOnly = c.DetachOnly
+	if len(c.Statuses) > 0 {
+		r.ByStatus = make(map[string]time.Duration, len(c.Statuses))
+		for status, days := range c.Statuses {
+			r.ByStatus[status] = time.Duration(days) * 24 * time.Hour
+		}
+	}
+	return r
+}
+
+// runEventsMaintenance сразу и затем раз в every готовит месячные секции событий вперёд и применяет сроки
+// хранения. Ошибки только логируются: следующий проход повторит.
+func runEventsMaintenance(ctx context.Context, m eventsMaintainer, r pgtracking.EventRetention, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		res, err := m.MaintainEvents(ctx, time.Now().UTC(), r)
+		switch {
+		case err != nil:
+			slog.Error("events maintenance", "error", err.Error())
+		case res.Partitions > 0 || res.Events > 0:
+			slog.Info("events: applied retention",
+				"partitions", res.Partitions, "detach_only", r.DetachOnly, "trackings", res.Trackings, "events", res.Events)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package trackapi
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEvents struct {
+	calls     atomic.Int32
+	retention atomic.Pointer[pgtracking.EventRetention]
+}
+
+func (f *fakeEvents) MaintainEvents(_ context.Context, _ time.Time, r pgtracking.EventRetention) (pgtracking.EventMaintenance, error) {
+	f.retention.Store(&r)
+	if f.calls.Add(1) == 1 {
+		return pgtracking.EventMaintenance{}, errors.New("db is down")
+	}
+	return pgtracking.EventMaintenance{Partitions: 1}, nil
+}
+
+func TestRunEventsMaintenance(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &fakeEvents{}
+	r := eventRetention(&config.EventRetentionConfig{MaxAgeDays: 730, Statuses: map[string]int{"DELIVERED": 365}})
+	done := make(chan struct{})
+	go func() {
+		runEventsMaintenance(ctx, f, r, 10*time.Millisecond)
+		close(done)
+	}()
+
+	// ошибка первого прохода не останавливает обслуживание
+	require.Eventually(t, func() bool { return f.calls.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	got := f.retention.Load()
+	require.Equal(t, 730*24*time.Hour, got.MaxAge)
+	require.Equal(t, map[string]time.Duration{"DELIVERED": 365 * 24 * time.Hour}, got.ByStatus)
+	cancel()
+	<-done
+
+	require.Equal(t, pgtracking.EventRetention{}, eventRetention(nil))
+}
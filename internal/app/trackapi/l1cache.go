@@ -0,0 +1,48 @@
+package trackapi
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// invalidationSubscriber — подписка на изменения кэша с других реплик (rediscache.Invalidations).
+type invalidationSubscriber interface {
+	Subscribe(ctx context.Context, drop func(keys []string), reset func()) error
+}
+
+// l1Invalidator — кэш в памяти реплики (tiered.Cache).
+type l1Invalidator interface {
+	Invalidate(keys ...string)
+	InvalidateAll()
+}
+
+// runCacheInvalidations держит подписку на изменения кэша до отмены ctx и сбрасывает по ним кэш в памяти,
+// а также сообщает о них changed (trackings.Service.CacheChanged; без ключей — изменилось неизвестно что).
+// Пока подписки нет, сообщения теряются, поэтому после обрыва кэш в памяти очищается целиком, а подписка
+// повторяется через retry.
+func runCacheInvalidations(ctx context.Context, sub invalidationSubscriber, l1 l1Invalidator, changed func(keys ...string), retry time.Duration) {
+	drop := func(keys []string) {
+		l1.Invalidate(keys...)
+		changed(keys...)
+	}
+	reset := func() {
+		l1.InvalidateAll()
+		changed()
+	}
+	for {
+		err := sub.Subscribe(ctx, drop, reset)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			slog.Error("cache invalidations", "error", err.Error())
+		}
+		reset()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+	}
+}
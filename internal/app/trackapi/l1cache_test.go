@@ -0,0 +1,75 @@
+package trackapi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSubscriber struct {
+	calls atomic.Int32
+}
+
+func (f *fakeSubscriber) Subscribe(ctx context.Context, drop func(keys []string), reset func()) error {
+	reset()
+	drop([]string{"tracking:1:current"})
+	if f.calls.Add(1) == 1 {
+		return errors.New("connection reset")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+type fakeL1 struct {
+	mu     sync.Mutex
+	keys   []string
+	purges int
+}
+
+func (f *fakeL1) Invalidate(keys ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys = append(f.keys, keys...)
+}
+
+func (f *fakeL1) InvalidateAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.purges++
+}
+
+func TestRunCacheInvalidations(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, l1 := &fakeSubscriber{}, &fakeL1{}
+	var changedMu sync.Mutex
+	var changed [][]string
+	onChange := func(keys ...string) {
+		changedMu.Lock()
+		defer changedMu.Unlock()
+		changed = append(changed, keys)
+	}
+	done := make(chan struct{})
+	go func() {
+		runCacheInvalidations(ctx, sub, l1, onChange, 10*time.Millisecond)
+		close(done)
+	}()
+
+	// обрыв подписки не останавливает её: переподписываемся
+	require.Eventually(t, func() bool { return sub.calls.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+
+	l1.mu.Lock()
+	defer l1.mu.Unlock()
+	require.Equal(t, []string{"tracking:1:current", "tracking:1:current"}, l1.keys)
+	require.Equal(t, 3, l1.purges) // две подписки и обрыв между ними
+	// дозагрузкам кэша сообщается о том же: сброс — без ключей
+	changedMu.Lock()
+	defer changedMu.Unlock()
+	require.Equal(t, [][]string{nil, {"tracking:1:current"}, nil, nil, {"tracking:1:current"}}, changed)
+}
@@ -1,4 +1,4 @@
-package main
+package trackapi
 
 import (
 	"context"
@@ -11,10 +11,22 @@ import (
 	"strings"
 	"time"
 
+	alertsapi "github.com/BearBump/TrackBox/internal/api/alerts_api"
+	analyticsapi "github.com/BearBump/TrackBox/internal/api/analytics_api"
+	bulkapi "github.com/BearBump/TrackBox/internal/api/bulk_api"
+	carriersapi "github.com/BearBump/TrackBox/internal/api/carriers_api"
 	trackingsapi "github.com/BearBump/TrackBox/internal/api/trackings_api"
 	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/pb/alerts_api"
+	"github.com/BearBump/TrackBox/internal/pb/analytics_api"
+	"github.com/BearBump/TrackBox/internal/pb/carriers_api"
 	"github.com/BearBump/TrackBox/internal/pb/trackings_api"
+	"github.com/BearBump/TrackBox/internal/services/analytics"
+	"github.com/BearBump/TrackBox/internal/services/bulk"
+	"github.com/BearBump/TrackBox/internal/services/carriers"
+	"github.com/BearBump/TrackBox/internal/services/sla"
 	"github.com/BearBump/TrackBox/internal/services/trackings"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
 	"github.com/go-chi/chi/v5"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -31,6 +43,12 @@ type trackAPIOpts struct {
 	topic         string
 	consumerGroup string
 
+	carriersRefresh time.Duration
+	// Срок хранения журнала проверок (tracking_checks).
+	checkLogRetention time.Duration
+	// Сроки хранения событий треков (tracking_events).
+	eventRetention pgtracking.EventRetention
+
 	onListen func(grpcAddr, httpAddr string)
 }
 
@@ -38,7 +56,11 @@ type kafkaConsumer interface {
 	Consume(ctx context.Context, handler func(key, value []byte) error) error
 }
 
-func runTrackAPI(ctx context.Context, opts trackAPIOpts, svc *trackings.Service, consumer kafkaConsumer) error {
+// imports (может быть nil) — импорт/экспорт: HTTP-ручки и фоновая обработка задач импорта.
+// carrierSvc (может быть nil) — админский справочник перевозчиков и его периодическое перечитывание.
+// alertSvc (может быть nil) — список алертов SLA (сами алерты считает track-worker).
+// analyticsSvc (может быть nil) — агрегаты по перевозчикам (/analytics/*).
+func runTrackAPI(ctx context.Context, opts trackAPIOpts, svc *trackings.Service, imports *bulk.Service, carrierSvc *carriers.Service, alertSvc *sla.Service, analyticsSvc *analytics.Service, consumer kafkaConsumer) error {
 	if opts.swaggerPath == "" {
 		return fmt.Errorf("swaggerPath env var is required")
 	}
@@ -47,6 +69,19 @@ func runTrackAPI(ctx context.Context, opts trackAPIOpts, svc *trackings.Service,
 	}
 
 	api := trackingsapi.New(svc)
+	var admin *carriersapi.CarriersAPI
+	if carrierSvc != nil {
+		admin = carriersapi.New(carrierSvc)
+		go carrierSvc.RunRefresh(ctx, opts.carriersRefresh)
+	}
+	var alerts *alertsapi.AlertsAPI
+	if alertSvc != nil {
+		alerts = alertsapi.New(alertSvc)
+	}
+	var stats *analyticsapi.AnalyticsAPI
+	if analyticsSvc != nil {
+		stats = analyticsapi.New(analyticsSvc)
+	}
 
 	grpcLis, err := net.Listen("tcp", opts.grpcAddr)
 	if err != nil {
@@ -69,12 +104,18 @@ func runTrackAPI(ctx context.Context, opts trackAPIOpts, svc *trackings.Service,
 
 	grpcErr := make(chan error, 1)
 	go func() {
-		grpcErr <- runGRPCServer(ctx, grpcLis, api)
+		grpcErr <- runGRPCServer(ctx, grpcLis, api, admin, alerts, stats)
 	}()
 
+	var routes func(chi.Router)
+	if imports != nil {
+		routes = bulkapi.New(imports).Register
+		go imports.RunImports(ctx, 2*time.Second)
+	}
+
 	httpErr := make(chan error, 1)
 	go func() {
-		httpErr <- runGatewayServer(ctx, httpLis, dialAddr, opts.swaggerPath)
+		httpErr <- runGatewayServer(ctx, httpLis, dialAddr, opts.swaggerPath, routes)
 	}()
 
 	go func() {
@@ -102,9 +143,21 @@ func runTrackAPI(ctx context.Context, opts trackAPIOpts, svc *trackings.Service,
 	}
 }
 
-func runGRPCServer(ctx context.Context, lis net.Listener, api *trackingsapi.TrackingsAPI) error {
+// admin (может быть nil) — CarriersService; без него gateway отвечает на /admin/carriers кодом Unimplemented.
+// alerts (может быть nil) — AlertsService, аналогично для /alerts.
+// stats (может быть nil) — AnalyticsService, аналогично для /analytics/*.
+func runGRPCServer(ctx context.Context, lis net.Listener, api *trackingsapi.TrackingsAPI, admin *carriersapi.CarriersAPI, alerts *alertsapi.AlertsAPI, stats *analyticsapi.AnalyticsAPI) error {
 	s := grpc.NewServer()
 	trackings_api.RegisterTrackingsServiceServer(s, api)
+	if admin != nil {
+		carriers_api.RegisterCarriersServiceServer(s, admin)
+	}
+	if alerts != nil {
+		alerts_api.RegisterAlertsServiceServer(s, alerts)
+	}
+	if stats != nil {
+		analytics_api.RegisterAnalyticsServiceServer(s, stats)
+	}
 
 	go func() {
 		<-ctx.Done()
@@ -125,7 +178,8 @@ func runGRPCServer(ctx context.Context, lis net.Listener, api *trackingsapi.Trac
 	return s.Serve(lis)
 }
 
-func runGatewayServer(ctx context.Context, lis net.Listener, grpcAddr string, swaggerPath string) error {
+// routes (может быть nil) регистрирует обычные HTTP-ручки рядом с grpc-gateway.
+func runGatewayServer(ctx context.Context, lis net.Listener, grpcAddr string, swaggerPath string, routes func(chi.Router)) error {
 	r := chi.NewRouter()
 	r.Get("/swagger.json", func(w http.ResponseWriter, r *http.Request) {
 		// Swagger UI loves to cache swagger.json very aggressively in browsers,
@@ -144,11 +198,27 @@ func runGatewayServer(ctx context.Context, lis net.Listener, grpcAddr string, sw
 		httpSwagger.URL(swaggerURL),
 	))
 
+	if routes != nil {
+		routes(r)
+	}
+
 	mux := runtime.NewServeMux()
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
 	if err := trackings_api.RegisterTrackingsServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
 		return err
 	}
+	if err := carriers_api.RegisterCarriersServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return err
+	}
+	if err := alerts_api.RegisterAlertsServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return err
+	}
+	if err := analytics_api.RegisterAnalyticsServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return err
+	}
+	// Ответ потокового создания пишется, пока тело запроса ещё читается; без full duplex HTTP/1.1 сервер
+	// закрывает тело после первой записи ответа.
+	r.With(fullDuplex).Post("/trackings/stream", mux.ServeHTTP)
 	r.Mount("/", mux)
 
 	srv := &http.Server{Handler: r}
@@ -163,4 +233,9 @@ func runGatewayServer(ctx context.Context, lis net.Listener, grpcAddr string, sw
 	return srv.Serve(lis)
 }
 
-
+func fullDuplex(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = http.NewResponseController(w).EnableFullDuplex()
+		next.ServeHTTP(w, r)
+	})
+}
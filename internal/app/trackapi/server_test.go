@@ -0,0 +1,243 @@
+package trackapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	trackingsapi "github.com/BearBump/TrackBox/internal/api/trackings_api"
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/services/trackings"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct{}
+
+func (r *fakeRepo) CreateOrGetTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]*models.Tracking, error) {
+	return []*models.Tracking{}, nil
+}
+func (r *fakeRepo) BulkCreateTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]pgtracking.CreateResult, error) {
+	out := make([]pgtracking.CreateResult, 0, len(items))
+	for i, it := range items {
+		out = append(out, pgtracking.CreateResult{Tracking: &models.Tracking{ID: uint64(i + 1), CarrierCode: it.CarrierCode, TrackNumber: it.TrackNumber}, Created: true})
+	}
+	return out, nil
+}
+func (r *fakeRepo) GetTrackingsByIDs(ctx context.Context, ids []uint64) ([]*models.Tracking, error) {
+	return []*models.Tracking{}, nil
+}
+func (r *fakeRepo) ListTrackingEvents(ctx context.Context, trackingID uint64, f pgtracking.EventFilter, limit, offset int) ([]*models.TrackingEvent, error) {
+	return []*models.TrackingEvent{}, nil
+}
+func (r *fakeRepo) ListEventKeys(ctx context.Context, trackingID uint64) ([]string, error) {
+	return nil, nil
+}
+func (r *fakeRepo) ListTrackingEventsPage(ctx context.Context, trackingID uint64, f pgtracking.EventFilter, asc bool, after *pgtracking.EventCursor, limit int) ([]*models.TrackingEvent, error) {
+	return []*models.TrackingEvent{}, nil
+}
+func (r *fakeRepo) DeleteTracking(ctx context.Context, id uint64) error { return nil }
+func (r *fakeRepo) ListChanges(ctx context.Context, after pgtracking.ChangeCursor, limit int) ([]*models.Change, error) {
+	return nil, nil
+}
+func (r *fakeRepo) ListLatestEvents(ctx context.Context, trackingIDs []uint64, limit int, since time.Time) (map[uint64][]*models.TrackingEvent, error) {
+	return nil, nil
+}
+func (r *fakeRepo) CountTrackingEvents(ctx context.Context, trackingID uint64, f pgtracking.EventFilter) (int64, error) {
+	return 0, nil
+}
+func (r *fakeRepo) RefreshTracking(ctx context.Context, trackingID uint64) error { return nil }
+func (r *fakeRepo) ApplyTrackingUpdate(ctx context.Context, upd pgtracking.TrackingUpdate) error {
+	return nil
+}
+func (r *fakeRepo) UpdateTracking(ctx context.Context, id uint64, p models.TrackingPatch) (*models.Tracking, error) {
+	return nil, pgtracking.ErrTrackingNotFound
+}
+func (r *fakeRepo) ScanTrackings(ctx context.Context, f pgtracking.TrackingFilter, afterID uint64, limit int) ([]*models.Tracking, error) {
+	return []*models.Tracking{}, nil
+}
+func (r *fakeRepo) ListETAHistory(ctx context.Context, trackingID uint64, limit int) ([]*models.ETAChange, error) {
+	return []*models.ETAChange{}, nil
+}
+
+func (r *fakeRepo) ListEventRevisions(ctx context.Context, trackingID uint64, limit int) ([]*models.EventRevision, error) {
+	return []*models.EventRevision{}, nil
+}
+func (r *fakeRepo) ListTrackingChecks(ctx context.Context, trackingID uint64, failedOnly bool, before time.Time, limit int) ([]*models.TrackingCheck, error) {
+	return []*models.TrackingCheck{}, nil
+}
+
+func TestRunServers_SwaggerServed(t *testing.T) {
+	dir := t.TempDir()
+	sw := filepath.Join(dir, "swagger.json")
+	require.NoError(t, os.WriteFile(sw, []byte(`{"swagger":"2.0"}`), 0o600))
+
+	svc := trackings.New(&fakeRepo{}, nil, time.Minute)
+	api := trackingsapi.New(svc)
+
+	grpcLis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	httpLis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	grpcErr := make(chan error, 1)
+	go func() { grpcErr <- runGRPCServer(ctx, grpcLis, api, nil, nil, nil) }()
+
+	httpErr := make(chan error, 1)
+	go func() { httpErr <- runGatewayServer(ctx, httpLis, grpcLis.Addr().String(), sw, nil) }()
+
+	// ждём, пока gateway поднимется (очень коротко)
+	time.Sleep(50 * time.Millisecond)
+
+	resp, err := http.Get("http://" + httpLis.Addr().String() + "/swagger.json")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, 200, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	require.Contains(t, string(body), "\"swagger\"")
+
+	cancel()
+
+	select {
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting servers to stop")
+	case <-grpcErr:
+	}
+	select {
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting gateway to stop")
+	case <-httpErr:
+	}
+}
+
+func TestGateway_StreamCreateTrackings(t *testing.T) {
+	api := trackingsapi.New(trackings.New(&fakeRepo{}, nil, time.Minute))
+
+	grpcLis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	httpLis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = runGRPCServer(ctx, grpcLis, api, nil, nil, nil) }()
+	go func() { _ = runGatewayServer(ctx, httpLis, grpcLis.Addr().String(), "", nil) }()
+	time.Sleep(50 * time.Millisecond)
+
+	// newline-delimited JSON: по объекту на элемент потока. Последний элемент клиент шлёт только после
+	// ответа на первую пачку — тело запроса должно читаться и после начала ответа (full duplex).
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	firstRead := make(chan struct{})
+	stalled := make(chan bool, 1)
+	go func() {
+		_, _ = io.WriteString(pw, `{"carrierCode":"CDEK"}`+"\n")
+		for i := 1; i < trackings.BulkChunkSize; i++ {
+			_, _ = fmt.Fprintf(pw, `{"carrierCode":"CDEK","trackNumber":"10000%05d"}`+"\n", i)
+		}
+		select {
+		case <-firstRead:
+			stalled <- false
+		case <-time.After(5 * time.Second): // без full duplex ответа до конца тела не будет
+			stalled <- true
+		}
+		_, _ = io.WriteString(pw, `{"carrierCode":"CDEK","trackNumber":"1000099999"}`+"\n")
+		_ = pw.Close()
+	}()
+	resp, err := http.Post("http://"+httpLis.Addr().String()+"/trackings/stream", "application/json", pr)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	type chunk struct {
+		Result struct {
+			Results []struct {
+				Index  string `json:"index"`
+				Status string `json:"status"`
+				Reason string `json:"reason"`
+			} `json:"results"`
+			Created string `json:"created"`
+			Invalid string `json:"invalid"`
+		} `json:"result"`
+	}
+	var out []chunk
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		var c chunk
+		require.NoError(t, dec.Decode(&c))
+		out = append(out, c)
+		if len(out) == 1 {
+			close(firstRead)
+		}
+	}
+	require.False(t, <-stalled, "first chunk must be answered while the request body is still open")
+	require.Len(t, out, 2)
+	require.Len(t, out[0].Result.Results, trackings.BulkChunkSize)
+	require.Equal(t, "INVALID", out[0].Result.Results[0].Status)
+	require.Equal(t, "trackNumber is required", out[0].Result.Results[0].Reason)
+	require.Equal(t, "CREATED", out[0].Result.Results[1].Status)
+
+	require.Len(t, out[1].Result.Results, 1)
+	require.Equal(t, "1000", out[1].Result.Results[0].Index)
+	require.Equal(t, "CREATED", out[1].Result.Results[0].Status)
+	require.Equal(t, "1000", out[1].Result.Created)
+	require.Equal(t, "1", out[1].Result.Invalid)
+}
+
+func TestRunTrackAPI_SwaggerServed(t *testing.T) {
+	dir := t.TempDir()
+	sw := filepath.Join(dir, "swagger.json")
+	require.NoError(t, os.WriteFile(sw, []byte(`{"swagger":"2.0"}`), 0o600))
+
+	svc := trackings.New(&fakeRepo{}, nil, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	addrCh := make(chan string, 1)
+
+	opts := trackAPIOpts{
+		grpcAddr:     "127.0.0.1:0",
+		httpAddr:     "127.0.0.1:0",
+		grpcDialAddr: "127.0.0.1:0", // будет подменён внутри runTrackAPI
+		swaggerPath:  sw,
+		topic:        "t",
+		consumerGroup:"g",
+		onListen: func(_grpcAddr, httpAddr string) { addrCh <- httpAddr },
+	}
+
+	cons := fakeConsumer{}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- runTrackAPI(ctx, opts, svc, nil, nil, nil, nil, cons)
+	}()
+
+	httpAddr := <-addrCh
+
+	resp, err := http.Get("http://" + httpAddr + "/swagger.json")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, 200, resp.StatusCode)
+
+	cancel()
+	require.Error(t, <-errCh)
+}
+
+type fakeConsumer struct{}
+
+func (c fakeConsumer) Consume(ctx context.Context, handler func(key, value []byte) error) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+
@@ -0,0 +1,419 @@
+// Package trackworker — track-worker: poller + ops HTTP + внутренний gRPC (CheckTracking).
+package trackworker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/app"
+	"github.com/BearBump/TrackBox/internal/broker/kafka"
+	"github.com/BearBump/TrackBox/internal/cache/rediscache"
+	"github.com/BearBump/TrackBox/internal/geo"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier/emulatorv1"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier/fake"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier/track24http"
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/services/poller"
+	"github.com/BearBump/TrackBox/internal/services/sla"
+)
+
+// Options — то, что не лежит в конфиге.
+type Options struct {
+	SwaggerPath string
+	// ConfigPath включает hot reload конфига (SIGHUP + изменение файла); пусто — без reload.
+	ConfigPath string
+	// Override применяется к каждому перечитанному конфигу, чтобы флаги CLI не терялись при reload.
+	Override func(*config.Config)
+}
+
+// Run запускает track-worker и блокируется до отмены ctx.
+func Run(ctx context.Context, cfg *config.Config, opts Options) error {
+	return runTrackWorker(ctx, cfg, opts, defaultWorkerFactories(opts))
+}
+
+type workerFactories struct {
+	newStorage func(cfg *config.Config) (repo poller.Repository, closeFn func(), err error)
+	newProducer func(cfg *config.Config) poller.Producer
+	newRateLimiter func(cfg *config.Config) poller.RateLimiter
+	newCarrierClient func(cfg *config.Config) carrier.Client
+	// watchConfig (optional) calls apply with every successfully reloaded config until ctx is done.
+	watchConfig func(ctx context.Context, apply func(*config.Config))
+}
+
+func defaultWorkerFactories(opts Options) workerFactories {
+	return workerFactories{
+		newStorage: func(cfg *config.Config) (poller.Repository, func(), error) {
+			st, err := app.OpenStorage(context.Background(), cfg, 60*time.Second)
+			if err != nil {
+				return nil, nil, err
+			}
+			return st, st.Close, nil
+		},
+		newProducer: func(cfg *config.Config) poller.Producer {
+			return kafka.NewProducer(app.KafkaBrokers(cfg))
+		},
+		newRateLimiter: func(cfg *config.Config) poller.RateLimiter {
+			return rediscache.NewRateLimiter(app.RedisAddr(cfg))
+		},
+		newCarrierClient: func(cfg *config.Config) carrier.Client {
+			return newCarrierClient(cfg, cfg.TrackBox.CarrierEmulatorMode, cfg.TrackBox.CarrierEmulatorAPIKey)
+		},
+		watchConfig: func(ctx context.Context, apply func(*config.Config)) {
+			if opts.ConfigPath == "" {
+				return
+			}
+			config.Watch(ctx, opts.ConfigPath, 5*time.Second, func(next *config.Config) {
+				if opts.Override != nil {
+					opts.Override(next)
+					if err := next.Validate(); err != nil {
+						slog.Error("config reload rejected after flag overrides", "error", err.Error())
+						return
+					}
+				}
+				apply(next)
+			})
+		},
+	}
+}
+
+// newCarrierClient — клиент перевозчика по режиму ("v1" | "track24" | прочее — fake).
+// По умолчанию для демо используем python carrier-emulator, если задан base_url.
+// Иначе — fallback на локальный fake.
+func newCarrierClient(cfg *config.Config, mode, apiKey string) carrier.Client {
+	if cfg.TrackBox.CarrierEmulatorBaseURL != "" && mode != "" {
+		switch mode {
+		case "v1":
+			return emulatorv1.New(cfg.TrackBox.CarrierEmulatorBaseURL, apiKey)
+		case "track24":
+			return track24http.New(cfg.TrackBox.CarrierEmulatorBaseURL, apiKey, cfg.TrackBox.CarrierEmulatorDomain)
+		default:
+			return fake.New()
+		}
+	}
+	return fake.New()
+}
+
+func schedulingPolicies(sc *config.SchedulingConfig) poller.SchedulingPolicies {
+	out := poller.SchedulingPolicies{
+		Default:  schedulePolicy(sc.Default),
+		Carriers: make(map[string]poller.SchedulePolicy, len(sc.Carriers)),
+	}
+	for code, p := range sc.Carriers {
+		out.Carriers[code] = schedulePolicy(p)
+	}
+	return out
+}
+
+func schedulePolicy(p config.SchedulePolicyConfig) poller.SchedulePolicy {
+	sec := func(n int) time.Duration { return time.Duration(n) * time.Second }
+	out := poller.SchedulePolicy{
+		Statuses: make(map[string]poller.DelayRange, len(p.Statuses)),
+		Jitter:   poller.JitterMode(p.Jitter),
+	}
+	for st, r := range p.Statuses {
+		out.Statuses[st] = poller.DelayRange{Min: sec(r.MinSeconds), Max: sec(r.MaxSeconds)}
+	}
+	if b := p.Backoff; b != nil {
+		out.Backoff = poller.BackoffPolicy{
+			Initial:     sec(b.InitialSeconds),
+			Multiplier:  b.Multiplier,
+			Max:         sec(b.MaxSeconds),
+			GiveUpDelay: sec(b.GiveUpSeconds),
+		}
+		if b.MaxAttempts != nil {
+			n := int32(*b.MaxAttempts)
+			out.Backoff.MaxAttempts = &n
+		}
+	}
+	return out
+}
+
+func historyPlannerConfig(cfg *config.Config) poller.HistoryPlannerConfig {
+	hcfg := poller.HistoryPlannerConfig{
+		MinDelay:        time.Duration(cfg.TrackBox.WorkerPlannerMinDelaySeconds) * time.Second,
+		MaxDelay:        time.Duration(cfg.TrackBox.WorkerPlannerMaxDelaySeconds) * time.Second,
+		RefreshInterval: time.Duration(cfg.TrackBox.WorkerPlannerRefreshSeconds) * time.Second,
+	}
+	if len(cfg.TrackBox.CarrierBusinessHours) > 0 {
+		hcfg.BusinessHours = make(map[string]poller.BusinessHours, len(cfg.TrackBox.CarrierBusinessHours))
+	}
+	for carrierCode, bh := range cfg.TrackBox.CarrierBusinessHours {
+		loc := time.UTC
+		if bh.Timezone != "" {
+			l, err := time.LoadLocation(bh.Timezone)
+			if err != nil {
+				slog.Warn("unknown carrier timezone, using UTC", "carrier", carrierCode, "timezone", bh.Timezone)
+			} else {
+				loc = l
+			}
+		}
+		hcfg.BusinessHours[carrierCode] = poller.BusinessHours{Location: loc, StartHour: bh.StartHour, EndHour: bh.EndHour}
+	}
+	return hcfg
+}
+
+func runTrackWorker(ctx context.Context, cfg *config.Config, opts Options, f workerFactories) error {
+	workerSwaggerPath := opts.SwaggerPath
+	if workerSwaggerPath == "" {
+		workerSwaggerPath = "/app/swagger.json"
+	}
+
+	repo, closeFn, err := f.newStorage(cfg)
+	if err != nil {
+		return err
+	}
+	if closeFn != nil {
+		defer closeFn()
+	}
+
+	producer := f.newProducer(cfg)
+	rl := f.newRateLimiter(cfg)
+	// Маршруты по перевозчикам из таблицы carriers; без своего backend — клиент из конфига.
+	router := carrier.NewRouter(f.newCarrierClient(cfg))
+
+	planners := newWorkerPlanners(ctx, repo)
+	p := poller.New(repo, router, producer, rl, cfg.Kafka.TrackingUpdatedTopicName).
+		WithSettings(
+			time.Duration(cfg.TrackBox.WorkerPollIntervalSeconds)*time.Second,
+			cfg.TrackBox.WorkerBatchSize,
+			cfg.TrackBox.WorkerConcurrency,
+			time.Duration(cfg.TrackBox.WorkerLeaseSeconds)*time.Second,
+			int64(cfg.TrackBox.WorkerRateLimitPerMinute),
+		).
+		WithGeocoder(geo.Default()).
+		WithInstance(instanceName())
+	p.Reload(planners.liveSettings(cfg))
+	// Архив сырых ответов перевозчиков (trackbox.response_archive) — для разбора инцидентов и trackbox reparse.
+	arch, err := app.OpenArchive(cfg)
+	if err != nil {
+		return err
+	}
+	if arch != nil {
+		p.WithArchive(arch)
+	}
+
+	var current atomic.Pointer[config.Config]
+	current.Store(cfg)
+	if carriersRepo, ok := repo.(carrierRepository); ok {
+		cs := &carrierSync{repo: carriersRepo, cfg: current.Load, router: router, planners: planners, poller: p}
+		cs.sync(ctx)
+		go cs.run(ctx, time.Duration(cfg.TrackBox.CarriersRefreshSeconds)*time.Second)
+	}
+	// SLA: оценка идёт, только если блок trackbox.sla задан при старте; правила перечитываются на лету.
+	var slaSvc *sla.Service
+	if slaRepo, ok := repo.(sla.Repository); ok && cfg.TrackBox.SLA != nil {
+		slaSvc = sla.New(slaRepo).WithPublisher(producer, cfg.Kafka.TrackingAlertTopicName)
+		slaSvc.SetRules(sla.RulesFromConfig(cfg.TrackBox.SLA))
+		go slaSvc.Run(ctx, time.Duration(cfg.TrackBox.SLA.EvaluateIntervalSeconds)*time.Second)
+	}
+	if f.watchConfig != nil {
+		go f.watchConfig(ctx, func(next *config.Config) {
+			warnRestartRequired(current.Load(), next)
+			p.Reload(planners.liveSettings(next))
+			if slaSvc != nil {
+				slaSvc.SetRules(sla.RulesFromConfig(next.TrackBox.SLA))
+			}
+			current.Store(next)
+		})
+	}
+
+	go func() {
+		if err := runWorkerHTTPServer(ctx, workerHTTPOpts{
+			httpAddr:    cfg.TrackBox.WorkerHTTPAddr,
+			swaggerPath: workerSwaggerPath,
+			poller:      p,
+			cfg:         current.Load,
+			history:     planners.historyRepo,
+			strategies:  planners.backtestStrategies,
+		}); err != nil && err != context.Canceled {
+			slog.Error("worker http server stopped", "error", err.Error())
+		}
+	}()
+
+	go func() {
+		if err := runWorkerGRPCServer(ctx, workerGRPCOpts{
+			grpcAddr: cfg.TrackBox.WorkerGRPCAddr,
+			checker:  p,
+		}); err != nil && err != context.Canceled {
+			slog.Error("worker grpc server stopped", "error", err.Error())
+		}
+	}()
+
+	return p.Run(ctx)
+}
+
+// workerPlanners builds scheduling strategies from config. A single history planner lives for the whole
+// process, so its learned carrier cadence survives config reloads.
+type workerPlanners struct {
+	ctx         context.Context
+	historyRepo poller.HistoryRepository
+	history     *poller.HistoryPlanner
+	refreshOnce sync.Once
+
+	mu       sync.Mutex
+	base     poller.Strategy
+	carriers []*models.Carrier // последний снимок таблицы carriers (nil — не загружалась)
+}
+
+func newWorkerPlanners(ctx context.Context, repo poller.Repository) *workerPlanners {
+	historyRepo, _ := repo.(poller.HistoryRepository)
+	return &workerPlanners{
+		ctx:         ctx,
+		historyRepo: historyRepo,
+		history:     poller.NewHistoryPlanner(poller.HistoryPlannerConfig{}, nil),
+	}
+}
+
+func (w *workerPlanners) setCarriers(list []*models.Carrier) {
+	w.mu.Lock()
+	w.carriers = list
+	w.mu.Unlock()
+}
+
+func (w *workerPlanners) carrierList() []*models.Carrier {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.carriers
+}
+
+func (w *workerPlanners) liveSettings(cfg *config.Config) poller.LiveSettings {
+	return poller.LiveSettings{
+		BatchSize:          cfg.TrackBox.WorkerBatchSize,
+		RateLimitPerMinute: int64(cfg.TrackBox.WorkerRateLimitPerMinute),
+		CarrierRateLimits:  carrierRateLimits(cfg, w.carrierList()),
+		Strategy:           w.strategy(cfg),
+		ZoneRules:          zoneRules(cfg),
+	}
+}
+
+// zoneRules — пояса местного времени перевозчиков из carrier_timezones (конфиг уже провалидирован).
+func zoneRules(cfg *config.Config) map[string]poller.ZoneRule {
+	if len(cfg.TrackBox.CarrierTimezones) == 0 {
+		return nil
+	}
+	out := make(map[string]poller.ZoneRule, len(cfg.TrackBox.CarrierTimezones))
+	for code, tz := range cfg.TrackBox.CarrierTimezones {
+		rule := poller.ZoneRule{ByLocation: tz.ByLocation == nil || *tz.ByLocation}
+		if tz.Timezone != "" {
+			l, err := time.LoadLocation(tz.Timezone)
+			if err != nil {
+				slog.Warn("unknown carrier timezone, using UTC", "carrier", code, "timezone", tz.Timezone)
+			} else {
+				rule.Zone = l
+			}
+		}
+		out[code] = rule
+	}
+	return out
+}
+
+func (w *workerPlanners) strategy(cfg *config.Config) poller.Strategy {
+	var base poller.Strategy = poller.NewPlanner(plannerConfig(cfg), nil)
+	if cfg.TrackBox.Scheduling != nil {
+		base = poller.NewPolicyPlanner(schedulingPolicies(cfg.TrackBox.Scheduling), nil)
+	}
+	base = withCarrierPolicies(cfg, base, w.carrierList())
+	w.history.Reconfigure(historyPlannerConfig(cfg), base)
+	w.mu.Lock()
+	w.base = base
+	w.mu.Unlock()
+
+	if cfg.TrackBox.WorkerPlanner != "history" {
+		return base
+	}
+	if w.historyRepo == nil {
+		slog.Warn("history planner requested but storage has no event history; using static planner")
+		return base
+	}
+	// интервал обновления RunRefresh читает из настроек планировщика на каждом тике
+	w.refreshOnce.Do(func() {
+		go w.history.RunRefresh(w.ctx, w.historyRepo)
+	})
+	return w.history
+}
+
+func (w *workerPlanners) backtestStrategies() map[string]poller.Strategy {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return map[string]poller.Strategy{
+		"static":  w.base,
+		"history": w.history,
+	}
+}
+
+func plannerConfig(cfg *config.Config) poller.PlannerConfig {
+	sec := func(n int) time.Duration { return time.Duration(n) * time.Second }
+	return poller.PlannerConfig{
+		InTransitMinDelay: sec(cfg.TrackBox.WorkerNextCheckInTransitMinSeconds),
+		InTransitMaxDelay: sec(cfg.TrackBox.WorkerNextCheckInTransitMaxSeconds),
+		UnknownDelay:      sec(cfg.TrackBox.WorkerNextCheckUnknownSeconds),
+		Backoff1:          sec(cfg.TrackBox.WorkerBackoff1Seconds),
+		Backoff2:          sec(cfg.TrackBox.WorkerBackoff2Seconds),
+		Backoff3:          sec(cfg.TrackBox.WorkerBackoff3Seconds),
+		Backoff4:          sec(cfg.TrackBox.WorkerBackoff4Seconds),
+	}
+}
+
+// warnRestartRequired logs settings that changed in the file but are only applied on restart.
+func warnRestartRequired(prev, next *config.Config) {
+	if prev == nil || next == nil {
+		return
+	}
+	var changed []string
+	if prev.Database != next.Database {
+		changed = append(changed, "database")
+	}
+	if prev.Kafka != next.Kafka {
+		changed = append(changed, "kafka")
+	}
+	if prev.Redis != next.Redis {
+		changed = append(changed, "redis")
+	}
+	pt, nt := prev.TrackBox, next.TrackBox
+	if pt.WorkerPollIntervalSeconds != nt.WorkerPollIntervalSeconds || pt.WorkerConcurrency != nt.WorkerConcurrency ||
+		pt.WorkerLeaseSeconds != nt.WorkerLeaseSeconds {
+		changed = append(changed, "worker poll interval/concurrency/lease")
+	}
+	if pt.WorkerHTTPAddr != nt.WorkerHTTPAddr || pt.WorkerGRPCAddr != nt.WorkerGRPCAddr {
+		changed = append(changed, "worker addresses")
+	}
+	if pt.CarrierEmulatorBaseURL != nt.CarrierEmulatorBaseURL || pt.CarrierEmulatorMode != nt.CarrierEmulatorMode ||
+		pt.CarrierEmulatorAPIKey != nt.CarrierEmulatorAPIKey || pt.CarrierEmulatorDomain != nt.CarrierEmulatorDomain {
+		changed = append(changed, "carrier client")
+	}
+	if (pt.SLA == nil) != (nt.SLA == nil) ||
+		(pt.SLA != nil && nt.SLA != nil && pt.SLA.EvaluateIntervalSeconds != nt.SLA.EvaluateIntervalSeconds) {
+		changed = append(changed, "sla evaluator")
+	}
+	if (pt.ResponseArchive == nil) != (nt.ResponseArchive == nil) ||
+		(pt.ResponseArchive != nil && nt.ResponseArchive != nil && *pt.ResponseArchive != *nt.ResponseArchive) {
+		changed = append(changed, "response archive")
+	}
+	if len(changed) > 0 {
+		slog.Warn("config changes require a restart to take effect", "sections", changed)
+	}
+}
+
+// RebuildPoller — poller с той же нормализацией ответов, что у worker'а (geo, пояса местного времени),
+// но без перевозчика, Kafka и rate limit: только для Poller.Rebuild (trackbox reparse).
+func RebuildPoller(cfg *config.Config) *poller.Poller {
+	p := poller.New(nil, nil, nil, nil, "").WithGeocoder(geo.Default())
+	p.Reload(poller.LiveSettings{ZoneRules: zoneRules(cfg)})
+	return p
+}
+
+// instanceName — имя экземпляра worker'а в журнале проверок: hostname (в Docker — id контейнера) и pid.
+func instanceName() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "track-worker"
+	}
+	return fmt.Sprintf("%s/%d", host, os.Getpid())
+}
@@ -1,4 +1,4 @@
-package main
+package trackworker
 
 import (
 	"context"
@@ -26,7 +26,7 @@ type noopProducer struct{}
 func (p noopProducer) Publish(ctx context.Context, topic string, key, value []byte) error { return nil }
 
 func TestDefaultWorkerFactories_SelectCarrierClient(t *testing.T) {
-	f := defaultWorkerFactories()
+	f := defaultWorkerFactories(Options{})
 
 	cfgV1 := &config.Config{
 		TrackBox: config.TrackBoxConfig{
@@ -63,7 +63,7 @@ func TestDefaultWorkerFactories_SelectCarrierClient(t *testing.T) {
 }
 
 func TestDefaultWorkerFactories_ProducerAndRateLimiter_NonNil(t *testing.T) {
-	f := defaultWorkerFactories()
+	f := defaultWorkerFactories(Options{})
 	cfg := &config.Config{
 		Kafka: config.KafkaConfig{Host: "localhost", Port: 9092},
 		Redis: config.RedisConfig{Host: "localhost", Port: 6379},
@@ -72,7 +72,7 @@ func TestDefaultWorkerFactories_ProducerAndRateLimiter_NonNil(t *testing.T) {
 	require.NotNil(t, f.newRateLimiter(cfg))
 }
 
-func TestRunTrackWorker_ContextCanceled(t *testing.T) {
+func TestRun_ContextCanceled(t *testing.T) {
 	calledClose := false
 
 	f := workerFactories{
@@ -94,13 +94,43 @@ func TestRunTrackWorker_ContextCanceled(t *testing.T) {
 		Kafka:    config.KafkaConfig{TrackingUpdatedTopicName: "t"},
 		TrackBox: config.TrackBoxConfig{WorkerPollIntervalSeconds: 1},
 	}
+	cfg.ApplyDefaults()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
-	err := RunTrackWorker(ctx, cfg, f)
+	err := runTrackWorker(ctx, cfg, Options{}, f)
 	require.ErrorIs(t, err, context.Canceled)
 	require.True(t, calledClose)
 }
 
 
+
+func TestSchedulingPolicies_FromConfig(t *testing.T) {
+	maxAttempts, unlimited := 3, 0
+	sp := schedulingPolicies(&config.SchedulingConfig{
+		Default: config.SchedulePolicyConfig{
+			Jitter: "none",
+			Statuses: map[string]config.DelayRangeConfig{
+				"IN_TRANSIT": {MinSeconds: 1800, MaxSeconds: 7200},
+			},
+			Backoff: &config.BackoffConfig{InitialSeconds: 300, Multiplier: 2, MaxSeconds: 3600, MaxAttempts: &maxAttempts, GiveUpSeconds: 86400},
+		},
+		Carriers: map[string]config.SchedulePolicyConfig{
+			"POST_RU": {Statuses: map[string]config.DelayRangeConfig{"IN_TRANSIT": {MinSeconds: 3600}}},
+			"CDEK":    {Backoff: &config.BackoffConfig{MaxAttempts: &unlimited}},
+		},
+	})
+
+	require.Equal(t, poller.JitterNone, sp.Default.Jitter)
+	require.Equal(t, poller.DelayRange{Min: 30 * time.Minute, Max: 2 * time.Hour}, sp.Default.Statuses["IN_TRANSIT"])
+	require.Equal(t, int32(3), *sp.Default.Backoff.MaxAttempts)
+	require.Equal(t, 24*time.Hour, sp.Default.Backoff.GiveUpDelay)
+	require.Equal(t, time.Hour, sp.Carriers["POST_RU"].Statuses["IN_TRANSIT"].Min)
+
+	pp := poller.NewPolicyPlanner(sp, nil)
+	require.Equal(t, time.Hour, pp.PlanNextCheck(poller.PlanInput{CarrierCode: "POST_RU", Status: "IN_TRANSIT"}))
+	require.Equal(t, 24*time.Hour, pp.PlanRetry(poller.PlanInput{CarrierCode: "POST_RU", FailCount: 4}))
+	// max_attempts: 0 у перевозчика снимает лимит default
+	require.Equal(t, time.Hour, pp.PlanRetry(poller.PlanInput{CarrierCode: "CDEK", FailCount: 10}))
+}
@@ -0,0 +1,135 @@
+package trackworker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier/fake"
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/services/poller"
+)
+
+// carrierRepository — справочник перевозчиков; если storage его не умеет, worker работает только по конфигу.
+type carrierRepository interface {
+	ListCarriers(ctx context.Context) ([]*models.Carrier, error)
+}
+
+// carrierSync периодически перечитывает таблицу carriers и применяет её к poller'у:
+// клиенты перевозчиков, лимиты запросов и политики планирования.
+type carrierSync struct {
+	repo     carrierRepository
+	cfg      func() *config.Config
+	router   *carrier.Router
+	planners *workerPlanners
+	poller   *poller.Poller
+}
+
+func (s *carrierSync) run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			s.sync(ctx)
+		}
+	}
+}
+
+// sync применяет текущее состояние таблицы; при ошибке чтения остаётся предыдущее.
+func (s *carrierSync) sync(ctx context.Context) {
+	list, err := s.repo.ListCarriers(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Warn("carriers refresh failed", "error", err.Error())
+		}
+		return
+	}
+	cfg := s.cfg()
+	s.planners.setCarriers(list)
+	s.router.SetRoutes(carrierRoutes(cfg, list))
+	s.poller.Reload(s.planners.liveSettings(cfg))
+}
+
+// carrierRoutes строит клиентов для перевозчиков со своим backend или ключом.
+// Адрес и домен эмулятора — из конфига worker'а; ключ — по credentials_ref.
+func carrierRoutes(cfg *config.Config, list []*models.Carrier) map[string]carrier.Client {
+	routes := make(map[string]carrier.Client)
+	for _, c := range list {
+		if !c.Enabled || (c.Backend == models.CarrierBackendDefault && c.CredentialsRef == "") {
+			continue
+		}
+		key := cfg.TrackBox.CarrierEmulatorAPIKey
+		if c.CredentialsRef != "" {
+			v, err := config.ResolveSecretRef(c.CredentialsRef)
+			if err != nil {
+				slog.Warn("carrier credentials unavailable, using default client", "carrier", c.Code, "error", err.Error())
+				continue
+			}
+			key = v
+		}
+		switch c.Backend {
+		case models.CarrierBackendEmulatorV1:
+			routes[c.Code] = newCarrierClient(cfg, "v1", key)
+		case models.CarrierBackendTrack24:
+			routes[c.Code] = newCarrierClient(cfg, "track24", key)
+		case models.CarrierBackendFake:
+			routes[c.Code] = fake.New()
+		default:
+			routes[c.Code] = newCarrierClient(cfg, cfg.TrackBox.CarrierEmulatorMode, key)
+		}
+	}
+	return routes
+}
+
+// carrierRateLimits — лимиты по перевозчикам: устаревшие поля конфига, поверх — значения из таблицы carriers.
+func carrierRateLimits(cfg *config.Config, list []*models.Carrier) map[string]int64 {
+	out := map[string]int64{
+		"CDEK":    int64(cfg.TrackBox.WorkerRateLimitCDEKPerMinute),
+		"POST_RU": int64(cfg.TrackBox.WorkerRateLimitPostRuPerMinute),
+	}
+	for _, c := range list {
+		if c.RateLimitPerMinute > 0 {
+			out[c.Code] = int64(c.RateLimitPerMinute)
+		}
+	}
+	return out
+}
+
+// withCarrierPolicies отдаёт перевозчиков с политикой в таблице carriers отдельному PolicyPlanner'у
+// (незаданное в политике наследуется от scheduling.default конфига); остальные остаются на base.
+func withCarrierPolicies(cfg *config.Config, base poller.Strategy, list []*models.Carrier) poller.Strategy {
+	policies := make(map[string]poller.SchedulePolicy)
+	for _, c := range list {
+		if c.SchedulingJSON == nil {
+			continue
+		}
+		p, err := config.ParseSchedulePolicy([]byte(*c.SchedulingJSON), "carriers."+c.Code+".scheduling")
+		if err != nil {
+			slog.Warn("invalid carrier scheduling policy ignored", "carrier", c.Code, "error", err.Error())
+			continue
+		}
+		policies[c.Code] = schedulePolicy(p)
+	}
+	if len(policies) == 0 {
+		return base
+	}
+
+	var def poller.SchedulePolicy
+	if cfg.TrackBox.Scheduling != nil {
+		def = schedulePolicy(cfg.TrackBox.Scheduling.Default)
+	}
+	pp := poller.NewPolicyPlanner(poller.SchedulingPolicies{Default: def, Carriers: policies}, nil)
+	routes := make(map[string]poller.Strategy, len(policies))
+	for code := range policies {
+		routes[code] = pp
+	}
+	return poller.CarrierStrategy{Default: base, Carriers: routes}
+}
@@ -0,0 +1,86 @@
+package trackworker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier/emulatorv1"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier/fake"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier/track24http"
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/services/poller"
+	"github.com/stretchr/testify/require"
+)
+
+type carriersRepo struct {
+	fakeRepo
+	list []*models.Carrier
+}
+
+func (r *carriersRepo) ListCarriers(context.Context) ([]*models.Carrier, error) {
+	return r.list, nil
+}
+
+func TestCarrierRoutes(t *testing.T) {
+	t.Setenv("TB_TEST_DHL_KEY", "dhl-key")
+	cfg := &config.Config{TrackBox: config.TrackBoxConfig{
+		CarrierEmulatorBaseURL: "http://localhost:9000",
+		CarrierEmulatorMode:    "v1",
+		CarrierEmulatorAPIKey:  "k",
+	}}
+	routes := carrierRoutes(cfg, []*models.Carrier{
+		{Code: "CDEK", Enabled: true},
+		{Code: "DHL", Backend: models.CarrierBackendTrack24, CredentialsRef: "env:TB_TEST_DHL_KEY", Enabled: true},
+		{Code: "UPS", Backend: models.CarrierBackendFake, Enabled: true},
+		{Code: "POST_RU", CredentialsRef: "env:TB_TEST_DHL_KEY", Enabled: true},
+		{Code: "OFF", Backend: models.CarrierBackendFake},
+		{Code: "NOKEY", Backend: models.CarrierBackendTrack24, CredentialsRef: "env:TB_TEST_MISSING_KEY", Enabled: true},
+	})
+
+	require.Len(t, routes, 3)
+	require.IsType(t, &track24http.Client{}, routes["DHL"])
+	require.IsType(t, &fake.FakeClient{}, routes["UPS"])
+	require.IsType(t, &emulatorv1.Client{}, routes["POST_RU"])
+}
+
+func TestCarrierRateLimits(t *testing.T) {
+	cfg := &config.Config{TrackBox: config.TrackBoxConfig{WorkerRateLimitCDEKPerMinute: 60, WorkerRateLimitPostRuPerMinute: 20}}
+	got := carrierRateLimits(cfg, []*models.Carrier{
+		{Code: "CDEK", RateLimitPerMinute: 90},
+		{Code: "POST_RU"},
+		{Code: "DHL", RateLimitPerMinute: 10},
+	})
+	require.Equal(t, map[string]int64{"CDEK": 90, "POST_RU": 20, "DHL": 10}, got)
+}
+
+func TestCarrierSync_AppliesToPoller(t *testing.T) {
+	policy := `{"jitter":"none","statuses":{"IN_TRANSIT":{"min_seconds":600}}}`
+	repo := &carriersRepo{list: []*models.Carrier{
+		{Code: "DHL", Backend: models.CarrierBackendFake, RateLimitPerMinute: 10, SchedulingJSON: &policy, Enabled: true},
+	}}
+	cfg := &config.Config{}
+	cfg.ApplyDefaults()
+	cfg.TrackBox.WorkerPlanner = "static"
+
+	planners := newWorkerPlanners(context.Background(), repo)
+	p := poller.New(repo, nil, noopProducer{}, nil, "t")
+	cs := &carrierSync{
+		repo:     repo,
+		cfg:      func() *config.Config { return cfg },
+		router:   carrier.NewRouter(nil),
+		planners: planners,
+		poller:   p,
+	}
+	cs.sync(context.Background())
+
+	live := p.Live()
+	require.Equal(t, int64(10), live.CarrierRateLimits["DHL"])
+	require.Equal(t, 10*time.Minute, live.Strategy.PlanNextCheck(poller.PlanInput{CarrierCode: "DHL", Status: models.TrackingStatusInTransit}))
+
+	res, err := cs.router.GetTracking(context.Background(), "DHL", "JD0001")
+	require.NoError(t, err)
+	require.NotEmpty(t, res.Status)
+}
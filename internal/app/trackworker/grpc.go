@@ -0,0 +1,55 @@
+package trackworker
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"time"
+
+	workerapi "github.com/BearBump/TrackBox/internal/api/worker_api"
+	"github.com/BearBump/TrackBox/internal/pb/worker_api"
+	"google.golang.org/grpc"
+)
+
+type workerGRPCOpts struct {
+	grpcAddr string
+	onListen func(grpcAddr string)
+
+	checker workerapi.Checker
+}
+
+// runWorkerGRPCServer поднимает внутренний gRPC API воркера (CheckTracking для track-api).
+func runWorkerGRPCServer(ctx context.Context, opts workerGRPCOpts) error {
+	if opts.grpcAddr == "" {
+		opts.grpcAddr = ":50052"
+	}
+
+	lis, err := net.Listen("tcp", opts.grpcAddr)
+	if err != nil {
+		return err
+	}
+	if opts.onListen != nil {
+		opts.onListen(lis.Addr().String())
+	}
+
+	s := grpc.NewServer()
+	worker_api.RegisterWorkerServiceServer(s, workerapi.New(opts.checker))
+
+	go func() {
+		<-ctx.Done()
+		stopped := make(chan struct{})
+		go func() {
+			s.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(2 * time.Second):
+			s.Stop()
+		}
+		_ = lis.Close()
+	}()
+
+	slog.Info("worker gRPC server listening", "addr", lis.Addr().String())
+	return s.Serve(lis)
+}
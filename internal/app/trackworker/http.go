@@ -1,4 +1,4 @@
-package main
+package trackworker
 
 import (
 	"context"
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/BearBump/TrackBox/config"
@@ -21,7 +22,11 @@ type workerHTTPOpts struct {
 	onListen   func(httpAddr string)
 
 	poller *poller.Poller
-	cfg    *config.Config
+	cfg    func() *config.Config // current config (changes on hot reload)
+
+	// Backtest: stored event histories and the strategies to compare on them.
+	history    poller.HistoryRepository
+	strategies func() map[string]poller.Strategy
 }
 
 func runWorkerHTTPServer(ctx context.Context, opts workerHTTPOpts) error {
@@ -65,22 +70,27 @@ func runWorkerHTTPServer(ctx context.Context, opts workerHTTPOpts) error {
 
 	r.Get("/config", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		if opts.cfg == nil {
+		if opts.cfg == nil || opts.cfg() == nil {
 			_, _ = w.Write([]byte(`{"error":"config not wired"}`))
 			return
 		}
+		cfg := opts.cfg()
 		// Avoid dumping secrets; show only operational worker settings.
 		out := map[string]any{
-			"pollIntervalSeconds": opts.cfg.TrackBox.WorkerPollIntervalSeconds,
-			"batchSize":           opts.cfg.TrackBox.WorkerBatchSize,
-			"concurrency":         opts.cfg.TrackBox.WorkerConcurrency,
-			"leaseSeconds":        opts.cfg.TrackBox.WorkerLeaseSeconds,
-			"rateLimitPerMinute":  opts.cfg.TrackBox.WorkerRateLimitPerMinute,
-			"rateLimitCDEKPerMinute":   opts.cfg.TrackBox.WorkerRateLimitCDEKPerMinute,
-			"rateLimitPostRuPerMinute": opts.cfg.TrackBox.WorkerRateLimitPostRuPerMinute,
-			"nextCheckInTransitMinSeconds": opts.cfg.TrackBox.WorkerNextCheckInTransitMinSeconds,
-			"nextCheckInTransitMaxSeconds": opts.cfg.TrackBox.WorkerNextCheckInTransitMaxSeconds,
-			"nextCheckUnknownSeconds":      opts.cfg.TrackBox.WorkerNextCheckUnknownSeconds,
+			"pollIntervalSeconds": cfg.TrackBox.WorkerPollIntervalSeconds,
+			"batchSize":           cfg.TrackBox.WorkerBatchSize,
+			"concurrency":         cfg.TrackBox.WorkerConcurrency,
+			"leaseSeconds":        cfg.TrackBox.WorkerLeaseSeconds,
+			"rateLimitPerMinute":  cfg.TrackBox.WorkerRateLimitPerMinute,
+			"nextCheckInTransitMinSeconds": cfg.TrackBox.WorkerNextCheckInTransitMinSeconds,
+			"nextCheckInTransitMaxSeconds": cfg.TrackBox.WorkerNextCheckInTransitMaxSeconds,
+			"nextCheckUnknownSeconds":      cfg.TrackBox.WorkerNextCheckUnknownSeconds,
+			"planner":                      cfg.TrackBox.WorkerPlanner,
+			"schedulingPolicies":           cfg.TrackBox.Scheduling != nil,
+		}
+		if opts.poller != nil {
+			// Действующие лимиты по перевозчикам: конфиг + справочник carriers.
+			out["carrierRateLimits"] = opts.poller.Live().CarrierRateLimits
 		}
 		_ = json.NewEncoder(w).Encode(out)
 	})
@@ -95,6 +105,50 @@ func runWorkerHTTPServer(ctx context.Context, opts workerHTTPOpts) error {
 		_, _ = w.Write([]byte(`{"triggered":true}`))
 	})
 
+	r.Get("/planner/backtest", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if opts.history == nil || opts.strategies == nil {
+			_, _ = w.Write([]byte(`{"error":"event history not wired"}`))
+			return
+		}
+		days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+		if days <= 0 {
+			days = 30
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		now := time.Now().UTC()
+		since := now.Add(-time.Duration(days) * 24 * time.Hour)
+
+		histories, err := opts.history.LoadTrackingHistories(r.Context(), r.URL.Query().Get("carrier"), since, limit)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		out := map[string]any{}
+		for name, s := range opts.strategies() {
+			if hp, ok := s.(*poller.HistoryPlanner); ok {
+				if err := hp.Refresh(r.Context(), opts.history, now); err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+					return
+				}
+			}
+			rep := poller.Backtest(s, histories)
+			out[name] = map[string]any{
+				"trackings":                 rep.Trackings,
+				"checks":                    rep.Checks,
+				"events":                    rep.Events,
+				"checksPerEvent":            rep.ChecksPerEvent,
+				"meanDetectionDelaySeconds": rep.MeanDetectionDelay.Seconds(),
+				"p90DetectionDelaySeconds":  rep.P90DetectionDelay.Seconds(),
+				"maxDetectionDelaySeconds":  rep.MaxDetectionDelay.Seconds(),
+			}
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	})
+
 	// Serve swagger with no-cache + cachebuster (same trick as track-api).
 	r.Get("/swagger.json", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Cache-Control", "no-store")
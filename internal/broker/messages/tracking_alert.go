@@ -0,0 +1,37 @@
+package messages
+
+import (
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+)
+
+// TrackingAlert — открытие или закрытие алерта SLA (топик tracking.alert, ключ — tracking_id).
+// Доставка at-least-once: подписчик должен быть готов к повтору (id + state).
+type TrackingAlert struct {
+	ID          uint64     `json:"id"`
+	TrackingID  uint64     `json:"tracking_id"`
+	CarrierCode string     `json:"carrier_code"`
+	TrackNumber string     `json:"track_number"`
+	Kind        string     `json:"kind"`
+	State       string     `json:"state"` // OPEN | RESOLVED
+	Rule        string     `json:"rule"`
+	Details     string     `json:"details,omitempty"`
+	OpenedAt    time.Time  `json:"opened_at"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+}
+
+func TrackingAlertFromModel(a *models.Alert) TrackingAlert {
+	return TrackingAlert{
+		ID:          a.ID,
+		TrackingID:  a.TrackingID,
+		CarrierCode: a.CarrierCode,
+		TrackNumber: a.TrackNumber,
+		Kind:        a.Kind,
+		State:       a.State(),
+		Rule:        a.Rule,
+		Details:     a.Details,
+		OpenedAt:    a.OpenedAt,
+		ResolvedAt:  a.ResolvedAt,
+	}
+}
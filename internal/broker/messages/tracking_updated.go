@@ -3,12 +3,31 @@ package messages
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+)
+
+// Виды сообщений TrackingUpdated (поле kind).
+const (
+	// KindStatusChanged — сменился статус трека (или это первая успешная проверка).
+	KindStatusChanged = "status_changed"
+	// KindEventsChanged — статус тот же, но у перевозчика новые/исправленные события или сведения об отправлении.
+	KindEventsChanged = "events_changed"
+	// KindCheckedNoChange — ответ перевозчика не изменился: в сообщении нет events и shipment.
+	KindCheckedNoChange = "checked_no_change"
+	// KindCheckFailed — перевозчик ответил ошибкой (см. error).
+	KindCheckFailed = "check_failed"
 )
 
 type TrackingUpdated struct {
 	TrackingID uint64 `json:"tracking_id"`
 	CheckedAt  time.Time `json:"checked_at"`
 
+	// Kind — см. Kind*; пусто у сообщений от старых воркеров (считаются полными).
+	Kind string `json:"kind,omitempty"`
+	// Fingerprint — отпечаток ответа перевозчика, сохраняется у трека для следующей проверки.
+	Fingerprint string `json:"fingerprint,omitempty"`
+
 	Status    string `json:"status,omitempty"`
 	StatusRaw string `json:"status_raw,omitempty"`
 	StatusAt  *time.Time `json:"status_at,omitempty"`
@@ -17,7 +36,17 @@ type TrackingUpdated struct {
 
 	Events []TrackingEvent `json:"events,omitempty"`
 
+	Shipment *Shipment `json:"shipment,omitempty"`
+
 	Error *string `json:"error,omitempty"`
+
+	// Check — как прошла проверка (журнал проверок track-api); nil у сообщений от старых воркеров.
+	Check *CheckInfo `json:"check,omitempty"`
+
+	// Пользовательские атрибуты трека на момент проверки — чтобы подписчикам не ходить за ними в API.
+	ExternalID *string         `json:"external_id,omitempty"`
+	Tags       []string        `json:"tags,omitempty"`
+	Metadata   json.RawMessage `json:"metadata,omitempty"`
 }
 
 type TrackingEvent struct {
@@ -27,6 +56,103 @@ type TrackingEvent struct {
 	Location  *string `json:"location,omitempty"`
 	Message   *string `json:"message,omitempty"`
 	Payload   json.RawMessage `json:"payload,omitempty"`
+	Place     *Place `json:"place,omitempty"`
+
+	// EventTimeRaw — время строкой, как его прислал перевозчик; TimeInferred — время подставлено worker'ом.
+	EventTimeRaw *string `json:"event_time_raw,omitempty"`
+	TimeInferred bool    `json:"time_inferred,omitempty"`
+}
+
+
+
+// CheckInfo — сведения о самой проверке: кто и через какой бэкенд ходил к перевозчику и что получил.
+type CheckInfo struct {
+	Worker       string `json:"worker,omitempty"`  // экземпляр track-worker
+	Backend      string `json:"backend,omitempty"` // models.CarrierBackend*
+	LatencyMS    int64  `json:"latency_ms"`
+	HTTPStatus   int    `json:"http_status,omitempty"`
+	ErrorClass   string `json:"error_class,omitempty"` // carrier.ErrorClass*
+	EventsCount  int    `json:"events_count"`          // событий в ответе перевозчика (до отсева неизменившихся)
+	ResponseHash string `json:"response_hash,omitempty"`
+	ResponseRef  string `json:"response_ref,omitempty"` // ключ ответа в архиве (trackbox.response_archive)
+}
+
+// Shipment — сведения об отправлении из ответа перевозчика (см. models.ShipmentDetails).
+type Shipment struct {
+	EstimatedDelivery *time.Time `json:"estimated_delivery,omitempty"`
+	WeightGrams       int32      `json:"weight_grams,omitempty"`
+	Origin            string     `json:"origin,omitempty"`
+	Destination       string     `json:"destination,omitempty"`
+	RecipientCity     string     `json:"recipient_city,omitempty"`
+	ServiceType       string     `json:"service_type,omitempty"`
 }
 
+// ShipmentFromModel — nil, если перевозчик ничего не сообщил.
+func ShipmentFromModel(d *models.ShipmentDetails) *Shipment {
+	if d.IsEmpty() {
+		return nil
+	}
+	return &Shipment{
+		EstimatedDelivery: d.EstimatedDelivery,
+		WeightGrams:       d.WeightGrams,
+		Origin:            d.Origin,
+		Destination:       d.Destination,
+		RecipientCity:     d.RecipientCity,
+		ServiceType:       d.ServiceType,
+	}
+}
+
+// Model — обратное преобразование; nil для nil.
+func (s *Shipment) Model() *models.ShipmentDetails {
+	if s == nil {
+		return nil
+	}
+	return &models.ShipmentDetails{
+		EstimatedDelivery: s.EstimatedDelivery,
+		WeightGrams:       s.WeightGrams,
+		Origin:            s.Origin,
+		Destination:       s.Destination,
+		RecipientCity:     s.RecipientCity,
+		ServiceType:       s.ServiceType,
+	}
+}
 
+// Place — место события в структурированном виде (см. models.Location).
+type Place struct {
+	Country    string   `json:"country,omitempty"`
+	Region     string   `json:"region,omitempty"`
+	City       string   `json:"city,omitempty"`
+	PostalCode string   `json:"postal_code,omitempty"`
+	Lat        *float64 `json:"lat,omitempty"`
+	Lon        *float64 `json:"lon,omitempty"`
+}
+
+// PlaceFromModel — nil, если о месте ничего не известно.
+func PlaceFromModel(l *models.Location) *Place {
+	if l.IsEmpty() {
+		return nil
+	}
+	return &Place{
+		Country:    l.Country,
+		Region:     l.Region,
+		City:       l.City,
+		PostalCode: l.PostalCode,
+		Lat:        l.Lat,
+		Lon:        l.Lon,
+	}
+}
+
+// Model — обратное преобразование; nil для nil.
+func (p *Place) Model() *models.Location {
+	if p == nil {
+		return nil
+	}
+	return &models.Location{
+		Country:    p.Country,
+		Region:     p.Region,
+		City:       p.City,
+		PostalCode: p.PostalCode,
+		Lat:        p.Lat,
+		Lon:        p.Lon,
+	}
+}
@@ -7,9 +7,19 @@ import (
 
 type BytesCache interface {
 	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// MGet читает ключи одним запросом; результат — в порядке keys, nil на месте отсутствующего ключа.
+	MGet(ctx context.Context, keys []string) ([][]byte, error)
 	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// MSet записывает несколько ключей за один round-trip, у каждого свой TTL.
+	MSet(ctx context.Context, items []Item) error
+	Delete(ctx context.Context, keys ...string) error
 }
 
-//go:generate mockery
-
+// Item — запись для MSet.
+type Item struct {
+	Key   string
+	Value []byte
+	TTL   time.Duration
+}
 
+//go:generate mockery
@@ -0,0 +1,135 @@
+// Package memcache — ограниченный кэш в памяти процесса (LRU + TTL), реализация cache.BytesCache.
+package memcache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/cache"
+)
+
+// Cache держит не больше maxEntries ключей; при переполнении вытесняется давно не читанный.
+// Запись живёт не дольше maxTTL, даже если при записи передан TTL больше (или 0).
+type Cache struct {
+	maxEntries int
+	maxTTL     time.Duration
+	now        func() time.Time
+
+	mu    sync.Mutex
+	ll    *list.List // от недавно использованных к давно не использованным
+	items map[string]*list.Element
+}
+
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func New(maxEntries int, maxTTL time.Duration) *Cache {
+	return &Cache{
+		maxEntries: maxEntries,
+		maxTTL:     maxTTL,
+		now:        time.Now,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.get(key, c.now())
+	return v, ok, nil
+}
+
+func (c *Cache) MGet(ctx context.Context, keys []string) ([][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	out := make([][]byte, len(keys))
+	for i, k := range keys {
+		out[i], _ = c.get(k, now)
+	}
+	return out, nil
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, value, ttl, c.now())
+	return nil
+}
+
+func (c *Cache) MSet(ctx context.Context, items []cache.Item) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for _, it := range items {
+		c.set(it.Key, it.Value, it.TTL, now)
+	}
+	return nil
+}
+
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range keys {
+		if el, ok := c.items[k]; ok {
+			c.remove(el)
+		}
+	}
+	return nil
+}
+
+// Purge удаляет все записи.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	clear(c.items)
+}
+
+// Len — число записей (включая истёкшие, но ещё не вытесненные).
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *Cache) get(key string, now time.Time) ([]byte, bool) {
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !now.Before(e.expiresAt) {
+		c.remove(el)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *Cache) set(key string, value []byte, ttl time.Duration, now time.Time) {
+	if ttl <= 0 || ttl > c.maxTTL {
+		ttl = c.maxTTL
+	}
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value, e.expiresAt = value, now.Add(ttl)
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&entry{key: key, value: value, expiresAt: now.Add(ttl)})
+	for c.ll.Len() > c.maxEntries {
+		c.remove(c.ll.Back())
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry).key)
+}
@@ -0,0 +1,58 @@
+package memcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/cache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_LRU(t *testing.T) {
+	c := New(2, time.Minute)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+	_, ok, _ := c.Get(ctx, "a") // "a" теперь недавно читанный
+	require.True(t, ok)
+	require.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+
+	vals, err := c.MGet(ctx, []string{"a", "b", "c"})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("1"), nil, []byte("3")}, vals)
+	require.Equal(t, 2, c.Len())
+
+	require.NoError(t, c.MSet(ctx, []cache.Item{{Key: "a", Value: []byte("10")}, {Key: "d", Value: []byte("4")}}))
+	vals, _ = c.MGet(ctx, []string{"a", "c", "d"})
+	require.Equal(t, [][]byte{[]byte("10"), nil, []byte("4")}, vals)
+
+	require.NoError(t, c.Delete(ctx, "a", "missing"))
+	_, ok, _ = c.Get(ctx, "a")
+	require.False(t, ok)
+
+	c.Purge()
+	require.Equal(t, 0, c.Len())
+}
+
+func TestCache_TTL(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New(10, 5*time.Second)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "short", []byte("1"), time.Second))
+	require.NoError(t, c.Set(ctx, "long", []byte("2"), time.Hour)) // не дольше maxTTL
+
+	now = now.Add(2 * time.Second)
+	_, ok, _ := c.Get(ctx, "short")
+	require.False(t, ok)
+	_, ok, _ = c.Get(ctx, "long")
+	require.True(t, ok)
+
+	now = now.Add(3 * time.Second)
+	_, ok, _ = c.Get(ctx, "long")
+	require.False(t, ok)
+	require.Equal(t, 0, c.Len())
+}
@@ -4,9 +4,12 @@ package mocks
 
 import (
 	context "context"
-	time "time"
+
+	cache "github.com/BearBump/TrackBox/internal/cache"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // MockBytesCache is an autogenerated mock type for the BytesCache type
@@ -22,6 +25,67 @@ func (_m *MockBytesCache) EXPECT() *MockBytesCache_Expecter {
 	return &MockBytesCache_Expecter{mock: &_m.Mock}
 }
 
+// Delete provides a mock function with given fields: ctx, keys
+func (_m *MockBytesCache) Delete(ctx context.Context, keys ...string) error {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) error); ok {
+		r0 = rf(ctx, keys...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockBytesCache_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockBytesCache_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keys ...string
+func (_e *MockBytesCache_Expecter) Delete(ctx interface{}, keys ...interface{}) *MockBytesCache_Delete_Call {
+	return &MockBytesCache_Delete_Call{Call: _e.mock.On("Delete",
+		append([]interface{}{ctx}, keys...)...)}
+}
+
+func (_c *MockBytesCache_Delete_Call) Run(run func(ctx context.Context, keys ...string)) *MockBytesCache_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]string, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		run(args[0].(context.Context), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockBytesCache_Delete_Call) Return(_a0 error) *MockBytesCache_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockBytesCache_Delete_Call) RunAndReturn(run func(context.Context, ...string) error) *MockBytesCache_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Get provides a mock function with given fields: ctx, key
 func (_m *MockBytesCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
 	ret := _m.Called(ctx, key)
@@ -88,6 +152,112 @@ func (_c *MockBytesCache_Get_Call) RunAndReturn(run func(context.Context, string
 	return _c
 }
 
+// MGet provides a mock function with given fields: ctx, keys
+func (_m *MockBytesCache) MGet(ctx context.Context, keys []string) ([][]byte, error) {
+	ret := _m.Called(ctx, keys)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MGet")
+	}
+
+	var r0 [][]byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([][]byte, error)); ok {
+		return rf(ctx, keys)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) [][]byte); ok {
+		r0 = rf(ctx, keys)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([][]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, keys)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockBytesCache_MGet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MGet'
+type MockBytesCache_MGet_Call struct {
+	*mock.Call
+}
+
+// MGet is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keys []string
+func (_e *MockBytesCache_Expecter) MGet(ctx interface{}, keys interface{}) *MockBytesCache_MGet_Call {
+	return &MockBytesCache_MGet_Call{Call: _e.mock.On("MGet", ctx, keys)}
+}
+
+func (_c *MockBytesCache_MGet_Call) Run(run func(ctx context.Context, keys []string)) *MockBytesCache_MGet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockBytesCache_MGet_Call) Return(_a0 [][]byte, _a1 error) *MockBytesCache_MGet_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockBytesCache_MGet_Call) RunAndReturn(run func(context.Context, []string) ([][]byte, error)) *MockBytesCache_MGet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MSet provides a mock function with given fields: ctx, items
+func (_m *MockBytesCache) MSet(ctx context.Context, items []cache.Item) error {
+	ret := _m.Called(ctx, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MSet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []cache.Item) error); ok {
+		r0 = rf(ctx, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockBytesCache_MSet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MSet'
+type MockBytesCache_MSet_Call struct {
+	*mock.Call
+}
+
+// MSet is a helper method to define mock.On call
+//   - ctx context.Context
+//   - items []cache.Item
+func (_e *MockBytesCache_Expecter) MSet(ctx interface{}, items interface{}) *MockBytesCache_MSet_Call {
+	return &MockBytesCache_MSet_Call{Call: _e.mock.On("MSet", ctx, items)}
+}
+
+func (_c *MockBytesCache_MSet_Call) Run(run func(ctx context.Context, items []cache.Item)) *MockBytesCache_MSet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]cache.Item))
+	})
+	return _c
+}
+
+func (_c *MockBytesCache_MSet_Call) Return(_a0 error) *MockBytesCache_MSet_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockBytesCache_MSet_Call) RunAndReturn(run func(context.Context, []cache.Item) error) *MockBytesCache_MSet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Set provides a mock function with given fields: ctx, key, value, ttl
 func (_m *MockBytesCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
 	ret := _m.Called(ctx, key, value, ttl)
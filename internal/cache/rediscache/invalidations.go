@@ -0,0 +1,80 @@
+package rediscache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// Invalidations — сообщения «эти ключи изменились» между репликами через Redis pub/sub: по ним реплики
+// сбрасывают свой кэш в памяти. Собственные сообщения реплика пропускает (поле src).
+// Pub/sub не хранит сообщения: всё, что опубликовано, пока подписчик не подключён, теряется.
+type Invalidations struct {
+	c       *redis.Client
+	channel string
+	origin  string
+}
+
+type invalidation struct {
+	Src  string   `json:"src"`
+	Keys []string `json:"keys"`
+}
+
+func NewInvalidations(addr, channel string) *Invalidations {
+	return &Invalidations{
+		c:       redis.NewClient(&redis.Options{Addr: addr}),
+		channel: channel,
+		origin:  rand.Text(),
+	}
+}
+
+// Publish сообщает остальным репликам об изменившихся ключах.
+func (i *Invalidations) Publish(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(invalidation{Src: i.origin, Keys: keys})
+	if err := i.c.Publish(ctx, i.channel, b).Err(); err != nil {
+		return errors.Wrap(err, "redis publish")
+	}
+	return nil
+}
+
+// Subscribe слушает канал до отмены ctx (тогда возвращает nil) или до ошибки соединения.
+// drop получает ключи, изменённые другими репликами; reset вызывается после подписки — сообщения,
+// пришедшие до неё, потеряны, и кэш в памяти нужно считать устаревшим целиком.
+func (i *Invalidations) Subscribe(ctx context.Context, drop func(keys []string), reset func()) error {
+	ps := i.c.Subscribe(ctx, i.channel)
+	defer func() { _ = ps.Close() }()
+	// Receive не прерывается отменой ctx, пока ждёт сообщение, — прерываем закрытием подписки.
+	stop := context.AfterFunc(ctx, func() { _ = ps.Close() })
+	defer stop()
+	for {
+		msg, err := ps.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrap(err, "redis subscribe")
+		}
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind == "subscribe" {
+				reset()
+			}
+		case *redis.Message:
+			var inv invalidation
+			if json.Unmarshal([]byte(m.Payload), &inv) != nil || inv.Src == i.origin {
+				continue
+			}
+			drop(inv.Keys)
+		}
+	}
+}
+
+func (i *Invalidations) Close() error {
+	return i.c.Close()
+}
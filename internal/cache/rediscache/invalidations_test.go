@@ -0,0 +1,45 @@
+package rediscache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidations_PublishSubscribe(t *testing.T) {
+	mr := miniredis.RunT(t)
+	a := NewInvalidations(mr.Addr(), "inv")
+	b := NewInvalidations(mr.Addr(), "inv")
+	defer a.Close()
+	defer b.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dropped := make(chan []string, 10)
+	resets := make(chan struct{}, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Subscribe(ctx, func(keys []string) { dropped <- keys }, func() { resets <- struct{}{} })
+	}()
+
+	select {
+	case <-resets:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no subscription")
+	}
+
+	require.NoError(t, b.Publish(ctx, "own")) // своё сообщение подписчик пропускает
+	require.NoError(t, a.Publish(ctx, "k1", "k2"))
+	select {
+	case keys := <-dropped:
+		require.Equal(t, []string{"k1", "k2"}, keys)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no invalidation")
+	}
+	require.Empty(t, dropped)
+
+	cancel()
+	require.NoError(t, <-done)
+}
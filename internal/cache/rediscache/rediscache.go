@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/BearBump/TrackBox/internal/cache"
 	"github.com/pkg/errors"
 	"github.com/redis/go-redis/v9"
 )
@@ -31,6 +32,24 @@ func (r *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error)
 	return val, true, nil
 }
 
+// MGet — один MGET на все ключи.
+func (r *RedisCache) MGet(ctx context.Context, keys []string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	vals, err := r.c.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "redis mget")
+	}
+	out := make([][]byte, len(keys))
+	for i, v := range vals {
+		if s, ok := v.(string); ok {
+			out[i] = []byte(s)
+		}
+	}
+	return out, nil
+}
+
 func (r *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
 	if err := r.c.Set(ctx, key, value, ttl).Err(); err != nil {
 		return errors.Wrap(err, "redis set")
@@ -38,4 +57,29 @@ func (r *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time
 	return nil
 }
 
+// MSet — SET с TTL на каждый ключ в одном пайплайне (у MSET самого Redis нет TTL).
+func (r *RedisCache) MSet(ctx context.Context, items []cache.Item) error {
+	if len(items) == 0 {
+		return nil
+	}
+	pipe := r.c.Pipeline()
+	for _, it := range items {
+		pipe.Set(ctx, it.Key, it.Value, it.TTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errors.Wrap(err, "redis mset")
+	}
+	return nil
+}
+
+func (r *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := r.c.Del(ctx, keys...).Err(); err != nil {
+		return errors.Wrap(err, "redis del")
+	}
+	return nil
+}
+
 
@@ -2,9 +2,11 @@ package rediscache
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/BearBump/TrackBox/internal/cache"
 	miniredis "github.com/alicebob/miniredis/v2"
 	"github.com/stretchr/testify/require"
 )
@@ -20,6 +22,100 @@ func TestRedisCache_GetSet(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, ok)
 	require.Equal(t, []byte("v"), b)
+
+	require.NoError(t, c.Delete(ctx, "k"))
+	_, ok, err = c.Get(ctx, "k")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestRedisCache_MGetMSet(t *testing.T) {
+	mr := miniredis.RunT(t)
+	c := New(mr.Addr())
+
+	ctx := context.Background()
+	require.NoError(t, c.MSet(ctx, []cache.Item{
+		{Key: "a", Value: []byte("1"), TTL: time.Minute},
+		{Key: "b", Value: []byte("2"), TTL: time.Second},
+	}))
+	require.Equal(t, time.Second, mr.TTL("b"))
+
+	vals, err := c.MGet(ctx, []string{"a", "missing", "b"})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("1"), nil, []byte("2")}, vals)
+
+	require.NoError(t, c.Delete(ctx, "a", "b"))
+	vals, err = c.MGet(ctx, []string{"a", "b"})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{nil, nil}, vals)
+
+	// пустые вызовы в Redis не ходят
+	vals, err = c.MGet(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, vals)
+	require.NoError(t, c.MSet(ctx, nil))
+	require.NoError(t, c.Delete(ctx))
+}
+
+// Чтение 100 ключей: по одному GET (как раньше читал GetTrackingsByIDs) против одного MGET.
+func BenchmarkRedisCache_Read100(b *testing.B) {
+	mr := miniredis.RunT(b)
+	c := New(mr.Addr())
+	ctx := context.Background()
+
+	keys := make([]string, 100)
+	items := make([]cache.Item, len(keys))
+	for i := range keys {
+		keys[i] = "tracking:" + strconv.Itoa(i) + ":current"
+		items[i] = cache.Item{Key: keys[i], Value: make([]byte, 512), TTL: time.Minute}
+	}
+	require.NoError(b, c.MSet(ctx, items))
+
+	b.Run("get-per-key", func(b *testing.B) {
+		for b.Loop() {
+			for _, k := range keys {
+				if _, _, err := c.Get(ctx, k); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("mget", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := c.MGet(ctx, keys); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// Запись 100 ключей: по одному SET против пайплайна MSet.
+func BenchmarkRedisCache_Write100(b *testing.B) {
+	mr := miniredis.RunT(b)
+	c := New(mr.Addr())
+	ctx := context.Background()
+
+	items := make([]cache.Item, 100)
+	for i := range items {
+		items[i] = cache.Item{Key: "tracking:" + strconv.Itoa(i) + ":current", Value: make([]byte, 512), TTL: time.Minute}
+	}
+
+	b.Run("set-per-key", func(b *testing.B) {
+		for b.Loop() {
+			for _, it := range items {
+				if err := c.Set(ctx, it.Key, it.Value, it.TTL); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("mset-pipeline", func(b *testing.B) {
+		for b.Loop() {
+			if err := c.MSet(ctx, items); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
 }
 
 func TestRateLimiter_Allow(t *testing.T) {
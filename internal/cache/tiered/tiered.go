@@ -0,0 +1,134 @@
+// Package tiered — двухуровневый кэш: память процесса (L1) перед общим кэшем (L2, Redis).
+package tiered
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/cache"
+	"github.com/BearBump/TrackBox/internal/cache/memcache"
+)
+
+// Cache читает сначала из L1, промахи — из L2 с заполнением L1; пишет и удаляет в обоих уровнях.
+//
+// L1 у каждой реплики свой: об изменениях на других репликах узнаём через Invalidate/InvalidateAll
+// (их вызывает подписка на Redis pub/sub, см. rediscache.Invalidations). Чтобы значение, прочитанное из L2
+// до сброса, не легло в L1 уже после него, заполнение L1 пропускается, если за время чтения был сброс.
+type Cache struct {
+	l1 *memcache.Cache
+	l2 cache.BytesCache
+
+	mu    sync.Mutex // упорядочивает заполнение L1 и сбросы
+	epoch uint64     // растёт при каждом сбросе
+}
+
+func New(l1 *memcache.Cache, l2 cache.BytesCache) *Cache {
+	return &Cache{l1: l1, l2: l2}
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if v, ok, _ := c.l1.Get(ctx, key); ok {
+		return v, true, nil
+	}
+	epoch := c.currentEpoch()
+	v, ok, err := c.l2.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	c.fill(ctx, epoch, []cache.Item{{Key: key, Value: v}})
+	return v, true, nil
+}
+
+func (c *Cache) MGet(ctx context.Context, keys []string) ([][]byte, error) {
+	out, _ := c.l1.MGet(ctx, keys)
+	var missKeys []string
+	var missPos []int
+	for i, v := range out {
+		if v == nil {
+			missKeys = append(missKeys, keys[i])
+			missPos = append(missPos, i)
+		}
+	}
+	if len(missKeys) == 0 {
+		return out, nil
+	}
+
+	epoch := c.currentEpoch()
+	vals, err := c.l2.MGet(ctx, missKeys)
+	if err != nil {
+		return nil, err
+	}
+	fill := make([]cache.Item, 0, len(vals))
+	for j, v := range vals {
+		if v != nil {
+			out[missPos[j]] = v
+			fill = append(fill, cache.Item{Key: missKeys[j], Value: v})
+		}
+	}
+	c.fill(ctx, epoch, fill)
+	return out, nil
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.MSet(ctx, []cache.Item{{Key: key, Value: value, TTL: ttl}})
+}
+
+// MSet пишет в L2, затем в L1; если L2 не принял запись, ключи убираются и из L1.
+func (c *Cache) MSet(ctx context.Context, items []cache.Item) error {
+	if err := c.l2.MSet(ctx, items); err != nil {
+		keys := make([]string, len(items))
+		for i, it := range items {
+			keys[i] = it.Key
+		}
+		c.Invalidate(keys...)
+		return err
+	}
+	// сдвигаем epoch и здесь: параллельное чтение, взявшее из L2 прежнее значение, не затрёт новое в L1
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.epoch++
+	return c.l1.MSet(ctx, items)
+}
+
+// Delete удаляет из L2, затем из L1: в обратном порядке чтение между ними вернуло бы в L1 старое значение.
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	err := c.l2.Delete(ctx, keys...)
+	c.Invalidate(keys...)
+	return err
+}
+
+// Invalidate убирает ключи из L1 (L2 не трогает).
+func (c *Cache) Invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.epoch++
+	_ = c.l1.Delete(context.Background(), keys...)
+}
+
+// InvalidateAll очищает L1 — например, после переподключения к pub/sub, когда сообщения могли потеряться.
+func (c *Cache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.epoch++
+	c.l1.Purge()
+}
+
+func (c *Cache) currentEpoch() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.epoch
+}
+
+// fill кладёт прочитанное из L2 в L1 (TTL — предельный у L1), если с начала чтения не было сбросов.
+func (c *Cache) fill(ctx context.Context, epoch uint64, items []cache.Item) {
+	if len(items) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.epoch != epoch {
+		return
+	}
+	_ = c.l1.MSet(ctx, items)
+}
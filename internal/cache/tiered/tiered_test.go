@@ -0,0 +1,126 @@
+package tiered
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/cache"
+	"github.com/BearBump/TrackBox/internal/cache/memcache"
+	"github.com/BearBump/TrackBox/internal/cache/rediscache"
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func newTiered(t testing.TB) (*Cache, *memcache.Cache, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	l1 := memcache.New(100, time.Minute)
+	return New(l1, rediscache.New(mr.Addr())), l1, mr
+}
+
+func TestCache_ReadThroughL1(t *testing.T) {
+	c, l1, mr := newTiered(t)
+	ctx := context.Background()
+
+	require.NoError(t, mr.Set("a", "1"))
+	require.NoError(t, mr.Set("b", "2"))
+
+	vals, err := c.MGet(ctx, []string{"a", "b", "missing"})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("1"), []byte("2"), nil}, vals)
+	require.Equal(t, 2, l1.Len())
+
+	// второе чтение — из памяти, даже если Redis уже поменялся
+	require.NoError(t, mr.Set("a", "changed"))
+	v, ok, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("1"), v)
+
+	// сброс с другой реплики
+	c.Invalidate("a")
+	v, _, _ = c.Get(ctx, "a")
+	require.Equal(t, []byte("changed"), v)
+
+	c.InvalidateAll()
+	require.Equal(t, 0, l1.Len())
+}
+
+func TestCache_WritesBothLevels(t *testing.T) {
+	c, l1, mr := newTiered(t)
+	ctx := context.Background()
+
+	require.NoError(t, c.MSet(ctx, []cache.Item{{Key: "a", Value: []byte("1"), TTL: time.Hour}}))
+	got, err := mr.Get("a")
+	require.NoError(t, err)
+	require.Equal(t, "1", got)
+	v, ok, _ := l1.Get(ctx, "a")
+	require.True(t, ok)
+	require.Equal(t, []byte("1"), v)
+
+	require.NoError(t, c.Delete(ctx, "a"))
+	require.False(t, mr.Exists("a"))
+	_, ok, _ = l1.Get(ctx, "a")
+	require.False(t, ok)
+
+	// Redis не принял запись — в памяти её тоже нет
+	require.NoError(t, c.Set(ctx, "b", []byte("old"), time.Hour))
+	mr.SetError("down")
+	require.Error(t, c.Set(ctx, "b", []byte("new"), time.Hour))
+	_, ok, _ = l1.Get(ctx, "b")
+	require.False(t, ok)
+}
+
+func TestCache_FillSkippedAfterInvalidation(t *testing.T) {
+	l1 := memcache.New(10, time.Minute)
+	l2 := &invalidatingCache{}
+	c := New(l1, l2)
+	l2.onRead = func() { c.Invalidate("a") } // сброс пришёл, пока читали L2
+
+	v, ok, err := c.Get(context.Background(), "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, []byte("old"), v)
+	require.Equal(t, 0, l1.Len())
+}
+
+// invalidatingCache — L2, который отдаёт "old" и во время чтения вызывает onRead.
+type invalidatingCache struct {
+	cache.BytesCache
+	onRead func()
+}
+
+func (c *invalidatingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.onRead()
+	return []byte("old"), true, nil
+}
+
+// Чтение 100 горячих ключей: только Redis против памяти перед Redis.
+func BenchmarkCache_Read100(b *testing.B) {
+	c, _, mr := newTiered(b)
+	ctx := context.Background()
+	l2 := rediscache.New(mr.Addr())
+
+	keys := make([]string, 100)
+	items := make([]cache.Item, len(keys))
+	for i := range keys {
+		keys[i] = "tracking:" + string(rune('a'+i%26)) + string(rune('a'+i/26)) + ":current"
+		items[i] = cache.Item{Key: keys[i], Value: make([]byte, 512), TTL: time.Minute}
+	}
+	require.NoError(b, c.MSet(ctx, items))
+
+	b.Run("redis", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := l2.MGet(ctx, keys); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("l1+redis", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := c.MGet(ctx, keys); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,254 @@
+// Package geo — офлайн-геокодинг мест событий: по почтовому индексу или названию города
+// находит страну, регион, координаты и часовой пояс. Справочник встроен в бинарник (data/ru_places.csv),
+// внешних запросов нет.
+package geo
+
+import (
+	"embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // пояса справочника не должны зависеть от tzdata в образе
+	"unicode"
+
+	"github.com/BearBump/TrackBox/internal/models"
+)
+
+//go:embed data/ru_places.csv
+var dataFS embed.FS
+
+// maxNameWords — самое длинное название в справочнике в словах ("Saint Petersburg", "Нижний Новгород").
+const maxNameWords = 3
+
+var postalCodeRe = regexp.MustCompile(`(?:^|\D)(\d{6})(?:\D|$)`)
+
+type place struct {
+	country  string
+	region   string
+	city     string
+	lat, lon *float64
+	zone     *time.Location
+}
+
+// Dataset — справочник мест; после загрузки только читается, безопасен для конкурентного использования.
+type Dataset struct {
+	byPrefix map[string]*place // первые 3 цифры индекса
+	byName   map[string]*place // normName(название города)
+	byRegion map[string]*place // normName(регион) — первая строка региона, для часового пояса
+}
+
+var defaultDataset = sync.OnceValue(func() *Dataset {
+	f, err := dataFS.Open("data/ru_places.csv")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	d, err := Parse(f)
+	if err != nil {
+		panic("geo: embedded dataset: " + err.Error())
+	}
+	return d
+})
+
+// Default — встроенный справочник.
+func Default() *Dataset {
+	return defaultDataset()
+}
+
+// Parse читает справочник в формате data/ru_places.csv
+// (prefixes,country,region,city,lat,lon,tz,names; строки с '#' — комментарии).
+func Parse(r io.Reader) (*Dataset, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = 8
+
+	d := &Dataset{byPrefix: make(map[string]*place), byName: make(map[string]*place), byRegion: make(map[string]*place)}
+	header := true
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header {
+			header = false
+			continue
+		}
+		line, _ := cr.FieldPos(0)
+		p, err := parsePlace(rec)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		prefixes, err := expandPrefixes(rec[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		for _, pre := range prefixes {
+			if _, dup := d.byPrefix[pre]; dup {
+				return nil, fmt.Errorf("line %d: postal prefix %s already defined", line, pre)
+			}
+			d.byPrefix[pre] = p
+		}
+		if _, ok := d.byRegion[normName(p.region)]; !ok {
+			d.byRegion[normName(p.region)] = p
+		}
+		for _, name := range strings.Split(rec[7], "|") {
+			key := normName(name)
+			if key == "" {
+				continue
+			}
+			if _, dup := d.byName[key]; dup {
+				return nil, fmt.Errorf("line %d: name %q already defined", line, name)
+			}
+			d.byName[key] = p
+		}
+	}
+	return d, nil
+}
+
+func parsePlace(rec []string) (*place, error) {
+	p := &place{
+		country: strings.ToUpper(strings.TrimSpace(rec[1])),
+		region:  strings.TrimSpace(rec[2]),
+		city:    strings.TrimSpace(rec[3]),
+	}
+	if p.country == "" || p.region == "" {
+		return nil, fmt.Errorf("country and region are required")
+	}
+	zone, err := time.LoadLocation(strings.TrimSpace(rec[6]))
+	if err != nil || rec[6] == "" {
+		return nil, fmt.Errorf("bad tz %q", rec[6])
+	}
+	p.zone = zone
+	if rec[4] == "" && rec[5] == "" {
+		return p, nil
+	}
+	lat, err := strconv.ParseFloat(rec[4], 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("bad lat %q", rec[4])
+	}
+	lon, err := strconv.ParseFloat(rec[5], 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return nil, fmt.Errorf("bad lon %q", rec[5])
+	}
+	p.lat, p.lon = &lat, &lon
+	return p, nil
+}
+
+// expandPrefixes: "420" -> [420], "101-103" -> [101 102 103].
+func expandPrefixes(s string) ([]string, error) {
+	from, to, isRange := strings.Cut(strings.TrimSpace(s), "-")
+	if !isRange {
+		to = from
+	}
+	a, errA := strconv.Atoi(from)
+	b, errB := strconv.Atoi(to)
+	if errA != nil || errB != nil || len(from) != 3 || len(to) != 3 || a > b {
+		return nil, fmt.Errorf("bad postal prefixes %q", s)
+	}
+	out := make([]string, 0, b-a+1)
+	for i := a; i <= b; i++ {
+		out = append(out, fmt.Sprintf("%03d", i))
+	}
+	return out, nil
+}
+
+// Resolve дополняет место события по справочнику. loc — что отдал перевозчик (может быть nil),
+// text — место свободным текстом (TrackingEvent.Location). Заданные перевозчиком поля не перезаписываются.
+// Порядок поиска: почтовый индекс (из loc или из текста), город из loc, название города в тексте.
+// nil — о месте ничего не известно.
+func (d *Dataset) Resolve(loc *models.Location, text string) *models.Location {
+	var out models.Location
+	if loc != nil {
+		out = *loc
+	}
+	if out.PostalCode == "" {
+		if m := postalCodeRe.FindStringSubmatch(text); m != nil {
+			out.PostalCode = m[1]
+		}
+	}
+
+	var p *place
+	if len(out.PostalCode) == 6 {
+		p = d.byPrefix[out.PostalCode[:3]]
+	}
+	if p == nil && out.City != "" {
+		p = d.byName[normName(out.City)]
+	}
+	if p == nil && out.City == "" {
+		p = d.matchText(text)
+	}
+	if p != nil && (out.Country == "" || out.Country == p.country) {
+		d.fill(&out, p)
+	}
+
+	if out.IsEmpty() {
+		return nil
+	}
+	return &out
+}
+
+func (d *Dataset) fill(out *models.Location, p *place) {
+	out.Country = p.country
+	if out.Region == "" {
+		out.Region = p.region
+	}
+	if out.City == "" {
+		out.City = p.city
+	}
+	// Координаты города ставим, только если событие действительно в нём, а не где-то в области
+	// (город перевозчика может быть записан любым из написаний справочника).
+	if out.Lat == nil && out.Lon == nil && p.lat != nil && d.byName[normName(out.City)] == p {
+		lat, lon := *p.lat, *p.lon
+		out.Lat, out.Lon = &lat, &lon
+	}
+}
+
+// TimeZone — часовой пояс места по справочнику: по индексу, городу или региону; nil — неизвестен.
+func (d *Dataset) TimeZone(loc *models.Location) *time.Location {
+	if loc == nil {
+		return nil
+	}
+	var p *place
+	if len(loc.PostalCode) == 6 {
+		p = d.byPrefix[loc.PostalCode[:3]]
+	}
+	if p == nil && loc.City != "" {
+		p = d.byName[normName(loc.City)]
+	}
+	if p == nil && loc.Region != "" {
+		p = d.byRegion[normName(loc.Region)]
+	}
+	if p == nil || (loc.Country != "" && loc.Country != p.country) {
+		return nil
+	}
+	return p.zone
+}
+
+// matchText ищет название города среди слов текста: сначала более длинные сочетания, слева направо.
+func (d *Dataset) matchText(text string) *place {
+	words := strings.FieldsFunc(normName(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+	for n := min(maxNameWords, len(words)); n > 0; n-- {
+		for i := 0; i+n <= len(words); i++ {
+			if p, ok := d.byName[strings.Join(words[i:i+n], " ")]; ok {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+func normName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, "ё", "е")
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,84 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	d := Default()
+
+	tests := []struct {
+		name       string
+		loc        *models.Location
+		text       string
+		wantRegion string
+		wantCity   string
+		wantPostal string
+		wantCoords bool
+		wantNil    bool
+	}{
+		{name: "postal code", loc: &models.Location{PostalCode: "420012"}, wantRegion: "Республика Татарстан", wantCity: "Казань", wantPostal: "420012", wantCoords: true},
+		{name: "postal code in text", text: "Казань МСЦ 420300", wantRegion: "Республика Татарстан", wantCity: "Казань", wantPostal: "420300", wantCoords: true},
+		{name: "region-only prefix", loc: &models.Location{PostalCode: "422540", City: "Зеленодольск"}, wantRegion: "Республика Татарстан", wantCity: "Зеленодольск", wantPostal: "422540"},
+		{name: "city from carrier", loc: &models.Location{City: "moscow"}, wantRegion: "Москва", wantCity: "moscow", wantCoords: true},
+		{name: "city in text", text: "Сортировочный центр, г. Нижний Новгород", wantRegion: "Нижегородская область", wantCity: "Нижний Новгород", wantCoords: true},
+		{name: "yo and case", text: "САНКТ-ПЕТЕРБУРГ", wantRegion: "Санкт-Петербург", wantCity: "Санкт-Петербург", wantCoords: true},
+		{name: "unknown", text: "Sorting center", wantNil: true},
+		{name: "foreign country kept", loc: &models.Location{Country: "KZ", City: "Казань"}, wantCity: "Казань"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := d.Resolve(tt.loc, tt.text)
+			if tt.wantNil {
+				require.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			require.Equal(t, tt.wantRegion, got.Region)
+			require.Equal(t, tt.wantCity, got.City)
+			require.Equal(t, tt.wantPostal, got.PostalCode)
+			require.Equal(t, tt.wantCoords, got.Lat != nil && got.Lon != nil)
+		})
+	}
+}
+
+func TestResolve_KeepsCarrierFields(t *testing.T) {
+	lat, lon := 1.5, 2.5
+	got := Default().Resolve(&models.Location{Region: "Татарстан", PostalCode: "420000", Lat: &lat, Lon: &lon}, "")
+	require.Equal(t, "RU", got.Country)
+	require.Equal(t, "Татарстан", got.Region)
+	require.Equal(t, "Казань", got.City)
+	require.Equal(t, 1.5, *got.Lat)
+	require.Equal(t, 2.5, *got.Lon)
+}
+
+func TestParse_Errors(t *testing.T) {
+	const header = "prefixes,country,region,city,lat,lon,tz,names\n"
+	for name, body := range map[string]string{
+		"dup prefix": "101-102,RU,A,A,1,1,UTC,A\n102,RU,B,B,1,1,UTC,B\n",
+		"dup name":   "101,RU,A,A,1,1,UTC,X\n102,RU,B,B,1,1,UTC,x\n",
+		"bad range":  "12-13,RU,A,A,1,1,UTC,A\n",
+		"bad lat":    "101,RU,A,A,91,1,UTC,A\n",
+		"no region":  "101,RU,,A,1,1,UTC,A\n",
+		"bad tz":     "101,RU,A,A,1,1,Mars/Olympus,A\n",
+		"no tz":      "101,RU,A,A,1,1,,A\n",
+	} {
+		_, err := Parse(strings.NewReader(header + body))
+		require.Error(t, err, name)
+	}
+}
+
+func TestTimeZone(t *testing.T) {
+	d := Default()
+	require.Equal(t, "Asia/Yekaterinburg", d.TimeZone(&models.Location{PostalCode: "620014"}).String())
+	require.Equal(t, "Asia/Vladivostok", d.TimeZone(&models.Location{City: "Владивосток"}).String())
+	require.Equal(t, "Europe/Samara", d.TimeZone(&models.Location{Region: "самарская область"}).String())
+	require.Equal(t, "Europe/Moscow", d.TimeZone(&models.Location{PostalCode: "422540"}).String())
+	require.Nil(t, d.TimeZone(&models.Location{City: "Berlin"}))
+	require.Nil(t, d.TimeZone(&models.Location{Country: "KZ", City: "Казань"}))
+	require.Nil(t, d.TimeZone(nil))
+}
@@ -12,10 +12,26 @@ type TrackingResult struct {
 	StatusRaw string
 	StatusAt  *time.Time
 	Events    []*models.TrackingEvent
+
+	// Shipment — сведения об отправлении (ETA, вес, откуда/куда, тариф), если перевозчик их отдаёт;
+	// ETA заодно подсказка для планировщика.
+	Shipment *models.ShipmentDetails
+
+	// Raw — тело ответа перевозчика как есть, HTTPStatus — код ответа (журнал проверок, архив ответов);
+	// у клиентов не по HTTP — nil и 0.
+	Raw        []byte
+	HTTPStatus int
 }
 
+// Client ходит к перевозчику. Ошибки по возможности — *Error с классом (см. Classify).
+// Если ответ получен, но не разобрался (DECODE, CARRIER), вместе с ошибкой возвращаются Raw и HTTPStatus —
+// такой ответ тоже попадает в архив и может быть разобран заново (trackbox reparse).
 type Client interface {
 	GetTracking(ctx context.Context, carrierCode, trackNumber string) (TrackingResult, error)
 }
 
+// Parser разбирает тело ответа бэкенда без запроса к нему (повторный разбор архивных ответов).
+// receivedAt — когда ответ получен: время статуса для перевозчиков, которые его не присылают.
+type Parser func(body []byte, receivedAt time.Time) (TrackingResult, error)
+
 
@@ -39,6 +39,16 @@ type respEvent struct {
 	Location  *string    `json:"location,omitempty"`
 	Message   *string    `json:"message,omitempty"`
 	Payload   any        `json:"payload,omitempty"`
+	Place     *respPlace `json:"place,omitempty"`
+}
+
+type respPlace struct {
+	Country    string   `json:"country,omitempty"`
+	Region     string   `json:"region,omitempty"`
+	City       string   `json:"city,omitempty"`
+	PostalCode string   `json:"postal_code,omitempty"`
+	Lat        *float64 `json:"lat,omitempty"`
+	Lon        *float64 `json:"lon,omitempty"`
 }
 
 type respBody struct {
@@ -49,8 +59,18 @@ type respBody struct {
 	StatusRaw   string      `json:"status_raw"`
 	StatusAt    time.Time   `json:"status_at"`
 	Events      []respEvent `json:"events"`
+
+	EstimatedDelivery *time.Time `json:"estimated_delivery,omitempty"`
+	WeightGrams       int32      `json:"weight_grams,omitempty"`
+	Origin            string     `json:"origin,omitempty"`
+	Destination       string     `json:"destination,omitempty"`
+	RecipientCity     string     `json:"recipient_city,omitempty"`
+	ServiceType       string     `json:"service_type,omitempty"`
 }
 
+// Backend — для журнала проверок (carrier.BackendOf).
+func (c *Client) Backend() string { return models.CarrierBackendEmulatorV1 }
+
 func (c *Client) GetTracking(ctx context.Context, carrierCode, trackNumber string) (carrier.TrackingResult, error) {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
@@ -75,15 +95,26 @@ func (c *Client) GetTracking(ctx context.Context, carrierCode, trackNumber strin
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 429 {
-		return carrier.TrackingResult{}, fmt.Errorf("carrier emulator rate limit (429)")
+		return carrier.TrackingResult{}, carrier.NewError(carrier.ErrorClassRateLimited, resp.StatusCode, fmt.Errorf("carrier emulator rate limit (429)"))
 	}
 	if resp.StatusCode/100 != 2 {
-		return carrier.TrackingResult{}, fmt.Errorf("carrier emulator http %d", resp.StatusCode)
+		return carrier.TrackingResult{}, carrier.NewError(carrier.ErrorClassHTTP, resp.StatusCode, fmt.Errorf("carrier emulator http %d", resp.StatusCode))
+	}
+
+	body, err := carrier.ReadBody(resp.Body, resp.StatusCode)
+	if err != nil {
+		return carrier.TrackingResult{}, err
 	}
+	res, err := Parse(body, time.Now().UTC())
+	res.Raw, res.HTTPStatus = body, resp.StatusCode
+	return res, carrier.WithHTTPStatus(err, resp.StatusCode)
+}
 
+// Parse разбирает тело ответа /v1/tracking (carrier.Parser); receivedAt — время статуса, если его нет в ответе.
+func Parse(body []byte, receivedAt time.Time) (carrier.TrackingResult, error) {
 	var rb respBody
-	if err := json.NewDecoder(resp.Body).Decode(&rb); err != nil {
-		return carrier.TrackingResult{}, errors.Wrap(err, "decode")
+	if err := json.Unmarshal(body, &rb); err != nil {
+		return carrier.TrackingResult{}, carrier.NewError(carrier.ErrorClassDecode, 0, errors.Wrap(err, "decode"))
 	}
 
 	status := rb.Status
@@ -93,22 +124,54 @@ func (c *Client) GetTracking(ctx context.Context, carrierCode, trackNumber strin
 
 	var evs []*models.TrackingEvent
 	for _, e := range rb.Events {
-		evs = append(evs, &models.TrackingEvent{
+		ev := &models.TrackingEvent{
 			Status:    e.Status,
 			StatusRaw: e.StatusRaw,
 			EventTime: e.EventTime,
 			Location:  e.Location,
 			Message:   e.Message,
-		})
+		}
+		if e.Payload != nil {
+			b, err := json.Marshal(e.Payload)
+			if err != nil {
+				return carrier.TrackingResult{}, carrier.NewError(carrier.ErrorClassDecode, 0, errors.Wrap(err, "encode event payload"))
+			}
+			payload := string(b)
+			ev.PayloadJSON = &payload
+		}
+		if pl := e.Place; pl != nil {
+			ev.Place = &models.Location{
+				Country:    pl.Country,
+				Region:     pl.Region,
+				City:       pl.City,
+				PostalCode: pl.PostalCode,
+				Lat:        pl.Lat,
+				Lon:        pl.Lon,
+			}
+		}
+		evs = append(evs, ev)
 	}
 
 	statusAt := rb.StatusAt
-	return carrier.TrackingResult{
+	if statusAt.IsZero() {
+		statusAt = receivedAt
+	}
+	res := carrier.TrackingResult{
 		Status:    status,
 		StatusRaw: rb.StatusRaw,
 		StatusAt:  &statusAt,
 		Events:    evs,
-	}, nil
+	}
+	sd := &models.ShipmentDetails{
+		EstimatedDelivery: rb.EstimatedDelivery,
+		WeightGrams:       rb.WeightGrams,
+		Origin:            rb.Origin,
+		Destination:       rb.Destination,
+		RecipientCity:     rb.RecipientCity,
+		ServiceType:       rb.ServiceType,
+	}
+	if !sd.IsEmpty() {
+		res.Shipment = sd
+	}
+	return res, nil
 }
-
-
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/BearBump/TrackBox/internal/integrations/carrier"
 	"github.com/stretchr/testify/require"
 )
 
@@ -20,7 +21,14 @@ func TestClient_GetTracking_OK(t *testing.T) {
   "status": "IN_TRANSIT",
   "status_raw": "raw",
   "status_at": "2025-01-01T00:00:00Z",
-  "events": [{"status":"IN_TRANSIT","status_raw":"raw","event_time":"2025-01-01T00:00:00Z"}]
+  "events": [{"status":"IN_TRANSIT","status_raw":"raw","event_time":"2025-01-01T00:00:00Z",
+              "place":{"country":"RU","city":"Moscow","postal_code":"101000","lat":55.75}}],
+  "estimated_delivery": "2025-01-03T12:00:00Z",
+  "weight_grams": 1250,
+  "origin": "Moscow",
+  "destination": "Kazan",
+  "recipient_city": "Kazan",
+  "service_type": "door-to-door"
 }`))
 	}))
 	defer srv.Close()
@@ -32,6 +40,23 @@ func TestClient_GetTracking_OK(t *testing.T) {
 	require.NotNil(t, res.StatusAt)
 	require.WithinDuration(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *res.StatusAt, time.Second)
 	require.Len(t, res.Events, 1)
+	pl := res.Events[0].Place
+	require.NotNil(t, pl)
+	require.Equal(t, "RU", pl.Country)
+	require.Equal(t, "Moscow", pl.City)
+	require.Equal(t, "101000", pl.PostalCode)
+	require.Equal(t, 55.75, *pl.Lat)
+	require.Nil(t, pl.Lon)
+	require.NotNil(t, res.Shipment)
+	require.NotNil(t, res.Shipment.EstimatedDelivery)
+	require.WithinDuration(t, time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC), *res.Shipment.EstimatedDelivery, time.Second)
+	require.EqualValues(t, 1250, res.Shipment.WeightGrams)
+	require.Equal(t, "Moscow", res.Shipment.Origin)
+	require.Equal(t, "Kazan", res.Shipment.Destination)
+	require.Equal(t, "Kazan", res.Shipment.RecipientCity)
+	require.Equal(t, "door-to-door", res.Shipment.ServiceType)
+	require.Equal(t, http.StatusOK, res.HTTPStatus)
+	require.Contains(t, string(res.Raw), `"track_number": "123"`)
 }
 
 func TestClient_GetTracking_429(t *testing.T) {
@@ -43,6 +68,38 @@ func TestClient_GetTracking_429(t *testing.T) {
 	c := New(srv.URL, "k")
 	_, err := c.GetTracking(context.Background(), "CDEK", "123")
 	require.Error(t, err)
+	class, code := carrier.Classify(err)
+	require.Equal(t, carrier.ErrorClassRateLimited, class)
+	require.Equal(t, 429, code)
 }
 
 
+
+func TestParse_Payload(t *testing.T) {
+	received := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	res, err := Parse([]byte(`{"status":"IN_TRANSIT","events":[
+  {"status":"IN_TRANSIT","status_raw":"raw","event_time":"2025-01-01T00:00:00Z","payload":{"office":"MSK-1","weight":1.5}},
+  {"status":"IN_TRANSIT","status_raw":"raw2","event_time":"2025-01-01T01:00:00Z"}
+]}`), received)
+	require.NoError(t, err)
+	require.Equal(t, received, *res.StatusAt)
+	require.Len(t, res.Events, 2)
+	require.NotNil(t, res.Events[0].PayloadJSON)
+	require.JSONEq(t, `{"office":"MSK-1","weight":1.5}`, *res.Events[0].PayloadJSON)
+	require.Nil(t, res.Events[1].PayloadJSON)
+}
+
+func TestClient_GetTracking_DecodeErrorKeepsBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status": [`))
+	}))
+	defer srv.Close()
+
+	res, err := New(srv.URL, "k").GetTracking(context.Background(), "CDEK", "123")
+	require.Error(t, err)
+	class, code := carrier.Classify(err)
+	require.Equal(t, carrier.ErrorClassDecode, class)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, `{"status": [`, string(res.Raw))
+	require.Equal(t, http.StatusOK, res.HTTPStatus)
+}
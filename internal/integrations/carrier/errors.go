@@ -0,0 +1,96 @@
+package carrier
+
+import (
+	"context"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// Классы ошибок запроса к перевозчику (журнал проверок, tracking_checks.error_class).
+const (
+	ErrorClassTimeout     = "TIMEOUT"
+	ErrorClassNetwork     = "NETWORK"
+	ErrorClassRateLimited = "RATE_LIMITED"
+	ErrorClassHTTP        = "HTTP"    // перевозчик ответил не 2xx
+	ErrorClassDecode      = "DECODE"  // ответ не разобрался
+	ErrorClassCarrier     = "CARRIER" // ответ разобрался, но перевозчик сообщил об ошибке
+	ErrorClassOther       = "OTHER"
+)
+
+// Error — ошибка запроса к перевозчику с классом и HTTP-кодом ответа (0 — ответа не было).
+// Текст ошибки — текст Err, так что оборачивание не меняет сообщений в last_error.
+type Error struct {
+	Class      string
+	HTTPStatus int
+	Err        error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+func NewError(class string, httpStatus int, err error) error {
+	return &Error{Class: class, HTTPStatus: httpStatus, Err: err}
+}
+
+// WithHTTPStatus проставляет код ответа в *Error без кода (Parser не знает, с каким кодом пришло тело).
+func WithHTTPStatus(err error, httpStatus int) error {
+	var ce *Error
+	if errors.As(err, &ce) && ce.HTTPStatus == 0 {
+		ce.HTTPStatus = httpStatus
+	}
+	return err
+}
+
+// Classify — класс ошибки и HTTP-код для журнала проверок. Ошибки без *Error разбираются по типу:
+// таймауты (контекст, http.Client) и сетевые ошибки, остальное — OTHER.
+func Classify(err error) (class string, httpStatus int) {
+	if err == nil {
+		return "", 0
+	}
+	var ce *Error
+	if errors.As(err, &ce) {
+		return ce.Class, ce.HTTPStatus
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassTimeout, 0
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		if ne.Timeout() {
+			return ErrorClassTimeout, 0
+		}
+		return ErrorClassNetwork, 0
+	}
+	return ErrorClassOther, 0
+}
+
+// MaxResponseBytes — сколько тела ответа перевозчика читается; длиннее — ошибка DECODE.
+const MaxResponseBytes = 8 << 20
+
+// ReadBody читает тело ответа целиком (не больше MaxResponseBytes).
+func ReadBody(r io.Reader, httpStatus int) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(r, MaxResponseBytes+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "read body")
+	}
+	if len(b) > MaxResponseBytes {
+		return nil, NewError(ErrorClassDecode, httpStatus, errors.Errorf("response is larger than %d bytes", MaxResponseBytes))
+	}
+	return b, nil
+}
+
+// BackendOf — бэкенд (models.CarrierBackend*), который обслуживает перевозчика; "" — клиент не сообщает.
+func BackendOf(c Client, carrierCode string) string {
+	switch v := c.(type) {
+	case interface {
+		BackendFor(carrierCode string) string
+	}:
+		return v.BackendFor(carrierCode)
+	case interface{ Backend() string }:
+		return v.Backend()
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+package carrier
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type namedClient struct{ staticClient }
+
+func (namedClient) Backend() string { return models.CarrierBackendFake }
+
+func TestClassify(t *testing.T) {
+	class, code := Classify(errors.Wrap(NewError(ErrorClassHTTP, 502, fmt.Errorf("http 502")), "get tracking"))
+	require.Equal(t, ErrorClassHTTP, class)
+	require.Equal(t, 502, code)
+
+	class, _ = Classify(errors.Wrap(context.DeadlineExceeded, "do request"))
+	require.Equal(t, ErrorClassTimeout, class)
+	class, _ = Classify(&net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")})
+	require.Equal(t, ErrorClassNetwork, class)
+	class, _ = Classify(fmt.Errorf("boom"))
+	require.Equal(t, ErrorClassOther, class)
+	class, _ = Classify(nil)
+	require.Empty(t, class)
+}
+
+func TestRouter_BackendFor(t *testing.T) {
+	r := NewRouter(staticClient("default"))
+	r.SetRoutes(map[string]Client{"CDEK": namedClient{}})
+	require.Equal(t, models.CarrierBackendFake, r.BackendFor("CDEK"))
+	require.Empty(t, r.BackendFor("POST_RU"))
+	require.Equal(t, models.CarrierBackendFake, BackendOf(r, "CDEK"))
+}
@@ -15,6 +15,9 @@ type FakeClient struct{}
 
 func New() *FakeClient { return &FakeClient{} }
 
+// Backend — для журнала проверок (carrier.BackendOf).
+func (f *FakeClient) Backend() string { return models.CarrierBackendFake }
+
 func (f *FakeClient) GetTracking(ctx context.Context, carrierCode, trackNumber string) (carrier.TrackingResult, error) {
 	now := time.Now().UTC()
 
@@ -0,0 +1,46 @@
+package carrier
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Router выбирает клиента по коду перевозчика; маршруты можно заменять на лету (справочник carriers).
+// Перевозчики без своего маршрута идут в клиент по умолчанию.
+type Router struct {
+	def    Client
+	routes atomic.Pointer[map[string]Client]
+}
+
+func NewRouter(def Client) *Router {
+	return &Router{def: def}
+}
+
+// SetRoutes атомарно заменяет все маршруты.
+func (r *Router) SetRoutes(routes map[string]Client) {
+	m := make(map[string]Client, len(routes))
+	for code, c := range routes {
+		if c != nil {
+			m[code] = c
+		}
+	}
+	r.routes.Store(&m)
+}
+
+func (r *Router) client(carrierCode string) Client {
+	if m := r.routes.Load(); m != nil {
+		if c, ok := (*m)[carrierCode]; ok {
+			return c
+		}
+	}
+	return r.def
+}
+
+func (r *Router) GetTracking(ctx context.Context, carrierCode, trackNumber string) (TrackingResult, error) {
+	return r.client(carrierCode).GetTracking(ctx, carrierCode, trackNumber)
+}
+
+// BackendFor — бэкенд клиента, который обслуживает перевозчика (см. BackendOf).
+func (r *Router) BackendFor(carrierCode string) string {
+	return BackendOf(r.client(carrierCode), carrierCode)
+}
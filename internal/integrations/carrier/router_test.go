@@ -0,0 +1,33 @@
+package carrier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type staticClient string
+
+func (s staticClient) GetTracking(context.Context, string, string) (TrackingResult, error) {
+	return TrackingResult{StatusRaw: string(s)}, nil
+}
+
+func TestRouter(t *testing.T) {
+	ctx := context.Background()
+	r := NewRouter(staticClient("default"))
+
+	res, err := r.GetTracking(ctx, "CDEK", "1")
+	require.NoError(t, err)
+	require.Equal(t, "default", res.StatusRaw)
+
+	r.SetRoutes(map[string]Client{"CDEK": staticClient("cdek")})
+	res, _ = r.GetTracking(ctx, "CDEK", "1")
+	require.Equal(t, "cdek", res.StatusRaw)
+	res, _ = r.GetTracking(ctx, "POST_RU", "1")
+	require.Equal(t, "default", res.StatusRaw)
+
+	r.SetRoutes(nil)
+	res, _ = r.GetTracking(ctx, "CDEK", "1")
+	require.Equal(t, "default", res.StatusRaw)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -38,17 +39,26 @@ func New(baseURL, apiKey, domain string) *Client {
 type track24Resp struct {
 	Status string `json:"status"`
 	Data   struct {
+		FromCountry        string `json:"fromCountry"`
+		FromCity           string `json:"fromCity"`
+		DestinationCountry string `json:"destinationCountry"`
+		DestinationCity    string `json:"destinationCity"`
+
 		Events []struct {
 			OperationDateTime        string `json:"operationDateTime"`
 			OperationAttribute       string `json:"operationAttribute"`
 			OperationType            string `json:"operationType"`
 			OperationPlaceName       string `json:"operationPlaceName"`
 			OperationPlacePostalCode string `json:"operationPlacePostalCode"`
+			ItemWeight               string `json:"itemWeight"`
 			Source                   string `json:"source"`
 		} `json:"events"`
 	} `json:"data"`
 }
 
+// Backend — для журнала проверок (carrier.BackendOf).
+func (c *Client) Backend() string { return models.CarrierBackendTrack24 }
+
 func (c *Client) GetTracking(ctx context.Context, carrierCode, trackNumber string) (carrier.TrackingResult, error) {
 	_ = carrierCode // в Track24 запросе carrier обычно автоопределяется
 
@@ -76,20 +86,35 @@ func (c *Client) GetTracking(ctx context.Context, carrierCode, trackNumber strin
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return carrier.TrackingResult{}, carrier.NewError(carrier.ErrorClassRateLimited, resp.StatusCode, fmt.Errorf("track24 emulator http %d", resp.StatusCode))
+	}
 	if resp.StatusCode/100 != 2 {
-		return carrier.TrackingResult{}, fmt.Errorf("track24 emulator http %d", resp.StatusCode)
+		return carrier.TrackingResult{}, carrier.NewError(carrier.ErrorClassHTTP, resp.StatusCode, fmt.Errorf("track24 emulator http %d", resp.StatusCode))
 	}
 
+	body, err := carrier.ReadBody(resp.Body, resp.StatusCode)
+	if err != nil {
+		return carrier.TrackingResult{}, err
+	}
+	res, err := Parse(body, time.Now().UTC())
+	res.Raw, res.HTTPStatus = body, resp.StatusCode
+	return res, carrier.WithHTTPStatus(err, resp.StatusCode)
+}
+
+// Parse разбирает тело ответа tracking.json.php (carrier.Parser). Track24 не присылает время статуса:
+// им считается receivedAt.
+func Parse(body []byte, receivedAt time.Time) (carrier.TrackingResult, error) {
 	var r track24Resp
-	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
-		return carrier.TrackingResult{}, errors.Wrap(err, "decode")
+	if err := json.Unmarshal(body, &r); err != nil {
+		return carrier.TrackingResult{}, carrier.NewError(carrier.ErrorClassDecode, 0, errors.Wrap(err, "decode"))
 	}
 	if r.Status != "ok" {
-		return carrier.TrackingResult{}, fmt.Errorf("track24 emulator status=%s", r.Status)
+		return carrier.TrackingResult{}, carrier.NewError(carrier.ErrorClassCarrier, 0, fmt.Errorf("track24 emulator status=%s", r.Status))
 	}
 
 	// Простейшая нормализация: если последняя операция содержит "вруч" или "достав" -> DELIVERED, иначе IN_TRANSIT.
-	now := time.Now().UTC()
+	now := receivedAt
 	status := models.TrackingStatusInTransit
 	statusRaw := ""
 	var events []*models.TrackingEvent
@@ -99,21 +124,26 @@ func (c *Client) GetTracking(ctx context.Context, carrierCode, trackNumber strin
 		loc := e.OperationPlaceName
 		statusRaw = msg
 
-		evTime := now
-		// Track24 пример: "02.07.2014 19:16:00"
-		if e.OperationDateTime != "" {
-			if t, err := time.ParseInLocation("02.01.2006 15:04:05", e.OperationDateTime, time.UTC); err == nil {
-				evTime = t.UTC()
-			}
-		}
-
-		events = append(events, &models.TrackingEvent{
+		ev := &models.TrackingEvent{
 			Status:    status,
 			StatusRaw: msg,
-			EventTime: evTime,
 			Location:  strPtr(loc),
 			Message:   strPtr(msg),
-		})
+		}
+		// Track24 отдаёт местное время места операции без пояса; пояс применяет worker.
+		// Не разобралось — EventTime остаётся нулевым, время подставит worker (см. poller.normalizeEventTimes).
+		if raw := strings.TrimSpace(e.OperationDateTime); raw != "" {
+			ev.EventTimeRaw = &raw
+			if t, ok := parseLocalTime(raw); ok {
+				ev.EventTime = t
+				ev.EventTimeLocal = true
+			}
+		}
+		// Регион и координаты по индексу дополняет worker (geo); здесь — только то, что прислал Track24.
+		if pc := strings.TrimSpace(e.OperationPlacePostalCode); pc != "" {
+			ev.Place = &models.Location{PostalCode: pc}
+		}
+		events = append(events, ev)
 	}
 
 	if len(r.Data.Events) > 0 {
@@ -124,12 +154,38 @@ func (c *Client) GetTracking(ctx context.Context, carrierCode, trackNumber strin
 		}
 	}
 
-	return carrier.TrackingResult{
+	res := carrier.TrackingResult{
 		Status:    status,
 		StatusRaw: statusRaw,
 		StatusAt:  &now,
 		Events:    events,
-	}, nil
+	}
+	sd := &models.ShipmentDetails{
+		Origin:        joinPlace(r.Data.FromCity, r.Data.FromCountry),
+		Destination:   joinPlace(r.Data.DestinationCity, r.Data.DestinationCountry),
+		RecipientCity: r.Data.DestinationCity,
+	}
+	// Вес приходит в операциях (в граммах, как у Почты России); берём последний известный.
+	for _, e := range r.Data.Events {
+		if w, err := strconv.ParseInt(strings.TrimSpace(e.ItemWeight), 10, 32); err == nil && w > 0 {
+			sd.WeightGrams = int32(w)
+		}
+	}
+	if !sd.IsEmpty() {
+		res.Shipment = sd
+	}
+	return res, nil
+}
+
+func joinPlace(city, country string) string {
+	switch {
+	case city == "":
+		return country
+	case country == "":
+		return city
+	default:
+		return city + ", " + country
+	}
 }
 
 func containsDeliveredHint(s string) bool {
@@ -137,6 +193,19 @@ func containsDeliveredHint(s string) bool {
 	return strings.Contains(low, "вруч") || strings.Contains(low, "достав") || strings.Contains(low, "delivered")
 }
 
+// localTimeLayouts — форматы времени Track24; пример: "02.07.2014 19:16:00".
+var localTimeLayouts = []string{"02.01.2006 15:04:05", "02.01.2006 15:04", "2006-01-02 15:04:05"}
+
+// parseLocalTime разбирает местное время без пояса (результат — те же часы в UTC-представлении).
+func parseLocalTime(s string) (time.Time, bool) {
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
 func strPtr(s string) *string {
 	if s == "" {
 		return nil
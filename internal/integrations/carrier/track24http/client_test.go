@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/BearBump/TrackBox/internal/models"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,9 +22,12 @@ func TestClient_GetTracking_OK(t *testing.T) {
 		_, _ = w.Write([]byte(`{
   "status": "ok",
   "data": {
+    "fromCountry": "Россия",
+    "fromCity": "Москва",
+    "destinationCity": "Казань",
     "events": [
-      {"operationDateTime":"01.01.2025 00:00:00","operationAttribute":"Accepted","operationType":"ACCEPTED","operationPlaceName":"Moscow","operationPlacePostalCode":"000000","source":"emulator"},
-      {"operationDateTime":"01.01.2025 00:10:00","operationAttribute":"Delivered","operationType":"DELIVERED","operationPlaceName":"Moscow","operationPlacePostalCode":"000000","source":"emulator"}
+      {"operationDateTime":"01.01.2025 00:00:00","operationAttribute":"Accepted","operationType":"ACCEPTED","operationPlaceName":"Moscow","operationPlacePostalCode":"","itemWeight":"350","source":"emulator"},
+      {"operationDateTime":"01.01.2025 00:10:00","operationAttribute":"Delivered","operationType":"DELIVERED","operationPlaceName":"Казань МСЦ","operationPlacePostalCode":"420300","source":"emulator"}
     ]
   }
 }`))
@@ -37,6 +41,43 @@ func TestClient_GetTracking_OK(t *testing.T) {
 	require.NotNil(t, res.StatusAt)
 	require.Len(t, res.Events, 2)
 	require.WithinDuration(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), res.Events[0].EventTime, time.Second)
+	require.True(t, res.Events[0].EventTimeLocal)
+	require.Equal(t, "01.01.2025 00:00:00", *res.Events[0].EventTimeRaw)
+	require.Nil(t, res.Events[0].Place)
+	require.Equal(t, &models.Location{PostalCode: "420300"}, res.Events[1].Place)
+	require.NotNil(t, res.Shipment)
+	require.Equal(t, "Москва, Россия", res.Shipment.Origin)
+	require.Equal(t, "Казань", res.Shipment.Destination)
+	require.Equal(t, "Казань", res.Shipment.RecipientCity)
+	require.EqualValues(t, 350, res.Shipment.WeightGrams)
+	require.Nil(t, res.Shipment.EstimatedDelivery)
+}
+
+func TestClient_GetTracking_EventTimes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"ok","data":{"events":[
+  {"operationDateTime":"30.03.2025 02:30","operationAttribute":"Accepted"},
+  {"operationDateTime":"2025-03-31 10:00:00","operationAttribute":"Sorted"},
+  {"operationDateTime":"31 марта, вечер","operationAttribute":"In transit"},
+  {"operationAttribute":"Delivered"}
+]}}`))
+	}))
+	defer srv.Close()
+
+	res, err := New(srv.URL, "demo", "d").GetTracking(context.Background(), "POST_RU", "CODE")
+	require.NoError(t, err)
+	require.Len(t, res.Events, 4)
+
+	require.Equal(t, time.Date(2025, 3, 30, 2, 30, 0, 0, time.UTC), res.Events[0].EventTime)
+	require.True(t, res.Events[0].EventTimeLocal)
+	require.Equal(t, time.Date(2025, 3, 31, 10, 0, 0, 0, time.UTC), res.Events[1].EventTime)
+
+	// не разобралось — без подстановки time.Now(), время выставит worker
+	require.True(t, res.Events[2].EventTime.IsZero())
+	require.False(t, res.Events[2].EventTimeLocal)
+	require.Equal(t, "31 марта, вечер", *res.Events[2].EventTimeRaw)
+	require.True(t, res.Events[3].EventTime.IsZero())
+	require.Nil(t, res.Events[3].EventTimeRaw)
 }
 
 func TestContainsDeliveredHint(t *testing.T) {
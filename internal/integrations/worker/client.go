@@ -0,0 +1,143 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/models"
+	pb_models "github.com/BearBump/TrackBox/internal/pb/models"
+	"github.com/BearBump/TrackBox/internal/pb/worker_api"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Client — gRPC клиент внутреннего API track-worker.
+type Client struct {
+	conn *grpc.ClientConn
+	c    worker_api.WorkerServiceClient
+}
+
+func New(target string) (*Client, error) {
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, errors.Wrap(err, "dial worker")
+	}
+	return newClientWithConn(conn, worker_api.NewWorkerServiceClient(conn)), nil
+}
+
+func newClientWithConn(conn *grpc.ClientConn, c worker_api.WorkerServiceClient) *Client {
+	return &Client{conn: conn, c: c}
+}
+
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// CheckTracking просит воркер немедленно сходить к перевозчику.
+// Результат возвращается в том же виде, в каком воркер публикует его в Kafka.
+func (c *Client) CheckTracking(ctx context.Context, t *models.Tracking) (messages.TrackingUpdated, error) {
+	resp, err := c.c.CheckTracking(ctx, &worker_api.CheckTrackingRequest{
+		Tracking: &pb_models.Tracking{
+			Id:             t.ID,
+			CarrierCode:    t.CarrierCode,
+			TrackNumber:    t.TrackNumber,
+			Status:         t.Status,
+			StatusRaw:      t.StatusRaw,
+			CheckFailCount: t.CheckFailCount,
+			CreatedAt:      createdAt(t),
+			// Атрибуты воркер кладёт в tracking.updated так же, как при плановой проверке.
+			MetadataJson: derefString(t.Metadata),
+			Tags:         t.Tags,
+			ExternalId:   derefString(t.ExternalID),
+		},
+		Fingerprint: t.Fingerprint,
+	})
+	if err != nil {
+		return messages.TrackingUpdated{}, errors.Wrap(err, "worker check tracking")
+	}
+
+	msg := messages.TrackingUpdated{
+		TrackingID:  t.ID,
+		CheckedAt:   resp.GetCheckedAt().AsTime(),
+		Status:      resp.GetStatus(),
+		StatusRaw:   resp.GetStatusRaw(),
+		NextCheckAt: resp.GetNextCheckAt().AsTime(),
+		Kind:        resp.GetKind(),
+		Fingerprint: resp.GetFingerprint(),
+	}
+	if resp.GetStatusAt() != nil {
+		statusAt := resp.GetStatusAt().AsTime()
+		msg.StatusAt = &statusAt
+	}
+	if resp.GetError() != "" {
+		e := resp.GetError()
+		msg.Error = &e
+	}
+	if sd := resp.GetShipment(); sd != nil {
+		msg.Shipment = &messages.Shipment{
+			WeightGrams:   sd.GetWeightGrams(),
+			Origin:        sd.GetOrigin(),
+			Destination:   sd.GetDestination(),
+			RecipientCity: sd.GetRecipientCity(),
+			ServiceType:   sd.GetServiceType(),
+		}
+		if sd.GetEstimatedDelivery() != nil {
+			eta := sd.GetEstimatedDelivery().AsTime()
+			msg.Shipment.EstimatedDelivery = &eta
+		}
+	}
+	for _, e := range resp.GetEvents() {
+		ev := messages.TrackingEvent{
+			Status:       e.GetStatus(),
+			StatusRaw:    e.GetStatusRaw(),
+			EventTime:    e.GetEventTime().AsTime(),
+			Location:     optString(e.GetLocation()),
+			Message:      optString(e.GetMessage()),
+			EventTimeRaw: optString(e.GetEventTimeRaw()),
+			TimeInferred: e.GetTimeInferred(),
+		}
+		if e.GetPayloadJson() != "" {
+			ev.Payload = json.RawMessage(e.GetPayloadJson())
+		}
+		if pl := e.GetPlace(); pl != nil {
+			ev.Place = &messages.Place{
+				Country:    pl.GetCountry(),
+				Region:     pl.GetRegion(),
+				City:       pl.GetCity(),
+				PostalCode: pl.GetPostalCode(),
+				Lat:        pl.Lat,
+				Lon:        pl.Lon,
+			}
+		}
+		msg.Events = append(msg.Events, ev)
+	}
+	return msg, nil
+}
+
+// createdAt — время создания трека: worker подставляет его событиям без разобранного времени.
+func createdAt(t *models.Tracking) *timestamppb.Timestamp {
+	if t.CreatedAt.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t.CreatedAt)
+}
+
+func optString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
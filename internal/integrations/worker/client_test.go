@@ -0,0 +1,136 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/api/worker_api"
+	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/models"
+	pb_models "github.com/BearBump/TrackBox/internal/pb/models"
+	pb_worker "github.com/BearBump/TrackBox/internal/pb/worker_api"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type fakeWorkerClient struct {
+	req  *pb_worker.CheckTrackingRequest
+	resp *pb_worker.CheckTrackingResponse
+	err  error
+}
+
+func (f *fakeWorkerClient) CheckTracking(ctx context.Context, in *pb_worker.CheckTrackingRequest, opts ...grpc.CallOption) (*pb_worker.CheckTrackingResponse, error) {
+	f.req = in
+	return f.resp, f.err
+}
+
+func TestClient_CheckTracking(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fc := &fakeWorkerClient{resp: &pb_worker.CheckTrackingResponse{
+		CheckedAt:   timestamppb.New(now),
+		Status:      "DELIVERED",
+		StatusRaw:   "raw",
+		StatusAt:    timestamppb.New(now),
+		NextCheckAt: timestamppb.New(now.Add(time.Hour)),
+		Events: []*pb_models.TrackingEvent{
+			{Status: "DELIVERED", StatusRaw: "raw", EventTime: timestamppb.New(now), Message: "Вручено", PayloadJson: `{"a":1}`,
+				Place:        &pb_models.EventLocation{City: "Казань", Lat: proto.Float64(55.8), Lon: proto.Float64(49.1)},
+				EventTimeRaw: "01.01.2025 10:00", TimeInferred: true},
+		},
+		Shipment:    &pb_models.ShipmentDetails{EstimatedDelivery: timestamppb.New(now), WeightGrams: 350, Origin: "Москва"},
+		Kind:        "status_changed",
+		Fingerprint: "fp2",
+	}}
+	c := newClientWithConn(nil, fc)
+
+	msg, err := c.CheckTracking(context.Background(), &models.Tracking{ID: 7, CarrierCode: "POST_RU", TrackNumber: "RA1", CheckFailCount: 1, CreatedAt: now, Fingerprint: "fp1"})
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), fc.req.Tracking.Id)
+	require.Equal(t, "POST_RU", fc.req.Tracking.CarrierCode)
+	require.Equal(t, now, fc.req.Tracking.CreatedAt.AsTime())
+	require.Equal(t, "fp1", fc.req.Fingerprint)
+	require.Equal(t, "status_changed", msg.Kind)
+	require.Equal(t, "fp2", msg.Fingerprint)
+	require.Equal(t, uint64(7), msg.TrackingID)
+	require.Equal(t, "DELIVERED", msg.Status)
+	require.NotNil(t, msg.StatusAt)
+	require.Nil(t, msg.Error)
+	require.Len(t, msg.Events, 1)
+	require.Nil(t, msg.Events[0].Location)
+	require.Equal(t, "Вручено", *msg.Events[0].Message)
+	require.JSONEq(t, `{"a":1}`, string(msg.Events[0].Payload))
+	require.Equal(t, "Казань", msg.Events[0].Place.City)
+	require.Equal(t, 55.8, *msg.Events[0].Place.Lat)
+	require.Equal(t, 49.1, *msg.Events[0].Place.Lon)
+	require.Equal(t, "01.01.2025 10:00", *msg.Events[0].EventTimeRaw)
+	require.True(t, msg.Events[0].TimeInferred)
+	require.Equal(t, now, *msg.Shipment.EstimatedDelivery)
+	require.EqualValues(t, 350, msg.Shipment.WeightGrams)
+	require.Equal(t, "Москва", msg.Shipment.Origin)
+	require.NoError(t, c.Close())
+}
+
+type recordingChecker struct {
+	got *models.Tracking
+}
+
+func (c *recordingChecker) CheckNow(ctx context.Context, tr *models.Tracking) (messages.TrackingUpdated, error) {
+	c.got = tr
+	return messages.TrackingUpdated{TrackingID: tr.ID, CheckedAt: time.Now().UTC()}, nil
+}
+
+// Атрибуты трека доходят до воркера: без них tracking.updated от CheckTrackingNow уходил бы без external_id,
+// тегов и metadata, в отличие от плановой проверки.
+func TestClient_CheckTracking_RoundTripAttributes(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	rc := &recordingChecker{}
+	pb_worker.RegisterWorkerServiceServer(srv, worker_api.New(rc))
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	c := newClientWithConn(conn, pb_worker.NewWorkerServiceClient(conn))
+	defer func() { _ = c.Close() }()
+
+	meta, ext := `{"order":42}`, "ORD-42"
+	in := &models.Tracking{ID: 7, CarrierCode: "CDEK", TrackNumber: "A1", Metadata: &meta, Tags: []string{"vip", "b2b"}, ExternalID: &ext}
+	_, err = c.CheckTracking(context.Background(), in)
+	require.NoError(t, err)
+	require.Equal(t, &meta, rc.got.Metadata)
+	require.Equal(t, []string{"vip", "b2b"}, rc.got.Tags)
+	require.Equal(t, &ext, rc.got.ExternalID)
+
+	_, err = c.CheckTracking(context.Background(), &models.Tracking{ID: 8, CarrierCode: "CDEK", TrackNumber: "A2"})
+	require.NoError(t, err)
+	require.Nil(t, rc.got.Metadata)
+	require.Empty(t, rc.got.Tags)
+	require.Nil(t, rc.got.ExternalID)
+}
+
+func TestClient_CheckTracking_Errors(t *testing.T) {
+	c := newClientWithConn(nil, &fakeWorkerClient{err: errors.New("unavailable")})
+	_, err := c.CheckTracking(context.Background(), &models.Tracking{ID: 1})
+	require.Error(t, err)
+
+	c = newClientWithConn(nil, &fakeWorkerClient{resp: &pb_worker.CheckTrackingResponse{Error: "http 503"}})
+	msg, err := c.CheckTracking(context.Background(), &models.Tracking{ID: 1})
+	require.NoError(t, err)
+	require.Equal(t, "http 503", *msg.Error)
+}
+
+func TestNew_Close(t *testing.T) {
+	c, err := New("localhost:0")
+	require.NoError(t, err)
+	require.NoError(t, c.Close())
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Виды алертов SLA.
+const (
+	AlertKindNoEvents    = "NO_EVENTS"    // слишком долго нет новых событий
+	AlertKindTransitTime = "TRANSIT_TIME" // слишком долго в пути
+	AlertKindETAMissed   = "ETA_MISSED"   // ожидаемая дата доставки прошла, а трек не доставлен
+)
+
+// Состояния алерта.
+const (
+	AlertStateOpen     = "OPEN"
+	AlertStateResolved = "RESOLVED"
+)
+
+// Alert — нарушение SLA по треку. Открытый алерт одного вида у трека может быть только один;
+// закрывается, когда нарушение пропало (пришло событие, трек доставлен, правило смягчили).
+type Alert struct {
+	ID          uint64
+	TrackingID  uint64
+	CarrierCode string
+	TrackNumber string
+	Kind        string
+	// Rule — имя правила SLA, по которому открыт алерт.
+	Rule string
+	// Details — человекочитаемое описание нарушения на момент открытия.
+	Details    string
+	OpenedAt   time.Time
+	ResolvedAt *time.Time
+}
+
+// State — OPEN или RESOLVED.
+func (a *Alert) State() string {
+	if a.ResolvedAt != nil {
+		return AlertStateResolved
+	}
+	return AlertStateOpen
+}
+
+// SLASubject — недоставленный трек со сводкой по событиям и уже открытым алертам (вход оценки SLA).
+type SLASubject struct {
+	Tracking     *Tracking
+	FirstEventAt *time.Time // nil — событий ещё нет
+	LastEventAt  *time.Time
+	OpenKinds    []string
+}
+
+// AlertFilter — фильтр списка алертов; пустые поля не фильтруют.
+type AlertFilter struct {
+	State       string // OPEN | RESOLVED
+	TrackingID  uint64
+	Kind        string
+	CarrierCode string
+}
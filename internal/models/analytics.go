@@ -0,0 +1,96 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// TransitBucketBounds — нижние границы корзин гистограммы времени доставки, в часах (последняя корзина открыта).
+// Границы хранятся только здесь: по индексу корзины в БД считаются перцентили, поэтому менять можно
+// только добавлением новых границ в конец.
+var TransitBucketBounds = []float64{
+	0, 1, 2, 4, 6, 8, 12, 18, 24, 36, 48, 60, 72, 96, 120, 144, 168,
+	216, 264, 336, 432, 504, 720, 1080, 1440, 2160,
+}
+
+// TransitBucketIndex — индекс корзины гистограммы для длительности d (отрицательные — в нулевую).
+func TransitBucketIndex(d time.Duration) int {
+	h := d.Hours()
+	i := sort.Search(len(TransitBucketBounds), func(i int) bool { return TransitBucketBounds[i] > h }) - 1
+	if i < 0 {
+		return 0
+	}
+	return i
+}
+
+// StatusCount — сколько треков перевозчика сейчас в статусе.
+type StatusCount struct {
+	CarrierCode string
+	Status      string
+	Trackings   int64
+}
+
+// CarrierDayStats — дневной срез по перевозчику (день — UTC, по времени проверки).
+type CarrierDayStats struct {
+	Day           time.Time
+	CarrierCode   string
+	Checks        int64
+	FailedChecks  int64
+	StatusChanges int64
+	Delivered     int64
+}
+
+// StatusEntries — сколько треков перешло в статус за день.
+type StatusEntries struct {
+	Day         time.Time
+	CarrierCode string
+	Status      string
+	Entered     int64
+}
+
+// TransitHistogramRow — сколько доставленных за день треков попало в корзину Bucket (см. TransitBucketBounds).
+type TransitHistogramRow struct {
+	Day         time.Time
+	CarrierCode string
+	Bucket      int
+	Deliveries  int64
+}
+
+// Шаг агрегации аналитики (границы — UTC, неделя начинается с понедельника).
+const (
+	AnalyticsBucketDay   = "DAY"
+	AnalyticsBucketWeek  = "WEEK"
+	AnalyticsBucketMonth = "MONTH"
+)
+
+// StatusBucket — переходы в статусы у перевозчика за интервал.
+type StatusBucket struct {
+	Start       time.Time
+	CarrierCode string
+	Entered     map[string]int64 // статус → сколько треков в него перешло
+}
+
+// CheckBucket — объём проверок и доля ошибок у перевозчика за интервал.
+type CheckBucket struct {
+	Start         time.Time
+	CarrierCode   string
+	Checks        int64
+	FailedChecks  int64
+	FailureRate   float64 // FailedChecks / Checks; 0 без проверок
+	StatusChanges int64
+	Delivered     int64
+}
+
+// TransitBucket — время доставки у треков перевозчика, доставленных за интервал.
+type TransitBucket struct {
+	Start       time.Time
+	CarrierCode string
+	Deliveries  int64
+	Percentiles []TransitPercentile
+}
+
+// TransitPercentile — перцентиль времени доставки (оценка по гистограмме, точность — ширина корзины).
+type TransitPercentile struct {
+	Percentile float64 // 0..100
+	Value      time.Duration
+}
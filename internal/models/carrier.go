@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Бэкенды перевозчика — каким клиентом worker ходит за статусами.
+const (
+	CarrierBackendDefault    = ""            // клиент из конфига worker'а (carrier_emulator_mode)
+	CarrierBackendEmulatorV1 = "emulator_v1" // python carrier-emulator, /v1/tracking
+	CarrierBackendTrack24    = "track24"     // Track24-совместимый HTTP API
+	CarrierBackendFake       = "fake"        // детерминированная заглушка
+)
+
+// Изменяемые поля перевозчика — имена для частичного обновления (как в API).
+const (
+	CarrierFieldDisplayName    = "display_name"
+	CarrierFieldBackend        = "backend"
+	CarrierFieldCredentialsRef = "credentials_ref"
+	CarrierFieldRateLimit      = "rate_limit_per_minute"
+	CarrierFieldScheduling     = "scheduling_json"
+	CarrierFieldEnabled        = "enabled"
+)
+
+// Carrier — запись справочника перевозчиков (таблица carriers).
+type Carrier struct {
+	Code        string
+	DisplayName string
+	Backend     string
+
+	// CredentialsRef — ссылка на ключ API ("env:NAME" | "file:/path"); сам ключ в БД не хранится.
+	// Пусто — ключ из конфига worker'а.
+	CredentialsRef string
+
+	// RateLimitPerMinute — лимит запросов к перевозчику; 0 — общий лимит worker'а.
+	RateLimitPerMinute int32
+
+	// SchedulingJSON — политика планирования в формате scheduling.carriers.<code> конфига (JSON);
+	// nil — политика из конфига.
+	SchedulingJSON *string
+
+	// Enabled=false: новые треки не принимаются, существующие не проверяются.
+	Enabled bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Виды записей ленты изменений (ListChanges), в порядке внутри одного change_seq.
+const (
+	ChangeTracking = 1 // трек (текущее состояние)
+	ChangeEvent    = 2 // событие трека (текущее состояние, включая пропавшие у перевозчика)
+	ChangeDeleted  = 3 // трек удалён
+)
+
+// Change — запись ленты изменений: текущее состояние изменённого трека, события или отметка об удалении трека.
+// Seq — номер изменения; у всего, что изменила одна транзакция, он общий.
+type Change struct {
+	Seq      int64
+	Kind     int
+	Tracking *Tracking
+	Event    *TrackingEvent
+	Deleted  *TrackingTombstone
+}
+
+// TrackingTombstone — отметка об удалённом треке: остаётся в ленте изменений, чтобы зеркала тоже его удалили.
+type TrackingTombstone struct {
+	TrackingID  uint64
+	CarrierCode string
+	TrackNumber string
+	ExternalID  *string
+	DeletedAt   time.Time
+}
+
+// ID — id трека или события, к которому относится запись.
+func (c *Change) ID() uint64 {
+	switch {
+	case c.Tracking != nil:
+		return c.Tracking.ID
+	case c.Event != nil:
+		return c.Event.ID
+	case c.Deleted != nil:
+		return c.Deleted.TrackingID
+	}
+	return 0
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// TrackingCheck — запись журнала проверок (tracking_checks): одна проверка трека worker'ом,
+// в том числе неудачная или без изменений.
+type TrackingCheck struct {
+	TrackingID uint64
+	CheckedAt  time.Time
+
+	Kind   string  // messages.Kind*; пусто у сообщений от старых воркеров
+	Status string  // статус из ответа; пусто при ошибке
+	Error  *string // текст ошибки перевозчика
+
+	// Сведения от worker'а (messages.CheckInfo); нулевые у сообщений от старых воркеров.
+	ErrorClass   string // carrier.ErrorClass*
+	Worker       string
+	Backend      string
+	LatencyMS    int64
+	HTTPStatus   int
+	EventsCount  int
+	ResponseHash string
+	ResponseRef  string // ключ сырого ответа в архиве (internal/storage/archive); пусто — не сохранялся
+}
+
+// Failed — проверка закончилась ошибкой перевозчика.
+func (c *TrackingCheck) Failed() bool {
+	return c.Error != nil && *c.Error != ""
+}
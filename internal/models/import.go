@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Статусы задачи импорта.
+const (
+	ImportJobUploading = "UPLOADING" // строки ещё принимаются
+	ImportJobQueued    = "QUEUED"
+	ImportJobRunning   = "RUNNING"
+	ImportJobDone      = "DONE"
+	ImportJobFailed    = "FAILED"
+)
+
+// ImportJob — асинхронный импорт треков из CSV/JSONL.
+type ImportJob struct {
+	ID     uint64
+	Status string
+	Format string
+	Source string // имя файла / откуда загружено, для людей
+
+	TotalRows     int64
+	InvalidRows   int64 // не прошли валидацию при загрузке, см. ImportRow.Error
+	ProcessedRows int64 // обработано строк (включая невалидные) — прогресс
+	ImportedRows  int64 // валидные строки, по которым трек создан или уже был
+
+	// Cursor — номер последней обработанной строки; обработка продолжается с него после рестарта.
+	Cursor    int64
+	Attempts  int32
+	LastError *string
+
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FinishedAt *time.Time
+}
+
+// ImportRow — строка файла импорта. Line — номер строки в исходном файле.
+type ImportRow struct {
+	Line        int64
+	CarrierCode string
+	TrackNumber string
+	Metadata    *string // JSON-объект, необязательный
+	Tags        []string
+	ExternalID  *string
+	Error       *string // причина, по которой строка не будет импортирована
+}
@@ -0,0 +1,16 @@
+package models
+
+// Location — место события. Пустое поле — неизвестно.
+type Location struct {
+	Country    string // ISO 3166-1 alpha-2, например "RU"
+	Region     string // субъект / область
+	City       string
+	PostalCode string
+	Lat        *float64
+	Lon        *float64
+}
+
+// IsEmpty — о месте ничего не известно.
+func (l *Location) IsEmpty() bool {
+	return l == nil || (l.Country == "" && l.Region == "" && l.City == "" && l.PostalCode == "" && l.Lat == nil && l.Lon == nil)
+}
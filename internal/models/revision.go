@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Виды ревизий события.
+const (
+	EventRevisionEdited   = "EDITED"   // перевозчик исправил время, место или текст события
+	EventRevisionRemoved  = "REMOVED"  // событие пропало из ответа перевозчика
+	EventRevisionRestored = "RESTORED" // пропавшее событие вернулось
+)
+
+// EventRevision — изменение уже сохранённого события на стороне перевозчика.
+type EventRevision struct {
+	ID         uint64
+	TrackingID uint64
+	EventID    uint64
+	Kind       string
+	// Previous — событие до изменения (status, status_raw, event_time, location, message).
+	Previous   *TrackingEvent
+	ObservedAt time.Time
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// ShipmentDetails — сведения об отправлении, которые отдаёт перевозчик. Пустое поле — неизвестно.
+type ShipmentDetails struct {
+	EstimatedDelivery *time.Time
+	WeightGrams       int32
+	Origin            string // откуда (город/страна, как отдаёт перевозчик)
+	Destination       string
+	RecipientCity     string
+	ServiceType       string // тариф / вид отправления
+}
+
+// IsEmpty — перевозчик ничего не сообщил.
+func (d *ShipmentDetails) IsEmpty() bool {
+	return d == nil || *d == ShipmentDetails{}
+}
+
+// Merge накладывает непустые поля upd на d (перевозчики отдают сведения не в каждом ответе)
+// и возвращает результат; d не меняется.
+func (d *ShipmentDetails) Merge(upd *ShipmentDetails) *ShipmentDetails {
+	var out ShipmentDetails
+	if d != nil {
+		out = *d
+	}
+	if upd == nil {
+		return &out
+	}
+	if upd.EstimatedDelivery != nil {
+		out.EstimatedDelivery = upd.EstimatedDelivery
+	}
+	if upd.WeightGrams != 0 {
+		out.WeightGrams = upd.WeightGrams
+	}
+	if upd.Origin != "" {
+		out.Origin = upd.Origin
+	}
+	if upd.Destination != "" {
+		out.Destination = upd.Destination
+	}
+	if upd.RecipientCity != "" {
+		out.RecipientCity = upd.RecipientCity
+	}
+	if upd.ServiceType != "" {
+		out.ServiceType = upd.ServiceType
+	}
+	return &out
+}
+
+// ETAChange — смена ожидаемой даты доставки (история для оценки сдвигов).
+type ETAChange struct {
+	TrackingID        uint64
+	EstimatedDelivery time.Time
+	Previous          *time.Time // nil — первая известная ETA
+	ObservedAt        time.Time
+}
+
+// Slip — на сколько сдвинулась ETA (положительное — позже); 0 для первой записи.
+func (c ETAChange) Slip() time.Duration {
+	if c.Previous == nil {
+		return 0
+	}
+	return c.EstimatedDelivery.Sub(*c.Previous)
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Этапы доставки (вехи таймлайна), по порядку.
+const (
+	MilestoneAccepted       = "ACCEPTED"
+	MilestoneInTransit      = "IN_TRANSIT"
+	MilestoneArrived        = "ARRIVED_AT_DESTINATION"
+	MilestoneOutForDelivery = "OUT_FOR_DELIVERY"
+	MilestoneDelivered      = "DELIVERED"
+)
+
+// Milestones — все этапы по порядку.
+var Milestones = []string{MilestoneAccepted, MilestoneInTransit, MilestoneArrived, MilestoneOutForDelivery, MilestoneDelivered}
+
+// Timeline — история трека для клиента: этапы доставки и события без повторов.
+type Timeline struct {
+	TrackingID uint64
+	// CurrentStage — последний достигнутый этап; "" — событий ещё нет.
+	CurrentStage string
+	Milestones   []Milestone
+	Entries      []TimelineEntry
+	// Elapsed — от первого события до вручения (или до текущего момента, если не вручено).
+	Elapsed time.Duration
+}
+
+// Milestone — этап доставки.
+type Milestone struct {
+	Stage string
+	// Reached — этап пройден; ReachedAt может быть nil, если перевозчик его пропустил
+	// (например, сразу «вручено» после «принято»).
+	Reached   bool
+	ReachedAt *time.Time
+	EventID   uint64 // первое событие этапа; 0 — этап пропущен или не достигнут
+	// SincePrevious — от предыдущего этапа с известным временем; 0 для первого.
+	SincePrevious time.Duration
+}
+
+// TimelineEntry — одно или несколько подряд идущих одинаковых событий (повторы перевозчика склеиваются).
+type TimelineEntry struct {
+	Stage     string
+	Status    string
+	StatusRaw string
+	Location  *string
+	Message   *string
+	Place     *Location
+	FirstAt   time.Time
+	LastAt    time.Time
+	EventIDs  []uint64
+}
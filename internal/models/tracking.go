@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Нормализованные статусы (можно расширять).
 const (
@@ -22,6 +25,17 @@ type Tracking struct {
 	LastError    *string
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+
+	// Пользовательские атрибуты: TrackBox их не интерпретирует, только хранит, фильтрует и отдаёт.
+	Metadata   *string  // JSON-объект
+	Tags       []string // без повторов, в порядке добавления
+	ExternalID *string  // id во внешней системе (например, номер заказа)
+
+	// Shipment — последние сведения об отправлении от перевозчика; nil — перевозчик их не отдавал.
+	Shipment *ShipmentDetails
+
+	// Fingerprint — отпечаток последнего успешного ответа перевозчика (см. poller); "" — ещё не было.
+	Fingerprint string
 }
 
 type TrackingEvent struct {
@@ -34,11 +48,65 @@ type TrackingEvent struct {
 	Message    *string
 	PayloadJSON *string
 	CreatedAt  time.Time
+
+	// Place — место события в структурированном виде (от перевозчика + справочник geo); nil — неизвестно.
+	// Location остаётся как есть: это текст перевозчика, он входит в ключ дедупликации.
+	Place *Location
+
+	// EventTimeRaw — время события строкой, как его прислал перевозчик (если он шлёт местное время без пояса).
+	EventTimeRaw *string
+	// EventTimeLocal — EventTime ещё местное (часы/минуты перевозчика в UTC-представлении): пояс
+	// по правилам перевозчика применяет worker (см. poller.ZoneRule). В БД не хранится.
+	EventTimeLocal bool
+	// TimeInferred — время события в ответе перевозчика не разобралось и подставлено (см. poller).
+	TimeInferred bool
+
+	// Revision — сколько раз перевозчик исправлял событие (см. EventRevision); 0 — не исправлялось.
+	Revision int32
+	// RemovedAt — когда событие пропало из ответа перевозчика; такие события не отдаются в списках.
+	RemovedAt *time.Time
+}
+
+// DedupKey — ключ дедупликации события (uq_tracking_events_dedup): status_raw, время, location, message.
+func (e *TrackingEvent) DedupKey() string {
+	loc, msg := "", ""
+	if e.Location != nil {
+		loc = *e.Location
+	}
+	if e.Message != nil {
+		msg = *e.Message
+	}
+	return fmt.Sprintf("%s|%d|%s|%s", e.StatusRaw, e.EventTime.UTC().UnixMicro(), loc, msg)
 }
 
 type TrackingCreateInput struct {
 	CarrierCode string
 	TrackNumber string
+
+	// Атрибуты применяются только к новому треку; у существующего не меняются (см. TrackingPatch).
+	Metadata   *string
+	Tags       []string
+	ExternalID *string
 }
 
+// TrackingPatch — изменение пользовательских атрибутов трека; nil / пустые поля не меняются.
+type TrackingPatch struct {
+	Metadata   *string // "" — удалить
+	ExternalID *string // "" — удалить
+	ClearTags  bool    // сначала убрать все теги, потом применить AddTags
+	AddTags    []string
+	RemoveTags []string // применяется последним: тег из AddTags и RemoveTags удаляется
+}
 
+// TrackingHistory — трек вместе с историей событий (для бэктеста планировщика).
+type TrackingHistory struct {
+	Tracking *Tracking
+	Events   []*TrackingEvent
+}
+
+// CarrierCadence — типичный интервал между событиями у перевозчика.
+type CarrierCadence struct {
+	CarrierCode string
+	MedianGap   time.Duration
+	Samples     int64
+}
@@ -0,0 +1,129 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.2
+// source: alerts_api/alerts.proto
+
+package alerts_api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AlertsService_ListAlerts_FullMethodName = "/trackbox.alerts.v1.AlertsService/ListAlerts"
+)
+
+// AlertsServiceClient is the client API for AlertsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Алерты SLA доставки. Оценку по правилам trackbox.sla ведёт track-worker;
+// открытие и закрытие алертов также публикуется в Kafka (tracking.alert).
+type AlertsServiceClient interface {
+	// Алерты от новых к старым.
+	ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error)
+}
+
+type alertsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAlertsServiceClient(cc grpc.ClientConnInterface) AlertsServiceClient {
+	return &alertsServiceClient{cc}
+}
+
+func (c *alertsServiceClient) ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertsResponse)
+	err := c.cc.Invoke(ctx, AlertsService_ListAlerts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AlertsServiceServer is the server API for AlertsService service.
+// All implementations must embed UnimplementedAlertsServiceServer
+// for forward compatibility.
+//
+// Алерты SLA доставки. Оценку по правилам trackbox.sla ведёт track-worker;
+// открытие и закрытие алертов также публикуется в Kafka (tracking.alert).
+type AlertsServiceServer interface {
+	// Алерты от новых к старым.
+	ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error)
+	mustEmbedUnimplementedAlertsServiceServer()
+}
+
+// UnimplementedAlertsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAlertsServiceServer struct{}
+
+func (UnimplementedAlertsServiceServer) ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAlerts not implemented")
+}
+func (UnimplementedAlertsServiceServer) mustEmbedUnimplementedAlertsServiceServer() {}
+func (UnimplementedAlertsServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeAlertsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AlertsServiceServer will
+// result in compilation errors.
+type UnsafeAlertsServiceServer interface {
+	mustEmbedUnimplementedAlertsServiceServer()
+}
+
+func RegisterAlertsServiceServer(s grpc.ServiceRegistrar, srv AlertsServiceServer) {
+	// If the following call panics, it indicates UnimplementedAlertsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AlertsService_ServiceDesc, srv)
+}
+
+func _AlertsService_ListAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertsServiceServer).ListAlerts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertsService_ListAlerts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertsServiceServer).ListAlerts(ctx, req.(*ListAlertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AlertsService_ServiceDesc is the grpc.ServiceDesc for AlertsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AlertsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "trackbox.alerts.v1.AlertsService",
+	HandlerType: (*AlertsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListAlerts",
+			Handler:    _AlertsService_ListAlerts_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "alerts_api/alerts.proto",
+}
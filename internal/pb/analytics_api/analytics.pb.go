@@ -0,0 +1,787 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.2
+// source: analytics_api/analytics.proto
+
+package analytics_api
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AnalyticsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Пусто — все перевозчики (результат всё равно по каждому).
+	CarrierCode string `protobuf:"bytes,1,opt,name=carrier_code,json=carrierCode,proto3" json:"carrier_code,omitempty"`
+	// Период по дням UTC, включительно; по умолчанию — последние 30 дней. Не длиннее 2 лет.
+	From *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	// DAY (по умолчанию) | WEEK (с понедельника) | MONTH
+	Bucket        string `protobuf:"bytes,4,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyticsRequest) Reset() {
+	*x = AnalyticsRequest{}
+	mi := &file_analytics_api_analytics_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyticsRequest) ProtoMessage() {}
+
+func (x *AnalyticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_api_analytics_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyticsRequest.ProtoReflect.Descriptor instead.
+func (*AnalyticsRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_api_analytics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AnalyticsRequest) GetCarrierCode() string {
+	if x != nil {
+		return x.CarrierCode
+	}
+	return ""
+}
+
+func (x *AnalyticsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *AnalyticsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *AnalyticsRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+type StatusCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CarrierCode   string                 `protobuf:"bytes,1,opt,name=carrier_code,json=carrierCode,proto3" json:"carrier_code,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Trackings     int64                  `protobuf:"varint,3,opt,name=trackings,proto3" json:"trackings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusCount) Reset() {
+	*x = StatusCount{}
+	mi := &file_analytics_api_analytics_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusCount) ProtoMessage() {}
+
+func (x *StatusCount) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_api_analytics_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusCount.ProtoReflect.Descriptor instead.
+func (*StatusCount) Descriptor() ([]byte, []int) {
+	return file_analytics_api_analytics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StatusCount) GetCarrierCode() string {
+	if x != nil {
+		return x.CarrierCode
+	}
+	return ""
+}
+
+func (x *StatusCount) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *StatusCount) GetTrackings() int64 {
+	if x != nil {
+		return x.Trackings
+	}
+	return 0
+}
+
+type StatusBucket struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Start       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	CarrierCode string                 `protobuf:"bytes,2,opt,name=carrier_code,json=carrierCode,proto3" json:"carrier_code,omitempty"`
+	// Статус → сколько треков в него перешло за интервал.
+	Entered       map[string]int64 `protobuf:"bytes,3,rep,name=entered,proto3" json:"entered,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusBucket) Reset() {
+	*x = StatusBucket{}
+	mi := &file_analytics_api_analytics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusBucket) ProtoMessage() {}
+
+func (x *StatusBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_api_analytics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusBucket.ProtoReflect.Descriptor instead.
+func (*StatusBucket) Descriptor() ([]byte, []int) {
+	return file_analytics_api_analytics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StatusBucket) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *StatusBucket) GetCarrierCode() string {
+	if x != nil {
+		return x.CarrierCode
+	}
+	return ""
+}
+
+func (x *StatusBucket) GetEntered() map[string]int64 {
+	if x != nil {
+		return x.Entered
+	}
+	return nil
+}
+
+type StatusDistributionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Current       []*StatusCount         `protobuf:"bytes,1,rep,name=current,proto3" json:"current,omitempty"`
+	Buckets       []*StatusBucket        `protobuf:"bytes,2,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusDistributionResponse) Reset() {
+	*x = StatusDistributionResponse{}
+	mi := &file_analytics_api_analytics_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusDistributionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusDistributionResponse) ProtoMessage() {}
+
+func (x *StatusDistributionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_api_analytics_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusDistributionResponse.ProtoReflect.Descriptor instead.
+func (*StatusDistributionResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_api_analytics_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StatusDistributionResponse) GetCurrent() []*StatusCount {
+	if x != nil {
+		return x.Current
+	}
+	return nil
+}
+
+func (x *StatusDistributionResponse) GetBuckets() []*StatusBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type TransitTimesRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	CarrierCode string                 `protobuf:"bytes,1,opt,name=carrier_code,json=carrierCode,proto3" json:"carrier_code,omitempty"`
+	From        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Bucket      string                 `protobuf:"bytes,4,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	// В (0, 100], не больше 10; пусто — 50, 90, 95.
+	Percentiles   []float64 `protobuf:"fixed64,5,rep,packed,name=percentiles,proto3" json:"percentiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitTimesRequest) Reset() {
+	*x = TransitTimesRequest{}
+	mi := &file_analytics_api_analytics_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitTimesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitTimesRequest) ProtoMessage() {}
+
+func (x *TransitTimesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_api_analytics_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitTimesRequest.ProtoReflect.Descriptor instead.
+func (*TransitTimesRequest) Descriptor() ([]byte, []int) {
+	return file_analytics_api_analytics_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TransitTimesRequest) GetCarrierCode() string {
+	if x != nil {
+		return x.CarrierCode
+	}
+	return ""
+}
+
+func (x *TransitTimesRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *TransitTimesRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *TransitTimesRequest) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *TransitTimesRequest) GetPercentiles() []float64 {
+	if x != nil {
+		return x.Percentiles
+	}
+	return nil
+}
+
+type TransitPercentile struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Percentile float64                `protobuf:"fixed64,1,opt,name=percentile,proto3" json:"percentile,omitempty"`
+	// Оценка по гистограмме: точность — ширина корзины (от часа для коротких до суток и больше для длинных).
+	Value         *durationpb.Duration `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitPercentile) Reset() {
+	*x = TransitPercentile{}
+	mi := &file_analytics_api_analytics_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitPercentile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitPercentile) ProtoMessage() {}
+
+func (x *TransitPercentile) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_api_analytics_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitPercentile.ProtoReflect.Descriptor instead.
+func (*TransitPercentile) Descriptor() ([]byte, []int) {
+	return file_analytics_api_analytics_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TransitPercentile) GetPercentile() float64 {
+	if x != nil {
+		return x.Percentile
+	}
+	return 0
+}
+
+func (x *TransitPercentile) GetValue() *durationpb.Duration {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type TransitBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	CarrierCode   string                 `protobuf:"bytes,2,opt,name=carrier_code,json=carrierCode,proto3" json:"carrier_code,omitempty"`
+	Deliveries    int64                  `protobuf:"varint,3,opt,name=deliveries,proto3" json:"deliveries,omitempty"`
+	Percentiles   []*TransitPercentile   `protobuf:"bytes,4,rep,name=percentiles,proto3" json:"percentiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitBucket) Reset() {
+	*x = TransitBucket{}
+	mi := &file_analytics_api_analytics_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitBucket) ProtoMessage() {}
+
+func (x *TransitBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_api_analytics_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitBucket.ProtoReflect.Descriptor instead.
+func (*TransitBucket) Descriptor() ([]byte, []int) {
+	return file_analytics_api_analytics_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TransitBucket) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *TransitBucket) GetCarrierCode() string {
+	if x != nil {
+		return x.CarrierCode
+	}
+	return ""
+}
+
+func (x *TransitBucket) GetDeliveries() int64 {
+	if x != nil {
+		return x.Deliveries
+	}
+	return 0
+}
+
+func (x *TransitBucket) GetPercentiles() []*TransitPercentile {
+	if x != nil {
+		return x.Percentiles
+	}
+	return nil
+}
+
+type TransitTimesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buckets       []*TransitBucket       `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitTimesResponse) Reset() {
+	*x = TransitTimesResponse{}
+	mi := &file_analytics_api_analytics_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitTimesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitTimesResponse) ProtoMessage() {}
+
+func (x *TransitTimesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_api_analytics_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitTimesResponse.ProtoReflect.Descriptor instead.
+func (*TransitTimesResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_api_analytics_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TransitTimesResponse) GetBuckets() []*TransitBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type CheckBucket struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Start        *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	CarrierCode  string                 `protobuf:"bytes,2,opt,name=carrier_code,json=carrierCode,proto3" json:"carrier_code,omitempty"`
+	Checks       int64                  `protobuf:"varint,3,opt,name=checks,proto3" json:"checks,omitempty"`
+	FailedChecks int64                  `protobuf:"varint,4,opt,name=failed_checks,json=failedChecks,proto3" json:"failed_checks,omitempty"`
+	// failed_checks / checks.
+	FailureRate   float64 `protobuf:"fixed64,5,opt,name=failure_rate,json=failureRate,proto3" json:"failure_rate,omitempty"`
+	StatusChanges int64   `protobuf:"varint,6,opt,name=status_changes,json=statusChanges,proto3" json:"status_changes,omitempty"`
+	Delivered     int64   `protobuf:"varint,7,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckBucket) Reset() {
+	*x = CheckBucket{}
+	mi := &file_analytics_api_analytics_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckBucket) ProtoMessage() {}
+
+func (x *CheckBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_api_analytics_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckBucket.ProtoReflect.Descriptor instead.
+func (*CheckBucket) Descriptor() ([]byte, []int) {
+	return file_analytics_api_analytics_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CheckBucket) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *CheckBucket) GetCarrierCode() string {
+	if x != nil {
+		return x.CarrierCode
+	}
+	return ""
+}
+
+func (x *CheckBucket) GetChecks() int64 {
+	if x != nil {
+		return x.Checks
+	}
+	return 0
+}
+
+func (x *CheckBucket) GetFailedChecks() int64 {
+	if x != nil {
+		return x.FailedChecks
+	}
+	return 0
+}
+
+func (x *CheckBucket) GetFailureRate() float64 {
+	if x != nil {
+		return x.FailureRate
+	}
+	return 0
+}
+
+func (x *CheckBucket) GetStatusChanges() int64 {
+	if x != nil {
+		return x.StatusChanges
+	}
+	return 0
+}
+
+func (x *CheckBucket) GetDelivered() int64 {
+	if x != nil {
+		return x.Delivered
+	}
+	return 0
+}
+
+type CheckStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Buckets       []*CheckBucket         `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckStatsResponse) Reset() {
+	*x = CheckStatsResponse{}
+	mi := &file_analytics_api_analytics_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckStatsResponse) ProtoMessage() {}
+
+func (x *CheckStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analytics_api_analytics_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckStatsResponse.ProtoReflect.Descriptor instead.
+func (*CheckStatsResponse) Descriptor() ([]byte, []int) {
+	return file_analytics_api_analytics_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CheckStatsResponse) GetBuckets() []*CheckBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+var File_analytics_api_analytics_proto protoreflect.FileDescriptor
+
+const file_analytics_api_analytics_proto_rawDesc = "" +
+	"\n" +
+	"\x1danalytics_api/analytics.proto\x12\x15trackbox.analytics.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa9\x01\n" +
+	"\x10AnalyticsRequest\x12!\n" +
+	"\fcarrier_code\x18\x01 \x01(\tR\vcarrierCode\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x16\n" +
+	"\x06bucket\x18\x04 \x01(\tR\x06bucket\"f\n" +
+	"\vStatusCount\x12!\n" +
+	"\fcarrier_code\x18\x01 \x01(\tR\vcarrierCode\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1c\n" +
+	"\ttrackings\x18\x03 \x01(\x03R\ttrackings\"\xeb\x01\n" +
+	"\fStatusBucket\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12!\n" +
+	"\fcarrier_code\x18\x02 \x01(\tR\vcarrierCode\x12J\n" +
+	"\aentered\x18\x03 \x03(\v20.trackbox.analytics.v1.StatusBucket.EnteredEntryR\aentered\x1a:\n" +
+	"\fEnteredEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\x99\x01\n" +
+	"\x1aStatusDistributionResponse\x12<\n" +
+	"\acurrent\x18\x01 \x03(\v2\".trackbox.analytics.v1.StatusCountR\acurrent\x12=\n" +
+	"\abuckets\x18\x02 \x03(\v2#.trackbox.analytics.v1.StatusBucketR\abuckets\"\xce\x01\n" +
+	"\x13TransitTimesRequest\x12!\n" +
+	"\fcarrier_code\x18\x01 \x01(\tR\vcarrierCode\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x16\n" +
+	"\x06bucket\x18\x04 \x01(\tR\x06bucket\x12 \n" +
+	"\vpercentiles\x18\x05 \x03(\x01R\vpercentiles\"d\n" +
+	"\x11TransitPercentile\x12\x1e\n" +
+	"\n" +
+	"percentile\x18\x01 \x01(\x01R\n" +
+	"percentile\x12/\n" +
+	"\x05value\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x05value\"\xd0\x01\n" +
+	"\rTransitBucket\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12!\n" +
+	"\fcarrier_code\x18\x02 \x01(\tR\vcarrierCode\x12\x1e\n" +
+	"\n" +
+	"deliveries\x18\x03 \x01(\x03R\n" +
+	"deliveries\x12J\n" +
+	"\vpercentiles\x18\x04 \x03(\v2(.trackbox.analytics.v1.TransitPercentileR\vpercentiles\"V\n" +
+	"\x14TransitTimesResponse\x12>\n" +
+	"\abuckets\x18\x01 \x03(\v2$.trackbox.analytics.v1.TransitBucketR\abuckets\"\x87\x02\n" +
+	"\vCheckBucket\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12!\n" +
+	"\fcarrier_code\x18\x02 \x01(\tR\vcarrierCode\x12\x16\n" +
+	"\x06checks\x18\x03 \x01(\x03R\x06checks\x12#\n" +
+	"\rfailed_checks\x18\x04 \x01(\x03R\ffailedChecks\x12!\n" +
+	"\ffailure_rate\x18\x05 \x01(\x01R\vfailureRate\x12%\n" +
+	"\x0estatus_changes\x18\x06 \x01(\x03R\rstatusChanges\x12\x1c\n" +
+	"\tdelivered\x18\a \x01(\x03R\tdelivered\"R\n" +
+	"\x12CheckStatsResponse\x12<\n" +
+	"\abuckets\x18\x01 \x03(\v2\".trackbox.analytics.v1.CheckBucketR\abuckets2\xbf\x03\n" +
+	"\x10AnalyticsService\x12\x9b\x01\n" +
+	"\x15GetStatusDistribution\x12'.trackbox.analytics.v1.AnalyticsRequest\x1a1.trackbox.analytics.v1.StatusDistributionResponse\"&\x82\xd3\xe4\x93\x02 \x12\x1e/analytics/status-distribution\x12\x8c\x01\n" +
+	"\x0fGetTransitTimes\x12*.trackbox.analytics.v1.TransitTimesRequest\x1a+.trackbox.analytics.v1.TransitTimesResponse\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/analytics/transit-times\x12~\n" +
+	"\rGetCheckStats\x12'.trackbox.analytics.v1.AnalyticsRequest\x1a).trackbox.analytics.v1.CheckStatsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/analytics/checksB8Z6github.com/BearBump/TrackBox/internal/pb/analytics_apib\x06proto3"
+
+var (
+	file_analytics_api_analytics_proto_rawDescOnce sync.Once
+	file_analytics_api_analytics_proto_rawDescData []byte
+)
+
+func file_analytics_api_analytics_proto_rawDescGZIP() []byte {
+	file_analytics_api_analytics_proto_rawDescOnce.Do(func() {
+		file_analytics_api_analytics_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_analytics_api_analytics_proto_rawDesc), len(file_analytics_api_analytics_proto_rawDesc)))
+	})
+	return file_analytics_api_analytics_proto_rawDescData
+}
+
+var file_analytics_api_analytics_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_analytics_api_analytics_proto_goTypes = []any{
+	(*AnalyticsRequest)(nil),           // 0: trackbox.analytics.v1.AnalyticsRequest
+	(*StatusCount)(nil),                // 1: trackbox.analytics.v1.StatusCount
+	(*StatusBucket)(nil),               // 2: trackbox.analytics.v1.StatusBucket
+	(*StatusDistributionResponse)(nil), // 3: trackbox.analytics.v1.StatusDistributionResponse
+	(*TransitTimesRequest)(nil),        // 4: trackbox.analytics.v1.TransitTimesRequest
+	(*TransitPercentile)(nil),          // 5: trackbox.analytics.v1.TransitPercentile
+	(*TransitBucket)(nil),              // 6: trackbox.analytics.v1.TransitBucket
+	(*TransitTimesResponse)(nil),       // 7: trackbox.analytics.v1.TransitTimesResponse
+	(*CheckBucket)(nil),                // 8: trackbox.analytics.v1.CheckBucket
+	(*CheckStatsResponse)(nil),         // 9: trackbox.analytics.v1.CheckStatsResponse
+	nil,                                // 10: trackbox.analytics.v1.StatusBucket.EnteredEntry
+	(*timestamppb.Timestamp)(nil),      // 11: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),        // 12: google.protobuf.Duration
+}
+var file_analytics_api_analytics_proto_depIdxs = []int32{
+	11, // 0: trackbox.analytics.v1.AnalyticsRequest.from:type_name -> google.protobuf.Timestamp
+	11, // 1: trackbox.analytics.v1.AnalyticsRequest.to:type_name -> google.protobuf.Timestamp
+	11, // 2: trackbox.analytics.v1.StatusBucket.start:type_name -> google.protobuf.Timestamp
+	10, // 3: trackbox.analytics.v1.StatusBucket.entered:type_name -> trackbox.analytics.v1.StatusBucket.EnteredEntry
+	1,  // 4: trackbox.analytics.v1.StatusDistributionResponse.current:type_name -> trackbox.analytics.v1.StatusCount
+	2,  // 5: trackbox.analytics.v1.StatusDistributionResponse.buckets:type_name -> trackbox.analytics.v1.StatusBucket
+	11, // 6: trackbox.analytics.v1.TransitTimesRequest.from:type_name -> google.protobuf.Timestamp
+	11, // 7: trackbox.analytics.v1.TransitTimesRequest.to:type_name -> google.protobuf.Timestamp
+	12, // 8: trackbox.analytics.v1.TransitPercentile.value:type_name -> google.protobuf.Duration
+	11, // 9: trackbox.analytics.v1.TransitBucket.start:type_name -> google.protobuf.Timestamp
+	5,  // 10: trackbox.analytics.v1.TransitBucket.percentiles:type_name -> trackbox.analytics.v1.TransitPercentile
+	6,  // 11: trackbox.analytics.v1.TransitTimesResponse.buckets:type_name -> trackbox.analytics.v1.TransitBucket
+	11, // 12: trackbox.analytics.v1.CheckBucket.start:type_name -> google.protobuf.Timestamp
+	8,  // 13: trackbox.analytics.v1.CheckStatsResponse.buckets:type_name -> trackbox.analytics.v1.CheckBucket
+	0,  // 14: trackbox.analytics.v1.AnalyticsService.GetStatusDistribution:input_type -> trackbox.analytics.v1.AnalyticsRequest
+	4,  // 15: trackbox.analytics.v1.AnalyticsService.GetTransitTimes:input_type -> trackbox.analytics.v1.TransitTimesRequest
+	0,  // 16: trackbox.analytics.v1.AnalyticsService.GetCheckStats:input_type -> trackbox.analytics.v1.AnalyticsRequest
+	3,  // 17: trackbox.analytics.v1.AnalyticsService.GetStatusDistribution:output_type -> trackbox.analytics.v1.StatusDistributionResponse
+	7,  // 18: trackbox.analytics.v1.AnalyticsService.GetTransitTimes:output_type -> trackbox.analytics.v1.TransitTimesResponse
+	9,  // 19: trackbox.analytics.v1.AnalyticsService.GetCheckStats:output_type -> trackbox.analytics.v1.CheckStatsResponse
+	17, // [17:20] is the sub-list for method output_type
+	14, // [14:17] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_analytics_api_analytics_proto_init() }
+func file_analytics_api_analytics_proto_init() {
+	if File_analytics_api_analytics_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_analytics_api_analytics_proto_rawDesc), len(file_analytics_api_analytics_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_analytics_api_analytics_proto_goTypes,
+		DependencyIndexes: file_analytics_api_analytics_proto_depIdxs,
+		MessageInfos:      file_analytics_api_analytics_proto_msgTypes,
+	}.Build()
+	File_analytics_api_analytics_proto = out.File
+	file_analytics_api_analytics_proto_goTypes = nil
+	file_analytics_api_analytics_proto_depIdxs = nil
+}
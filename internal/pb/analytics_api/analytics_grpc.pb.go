@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.2
+// source: analytics_api/analytics.proto
+
+package analytics_api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AnalyticsService_GetStatusDistribution_FullMethodName = "/trackbox.analytics.v1.AnalyticsService/GetStatusDistribution"
+	AnalyticsService_GetTransitTimes_FullMethodName       = "/trackbox.analytics.v1.AnalyticsService/GetTransitTimes"
+	AnalyticsService_GetCheckStats_FullMethodName         = "/trackbox.analytics.v1.AnalyticsService/GetCheckStats"
+)
+
+// AnalyticsServiceClient is the client API for AnalyticsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Аналитика по перевозчикам. Считается по агрегатам, которые track-api ведёт при применении проверок
+// (дни — UTC), поэтому проверки и ошибки учитываются с момента появления агрегатов.
+type AnalyticsServiceClient interface {
+	// Сколько треков сейчас в каждом статусе и сколько переходило в статусы по интервалам.
+	GetStatusDistribution(ctx context.Context, in *AnalyticsRequest, opts ...grpc.CallOption) (*StatusDistributionResponse, error)
+	// Перцентили времени доставки (от первого события до «доставлено») по дню доставки.
+	GetTransitTimes(ctx context.Context, in *TransitTimesRequest, opts ...grpc.CallOption) (*TransitTimesResponse, error)
+	// Объём проверок, доля ошибок, смены статуса и доставки.
+	GetCheckStats(ctx context.Context, in *AnalyticsRequest, opts ...grpc.CallOption) (*CheckStatsResponse, error)
+}
+
+type analyticsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAnalyticsServiceClient(cc grpc.ClientConnInterface) AnalyticsServiceClient {
+	return &analyticsServiceClient{cc}
+}
+
+func (c *analyticsServiceClient) GetStatusDistribution(ctx context.Context, in *AnalyticsRequest, opts ...grpc.CallOption) (*StatusDistributionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusDistributionResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetStatusDistribution_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) GetTransitTimes(ctx context.Context, in *TransitTimesRequest, opts ...grpc.CallOption) (*TransitTimesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransitTimesResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetTransitTimes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyticsServiceClient) GetCheckStats(ctx context.Context, in *AnalyticsRequest, opts ...grpc.CallOption) (*CheckStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckStatsResponse)
+	err := c.cc.Invoke(ctx, AnalyticsService_GetCheckStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AnalyticsServiceServer is the server API for AnalyticsService service.
+// All implementations must embed UnimplementedAnalyticsServiceServer
+// for forward compatibility.
+//
+// Аналитика по перевозчикам. Считается по агрегатам, которые track-api ведёт при применении проверок
+// (дни — UTC), поэтому проверки и ошибки учитываются с момента появления агрегатов.
+type AnalyticsServiceServer interface {
+	// Сколько треков сейчас в каждом статусе и сколько переходило в статусы по интервалам.
+	GetStatusDistribution(context.Context, *AnalyticsRequest) (*StatusDistributionResponse, error)
+	// Перцентили времени доставки (от первого события до «доставлено») по дню доставки.
+	GetTransitTimes(context.Context, *TransitTimesRequest) (*TransitTimesResponse, error)
+	// Объём проверок, доля ошибок, смены статуса и доставки.
+	GetCheckStats(context.Context, *AnalyticsRequest) (*CheckStatsResponse, error)
+	mustEmbedUnimplementedAnalyticsServiceServer()
+}
+
+// UnimplementedAnalyticsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAnalyticsServiceServer struct{}
+
+func (UnimplementedAnalyticsServiceServer) GetStatusDistribution(context.Context, *AnalyticsRequest) (*StatusDistributionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStatusDistribution not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetTransitTimes(context.Context, *TransitTimesRequest) (*TransitTimesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTransitTimes not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) GetCheckStats(context.Context, *AnalyticsRequest) (*CheckStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCheckStats not implemented")
+}
+func (UnimplementedAnalyticsServiceServer) mustEmbedUnimplementedAnalyticsServiceServer() {}
+func (UnimplementedAnalyticsServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeAnalyticsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AnalyticsServiceServer will
+// result in compilation errors.
+type UnsafeAnalyticsServiceServer interface {
+	mustEmbedUnimplementedAnalyticsServiceServer()
+}
+
+func RegisterAnalyticsServiceServer(s grpc.ServiceRegistrar, srv AnalyticsServiceServer) {
+	// If the following call panics, it indicates UnimplementedAnalyticsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AnalyticsService_ServiceDesc, srv)
+}
+
+func _AnalyticsService_GetStatusDistribution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetStatusDistribution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetStatusDistribution_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetStatusDistribution(ctx, req.(*AnalyticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_GetTransitTimes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitTimesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetTransitTimes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetTransitTimes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetTransitTimes(ctx, req.(*TransitTimesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyticsService_GetCheckStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyticsServiceServer).GetCheckStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyticsService_GetCheckStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyticsServiceServer).GetCheckStats(ctx, req.(*AnalyticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AnalyticsService_ServiceDesc is the grpc.ServiceDesc for AnalyticsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AnalyticsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "trackbox.analytics.v1.AnalyticsService",
+	HandlerType: (*AnalyticsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatusDistribution",
+			Handler:    _AnalyticsService_GetStatusDistribution_Handler,
+		},
+		{
+			MethodName: "GetTransitTimes",
+			Handler:    _AnalyticsService_GetTransitTimes_Handler,
+		},
+		{
+			MethodName: "GetCheckStats",
+			Handler:    _AnalyticsService_GetCheckStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "analytics_api/analytics.proto",
+}
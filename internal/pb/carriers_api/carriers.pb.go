@@ -0,0 +1,471 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.2
+// source: carriers_api/carriers.proto
+
+package carriers_api
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Carrier struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Code        string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	DisplayName string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// "" (клиент из конфига worker'а) | emulator_v1 | track24 | fake
+	Backend string `protobuf:"bytes,3,opt,name=backend,proto3" json:"backend,omitempty"`
+	// Ссылка на API-ключ: env:NAME | file:/path. Сам ключ в БД не хранится.
+	CredentialsRef string `protobuf:"bytes,4,opt,name=credentials_ref,json=credentialsRef,proto3" json:"credentials_ref,omitempty"`
+	// 0 — общий лимит worker'а.
+	RateLimitPerMinute int32 `protobuf:"varint,5,opt,name=rate_limit_per_minute,json=rateLimitPerMinute,proto3" json:"rate_limit_per_minute,omitempty"`
+	// Политика планирования в формате scheduling.carriers.<code> конфига (JSON); пусто — из конфига.
+	SchedulingJson string                 `protobuf:"bytes,6,opt,name=scheduling_json,json=schedulingJson,proto3" json:"scheduling_json,omitempty"`
+	Enabled        bool                   `protobuf:"varint,7,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Carrier) Reset() {
+	*x = Carrier{}
+	mi := &file_carriers_api_carriers_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Carrier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Carrier) ProtoMessage() {}
+
+func (x *Carrier) ProtoReflect() protoreflect.Message {
+	mi := &file_carriers_api_carriers_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Carrier.ProtoReflect.Descriptor instead.
+func (*Carrier) Descriptor() ([]byte, []int) {
+	return file_carriers_api_carriers_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Carrier) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Carrier) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *Carrier) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *Carrier) GetCredentialsRef() string {
+	if x != nil {
+		return x.CredentialsRef
+	}
+	return ""
+}
+
+func (x *Carrier) GetRateLimitPerMinute() int32 {
+	if x != nil {
+		return x.RateLimitPerMinute
+	}
+	return 0
+}
+
+func (x *Carrier) GetSchedulingJson() string {
+	if x != nil {
+		return x.SchedulingJson
+	}
+	return ""
+}
+
+func (x *Carrier) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Carrier) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Carrier) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListCarriersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCarriersRequest) Reset() {
+	*x = ListCarriersRequest{}
+	mi := &file_carriers_api_carriers_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCarriersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarriersRequest) ProtoMessage() {}
+
+func (x *ListCarriersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carriers_api_carriers_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarriersRequest.ProtoReflect.Descriptor instead.
+func (*ListCarriersRequest) Descriptor() ([]byte, []int) {
+	return file_carriers_api_carriers_proto_rawDescGZIP(), []int{1}
+}
+
+type ListCarriersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Carriers      []*Carrier             `protobuf:"bytes,1,rep,name=carriers,proto3" json:"carriers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCarriersResponse) Reset() {
+	*x = ListCarriersResponse{}
+	mi := &file_carriers_api_carriers_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCarriersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarriersResponse) ProtoMessage() {}
+
+func (x *ListCarriersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_carriers_api_carriers_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarriersResponse.ProtoReflect.Descriptor instead.
+func (*ListCarriersResponse) Descriptor() ([]byte, []int) {
+	return file_carriers_api_carriers_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListCarriersResponse) GetCarriers() []*Carrier {
+	if x != nil {
+		return x.Carriers
+	}
+	return nil
+}
+
+type CreateCarrierRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Carrier       *Carrier               `protobuf:"bytes,1,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCarrierRequest) Reset() {
+	*x = CreateCarrierRequest{}
+	mi := &file_carriers_api_carriers_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCarrierRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCarrierRequest) ProtoMessage() {}
+
+func (x *CreateCarrierRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carriers_api_carriers_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCarrierRequest.ProtoReflect.Descriptor instead.
+func (*CreateCarrierRequest) Descriptor() ([]byte, []int) {
+	return file_carriers_api_carriers_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateCarrierRequest) GetCarrier() *Carrier {
+	if x != nil {
+		return x.Carrier
+	}
+	return nil
+}
+
+type UpdateCarrierRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Code    string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Carrier *Carrier               `protobuf:"bytes,2,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	// Поля carrier, которые нужно изменить: display_name, backend, credentials_ref, rate_limit_per_minute,
+	// scheduling_json, enabled. Пустая маска — все непустые поля carrier (enabled — только true).
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCarrierRequest) Reset() {
+	*x = UpdateCarrierRequest{}
+	mi := &file_carriers_api_carriers_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCarrierRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCarrierRequest) ProtoMessage() {}
+
+func (x *UpdateCarrierRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carriers_api_carriers_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCarrierRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCarrierRequest) Descriptor() ([]byte, []int) {
+	return file_carriers_api_carriers_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateCarrierRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *UpdateCarrierRequest) GetCarrier() *Carrier {
+	if x != nil {
+		return x.Carrier
+	}
+	return nil
+}
+
+func (x *UpdateCarrierRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type DisableCarrierRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableCarrierRequest) Reset() {
+	*x = DisableCarrierRequest{}
+	mi := &file_carriers_api_carriers_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableCarrierRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableCarrierRequest) ProtoMessage() {}
+
+func (x *DisableCarrierRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carriers_api_carriers_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableCarrierRequest.ProtoReflect.Descriptor instead.
+func (*DisableCarrierRequest) Descriptor() ([]byte, []int) {
+	return file_carriers_api_carriers_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DisableCarrierRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+var File_carriers_api_carriers_proto protoreflect.FileDescriptor
+
+const file_carriers_api_carriers_proto_rawDesc = "" +
+	"\n" +
+	"\x1bcarriers_api/carriers.proto\x12\x14trackbox.carriers.v1\x1a\x1cgoogle/api/annotations.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xef\x02\n" +
+	"\aCarrier\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12\x18\n" +
+	"\abackend\x18\x03 \x01(\tR\abackend\x12'\n" +
+	"\x0fcredentials_ref\x18\x04 \x01(\tR\x0ecredentialsRef\x121\n" +
+	"\x15rate_limit_per_minute\x18\x05 \x01(\x05R\x12rateLimitPerMinute\x12'\n" +
+	"\x0fscheduling_json\x18\x06 \x01(\tR\x0eschedulingJson\x12\x18\n" +
+	"\aenabled\x18\a \x01(\bR\aenabled\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x15\n" +
+	"\x13ListCarriersRequest\"Q\n" +
+	"\x14ListCarriersResponse\x129\n" +
+	"\bcarriers\x18\x01 \x03(\v2\x1d.trackbox.carriers.v1.CarrierR\bcarriers\"O\n" +
+	"\x14CreateCarrierRequest\x127\n" +
+	"\acarrier\x18\x01 \x01(\v2\x1d.trackbox.carriers.v1.CarrierR\acarrier\"\xa0\x01\n" +
+	"\x14UpdateCarrierRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x127\n" +
+	"\acarrier\x18\x02 \x01(\v2\x1d.trackbox.carriers.v1.CarrierR\acarrier\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"+\n" +
+	"\x15DisableCarrierRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code2\xbf\x04\n" +
+	"\x0fCarriersService\x12~\n" +
+	"\fListCarriers\x12).trackbox.carriers.v1.ListCarriersRequest\x1a*.trackbox.carriers.v1.ListCarriersResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/admin/carriers\x12|\n" +
+	"\rCreateCarrier\x12*.trackbox.carriers.v1.CreateCarrierRequest\x1a\x1d.trackbox.carriers.v1.Carrier\" \x82\xd3\xe4\x93\x02\x1a:\acarrier\"\x0f/admin/carriers\x12\xa6\x01\n" +
+	"\rUpdateCarrier\x12*.trackbox.carriers.v1.UpdateCarrierRequest\x1a\x1d.trackbox.carriers.v1.Carrier\"J\x82\xd3\xe4\x93\x02D:\acarrierZ!:\acarrier\x1a\x16/admin/carriers/{code}2\x16/admin/carriers/{code}\x12\x84\x01\n" +
+	"\x0eDisableCarrier\x12+.trackbox.carriers.v1.DisableCarrierRequest\x1a\x1d.trackbox.carriers.v1.Carrier\"&\x82\xd3\xe4\x93\x02 \"\x1e/admin/carriers/{code}/disableB7Z5github.com/BearBump/TrackBox/internal/pb/carriers_apib\x06proto3"
+
+var (
+	file_carriers_api_carriers_proto_rawDescOnce sync.Once
+	file_carriers_api_carriers_proto_rawDescData []byte
+)
+
+func file_carriers_api_carriers_proto_rawDescGZIP() []byte {
+	file_carriers_api_carriers_proto_rawDescOnce.Do(func() {
+		file_carriers_api_carriers_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_carriers_api_carriers_proto_rawDesc), len(file_carriers_api_carriers_proto_rawDesc)))
+	})
+	return file_carriers_api_carriers_proto_rawDescData
+}
+
+var file_carriers_api_carriers_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_carriers_api_carriers_proto_goTypes = []any{
+	(*Carrier)(nil),               // 0: trackbox.carriers.v1.Carrier
+	(*ListCarriersRequest)(nil),   // 1: trackbox.carriers.v1.ListCarriersRequest
+	(*ListCarriersResponse)(nil),  // 2: trackbox.carriers.v1.ListCarriersResponse
+	(*CreateCarrierRequest)(nil),  // 3: trackbox.carriers.v1.CreateCarrierRequest
+	(*UpdateCarrierRequest)(nil),  // 4: trackbox.carriers.v1.UpdateCarrierRequest
+	(*DisableCarrierRequest)(nil), // 5: trackbox.carriers.v1.DisableCarrierRequest
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil), // 7: google.protobuf.FieldMask
+}
+var file_carriers_api_carriers_proto_depIdxs = []int32{
+	6,  // 0: trackbox.carriers.v1.Carrier.created_at:type_name -> google.protobuf.Timestamp
+	6,  // 1: trackbox.carriers.v1.Carrier.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: trackbox.carriers.v1.ListCarriersResponse.carriers:type_name -> trackbox.carriers.v1.Carrier
+	0,  // 3: trackbox.carriers.v1.CreateCarrierRequest.carrier:type_name -> trackbox.carriers.v1.Carrier
+	0,  // 4: trackbox.carriers.v1.UpdateCarrierRequest.carrier:type_name -> trackbox.carriers.v1.Carrier
+	7,  // 5: trackbox.carriers.v1.UpdateCarrierRequest.update_mask:type_name -> google.protobuf.FieldMask
+	1,  // 6: trackbox.carriers.v1.CarriersService.ListCarriers:input_type -> trackbox.carriers.v1.ListCarriersRequest
+	3,  // 7: trackbox.carriers.v1.CarriersService.CreateCarrier:input_type -> trackbox.carriers.v1.CreateCarrierRequest
+	4,  // 8: trackbox.carriers.v1.CarriersService.UpdateCarrier:input_type -> trackbox.carriers.v1.UpdateCarrierRequest
+	5,  // 9: trackbox.carriers.v1.CarriersService.DisableCarrier:input_type -> trackbox.carriers.v1.DisableCarrierRequest
+	2,  // 10: trackbox.carriers.v1.CarriersService.ListCarriers:output_type -> trackbox.carriers.v1.ListCarriersResponse
+	0,  // 11: trackbox.carriers.v1.CarriersService.CreateCarrier:output_type -> trackbox.carriers.v1.Carrier
+	0,  // 12: trackbox.carriers.v1.CarriersService.UpdateCarrier:output_type -> trackbox.carriers.v1.Carrier
+	0,  // 13: trackbox.carriers.v1.CarriersService.DisableCarrier:output_type -> trackbox.carriers.v1.Carrier
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_carriers_api_carriers_proto_init() }
+func file_carriers_api_carriers_proto_init() {
+	if File_carriers_api_carriers_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_carriers_api_carriers_proto_rawDesc), len(file_carriers_api_carriers_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_carriers_api_carriers_proto_goTypes,
+		DependencyIndexes: file_carriers_api_carriers_proto_depIdxs,
+		MessageInfos:      file_carriers_api_carriers_proto_msgTypes,
+	}.Build()
+	File_carriers_api_carriers_proto = out.File
+	file_carriers_api_carriers_proto_goTypes = nil
+	file_carriers_api_carriers_proto_depIdxs = nil
+}
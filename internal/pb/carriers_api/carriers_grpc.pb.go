@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.2
+// source: carriers_api/carriers.proto
+
+package carriers_api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CarriersService_ListCarriers_FullMethodName   = "/trackbox.carriers.v1.CarriersService/ListCarriers"
+	CarriersService_CreateCarrier_FullMethodName  = "/trackbox.carriers.v1.CarriersService/CreateCarrier"
+	CarriersService_UpdateCarrier_FullMethodName  = "/trackbox.carriers.v1.CarriersService/UpdateCarrier"
+	CarriersService_DisableCarrier_FullMethodName = "/trackbox.carriers.v1.CarriersService/DisableCarrier"
+)
+
+// CarriersServiceClient is the client API for CarriersService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Админский справочник перевозчиков. track-api и track-worker перечитывают его на лету
+// (carriers_refresh_seconds), так что новый перевозчик не требует изменений кода.
+type CarriersServiceClient interface {
+	ListCarriers(ctx context.Context, in *ListCarriersRequest, opts ...grpc.CallOption) (*ListCarriersResponse, error)
+	CreateCarrier(ctx context.Context, in *CreateCarrierRequest, opts ...grpc.CallOption) (*Carrier, error)
+	// Частичное обновление: меняются только поля из update_mask, остальные остаются как были.
+	// HTTP PATCH берёт маску из ключей JSON-тела (можно передать "enabled": false); PUT оставлен
+	// для совместимости и без маски меняет только непустые поля.
+	UpdateCarrier(ctx context.Context, in *UpdateCarrierRequest, opts ...grpc.CallOption) (*Carrier, error)
+	// Отключённый перевозчик не принимает новые треки, worker перестаёт проверять существующие.
+	DisableCarrier(ctx context.Context, in *DisableCarrierRequest, opts ...grpc.CallOption) (*Carrier, error)
+}
+
+type carriersServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCarriersServiceClient(cc grpc.ClientConnInterface) CarriersServiceClient {
+	return &carriersServiceClient{cc}
+}
+
+func (c *carriersServiceClient) ListCarriers(ctx context.Context, in *ListCarriersRequest, opts ...grpc.CallOption) (*ListCarriersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCarriersResponse)
+	err := c.cc.Invoke(ctx, CarriersService_ListCarriers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carriersServiceClient) CreateCarrier(ctx context.Context, in *CreateCarrierRequest, opts ...grpc.CallOption) (*Carrier, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Carrier)
+	err := c.cc.Invoke(ctx, CarriersService_CreateCarrier_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carriersServiceClient) UpdateCarrier(ctx context.Context, in *UpdateCarrierRequest, opts ...grpc.CallOption) (*Carrier, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Carrier)
+	err := c.cc.Invoke(ctx, CarriersService_UpdateCarrier_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carriersServiceClient) DisableCarrier(ctx context.Context, in *DisableCarrierRequest, opts ...grpc.CallOption) (*Carrier, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Carrier)
+	err := c.cc.Invoke(ctx, CarriersService_DisableCarrier_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CarriersServiceServer is the server API for CarriersService service.
+// All implementations must embed UnimplementedCarriersServiceServer
+// for forward compatibility.
+//
+// Админский справочник перевозчиков. track-api и track-worker перечитывают его на лету
+// (carriers_refresh_seconds), так что новый перевозчик не требует изменений кода.
+type CarriersServiceServer interface {
+	ListCarriers(context.Context, *ListCarriersRequest) (*ListCarriersResponse, error)
+	CreateCarrier(context.Context, *CreateCarrierRequest) (*Carrier, error)
+	// Частичное обновление: меняются только поля из update_mask, остальные остаются как были.
+	// HTTP PATCH берёт маску из ключей JSON-тела (можно передать "enabled": false); PUT оставлен
+	// для совместимости и без маски меняет только непустые поля.
+	UpdateCarrier(context.Context, *UpdateCarrierRequest) (*Carrier, error)
+	// Отключённый перевозчик не принимает новые треки, worker перестаёт проверять существующие.
+	DisableCarrier(context.Context, *DisableCarrierRequest) (*Carrier, error)
+	mustEmbedUnimplementedCarriersServiceServer()
+}
+
+// UnimplementedCarriersServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCarriersServiceServer struct{}
+
+func (UnimplementedCarriersServiceServer) ListCarriers(context.Context, *ListCarriersRequest) (*ListCarriersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListCarriers not implemented")
+}
+func (UnimplementedCarriersServiceServer) CreateCarrier(context.Context, *CreateCarrierRequest) (*Carrier, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateCarrier not implemented")
+}
+func (UnimplementedCarriersServiceServer) UpdateCarrier(context.Context, *UpdateCarrierRequest) (*Carrier, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateCarrier not implemented")
+}
+func (UnimplementedCarriersServiceServer) DisableCarrier(context.Context, *DisableCarrierRequest) (*Carrier, error) {
+	return nil, status.Error(codes.Unimplemented, "method DisableCarrier not implemented")
+}
+func (UnimplementedCarriersServiceServer) mustEmbedUnimplementedCarriersServiceServer() {}
+func (UnimplementedCarriersServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeCarriersServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CarriersServiceServer will
+// result in compilation errors.
+type UnsafeCarriersServiceServer interface {
+	mustEmbedUnimplementedCarriersServiceServer()
+}
+
+func RegisterCarriersServiceServer(s grpc.ServiceRegistrar, srv CarriersServiceServer) {
+	// If the following call panics, it indicates UnimplementedCarriersServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CarriersService_ServiceDesc, srv)
+}
+
+func _CarriersService_ListCarriers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCarriersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarriersServiceServer).ListCarriers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarriersService_ListCarriers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarriersServiceServer).ListCarriers(ctx, req.(*ListCarriersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarriersService_CreateCarrier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCarrierRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarriersServiceServer).CreateCarrier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarriersService_CreateCarrier_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarriersServiceServer).CreateCarrier(ctx, req.(*CreateCarrierRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarriersService_UpdateCarrier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCarrierRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarriersServiceServer).UpdateCarrier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarriersService_UpdateCarrier_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarriersServiceServer).UpdateCarrier(ctx, req.(*UpdateCarrierRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarriersService_DisableCarrier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableCarrierRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarriersServiceServer).DisableCarrier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarriersService_DisableCarrier_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarriersServiceServer).DisableCarrier(ctx, req.(*DisableCarrierRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CarriersService_ServiceDesc is the grpc.ServiceDesc for CarriersService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CarriersService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "trackbox.carriers.v1.CarriersService",
+	HandlerType: (*CarriersServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListCarriers",
+			Handler:    _CarriersService_ListCarriers_Handler,
+		},
+		{
+			MethodName: "CreateCarrier",
+			Handler:    _CarriersService_CreateCarrier_Handler,
+		},
+		{
+			MethodName: "UpdateCarrier",
+			Handler:    _CarriersService_UpdateCarrier_Handler,
+		},
+		{
+			MethodName: "DisableCarrier",
+			Handler:    _CarriersService_DisableCarrier_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "carriers_api/carriers.proto",
+}
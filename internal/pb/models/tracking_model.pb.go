@@ -32,8 +32,19 @@ type TrackingEvent struct {
 	Location   string                 `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"`
 	Message    string                 `protobuf:"bytes,7,opt,name=message,proto3" json:"message,omitempty"`
 	// В protobuf это string (JSON), чтобы не тащить structpb/any.
-	PayloadJson   string                 `protobuf:"bytes,8,opt,name=payload_json,json=payloadJson,proto3" json:"payload_json,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	PayloadJson string                 `protobuf:"bytes,8,opt,name=payload_json,json=payloadJson,proto3" json:"payload_json,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Место события в структурированном виде; нет — неизвестно. location — текст перевозчика как есть.
+	Place *EventLocation `protobuf:"bytes,10,opt,name=place,proto3" json:"place,omitempty"`
+	// Время события строкой, как его прислал перевозчик (местное время без пояса); пусто — перевозчик прислал время с поясом.
+	EventTimeRaw string `protobuf:"bytes,11,opt,name=event_time_raw,json=eventTimeRaw,proto3" json:"event_time_raw,omitempty"`
+	// true — время у перевозчика не разобралось, event_time подставлен по соседним событиям.
+	TimeInferred bool `protobuf:"varint,12,opt,name=time_inferred,json=timeInferred,proto3" json:"time_inferred,omitempty"`
+	// Сколько раз перевозчик исправлял событие (см. ListEventRevisions).
+	Revision int32 `protobuf:"varint,13,opt,name=revision,proto3" json:"revision,omitempty"`
+	// Когда событие пропало из ответа перевозчика; заполнено только в ленте изменений (ListChanges) —
+	// в остальных ответах такие события не отдаются.
+	RemovedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=removed_at,json=removedAt,proto3" json:"removed_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -131,6 +142,126 @@ func (x *TrackingEvent) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *TrackingEvent) GetPlace() *EventLocation {
+	if x != nil {
+		return x.Place
+	}
+	return nil
+}
+
+func (x *TrackingEvent) GetEventTimeRaw() string {
+	if x != nil {
+		return x.EventTimeRaw
+	}
+	return ""
+}
+
+func (x *TrackingEvent) GetTimeInferred() bool {
+	if x != nil {
+		return x.TimeInferred
+	}
+	return false
+}
+
+func (x *TrackingEvent) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *TrackingEvent) GetRemovedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RemovedAt
+	}
+	return nil
+}
+
+// Место события (от перевозчика, дополненное офлайн-справочником); пустое поле — неизвестно.
+type EventLocation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Country       string                 `protobuf:"bytes,1,opt,name=country,proto3" json:"country,omitempty"` // ISO 3166-1 alpha-2
+	Region        string                 `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	City          string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,4,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Lat           *float64               `protobuf:"fixed64,5,opt,name=lat,proto3,oneof" json:"lat,omitempty"`
+	Lon           *float64               `protobuf:"fixed64,6,opt,name=lon,proto3,oneof" json:"lon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventLocation) Reset() {
+	*x = EventLocation{}
+	mi := &file_models_tracking_model_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventLocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventLocation) ProtoMessage() {}
+
+func (x *EventLocation) ProtoReflect() protoreflect.Message {
+	mi := &file_models_tracking_model_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventLocation.ProtoReflect.Descriptor instead.
+func (*EventLocation) Descriptor() ([]byte, []int) {
+	return file_models_tracking_model_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EventLocation) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *EventLocation) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *EventLocation) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *EventLocation) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *EventLocation) GetLat() float64 {
+	if x != nil && x.Lat != nil {
+		return *x.Lat
+	}
+	return 0
+}
+
+func (x *EventLocation) GetLon() float64 {
+	if x != nil && x.Lon != nil {
+		return *x.Lon
+	}
+	return 0
+}
+
 type Tracking struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -145,25 +276,642 @@ type Tracking struct {
 	LastError      string                 `protobuf:"bytes,10,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
 	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Пользовательские атрибуты: произвольный JSON-объект, теги и id во внешней системе.
+	MetadataJson string   `protobuf:"bytes,13,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
+	Tags         []string `protobuf:"bytes,14,rep,name=tags,proto3" json:"tags,omitempty"`
+	ExternalId   string   `protobuf:"bytes,15,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	// Последние сведения об отправлении от перевозчика; нет — перевозчик их не отдавал.
+	Shipment      *ShipmentDetails `protobuf:"bytes,16,opt,name=shipment,proto3" json:"shipment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tracking) Reset() {
+	*x = Tracking{}
+	mi := &file_models_tracking_model_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tracking) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tracking) ProtoMessage() {}
+
+func (x *Tracking) ProtoReflect() protoreflect.Message {
+	mi := &file_models_tracking_model_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tracking.ProtoReflect.Descriptor instead.
+func (*Tracking) Descriptor() ([]byte, []int) {
+	return file_models_tracking_model_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Tracking) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Tracking) GetCarrierCode() string {
+	if x != nil {
+		return x.CarrierCode
+	}
+	return ""
+}
+
+func (x *Tracking) GetTrackNumber() string {
+	if x != nil {
+		return x.TrackNumber
+	}
+	return ""
+}
+
+func (x *Tracking) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Tracking) GetStatusRaw() string {
+	if x != nil {
+		return x.StatusRaw
+	}
+	return ""
+}
+
+func (x *Tracking) GetStatusAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StatusAt
+	}
+	return nil
+}
+
+func (x *Tracking) GetLastCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastCheckedAt
+	}
+	return nil
+}
+
+func (x *Tracking) GetNextCheckAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextCheckAt
+	}
+	return nil
+}
+
+func (x *Tracking) GetCheckFailCount() int32 {
+	if x != nil {
+		return x.CheckFailCount
+	}
+	return 0
+}
+
+func (x *Tracking) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *Tracking) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Tracking) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Tracking) GetMetadataJson() string {
+	if x != nil {
+		return x.MetadataJson
+	}
+	return ""
+}
+
+func (x *Tracking) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Tracking) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *Tracking) GetShipment() *ShipmentDetails {
+	if x != nil {
+		return x.Shipment
+	}
+	return nil
+}
+
+// Сведения об отправлении; пустое поле — неизвестно.
+type ShipmentDetails struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	EstimatedDelivery *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=estimated_delivery,json=estimatedDelivery,proto3" json:"estimated_delivery,omitempty"`
+	WeightGrams       int32                  `protobuf:"varint,2,opt,name=weight_grams,json=weightGrams,proto3" json:"weight_grams,omitempty"`
+	Origin            string                 `protobuf:"bytes,3,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination       string                 `protobuf:"bytes,4,opt,name=destination,proto3" json:"destination,omitempty"`
+	RecipientCity     string                 `protobuf:"bytes,5,opt,name=recipient_city,json=recipientCity,proto3" json:"recipient_city,omitempty"`
+	ServiceType       string                 `protobuf:"bytes,6,opt,name=service_type,json=serviceType,proto3" json:"service_type,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ShipmentDetails) Reset() {
+	*x = ShipmentDetails{}
+	mi := &file_models_tracking_model_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShipmentDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShipmentDetails) ProtoMessage() {}
+
+func (x *ShipmentDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_models_tracking_model_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShipmentDetails.ProtoReflect.Descriptor instead.
+func (*ShipmentDetails) Descriptor() ([]byte, []int) {
+	return file_models_tracking_model_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ShipmentDetails) GetEstimatedDelivery() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedDelivery
+	}
+	return nil
+}
+
+func (x *ShipmentDetails) GetWeightGrams() int32 {
+	if x != nil {
+		return x.WeightGrams
+	}
+	return 0
+}
+
+func (x *ShipmentDetails) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *ShipmentDetails) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *ShipmentDetails) GetRecipientCity() string {
+	if x != nil {
+		return x.RecipientCity
+	}
+	return ""
+}
+
+func (x *ShipmentDetails) GetServiceType() string {
+	if x != nil {
+		return x.ServiceType
+	}
+	return ""
+}
+
+// Смена ожидаемой даты доставки.
+type EtaChange struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	EstimatedDelivery *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=estimated_delivery,json=estimatedDelivery,proto3" json:"estimated_delivery,omitempty"`
+	// Нет — первая известная ETA.
+	PreviousEstimatedDelivery *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=previous_estimated_delivery,json=previousEstimatedDelivery,proto3" json:"previous_estimated_delivery,omitempty"`
+	ObservedAt                *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=observed_at,json=observedAt,proto3" json:"observed_at,omitempty"`
+	// Сдвиг относительно предыдущей ETA (положительный — позже).
+	SlipSeconds   int64 `protobuf:"varint,4,opt,name=slip_seconds,json=slipSeconds,proto3" json:"slip_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EtaChange) Reset() {
+	*x = EtaChange{}
+	mi := &file_models_tracking_model_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EtaChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EtaChange) ProtoMessage() {}
+
+func (x *EtaChange) ProtoReflect() protoreflect.Message {
+	mi := &file_models_tracking_model_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EtaChange.ProtoReflect.Descriptor instead.
+func (*EtaChange) Descriptor() ([]byte, []int) {
+	return file_models_tracking_model_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EtaChange) GetEstimatedDelivery() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedDelivery
+	}
+	return nil
+}
+
+func (x *EtaChange) GetPreviousEstimatedDelivery() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PreviousEstimatedDelivery
+	}
+	return nil
+}
+
+func (x *EtaChange) GetObservedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ObservedAt
+	}
+	return nil
+}
+
+func (x *EtaChange) GetSlipSeconds() int64 {
+	if x != nil {
+		return x.SlipSeconds
+	}
+	return 0
+}
+
+// Проверка трека worker'ом (журнал проверок).
+type TrackingCheck struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	CheckedAt  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	// status_changed | events_changed | checked_no_change | check_failed; пусто — неизвестно (старый worker).
+	Kind   string `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Status string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Error  string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// TIMEOUT, NETWORK, RATE_LIMITED, HTTP, DECODE, CARRIER, OTHER.
+	ErrorClass string `protobuf:"bytes,6,opt,name=error_class,json=errorClass,proto3" json:"error_class,omitempty"`
+	// Экземпляр track-worker и бэкенд перевозчика (emulator_v1, track24, fake).
+	Worker    string `protobuf:"bytes,7,opt,name=worker,proto3" json:"worker,omitempty"`
+	Backend   string `protobuf:"bytes,8,opt,name=backend,proto3" json:"backend,omitempty"`
+	LatencyMs int64  `protobuf:"varint,9,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	// 0 — ответа не было или бэкенд не по HTTP.
+	HttpStatus int32 `protobuf:"varint,10,opt,name=http_status,json=httpStatus,proto3" json:"http_status,omitempty"`
+	// Событий в ответе перевозчика.
+	EventsCount int32 `protobuf:"varint,11,opt,name=events_count,json=eventsCount,proto3" json:"events_count,omitempty"`
+	// Хэш тела ответа: одинаковый хэш — перевозчик отдал то же самое.
+	ResponseHash string `protobuf:"bytes,12,opt,name=response_hash,json=responseHash,proto3" json:"response_hash,omitempty"`
+	// Ключ сырого ответа в архиве ответов (trackbox.response_archive); пусто — ответ не сохранялся.
+	ResponseRef   string `protobuf:"bytes,13,opt,name=response_ref,json=responseRef,proto3" json:"response_ref,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackingCheck) Reset() {
+	*x = TrackingCheck{}
+	mi := &file_models_tracking_model_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackingCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackingCheck) ProtoMessage() {}
+
+func (x *TrackingCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_models_tracking_model_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackingCheck.ProtoReflect.Descriptor instead.
+func (*TrackingCheck) Descriptor() ([]byte, []int) {
+	return file_models_tracking_model_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TrackingCheck) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+func (x *TrackingCheck) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+func (x *TrackingCheck) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *TrackingCheck) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TrackingCheck) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TrackingCheck) GetErrorClass() string {
+	if x != nil {
+		return x.ErrorClass
+	}
+	return ""
+}
+
+func (x *TrackingCheck) GetWorker() string {
+	if x != nil {
+		return x.Worker
+	}
+	return ""
+}
+
+func (x *TrackingCheck) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *TrackingCheck) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *TrackingCheck) GetHttpStatus() int32 {
+	if x != nil {
+		return x.HttpStatus
+	}
+	return 0
+}
+
+func (x *TrackingCheck) GetEventsCount() int32 {
+	if x != nil {
+		return x.EventsCount
+	}
+	return 0
+}
+
+func (x *TrackingCheck) GetResponseHash() string {
+	if x != nil {
+		return x.ResponseHash
+	}
+	return ""
+}
+
+func (x *TrackingCheck) GetResponseRef() string {
+	if x != nil {
+		return x.ResponseRef
+	}
+	return ""
+}
+
+// Изменение сохранённого события на стороне перевозчика.
+type EventRevision struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	EventId uint64                 `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// EDITED — исправлены время, место или текст; REMOVED — событие пропало из ответа; RESTORED — вернулось.
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Событие до изменения (status, status_raw, event_time, location, message).
+	Previous      *TrackingEvent         `protobuf:"bytes,3,opt,name=previous,proto3" json:"previous,omitempty"`
+	ObservedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=observed_at,json=observedAt,proto3" json:"observed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventRevision) Reset() {
+	*x = EventRevision{}
+	mi := &file_models_tracking_model_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventRevision) ProtoMessage() {}
+
+func (x *EventRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_models_tracking_model_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventRevision.ProtoReflect.Descriptor instead.
+func (*EventRevision) Descriptor() ([]byte, []int) {
+	return file_models_tracking_model_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EventRevision) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *EventRevision) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *EventRevision) GetPrevious() *TrackingEvent {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+func (x *EventRevision) GetObservedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ObservedAt
+	}
+	return nil
+}
+
+// Таймлайн трека: этапы доставки и события без повторов.
+type TrackingTimeline struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	// Последний достигнутый этап (ACCEPTED, IN_TRANSIT, ARRIVED_AT_DESTINATION, OUT_FOR_DELIVERY, DELIVERED);
+	// пусто — событий ещё нет.
+	CurrentStage string `protobuf:"bytes,2,opt,name=current_stage,json=currentStage,proto3" json:"current_stage,omitempty"`
+	// Все этапы по порядку, включая ещё не достигнутые.
+	Milestones []*Milestone     `protobuf:"bytes,3,rep,name=milestones,proto3" json:"milestones,omitempty"`
+	Entries    []*TimelineEntry `protobuf:"bytes,4,rep,name=entries,proto3" json:"entries,omitempty"`
+	// От первого события до вручения (или до текущего момента).
+	ElapsedSeconds int64 `protobuf:"varint,5,opt,name=elapsed_seconds,json=elapsedSeconds,proto3" json:"elapsed_seconds,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *Tracking) Reset() {
-	*x = Tracking{}
-	mi := &file_models_tracking_model_proto_msgTypes[1]
+func (x *TrackingTimeline) Reset() {
+	*x = TrackingTimeline{}
+	mi := &file_models_tracking_model_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackingTimeline) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackingTimeline) ProtoMessage() {}
+
+func (x *TrackingTimeline) ProtoReflect() protoreflect.Message {
+	mi := &file_models_tracking_model_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackingTimeline.ProtoReflect.Descriptor instead.
+func (*TrackingTimeline) Descriptor() ([]byte, []int) {
+	return file_models_tracking_model_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *TrackingTimeline) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+func (x *TrackingTimeline) GetCurrentStage() string {
+	if x != nil {
+		return x.CurrentStage
+	}
+	return ""
+}
+
+func (x *TrackingTimeline) GetMilestones() []*Milestone {
+	if x != nil {
+		return x.Milestones
+	}
+	return nil
+}
+
+func (x *TrackingTimeline) GetEntries() []*TimelineEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *TrackingTimeline) GetElapsedSeconds() int64 {
+	if x != nil {
+		return x.ElapsedSeconds
+	}
+	return 0
+}
+
+type Milestone struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Stage   string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`
+	Reached bool                   `protobuf:"varint,2,opt,name=reached,proto3" json:"reached,omitempty"`
+	// Нет — этап не достигнут или перевозчик его пропустил.
+	ReachedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=reached_at,json=reachedAt,proto3" json:"reached_at,omitempty"`
+	EventId   uint64                 `protobuf:"varint,4,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// От предыдущего этапа с известным временем.
+	SincePreviousSeconds int64 `protobuf:"varint,5,opt,name=since_previous_seconds,json=sincePreviousSeconds,proto3" json:"since_previous_seconds,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Milestone) Reset() {
+	*x = Milestone{}
+	mi := &file_models_tracking_model_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Tracking) String() string {
+func (x *Milestone) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Tracking) ProtoMessage() {}
+func (*Milestone) ProtoMessage() {}
 
-func (x *Tracking) ProtoReflect() protoreflect.Message {
-	mi := &file_models_tracking_model_proto_msgTypes[1]
+func (x *Milestone) ProtoReflect() protoreflect.Message {
+	mi := &file_models_tracking_model_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -174,106 +922,170 @@ func (x *Tracking) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Tracking.ProtoReflect.Descriptor instead.
-func (*Tracking) Descriptor() ([]byte, []int) {
-	return file_models_tracking_model_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use Milestone.ProtoReflect.Descriptor instead.
+func (*Milestone) Descriptor() ([]byte, []int) {
+	return file_models_tracking_model_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Tracking) GetId() uint64 {
+func (x *Milestone) GetStage() string {
 	if x != nil {
-		return x.Id
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *Milestone) GetReached() bool {
+	if x != nil {
+		return x.Reached
+	}
+	return false
+}
+
+func (x *Milestone) GetReachedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReachedAt
+	}
+	return nil
+}
+
+func (x *Milestone) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
 	}
 	return 0
 }
 
-func (x *Tracking) GetCarrierCode() string {
+func (x *Milestone) GetSincePreviousSeconds() int64 {
 	if x != nil {
-		return x.CarrierCode
+		return x.SincePreviousSeconds
 	}
-	return ""
+	return 0
 }
 
-func (x *Tracking) GetTrackNumber() string {
+// Подряд идущие одинаковые события перевозчика склеиваются в одну запись.
+type TimelineEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	StatusRaw     string                 `protobuf:"bytes,3,opt,name=status_raw,json=statusRaw,proto3" json:"status_raw,omitempty"`
+	Location      string                 `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	Place         *EventLocation         `protobuf:"bytes,6,opt,name=place,proto3" json:"place,omitempty"`
+	FirstAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=first_at,json=firstAt,proto3" json:"first_at,omitempty"`
+	LastAt        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_at,json=lastAt,proto3" json:"last_at,omitempty"`
+	EventIds      []uint64               `protobuf:"varint,9,rep,packed,name=event_ids,json=eventIds,proto3" json:"event_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimelineEntry) Reset() {
+	*x = TimelineEntry{}
+	mi := &file_models_tracking_model_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimelineEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimelineEntry) ProtoMessage() {}
+
+func (x *TimelineEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_models_tracking_model_proto_msgTypes[9]
 	if x != nil {
-		return x.TrackNumber
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *Tracking) GetStatus() string {
+// Deprecated: Use TimelineEntry.ProtoReflect.Descriptor instead.
+func (*TimelineEntry) Descriptor() ([]byte, []int) {
+	return file_models_tracking_model_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TimelineEntry) GetStage() string {
 	if x != nil {
-		return x.Status
+		return x.Stage
 	}
 	return ""
 }
 
-func (x *Tracking) GetStatusRaw() string {
+func (x *TimelineEntry) GetStatus() string {
 	if x != nil {
-		return x.StatusRaw
+		return x.Status
 	}
 	return ""
 }
 
-func (x *Tracking) GetStatusAt() *timestamppb.Timestamp {
+func (x *TimelineEntry) GetStatusRaw() string {
 	if x != nil {
-		return x.StatusAt
+		return x.StatusRaw
 	}
-	return nil
+	return ""
 }
 
-func (x *Tracking) GetLastCheckedAt() *timestamppb.Timestamp {
+func (x *TimelineEntry) GetLocation() string {
 	if x != nil {
-		return x.LastCheckedAt
+		return x.Location
 	}
-	return nil
+	return ""
 }
 
-func (x *Tracking) GetNextCheckAt() *timestamppb.Timestamp {
+func (x *TimelineEntry) GetMessage() string {
 	if x != nil {
-		return x.NextCheckAt
+		return x.Message
 	}
-	return nil
+	return ""
 }
 
-func (x *Tracking) GetCheckFailCount() int32 {
+func (x *TimelineEntry) GetPlace() *EventLocation {
 	if x != nil {
-		return x.CheckFailCount
+		return x.Place
 	}
-	return 0
+	return nil
 }
 
-func (x *Tracking) GetLastError() string {
+func (x *TimelineEntry) GetFirstAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.LastError
+		return x.FirstAt
 	}
-	return ""
+	return nil
 }
 
-func (x *Tracking) GetCreatedAt() *timestamppb.Timestamp {
+func (x *TimelineEntry) GetLastAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.CreatedAt
+		return x.LastAt
 	}
 	return nil
 }
 
-func (x *Tracking) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *TimelineEntry) GetEventIds() []uint64 {
 	if x != nil {
-		return x.UpdatedAt
+		return x.EventIds
 	}
 	return nil
 }
 
 type TrackingCreateInput struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CarrierCode   string                 `protobuf:"bytes,1,opt,name=carrier_code,json=carrierCode,proto3" json:"carrier_code,omitempty"`
-	TrackNumber   string                 `protobuf:"bytes,2,opt,name=track_number,json=trackNumber,proto3" json:"track_number,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	CarrierCode string                 `protobuf:"bytes,1,opt,name=carrier_code,json=carrierCode,proto3" json:"carrier_code,omitempty"`
+	TrackNumber string                 `protobuf:"bytes,2,opt,name=track_number,json=trackNumber,proto3" json:"track_number,omitempty"`
+	// Применяются только к новым трекам; для существующих — UpdateTracking.
+	MetadataJson  string   `protobuf:"bytes,3,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
+	Tags          []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	ExternalId    string   `protobuf:"bytes,5,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *TrackingCreateInput) Reset() {
 	*x = TrackingCreateInput{}
-	mi := &file_models_tracking_model_proto_msgTypes[2]
+	mi := &file_models_tracking_model_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -285,7 +1097,7 @@ func (x *TrackingCreateInput) String() string {
 func (*TrackingCreateInput) ProtoMessage() {}
 
 func (x *TrackingCreateInput) ProtoReflect() protoreflect.Message {
-	mi := &file_models_tracking_model_proto_msgTypes[2]
+	mi := &file_models_tracking_model_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -298,7 +1110,7 @@ func (x *TrackingCreateInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TrackingCreateInput.ProtoReflect.Descriptor instead.
 func (*TrackingCreateInput) Descriptor() ([]byte, []int) {
-	return file_models_tracking_model_proto_rawDescGZIP(), []int{2}
+	return file_models_tracking_model_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *TrackingCreateInput) GetCarrierCode() string {
@@ -315,11 +1127,217 @@ func (x *TrackingCreateInput) GetTrackNumber() string {
 	return ""
 }
 
+func (x *TrackingCreateInput) GetMetadataJson() string {
+	if x != nil {
+		return x.MetadataJson
+	}
+	return ""
+}
+
+func (x *TrackingCreateInput) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *TrackingCreateInput) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+// Удалённый трек (в ленте изменений).
+type TrackingTombstone struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	CarrierCode   string                 `protobuf:"bytes,2,opt,name=carrier_code,json=carrierCode,proto3" json:"carrier_code,omitempty"`
+	TrackNumber   string                 `protobuf:"bytes,3,opt,name=track_number,json=trackNumber,proto3" json:"track_number,omitempty"`
+	ExternalId    string                 `protobuf:"bytes,4,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackingTombstone) Reset() {
+	*x = TrackingTombstone{}
+	mi := &file_models_tracking_model_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackingTombstone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackingTombstone) ProtoMessage() {}
+
+func (x *TrackingTombstone) ProtoReflect() protoreflect.Message {
+	mi := &file_models_tracking_model_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackingTombstone.ProtoReflect.Descriptor instead.
+func (*TrackingTombstone) Descriptor() ([]byte, []int) {
+	return file_models_tracking_model_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TrackingTombstone) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+func (x *TrackingTombstone) GetCarrierCode() string {
+	if x != nil {
+		return x.CarrierCode
+	}
+	return ""
+}
+
+func (x *TrackingTombstone) GetTrackNumber() string {
+	if x != nil {
+		return x.TrackNumber
+	}
+	return ""
+}
+
+func (x *TrackingTombstone) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *TrackingTombstone) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+// Запись ленты изменений: текущее состояние трека или события либо удалённый трек.
+type Change struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Номер изменения; у всего, что изменилось вместе, он общий.
+	Seq int64 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	// Types that are valid to be assigned to Entity:
+	//
+	//	*Change_Tracking
+	//	*Change_Event
+	//	*Change_Deleted
+	Entity        isChange_Entity `protobuf_oneof:"entity"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Change) Reset() {
+	*x = Change{}
+	mi := &file_models_tracking_model_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Change) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Change) ProtoMessage() {}
+
+func (x *Change) ProtoReflect() protoreflect.Message {
+	mi := &file_models_tracking_model_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Change.ProtoReflect.Descriptor instead.
+func (*Change) Descriptor() ([]byte, []int) {
+	return file_models_tracking_model_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Change) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Change) GetEntity() isChange_Entity {
+	if x != nil {
+		return x.Entity
+	}
+	return nil
+}
+
+func (x *Change) GetTracking() *Tracking {
+	if x != nil {
+		if x, ok := x.Entity.(*Change_Tracking); ok {
+			return x.Tracking
+		}
+	}
+	return nil
+}
+
+func (x *Change) GetEvent() *TrackingEvent {
+	if x != nil {
+		if x, ok := x.Entity.(*Change_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+func (x *Change) GetDeleted() *TrackingTombstone {
+	if x != nil {
+		if x, ok := x.Entity.(*Change_Deleted); ok {
+			return x.Deleted
+		}
+	}
+	return nil
+}
+
+type isChange_Entity interface {
+	isChange_Entity()
+}
+
+type Change_Tracking struct {
+	Tracking *Tracking `protobuf:"bytes,2,opt,name=tracking,proto3,oneof"`
+}
+
+type Change_Event struct {
+	Event *TrackingEvent `protobuf:"bytes,3,opt,name=event,proto3,oneof"`
+}
+
+type Change_Deleted struct {
+	Deleted *TrackingTombstone `protobuf:"bytes,4,opt,name=deleted,proto3,oneof"`
+}
+
+func (*Change_Tracking) isChange_Entity() {}
+
+func (*Change_Event) isChange_Entity() {}
+
+func (*Change_Deleted) isChange_Entity() {}
+
 var File_models_tracking_model_proto protoreflect.FileDescriptor
 
 const file_models_tracking_model_proto_rawDesc = "" +
 	"\n" +
-	"\x1bmodels/tracking_model.proto\x12\x12trackbox.models.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc6\x02\n" +
+	"\x1bmodels/tracking_model.proto\x12\x12trackbox.models.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa1\x04\n" +
 	"\rTrackingEvent\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1f\n" +
 	"\vtracking_id\x18\x02 \x01(\x04R\n" +
@@ -333,7 +1351,24 @@ const file_models_tracking_model_proto_rawDesc = "" +
 	"\amessage\x18\a \x01(\tR\amessage\x12!\n" +
 	"\fpayload_json\x18\b \x01(\tR\vpayloadJson\x129\n" +
 	"\n" +
-	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x93\x04\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x127\n" +
+	"\x05place\x18\n" +
+	" \x01(\v2!.trackbox.models.v1.EventLocationR\x05place\x12$\n" +
+	"\x0eevent_time_raw\x18\v \x01(\tR\feventTimeRaw\x12#\n" +
+	"\rtime_inferred\x18\f \x01(\bR\ftimeInferred\x12\x1a\n" +
+	"\brevision\x18\r \x01(\x05R\brevision\x129\n" +
+	"\n" +
+	"removed_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tremovedAt\"\xb4\x01\n" +
+	"\rEventLocation\x12\x18\n" +
+	"\acountry\x18\x01 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x02 \x01(\tR\x06region\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12\x1f\n" +
+	"\vpostal_code\x18\x04 \x01(\tR\n" +
+	"postalCode\x12\x15\n" +
+	"\x03lat\x18\x05 \x01(\x01H\x00R\x03lat\x88\x01\x01\x12\x15\n" +
+	"\x03lon\x18\x06 \x01(\x01H\x01R\x03lon\x88\x01\x01B\x06\n" +
+	"\x04_latB\x06\n" +
+	"\x04_lon\"\xae\x05\n" +
 	"\bTracking\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\x12!\n" +
 	"\fcarrier_code\x18\x02 \x01(\tR\vcarrierCode\x12!\n" +
@@ -351,10 +1386,100 @@ const file_models_tracking_model_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"[\n" +
+	"updated_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12#\n" +
+	"\rmetadata_json\x18\r \x01(\tR\fmetadataJson\x12\x12\n" +
+	"\x04tags\x18\x0e \x03(\tR\x04tags\x12\x1f\n" +
+	"\vexternal_id\x18\x0f \x01(\tR\n" +
+	"externalId\x12?\n" +
+	"\bshipment\x18\x10 \x01(\v2#.trackbox.models.v1.ShipmentDetailsR\bshipment\"\x83\x02\n" +
+	"\x0fShipmentDetails\x12I\n" +
+	"\x12estimated_delivery\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x11estimatedDelivery\x12!\n" +
+	"\fweight_grams\x18\x02 \x01(\x05R\vweightGrams\x12\x16\n" +
+	"\x06origin\x18\x03 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x04 \x01(\tR\vdestination\x12%\n" +
+	"\x0erecipient_city\x18\x05 \x01(\tR\rrecipientCity\x12!\n" +
+	"\fservice_type\x18\x06 \x01(\tR\vserviceType\"\x92\x02\n" +
+	"\tEtaChange\x12I\n" +
+	"\x12estimated_delivery\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x11estimatedDelivery\x12Z\n" +
+	"\x1bprevious_estimated_delivery\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x19previousEstimatedDelivery\x12;\n" +
+	"\vobserved_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"observedAt\x12!\n" +
+	"\fslip_seconds\x18\x04 \x01(\x03R\vslipSeconds\"\xab\x03\n" +
+	"\rTrackingCheck\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\x129\n" +
+	"\n" +
+	"checked_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x1f\n" +
+	"\verror_class\x18\x06 \x01(\tR\n" +
+	"errorClass\x12\x16\n" +
+	"\x06worker\x18\a \x01(\tR\x06worker\x12\x18\n" +
+	"\abackend\x18\b \x01(\tR\abackend\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\t \x01(\x03R\tlatencyMs\x12\x1f\n" +
+	"\vhttp_status\x18\n" +
+	" \x01(\x05R\n" +
+	"httpStatus\x12!\n" +
+	"\fevents_count\x18\v \x01(\x05R\veventsCount\x12#\n" +
+	"\rresponse_hash\x18\f \x01(\tR\fresponseHash\x12!\n" +
+	"\fresponse_ref\x18\r \x01(\tR\vresponseRef\"\xba\x01\n" +
+	"\rEventRevision\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x04R\aeventId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12=\n" +
+	"\bprevious\x18\x03 \x01(\v2!.trackbox.models.v1.TrackingEventR\bprevious\x12;\n" +
+	"\vobserved_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"observedAt\"\xfd\x01\n" +
+	"\x10TrackingTimeline\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\x12#\n" +
+	"\rcurrent_stage\x18\x02 \x01(\tR\fcurrentStage\x12=\n" +
+	"\n" +
+	"milestones\x18\x03 \x03(\v2\x1d.trackbox.models.v1.MilestoneR\n" +
+	"milestones\x12;\n" +
+	"\aentries\x18\x04 \x03(\v2!.trackbox.models.v1.TimelineEntryR\aentries\x12'\n" +
+	"\x0felapsed_seconds\x18\x05 \x01(\x03R\x0eelapsedSeconds\"\xc7\x01\n" +
+	"\tMilestone\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x18\n" +
+	"\areached\x18\x02 \x01(\bR\areached\x129\n" +
+	"\n" +
+	"reached_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\treachedAt\x12\x19\n" +
+	"\bevent_id\x18\x04 \x01(\x04R\aeventId\x124\n" +
+	"\x16since_previous_seconds\x18\x05 \x01(\x03R\x14sincePreviousSeconds\"\xd4\x02\n" +
+	"\rTimelineEntry\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"status_raw\x18\x03 \x01(\tR\tstatusRaw\x12\x1a\n" +
+	"\blocation\x18\x04 \x01(\tR\blocation\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x127\n" +
+	"\x05place\x18\x06 \x01(\v2!.trackbox.models.v1.EventLocationR\x05place\x125\n" +
+	"\bfirst_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\afirstAt\x123\n" +
+	"\alast_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x06lastAt\x12\x1b\n" +
+	"\tevent_ids\x18\t \x03(\x04R\beventIds\"\xb5\x01\n" +
 	"\x13TrackingCreateInput\x12!\n" +
 	"\fcarrier_code\x18\x01 \x01(\tR\vcarrierCode\x12!\n" +
-	"\ftrack_number\x18\x02 \x01(\tR\vtrackNumberB1Z/github.com/BearBump/TrackBox/internal/pb/modelsb\x06proto3"
+	"\ftrack_number\x18\x02 \x01(\tR\vtrackNumber\x12#\n" +
+	"\rmetadata_json\x18\x03 \x01(\tR\fmetadataJson\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12\x1f\n" +
+	"\vexternal_id\x18\x05 \x01(\tR\n" +
+	"externalId\"\xd6\x01\n" +
+	"\x11TrackingTombstone\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\x12!\n" +
+	"\fcarrier_code\x18\x02 \x01(\tR\vcarrierCode\x12!\n" +
+	"\ftrack_number\x18\x03 \x01(\tR\vtrackNumber\x12\x1f\n" +
+	"\vexternal_id\x18\x04 \x01(\tR\n" +
+	"externalId\x129\n" +
+	"\n" +
+	"deleted_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\"\xde\x01\n" +
+	"\x06Change\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x03R\x03seq\x12:\n" +
+	"\btracking\x18\x02 \x01(\v2\x1c.trackbox.models.v1.TrackingH\x00R\btracking\x129\n" +
+	"\x05event\x18\x03 \x01(\v2!.trackbox.models.v1.TrackingEventH\x00R\x05event\x12A\n" +
+	"\adeleted\x18\x04 \x01(\v2%.trackbox.models.v1.TrackingTombstoneH\x00R\adeletedB\b\n" +
+	"\x06entityB1Z/github.com/BearBump/TrackBox/internal/pb/modelsb\x06proto3"
 
 var (
 	file_models_tracking_model_proto_rawDescOnce sync.Once
@@ -368,26 +1493,56 @@ func file_models_tracking_model_proto_rawDescGZIP() []byte {
 	return file_models_tracking_model_proto_rawDescData
 }
 
-var file_models_tracking_model_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_models_tracking_model_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_models_tracking_model_proto_goTypes = []any{
 	(*TrackingEvent)(nil),         // 0: trackbox.models.v1.TrackingEvent
-	(*Tracking)(nil),              // 1: trackbox.models.v1.Tracking
-	(*TrackingCreateInput)(nil),   // 2: trackbox.models.v1.TrackingCreateInput
-	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+	(*EventLocation)(nil),         // 1: trackbox.models.v1.EventLocation
+	(*Tracking)(nil),              // 2: trackbox.models.v1.Tracking
+	(*ShipmentDetails)(nil),       // 3: trackbox.models.v1.ShipmentDetails
+	(*EtaChange)(nil),             // 4: trackbox.models.v1.EtaChange
+	(*TrackingCheck)(nil),         // 5: trackbox.models.v1.TrackingCheck
+	(*EventRevision)(nil),         // 6: trackbox.models.v1.EventRevision
+	(*TrackingTimeline)(nil),      // 7: trackbox.models.v1.TrackingTimeline
+	(*Milestone)(nil),             // 8: trackbox.models.v1.Milestone
+	(*TimelineEntry)(nil),         // 9: trackbox.models.v1.TimelineEntry
+	(*TrackingCreateInput)(nil),   // 10: trackbox.models.v1.TrackingCreateInput
+	(*TrackingTombstone)(nil),     // 11: trackbox.models.v1.TrackingTombstone
+	(*Change)(nil),                // 12: trackbox.models.v1.Change
+	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
 }
 var file_models_tracking_model_proto_depIdxs = []int32{
-	3, // 0: trackbox.models.v1.TrackingEvent.event_time:type_name -> google.protobuf.Timestamp
-	3, // 1: trackbox.models.v1.TrackingEvent.created_at:type_name -> google.protobuf.Timestamp
-	3, // 2: trackbox.models.v1.Tracking.status_at:type_name -> google.protobuf.Timestamp
-	3, // 3: trackbox.models.v1.Tracking.last_checked_at:type_name -> google.protobuf.Timestamp
-	3, // 4: trackbox.models.v1.Tracking.next_check_at:type_name -> google.protobuf.Timestamp
-	3, // 5: trackbox.models.v1.Tracking.created_at:type_name -> google.protobuf.Timestamp
-	3, // 6: trackbox.models.v1.Tracking.updated_at:type_name -> google.protobuf.Timestamp
-	7, // [7:7] is the sub-list for method output_type
-	7, // [7:7] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	13, // 0: trackbox.models.v1.TrackingEvent.event_time:type_name -> google.protobuf.Timestamp
+	13, // 1: trackbox.models.v1.TrackingEvent.created_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: trackbox.models.v1.TrackingEvent.place:type_name -> trackbox.models.v1.EventLocation
+	13, // 3: trackbox.models.v1.TrackingEvent.removed_at:type_name -> google.protobuf.Timestamp
+	13, // 4: trackbox.models.v1.Tracking.status_at:type_name -> google.protobuf.Timestamp
+	13, // 5: trackbox.models.v1.Tracking.last_checked_at:type_name -> google.protobuf.Timestamp
+	13, // 6: trackbox.models.v1.Tracking.next_check_at:type_name -> google.protobuf.Timestamp
+	13, // 7: trackbox.models.v1.Tracking.created_at:type_name -> google.protobuf.Timestamp
+	13, // 8: trackbox.models.v1.Tracking.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 9: trackbox.models.v1.Tracking.shipment:type_name -> trackbox.models.v1.ShipmentDetails
+	13, // 10: trackbox.models.v1.ShipmentDetails.estimated_delivery:type_name -> google.protobuf.Timestamp
+	13, // 11: trackbox.models.v1.EtaChange.estimated_delivery:type_name -> google.protobuf.Timestamp
+	13, // 12: trackbox.models.v1.EtaChange.previous_estimated_delivery:type_name -> google.protobuf.Timestamp
+	13, // 13: trackbox.models.v1.EtaChange.observed_at:type_name -> google.protobuf.Timestamp
+	13, // 14: trackbox.models.v1.TrackingCheck.checked_at:type_name -> google.protobuf.Timestamp
+	0,  // 15: trackbox.models.v1.EventRevision.previous:type_name -> trackbox.models.v1.TrackingEvent
+	13, // 16: trackbox.models.v1.EventRevision.observed_at:type_name -> google.protobuf.Timestamp
+	8,  // 17: trackbox.models.v1.TrackingTimeline.milestones:type_name -> trackbox.models.v1.Milestone
+	9,  // 18: trackbox.models.v1.TrackingTimeline.entries:type_name -> trackbox.models.v1.TimelineEntry
+	13, // 19: trackbox.models.v1.Milestone.reached_at:type_name -> google.protobuf.Timestamp
+	1,  // 20: trackbox.models.v1.TimelineEntry.place:type_name -> trackbox.models.v1.EventLocation
+	13, // 21: trackbox.models.v1.TimelineEntry.first_at:type_name -> google.protobuf.Timestamp
+	13, // 22: trackbox.models.v1.TimelineEntry.last_at:type_name -> google.protobuf.Timestamp
+	13, // 23: trackbox.models.v1.TrackingTombstone.deleted_at:type_name -> google.protobuf.Timestamp
+	2,  // 24: trackbox.models.v1.Change.tracking:type_name -> trackbox.models.v1.Tracking
+	0,  // 25: trackbox.models.v1.Change.event:type_name -> trackbox.models.v1.TrackingEvent
+	11, // 26: trackbox.models.v1.Change.deleted:type_name -> trackbox.models.v1.TrackingTombstone
+	27, // [27:27] is the sub-list for method output_type
+	27, // [27:27] is the sub-list for method input_type
+	27, // [27:27] is the sub-list for extension type_name
+	27, // [27:27] is the sub-list for extension extendee
+	0,  // [0:27] is the sub-list for field type_name
 }
 
 func init() { file_models_tracking_model_proto_init() }
@@ -395,13 +1550,19 @@ func file_models_tracking_model_proto_init() {
 	if File_models_tracking_model_proto != nil {
 		return
 	}
+	file_models_tracking_model_proto_msgTypes[1].OneofWrappers = []any{}
+	file_models_tracking_model_proto_msgTypes[12].OneofWrappers = []any{
+		(*Change_Tracking)(nil),
+		(*Change_Event)(nil),
+		(*Change_Deleted)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_models_tracking_model_proto_rawDesc), len(file_models_tracking_model_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -12,6 +12,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -24,6 +25,58 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type CreateTrackingResult_ItemStatus int32
+
+const (
+	CreateTrackingResult_STATUS_UNSPECIFIED CreateTrackingResult_ItemStatus = 0
+	CreateTrackingResult_CREATED            CreateTrackingResult_ItemStatus = 1
+	CreateTrackingResult_EXISTED            CreateTrackingResult_ItemStatus = 2
+	CreateTrackingResult_INVALID            CreateTrackingResult_ItemStatus = 3
+)
+
+// Enum value maps for CreateTrackingResult_ItemStatus.
+var (
+	CreateTrackingResult_ItemStatus_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "CREATED",
+		2: "EXISTED",
+		3: "INVALID",
+	}
+	CreateTrackingResult_ItemStatus_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"CREATED":            1,
+		"EXISTED":            2,
+		"INVALID":            3,
+	}
+)
+
+func (x CreateTrackingResult_ItemStatus) Enum() *CreateTrackingResult_ItemStatus {
+	p := new(CreateTrackingResult_ItemStatus)
+	*p = x
+	return p
+}
+
+func (x CreateTrackingResult_ItemStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CreateTrackingResult_ItemStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_trackings_api_trackings_proto_enumTypes[0].Descriptor()
+}
+
+func (CreateTrackingResult_ItemStatus) Type() protoreflect.EnumType {
+	return &file_trackings_api_trackings_proto_enumTypes[0]
+}
+
+func (x CreateTrackingResult_ItemStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CreateTrackingResult_ItemStatus.Descriptor instead.
+func (CreateTrackingResult_ItemStatus) EnumDescriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{2, 0}
+}
+
 type CreateTrackingsRequest struct {
 	state         protoimpl.MessageState        `protogen:"open.v1"`
 	Items         []*models.TrackingCreateInput `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
@@ -112,6 +165,146 @@ func (x *CreateTrackingsResponse) GetTrackings() []*models.Tracking {
 	return nil
 }
 
+type CreateTrackingResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Порядковый номер элемента в потоке (с 0).
+	Index  uint64                          `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Status CreateTrackingResult_ItemStatus `protobuf:"varint,2,opt,name=status,proto3,enum=trackbox.trackings.v1.CreateTrackingResult_ItemStatus" json:"status,omitempty"`
+	// 0 для INVALID.
+	TrackingId uint64 `protobuf:"varint,3,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	// Причина для INVALID.
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTrackingResult) Reset() {
+	*x = CreateTrackingResult{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTrackingResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTrackingResult) ProtoMessage() {}
+
+func (x *CreateTrackingResult) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTrackingResult.ProtoReflect.Descriptor instead.
+func (*CreateTrackingResult) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateTrackingResult) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *CreateTrackingResult) GetStatus() CreateTrackingResult_ItemStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CreateTrackingResult_STATUS_UNSPECIFIED
+}
+
+func (x *CreateTrackingResult) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+func (x *CreateTrackingResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Результат одной пачки; счётчики — нарастающим итогом с начала потока.
+type StreamCreateTrackingsResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Results       []*CreateTrackingResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Created       uint64                  `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	Existed       uint64                  `protobuf:"varint,3,opt,name=existed,proto3" json:"existed,omitempty"`
+	Invalid       uint64                  `protobuf:"varint,4,opt,name=invalid,proto3" json:"invalid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamCreateTrackingsResponse) Reset() {
+	*x = StreamCreateTrackingsResponse{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamCreateTrackingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamCreateTrackingsResponse) ProtoMessage() {}
+
+func (x *StreamCreateTrackingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamCreateTrackingsResponse.ProtoReflect.Descriptor instead.
+func (*StreamCreateTrackingsResponse) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StreamCreateTrackingsResponse) GetResults() []*CreateTrackingResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *StreamCreateTrackingsResponse) GetCreated() uint64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *StreamCreateTrackingsResponse) GetExisted() uint64 {
+	if x != nil {
+		return x.Existed
+	}
+	return 0
+}
+
+func (x *StreamCreateTrackingsResponse) GetInvalid() uint64 {
+	if x != nil {
+		return x.Invalid
+	}
+	return 0
+}
+
 type GetTrackingsByIdsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ids           []uint64               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
@@ -121,7 +314,7 @@ type GetTrackingsByIdsRequest struct {
 
 func (x *GetTrackingsByIdsRequest) Reset() {
 	*x = GetTrackingsByIdsRequest{}
-	mi := &file_trackings_api_trackings_proto_msgTypes[2]
+	mi := &file_trackings_api_trackings_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -133,7 +326,7 @@ func (x *GetTrackingsByIdsRequest) String() string {
 func (*GetTrackingsByIdsRequest) ProtoMessage() {}
 
 func (x *GetTrackingsByIdsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_trackings_api_trackings_proto_msgTypes[2]
+	mi := &file_trackings_api_trackings_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -146,7 +339,7 @@ func (x *GetTrackingsByIdsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTrackingsByIdsRequest.ProtoReflect.Descriptor instead.
 func (*GetTrackingsByIdsRequest) Descriptor() ([]byte, []int) {
-	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{2}
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetTrackingsByIdsRequest) GetIds() []uint64 {
@@ -165,7 +358,7 @@ type GetTrackingsByIdsResponse struct {
 
 func (x *GetTrackingsByIdsResponse) Reset() {
 	*x = GetTrackingsByIdsResponse{}
-	mi := &file_trackings_api_trackings_proto_msgTypes[3]
+	mi := &file_trackings_api_trackings_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -177,7 +370,7 @@ func (x *GetTrackingsByIdsResponse) String() string {
 func (*GetTrackingsByIdsResponse) ProtoMessage() {}
 
 func (x *GetTrackingsByIdsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_trackings_api_trackings_proto_msgTypes[3]
+	mi := &file_trackings_api_trackings_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -190,7 +383,7 @@ func (x *GetTrackingsByIdsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTrackingsByIdsResponse.ProtoReflect.Descriptor instead.
 func (*GetTrackingsByIdsResponse) Descriptor() ([]byte, []int) {
-	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{3}
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetTrackingsByIdsResponse) GetTrackings() []*models.Tracking {
@@ -200,30 +393,37 @@ func (x *GetTrackingsByIdsResponse) GetTrackings() []*models.Tracking {
 	return nil
 }
 
-type ListTrackingEventsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TrackingId    uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+type ListTrackingsRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	CarrierCode string                 `protobuf:"bytes,1,opt,name=carrier_code,json=carrierCode,proto3" json:"carrier_code,omitempty"`
+	Status      string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Трек должен иметь все перечисленные теги.
+	Tags       []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	ExternalId string   `protobuf:"bytes,4,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
+	// JSON-объект: metadata трека должна его содержать.
+	MetadataJson string `protobuf:"bytes,5,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
+	// По умолчанию 100, максимум 1000.
+	PageSize      int32  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTrackingEventsRequest) Reset() {
-	*x = ListTrackingEventsRequest{}
-	mi := &file_trackings_api_trackings_proto_msgTypes[4]
+func (x *ListTrackingsRequest) Reset() {
+	*x = ListTrackingsRequest{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTrackingEventsRequest) String() string {
+func (x *ListTrackingsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTrackingEventsRequest) ProtoMessage() {}
+func (*ListTrackingsRequest) ProtoMessage() {}
 
-func (x *ListTrackingEventsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_trackings_api_trackings_proto_msgTypes[4]
+func (x *ListTrackingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -234,54 +434,84 @@ func (x *ListTrackingEventsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTrackingEventsRequest.ProtoReflect.Descriptor instead.
-func (*ListTrackingEventsRequest) Descriptor() ([]byte, []int) {
-	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ListTrackingsRequest.ProtoReflect.Descriptor instead.
+func (*ListTrackingsRequest) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *ListTrackingEventsRequest) GetTrackingId() uint64 {
+func (x *ListTrackingsRequest) GetCarrierCode() string {
 	if x != nil {
-		return x.TrackingId
+		return x.CarrierCode
 	}
-	return 0
+	return ""
 }
 
-func (x *ListTrackingEventsRequest) GetLimit() int32 {
+func (x *ListTrackingsRequest) GetStatus() string {
 	if x != nil {
-		return x.Limit
+		return x.Status
 	}
-	return 0
+	return ""
 }
 
-func (x *ListTrackingEventsRequest) GetOffset() int32 {
+func (x *ListTrackingsRequest) GetTags() []string {
 	if x != nil {
-		return x.Offset
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListTrackingsRequest) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+func (x *ListTrackingsRequest) GetMetadataJson() string {
+	if x != nil {
+		return x.MetadataJson
+	}
+	return ""
+}
+
+func (x *ListTrackingsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
 	}
 	return 0
 }
 
-type ListTrackingEventsResponse struct {
-	state         protoimpl.MessageState  `protogen:"open.v1"`
-	Events        []*models.TrackingEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+func (x *ListTrackingsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListTrackingsResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Trackings []*models.Tracking     `protobuf:"bytes,1,rep,name=trackings,proto3" json:"trackings,omitempty"`
+	// Пусто — страниц больше нет.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTrackingEventsResponse) Reset() {
-	*x = ListTrackingEventsResponse{}
-	mi := &file_trackings_api_trackings_proto_msgTypes[5]
+func (x *ListTrackingsResponse) Reset() {
+	*x = ListTrackingsResponse{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTrackingEventsResponse) String() string {
+func (x *ListTrackingsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTrackingEventsResponse) ProtoMessage() {}
+func (*ListTrackingsResponse) ProtoMessage() {}
 
-func (x *ListTrackingEventsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_trackings_api_trackings_proto_msgTypes[5]
+func (x *ListTrackingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -292,40 +522,55 @@ func (x *ListTrackingEventsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTrackingEventsResponse.ProtoReflect.Descriptor instead.
-func (*ListTrackingEventsResponse) Descriptor() ([]byte, []int) {
-	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ListTrackingsResponse.ProtoReflect.Descriptor instead.
+func (*ListTrackingsResponse) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ListTrackingEventsResponse) GetEvents() []*models.TrackingEvent {
+func (x *ListTrackingsResponse) GetTrackings() []*models.Tracking {
 	if x != nil {
-		return x.Events
+		return x.Trackings
 	}
 	return nil
 }
 
-type RefreshTrackingRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TrackingId    uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+func (x *ListTrackingsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type UpdateTrackingRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	// JSON-объект целиком заменяет metadata; "" — удалить.
+	MetadataJson *string `protobuf:"bytes,2,opt,name=metadata_json,json=metadataJson,proto3,oneof" json:"metadata_json,omitempty"`
+	// "" — удалить.
+	ExternalId *string `protobuf:"bytes,3,opt,name=external_id,json=externalId,proto3,oneof" json:"external_id,omitempty"`
+	// Порядок применения: clear_tags, add_tags, remove_tags (тег из обоих списков удаляется).
+	ClearTags     bool     `protobuf:"varint,4,opt,name=clear_tags,json=clearTags,proto3" json:"clear_tags,omitempty"`
+	AddTags       []string `protobuf:"bytes,5,rep,name=add_tags,json=addTags,proto3" json:"add_tags,omitempty"`
+	RemoveTags    []string `protobuf:"bytes,6,rep,name=remove_tags,json=removeTags,proto3" json:"remove_tags,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RefreshTrackingRequest) Reset() {
-	*x = RefreshTrackingRequest{}
-	mi := &file_trackings_api_trackings_proto_msgTypes[6]
+func (x *UpdateTrackingRequest) Reset() {
+	*x = UpdateTrackingRequest{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RefreshTrackingRequest) String() string {
+func (x *UpdateTrackingRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RefreshTrackingRequest) ProtoMessage() {}
+func (*UpdateTrackingRequest) ProtoMessage() {}
 
-func (x *RefreshTrackingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_trackings_api_trackings_proto_msgTypes[6]
+func (x *UpdateTrackingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -336,47 +581,1257 @@ func (x *RefreshTrackingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RefreshTrackingRequest.ProtoReflect.Descriptor instead.
-func (*RefreshTrackingRequest) Descriptor() ([]byte, []int) {
-	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use UpdateTrackingRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTrackingRequest) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *RefreshTrackingRequest) GetTrackingId() uint64 {
+func (x *UpdateTrackingRequest) GetTrackingId() uint64 {
 	if x != nil {
 		return x.TrackingId
 	}
 	return 0
 }
 
-var File_trackings_api_trackings_proto protoreflect.FileDescriptor
+func (x *UpdateTrackingRequest) GetMetadataJson() string {
+	if x != nil && x.MetadataJson != nil {
+		return *x.MetadataJson
+	}
+	return ""
+}
 
-const file_trackings_api_trackings_proto_rawDesc = "" +
-	"\n" +
-	"\x1dtrackings_api/trackings.proto\x12\x15trackbox.trackings.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1bmodels/tracking_model.proto\"W\n" +
-	"\x16CreateTrackingsRequest\x12=\n" +
-	"\x05items\x18\x01 \x03(\v2'.trackbox.models.v1.TrackingCreateInputR\x05items\"U\n" +
-	"\x17CreateTrackingsResponse\x12:\n" +
-	"\ttrackings\x18\x01 \x03(\v2\x1c.trackbox.models.v1.TrackingR\ttrackings\",\n" +
-	"\x18GetTrackingsByIdsRequest\x12\x10\n" +
-	"\x03ids\x18\x01 \x03(\x04R\x03ids\"W\n" +
-	"\x19GetTrackingsByIdsResponse\x12:\n" +
-	"\ttrackings\x18\x01 \x03(\v2\x1c.trackbox.models.v1.TrackingR\ttrackings\"j\n" +
-	"\x19ListTrackingEventsRequest\x12\x1f\n" +
-	"\vtracking_id\x18\x01 \x01(\x04R\n" +
-	"trackingId\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x03 \x01(\x05R\x06offset\"W\n" +
-	"\x1aListTrackingEventsResponse\x129\n" +
-	"\x06events\x18\x01 \x03(\v2!.trackbox.models.v1.TrackingEventR\x06events\"9\n" +
-	"\x16RefreshTrackingRequest\x12\x1f\n" +
-	"\vtracking_id\x18\x01 \x01(\x04R\n" +
-	"trackingId2\xe1\x04\n" +
+func (x *UpdateTrackingRequest) GetExternalId() string {
+	if x != nil && x.ExternalId != nil {
+		return *x.ExternalId
+	}
+	return ""
+}
+
+func (x *UpdateTrackingRequest) GetClearTags() bool {
+	if x != nil {
+		return x.ClearTags
+	}
+	return false
+}
+
+func (x *UpdateTrackingRequest) GetAddTags() []string {
+	if x != nil {
+		return x.AddTags
+	}
+	return nil
+}
+
+func (x *UpdateTrackingRequest) GetRemoveTags() []string {
+	if x != nil {
+		return x.RemoveTags
+	}
+	return nil
+}
+
+type ListTrackingEventsRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	// Устаревшая постраничность: offset > 0 включает LIMIT/OFFSET (новые первыми, limit не больше 500).
+	// Без offset limit — то же, что page_size.
+	Limit  int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset int32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Только события в этой стране / регионе (без учёта регистра); пусто — все.
+	Country string `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
+	Region  string `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+	// По умолчанию 100, максимум 1000.
+	PageSize int32 `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token предыдущего ответа; действует с теми же order и фильтрами.
+	PageToken string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// DESC (по умолчанию, новые первыми) | ASC
+	Order string `protobuf:"bytes,8,opt,name=order,proto3" json:"order,omitempty"`
+	// Только события с event_time в [from, to); пусто — без ограничения.
+	From *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=to,proto3" json:"to,omitempty"`
+	// Посчитать total_count — число событий под фильтрами (отдельный запрос).
+	IncludeTotalCount bool `protobuf:"varint,11,opt,name=include_total_count,json=includeTotalCount,proto3" json:"include_total_count,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListTrackingEventsRequest) Reset() {
+	*x = ListTrackingEventsRequest{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrackingEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrackingEventsRequest) ProtoMessage() {}
+
+func (x *ListTrackingEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrackingEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListTrackingEventsRequest) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListTrackingEventsRequest) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+func (x *ListTrackingEventsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTrackingEventsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListTrackingEventsRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *ListTrackingEventsRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *ListTrackingEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTrackingEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListTrackingEventsRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *ListTrackingEventsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListTrackingEventsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListTrackingEventsRequest) GetIncludeTotalCount() bool {
+	if x != nil {
+		return x.IncludeTotalCount
+	}
+	return false
+}
+
+type ListTrackingEventsResponse struct {
+	state  protoimpl.MessageState  `protogen:"open.v1"`
+	Events []*models.TrackingEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// Пусто — страниц больше нет.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Только с include_total_count.
+	TotalCount    *int64 `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3,oneof" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrackingEventsResponse) Reset() {
+	*x = ListTrackingEventsResponse{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrackingEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrackingEventsResponse) ProtoMessage() {}
+
+func (x *ListTrackingEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrackingEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListTrackingEventsResponse) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListTrackingEventsResponse) GetEvents() []*models.TrackingEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListTrackingEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListTrackingEventsResponse) GetTotalCount() int64 {
+	if x != nil && x.TotalCount != nil {
+		return *x.TotalCount
+	}
+	return 0
+}
+
+type BatchListTrackingEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Не больше 500; повторы убираются.
+	TrackingIds []uint64 `protobuf:"varint,1,rep,packed,name=tracking_ids,json=trackingIds,proto3" json:"tracking_ids,omitempty"`
+	// Событий на трек, новые первыми: по умолчанию 10, максимум 100.
+	LimitPerTracking int32 `protobuf:"varint,2,opt,name=limit_per_tracking,json=limitPerTracking,proto3" json:"limit_per_tracking,omitempty"`
+	// Только события, записанные позже (created_at): для инкрементальной синхронизации передавайте
+	// наибольший created_at из уже полученных.
+	Since         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchListTrackingEventsRequest) Reset() {
+	*x = BatchListTrackingEventsRequest{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchListTrackingEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchListTrackingEventsRequest) ProtoMessage() {}
+
+func (x *BatchListTrackingEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchListTrackingEventsRequest.ProtoReflect.Descriptor instead.
+func (*BatchListTrackingEventsRequest) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchListTrackingEventsRequest) GetTrackingIds() []uint64 {
+	if x != nil {
+		return x.TrackingIds
+	}
+	return nil
+}
+
+func (x *BatchListTrackingEventsRequest) GetLimitPerTracking() int32 {
+	if x != nil {
+		return x.LimitPerTracking
+	}
+	return 0
+}
+
+func (x *BatchListTrackingEventsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+type TrackingEvents struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	TrackingId    uint64                  `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	Events        []*models.TrackingEvent `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackingEvents) Reset() {
+	*x = TrackingEvents{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackingEvents) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackingEvents) ProtoMessage() {}
+
+func (x *TrackingEvents) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackingEvents.ProtoReflect.Descriptor instead.
+func (*TrackingEvents) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TrackingEvents) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+func (x *TrackingEvents) GetEvents() []*models.TrackingEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type BatchListTrackingEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// В порядке tracking_ids запроса, по записи на каждый трек (events пуст, если событий нет).
+	Trackings     []*TrackingEvents `protobuf:"bytes,1,rep,name=trackings,proto3" json:"trackings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchListTrackingEventsResponse) Reset() {
+	*x = BatchListTrackingEventsResponse{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchListTrackingEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchListTrackingEventsResponse) ProtoMessage() {}
+
+func (x *BatchListTrackingEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchListTrackingEventsResponse.ProtoReflect.Descriptor instead.
+func (*BatchListTrackingEventsResponse) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BatchListTrackingEventsResponse) GetTrackings() []*TrackingEvents {
+	if x != nil {
+		return x.Trackings
+	}
+	return nil
+}
+
+type GetTrackingTimelineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTrackingTimelineRequest) Reset() {
+	*x = GetTrackingTimelineRequest{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTrackingTimelineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTrackingTimelineRequest) ProtoMessage() {}
+
+func (x *GetTrackingTimelineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTrackingTimelineRequest.ProtoReflect.Descriptor instead.
+func (*GetTrackingTimelineRequest) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetTrackingTimelineRequest) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+type GetTrackingTimelineResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Timeline      *models.TrackingTimeline `protobuf:"bytes,1,opt,name=timeline,proto3" json:"timeline,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTrackingTimelineResponse) Reset() {
+	*x = GetTrackingTimelineResponse{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTrackingTimelineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTrackingTimelineResponse) ProtoMessage() {}
+
+func (x *GetTrackingTimelineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTrackingTimelineResponse.ProtoReflect.Descriptor instead.
+func (*GetTrackingTimelineResponse) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetTrackingTimelineResponse) GetTimeline() *models.TrackingTimeline {
+	if x != nil {
+		return x.Timeline
+	}
+	return nil
+}
+
+type ListEtaHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEtaHistoryRequest) Reset() {
+	*x = ListEtaHistoryRequest{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEtaHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEtaHistoryRequest) ProtoMessage() {}
+
+func (x *ListEtaHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEtaHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListEtaHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListEtaHistoryRequest) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+type ListEtaHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []*models.EtaChange    `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEtaHistoryResponse) Reset() {
+	*x = ListEtaHistoryResponse{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEtaHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEtaHistoryResponse) ProtoMessage() {}
+
+func (x *ListEtaHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEtaHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListEtaHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListEtaHistoryResponse) GetChanges() []*models.EtaChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type ListEventRevisionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventRevisionsRequest) Reset() {
+	*x = ListEventRevisionsRequest{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventRevisionsRequest) ProtoMessage() {}
+
+func (x *ListEventRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListEventRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListEventRevisionsRequest) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+type ListEventRevisionsResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Revisions     []*models.EventRevision `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventRevisionsResponse) Reset() {
+	*x = ListEventRevisionsResponse{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventRevisionsResponse) ProtoMessage() {}
+
+func (x *ListEventRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListEventRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListEventRevisionsResponse) GetRevisions() []*models.EventRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+type ListTrackingChecksRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	// Только неудачные проверки.
+	FailedOnly bool `protobuf:"varint,2,opt,name=failed_only,json=failedOnly,proto3" json:"failed_only,omitempty"`
+	// По умолчанию 100, максимум 1000.
+	PageSize      int32  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrackingChecksRequest) Reset() {
+	*x = ListTrackingChecksRequest{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrackingChecksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrackingChecksRequest) ProtoMessage() {}
+
+func (x *ListTrackingChecksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrackingChecksRequest.ProtoReflect.Descriptor instead.
+func (*ListTrackingChecksRequest) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListTrackingChecksRequest) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+func (x *ListTrackingChecksRequest) GetFailedOnly() bool {
+	if x != nil {
+		return x.FailedOnly
+	}
+	return false
+}
+
+func (x *ListTrackingChecksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTrackingChecksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListTrackingChecksResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Checks        []*models.TrackingCheck `protobuf:"bytes,1,rep,name=checks,proto3" json:"checks,omitempty"`
+	NextPageToken string                  `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrackingChecksResponse) Reset() {
+	*x = ListTrackingChecksResponse{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrackingChecksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrackingChecksResponse) ProtoMessage() {}
+
+func (x *ListTrackingChecksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrackingChecksResponse.ProtoReflect.Descriptor instead.
+func (*ListTrackingChecksResponse) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListTrackingChecksResponse) GetChecks() []*models.TrackingCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+func (x *ListTrackingChecksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type DeleteTrackingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTrackingRequest) Reset() {
+	*x = DeleteTrackingRequest{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTrackingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTrackingRequest) ProtoMessage() {}
+
+func (x *DeleteTrackingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTrackingRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTrackingRequest) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeleteTrackingRequest) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+type ListChangesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// next_cursor предыдущего ответа; пусто — с начала ленты.
+	Cursor string `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// По умолчанию 500, максимум 5000.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChangesRequest) Reset() {
+	*x = ListChangesRequest{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChangesRequest) ProtoMessage() {}
+
+func (x *ListChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChangesRequest.ProtoReflect.Descriptor instead.
+func (*ListChangesRequest) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListChangesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListChangesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListChangesResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Changes []*models.Change       `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	// Курсор для следующего запроса (без новых изменений — тот же, что в запросе).
+	NextCursor string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// true — после next_cursor уже есть изменения, можно запрашивать сразу.
+	HasMore       bool `protobuf:"varint,3,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChangesResponse) Reset() {
+	*x = ListChangesResponse{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChangesResponse) ProtoMessage() {}
+
+func (x *ListChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChangesResponse.ProtoReflect.Descriptor instead.
+func (*ListChangesResponse) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListChangesResponse) GetChanges() []*models.Change {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *ListChangesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *ListChangesResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+type RefreshTrackingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshTrackingRequest) Reset() {
+	*x = RefreshTrackingRequest{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshTrackingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshTrackingRequest) ProtoMessage() {}
+
+func (x *RefreshTrackingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshTrackingRequest.ProtoReflect.Descriptor instead.
+func (*RefreshTrackingRequest) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RefreshTrackingRequest) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+type CheckTrackingNowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TrackingId    uint64                 `protobuf:"varint,1,opt,name=tracking_id,json=trackingId,proto3" json:"tracking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckTrackingNowRequest) Reset() {
+	*x = CheckTrackingNowRequest{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckTrackingNowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckTrackingNowRequest) ProtoMessage() {}
+
+func (x *CheckTrackingNowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckTrackingNowRequest.ProtoReflect.Descriptor instead.
+func (*CheckTrackingNowRequest) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CheckTrackingNowRequest) GetTrackingId() uint64 {
+	if x != nil {
+		return x.TrackingId
+	}
+	return 0
+}
+
+type CheckTrackingNowResponse struct {
+	state     protoimpl.MessageState  `protogen:"open.v1"`
+	Tracking  *models.Tracking        `protobuf:"bytes,1,opt,name=tracking,proto3" json:"tracking,omitempty"`
+	NewEvents []*models.TrackingEvent `protobuf:"bytes,2,rep,name=new_events,json=newEvents,proto3" json:"new_events,omitempty"`
+	// true, если воркер не ответил вовремя и трек поставлен в очередь (как RefreshTracking).
+	Queued        bool `protobuf:"varint,3,opt,name=queued,proto3" json:"queued,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckTrackingNowResponse) Reset() {
+	*x = CheckTrackingNowResponse{}
+	mi := &file_trackings_api_trackings_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckTrackingNowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckTrackingNowResponse) ProtoMessage() {}
+
+func (x *CheckTrackingNowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_trackings_api_trackings_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckTrackingNowResponse.ProtoReflect.Descriptor instead.
+func (*CheckTrackingNowResponse) Descriptor() ([]byte, []int) {
+	return file_trackings_api_trackings_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CheckTrackingNowResponse) GetTracking() *models.Tracking {
+	if x != nil {
+		return x.Tracking
+	}
+	return nil
+}
+
+func (x *CheckTrackingNowResponse) GetNewEvents() []*models.TrackingEvent {
+	if x != nil {
+		return x.NewEvents
+	}
+	return nil
+}
+
+func (x *CheckTrackingNowResponse) GetQueued() bool {
+	if x != nil {
+		return x.Queued
+	}
+	return false
+}
+
+var File_trackings_api_trackings_proto protoreflect.FileDescriptor
+
+const file_trackings_api_trackings_proto_rawDesc = "" +
+	"\n" +
+	"\x1dtrackings_api/trackings.proto\x12\x15trackbox.trackings.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bmodels/tracking_model.proto\"W\n" +
+	"\x16CreateTrackingsRequest\x12=\n" +
+	"\x05items\x18\x01 \x03(\v2'.trackbox.models.v1.TrackingCreateInputR\x05items\"U\n" +
+	"\x17CreateTrackingsResponse\x12:\n" +
+	"\ttrackings\x18\x01 \x03(\v2\x1c.trackbox.models.v1.TrackingR\ttrackings\"\x82\x02\n" +
+	"\x14CreateTrackingResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x04R\x05index\x12N\n" +
+	"\x06status\x18\x02 \x01(\x0e26.trackbox.trackings.v1.CreateTrackingResult.ItemStatusR\x06status\x12\x1f\n" +
+	"\vtracking_id\x18\x03 \x01(\x04R\n" +
+	"trackingId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"K\n" +
+	"\n" +
+	"ItemStatus\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\v\n" +
+	"\aCREATED\x10\x01\x12\v\n" +
+	"\aEXISTED\x10\x02\x12\v\n" +
+	"\aINVALID\x10\x03\"\xb4\x01\n" +
+	"\x1dStreamCreateTrackingsResponse\x12E\n" +
+	"\aresults\x18\x01 \x03(\v2+.trackbox.trackings.v1.CreateTrackingResultR\aresults\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\x04R\acreated\x12\x18\n" +
+	"\aexisted\x18\x03 \x01(\x04R\aexisted\x12\x18\n" +
+	"\ainvalid\x18\x04 \x01(\x04R\ainvalid\",\n" +
+	"\x18GetTrackingsByIdsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x04R\x03ids\"W\n" +
+	"\x19GetTrackingsByIdsResponse\x12:\n" +
+	"\ttrackings\x18\x01 \x03(\v2\x1c.trackbox.models.v1.TrackingR\ttrackings\"\xe7\x01\n" +
+	"\x14ListTrackingsRequest\x12!\n" +
+	"\fcarrier_code\x18\x01 \x01(\tR\vcarrierCode\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x12\n" +
+	"\x04tags\x18\x03 \x03(\tR\x04tags\x12\x1f\n" +
+	"\vexternal_id\x18\x04 \x01(\tR\n" +
+	"externalId\x12#\n" +
+	"\rmetadata_json\x18\x05 \x01(\tR\fmetadataJson\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\"{\n" +
+	"\x15ListTrackingsResponse\x12:\n" +
+	"\ttrackings\x18\x01 \x03(\v2\x1c.trackbox.models.v1.TrackingR\ttrackings\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x85\x02\n" +
+	"\x15UpdateTrackingRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\x12(\n" +
+	"\rmetadata_json\x18\x02 \x01(\tH\x00R\fmetadataJson\x88\x01\x01\x12$\n" +
+	"\vexternal_id\x18\x03 \x01(\tH\x01R\n" +
+	"externalId\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"clear_tags\x18\x04 \x01(\bR\tclearTags\x12\x19\n" +
+	"\badd_tags\x18\x05 \x03(\tR\aaddTags\x12\x1f\n" +
+	"\vremove_tags\x18\x06 \x03(\tR\n" +
+	"removeTagsB\x10\n" +
+	"\x0e_metadata_jsonB\x0e\n" +
+	"\f_external_id\"\xfa\x02\n" +
+	"\x19ListTrackingEventsRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x18\n" +
+	"\acountry\x18\x04 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\x12\x14\n" +
+	"\x05order\x18\b \x01(\tR\x05order\x12.\n" +
+	"\x04from\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12.\n" +
+	"\x13include_total_count\x18\v \x01(\bR\x11includeTotalCount\"\xb5\x01\n" +
+	"\x1aListTrackingEventsResponse\x129\n" +
+	"\x06events\x18\x01 \x03(\v2!.trackbox.models.v1.TrackingEventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12$\n" +
+	"\vtotal_count\x18\x03 \x01(\x03H\x00R\n" +
+	"totalCount\x88\x01\x01B\x0e\n" +
+	"\f_total_count\"\xa3\x01\n" +
+	"\x1eBatchListTrackingEventsRequest\x12!\n" +
+	"\ftracking_ids\x18\x01 \x03(\x04R\vtrackingIds\x12,\n" +
+	"\x12limit_per_tracking\x18\x02 \x01(\x05R\x10limitPerTracking\x120\n" +
+	"\x05since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\"l\n" +
+	"\x0eTrackingEvents\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\x129\n" +
+	"\x06events\x18\x02 \x03(\v2!.trackbox.models.v1.TrackingEventR\x06events\"f\n" +
+	"\x1fBatchListTrackingEventsResponse\x12C\n" +
+	"\ttrackings\x18\x01 \x03(\v2%.trackbox.trackings.v1.TrackingEventsR\ttrackings\"=\n" +
+	"\x1aGetTrackingTimelineRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\"_\n" +
+	"\x1bGetTrackingTimelineResponse\x12@\n" +
+	"\btimeline\x18\x01 \x01(\v2$.trackbox.models.v1.TrackingTimelineR\btimeline\"8\n" +
+	"\x15ListEtaHistoryRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\"Q\n" +
+	"\x16ListEtaHistoryResponse\x127\n" +
+	"\achanges\x18\x01 \x03(\v2\x1d.trackbox.models.v1.EtaChangeR\achanges\"<\n" +
+	"\x19ListEventRevisionsRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\"]\n" +
+	"\x1aListEventRevisionsResponse\x12?\n" +
+	"\trevisions\x18\x01 \x03(\v2!.trackbox.models.v1.EventRevisionR\trevisions\"\x99\x01\n" +
+	"\x19ListTrackingChecksRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\x12\x1f\n" +
+	"\vfailed_only\x18\x02 \x01(\bR\n" +
+	"failedOnly\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"\x7f\n" +
+	"\x1aListTrackingChecksResponse\x129\n" +
+	"\x06checks\x18\x01 \x03(\v2!.trackbox.models.v1.TrackingCheckR\x06checks\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"8\n" +
+	"\x15DeleteTrackingRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\"B\n" +
+	"\x12ListChangesRequest\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"\x87\x01\n" +
+	"\x13ListChangesResponse\x124\n" +
+	"\achanges\x18\x01 \x03(\v2\x1a.trackbox.models.v1.ChangeR\achanges\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x19\n" +
+	"\bhas_more\x18\x03 \x01(\bR\ahasMore\"9\n" +
+	"\x16RefreshTrackingRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\":\n" +
+	"\x17CheckTrackingNowRequest\x12\x1f\n" +
+	"\vtracking_id\x18\x01 \x01(\x04R\n" +
+	"trackingId\"\xae\x01\n" +
+	"\x18CheckTrackingNowResponse\x128\n" +
+	"\btracking\x18\x01 \x01(\v2\x1c.trackbox.models.v1.TrackingR\btracking\x12@\n" +
+	"\n" +
+	"new_events\x18\x02 \x03(\v2!.trackbox.models.v1.TrackingEventR\tnewEvents\x12\x16\n" +
+	"\x06queued\x18\x03 \x01(\bR\x06queued2\xe4\x11\n" +
 	"\x10TrackingsService\x12\x87\x01\n" +
 	"\x0fCreateTrackings\x12-.trackbox.trackings.v1.CreateTrackingsRequest\x1a..trackbox.trackings.v1.CreateTrackingsResponse\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
 	"/trackings\x12\x98\x01\n" +
+	"\x15StreamCreateTrackings\x12'.trackbox.models.v1.TrackingCreateInput\x1a4.trackbox.trackings.v1.StreamCreateTrackingsResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/trackings/stream(\x010\x01\x12~\n" +
+	"\rListTrackings\x12+.trackbox.trackings.v1.ListTrackingsRequest\x1a,.trackbox.trackings.v1.ListTrackingsResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/trackings\x12\x81\x01\n" +
+	"\x0eUpdateTracking\x12,.trackbox.trackings.v1.UpdateTrackingRequest\x1a\x1c.trackbox.models.v1.Tracking\"#\x82\xd3\xe4\x93\x02\x1d:\x01*2\x18/trackings/{tracking_id}\x12\x98\x01\n" +
 	"\x11GetTrackingsByIds\x12/.trackbox.trackings.v1.GetTrackingsByIdsRequest\x1a0.trackbox.trackings.v1.GetTrackingsByIdsResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/trackings/get-by-ids\x12\xa2\x01\n" +
-	"\x12ListTrackingEvents\x120.trackbox.trackings.v1.ListTrackingEventsRequest\x1a1.trackbox.trackings.v1.ListTrackingEventsResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/trackings/{tracking_id}/events\x12\x82\x01\n" +
-	"\x0fRefreshTracking\x12-.trackbox.trackings.v1.RefreshTrackingRequest\x1a\x16.google.protobuf.Empty\"(\x82\xd3\xe4\x93\x02\"\" /trackings/{tracking_id}/refreshB8Z6github.com/BearBump/TrackBox/internal/pb/trackings_apib\x06proto3"
+	"\x12ListTrackingEvents\x120.trackbox.trackings.v1.ListTrackingEventsRequest\x1a1.trackbox.trackings.v1.ListTrackingEventsResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/trackings/{tracking_id}/events\x12\xb1\x01\n" +
+	"\x17BatchListTrackingEvents\x125.trackbox.trackings.v1.BatchListTrackingEventsRequest\x1a6.trackbox.trackings.v1.BatchListTrackingEventsResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/trackings/events/get-by-ids\x12\xa7\x01\n" +
+	"\x13GetTrackingTimeline\x121.trackbox.trackings.v1.GetTrackingTimelineRequest\x1a2.trackbox.trackings.v1.GetTrackingTimelineResponse\")\x82\xd3\xe4\x93\x02#\x12!/trackings/{tracking_id}/timeline\x12\x9b\x01\n" +
+	"\x0eListEtaHistory\x12,.trackbox.trackings.v1.ListEtaHistoryRequest\x1a-.trackbox.trackings.v1.ListEtaHistoryResponse\",\x82\xd3\xe4\x93\x02&\x12$/trackings/{tracking_id}/eta-history\x12\xac\x01\n" +
+	"\x12ListEventRevisions\x120.trackbox.trackings.v1.ListEventRevisionsRequest\x1a1.trackbox.trackings.v1.ListEventRevisionsResponse\"1\x82\xd3\xe4\x93\x02+\x12)/trackings/{tracking_id}/events/revisions\x12\xa2\x01\n" +
+	"\x12ListTrackingChecks\x120.trackbox.trackings.v1.ListTrackingChecksRequest\x1a1.trackbox.trackings.v1.ListTrackingChecksResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/trackings/{tracking_id}/checks\x12x\n" +
+	"\x0eDeleteTracking\x12,.trackbox.trackings.v1.DeleteTrackingRequest\x1a\x16.google.protobuf.Empty\" \x82\xd3\xe4\x93\x02\x1a*\x18/trackings/{tracking_id}\x12v\n" +
+	"\vListChanges\x12).trackbox.trackings.v1.ListChangesRequest\x1a*.trackbox.trackings.v1.ListChangesResponse\"\x10\x82\xd3\xe4\x93\x02\n" +
+	"\x12\b/changes\x12\x82\x01\n" +
+	"\x0fRefreshTracking\x12-.trackbox.trackings.v1.RefreshTrackingRequest\x1a\x16.google.protobuf.Empty\"(\x82\xd3\xe4\x93\x02\"\" /trackings/{tracking_id}/refresh\x12\x9f\x01\n" +
+	"\x10CheckTrackingNow\x12..trackbox.trackings.v1.CheckTrackingNowRequest\x1a/.trackbox.trackings.v1.CheckTrackingNowResponse\"*\x82\xd3\xe4\x93\x02$\"\"/trackings/{tracking_id}/check-nowB8Z6github.com/BearBump/TrackBox/internal/pb/trackings_apib\x06proto3"
 
 var (
 	file_trackings_api_trackings_proto_rawDescOnce sync.Once
@@ -390,38 +1845,104 @@ func file_trackings_api_trackings_proto_rawDescGZIP() []byte {
 	return file_trackings_api_trackings_proto_rawDescData
 }
 
-var file_trackings_api_trackings_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_trackings_api_trackings_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_trackings_api_trackings_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_trackings_api_trackings_proto_goTypes = []any{
-	(*CreateTrackingsRequest)(nil),     // 0: trackbox.trackings.v1.CreateTrackingsRequest
-	(*CreateTrackingsResponse)(nil),    // 1: trackbox.trackings.v1.CreateTrackingsResponse
-	(*GetTrackingsByIdsRequest)(nil),   // 2: trackbox.trackings.v1.GetTrackingsByIdsRequest
-	(*GetTrackingsByIdsResponse)(nil),  // 3: trackbox.trackings.v1.GetTrackingsByIdsResponse
-	(*ListTrackingEventsRequest)(nil),  // 4: trackbox.trackings.v1.ListTrackingEventsRequest
-	(*ListTrackingEventsResponse)(nil), // 5: trackbox.trackings.v1.ListTrackingEventsResponse
-	(*RefreshTrackingRequest)(nil),     // 6: trackbox.trackings.v1.RefreshTrackingRequest
-	(*models.TrackingCreateInput)(nil), // 7: trackbox.models.v1.TrackingCreateInput
-	(*models.Tracking)(nil),            // 8: trackbox.models.v1.Tracking
-	(*models.TrackingEvent)(nil),       // 9: trackbox.models.v1.TrackingEvent
-	(*emptypb.Empty)(nil),              // 10: google.protobuf.Empty
+	(CreateTrackingResult_ItemStatus)(0),    // 0: trackbox.trackings.v1.CreateTrackingResult.ItemStatus
+	(*CreateTrackingsRequest)(nil),          // 1: trackbox.trackings.v1.CreateTrackingsRequest
+	(*CreateTrackingsResponse)(nil),         // 2: trackbox.trackings.v1.CreateTrackingsResponse
+	(*CreateTrackingResult)(nil),            // 3: trackbox.trackings.v1.CreateTrackingResult
+	(*StreamCreateTrackingsResponse)(nil),   // 4: trackbox.trackings.v1.StreamCreateTrackingsResponse
+	(*GetTrackingsByIdsRequest)(nil),        // 5: trackbox.trackings.v1.GetTrackingsByIdsRequest
+	(*GetTrackingsByIdsResponse)(nil),       // 6: trackbox.trackings.v1.GetTrackingsByIdsResponse
+	(*ListTrackingsRequest)(nil),            // 7: trackbox.trackings.v1.ListTrackingsRequest
+	(*ListTrackingsResponse)(nil),           // 8: trackbox.trackings.v1.ListTrackingsResponse
+	(*UpdateTrackingRequest)(nil),           // 9: trackbox.trackings.v1.UpdateTrackingRequest
+	(*ListTrackingEventsRequest)(nil),       // 10: trackbox.trackings.v1.ListTrackingEventsRequest
+	(*ListTrackingEventsResponse)(nil),      // 11: trackbox.trackings.v1.ListTrackingEventsResponse
+	(*BatchListTrackingEventsRequest)(nil),  // 12: trackbox.trackings.v1.BatchListTrackingEventsRequest
+	(*TrackingEvents)(nil),                  // 13: trackbox.trackings.v1.TrackingEvents
+	(*BatchListTrackingEventsResponse)(nil), // 14: trackbox.trackings.v1.BatchListTrackingEventsResponse
+	(*GetTrackingTimelineRequest)(nil),      // 15: trackbox.trackings.v1.GetTrackingTimelineRequest
+	(*GetTrackingTimelineResponse)(nil),     // 16: trackbox.trackings.v1.GetTrackingTimelineResponse
+	(*ListEtaHistoryRequest)(nil),           // 17: trackbox.trackings.v1.ListEtaHistoryRequest
+	(*ListEtaHistoryResponse)(nil),          // 18: trackbox.trackings.v1.ListEtaHistoryResponse
+	(*ListEventRevisionsRequest)(nil),       // 19: trackbox.trackings.v1.ListEventRevisionsRequest
+	(*ListEventRevisionsResponse)(nil),      // 20: trackbox.trackings.v1.ListEventRevisionsResponse
+	(*ListTrackingChecksRequest)(nil),       // 21: trackbox.trackings.v1.ListTrackingChecksRequest
+	(*ListTrackingChecksResponse)(nil),      // 22: trackbox.trackings.v1.ListTrackingChecksResponse
+	(*DeleteTrackingRequest)(nil),           // 23: trackbox.trackings.v1.DeleteTrackingRequest
+	(*ListChangesRequest)(nil),              // 24: trackbox.trackings.v1.ListChangesRequest
+	(*ListChangesResponse)(nil),             // 25: trackbox.trackings.v1.ListChangesResponse
+	(*RefreshTrackingRequest)(nil),          // 26: trackbox.trackings.v1.RefreshTrackingRequest
+	(*CheckTrackingNowRequest)(nil),         // 27: trackbox.trackings.v1.CheckTrackingNowRequest
+	(*CheckTrackingNowResponse)(nil),        // 28: trackbox.trackings.v1.CheckTrackingNowResponse
+	(*models.TrackingCreateInput)(nil),      // 29: trackbox.models.v1.TrackingCreateInput
+	(*models.Tracking)(nil),                 // 30: trackbox.models.v1.Tracking
+	(*timestamppb.Timestamp)(nil),           // 31: google.protobuf.Timestamp
+	(*models.TrackingEvent)(nil),            // 32: trackbox.models.v1.TrackingEvent
+	(*models.TrackingTimeline)(nil),         // 33: trackbox.models.v1.TrackingTimeline
+	(*models.EtaChange)(nil),                // 34: trackbox.models.v1.EtaChange
+	(*models.EventRevision)(nil),            // 35: trackbox.models.v1.EventRevision
+	(*models.TrackingCheck)(nil),            // 36: trackbox.models.v1.TrackingCheck
+	(*models.Change)(nil),                   // 37: trackbox.models.v1.Change
+	(*emptypb.Empty)(nil),                   // 38: google.protobuf.Empty
 }
 var file_trackings_api_trackings_proto_depIdxs = []int32{
-	7,  // 0: trackbox.trackings.v1.CreateTrackingsRequest.items:type_name -> trackbox.models.v1.TrackingCreateInput
-	8,  // 1: trackbox.trackings.v1.CreateTrackingsResponse.trackings:type_name -> trackbox.models.v1.Tracking
-	8,  // 2: trackbox.trackings.v1.GetTrackingsByIdsResponse.trackings:type_name -> trackbox.models.v1.Tracking
-	9,  // 3: trackbox.trackings.v1.ListTrackingEventsResponse.events:type_name -> trackbox.models.v1.TrackingEvent
-	0,  // 4: trackbox.trackings.v1.TrackingsService.CreateTrackings:input_type -> trackbox.trackings.v1.CreateTrackingsRequest
-	2,  // 5: trackbox.trackings.v1.TrackingsService.GetTrackingsByIds:input_type -> trackbox.trackings.v1.GetTrackingsByIdsRequest
-	4,  // 6: trackbox.trackings.v1.TrackingsService.ListTrackingEvents:input_type -> trackbox.trackings.v1.ListTrackingEventsRequest
-	6,  // 7: trackbox.trackings.v1.TrackingsService.RefreshTracking:input_type -> trackbox.trackings.v1.RefreshTrackingRequest
-	1,  // 8: trackbox.trackings.v1.TrackingsService.CreateTrackings:output_type -> trackbox.trackings.v1.CreateTrackingsResponse
-	3,  // 9: trackbox.trackings.v1.TrackingsService.GetTrackingsByIds:output_type -> trackbox.trackings.v1.GetTrackingsByIdsResponse
-	5,  // 10: trackbox.trackings.v1.TrackingsService.ListTrackingEvents:output_type -> trackbox.trackings.v1.ListTrackingEventsResponse
-	10, // 11: trackbox.trackings.v1.TrackingsService.RefreshTracking:output_type -> google.protobuf.Empty
-	8,  // [8:12] is the sub-list for method output_type
-	4,  // [4:8] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	29, // 0: trackbox.trackings.v1.CreateTrackingsRequest.items:type_name -> trackbox.models.v1.TrackingCreateInput
+	30, // 1: trackbox.trackings.v1.CreateTrackingsResponse.trackings:type_name -> trackbox.models.v1.Tracking
+	0,  // 2: trackbox.trackings.v1.CreateTrackingResult.status:type_name -> trackbox.trackings.v1.CreateTrackingResult.ItemStatus
+	3,  // 3: trackbox.trackings.v1.StreamCreateTrackingsResponse.results:type_name -> trackbox.trackings.v1.CreateTrackingResult
+	30, // 4: trackbox.trackings.v1.GetTrackingsByIdsResponse.trackings:type_name -> trackbox.models.v1.Tracking
+	30, // 5: trackbox.trackings.v1.ListTrackingsResponse.trackings:type_name -> trackbox.models.v1.Tracking
+	31, // 6: trackbox.trackings.v1.ListTrackingEventsRequest.from:type_name -> google.protobuf.Timestamp
+	31, // 7: trackbox.trackings.v1.ListTrackingEventsRequest.to:type_name -> google.protobuf.Timestamp
+	32, // 8: trackbox.trackings.v1.ListTrackingEventsResponse.events:type_name -> trackbox.models.v1.TrackingEvent
+	31, // 9: trackbox.trackings.v1.BatchListTrackingEventsRequest.since:type_name -> google.protobuf.Timestamp
+	32, // 10: trackbox.trackings.v1.TrackingEvents.events:type_name -> trackbox.models.v1.TrackingEvent
+	13, // 11: trackbox.trackings.v1.BatchListTrackingEventsResponse.trackings:type_name -> trackbox.trackings.v1.TrackingEvents
+	33, // 12: trackbox.trackings.v1.GetTrackingTimelineResponse.timeline:type_name -> trackbox.models.v1.TrackingTimeline
+	34, // 13: trackbox.trackings.v1.ListEtaHistoryResponse.changes:type_name -> trackbox.models.v1.EtaChange
+	35, // 14: trackbox.trackings.v1.ListEventRevisionsResponse.revisions:type_name -> trackbox.models.v1.EventRevision
+	36, // 15: trackbox.trackings.v1.ListTrackingChecksResponse.checks:type_name -> trackbox.models.v1.TrackingCheck
+	37, // 16: trackbox.trackings.v1.ListChangesResponse.changes:type_name -> trackbox.models.v1.Change
+	30, // 17: trackbox.trackings.v1.CheckTrackingNowResponse.tracking:type_name -> trackbox.models.v1.Tracking
+	32, // 18: trackbox.trackings.v1.CheckTrackingNowResponse.new_events:type_name -> trackbox.models.v1.TrackingEvent
+	1,  // 19: trackbox.trackings.v1.TrackingsService.CreateTrackings:input_type -> trackbox.trackings.v1.CreateTrackingsRequest
+	29, // 20: trackbox.trackings.v1.TrackingsService.StreamCreateTrackings:input_type -> trackbox.models.v1.TrackingCreateInput
+	7,  // 21: trackbox.trackings.v1.TrackingsService.ListTrackings:input_type -> trackbox.trackings.v1.ListTrackingsRequest
+	9,  // 22: trackbox.trackings.v1.TrackingsService.UpdateTracking:input_type -> trackbox.trackings.v1.UpdateTrackingRequest
+	5,  // 23: trackbox.trackings.v1.TrackingsService.GetTrackingsByIds:input_type -> trackbox.trackings.v1.GetTrackingsByIdsRequest
+	10, // 24: trackbox.trackings.v1.TrackingsService.ListTrackingEvents:input_type -> trackbox.trackings.v1.ListTrackingEventsRequest
+	12, // 25: trackbox.trackings.v1.TrackingsService.BatchListTrackingEvents:input_type -> trackbox.trackings.v1.BatchListTrackingEventsRequest
+	15, // 26: trackbox.trackings.v1.TrackingsService.GetTrackingTimeline:input_type -> trackbox.trackings.v1.GetTrackingTimelineRequest
+	17, // 27: trackbox.trackings.v1.TrackingsService.ListEtaHistory:input_type -> trackbox.trackings.v1.ListEtaHistoryRequest
+	19, // 28: trackbox.trackings.v1.TrackingsService.ListEventRevisions:input_type -> trackbox.trackings.v1.ListEventRevisionsRequest
+	21, // 29: trackbox.trackings.v1.TrackingsService.ListTrackingChecks:input_type -> trackbox.trackings.v1.ListTrackingChecksRequest
+	23, // 30: trackbox.trackings.v1.TrackingsService.DeleteTracking:input_type -> trackbox.trackings.v1.DeleteTrackingRequest
+	24, // 31: trackbox.trackings.v1.TrackingsService.ListChanges:input_type -> trackbox.trackings.v1.ListChangesRequest
+	26, // 32: trackbox.trackings.v1.TrackingsService.RefreshTracking:input_type -> trackbox.trackings.v1.RefreshTrackingRequest
+	27, // 33: trackbox.trackings.v1.TrackingsService.CheckTrackingNow:input_type -> trackbox.trackings.v1.CheckTrackingNowRequest
+	2,  // 34: trackbox.trackings.v1.TrackingsService.CreateTrackings:output_type -> trackbox.trackings.v1.CreateTrackingsResponse
+	4,  // 35: trackbox.trackings.v1.TrackingsService.StreamCreateTrackings:output_type -> trackbox.trackings.v1.StreamCreateTrackingsResponse
+	8,  // 36: trackbox.trackings.v1.TrackingsService.ListTrackings:output_type -> trackbox.trackings.v1.ListTrackingsResponse
+	30, // 37: trackbox.trackings.v1.TrackingsService.UpdateTracking:output_type -> trackbox.models.v1.Tracking
+	6,  // 38: trackbox.trackings.v1.TrackingsService.GetTrackingsByIds:output_type -> trackbox.trackings.v1.GetTrackingsByIdsResponse
+	11, // 39: trackbox.trackings.v1.TrackingsService.ListTrackingEvents:output_type -> trackbox.trackings.v1.ListTrackingEventsResponse
+	14, // 40: trackbox.trackings.v1.TrackingsService.BatchListTrackingEvents:output_type -> trackbox.trackings.v1.BatchListTrackingEventsResponse
+	16, // 41: trackbox.trackings.v1.TrackingsService.GetTrackingTimeline:output_type -> trackbox.trackings.v1.GetTrackingTimelineResponse
+	18, // 42: trackbox.trackings.v1.TrackingsService.ListEtaHistory:output_type -> trackbox.trackings.v1.ListEtaHistoryResponse
+	20, // 43: trackbox.trackings.v1.TrackingsService.ListEventRevisions:output_type -> trackbox.trackings.v1.ListEventRevisionsResponse
+	22, // 44: trackbox.trackings.v1.TrackingsService.ListTrackingChecks:output_type -> trackbox.trackings.v1.ListTrackingChecksResponse
+	38, // 45: trackbox.trackings.v1.TrackingsService.DeleteTracking:output_type -> google.protobuf.Empty
+	25, // 46: trackbox.trackings.v1.TrackingsService.ListChanges:output_type -> trackbox.trackings.v1.ListChangesResponse
+	38, // 47: trackbox.trackings.v1.TrackingsService.RefreshTracking:output_type -> google.protobuf.Empty
+	28, // 48: trackbox.trackings.v1.TrackingsService.CheckTrackingNow:output_type -> trackbox.trackings.v1.CheckTrackingNowResponse
+	34, // [34:49] is the sub-list for method output_type
+	19, // [19:34] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_trackings_api_trackings_proto_init() }
@@ -429,18 +1950,21 @@ func file_trackings_api_trackings_proto_init() {
 	if File_trackings_api_trackings_proto != nil {
 		return
 	}
+	file_trackings_api_trackings_proto_msgTypes[8].OneofWrappers = []any{}
+	file_trackings_api_trackings_proto_msgTypes[10].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_trackings_api_trackings_proto_rawDesc), len(file_trackings_api_trackings_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      1,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_trackings_api_trackings_proto_goTypes,
 		DependencyIndexes: file_trackings_api_trackings_proto_depIdxs,
+		EnumInfos:         file_trackings_api_trackings_proto_enumTypes,
 		MessageInfos:      file_trackings_api_trackings_proto_msgTypes,
 	}.Build()
 	File_trackings_api_trackings_proto = out.File
@@ -8,6 +8,7 @@ package trackings_api
 
 import (
 	context "context"
+	models "github.com/BearBump/TrackBox/internal/pb/models"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -20,10 +21,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TrackingsService_CreateTrackings_FullMethodName    = "/trackbox.trackings.v1.TrackingsService/CreateTrackings"
-	TrackingsService_GetTrackingsByIds_FullMethodName  = "/trackbox.trackings.v1.TrackingsService/GetTrackingsByIds"
-	TrackingsService_ListTrackingEvents_FullMethodName = "/trackbox.trackings.v1.TrackingsService/ListTrackingEvents"
-	TrackingsService_RefreshTracking_FullMethodName    = "/trackbox.trackings.v1.TrackingsService/RefreshTracking"
+	TrackingsService_CreateTrackings_FullMethodName         = "/trackbox.trackings.v1.TrackingsService/CreateTrackings"
+	TrackingsService_StreamCreateTrackings_FullMethodName   = "/trackbox.trackings.v1.TrackingsService/StreamCreateTrackings"
+	TrackingsService_ListTrackings_FullMethodName           = "/trackbox.trackings.v1.TrackingsService/ListTrackings"
+	TrackingsService_UpdateTracking_FullMethodName          = "/trackbox.trackings.v1.TrackingsService/UpdateTracking"
+	TrackingsService_GetTrackingsByIds_FullMethodName       = "/trackbox.trackings.v1.TrackingsService/GetTrackingsByIds"
+	TrackingsService_ListTrackingEvents_FullMethodName      = "/trackbox.trackings.v1.TrackingsService/ListTrackingEvents"
+	TrackingsService_BatchListTrackingEvents_FullMethodName = "/trackbox.trackings.v1.TrackingsService/BatchListTrackingEvents"
+	TrackingsService_GetTrackingTimeline_FullMethodName     = "/trackbox.trackings.v1.TrackingsService/GetTrackingTimeline"
+	TrackingsService_ListEtaHistory_FullMethodName          = "/trackbox.trackings.v1.TrackingsService/ListEtaHistory"
+	TrackingsService_ListEventRevisions_FullMethodName      = "/trackbox.trackings.v1.TrackingsService/ListEventRevisions"
+	TrackingsService_ListTrackingChecks_FullMethodName      = "/trackbox.trackings.v1.TrackingsService/ListTrackingChecks"
+	TrackingsService_DeleteTracking_FullMethodName          = "/trackbox.trackings.v1.TrackingsService/DeleteTracking"
+	TrackingsService_ListChanges_FullMethodName             = "/trackbox.trackings.v1.TrackingsService/ListChanges"
+	TrackingsService_RefreshTracking_FullMethodName         = "/trackbox.trackings.v1.TrackingsService/RefreshTracking"
+	TrackingsService_CheckTrackingNow_FullMethodName        = "/trackbox.trackings.v1.TrackingsService/CheckTrackingNow"
 )
 
 // TrackingsServiceClient is the client API for TrackingsService service.
@@ -31,9 +43,36 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type TrackingsServiceClient interface {
 	CreateTrackings(ctx context.Context, in *CreateTrackingsRequest, opts ...grpc.CallOption) (*CreateTrackingsResponse, error)
+	// Потоковое создание: клиент шлёт сколько угодно элементов, каждый проверяется отдельно.
+	// Сервер отвечает по мере записи — сообщением на каждую пачку (до 1000 элементов): статусы элементов
+	// пачки в порядке отправки и счётчики с начала потока. Ни память сервера, ни размер сообщения
+	// не растут с длиной потока.
+	// Через HTTP тело и ответ — JSON-объекты подряд (newline-delimited).
+	StreamCreateTrackings(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[models.TrackingCreateInput, StreamCreateTrackingsResponse], error)
+	// Список треков по фильтру (теги, externalId, metadata, перевозчик, статус), постранично.
+	ListTrackings(ctx context.Context, in *ListTrackingsRequest, opts ...grpc.CallOption) (*ListTrackingsResponse, error)
+	// Меняет пользовательские атрибуты трека; незаданные поля не трогаются.
+	UpdateTracking(ctx context.Context, in *UpdateTrackingRequest, opts ...grpc.CallOption) (*models.Tracking, error)
 	GetTrackingsByIds(ctx context.Context, in *GetTrackingsByIdsRequest, opts ...grpc.CallOption) (*GetTrackingsByIdsResponse, error)
+	// История событий трека постранично: курсор по (event_time, id), в обе стороны, с фильтрами по месту и времени.
 	ListTrackingEvents(ctx context.Context, in *ListTrackingEventsRequest, opts ...grpc.CallOption) (*ListTrackingEventsResponse, error)
+	// Последние события многих треков одним запросом (например, для списка заказов).
+	BatchListTrackingEvents(ctx context.Context, in *BatchListTrackingEventsRequest, opts ...grpc.CallOption) (*BatchListTrackingEventsResponse, error)
+	// Таймлайн: этапы доставки, время между ними и события без повторов.
+	GetTrackingTimeline(ctx context.Context, in *GetTrackingTimelineRequest, opts ...grpc.CallOption) (*GetTrackingTimelineResponse, error)
+	// История ETA: когда и на сколько сдвигалась ожидаемая дата доставки.
+	ListEtaHistory(ctx context.Context, in *ListEtaHistoryRequest, opts ...grpc.CallOption) (*ListEtaHistoryResponse, error)
+	// Ревизии событий: какие события перевозчик исправил или убрал из истории.
+	ListEventRevisions(ctx context.Context, in *ListEventRevisionsRequest, opts ...grpc.CallOption) (*ListEventRevisionsResponse, error)
+	// Журнал проверок трека, новые первыми: когда, каким worker'ом и через какой бэкенд, с каким результатом.
+	ListTrackingChecks(ctx context.Context, in *ListTrackingChecksRequest, opts ...grpc.CallOption) (*ListTrackingChecksResponse, error)
+	// Удаляет трек со всеми событиями и историей; в ленте изменений остаётся отметка об удалении.
+	DeleteTracking(ctx context.Context, in *DeleteTrackingRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Лента изменений для копий данных TrackBox: треки, события и удалённые треки после курсора,
+	// в порядке фиксации изменений.
+	ListChanges(ctx context.Context, in *ListChangesRequest, opts ...grpc.CallOption) (*ListChangesResponse, error)
 	RefreshTracking(ctx context.Context, in *RefreshTrackingRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CheckTrackingNow(ctx context.Context, in *CheckTrackingNowRequest, opts ...grpc.CallOption) (*CheckTrackingNowResponse, error)
 }
 
 type trackingsServiceClient struct {
@@ -54,6 +93,39 @@ func (c *trackingsServiceClient) CreateTrackings(ctx context.Context, in *Create
 	return out, nil
 }
 
+func (c *trackingsServiceClient) StreamCreateTrackings(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[models.TrackingCreateInput, StreamCreateTrackingsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TrackingsService_ServiceDesc.Streams[0], TrackingsService_StreamCreateTrackings_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[models.TrackingCreateInput, StreamCreateTrackingsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrackingsService_StreamCreateTrackingsClient = grpc.BidiStreamingClient[models.TrackingCreateInput, StreamCreateTrackingsResponse]
+
+func (c *trackingsServiceClient) ListTrackings(ctx context.Context, in *ListTrackingsRequest, opts ...grpc.CallOption) (*ListTrackingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTrackingsResponse)
+	err := c.cc.Invoke(ctx, TrackingsService_ListTrackings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackingsServiceClient) UpdateTracking(ctx context.Context, in *UpdateTrackingRequest, opts ...grpc.CallOption) (*models.Tracking, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(models.Tracking)
+	err := c.cc.Invoke(ctx, TrackingsService_UpdateTracking_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *trackingsServiceClient) GetTrackingsByIds(ctx context.Context, in *GetTrackingsByIdsRequest, opts ...grpc.CallOption) (*GetTrackingsByIdsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetTrackingsByIdsResponse)
@@ -74,6 +146,76 @@ func (c *trackingsServiceClient) ListTrackingEvents(ctx context.Context, in *Lis
 	return out, nil
 }
 
+func (c *trackingsServiceClient) BatchListTrackingEvents(ctx context.Context, in *BatchListTrackingEventsRequest, opts ...grpc.CallOption) (*BatchListTrackingEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchListTrackingEventsResponse)
+	err := c.cc.Invoke(ctx, TrackingsService_BatchListTrackingEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackingsServiceClient) GetTrackingTimeline(ctx context.Context, in *GetTrackingTimelineRequest, opts ...grpc.CallOption) (*GetTrackingTimelineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTrackingTimelineResponse)
+	err := c.cc.Invoke(ctx, TrackingsService_GetTrackingTimeline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackingsServiceClient) ListEtaHistory(ctx context.Context, in *ListEtaHistoryRequest, opts ...grpc.CallOption) (*ListEtaHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEtaHistoryResponse)
+	err := c.cc.Invoke(ctx, TrackingsService_ListEtaHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackingsServiceClient) ListEventRevisions(ctx context.Context, in *ListEventRevisionsRequest, opts ...grpc.CallOption) (*ListEventRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEventRevisionsResponse)
+	err := c.cc.Invoke(ctx, TrackingsService_ListEventRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackingsServiceClient) ListTrackingChecks(ctx context.Context, in *ListTrackingChecksRequest, opts ...grpc.CallOption) (*ListTrackingChecksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTrackingChecksResponse)
+	err := c.cc.Invoke(ctx, TrackingsService_ListTrackingChecks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackingsServiceClient) DeleteTracking(ctx context.Context, in *DeleteTrackingRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TrackingsService_DeleteTracking_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trackingsServiceClient) ListChanges(ctx context.Context, in *ListChangesRequest, opts ...grpc.CallOption) (*ListChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChangesResponse)
+	err := c.cc.Invoke(ctx, TrackingsService_ListChanges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *trackingsServiceClient) RefreshTracking(ctx context.Context, in *RefreshTrackingRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -84,14 +226,51 @@ func (c *trackingsServiceClient) RefreshTracking(ctx context.Context, in *Refres
 	return out, nil
 }
 
+func (c *trackingsServiceClient) CheckTrackingNow(ctx context.Context, in *CheckTrackingNowRequest, opts ...grpc.CallOption) (*CheckTrackingNowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckTrackingNowResponse)
+	err := c.cc.Invoke(ctx, TrackingsService_CheckTrackingNow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TrackingsServiceServer is the server API for TrackingsService service.
 // All implementations must embed UnimplementedTrackingsServiceServer
 // for forward compatibility.
 type TrackingsServiceServer interface {
 	CreateTrackings(context.Context, *CreateTrackingsRequest) (*CreateTrackingsResponse, error)
+	// Потоковое создание: клиент шлёт сколько угодно элементов, каждый проверяется отдельно.
+	// Сервер отвечает по мере записи — сообщением на каждую пачку (до 1000 элементов): статусы элементов
+	// пачки в порядке отправки и счётчики с начала потока. Ни память сервера, ни размер сообщения
+	// не растут с длиной потока.
+	// Через HTTP тело и ответ — JSON-объекты подряд (newline-delimited).
+	StreamCreateTrackings(grpc.BidiStreamingServer[models.TrackingCreateInput, StreamCreateTrackingsResponse]) error
+	// Список треков по фильтру (теги, externalId, metadata, перевозчик, статус), постранично.
+	ListTrackings(context.Context, *ListTrackingsRequest) (*ListTrackingsResponse, error)
+	// Меняет пользовательские атрибуты трека; незаданные поля не трогаются.
+	UpdateTracking(context.Context, *UpdateTrackingRequest) (*models.Tracking, error)
 	GetTrackingsByIds(context.Context, *GetTrackingsByIdsRequest) (*GetTrackingsByIdsResponse, error)
+	// История событий трека постранично: курсор по (event_time, id), в обе стороны, с фильтрами по месту и времени.
 	ListTrackingEvents(context.Context, *ListTrackingEventsRequest) (*ListTrackingEventsResponse, error)
+	// Последние события многих треков одним запросом (например, для списка заказов).
+	BatchListTrackingEvents(context.Context, *BatchListTrackingEventsRequest) (*BatchListTrackingEventsResponse, error)
+	// Таймлайн: этапы доставки, время между ними и события без повторов.
+	GetTrackingTimeline(context.Context, *GetTrackingTimelineRequest) (*GetTrackingTimelineResponse, error)
+	// История ETA: когда и на сколько сдвигалась ожидаемая дата доставки.
+	ListEtaHistory(context.Context, *ListEtaHistoryRequest) (*ListEtaHistoryResponse, error)
+	// Ревизии событий: какие события перевозчик исправил или убрал из истории.
+	ListEventRevisions(context.Context, *ListEventRevisionsRequest) (*ListEventRevisionsResponse, error)
+	// Журнал проверок трека, новые первыми: когда, каким worker'ом и через какой бэкенд, с каким результатом.
+	ListTrackingChecks(context.Context, *ListTrackingChecksRequest) (*ListTrackingChecksResponse, error)
+	// Удаляет трек со всеми событиями и историей; в ленте изменений остаётся отметка об удалении.
+	DeleteTracking(context.Context, *DeleteTrackingRequest) (*emptypb.Empty, error)
+	// Лента изменений для копий данных TrackBox: треки, события и удалённые треки после курсора,
+	// в порядке фиксации изменений.
+	ListChanges(context.Context, *ListChangesRequest) (*ListChangesResponse, error)
 	RefreshTracking(context.Context, *RefreshTrackingRequest) (*emptypb.Empty, error)
+	CheckTrackingNow(context.Context, *CheckTrackingNowRequest) (*CheckTrackingNowResponse, error)
 	mustEmbedUnimplementedTrackingsServiceServer()
 }
 
@@ -105,15 +284,48 @@ type UnimplementedTrackingsServiceServer struct{}
 func (UnimplementedTrackingsServiceServer) CreateTrackings(context.Context, *CreateTrackingsRequest) (*CreateTrackingsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CreateTrackings not implemented")
 }
+func (UnimplementedTrackingsServiceServer) StreamCreateTrackings(grpc.BidiStreamingServer[models.TrackingCreateInput, StreamCreateTrackingsResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamCreateTrackings not implemented")
+}
+func (UnimplementedTrackingsServiceServer) ListTrackings(context.Context, *ListTrackingsRequest) (*ListTrackingsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTrackings not implemented")
+}
+func (UnimplementedTrackingsServiceServer) UpdateTracking(context.Context, *UpdateTrackingRequest) (*models.Tracking, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateTracking not implemented")
+}
 func (UnimplementedTrackingsServiceServer) GetTrackingsByIds(context.Context, *GetTrackingsByIdsRequest) (*GetTrackingsByIdsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetTrackingsByIds not implemented")
 }
 func (UnimplementedTrackingsServiceServer) ListTrackingEvents(context.Context, *ListTrackingEventsRequest) (*ListTrackingEventsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListTrackingEvents not implemented")
 }
+func (UnimplementedTrackingsServiceServer) BatchListTrackingEvents(context.Context, *BatchListTrackingEventsRequest) (*BatchListTrackingEventsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchListTrackingEvents not implemented")
+}
+func (UnimplementedTrackingsServiceServer) GetTrackingTimeline(context.Context, *GetTrackingTimelineRequest) (*GetTrackingTimelineResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTrackingTimeline not implemented")
+}
+func (UnimplementedTrackingsServiceServer) ListEtaHistory(context.Context, *ListEtaHistoryRequest) (*ListEtaHistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListEtaHistory not implemented")
+}
+func (UnimplementedTrackingsServiceServer) ListEventRevisions(context.Context, *ListEventRevisionsRequest) (*ListEventRevisionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListEventRevisions not implemented")
+}
+func (UnimplementedTrackingsServiceServer) ListTrackingChecks(context.Context, *ListTrackingChecksRequest) (*ListTrackingChecksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTrackingChecks not implemented")
+}
+func (UnimplementedTrackingsServiceServer) DeleteTracking(context.Context, *DeleteTrackingRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteTracking not implemented")
+}
+func (UnimplementedTrackingsServiceServer) ListChanges(context.Context, *ListChangesRequest) (*ListChangesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListChanges not implemented")
+}
 func (UnimplementedTrackingsServiceServer) RefreshTracking(context.Context, *RefreshTrackingRequest) (*emptypb.Empty, error) {
 	return nil, status.Error(codes.Unimplemented, "method RefreshTracking not implemented")
 }
+func (UnimplementedTrackingsServiceServer) CheckTrackingNow(context.Context, *CheckTrackingNowRequest) (*CheckTrackingNowResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CheckTrackingNow not implemented")
+}
 func (UnimplementedTrackingsServiceServer) mustEmbedUnimplementedTrackingsServiceServer() {}
 func (UnimplementedTrackingsServiceServer) testEmbeddedByValue()                          {}
 
@@ -153,6 +365,49 @@ func _TrackingsService_CreateTrackings_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrackingsService_StreamCreateTrackings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TrackingsServiceServer).StreamCreateTrackings(&grpc.GenericServerStream[models.TrackingCreateInput, StreamCreateTrackingsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TrackingsService_StreamCreateTrackingsServer = grpc.BidiStreamingServer[models.TrackingCreateInput, StreamCreateTrackingsResponse]
+
+func _TrackingsService_ListTrackings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrackingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackingsServiceServer).ListTrackings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackingsService_ListTrackings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackingsServiceServer).ListTrackings(ctx, req.(*ListTrackingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackingsService_UpdateTracking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTrackingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackingsServiceServer).UpdateTracking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackingsService_UpdateTracking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackingsServiceServer).UpdateTracking(ctx, req.(*UpdateTrackingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TrackingsService_GetTrackingsByIds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetTrackingsByIdsRequest)
 	if err := dec(in); err != nil {
@@ -189,6 +444,132 @@ func _TrackingsService_ListTrackingEvents_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrackingsService_BatchListTrackingEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchListTrackingEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackingsServiceServer).BatchListTrackingEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackingsService_BatchListTrackingEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackingsServiceServer).BatchListTrackingEvents(ctx, req.(*BatchListTrackingEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackingsService_GetTrackingTimeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTrackingTimelineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackingsServiceServer).GetTrackingTimeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackingsService_GetTrackingTimeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackingsServiceServer).GetTrackingTimeline(ctx, req.(*GetTrackingTimelineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackingsService_ListEtaHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEtaHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackingsServiceServer).ListEtaHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackingsService_ListEtaHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackingsServiceServer).ListEtaHistory(ctx, req.(*ListEtaHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackingsService_ListEventRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEventRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackingsServiceServer).ListEventRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackingsService_ListEventRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackingsServiceServer).ListEventRevisions(ctx, req.(*ListEventRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackingsService_ListTrackingChecks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrackingChecksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackingsServiceServer).ListTrackingChecks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackingsService_ListTrackingChecks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackingsServiceServer).ListTrackingChecks(ctx, req.(*ListTrackingChecksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackingsService_DeleteTracking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTrackingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackingsServiceServer).DeleteTracking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackingsService_DeleteTracking_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackingsServiceServer).DeleteTracking(ctx, req.(*DeleteTrackingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrackingsService_ListChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackingsServiceServer).ListChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackingsService_ListChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackingsServiceServer).ListChanges(ctx, req.(*ListChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TrackingsService_RefreshTracking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RefreshTrackingRequest)
 	if err := dec(in); err != nil {
@@ -207,6 +588,24 @@ func _TrackingsService_RefreshTracking_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrackingsService_CheckTrackingNow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckTrackingNowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrackingsServiceServer).CheckTrackingNow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrackingsService_CheckTrackingNow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrackingsServiceServer).CheckTrackingNow(ctx, req.(*CheckTrackingNowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TrackingsService_ServiceDesc is the grpc.ServiceDesc for TrackingsService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -218,6 +617,14 @@ var TrackingsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateTrackings",
 			Handler:    _TrackingsService_CreateTrackings_Handler,
 		},
+		{
+			MethodName: "ListTrackings",
+			Handler:    _TrackingsService_ListTrackings_Handler,
+		},
+		{
+			MethodName: "UpdateTracking",
+			Handler:    _TrackingsService_UpdateTracking_Handler,
+		},
 		{
 			MethodName: "GetTrackingsByIds",
 			Handler:    _TrackingsService_GetTrackingsByIds_Handler,
@@ -226,11 +633,50 @@ var TrackingsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListTrackingEvents",
 			Handler:    _TrackingsService_ListTrackingEvents_Handler,
 		},
+		{
+			MethodName: "BatchListTrackingEvents",
+			Handler:    _TrackingsService_BatchListTrackingEvents_Handler,
+		},
+		{
+			MethodName: "GetTrackingTimeline",
+			Handler:    _TrackingsService_GetTrackingTimeline_Handler,
+		},
+		{
+			MethodName: "ListEtaHistory",
+			Handler:    _TrackingsService_ListEtaHistory_Handler,
+		},
+		{
+			MethodName: "ListEventRevisions",
+			Handler:    _TrackingsService_ListEventRevisions_Handler,
+		},
+		{
+			MethodName: "ListTrackingChecks",
+			Handler:    _TrackingsService_ListTrackingChecks_Handler,
+		},
+		{
+			MethodName: "DeleteTracking",
+			Handler:    _TrackingsService_DeleteTracking_Handler,
+		},
+		{
+			MethodName: "ListChanges",
+			Handler:    _TrackingsService_ListChanges_Handler,
+		},
 		{
 			MethodName: "RefreshTracking",
 			Handler:    _TrackingsService_RefreshTracking_Handler,
 		},
+		{
+			MethodName: "CheckTrackingNow",
+			Handler:    _TrackingsService_CheckTrackingNow_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamCreateTrackings",
+			Handler:       _TrackingsService_StreamCreateTrackings_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "trackings_api/trackings.proto",
 }
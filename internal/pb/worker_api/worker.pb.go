@@ -0,0 +1,281 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.2
+// source: worker_api/worker.proto
+
+package worker_api
+
+import (
+	models "github.com/BearBump/TrackBox/internal/pb/models"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CheckTrackingRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Tracking *models.Tracking       `protobuf:"bytes,1,opt,name=tracking,proto3" json:"tracking,omitempty"`
+	// Отпечаток последнего ответа перевозчика (из БД); пусто — неизвестен, ответ считается изменившимся.
+	Fingerprint   string `protobuf:"bytes,2,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckTrackingRequest) Reset() {
+	*x = CheckTrackingRequest{}
+	mi := &file_worker_api_worker_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckTrackingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckTrackingRequest) ProtoMessage() {}
+
+func (x *CheckTrackingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_api_worker_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckTrackingRequest.ProtoReflect.Descriptor instead.
+func (*CheckTrackingRequest) Descriptor() ([]byte, []int) {
+	return file_worker_api_worker_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CheckTrackingRequest) GetTracking() *models.Tracking {
+	if x != nil {
+		return x.Tracking
+	}
+	return nil
+}
+
+func (x *CheckTrackingRequest) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+type CheckTrackingResponse struct {
+	state       protoimpl.MessageState  `protogen:"open.v1"`
+	CheckedAt   *timestamppb.Timestamp  `protobuf:"bytes,1,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	Status      string                  `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	StatusRaw   string                  `protobuf:"bytes,3,opt,name=status_raw,json=statusRaw,proto3" json:"status_raw,omitempty"`
+	StatusAt    *timestamppb.Timestamp  `protobuf:"bytes,4,opt,name=status_at,json=statusAt,proto3" json:"status_at,omitempty"`
+	NextCheckAt *timestamppb.Timestamp  `protobuf:"bytes,5,opt,name=next_check_at,json=nextCheckAt,proto3" json:"next_check_at,omitempty"`
+	Events      []*models.TrackingEvent `protobuf:"bytes,6,rep,name=events,proto3" json:"events,omitempty"`
+	Error       string                  `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	Shipment    *models.ShipmentDetails `protobuf:"bytes,8,opt,name=shipment,proto3" json:"shipment,omitempty"`
+	// Вид результата (status_changed, events_changed, checked_no_change, check_failed); при checked_no_change
+	// events и shipment не заполняются.
+	Kind          string `protobuf:"bytes,9,opt,name=kind,proto3" json:"kind,omitempty"`
+	Fingerprint   string `protobuf:"bytes,10,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckTrackingResponse) Reset() {
+	*x = CheckTrackingResponse{}
+	mi := &file_worker_api_worker_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckTrackingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckTrackingResponse) ProtoMessage() {}
+
+func (x *CheckTrackingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_api_worker_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckTrackingResponse.ProtoReflect.Descriptor instead.
+func (*CheckTrackingResponse) Descriptor() ([]byte, []int) {
+	return file_worker_api_worker_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CheckTrackingResponse) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+func (x *CheckTrackingResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CheckTrackingResponse) GetStatusRaw() string {
+	if x != nil {
+		return x.StatusRaw
+	}
+	return ""
+}
+
+func (x *CheckTrackingResponse) GetStatusAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StatusAt
+	}
+	return nil
+}
+
+func (x *CheckTrackingResponse) GetNextCheckAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextCheckAt
+	}
+	return nil
+}
+
+func (x *CheckTrackingResponse) GetEvents() []*models.TrackingEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *CheckTrackingResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CheckTrackingResponse) GetShipment() *models.ShipmentDetails {
+	if x != nil {
+		return x.Shipment
+	}
+	return nil
+}
+
+func (x *CheckTrackingResponse) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *CheckTrackingResponse) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+var File_worker_api_worker_proto protoreflect.FileDescriptor
+
+const file_worker_api_worker_proto_rawDesc = "" +
+	"\n" +
+	"\x17worker_api/worker.proto\x12\x12trackbox.worker.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bmodels/tracking_model.proto\"r\n" +
+	"\x14CheckTrackingRequest\x128\n" +
+	"\btracking\x18\x01 \x01(\v2\x1c.trackbox.models.v1.TrackingR\btracking\x12 \n" +
+	"\vfingerprint\x18\x02 \x01(\tR\vfingerprint\"\xca\x03\n" +
+	"\x15CheckTrackingResponse\x129\n" +
+	"\n" +
+	"checked_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"status_raw\x18\x03 \x01(\tR\tstatusRaw\x127\n" +
+	"\tstatus_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bstatusAt\x12>\n" +
+	"\rnext_check_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vnextCheckAt\x129\n" +
+	"\x06events\x18\x06 \x03(\v2!.trackbox.models.v1.TrackingEventR\x06events\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12?\n" +
+	"\bshipment\x18\b \x01(\v2#.trackbox.models.v1.ShipmentDetailsR\bshipment\x12\x12\n" +
+	"\x04kind\x18\t \x01(\tR\x04kind\x12 \n" +
+	"\vfingerprint\x18\n" +
+	" \x01(\tR\vfingerprint2u\n" +
+	"\rWorkerService\x12d\n" +
+	"\rCheckTracking\x12(.trackbox.worker.v1.CheckTrackingRequest\x1a).trackbox.worker.v1.CheckTrackingResponseB5Z3github.com/BearBump/TrackBox/internal/pb/worker_apib\x06proto3"
+
+var (
+	file_worker_api_worker_proto_rawDescOnce sync.Once
+	file_worker_api_worker_proto_rawDescData []byte
+)
+
+func file_worker_api_worker_proto_rawDescGZIP() []byte {
+	file_worker_api_worker_proto_rawDescOnce.Do(func() {
+		file_worker_api_worker_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_worker_api_worker_proto_rawDesc), len(file_worker_api_worker_proto_rawDesc)))
+	})
+	return file_worker_api_worker_proto_rawDescData
+}
+
+var file_worker_api_worker_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_worker_api_worker_proto_goTypes = []any{
+	(*CheckTrackingRequest)(nil),   // 0: trackbox.worker.v1.CheckTrackingRequest
+	(*CheckTrackingResponse)(nil),  // 1: trackbox.worker.v1.CheckTrackingResponse
+	(*models.Tracking)(nil),        // 2: trackbox.models.v1.Tracking
+	(*timestamppb.Timestamp)(nil),  // 3: google.protobuf.Timestamp
+	(*models.TrackingEvent)(nil),   // 4: trackbox.models.v1.TrackingEvent
+	(*models.ShipmentDetails)(nil), // 5: trackbox.models.v1.ShipmentDetails
+}
+var file_worker_api_worker_proto_depIdxs = []int32{
+	2, // 0: trackbox.worker.v1.CheckTrackingRequest.tracking:type_name -> trackbox.models.v1.Tracking
+	3, // 1: trackbox.worker.v1.CheckTrackingResponse.checked_at:type_name -> google.protobuf.Timestamp
+	3, // 2: trackbox.worker.v1.CheckTrackingResponse.status_at:type_name -> google.protobuf.Timestamp
+	3, // 3: trackbox.worker.v1.CheckTrackingResponse.next_check_at:type_name -> google.protobuf.Timestamp
+	4, // 4: trackbox.worker.v1.CheckTrackingResponse.events:type_name -> trackbox.models.v1.TrackingEvent
+	5, // 5: trackbox.worker.v1.CheckTrackingResponse.shipment:type_name -> trackbox.models.v1.ShipmentDetails
+	0, // 6: trackbox.worker.v1.WorkerService.CheckTracking:input_type -> trackbox.worker.v1.CheckTrackingRequest
+	1, // 7: trackbox.worker.v1.WorkerService.CheckTracking:output_type -> trackbox.worker.v1.CheckTrackingResponse
+	7, // [7:8] is the sub-list for method output_type
+	6, // [6:7] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_worker_api_worker_proto_init() }
+func file_worker_api_worker_proto_init() {
+	if File_worker_api_worker_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_worker_api_worker_proto_rawDesc), len(file_worker_api_worker_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_worker_api_worker_proto_goTypes,
+		DependencyIndexes: file_worker_api_worker_proto_depIdxs,
+		MessageInfos:      file_worker_api_worker_proto_msgTypes,
+	}.Build()
+	File_worker_api_worker_proto = out.File
+	file_worker_api_worker_proto_goTypes = nil
+	file_worker_api_worker_proto_depIdxs = nil
+}
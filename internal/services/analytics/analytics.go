@@ -0,0 +1,278 @@
+// Package analytics — агрегаты по перевозчикам для track-api: распределение по статусам, время доставки,
+// объём проверок и доля ошибок. Считается по rollup-таблицам, которые pgtracking ведёт при применении
+// проверок, так что запросы не сканируют trackings/tracking_events.
+package analytics
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/tracknumber"
+	"github.com/pkg/errors"
+)
+
+type Repository interface {
+	StatusCounts(ctx context.Context, carrierCode string) ([]models.StatusCount, error)
+	DailyStats(ctx context.Context, carrierCode string, from, to time.Time) ([]models.CarrierDayStats, error)
+	StatusEntries(ctx context.Context, carrierCode string, from, to time.Time) ([]models.StatusEntries, error)
+	TransitHistogram(ctx context.Context, carrierCode string, from, to time.Time) ([]models.TransitHistogramRow, error)
+}
+
+// ErrInvalidArgument — запрос не прошёл проверку (текст ошибки — что именно не так).
+var ErrInvalidArgument = errors.New("invalid argument")
+
+const (
+	// DefaultRange — период по умолчанию, если from не задан.
+	DefaultRange = 30 * 24 * time.Hour
+	// MaxRange — самый длинный период одного запроса.
+	MaxRange       = 2 * 366 * 24 * time.Hour
+	maxPercentiles = 10
+)
+
+// DefaultPercentiles — перцентили времени доставки, если в запросе не заданы.
+var DefaultPercentiles = []float64{50, 90, 95}
+
+// Query — период [From, To] (по дням UTC, включительно), шаг и перевозчик (пусто — все).
+type Query struct {
+	CarrierCode string
+	From, To    time.Time
+	Bucket      string
+}
+
+type Service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+func New(repo Repository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+// normalize подставляет значения по умолчанию (до «сейчас», за DefaultRange, по дням) и проверяет запрос.
+func (s *Service) normalize(q Query) (Query, error) {
+	q.CarrierCode = tracknumber.CanonicalCarrier(q.CarrierCode)
+	q.Bucket = strings.ToUpper(strings.TrimSpace(q.Bucket))
+	switch q.Bucket {
+	case "":
+		q.Bucket = models.AnalyticsBucketDay
+	case models.AnalyticsBucketDay, models.AnalyticsBucketWeek, models.AnalyticsBucketMonth:
+	default:
+		return q, errors.Wrapf(ErrInvalidArgument, "unknown bucket %q (want DAY|WEEK|MONTH)", q.Bucket)
+	}
+	if q.To.IsZero() {
+		q.To = s.now()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-DefaultRange)
+	}
+	q.From, q.To = q.From.UTC(), q.To.UTC()
+	if q.From.After(q.To) {
+		return q, errors.Wrap(ErrInvalidArgument, "from must not be after to")
+	}
+	if q.To.Sub(q.From) > MaxRange {
+		return q, errors.Wrap(ErrInvalidArgument, "period is longer than 2 years")
+	}
+	return q, nil
+}
+
+// bucketStart — начало интервала, в который попадает день.
+func bucketStart(day time.Time, bucket string) time.Time {
+	day = day.UTC().Truncate(24 * time.Hour)
+	switch bucket {
+	case models.AnalyticsBucketWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case models.AnalyticsBucketMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+type bucketKey struct {
+	start   time.Time
+	carrier string
+}
+
+// grouper собирает дневные строки в интервалы и отдаёт их по началу интервала и перевозчику.
+type grouper[T any] struct {
+	bucket string
+	byKey  map[bucketKey]*T
+	newFn  func(bucketKey) *T
+}
+
+func newGrouper[T any](bucket string, newFn func(bucketKey) *T) *grouper[T] {
+	return &grouper[T]{bucket: bucket, byKey: map[bucketKey]*T{}, newFn: newFn}
+}
+
+func (g *grouper[T]) get(day time.Time, carrier string) *T {
+	k := bucketKey{bucketStart(day, g.bucket), carrier}
+	v, ok := g.byKey[k]
+	if !ok {
+		v = g.newFn(k)
+		g.byKey[k] = v
+	}
+	return v
+}
+
+func (g *grouper[T]) sorted() []*T {
+	keys := make([]bucketKey, 0, len(g.byKey))
+	for k := range g.byKey {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b bucketKey) int {
+		if c := a.start.Compare(b.start); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.carrier, b.carrier)
+	})
+	out := make([]*T, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, g.byKey[k])
+	}
+	return out
+}
+
+// StatusDistribution — сколько треков сейчас в каждом статусе и сколько переходило в статусы по интервалам.
+func (s *Service) StatusDistribution(ctx context.Context, q Query) ([]models.StatusCount, []*models.StatusBucket, error) {
+	q, err := s.normalize(q)
+	if err != nil {
+		return nil, nil, err
+	}
+	current, err := s.repo.StatusCounts(ctx, q.CarrierCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := s.repo.StatusEntries(ctx, q.CarrierCode, q.From, q.To)
+	if err != nil {
+		return nil, nil, err
+	}
+	g := newGrouper(q.Bucket, func(k bucketKey) *models.StatusBucket {
+		return &models.StatusBucket{Start: k.start, CarrierCode: k.carrier, Entered: map[string]int64{}}
+	})
+	for _, r := range rows {
+		g.get(r.Day, r.CarrierCode).Entered[r.Status] += r.Entered
+	}
+	return current, g.sorted(), nil
+}
+
+// CheckStats — объём проверок, ошибки и доставки по интервалам.
+func (s *Service) CheckStats(ctx context.Context, q Query) ([]*models.CheckBucket, error) {
+	q, err := s.normalize(q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.DailyStats(ctx, q.CarrierCode, q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+	g := newGrouper(q.Bucket, func(k bucketKey) *models.CheckBucket {
+		return &models.CheckBucket{Start: k.start, CarrierCode: k.carrier}
+	})
+	for _, r := range rows {
+		b := g.get(r.Day, r.CarrierCode)
+		b.Checks += r.Checks
+		b.FailedChecks += r.FailedChecks
+		b.StatusChanges += r.StatusChanges
+		b.Delivered += r.Delivered
+	}
+	out := g.sorted()
+	for _, b := range out {
+		if b.Checks > 0 {
+			b.FailureRate = float64(b.FailedChecks) / float64(b.Checks)
+		}
+	}
+	return out, nil
+}
+
+// TransitTimes — перцентили времени доставки по интервалам (по дню доставки).
+// percentiles — в (0, 100]; пусто — DefaultPercentiles.
+func (s *Service) TransitTimes(ctx context.Context, q Query, percentiles []float64) ([]*models.TransitBucket, error) {
+	q, err := s.normalize(q)
+	if err != nil {
+		return nil, err
+	}
+	if len(percentiles) == 0 {
+		percentiles = DefaultPercentiles
+	}
+	if len(percentiles) > maxPercentiles {
+		return nil, errors.Wrapf(ErrInvalidArgument, "at most %d percentiles", maxPercentiles)
+	}
+	for _, p := range percentiles {
+		if p <= 0 || p > 100 {
+			return nil, errors.Wrapf(ErrInvalidArgument, "percentile %v is out of (0, 100]", p)
+		}
+	}
+	rows, err := s.repo.TransitHistogram(ctx, q.CarrierCode, q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+
+	type acc struct {
+		bucket *models.TransitBucket
+		hist   []int64
+	}
+	g := newGrouper(q.Bucket, func(k bucketKey) *acc {
+		return &acc{
+			bucket: &models.TransitBucket{Start: k.start, CarrierCode: k.carrier},
+			hist:   make([]int64, len(models.TransitBucketBounds)),
+		}
+	})
+	for _, r := range rows {
+		if r.Bucket < 0 || r.Bucket >= len(models.TransitBucketBounds) {
+			continue
+		}
+		a := g.get(r.Day, r.CarrierCode)
+		a.hist[r.Bucket] += r.Deliveries
+		a.bucket.Deliveries += r.Deliveries
+	}
+	accs := g.sorted()
+	out := make([]*models.TransitBucket, 0, len(accs))
+	for _, a := range accs {
+		for _, p := range percentiles {
+			a.bucket.Percentiles = append(a.bucket.Percentiles, models.TransitPercentile{
+				Percentile: p,
+				Value:      histogramPercentile(a.hist, p),
+			})
+		}
+		out = append(out, a.bucket)
+	}
+	return out, nil
+}
+
+// histogramPercentile оценивает перцентиль по гистограмме (корзины models.TransitBucketBounds):
+// линейно внутри корзины, для открытой последней корзины — её нижняя граница.
+func histogramPercentile(hist []int64, p float64) time.Duration {
+	var total int64
+	for _, c := range hist {
+		total += c
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(total)
+	var cum float64
+	bounds := models.TransitBucketBounds
+	for i, c := range hist {
+		if c == 0 {
+			continue
+		}
+		if cum+float64(c) >= rank {
+			lo := bounds[i]
+			if i+1 >= len(bounds) {
+				return hours(lo)
+			}
+			frac := (rank - cum) / float64(c)
+			return hours(lo + frac*(bounds[i+1]-lo))
+		}
+		cum += float64(c)
+	}
+	return hours(bounds[len(bounds)-1])
+}
+
+func hours(h float64) time.Duration {
+	return time.Duration(h * float64(time.Hour)).Round(time.Minute)
+}
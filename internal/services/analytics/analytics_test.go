@@ -0,0 +1,127 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	counts  []models.StatusCount
+	daily   []models.CarrierDayStats
+	entries []models.StatusEntries
+	hist    []models.TransitHistogramRow
+
+	carrier  string
+	from, to time.Time
+}
+
+func (f *fakeRepo) StatusCounts(_ context.Context, carrierCode string) ([]models.StatusCount, error) {
+	f.carrier = carrierCode
+	return f.counts, nil
+}
+
+func (f *fakeRepo) DailyStats(_ context.Context, carrierCode string, from, to time.Time) ([]models.CarrierDayStats, error) {
+	f.carrier, f.from, f.to = carrierCode, from, to
+	return f.daily, nil
+}
+
+func (f *fakeRepo) StatusEntries(_ context.Context, carrierCode string, from, to time.Time) ([]models.StatusEntries, error) {
+	f.carrier, f.from, f.to = carrierCode, from, to
+	return f.entries, nil
+}
+
+func (f *fakeRepo) TransitHistogram(_ context.Context, carrierCode string, from, to time.Time) ([]models.TransitHistogramRow, error) {
+	f.carrier, f.from, f.to = carrierCode, from, to
+	return f.hist, nil
+}
+
+func day(d int) time.Time { return time.Date(2026, 6, d, 0, 0, 0, 0, time.UTC) }
+
+func TestBucketStart(t *testing.T) {
+	// 2026-06-03 — среда.
+	require.Equal(t, day(3), bucketStart(day(3).Add(5*time.Hour), models.AnalyticsBucketDay))
+	require.Equal(t, day(1), bucketStart(day(3), models.AnalyticsBucketWeek))
+	require.Equal(t, day(1), bucketStart(day(7), models.AnalyticsBucketWeek))
+	require.Equal(t, day(8), bucketStart(day(8), models.AnalyticsBucketWeek))
+	require.Equal(t, day(1), bucketStart(day(30), models.AnalyticsBucketMonth))
+}
+
+func TestCheckStats_Weekly(t *testing.T) {
+	repo := &fakeRepo{daily: []models.CarrierDayStats{
+		{Day: day(1), CarrierCode: "CDEK", Checks: 10, FailedChecks: 1, Delivered: 2},
+		{Day: day(1), CarrierCode: "POST_RU", Checks: 4, FailedChecks: 2},
+		{Day: day(2), CarrierCode: "CDEK", Checks: 10, FailedChecks: 3, StatusChanges: 5},
+		{Day: day(8), CarrierCode: "CDEK", Checks: 5},
+	}}
+	s := New(repo)
+	out, err := s.CheckStats(context.Background(), Query{From: day(1), To: day(14), Bucket: "week"})
+	require.NoError(t, err)
+	require.Len(t, out, 3)
+	require.Equal(t, models.CheckBucket{Start: day(1), CarrierCode: "CDEK", Checks: 20, FailedChecks: 4, FailureRate: 0.2, StatusChanges: 5, Delivered: 2}, *out[0])
+	require.Equal(t, "POST_RU", out[1].CarrierCode)
+	require.Equal(t, 0.5, out[1].FailureRate)
+	require.Equal(t, day(8), out[2].Start)
+}
+
+func TestStatusDistribution(t *testing.T) {
+	repo := &fakeRepo{
+		counts: []models.StatusCount{{CarrierCode: "CDEK", Status: models.TrackingStatusInTransit, Trackings: 7}},
+		entries: []models.StatusEntries{
+			{Day: day(1), CarrierCode: "CDEK", Status: models.TrackingStatusInTransit, Entered: 3},
+			{Day: day(20), CarrierCode: "CDEK", Status: models.TrackingStatusDelivered, Entered: 2},
+			{Day: day(21), CarrierCode: "CDEK", Status: models.TrackingStatusDelivered, Entered: 1},
+		},
+	}
+	s := New(repo)
+	s.now = func() time.Time { return day(25) }
+	current, buckets, err := s.StatusDistribution(context.Background(), Query{CarrierCode: "cdek", Bucket: models.AnalyticsBucketMonth})
+	require.NoError(t, err)
+	require.Equal(t, "CDEK", repo.carrier)
+	require.Equal(t, day(25).Add(-DefaultRange), repo.from)
+	require.Equal(t, repo.counts, current)
+	require.Len(t, buckets, 1)
+	require.Equal(t, map[string]int64{models.TrackingStatusInTransit: 3, models.TrackingStatusDelivered: 3}, buckets[0].Entered)
+}
+
+func TestTransitTimes(t *testing.T) {
+	// 10 доставок: 5 в корзине [24h, 36h), 5 в [48h, 60h).
+	b24 := models.TransitBucketIndex(30 * time.Hour)
+	b48 := models.TransitBucketIndex(50 * time.Hour)
+	repo := &fakeRepo{hist: []models.TransitHistogramRow{
+		{Day: day(1), CarrierCode: "CDEK", Bucket: b24, Deliveries: 5},
+		{Day: day(2), CarrierCode: "CDEK", Bucket: b48, Deliveries: 5},
+	}}
+	out, err := New(repo).TransitTimes(context.Background(), Query{From: day(1), To: day(7), Bucket: "MONTH"}, []float64{10, 50, 90})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.EqualValues(t, 10, out[0].Deliveries)
+	got := map[float64]time.Duration{}
+	for _, p := range out[0].Percentiles {
+		got[p.Percentile] = p.Value
+	}
+	require.Equal(t, map[float64]time.Duration{10: 26*time.Hour + 24*time.Minute, 50: 36 * time.Hour, 90: 57*time.Hour + 36*time.Minute}, got)
+
+	_, err = New(repo).TransitTimes(context.Background(), Query{}, []float64{0})
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	s := New(&fakeRepo{})
+	_, err := s.CheckStats(context.Background(), Query{Bucket: "HOUR"})
+	require.ErrorIs(t, err, ErrInvalidArgument)
+	_, err = s.CheckStats(context.Background(), Query{From: day(10), To: day(1)})
+	require.ErrorIs(t, err, ErrInvalidArgument)
+	_, err = s.CheckStats(context.Background(), Query{From: day(1).AddDate(-3, 0, 0), To: day(1)})
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestTransitBucketIndex(t *testing.T) {
+	require.Equal(t, 0, models.TransitBucketIndex(-time.Hour))
+	require.Equal(t, 0, models.TransitBucketIndex(30*time.Minute))
+	require.Equal(t, 1, models.TransitBucketIndex(time.Hour))
+	require.Equal(t, len(models.TransitBucketBounds)-1, models.TransitBucketIndex(10000*time.Hour))
+}
@@ -0,0 +1,247 @@
+package bulk
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"github.com/pkg/errors"
+)
+
+// exportPageSize — треков на страницу выборки (и на один запрос событий).
+const exportPageSize = 500
+
+type ExportOptions struct {
+	Format string // FormatJSONL (по умолчанию) | FormatCSV
+	Filter pgtracking.TrackingFilter
+	// Events — добавить историю событий: в JSONL массивом "events", в CSV — строкой на событие.
+	Events bool
+}
+
+// Export пишет треки (и, опционально, события) в w постранично, не держа выгрузку в памяти.
+// Возвращает число выгруженных треков.
+func (s *Service) Export(ctx context.Context, w io.Writer, opts ExportOptions) (int, error) {
+	if opts.Format == "" {
+		opts.Format = FormatJSONL
+	}
+	ew, err := newExportWriter(w, opts.Format, opts.Events)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	var after uint64
+	for {
+		page, err := s.repo.ScanTrackings(ctx, opts.Filter, after, exportPageSize)
+		if err != nil {
+			return n, err
+		}
+		var events map[uint64][]*models.TrackingEvent
+		if opts.Events && len(page) > 0 {
+			ids := make([]uint64, 0, len(page))
+			for _, t := range page {
+				ids = append(ids, t.ID)
+			}
+			if events, err = s.repo.ListEventsForTrackings(ctx, ids); err != nil {
+				return n, err
+			}
+		}
+		for _, t := range page {
+			if err := ew.write(t, events[t.ID]); err != nil {
+				return n, errors.Wrap(err, "write export")
+			}
+			n++
+		}
+		if err := ew.flush(); err != nil {
+			return n, errors.Wrap(err, "write export")
+		}
+		if len(page) < exportPageSize {
+			return n, nil
+		}
+		after = page[len(page)-1].ID
+	}
+}
+
+type exportEvent struct {
+	Status    string          `json:"status"`
+	StatusRaw string          `json:"status_raw"`
+	EventTime time.Time       `json:"event_time"`
+	Location  *string         `json:"location,omitempty"`
+	Message   *string         `json:"message,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	Place     *exportPlace    `json:"place,omitempty"`
+}
+
+type exportPlace struct {
+	Country    string   `json:"country,omitempty"`
+	Region     string   `json:"region,omitempty"`
+	City       string   `json:"city,omitempty"`
+	PostalCode string   `json:"postal_code,omitempty"`
+	Lat        *float64 `json:"lat,omitempty"`
+	Lon        *float64 `json:"lon,omitempty"`
+}
+
+// exportRecord — строка JSONL-выгрузки.
+type exportRecord struct {
+	ID             uint64          `json:"id"`
+	CarrierCode    string          `json:"carrier_code"`
+	TrackNumber    string          `json:"track_number"`
+	Status         string          `json:"status"`
+	StatusRaw      string          `json:"status_raw"`
+	StatusAt       *time.Time      `json:"status_at,omitempty"`
+	LastCheckedAt  *time.Time      `json:"last_checked_at,omitempty"`
+	NextCheckAt    time.Time       `json:"next_check_at"`
+	CheckFailCount int32           `json:"check_fail_count"`
+	LastError      *string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	ExternalID     *string         `json:"external_id,omitempty"`
+	Tags           []string        `json:"tags,omitempty"`
+	Metadata       json.RawMessage `json:"metadata,omitempty"`
+	Events         []exportEvent   `json:"events,omitempty"`
+}
+
+var (
+	csvTrackingHeader = []string{
+		"id", "carrier_code", "track_number", "status", "status_raw",
+		"status_at", "last_checked_at", "next_check_at", "check_fail_count", "last_error",
+		"created_at", "updated_at", "external_id", "tags", "metadata",
+	}
+	csvEventHeader = []string{
+		"event_status", "event_status_raw", "event_time", "event_location", "event_message",
+		"event_country", "event_region", "event_city", "event_postal_code",
+	}
+)
+
+type exportWriter struct {
+	events bool
+	csv    *csv.Writer
+	json   *json.Encoder
+}
+
+func newExportWriter(w io.Writer, format string, events bool) (*exportWriter, error) {
+	ew := &exportWriter{events: events}
+	switch format {
+	case FormatCSV:
+		ew.csv = csv.NewWriter(w)
+		header := csvTrackingHeader
+		if events {
+			header = append(append([]string{}, csvTrackingHeader...), csvEventHeader...)
+		}
+		if err := ew.csv.Write(header); err != nil {
+			return nil, err
+		}
+	case FormatJSONL:
+		ew.json = json.NewEncoder(w)
+	default:
+		return nil, errors.Errorf("unknown format %q (want csv|jsonl)", format)
+	}
+	return ew, nil
+}
+
+func (ew *exportWriter) write(t *models.Tracking, evs []*models.TrackingEvent) error {
+	if ew.json != nil {
+		rec := exportRecord{
+			ID:             t.ID,
+			CarrierCode:    t.CarrierCode,
+			TrackNumber:    t.TrackNumber,
+			Status:         t.Status,
+			StatusRaw:      t.StatusRaw,
+			StatusAt:       t.StatusAt,
+			LastCheckedAt:  t.LastCheckedAt,
+			NextCheckAt:    t.NextCheckAt,
+			CheckFailCount: t.CheckFailCount,
+			LastError:      t.LastError,
+			CreatedAt:      t.CreatedAt,
+			UpdatedAt:      t.UpdatedAt,
+			ExternalID:     t.ExternalID,
+			Tags:           t.Tags,
+		}
+		if t.Metadata != nil {
+			rec.Metadata = json.RawMessage(*t.Metadata)
+		}
+		for _, e := range evs {
+			var payload json.RawMessage
+			if e.PayloadJSON != nil && *e.PayloadJSON != "" {
+				payload = json.RawMessage(*e.PayloadJSON)
+			}
+			rec.Events = append(rec.Events, exportEvent{
+				Status:    e.Status,
+				StatusRaw: e.StatusRaw,
+				EventTime: e.EventTime,
+				Location:  e.Location,
+				Message:   e.Message,
+				Payload:   payload,
+				Place:     toExportPlace(e.Place),
+			})
+		}
+		return ew.json.Encode(rec)
+	}
+
+	base := []string{
+		strconv.FormatUint(t.ID, 10), t.CarrierCode, t.TrackNumber, t.Status, t.StatusRaw,
+		csvTime(t.StatusAt), csvTime(t.LastCheckedAt), csvTime(&t.NextCheckAt), strconv.Itoa(int(t.CheckFailCount)), csvString(t.LastError),
+		csvTime(&t.CreatedAt), csvTime(&t.UpdatedAt), csvString(t.ExternalID), strings.Join(t.Tags, ";"), csvString(t.Metadata),
+	}
+	if !ew.events {
+		return ew.csv.Write(base)
+	}
+	if len(evs) == 0 {
+		return ew.csv.Write(append(base, make([]string, len(csvEventHeader))...))
+	}
+	for _, e := range evs {
+		var place models.Location
+		if e.Place != nil {
+			place = *e.Place
+		}
+		row := append(append([]string{}, base...),
+			e.Status, e.StatusRaw, csvTime(&e.EventTime), csvString(e.Location), csvString(e.Message),
+			place.Country, place.Region, place.City, place.PostalCode)
+		if err := ew.csv.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toExportPlace(l *models.Location) *exportPlace {
+	if l.IsEmpty() {
+		return nil
+	}
+	return &exportPlace{
+		Country:    l.Country,
+		Region:     l.Region,
+		City:       l.City,
+		PostalCode: l.PostalCode,
+		Lat:        l.Lat,
+		Lon:        l.Lon,
+	}
+}
+
+func (ew *exportWriter) flush() error {
+	if ew.csv == nil {
+		return nil
+	}
+	ew.csv.Flush()
+	return ew.csv.Error()
+}
+
+func csvTime(v *time.Time) string {
+	if v == nil {
+		return ""
+	}
+	return v.UTC().Format(time.RFC3339)
+}
+
+func csvString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,242 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/services/trackings"
+	"github.com/BearBump/TrackBox/internal/tracknumber"
+	"github.com/pkg/errors"
+)
+
+// Форматы импорта/экспорта.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// maxLineBytes — предел длины строки JSONL (и metadata в ней).
+const maxLineBytes = 1 << 20
+
+// DetectFormat: явный формат важнее расширения файла; по умолчанию — def.
+func DetectFormat(explicit, path, def string) (string, error) {
+	f := strings.ToLower(explicit)
+	if f == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			f = FormatCSV
+		case ".jsonl", ".ndjson":
+			f = FormatJSONL
+		default:
+			f = def
+		}
+	}
+	if f != FormatCSV && f != FormatJSONL {
+		return "", errors.Errorf("unknown format %q (want csv|jsonl)", explicit)
+	}
+	return f, nil
+}
+
+// RowReader читает файл импорта построчно, не загружая его в память.
+// Ошибки отдельных строк не прерывают чтение: они попадают в ImportRow.Error.
+//
+// CSV: carrier_code,track_number[,metadata[,tags[,external_id]]]; теги через ';'.
+// Строка-заголовок необязательна, с ней порядок колонок любой.
+// JSONL: {"carrier_code":..,"track_number":..,"metadata":{..},"tags":[..],"external_id":..}
+// (camelCase ключи тоже принимаются).
+type RowReader struct {
+	format string
+
+	csv     *csv.Reader
+	cols    map[string]int // CSV: колонка -> индекс
+	started bool           // CSV: первая запись уже прочитана (дальше заголовок не ищем)
+	scan    *bufio.Scanner
+	line    int64
+
+	carriers *tracknumber.Registry
+}
+
+func NewRowReader(r io.Reader, format string) (*RowReader, error) {
+	rr := &RowReader{format: format, carriers: tracknumber.Default()}
+	switch format {
+	case FormatCSV:
+		rr.csv = csv.NewReader(r)
+		rr.csv.FieldsPerRecord = -1
+		rr.csv.TrimLeadingSpace = true
+		rr.cols = map[string]int{"carrier_code": 0, "track_number": 1, "metadata": 2, "tags": 3, "external_id": 4}
+	case FormatJSONL:
+		rr.scan = bufio.NewScanner(r)
+		rr.scan.Buffer(make([]byte, 64*1024), maxLineBytes)
+	default:
+		return nil, errors.Errorf("unknown format %q", format)
+	}
+	return rr, nil
+}
+
+// WithCarriers задаёт справочник перевозчиков для проверки строк (по умолчанию tracknumber.Default()).
+func (rr *RowReader) WithCarriers(r *tracknumber.Registry) *RowReader {
+	if r != nil {
+		rr.carriers = r
+	}
+	return rr
+}
+
+// Next возвращает следующую непустую строку или io.EOF. Прочие ошибки — ошибки чтения самого файла.
+func (rr *RowReader) Next() (models.ImportRow, error) {
+	if rr.format == FormatCSV {
+		return rr.nextCSV()
+	}
+	return rr.nextJSONL()
+}
+
+func (rr *RowReader) nextCSV() (models.ImportRow, error) {
+	for {
+		rec, err := rr.csv.Read()
+		if err == io.EOF {
+			return models.ImportRow{}, io.EOF
+		}
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			// Битая строка (кавычки и т.п.) — отмечаем и читаем дальше.
+			rr.started = true
+			return invalidRow(int64(perr.StartLine), perr.Err.Error()), nil
+		}
+		if err != nil {
+			return models.ImportRow{}, errors.Wrap(err, "read csv")
+		}
+		line, _ := rr.csv.FieldPos(0)
+		first := !rr.started
+		rr.started = true
+		rr.line = int64(line)
+
+		if len(rec) == 1 && strings.TrimSpace(rec[0]) == "" {
+			continue
+		}
+		if first && rr.header(rec) {
+			continue
+		}
+
+		get := func(col string) string {
+			if i, ok := rr.cols[col]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		row := models.ImportRow{Line: rr.line, CarrierCode: get("carrier_code"), TrackNumber: get("track_number")}
+		if m := get("metadata"); m != "" {
+			row.Metadata = &m
+		}
+		if tags := get("tags"); tags != "" {
+			row.Tags = strings.Split(tags, ";")
+		}
+		if id := get("external_id"); id != "" {
+			row.ExternalID = &id
+		}
+		return rr.validate(row), nil
+	}
+}
+
+// header распознаёт строку-заголовок и запоминает порядок колонок.
+func (rr *RowReader) header(rec []string) bool {
+	cols := map[string]int{}
+	for i, name := range rec {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := cols["carrier_code"]; !ok {
+		return false
+	}
+	rr.cols = cols
+	return true
+}
+
+type jsonRow struct {
+	CarrierCode  string          `json:"carrier_code"`
+	TrackNumber  string          `json:"track_number"`
+	CarrierCodeC string          `json:"carrierCode"`
+	TrackNumberC string          `json:"trackNumber"`
+	Metadata     json.RawMessage `json:"metadata"`
+	Tags         []string        `json:"tags"`
+	ExternalID   string          `json:"external_id"`
+	ExternalIDC  string          `json:"externalId"`
+}
+
+func (rr *RowReader) nextJSONL() (models.ImportRow, error) {
+	for rr.scan.Scan() {
+		rr.line++
+		text := bytes.TrimSpace(rr.scan.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var j jsonRow
+		if err := json.Unmarshal(text, &j); err != nil {
+			return invalidRow(rr.line, "invalid json: "+err.Error()), nil
+		}
+		row := models.ImportRow{Line: rr.line, CarrierCode: strings.TrimSpace(j.CarrierCode), TrackNumber: strings.TrimSpace(j.TrackNumber)}
+		if row.CarrierCode == "" {
+			row.CarrierCode = strings.TrimSpace(j.CarrierCodeC)
+		}
+		if row.TrackNumber == "" {
+			row.TrackNumber = strings.TrimSpace(j.TrackNumberC)
+		}
+		if len(j.Metadata) > 0 && string(j.Metadata) != "null" {
+			m := string(j.Metadata)
+			row.Metadata = &m
+		}
+		row.Tags = j.Tags
+		if id := strings.TrimSpace(j.ExternalID + j.ExternalIDC); id != "" {
+			row.ExternalID = &id
+		}
+		return rr.validate(row), nil
+	}
+	if err := rr.scan.Err(); err != nil {
+		return models.ImportRow{}, errors.Wrapf(err, "read jsonl after line %d", rr.line)
+	}
+	return models.ImportRow{}, io.EOF
+}
+
+// validate приводит перевозчика, номер и атрибуты к канонической форме (пустой carrier_code —
+// автоопределение) и помечает строку невалидной с причиной.
+func (rr *RowReader) validate(row models.ImportRow) models.ImportRow {
+	if err := rr.normalize(&row); err != nil {
+		reason := err.Error()
+		row.Error = &reason
+	}
+	return row
+}
+
+func (rr *RowReader) normalize(row *models.ImportRow) error {
+	if row.TrackNumber == "" {
+		return errors.New("track_number is required")
+	}
+	var err error
+	if row.Metadata, err = trackings.NormalizeMetadata(row.Metadata); err != nil {
+		return err
+	}
+	if row.Tags, err = trackings.NormalizeTags(row.Tags); err != nil {
+		return err
+	}
+	if row.ExternalID, err = trackings.NormalizeExternalID(row.ExternalID); err != nil {
+		return err
+	}
+	row.CarrierCode, row.TrackNumber, err = rr.carriers.Normalize(row.CarrierCode, row.TrackNumber)
+	return err
+}
+
+func invalidRow(line int64, reason string) models.ImportRow {
+	return models.ImportRow{Line: line, Error: &reason}
+}
+
+// RowError — человекочитаемая ошибка строки (для CLI).
+func RowError(r models.ImportRow) string {
+	if r.Error == nil {
+		return ""
+	}
+	return fmt.Sprintf("line %d: %s", r.Line, *r.Error)
+}
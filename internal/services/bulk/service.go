@@ -0,0 +1,352 @@
+// Package bulk — массовый импорт треков из CSV/JSONL асинхронными задачами и потоковый экспорт.
+package bulk
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/services/trackings"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"github.com/BearBump/TrackBox/internal/tracknumber"
+	"github.com/pkg/errors"
+)
+
+type Repository interface {
+	CreateOrGetTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]*models.Tracking, error)
+	ScanTrackings(ctx context.Context, f pgtracking.TrackingFilter, afterID uint64, limit int) ([]*models.Tracking, error)
+	ListEventsForTrackings(ctx context.Context, trackingIDs []uint64) (map[uint64][]*models.TrackingEvent, error)
+
+	CreateImportJob(ctx context.Context, format, source string) (*models.ImportJob, error)
+	AddImportRows(ctx context.Context, jobID uint64, rows []models.ImportRow) error
+	FinishImportUpload(ctx context.Context, jobID uint64, total, invalid int64) error
+	FailImportJob(ctx context.Context, jobID uint64, reason string) error
+	ClaimImportJob(ctx context.Context, now time.Time, lease time.Duration) (*models.ImportJob, error)
+	NextImportRows(ctx context.Context, jobID uint64, afterLine int64, limit int) ([]models.ImportRow, error)
+	AdvanceImportJob(ctx context.Context, jobID uint64, cursor, processed, imported int64, leaseUntil time.Time) error
+	ReleaseImportJob(ctx context.Context, jobID uint64, reason string) error
+	CompleteImportJob(ctx context.Context, jobID uint64) error
+	RetryImportJob(ctx context.Context, jobID uint64) (*models.ImportJob, error)
+	GetImportJob(ctx context.Context, jobID uint64) (*models.ImportJob, error)
+	ListImportErrors(ctx context.Context, jobID uint64, limit, offset int) ([]models.ImportRow, error)
+}
+
+// Creator создаёт треки так же, как API (trackings.Service): с проверкой номеров и сбросом кэша текущего состояния.
+type Creator interface {
+	CreateTrackingsBulk(ctx context.Context, items []models.TrackingCreateInput) ([]trackings.ItemResult, error)
+}
+
+// ErrJobNotFound — задачи нет (или она не в том статусе для операции).
+var ErrJobNotFound = pgtracking.ErrImportJobNotFound
+
+type Config struct {
+	UploadChunk int           // строк на один COPY при загрузке; default: 1000
+	BatchSize   int           // строк на одну вставку треков при обработке; default: 500
+	Lease       time.Duration // аренда задачи обработчиком; default: 2m
+	MaxAttempts int32         // больше стольких захватов подряд без прогресса — задача FAILED; default: 5
+}
+
+func DefaultConfig() Config {
+	return Config{
+		UploadChunk: 1000,
+		BatchSize:   500,
+		Lease:       2 * time.Minute,
+		MaxAttempts: 5,
+	}
+}
+
+type Service struct {
+	repo Repository
+	cfg  Config
+	now  func() time.Time
+
+	carriers func() *tracknumber.Registry
+	creator  Creator
+}
+
+func New(repo Repository, cfg Config) *Service {
+	def := DefaultConfig()
+	if cfg.UploadChunk <= 0 {
+		cfg.UploadChunk = def.UploadChunk
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = def.BatchSize
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = def.Lease
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = def.MaxAttempts
+	}
+	return &Service{repo: repo, cfg: cfg, now: func() time.Time { return time.Now().UTC() }, carriers: tracknumber.Default}
+}
+
+// WithCarriers задаёт источник актуального справочника перевозчиков (по умолчанию tracknumber.Default).
+func (s *Service) WithCarriers(carriers func() *tracknumber.Registry) *Service {
+	if carriers != nil {
+		s.carriers = carriers
+	}
+	return s
+}
+
+// WithTrackings направляет создание треков при обработке через c. Без него треки пишутся прямо в репозиторий,
+// и только что запрошенный (а значит, запомненный кэшем как отсутствующий) трек ещё negativeTTL не находится.
+func (s *Service) WithTrackings(c Creator) *Service {
+	s.creator = c
+	return s
+}
+
+// StartImport читает файл потоком, сохраняет строки задачи (невалидные — с причиной) и ставит задачу в очередь.
+// Сами треки создаёт обработчик (RunImports / ProcessNext). Если чтение файла оборвалось, задача FAILED.
+func (s *Service) StartImport(ctx context.Context, r io.Reader, format, source string) (*models.ImportJob, error) {
+	rr, err := NewRowReader(r, format)
+	if err != nil {
+		return nil, err
+	}
+	rr.WithCarriers(s.carriers())
+	job, err := s.repo.CreateImportJob(ctx, format, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var total, invalid int64
+	chunk := make([]models.ImportRow, 0, s.cfg.UploadChunk)
+	fail := func(cause error) (*models.ImportJob, error) {
+		// ctx мог быть отменён (клиент оборвал загрузку) — помечаем задачу в отдельном контексте.
+		failCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := s.repo.FailImportJob(failCtx, job.ID, "upload: "+cause.Error()); err != nil {
+			slog.Error("mark import job failed", "job_id", job.ID, "error", err.Error())
+		}
+		return nil, errors.Wrapf(cause, "import job %d", job.ID)
+	}
+
+	for {
+		row, err := rr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fail(err)
+		}
+		total++
+		if row.Error != nil {
+			invalid++
+		}
+		chunk = append(chunk, row)
+		if len(chunk) == cap(chunk) {
+			if err := s.repo.AddImportRows(ctx, job.ID, chunk); err != nil {
+				return fail(err)
+			}
+			chunk = chunk[:0]
+		}
+	}
+	if err := s.repo.AddImportRows(ctx, job.ID, chunk); err != nil {
+		return fail(err)
+	}
+	if total == 0 {
+		return fail(errors.New("file has no rows"))
+	}
+	if err := s.repo.FinishImportUpload(ctx, job.ID, total, invalid); err != nil {
+		return fail(err)
+	}
+	return s.repo.GetImportJob(ctx, job.ID)
+}
+
+// ValidateImport прогоняет файл через парсер без записи (dry run). Возвращает число строк и невалидные строки.
+func ValidateImport(r io.Reader, format string) (int64, []models.ImportRow, error) {
+	rr, err := NewRowReader(r, format)
+	if err != nil {
+		return 0, nil, err
+	}
+	var total int64
+	var bad []models.ImportRow
+	for {
+		row, err := rr.Next()
+		if err == io.EOF {
+			return total, bad, nil
+		}
+		if err != nil {
+			return total, bad, err
+		}
+		total++
+		if row.Error != nil {
+			bad = append(bad, row)
+		}
+	}
+}
+
+func (s *Service) GetImportJob(ctx context.Context, id uint64) (*models.ImportJob, error) {
+	return s.repo.GetImportJob(ctx, id)
+}
+
+func (s *Service) ListImportErrors(ctx context.Context, id uint64, limit, offset int) ([]models.ImportRow, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := s.repo.GetImportJob(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListImportErrors(ctx, id, limit, offset)
+}
+
+// ResumeImport возвращает FAILED-задачу в очередь; уже обработанные строки повторно не трогаются.
+func (s *Service) ResumeImport(ctx context.Context, id uint64) (*models.ImportJob, error) {
+	return s.repo.RetryImportJob(ctx, id)
+}
+
+// RunImports обрабатывает задачи из очереди, пока ctx не отменён. Безопасно запускать на нескольких репликах.
+func (s *Service) RunImports(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		for {
+			ok, err := s.ProcessNext(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.Error("import job processing failed", "error", err.Error())
+			}
+			if !ok || err != nil {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// ProcessNext захватывает одну задачу и обрабатывает её до конца. false — очередь пуста.
+func (s *Service) ProcessNext(ctx context.Context) (bool, error) {
+	job, err := s.repo.ClaimImportJob(ctx, s.now(), s.cfg.Lease)
+	if err != nil || job == nil {
+		return false, err
+	}
+	return true, s.process(ctx, job)
+}
+
+// ProcessJob разбирает очередь по порядку до задачи id включительно (CLI: импорт «здесь и сейчас»)
+// и возвращает её состояние. Задачу, которую держит другой обработчик, не ждёт.
+func (s *Service) ProcessJob(ctx context.Context, id uint64) (*models.ImportJob, error) {
+	for {
+		job, err := s.repo.ClaimImportJob(ctx, s.now(), s.cfg.Lease)
+		if err != nil {
+			return nil, err
+		}
+		if job == nil {
+			break
+		}
+		if err := s.process(ctx, job); err != nil {
+			return nil, err
+		}
+		if job.ID == id {
+			break
+		}
+	}
+	return s.repo.GetImportJob(ctx, id)
+}
+
+func (s *Service) process(ctx context.Context, job *models.ImportJob) error {
+	if job.Attempts > s.cfg.MaxAttempts {
+		reason := "too many attempts"
+		if job.LastError != nil {
+			reason += ": " + *job.LastError
+		}
+		return s.repo.FailImportJob(ctx, job.ID, reason)
+	}
+	slog.Info("import job started", "job_id", job.ID, "cursor", job.Cursor, "total", job.TotalRows)
+
+	cursor := job.Cursor
+	for {
+		rows, err := s.repo.NextImportRows(ctx, job.ID, cursor, s.cfg.BatchSize)
+		if err != nil {
+			return s.release(ctx, job.ID, err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		items := make([]models.TrackingCreateInput, 0, len(rows))
+		lines := make([]int64, 0, len(rows)) // строк файла на элемент items (повторы номера — в одном)
+		seen := make(map[string]int, len(rows))
+		valid := int64(0)
+		for _, r := range rows {
+			if r.Error != nil {
+				continue
+			}
+			valid++
+			k := r.CarrierCode + "|" + r.TrackNumber
+			if i, ok := seen[k]; ok {
+				lines[i]++
+				continue
+			}
+			seen[k] = len(items)
+			items = append(items, models.TrackingCreateInput{
+				CarrierCode: r.CarrierCode,
+				TrackNumber: r.TrackNumber,
+				Metadata:    r.Metadata,
+				Tags:        r.Tags,
+				ExternalID:  r.ExternalID,
+			})
+			lines = append(lines, 1)
+		}
+		if len(items) > 0 {
+			// Повтор пачки после падения безопасен: создание треков идемпотентно.
+			rejected, err := s.create(ctx, items)
+			if err != nil {
+				return s.release(ctx, job.ID, err)
+			}
+			for _, i := range rejected {
+				valid -= lines[i]
+			}
+		}
+
+		cursor = rows[len(rows)-1].Line
+		if err := s.repo.AdvanceImportJob(ctx, job.ID, cursor, int64(len(rows)), valid, s.now().Add(s.cfg.Lease)); err != nil {
+			return s.release(ctx, job.ID, err)
+		}
+	}
+
+	if err := s.repo.CompleteImportJob(ctx, job.ID); err != nil {
+		return s.release(ctx, job.ID, err)
+	}
+	slog.Info("import job done", "job_id", job.ID)
+	return nil
+}
+
+// create создаёт треки пачки и возвращает индексы элементов, которые Creator отклонил: строки проверялись
+// при загрузке, но справочник перевозчиков с тех пор мог измениться.
+func (s *Service) create(ctx context.Context, items []models.TrackingCreateInput) ([]int, error) {
+	if s.creator == nil {
+		_, err := s.repo.CreateOrGetTrackings(ctx, items)
+		return nil, err
+	}
+	res, err := s.creator.CreateTrackingsBulk(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	var rejected []int
+	for i, r := range res {
+		if r.Status == trackings.ItemInvalid {
+			slog.Warn("import row rejected", "carrier", items[i].CarrierCode, "track_number", items[i].TrackNumber, "reason", r.Reason)
+			rejected = append(rejected, i)
+		}
+	}
+	return rejected, nil
+}
+
+// release возвращает задачу в очередь после временной ошибки; следующий захват продолжит с курсора.
+func (s *Service) release(ctx context.Context, jobID uint64, cause error) error {
+	relCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := s.repo.ReleaseImportJob(relCtx, jobID, cause.Error()); err != nil {
+		slog.Error("release import job", "job_id", jobID, "error", err.Error())
+	}
+	return errors.Wrapf(cause, "import job %d", jobID)
+}
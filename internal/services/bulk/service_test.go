@@ -0,0 +1,520 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/services/trackings"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo — in-memory реализация Repository с семантикой pgtracking (аренда, курсор, очистка строк).
+type fakeRepo struct {
+	mu        sync.Mutex
+	trackings []*models.Tracking
+	events    map[uint64][]*models.TrackingEvent
+	jobs      map[uint64]*models.ImportJob
+	rows      map[uint64][]models.ImportRow
+	lease     map[uint64]time.Time
+	nextJobID uint64
+
+	createCalls int
+	failCreate  int // столько следующих CreateOrGetTrackings вернут ошибку
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		events: map[uint64][]*models.TrackingEvent{},
+		jobs:   map[uint64]*models.ImportJob{},
+		rows:   map[uint64][]models.ImportRow{},
+		lease:  map[uint64]time.Time{},
+	}
+}
+
+func (r *fakeRepo) CreateOrGetTrackings(_ context.Context, items []models.TrackingCreateInput) ([]*models.Tracking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.createCalls++
+	if r.failCreate > 0 {
+		r.failCreate--
+		return nil, errors.New("db is down")
+	}
+	var out []*models.Tracking
+	for _, it := range items {
+		var found *models.Tracking
+		for _, t := range r.trackings {
+			if t.CarrierCode == it.CarrierCode && t.TrackNumber == it.TrackNumber {
+				found = t
+			}
+		}
+		if found == nil {
+			found = &models.Tracking{ID: uint64(len(r.trackings) + 1), CarrierCode: it.CarrierCode, TrackNumber: it.TrackNumber, Status: models.TrackingStatusUnknown}
+			r.trackings = append(r.trackings, found)
+		}
+		out = append(out, found)
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) ScanTrackings(_ context.Context, f pgtracking.TrackingFilter, afterID uint64, limit int) ([]*models.Tracking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*models.Tracking
+	for _, t := range r.trackings {
+		if t.ID <= afterID || (f.CarrierCode != "" && t.CarrierCode != f.CarrierCode) || (f.Status != "" && t.Status != f.Status) {
+			continue
+		}
+		out = append(out, t)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) ListEventsForTrackings(_ context.Context, ids []uint64) (map[uint64][]*models.TrackingEvent, error) {
+	out := map[uint64][]*models.TrackingEvent{}
+	for _, id := range ids {
+		if evs, ok := r.events[id]; ok {
+			out[id] = evs
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) CreateImportJob(_ context.Context, format, source string) (*models.ImportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextJobID++
+	j := &models.ImportJob{ID: r.nextJobID, Status: models.ImportJobUploading, Format: format, Source: source}
+	r.jobs[j.ID] = j
+	cp := *j
+	return &cp, nil
+}
+
+func (r *fakeRepo) AddImportRows(_ context.Context, jobID uint64, rows []models.ImportRow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rows[jobID] = append(r.rows[jobID], rows...)
+	return nil
+}
+
+func (r *fakeRepo) FinishImportUpload(_ context.Context, jobID uint64, total, invalid int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j := r.jobs[jobID]
+	j.Status, j.TotalRows, j.InvalidRows = models.ImportJobQueued, total, invalid
+	return nil
+}
+
+func (r *fakeRepo) FailImportJob(_ context.Context, jobID uint64, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j := r.jobs[jobID]
+	j.Status, j.LastError = models.ImportJobFailed, &reason
+	return nil
+}
+
+func (r *fakeRepo) ClaimImportJob(_ context.Context, now time.Time, lease time.Duration) (*models.ImportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]uint64, 0, len(r.jobs))
+	for id := range r.jobs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, k int) bool { return ids[i] < ids[k] })
+	for _, id := range ids {
+		j := r.jobs[id]
+		if j.Status == models.ImportJobQueued || (j.Status == models.ImportJobRunning && r.lease[id].Before(now)) {
+			j.Status = models.ImportJobRunning
+			j.Attempts++
+			r.lease[id] = now.Add(lease)
+			cp := *j
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRepo) NextImportRows(_ context.Context, jobID uint64, afterLine int64, limit int) ([]models.ImportRow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.ImportRow
+	for _, row := range r.rows[jobID] {
+		if row.Line > afterLine && len(out) < limit {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) AdvanceImportJob(_ context.Context, jobID uint64, cursor, processed, imported int64, leaseUntil time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j := r.jobs[jobID]
+	j.Cursor = cursor
+	j.ProcessedRows += processed
+	j.ImportedRows += imported
+	j.Attempts = 0
+	j.LastError = nil
+	r.lease[jobID] = leaseUntil
+	return nil
+}
+
+func (r *fakeRepo) ReleaseImportJob(_ context.Context, jobID uint64, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j := r.jobs[jobID]
+	j.Status, j.LastError = models.ImportJobQueued, &reason
+	return nil
+}
+
+func (r *fakeRepo) CompleteImportJob(_ context.Context, jobID uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var keep []models.ImportRow
+	for _, row := range r.rows[jobID] {
+		if row.Error != nil {
+			keep = append(keep, row)
+		}
+	}
+	r.rows[jobID] = keep
+	r.jobs[jobID].Status = models.ImportJobDone
+	return nil
+}
+
+func (r *fakeRepo) RetryImportJob(_ context.Context, jobID uint64) (*models.ImportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j, ok := r.jobs[jobID]
+	if !ok || j.Status != models.ImportJobFailed {
+		return nil, pgtracking.ErrImportJobNotFound
+	}
+	j.Status, j.Attempts, j.LastError = models.ImportJobQueued, 0, nil
+	cp := *j
+	return &cp, nil
+}
+
+func (r *fakeRepo) GetImportJob(_ context.Context, jobID uint64) (*models.ImportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	j, ok := r.jobs[jobID]
+	if !ok {
+		return nil, pgtracking.ErrImportJobNotFound
+	}
+	cp := *j
+	return &cp, nil
+}
+
+func (r *fakeRepo) ListImportErrors(_ context.Context, jobID uint64, limit, offset int) ([]models.ImportRow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.ImportRow
+	for _, row := range r.rows[jobID] {
+		if row.Error != nil {
+			out = append(out, row)
+		}
+	}
+	if offset >= len(out) {
+		return nil, nil
+	}
+	out = out[offset:]
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func TestImport_EndToEnd(t *testing.T) {
+	repo := newFakeRepo()
+	svc := New(repo, Config{UploadChunk: 2, BatchSize: 2})
+	ctx := context.Background()
+
+	in := "carrier_code,track_number,metadata\n" +
+		"CDEK,1000000001,\n" +
+		"POST_RU,RA123456785RU,\"{\"\"shop\"\":\"\"x\"\"}\"\n" +
+		",C3,\n" +
+		"cdek,1000000001,\n" +
+		"CDEK,1000000004,[1]\n" +
+		",10100100000010,\n"
+	job, err := svc.StartImport(ctx, strings.NewReader(in), FormatCSV, "test.csv")
+	require.NoError(t, err)
+	require.Equal(t, models.ImportJobQueued, job.Status)
+	require.EqualValues(t, 6, job.TotalRows)
+	require.EqualValues(t, 2, job.InvalidRows)
+
+	ok, err := svc.ProcessNext(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	job, err = svc.GetImportJob(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.ImportJobDone, job.Status)
+	require.EqualValues(t, 6, job.ProcessedRows)
+	require.EqualValues(t, 4, job.ImportedRows)
+	require.EqualValues(t, 7, job.Cursor)
+	require.Len(t, repo.trackings, 3)
+
+	errs, err := svc.ListImportErrors(ctx, job.ID, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, errs, 2)
+	require.Equal(t, "line 4: carrier cannot be detected from track number", RowError(errs[0]))
+	require.Equal(t, "line 6: metadata must be a JSON object", RowError(errs[1]))
+
+	ok, err = svc.ProcessNext(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+// fakeCreator создаёт треки в repo, как trackings.Service, и отклоняет номера из reject.
+type fakeCreator struct {
+	repo   *fakeRepo
+	reject map[string]bool
+	calls  [][]models.TrackingCreateInput
+}
+
+func (c *fakeCreator) CreateTrackingsBulk(ctx context.Context, items []models.TrackingCreateInput) ([]trackings.ItemResult, error) {
+	c.calls = append(c.calls, items)
+	out := make([]trackings.ItemResult, len(items))
+	for i, it := range items {
+		if c.reject[it.TrackNumber] {
+			out[i] = trackings.ItemResult{Status: trackings.ItemInvalid, Reason: "carrier is disabled"}
+			continue
+		}
+		ts, err := c.repo.CreateOrGetTrackings(ctx, []models.TrackingCreateInput{it})
+		if err != nil {
+			return nil, err
+		}
+		out[i] = trackings.ItemResult{Status: trackings.ItemCreated, Tracking: ts[0]}
+	}
+	return out, nil
+}
+
+func TestImport_CreatesThroughTrackingsService(t *testing.T) {
+	repo := newFakeRepo()
+	cr := &fakeCreator{repo: repo, reject: map[string]bool{"1000000002": true}}
+	svc := New(repo, Config{BatchSize: 10}).WithTrackings(cr)
+	ctx := context.Background()
+
+	in := "carrier_code,track_number\nCDEK,1000000001\nCDEK,1000000002\nCDEK,1000000002\nCDEK,1000000003\n"
+	job, err := svc.StartImport(ctx, strings.NewReader(in), FormatCSV, "")
+	require.NoError(t, err)
+	job, err = svc.ProcessJob(ctx, job.ID)
+	require.NoError(t, err)
+
+	require.Equal(t, models.ImportJobDone, job.Status)
+	require.Len(t, cr.calls, 1)
+	require.Len(t, cr.calls[0], 3)
+	// обе строки отклонённого номера не считаются импортированными
+	require.EqualValues(t, 4, job.ProcessedRows)
+	require.EqualValues(t, 2, job.ImportedRows)
+	require.Len(t, repo.trackings, 2)
+}
+
+func TestImport_ResumesFromCursorAfterFailure(t *testing.T) {
+	repo := newFakeRepo()
+	svc := New(repo, Config{BatchSize: 2, MaxAttempts: 2})
+	ctx := context.Background()
+
+	var in bytes.Buffer
+	for _, n := range []string{"1000000001", "1000000002", "1000000003", "1000000004", "1000000005"} {
+		_ = json.NewEncoder(&in).Encode(map[string]string{"carrierCode": "CDEK", "trackNumber": n})
+	}
+	job, err := svc.StartImport(ctx, &in, FormatJSONL, "")
+	require.NoError(t, err)
+
+	// Первую пачку обрабатывает «упавший» обработчик, затем ломаем БД:
+	// задача возвращается в очередь, курсор остаётся после строки 2.
+	origCreate := repo.createCalls
+	claimed, err := repo.ClaimImportJob(ctx, time.Now(), time.Minute)
+	require.NoError(t, err)
+	rows, _ := repo.NextImportRows(ctx, claimed.ID, 0, 2)
+	_, _ = repo.CreateOrGetTrackings(ctx, []models.TrackingCreateInput{{CarrierCode: "CDEK", TrackNumber: rows[0].TrackNumber}, {CarrierCode: "CDEK", TrackNumber: rows[1].TrackNumber}})
+	require.NoError(t, repo.AdvanceImportJob(ctx, claimed.ID, rows[1].Line, 2, 2, time.Now().Add(time.Minute)))
+	require.NoError(t, repo.ReleaseImportJob(ctx, claimed.ID, "worker crashed"))
+
+	repo.failCreate = 1
+	_, err = svc.ProcessNext(ctx)
+	require.Error(t, err)
+	job, _ = svc.GetImportJob(ctx, job.ID)
+	require.Equal(t, models.ImportJobQueued, job.Status)
+	require.EqualValues(t, 2, job.Cursor)
+	require.Contains(t, *job.LastError, "db is down")
+
+	_, err = svc.ProcessNext(ctx)
+	require.NoError(t, err)
+	job, _ = svc.GetImportJob(ctx, job.ID)
+	require.Equal(t, models.ImportJobDone, job.Status)
+	require.EqualValues(t, 5, job.ImportedRows)
+	require.Len(t, repo.trackings, 5)
+	// 1 ручной + 1 упавший + 2 пачки (C,D) и (E) после возобновления
+	require.Equal(t, origCreate+4, repo.createCalls)
+}
+
+func TestImport_TooManyAttemptsFailsAndResume(t *testing.T) {
+	repo := newFakeRepo()
+	svc := New(repo, Config{MaxAttempts: 2})
+	ctx := context.Background()
+
+	job, err := svc.StartImport(ctx, strings.NewReader("CDEK,1000000001\n"), FormatCSV, "")
+	require.NoError(t, err)
+
+	repo.failCreate = 10
+	for i := 0; i < 3; i++ {
+		_, _ = svc.ProcessNext(ctx)
+	}
+	job, _ = svc.GetImportJob(ctx, job.ID)
+	require.Equal(t, models.ImportJobFailed, job.Status)
+	require.Contains(t, *job.LastError, "too many attempts")
+
+	repo.failCreate = 0
+	_, err = svc.ResumeImport(ctx, job.ID)
+	require.NoError(t, err)
+	job, err = svc.ProcessJob(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.ImportJobDone, job.Status)
+
+	_, err = svc.ResumeImport(ctx, job.ID)
+	require.ErrorIs(t, err, ErrJobNotFound)
+}
+
+type failingReader struct{ data io.Reader }
+
+func (r failingReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func TestStartImport_InterruptedUploadFailsJob(t *testing.T) {
+	repo := newFakeRepo()
+	svc := New(repo, Config{})
+
+	_, err := svc.StartImport(context.Background(), failingReader{strings.NewReader(`{"carrier_code":"CDEK","track_number":"A"}` + "\n")}, FormatJSONL, "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "connection reset")
+	require.Equal(t, models.ImportJobFailed, repo.jobs[1].Status)
+
+	_, err = svc.StartImport(context.Background(), strings.NewReader("\n\n"), FormatCSV, "")
+	require.Error(t, err)
+	require.Equal(t, models.ImportJobFailed, repo.jobs[2].Status)
+}
+
+func TestRowReader(t *testing.T) {
+	rr, err := NewRowReader(strings.NewReader("track_number,carrier_code\n1000 000 001,cdek\n\"bad,CDEK\n"), FormatCSV)
+	require.NoError(t, err)
+	row, err := rr.Next()
+	require.NoError(t, err)
+	require.Equal(t, models.ImportRow{Line: 2, CarrierCode: "CDEK", TrackNumber: "1000000001"}, row)
+	row, err = rr.Next()
+	require.NoError(t, err)
+	require.NotNil(t, row.Error)
+	require.EqualValues(t, 3, row.Line)
+	_, err = rr.Next()
+	require.Equal(t, io.EOF, err)
+
+	total, bad, err := ValidateImport(strings.NewReader("{\"carrier_code\":\"CDEK\",\"track_number\":\"1000000001\",\"metadata\":{\"k\":1}}\nnot json\n"), FormatJSONL)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+	require.Len(t, bad, 1)
+	require.EqualValues(t, 2, bad[0].Line)
+	require.Contains(t, *bad[0].Error, "invalid json")
+}
+
+func TestRowReader_Attributes(t *testing.T) {
+	rr, err := NewRowReader(strings.NewReader("carrier_code,track_number,tags,external_id\n"+
+		"CDEK,1000000001, vip; b2b ;vip,ORD-1\n"+
+		"CDEK,1000000002,;,\n"), FormatCSV)
+	require.NoError(t, err)
+	row, err := rr.Next()
+	require.NoError(t, err)
+	require.Nil(t, row.Error)
+	require.Equal(t, []string{"vip", "b2b"}, row.Tags)
+	require.Equal(t, "ORD-1", *row.ExternalID)
+	row, err = rr.Next()
+	require.NoError(t, err)
+	require.Equal(t, "tag must not be empty", *row.Error)
+
+	rr, err = NewRowReader(strings.NewReader(`{"carrierCode":"CDEK","trackNumber":"1000000001","tags":["a"],"externalId":" X ","metadata":{ "k" : 1 }}`+"\n"), FormatJSONL)
+	require.NoError(t, err)
+	row, err = rr.Next()
+	require.NoError(t, err)
+	require.Nil(t, row.Error)
+	require.Equal(t, []string{"a"}, row.Tags)
+	require.Equal(t, "X", *row.ExternalID)
+	require.Equal(t, `{"k":1}`, *row.Metadata)
+}
+
+func TestDetectFormat(t *testing.T) {
+	f, err := DetectFormat("", "in.NDJSON", FormatCSV)
+	require.NoError(t, err)
+	require.Equal(t, FormatJSONL, f)
+
+	f, err = DetectFormat("CSV", "in.jsonl", FormatJSONL)
+	require.NoError(t, err)
+	require.Equal(t, FormatCSV, f)
+
+	_, err = DetectFormat("xml", "", FormatCSV)
+	require.Error(t, err)
+}
+
+func TestExport(t *testing.T) {
+	repo := newFakeRepo()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	loc := "Moscow"
+	for i := 1; i <= exportPageSize+1; i++ {
+		repo.trackings = append(repo.trackings, &models.Tracking{ID: uint64(i), CarrierCode: "CDEK", TrackNumber: "T", Status: models.TrackingStatusInTransit, NextCheckAt: now, CreatedAt: now, UpdatedAt: now})
+	}
+	repo.trackings[0].Status = models.TrackingStatusDelivered
+	ext, meta := "ORD-1", `{"shop":"x"}`
+	repo.trackings[0].ExternalID, repo.trackings[0].Tags, repo.trackings[0].Metadata = &ext, []string{"vip", "b2b"}, &meta
+	repo.events[1] = []*models.TrackingEvent{
+		{TrackingID: 1, Status: models.TrackingStatusInTransit, StatusRaw: "ACCEPTED", EventTime: now, Location: &loc,
+			Place: &models.Location{Country: "RU", Region: "Москва", City: "Москва", PostalCode: "101000"}},
+		{TrackingID: 1, Status: models.TrackingStatusDelivered, StatusRaw: "DELIVERED", EventTime: now.Add(time.Hour)},
+	}
+	svc := New(repo, Config{})
+
+	var buf bytes.Buffer
+	n, err := svc.Export(context.Background(), &buf, ExportOptions{Events: true})
+	require.NoError(t, err)
+	require.Equal(t, exportPageSize+1, n)
+	first := strings.SplitN(buf.String(), "\n", 2)[0]
+	var rec exportRecord
+	require.NoError(t, json.Unmarshal([]byte(first), &rec))
+	require.Len(t, rec.Events, 2)
+	require.Equal(t, "Moscow", *rec.Events[0].Location)
+	require.Equal(t, "101000", rec.Events[0].Place.PostalCode)
+	require.Nil(t, rec.Events[1].Place)
+	require.Equal(t, "ORD-1", *rec.ExternalID)
+	require.Equal(t, []string{"vip", "b2b"}, rec.Tags)
+	require.JSONEq(t, meta, string(rec.Metadata))
+
+	buf.Reset()
+	n, err = svc.Export(context.Background(), &buf, ExportOptions{Format: FormatCSV, Events: true, Filter: pgtracking.TrackingFilter{Status: models.TrackingStatusDelivered}})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3) // заголовок + строка на каждое событие
+	require.True(t, strings.HasSuffix(lines[0], "event_location,event_message,event_country,event_region,event_city,event_postal_code"))
+	require.Contains(t, lines[1], "ACCEPTED,2026-01-02T03:04:05Z,Moscow,,RU,Москва,Москва,101000")
+	require.True(t, strings.HasSuffix(lines[2], "DELIVERED,2026-01-02T04:04:05Z,,,,,,"))
+
+	buf.Reset()
+	_, err = svc.Export(context.Background(), &buf, ExportOptions{Format: FormatCSV, Filter: pgtracking.TrackingFilter{Status: models.TrackingStatusDelivered}})
+	require.NoError(t, err)
+	require.Equal(t, "id,carrier_code,track_number,status,status_raw,status_at,last_checked_at,next_check_at,check_fail_count,last_error,created_at,updated_at,external_id,tags,metadata\n"+
+		"1,CDEK,T,DELIVERED,,,,2026-01-02T03:04:05Z,0,,2026-01-02T03:04:05Z,2026-01-02T03:04:05Z,ORD-1,vip;b2b,\"{\"\"shop\"\":\"\"x\"\"}\"\n", buf.String())
+}
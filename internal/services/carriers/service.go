@@ -0,0 +1,220 @@
+// Package carriers — справочник перевозчиков в БД (таблица carriers): админские операции
+// и актуальный tracknumber.Registry, который track-api и track-worker перечитывают на лету.
+package carriers
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"github.com/BearBump/TrackBox/internal/tracknumber"
+	"github.com/pkg/errors"
+)
+
+type Repository interface {
+	ListCarriers(ctx context.Context) ([]*models.Carrier, error)
+	GetCarrier(ctx context.Context, code string) (*models.Carrier, error)
+	CreateCarrier(ctx context.Context, c models.Carrier) (*models.Carrier, error)
+	UpdateCarrier(ctx context.Context, c models.Carrier, fields []string) (*models.Carrier, error)
+	SetCarrierEnabled(ctx context.Context, code string, enabled bool) (*models.Carrier, error)
+}
+
+var (
+	// ErrInvalid — запись перевозчика не прошла проверку (текст ошибки — что именно не так).
+	ErrInvalid  = errors.New("invalid carrier")
+	ErrNotFound = pgtracking.ErrCarrierNotFound
+	ErrExists   = pgtracking.ErrCarrierExists
+)
+
+var codePattern = regexp.MustCompile(`^[A-Z0-9_]{2,32}$`)
+
+var updatableFields = map[string]bool{
+	models.CarrierFieldDisplayName:    true,
+	models.CarrierFieldBackend:        true,
+	models.CarrierFieldCredentialsRef: true,
+	models.CarrierFieldRateLimit:      true,
+	models.CarrierFieldScheduling:     true,
+	models.CarrierFieldEnabled:        true,
+}
+
+var knownBackends = map[string]bool{
+	models.CarrierBackendDefault:    true,
+	models.CarrierBackendEmulatorV1: true,
+	models.CarrierBackendTrack24:    true,
+	models.CarrierBackendFake:       true,
+}
+
+type Service struct {
+	repo     Repository
+	registry atomic.Pointer[tracknumber.Registry]
+}
+
+func New(repo Repository) *Service {
+	s := &Service{repo: repo}
+	s.registry.Store(tracknumber.Default())
+	return s
+}
+
+// Registry — справочник для проверки номеров по последнему успешно загруженному состоянию таблицы.
+func (s *Service) Registry() *tracknumber.Registry {
+	return s.registry.Load()
+}
+
+func (s *Service) List(ctx context.Context) ([]*models.Carrier, error) {
+	return s.repo.ListCarriers(ctx)
+}
+
+func (s *Service) Create(ctx context.Context, c models.Carrier) (*models.Carrier, error) {
+	c.Code = tracknumber.CanonicalCarrier(c.Code)
+	if err := s.validate(c, nil); err != nil {
+		return nil, err
+	}
+	if known, ok := s.Registry().Lookup(c.Code); ok && known.Code != c.Code {
+		return nil, errors.Wrapf(ErrInvalid, "code %s is an alias of %s", c.Code, known.Code)
+	}
+	out, err := s.repo.CreateCarrier(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	s.refreshAfterWrite(ctx)
+	return out, nil
+}
+
+// Update меняет только поля fields (models.CarrierField*) — остальные, в т.ч. enabled, остаются как были.
+func (s *Service) Update(ctx context.Context, c models.Carrier, fields []string) (*models.Carrier, error) {
+	c.Code = tracknumber.CanonicalCarrier(c.Code)
+	if len(fields) == 0 {
+		return nil, errors.Wrap(ErrInvalid, "no fields to update")
+	}
+	set := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if !updatableFields[f] {
+			return nil, errors.Wrapf(ErrInvalid, "field %q cannot be updated", f)
+		}
+		set[f] = true
+	}
+	if err := s.validate(c, set); err != nil {
+		return nil, err
+	}
+	out, err := s.repo.UpdateCarrier(ctx, c, fields)
+	if err != nil {
+		return nil, err
+	}
+	s.refreshAfterWrite(ctx)
+	return out, nil
+}
+
+// Disable отключает перевозчика: новые треки не принимаются, worker перестаёт проверять существующие.
+func (s *Service) Disable(ctx context.Context, code string) (*models.Carrier, error) {
+	out, err := s.repo.SetCarrierEnabled(ctx, tracknumber.CanonicalCarrier(code), false)
+	if err != nil {
+		return nil, err
+	}
+	s.refreshAfterWrite(ctx)
+	return out, nil
+}
+
+// validate проверяет код и поля из fields (nil — все поля).
+func (s *Service) validate(c models.Carrier, fields map[string]bool) error {
+	has := func(f string) bool { return fields == nil || fields[f] }
+	switch {
+	case !codePattern.MatchString(c.Code):
+		return errors.Wrapf(ErrInvalid, "code %q: want 2-32 characters A-Z, 0-9, _", c.Code)
+	case has(models.CarrierFieldDisplayName) && strings.TrimSpace(c.DisplayName) == "":
+		return errors.Wrap(ErrInvalid, "display_name is required")
+	case has(models.CarrierFieldBackend) && !knownBackends[c.Backend]:
+		return errors.Wrapf(ErrInvalid, "backend %q: want emulator_v1|track24|fake or empty", c.Backend)
+	case has(models.CarrierFieldRateLimit) && c.RateLimitPerMinute < 0:
+		return errors.Wrap(ErrInvalid, "rate_limit_per_minute must be >= 0")
+	}
+	if has(models.CarrierFieldCredentialsRef) && c.CredentialsRef != "" {
+		if err := config.ValidateSecretRef(c.CredentialsRef); err != nil {
+			return errors.Wrapf(ErrInvalid, "credentials_ref: %v", err)
+		}
+	}
+	if has(models.CarrierFieldScheduling) && c.SchedulingJSON != nil {
+		if _, err := config.ParseSchedulePolicy([]byte(*c.SchedulingJSON), "scheduling"); err != nil {
+			return errors.Wrap(ErrInvalid, err.Error())
+		}
+	}
+	return nil
+}
+
+// refreshAfterWrite сразу применяет изменение в этом процессе; остальные увидят его через RunRefresh.
+func (s *Service) refreshAfterWrite(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil {
+		slog.Warn("carriers refresh failed", "error", err.Error())
+	}
+}
+
+// Refresh перечитывает таблицу carriers и заменяет Registry.
+func (s *Service) Refresh(ctx context.Context) error {
+	list, err := s.repo.ListCarriers(ctx)
+	if err != nil {
+		return err
+	}
+	r, err := BuildRegistry(list)
+	if err != nil {
+		return err
+	}
+	s.registry.Store(r)
+	return nil
+}
+
+// RunRefresh обновляет справочник каждые interval до отмены ctx.
+func (s *Service) RunRefresh(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+			slog.Warn("carriers refresh failed", "error", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// BuildRegistry строит справочник из записей таблицы. Встроенные перевозчики сохраняют свои
+// алиасы и проверку формата номера; новые проверяются только по коду (формат номера любой).
+// Пустая таблица — tracknumber.Default().
+func BuildRegistry(list []*models.Carrier) (*tracknumber.Registry, error) {
+	if len(list) == 0 {
+		return tracknumber.Default(), nil
+	}
+	builtin := make(map[string]tracknumber.Carrier)
+	for _, c := range tracknumber.Builtin() {
+		builtin[c.Code] = c
+	}
+
+	out := make([]tracknumber.Carrier, 0, len(list)+len(builtin))
+	for _, c := range list {
+		tc, ok := builtin[c.Code]
+		if !ok {
+			tc = tracknumber.Carrier{Code: c.Code}
+		}
+		delete(builtin, c.Code)
+		tc.Name = c.DisplayName
+		tc.Disabled = !c.Enabled
+		out = append(out, tc)
+	}
+	// Встроенных перевозчиков, которых нет в таблице, оставляем как есть.
+	for _, c := range tracknumber.Builtin() {
+		if _, ok := builtin[c.Code]; ok {
+			out = append(out, c)
+		}
+	}
+	r, err := tracknumber.NewRegistry(out...)
+	return r, errors.Wrap(err, "build carriers registry")
+}
@@ -0,0 +1,158 @@
+package carriers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/tracknumber"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	byCode map[string]*models.Carrier
+	order  []string
+}
+
+func newFakeRepo(cs ...models.Carrier) *fakeRepo {
+	r := &fakeRepo{byCode: map[string]*models.Carrier{}}
+	for _, c := range cs {
+		c := c
+		r.byCode[c.Code] = &c
+		r.order = append(r.order, c.Code)
+	}
+	return r
+}
+
+func (r *fakeRepo) ListCarriers(context.Context) ([]*models.Carrier, error) {
+	out := make([]*models.Carrier, 0, len(r.order))
+	for _, code := range r.order {
+		out = append(out, r.byCode[code])
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) GetCarrier(_ context.Context, code string) (*models.Carrier, error) {
+	c, ok := r.byCode[code]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return c, nil
+}
+
+func (r *fakeRepo) CreateCarrier(_ context.Context, c models.Carrier) (*models.Carrier, error) {
+	if _, ok := r.byCode[c.Code]; ok {
+		return nil, ErrExists
+	}
+	r.byCode[c.Code] = &c
+	r.order = append(r.order, c.Code)
+	return &c, nil
+}
+
+func (r *fakeRepo) UpdateCarrier(_ context.Context, c models.Carrier, fields []string) (*models.Carrier, error) {
+	cur, ok := r.byCode[c.Code]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	for _, f := range fields {
+		switch f {
+		case models.CarrierFieldDisplayName:
+			cur.DisplayName = c.DisplayName
+		case models.CarrierFieldBackend:
+			cur.Backend = c.Backend
+		case models.CarrierFieldCredentialsRef:
+			cur.CredentialsRef = c.CredentialsRef
+		case models.CarrierFieldRateLimit:
+			cur.RateLimitPerMinute = c.RateLimitPerMinute
+		case models.CarrierFieldScheduling:
+			cur.SchedulingJSON = c.SchedulingJSON
+		case models.CarrierFieldEnabled:
+			cur.Enabled = c.Enabled
+		}
+	}
+	return cur, nil
+}
+
+func (r *fakeRepo) SetCarrierEnabled(_ context.Context, code string, enabled bool) (*models.Carrier, error) {
+	c, ok := r.byCode[code]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	c.Enabled = enabled
+	return c, nil
+}
+
+func seeded() *fakeRepo {
+	return newFakeRepo(
+		models.Carrier{Code: "CDEK", DisplayName: "СДЭК", Enabled: true},
+		models.Carrier{Code: "POST_RU", DisplayName: "Почта России", Enabled: true},
+	)
+}
+
+func TestService_CreateValidates(t *testing.T) {
+	s := New(seeded())
+	ctx := context.Background()
+	bad := "{\"statuses\":{\"LOST\":{\"min_seconds\":1}}}"
+
+	tests := []struct {
+		name string
+		c    models.Carrier
+		err  string
+	}{
+		{name: "bad code", c: models.Carrier{Code: "D", DisplayName: "x"}, err: "want 2-32 characters"},
+		{name: "no name", c: models.Carrier{Code: "DHL"}, err: "display_name is required"},
+		{name: "backend", c: models.Carrier{Code: "DHL", DisplayName: "DHL", Backend: "soap"}, err: `backend "soap"`},
+		{name: "rate", c: models.Carrier{Code: "DHL", DisplayName: "DHL", RateLimitPerMinute: -1}, err: "rate_limit_per_minute"},
+		{name: "credentials", c: models.Carrier{Code: "DHL", DisplayName: "DHL", CredentialsRef: "plain-key"}, err: "credentials_ref"},
+		{name: "scheduling", c: models.Carrier{Code: "DHL", DisplayName: "DHL", SchedulingJSON: &bad}, err: "scheduling.statuses.LOST"},
+		{name: "alias", c: models.Carrier{Code: "sdek", DisplayName: "x"}, err: "SDEK is an alias of CDEK"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Create(ctx, tt.c)
+			require.ErrorIs(t, err, ErrInvalid)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	_, err := s.Create(ctx, models.Carrier{Code: "cdek", DisplayName: "x"})
+	require.ErrorIs(t, err, ErrExists)
+}
+
+func TestService_RegistryFollowsTable(t *testing.T) {
+	s := New(seeded())
+	ctx := context.Background()
+
+	_, _, err := s.Registry().Normalize("DHL", "JD0001")
+	require.ErrorIs(t, err, tracknumber.ErrUnknownCarrier)
+
+	c, err := s.Create(ctx, models.Carrier{Code: " dhl ", DisplayName: "DHL Express", Backend: models.CarrierBackendFake, Enabled: true})
+	require.NoError(t, err)
+	require.Equal(t, "DHL", c.Code)
+
+	code, number, err := s.Registry().Normalize("dhl", "jd 0001")
+	require.NoError(t, err)
+	require.Equal(t, "DHL", code)
+	require.Equal(t, "JD0001", number)
+
+	// встроенные перевозчики сохраняют алиасы и проверку номера
+	code, _, err = s.Registry().Normalize("сдэк", "1234567890")
+	require.NoError(t, err)
+	require.Equal(t, "CDEK", code)
+	_, _, err = s.Registry().Normalize("CDEK", "12")
+	require.ErrorContains(t, err, "invalid CDEK track number")
+
+	_, err = s.Disable(ctx, "cdek")
+	require.NoError(t, err)
+	_, _, err = s.Registry().Normalize("CDEK", "1234567890")
+	require.ErrorIs(t, err, tracknumber.ErrDisabled)
+
+	_, err = s.Disable(ctx, "UPS")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestBuildRegistry_Empty(t *testing.T) {
+	r, err := BuildRegistry(nil)
+	require.NoError(t, err)
+	require.Same(t, tracknumber.Default(), r)
+}
@@ -0,0 +1,84 @@
+package poller
+
+import (
+	"sort"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+)
+
+// maxBacktestChecks защищает прогон от стратегий, возвращающих крошечные задержки.
+const maxBacktestChecks = 10000
+
+// BacktestReport — как стратегия повела бы себя на сохранённых историях.
+type BacktestReport struct {
+	Trackings int
+	Checks    int
+	Events    int
+
+	// Задержка обнаружения — время от события до первой проверки, которая его увидела.
+	MeanDetectionDelay time.Duration
+	P90DetectionDelay  time.Duration
+	MaxDetectionDelay  time.Duration
+
+	// ChecksPerEvent — главная метрика стоимости: меньше — дешевле.
+	ChecksPerEvent float64
+}
+
+// Backtest прогоняет стратегию по сохранённым историям событий.
+// Каждый трек начинается с проверки в момент первого события; считается, что на каждой проверке перевозчик
+// отдаёт все события с event_time <= времени проверки. Прогон трека заканчивается, когда увидено последнее событие.
+func Backtest(s Strategy, histories []*models.TrackingHistory) BacktestReport {
+	var rep BacktestReport
+	var delays []time.Duration
+
+	for _, h := range histories {
+		if h == nil || h.Tracking == nil || len(h.Events) == 0 {
+			continue
+		}
+		evs := make([]*models.TrackingEvent, len(h.Events))
+		copy(evs, h.Events)
+		sort.SliceStable(evs, func(i, j int) bool { return evs[i].EventTime.Before(evs[j].EventTime) })
+
+		rep.Trackings++
+		now := evs[0].EventTime
+		seen := 0
+		for checks := 0; seen < len(evs) && checks < maxBacktestChecks; checks++ {
+			rep.Checks++
+			for seen < len(evs) && !evs[seen].EventTime.After(now) {
+				if seen > 0 {
+					delays = append(delays, now.Sub(evs[seen].EventTime))
+				}
+				seen++
+			}
+			if seen == len(evs) {
+				break
+			}
+			delay := s.PlanNextCheck(PlanInput{
+				CarrierCode: h.Tracking.CarrierCode,
+				Status:      evs[seen-1].Status,
+				Now:         now,
+				Events:      evs[:seen],
+			})
+			if delay <= 0 {
+				delay = time.Second
+			}
+			now = now.Add(delay)
+		}
+	}
+
+	rep.Events = len(delays)
+	if len(delays) == 0 {
+		return rep
+	}
+	sort.Slice(delays, func(i, j int) bool { return delays[i] < delays[j] })
+	var sum time.Duration
+	for _, d := range delays {
+		sum += d
+	}
+	rep.MeanDetectionDelay = sum / time.Duration(len(delays))
+	rep.P90DetectionDelay = delays[(len(delays)*9)/10]
+	rep.MaxDetectionDelay = delays[len(delays)-1]
+	rep.ChecksPerEvent = float64(rep.Checks) / float64(rep.Events)
+	return rep
+}
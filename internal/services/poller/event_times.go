@@ -0,0 +1,89 @@
+package poller
+
+import (
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+)
+
+// ZoneRule — в каком часовом поясе перевозчик отдаёт местное время событий (models.TrackingEvent.EventTimeLocal).
+type ZoneRule struct {
+	// Zone — пояс перевозчика; nil — UTC.
+	Zone *time.Location
+	// ByLocation — сначала пояс места события (Geocoder.TimeZone по индексу/городу/региону), если он известен.
+	ByLocation bool
+}
+
+// DefaultZoneRule — для перевозчиков без правила: пояс по месту события, иначе UTC.
+var DefaultZoneRule = ZoneRule{ByLocation: true}
+
+func (p *Poller) zoneRule(carrierCode string) ZoneRule {
+	p.liveMu.RLock()
+	defer p.liveMu.RUnlock()
+	if r, ok := p.zoneRules[carrierCode]; ok {
+		return r
+	}
+	return DefaultZoneRule
+}
+
+func (p *Poller) eventZone(rule ZoneRule, place *models.Location) *time.Location {
+	if rule.ByLocation && p.geocoder != nil {
+		if z := p.geocoder.TimeZone(place); z != nil {
+			return z
+		}
+	}
+	if rule.Zone != nil {
+		return rule.Zone
+	}
+	return time.UTC
+}
+
+// normalizeEventTimes приводит время событий к UTC.
+//
+// Местное время (EventTimeLocal) переводится по правилу перевозчика. На переходах часов берём то,
+// что даёт time.Date: несуществующее время (весной) сдвигается на час вперёд, неоднозначное (осенью)
+// считается по зимнему времени. Главное — результат один и тот же при каждой проверке.
+//
+// Событию без разобранного времени (нулевой EventTime) подставляется время предыдущего события,
+// при его отсутствии — следующего, а если разобранных нет вовсе — fallback (создание трека).
+// Подстановка детерминирована: при повторной проверке событие получит то же время и не задвоится
+// в uq_tracking_events_dedup (в отличие от time.Now()). Такие события помечаются TimeInferred.
+func (p *Poller) normalizeEventTimes(carrierCode string, evs []*models.TrackingEvent, fallback time.Time) {
+	rule := p.zoneRule(carrierCode)
+	for _, e := range evs {
+		if e.EventTime.IsZero() || !e.EventTimeLocal {
+			continue
+		}
+		t := e.EventTime
+		e.EventTime = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
+			p.eventZone(rule, e.Place)).UTC()
+		e.EventTimeLocal = false
+	}
+
+	var prev time.Time
+	for i, e := range evs {
+		if !e.EventTime.IsZero() {
+			prev = e.EventTime
+			continue
+		}
+		t := prev
+		if t.IsZero() {
+			t = nextEventTime(evs[i+1:])
+		}
+		if t.IsZero() {
+			t = fallback
+		}
+		e.EventTime = t.UTC()
+		e.EventTimeLocal = false
+		e.TimeInferred = true
+	}
+}
+
+func nextEventTime(evs []*models.TrackingEvent) time.Time {
+	for _, e := range evs {
+		if !e.EventTime.IsZero() {
+			return e.EventTime
+		}
+	}
+	return time.Time{}
+}
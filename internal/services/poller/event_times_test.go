@@ -0,0 +1,104 @@
+package poller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/geo"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier"
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+// local — местное время перевозчика, как его отдаёт track24http.
+func local(s string) *models.TrackingEvent {
+	t, err := time.ParseInLocation("02.01.2006 15:04", s, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return &models.TrackingEvent{EventTime: t, EventTimeLocal: true, EventTimeRaw: &s}
+}
+
+func TestNormalizeEventTimes_Zones(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	p := New(nil, nil, nil, nil, "t").WithGeocoder(geo.Default())
+	p.Reload(LiveSettings{ZoneRules: map[string]ZoneRule{
+		"DHL":     {Zone: berlin},
+		"POST_RU": {Zone: berlin, ByLocation: true},
+	}})
+
+	tests := []struct {
+		name    string
+		carrier string
+		ev      *models.TrackingEvent
+		place   *models.Location
+		want    time.Time
+	}{
+		{name: "winter", carrier: "DHL", ev: local("15.01.2025 12:00"), want: time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{name: "summer", carrier: "DHL", ev: local("15.07.2025 12:00"), want: time.Date(2025, 7, 15, 10, 0, 0, 0, time.UTC)},
+		{name: "DST gap shifts forward", carrier: "DHL", ev: local("30.03.2025 02:30"), want: time.Date(2025, 3, 30, 1, 30, 0, 0, time.UTC)},
+		{name: "DST overlap uses winter time", carrier: "DHL", ev: local("26.10.2025 02:30"), want: time.Date(2025, 10, 26, 1, 30, 0, 0, time.UTC)},
+		{name: "zone by postal code", carrier: "POST_RU", ev: local("15.07.2025 12:00"), place: &models.Location{PostalCode: "690000"}, want: time.Date(2025, 7, 15, 2, 0, 0, 0, time.UTC)},
+		{name: "unknown place falls back to carrier zone", carrier: "POST_RU", ev: local("15.07.2025 12:00"), place: &models.Location{City: "Berlin"}, want: time.Date(2025, 7, 15, 10, 0, 0, 0, time.UTC)},
+		{name: "default rule by location", carrier: "CDEK", ev: local("15.07.2025 12:00"), place: &models.Location{City: "Екатеринбург"}, want: time.Date(2025, 7, 15, 7, 0, 0, 0, time.UTC)},
+		{name: "default rule UTC", carrier: "CDEK", ev: local("15.07.2025 12:00"), want: time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC)},
+		{name: "time with zone untouched", carrier: "DHL", ev: &models.TrackingEvent{EventTime: time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC)}, want: time.Date(2025, 7, 15, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.ev.Place = tt.place
+			p.normalizeEventTimes(tt.carrier, []*models.TrackingEvent{tt.ev}, time.Time{})
+			require.Equal(t, tt.want, tt.ev.EventTime)
+			require.Equal(t, time.UTC, tt.ev.EventTime.Location())
+			require.False(t, tt.ev.EventTimeLocal)
+			require.False(t, tt.ev.TimeInferred)
+		})
+	}
+}
+
+func TestNormalizeEventTimes_Unparseable(t *testing.T) {
+	p := New(nil, nil, nil, nil, "t")
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	bad := func() *models.TrackingEvent {
+		raw := "вчера вечером"
+		return &models.TrackingEvent{EventTimeRaw: &raw}
+	}
+
+	// между разобранными — время предыдущего, в начале — следующего
+	evs := []*models.TrackingEvent{bad(), local("02.01.2025 10:00"), bad(), local("03.01.2025 10:00")}
+	p.normalizeEventTimes("CDEK", evs, created)
+	require.Equal(t, time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), evs[0].EventTime)
+	require.Equal(t, time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC), evs[2].EventTime)
+	require.True(t, evs[0].TimeInferred)
+	require.True(t, evs[2].TimeInferred)
+	require.False(t, evs[1].TimeInferred)
+	require.Equal(t, "вчера вечером", *evs[2].EventTimeRaw)
+
+	// ничего не разобралось — время создания трека
+	evs = []*models.TrackingEvent{bad(), {}}
+	p.normalizeEventTimes("CDEK", evs, created)
+	require.Equal(t, created, evs[0].EventTime)
+	require.Equal(t, created, evs[1].EventTime)
+	require.True(t, evs[1].TimeInferred)
+}
+
+func TestPoller_check_inferredTimesAreStable(t *testing.T) {
+	raw := "??"
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := New(nil, fakeCarrier{res: carrier.TrackingResult{
+		Status: "IN_TRANSIT",
+		Events: []*models.TrackingEvent{{StatusRaw: "accepted", EventTimeRaw: &raw}},
+	}}, &fakeProducer{}, fakeRL{allowed: true}, "t")
+	tr := &models.Tracking{ID: 1, CarrierCode: "CDEK", CreatedAt: created}
+
+	first, err := p.CheckNow(context.Background(), tr)
+	require.NoError(t, err)
+	second, err := p.CheckNow(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, created, first.Events[0].EventTime)
+	require.Equal(t, first.Events[0].EventTime, second.Events[0].EventTime)
+	require.True(t, first.Events[0].TimeInferred)
+	require.Equal(t, &raw, first.Events[0].EventTimeRaw)
+}
@@ -0,0 +1,64 @@
+package poller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/models"
+)
+
+// fingerprint — отпечаток нормализованного ответа перевозчика: статус, события (после geo и часовых поясов)
+// и сведения об отправлении. Порядок событий не важен. Пользовательские атрибуты трека не входят.
+func fingerprint(msg messages.TrackingUpdated) string {
+	evs := slices.Clone(msg.Events)
+	slices.SortStableFunc(evs, func(a, b messages.TrackingEvent) int {
+		return strings.Compare(eventKey(a), eventKey(b))
+	})
+	b, _ := json.Marshal(struct {
+		Status    string                   `json:"status"`
+		StatusRaw string                   `json:"status_raw"`
+		StatusAt  *time.Time               `json:"status_at"`
+		Events    []messages.TrackingEvent `json:"events"`
+		Shipment  *messages.Shipment       `json:"shipment"`
+	}{msg.Status, msg.StatusRaw, msg.StatusAt, evs, msg.Shipment})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16])
+}
+
+// responseHash — хэш тела ответа перевозчика как есть (журнал проверок); "" — тела нет.
+func responseHash(raw []byte) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:16])
+}
+
+func eventKey(e messages.TrackingEvent) string {
+	return (&models.TrackingEvent{StatusRaw: e.StatusRaw, EventTime: e.EventTime, Location: e.Location, Message: e.Message}).DedupKey()
+}
+
+// classify проставляет msg.Kind и Fingerprint, сравнивая ответ с последним сохранённым у трека.
+// Если ничего не изменилось, events и shipment из сообщения убираются: track-api их уже сохранил.
+func classify(tr *models.Tracking, msg *messages.TrackingUpdated) {
+	if msg.Error != nil {
+		msg.Kind = messages.KindCheckFailed
+		return
+	}
+	msg.Fingerprint = fingerprint(*msg)
+	switch {
+	case tr.Fingerprint != "" && tr.Fingerprint == msg.Fingerprint:
+		msg.Kind = messages.KindCheckedNoChange
+		msg.Events = nil
+		msg.Shipment = nil
+	case msg.Status != tr.Status || msg.StatusRaw != tr.StatusRaw:
+		msg.Kind = messages.KindStatusChanged
+	default:
+		msg.Kind = messages.KindEventsChanged
+	}
+}
@@ -0,0 +1,68 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/integrations/carrier"
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoller_CheckNow_Kinds(t *testing.T) {
+	t0 := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	loc := "Москва"
+	res := carrier.TrackingResult{
+		Status:    models.TrackingStatusInTransit,
+		StatusRaw: "SORTED",
+		Events: []*models.TrackingEvent{
+			{Status: models.TrackingStatusInTransit, StatusRaw: "ACCEPTED", EventTime: t0, Location: &loc},
+			{Status: models.TrackingStatusInTransit, StatusRaw: "SORTED", EventTime: t0.Add(time.Hour), Location: &loc},
+		},
+	}
+	tr := &models.Tracking{ID: 1, CarrierCode: "C", TrackNumber: "N", Status: models.TrackingStatusUnknown, StatusRaw: "UNKNOWN"}
+
+	first, err := New(nil, fakeCarrier{res: res}, &fakeProducer{}, nil, "t").CheckNow(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, messages.KindStatusChanged, first.Kind)
+	require.NotEmpty(t, first.Fingerprint)
+	require.Len(t, first.Events, 2)
+
+	// тот же ответ, события в другом порядке — изменений нет, события не пересылаются
+	tr.Status, tr.StatusRaw, tr.Fingerprint = first.Status, first.StatusRaw, first.Fingerprint
+	res.Events = []*models.TrackingEvent{res.Events[1], res.Events[0]}
+	same, err := New(nil, fakeCarrier{res: res}, &fakeProducer{}, nil, "t").CheckNow(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, messages.KindCheckedNoChange, same.Kind)
+	require.Equal(t, first.Fingerprint, same.Fingerprint)
+	require.Empty(t, same.Events)
+	require.Equal(t, 2, same.Check.EventsCount) // в журнал — сколько событий прислал перевозчик
+	require.Equal(t, models.TrackingStatusInTransit, same.Status)
+
+	// перевозчик исправил место события — статус тот же
+	fixed := "Москва МСЦ"
+	ev := *res.Events[0]
+	ev.Location = &fixed
+	res.Events = []*models.TrackingEvent{&ev, res.Events[1]}
+	edited, err := New(nil, fakeCarrier{res: res}, &fakeProducer{}, nil, "t").CheckNow(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, messages.KindEventsChanged, edited.Kind)
+	require.NotEqual(t, first.Fingerprint, edited.Fingerprint)
+	require.Len(t, edited.Events, 2)
+
+	failed, err := New(nil, fakeCarrier{err: errors.New("boom")}, &fakeProducer{}, nil, "t").CheckNow(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, messages.KindCheckFailed, failed.Kind)
+	require.Empty(t, failed.Fingerprint)
+	require.Equal(t, carrier.ErrorClassOther, failed.Check.ErrorClass)
+
+	res.Raw, res.HTTPStatus = []byte(`{"status":"ok"}`), 200
+	withRaw, err := New(nil, fakeCarrier{res: res}, &fakeProducer{}, nil, "t").WithInstance("worker-1").CheckNow(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, "worker-1", withRaw.Check.Worker)
+	require.Equal(t, 200, withRaw.Check.HTTPStatus)
+	require.Len(t, withRaw.Check.ResponseHash, 32)
+}
@@ -0,0 +1,316 @@
+package poller
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+)
+
+// HistoryRepository — сохранённая история событий, на которой учится HistoryPlanner.
+type HistoryRepository interface {
+	CarrierEventCadence(ctx context.Context, since time.Time) ([]models.CarrierCadence, error)
+	LoadTrackingHistories(ctx context.Context, carrierCode string, since time.Time, limit int) ([]*models.TrackingHistory, error)
+}
+
+// BusinessHours — рабочее окно перевозчика по местному времени: вне его события почти не появляются.
+type BusinessHours struct {
+	Location  *time.Location
+	StartHour int // включительно, 0..23
+	EndHour   int // не включительно, 1..24
+}
+
+type HistoryPlannerConfig struct {
+	MinDelay time.Duration // по умолчанию: 1 минута
+	MaxDelay time.Duration // по умолчанию: 12 часов
+
+	// MinTrackingEvents — сколько событий нужно треку, чтобы доверять его собственному ритму.
+	MinTrackingEvents int // по умолчанию: 3
+	// CadenceWindow — за какой период ритм перевозчика считается по tracking_events.
+	CadenceWindow time.Duration // по умолчанию: 30 дней
+	// RefreshInterval — как часто RunRefresh перечитывает ритм; новое значение действует со следующего тика.
+	RefreshInterval time.Duration // по умолчанию: 10 минут
+
+	BusinessHours map[string]BusinessHours
+}
+
+func DefaultHistoryPlannerConfig() HistoryPlannerConfig {
+	return HistoryPlannerConfig{
+		MinDelay:          1 * time.Minute,
+		MaxDelay:          12 * time.Hour,
+		MinTrackingEvents: 3,
+		CadenceWindow:     30 * 24 * time.Hour,
+		RefreshInterval:   10 * time.Minute,
+	}
+}
+
+// HistoryPlanner предсказывает следующую смену статуса по ритму событий (трека и перевозчика),
+// рабочим часам перевозчика и ETA и ставит следующую проверку сразу после неё.
+// DELIVERED-треки и ошибки проверки отдаются fallback-стратегии.
+type HistoryPlanner struct {
+	mu       sync.RWMutex // защищает все поля: настройки и ритм меняются на ходу
+	cfg      HistoryPlannerConfig
+	fallback Strategy
+	cadence  map[string]models.CarrierCadence
+}
+
+func NewHistoryPlanner(cfg HistoryPlannerConfig, fallback Strategy) *HistoryPlanner {
+	h := &HistoryPlanner{cadence: map[string]models.CarrierCadence{}}
+	h.Reconfigure(cfg, fallback)
+	return h
+}
+
+// Reconfigure заменяет настройки и fallback-стратегию (горячая перезагрузка конфига); выученный ритм сохраняется.
+func (h *HistoryPlanner) Reconfigure(cfg HistoryPlannerConfig, fallback Strategy) {
+	def := DefaultHistoryPlannerConfig()
+	if cfg.MinDelay <= 0 {
+		cfg.MinDelay = def.MinDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = def.MaxDelay
+	}
+	if cfg.MaxDelay < cfg.MinDelay {
+		cfg.MaxDelay = cfg.MinDelay
+	}
+	if cfg.MinTrackingEvents <= 1 {
+		cfg.MinTrackingEvents = def.MinTrackingEvents
+	}
+	if cfg.CadenceWindow <= 0 {
+		cfg.CadenceWindow = def.CadenceWindow
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = def.RefreshInterval
+	}
+	if fallback == nil {
+		fallback = DefaultPlanner()
+	}
+	h.mu.Lock()
+	h.cfg = cfg
+	h.fallback = fallback
+	h.mu.Unlock()
+}
+
+func (h *HistoryPlanner) settings() (HistoryPlannerConfig, Strategy) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.cfg, h.fallback
+}
+
+// SetCarrierCadence заменяет статистику ритма по перевозчикам.
+func (h *HistoryPlanner) SetCarrierCadence(cs []models.CarrierCadence) {
+	m := make(map[string]models.CarrierCadence, len(cs))
+	for _, c := range cs {
+		m[c.CarrierCode] = c
+	}
+	h.mu.Lock()
+	h.cadence = m
+	h.mu.Unlock()
+}
+
+func (h *HistoryPlanner) carrierCadence(carrier string) (models.CarrierCadence, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	c, ok := h.cadence[carrier]
+	return c, ok
+}
+
+// Refresh перечитывает ритм перевозчиков из репозитория.
+func (h *HistoryPlanner) Refresh(ctx context.Context, repo HistoryRepository, now time.Time) error {
+	cfg, _ := h.settings()
+	cs, err := repo.CarrierEventCadence(ctx, now.Add(-cfg.CadenceWindow))
+	if err != nil {
+		return err
+	}
+	h.SetCarrierCadence(cs)
+	return nil
+}
+
+// RunRefresh периодически перечитывает ритм перевозчиков до отмены ctx.
+// Интервал берётся из текущих настроек перед каждым ожиданием, так что Reconfigure меняет его на ходу.
+func (h *HistoryPlanner) RunRefresh(ctx context.Context, repo HistoryRepository) {
+	t := time.NewTimer(0)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		if err := h.Refresh(ctx, repo, time.Now().UTC()); err != nil && ctx.Err() == nil {
+			slog.Error("refresh carrier cadence", "error", err.Error())
+		}
+		cfg, _ := h.settings()
+		t.Reset(cfg.RefreshInterval)
+	}
+}
+
+func (h *HistoryPlanner) PlanRetry(in PlanInput) time.Duration {
+	_, fallback := h.settings()
+	return fallback.PlanRetry(in)
+}
+
+func (h *HistoryPlanner) PlanNextCheck(in PlanInput) time.Duration {
+	cfg, fallback := h.settings()
+	if in.Status == models.TrackingStatusDelivered {
+		return fallback.PlanNextCheck(in)
+	}
+
+	gap, ok := h.expectedGap(cfg, in)
+	last, hasLast := lastEventTime(in.Events)
+	if !ok || !hasLast {
+		return fallback.PlanNextCheck(in)
+	}
+
+	var delay time.Duration
+	expected := last.Add(gap)
+	if in.Now.Before(expected) {
+		// Проверяем вскоре после ожидаемой смены статуса.
+		delay = expected.Sub(in.Now) + gap/10
+	} else {
+		// Просрочено: чем дольше посылка молчит, тем реже спрашиваем.
+		overdue := in.Now.Sub(expected)
+		delay = gap/2 + overdue/2
+	}
+
+	if in.ETA != nil {
+		untilETA := in.ETA.Sub(in.Now)
+		switch {
+		case untilETA > 0 && untilETA < delay:
+			delay = untilETA
+		case untilETA <= 0 && -untilETA < gap:
+			// ETA только что прошёл: доставка вероятна в любой момент.
+			delay = cfg.MinDelay
+		}
+	}
+
+	delay = clampDelay(cfg, delay)
+	if bh, ok := cfg.BusinessHours[in.CarrierCode]; ok {
+		// Сначала ограничиваем, потом сдвигаем: ограничение после сдвига могло вернуть проверку в нерабочее время.
+		// MaxDelay остаётся жёстким пределом: если следующее окно открывается позже него, проверяем
+		// в последний рабочий слот до предела, а если такого нет — на самом пределе.
+		at := bh.shift(in.Now.Add(delay))
+		if limit := in.Now.Add(cfg.MaxDelay); at.After(limit) {
+			at = limit
+			if last := bh.lastBefore(limit); last.Sub(in.Now) >= cfg.MinDelay {
+				at = last
+			}
+		}
+		delay = at.Sub(in.Now)
+	}
+	return delay
+}
+
+// expectedGap смешивает медианный интервал между событиями самого трека и перевозчика.
+func (h *HistoryPlanner) expectedGap(cfg HistoryPlannerConfig, in PlanInput) (time.Duration, bool) {
+	own, ownN := medianGap(in.Events)
+	cc, hasCarrier := h.carrierCadence(in.CarrierCode)
+	hasCarrier = hasCarrier && cc.MedianGap > 0
+
+	switch {
+	case ownN+1 >= cfg.MinTrackingEvents && hasCarrier:
+		// Вес трека — число его интервалов, вес перевозчика — MinTrackingEvents интервалов.
+		w := float64(ownN)
+		wc := float64(cfg.MinTrackingEvents)
+		return time.Duration((float64(own)*w + float64(cc.MedianGap)*wc) / (w + wc)), true
+	case ownN+1 >= cfg.MinTrackingEvents:
+		return own, true
+	case hasCarrier:
+		return cc.MedianGap, true
+	default:
+		return 0, false
+	}
+}
+
+func clampDelay(cfg HistoryPlannerConfig, d time.Duration) time.Duration {
+	if d < cfg.MinDelay {
+		return cfg.MinDelay
+	}
+	if d > cfg.MaxDelay {
+		return cfg.MaxDelay
+	}
+	return d
+}
+
+// shift переносит t на начало следующего рабочего окна, если t вне рабочих часов.
+func (b BusinessHours) shift(t time.Time) time.Time {
+	loc := b.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	if b.EndHour <= b.StartHour {
+		return t
+	}
+	local := t.In(loc)
+	h := local.Hour()
+	if h >= b.StartHour && h < b.EndHour {
+		return t
+	}
+	day := local
+	if h >= b.EndHour {
+		day = local.AddDate(0, 0, 1)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), b.StartHour, 0, 0, 0, loc).Add(15 * time.Minute)
+}
+
+// lastBefore возвращает t, если оно в рабочих часах, иначе последний слот (за 15 минут до закрытия)
+// предыдущего рабочего окна.
+func (b BusinessHours) lastBefore(t time.Time) time.Time {
+	loc := b.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	if b.EndHour <= b.StartHour {
+		return t
+	}
+	local := t.In(loc)
+	h := local.Hour()
+	if h >= b.StartHour && h < b.EndHour {
+		return t
+	}
+	day := local
+	if h < b.StartHour {
+		day = local.AddDate(0, 0, -1)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), b.EndHour, 0, 0, 0, loc).Add(-15 * time.Minute)
+}
+
+func lastEventTime(evs []*models.TrackingEvent) (time.Time, bool) {
+	var last time.Time
+	for _, e := range evs {
+		if e.EventTime.After(last) {
+			last = e.EventTime
+		}
+	}
+	return last, !last.IsZero()
+}
+
+// medianGap возвращает медиану интервалов между соседними различными временами событий и число интервалов.
+func medianGap(evs []*models.TrackingEvent) (time.Duration, int) {
+	if len(evs) < 2 {
+		return 0, 0
+	}
+	ts := make([]time.Time, 0, len(evs))
+	for _, e := range evs {
+		ts = append(ts, e.EventTime)
+	}
+	sort.Slice(ts, func(i, j int) bool { return ts[i].Before(ts[j]) })
+
+	gaps := make([]time.Duration, 0, len(ts)-1)
+	for i := 1; i < len(ts); i++ {
+		if g := ts[i].Sub(ts[i-1]); g > 0 {
+			gaps = append(gaps, g)
+		}
+	}
+	if len(gaps) == 0 {
+		return 0, 0
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	mid := len(gaps) / 2
+	if len(gaps)%2 == 0 {
+		return (gaps[mid-1] + gaps[mid]) / 2, len(gaps)
+	}
+	return gaps[mid], len(gaps)
+}
@@ -0,0 +1,244 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type HistoryPlannerSuite struct {
+	suite.Suite
+	base *Planner
+	t0   time.Time
+}
+
+func (s *HistoryPlannerSuite) SetupTest() {
+	s.base = NewPlanner(PlannerConfig{
+		InTransitMinDelay: 30 * time.Minute,
+		InTransitMaxDelay: 30 * time.Minute,
+		UnknownDelay:      90 * time.Minute,
+	}, nil)
+	s.t0 = time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *HistoryPlannerSuite) events(gaps ...time.Duration) []*models.TrackingEvent {
+	at := s.t0
+	evs := []*models.TrackingEvent{{Status: models.TrackingStatusInTransit, EventTime: at}}
+	for _, g := range gaps {
+		at = at.Add(g)
+		evs = append(evs, &models.TrackingEvent{Status: models.TrackingStatusInTransit, EventTime: at})
+	}
+	return evs
+}
+
+func (s *HistoryPlannerSuite) TestFallbackWithoutHistory() {
+	h := NewHistoryPlanner(HistoryPlannerConfig{}, s.base)
+	in := PlanInput{CarrierCode: "CDEK", Status: models.TrackingStatusInTransit, Now: s.t0}
+	s.Equal(30*time.Minute, h.PlanNextCheck(in))
+}
+
+func (s *HistoryPlannerSuite) TestDeliveredAndRetryDelegate() {
+	h := NewHistoryPlanner(HistoryPlannerConfig{}, s.base)
+	in := PlanInput{Status: models.TrackingStatusDelivered, Now: s.t0, Events: s.events(time.Hour, time.Hour)}
+	s.Equal(365*24*time.Hour, h.PlanNextCheck(in))
+	s.Equal(15*time.Minute, h.PlanRetry(PlanInput{FailCount: 2}))
+}
+
+func (s *HistoryPlannerSuite) TestOwnCadence_ChecksAfterExpectedChange() {
+	h := NewHistoryPlanner(HistoryPlannerConfig{}, s.base)
+	evs := s.events(4*time.Hour, 4*time.Hour) // последнее событие в t0+8h, интервал 4h
+	now := s.t0.Add(9 * time.Hour)
+
+	d := h.PlanNextCheck(PlanInput{Status: models.TrackingStatusInTransit, Now: now, Events: evs})
+	// смена ожидается в t0+12h → через 3h плюс 10% интервала.
+	s.Equal(3*time.Hour+24*time.Minute, d)
+}
+
+func (s *HistoryPlannerSuite) TestOverdue_BacksOff() {
+	h := NewHistoryPlanner(HistoryPlannerConfig{}, s.base)
+	evs := s.events(2*time.Hour, 2*time.Hour) // смена ожидается в t0+6h
+	now := s.t0.Add(10 * time.Hour)
+
+	d := h.PlanNextCheck(PlanInput{Status: models.TrackingStatusInTransit, Now: now, Events: evs})
+	s.Equal(1*time.Hour+2*time.Hour, d) // gap/2 + overdue/2
+}
+
+func (s *HistoryPlannerSuite) TestCarrierCadence_UsedForShortHistory() {
+	h := NewHistoryPlanner(HistoryPlannerConfig{}, s.base)
+	h.SetCarrierCadence([]models.CarrierCadence{{CarrierCode: "POST_RU", MedianGap: 10 * time.Hour, Samples: 100}})
+	evs := s.events()
+	now := s.t0.Add(1 * time.Hour)
+
+	d := h.PlanNextCheck(PlanInput{CarrierCode: "POST_RU", Status: models.TrackingStatusInTransit, Now: now, Events: evs})
+	s.Equal(9*time.Hour+time.Hour, d)
+}
+
+func (s *HistoryPlannerSuite) TestETA_CapsDelay() {
+	h := NewHistoryPlanner(HistoryPlannerConfig{}, s.base)
+	evs := s.events(6*time.Hour, 6*time.Hour)
+	now := s.t0.Add(13 * time.Hour)
+	eta := now.Add(2 * time.Hour)
+
+	d := h.PlanNextCheck(PlanInput{Status: models.TrackingStatusInTransit, Now: now, Events: evs, ETA: &eta})
+	s.Equal(2*time.Hour, d)
+}
+
+func (s *HistoryPlannerSuite) TestClampAndBusinessHours() {
+	msk := time.FixedZone("MSK", 3*60*60)
+	h := NewHistoryPlanner(HistoryPlannerConfig{
+		MaxDelay: 6 * time.Hour,
+		BusinessHours: map[string]BusinessHours{
+			"CDEK": {Location: msk, StartHour: 9, EndHour: 21},
+		},
+	}, s.base)
+
+	// 19:00 MSK, интервал 1h → проверка в 20:06 MSK (в рабочие часы).
+	now := time.Date(2025, 1, 10, 16, 0, 0, 0, time.UTC)
+	evs := []*models.TrackingEvent{
+		{EventTime: now.Add(-2 * time.Hour)},
+		{EventTime: now.Add(-1 * time.Hour)},
+		{EventTime: now},
+	}
+	d := h.PlanNextCheck(PlanInput{CarrierCode: "CDEK", Status: models.TrackingStatusInTransit, Now: now, Events: evs})
+	s.Equal(66*time.Minute, d)
+
+	// 20:30 MSK, интервал 2h → 22:42 MSK вне рабочих часов, утро 09:15 MSK позже предела 6h (02:30 MSK)
+	// → последний рабочий слот до предела: 20:45 MSK.
+	now = time.Date(2025, 1, 10, 17, 30, 0, 0, time.UTC)
+	evs = []*models.TrackingEvent{
+		{EventTime: now.Add(-4 * time.Hour)},
+		{EventTime: now.Add(-2 * time.Hour)},
+		{EventTime: now},
+	}
+	d = h.PlanNextCheck(PlanInput{CarrierCode: "CDEK", Status: models.TrackingStatusInTransit, Now: now, Events: evs})
+	s.Equal(15*time.Minute, d)
+	s.Equal(20, now.Add(d).In(msk).Hour())
+
+	// 22:00 MSK, до предела (04:00 MSK) рабочих слотов нет → проверяем на пределе.
+	now = time.Date(2025, 1, 10, 19, 0, 0, 0, time.UTC)
+	evs = []*models.TrackingEvent{
+		{EventTime: now.Add(-4 * time.Hour)},
+		{EventTime: now.Add(-2 * time.Hour)},
+		{EventTime: now},
+	}
+	d = h.PlanNextCheck(PlanInput{CarrierCode: "CDEK", Status: models.TrackingStatusInTransit, Now: now, Events: evs})
+	s.Equal(6*time.Hour, d)
+
+	// 01:00 MSK, интервал 1h → 02:06 MSK до открытия, 09:15 MSK укладывается в предел 12h: сдвиг остаётся.
+	h = NewHistoryPlanner(HistoryPlannerConfig{
+		MaxDelay: 12 * time.Hour,
+		BusinessHours: map[string]BusinessHours{
+			"CDEK": {Location: msk, StartHour: 9, EndHour: 21},
+		},
+	}, s.base)
+	now = time.Date(2025, 1, 10, 22, 0, 0, 0, time.UTC)
+	evs = []*models.TrackingEvent{
+		{EventTime: now.Add(-2 * time.Hour)},
+		{EventTime: now.Add(-1 * time.Hour)},
+		{EventTime: now},
+	}
+	d = h.PlanNextCheck(PlanInput{CarrierCode: "CDEK", Status: models.TrackingStatusInTransit, Now: now, Events: evs})
+	s.Equal(8*time.Hour+15*time.Minute, d)
+}
+
+func (s *HistoryPlannerSuite) TestBusinessHoursShift() {
+	bh := BusinessHours{Location: time.UTC, StartHour: 9, EndHour: 18}
+	s.Equal(time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC), bh.shift(time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)))
+	s.Equal(time.Date(2025, 1, 10, 9, 15, 0, 0, time.UTC), bh.shift(time.Date(2025, 1, 10, 3, 0, 0, 0, time.UTC)))
+	s.Equal(time.Date(2025, 1, 11, 9, 15, 0, 0, time.UTC), bh.shift(time.Date(2025, 1, 10, 19, 0, 0, 0, time.UTC)))
+
+	s.Equal(time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC), bh.lastBefore(time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)))
+	s.Equal(time.Date(2025, 1, 9, 17, 45, 0, 0, time.UTC), bh.lastBefore(time.Date(2025, 1, 10, 3, 0, 0, 0, time.UTC)))
+	s.Equal(time.Date(2025, 1, 10, 17, 45, 0, 0, time.UTC), bh.lastBefore(time.Date(2025, 1, 10, 19, 0, 0, 0, time.UTC)))
+}
+
+type fakeHistoryRepo struct {
+	cadence []models.CarrierCadence
+	err     error
+	calls   chan struct{} // если задан — сигнал на каждый CarrierEventCadence
+}
+
+func (f *fakeHistoryRepo) CarrierEventCadence(_ context.Context, _ time.Time) ([]models.CarrierCadence, error) {
+	if f.calls != nil {
+		f.calls <- struct{}{}
+	}
+	return f.cadence, f.err
+}
+
+func (f *fakeHistoryRepo) LoadTrackingHistories(_ context.Context, _ string, _ time.Time, _ int) ([]*models.TrackingHistory, error) {
+	return nil, f.err
+}
+
+func (s *HistoryPlannerSuite) TestRefresh() {
+	h := NewHistoryPlanner(HistoryPlannerConfig{}, s.base)
+	s.Require().NoError(h.Refresh(context.Background(), &fakeHistoryRepo{
+		cadence: []models.CarrierCadence{{CarrierCode: "CDEK", MedianGap: time.Hour}},
+	}, s.t0))
+	c, ok := h.carrierCadence("CDEK")
+	s.True(ok)
+	s.Equal(time.Hour, c.MedianGap)
+
+	s.Error(h.Refresh(context.Background(), &fakeHistoryRepo{err: errors.New("db down")}, s.t0))
+	_, ok = h.carrierCadence("CDEK")
+	s.True(ok, "cadence is kept on refresh error")
+}
+
+func (s *HistoryPlannerSuite) TestRunRefresh_RereadsInterval() {
+	repo := &fakeHistoryRepo{calls: make(chan struct{}, 100)}
+	h := NewHistoryPlanner(HistoryPlannerConfig{RefreshInterval: time.Millisecond}, s.base)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.RunRefresh(ctx, repo)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-repo.calls:
+		case <-time.After(time.Second):
+			s.FailNow("refresh did not run")
+		}
+	}
+
+	// новый интервал действует со следующего тика: уже взведённый таймер может сработать ещё раз
+	h.Reconfigure(HistoryPlannerConfig{RefreshInterval: time.Hour}, s.base)
+	time.Sleep(20 * time.Millisecond)
+	for len(repo.calls) > 0 {
+		<-repo.calls
+	}
+	time.Sleep(20 * time.Millisecond)
+	s.Empty(repo.calls)
+}
+
+func (s *HistoryPlannerSuite) TestBacktest_HistoryBeatsStatic() {
+	// События раз в 6 часов: опрос каждые 30 минут тратит много лишних проверок.
+	var histories []*models.TrackingHistory
+	for i := 0; i < 5; i++ {
+		evs := s.events(6*time.Hour, 6*time.Hour, 6*time.Hour, 6*time.Hour)
+		evs[len(evs)-1].Status = models.TrackingStatusDelivered
+		histories = append(histories, &models.TrackingHistory{
+			Tracking: &models.Tracking{ID: uint64(i + 1), CarrierCode: "CDEK"},
+			Events:   evs,
+		})
+	}
+	histories = append(histories, &models.TrackingHistory{Tracking: &models.Tracking{ID: 100}}) // без событий: пропускается
+
+	static := Backtest(s.base, histories)
+	s.Equal(5, static.Trackings)
+	s.Equal(20, static.Events)
+	s.Equal(5*(1+4*12), static.Checks)
+	s.Equal(time.Duration(0), static.MaxDetectionDelay)
+
+	h := NewHistoryPlanner(HistoryPlannerConfig{}, s.base)
+	h.SetCarrierCadence([]models.CarrierCadence{{CarrierCode: "CDEK", MedianGap: 6 * time.Hour}})
+	hist := Backtest(h, histories)
+	s.Equal(20, hist.Events)
+	s.Less(hist.ChecksPerEvent, static.ChecksPerEvent)
+	s.LessOrEqual(hist.P90DetectionDelay, 36*time.Minute)
+}
+
+func TestHistoryPlannerSuite(t *testing.T) {
+	suite.Run(t, new(HistoryPlannerSuite))
+}
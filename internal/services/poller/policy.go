@@ -0,0 +1,170 @@
+package poller
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+)
+
+type JitterMode string
+
+const (
+	// JitterNone: нижняя граница диапазона задержки, точное значение backoff.
+	JitterNone JitterMode = "none"
+	// JitterUniform: равномерно в [min,max] для диапазонов задержки, в [d/2,d] для backoff.
+	JitterUniform JitterMode = "uniform"
+)
+
+type DelayRange struct {
+	Min time.Duration
+	Max time.Duration // 0 — равно Min
+}
+
+// BackoffPolicy — экспоненциальный: Initial * Multiplier^(failCount-1), но не больше Max.
+// После MaxAttempts ошибок подряд трек откладывается на GiveUpDelay.
+// MaxAttempts — указатель: nil наследуется, явный 0 — без ограничения, даже если у base лимит есть.
+type BackoffPolicy struct {
+	Initial     time.Duration
+	Multiplier  float64
+	Max         time.Duration
+	MaxAttempts *int32
+	GiveUpDelay time.Duration
+}
+
+// SchedulePolicy — набор правил планирования; нулевые поля наследуются (перевозчик → default → встроенные).
+type SchedulePolicy struct {
+	Statuses map[string]DelayRange
+	Jitter   JitterMode
+	Backoff  BackoffPolicy
+}
+
+type SchedulingPolicies struct {
+	Default  SchedulePolicy
+	Carriers map[string]SchedulePolicy
+}
+
+func DefaultSchedulePolicy() SchedulePolicy {
+	def := DefaultPlannerConfig()
+	return SchedulePolicy{
+		Statuses: map[string]DelayRange{
+			models.TrackingStatusUnknown:   {Min: def.UnknownDelay, Max: def.UnknownDelay},
+			models.TrackingStatusInTransit: {Min: def.InTransitMinDelay, Max: def.InTransitMaxDelay},
+			models.TrackingStatusDelivered: {Min: def.DeliveredDelay, Max: def.DeliveredDelay},
+		},
+		Jitter: JitterUniform,
+		Backoff: BackoffPolicy{
+			Initial:     def.Backoff1,
+			Multiplier:  2,
+			Max:         def.Backoff4,
+			GiveUpDelay: 24 * time.Hour,
+		},
+	}
+}
+
+// PolicyPlanner выбирает правила SchedulePolicy по перевозчику, статусу и числу ошибок.
+type PolicyPlanner struct {
+	def      SchedulePolicy
+	carriers map[string]SchedulePolicy
+	r        Rand
+}
+
+func NewPolicyPlanner(p SchedulingPolicies, r Rand) *PolicyPlanner {
+	def := mergePolicy(p.Default, DefaultSchedulePolicy())
+	carriers := make(map[string]SchedulePolicy, len(p.Carriers))
+	for code, cp := range p.Carriers {
+		carriers[code] = mergePolicy(cp, def)
+	}
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &PolicyPlanner{def: def, carriers: carriers, r: r}
+}
+
+// mergePolicy заполняет нулевые поля p (для MaxAttempts — nil) из base.
+func mergePolicy(p, base SchedulePolicy) SchedulePolicy {
+	out := SchedulePolicy{
+		Statuses: make(map[string]DelayRange, len(base.Statuses)),
+		Jitter:   p.Jitter,
+		Backoff:  p.Backoff,
+	}
+	for st, r := range base.Statuses {
+		out.Statuses[st] = r
+	}
+	for st, r := range p.Statuses {
+		if r.Max < r.Min {
+			r.Max = r.Min
+		}
+		out.Statuses[st] = r
+	}
+	if out.Jitter == "" {
+		out.Jitter = base.Jitter
+	}
+	b := &out.Backoff
+	if b.Initial <= 0 {
+		b.Initial = base.Backoff.Initial
+	}
+	if b.Multiplier < 1 {
+		b.Multiplier = base.Backoff.Multiplier
+	}
+	if b.Max <= 0 {
+		b.Max = base.Backoff.Max
+	}
+	if b.Max < b.Initial {
+		b.Max = b.Initial
+	}
+	if b.MaxAttempts == nil {
+		b.MaxAttempts = base.Backoff.MaxAttempts
+	}
+	if b.GiveUpDelay <= 0 {
+		b.GiveUpDelay = base.Backoff.GiveUpDelay
+	}
+	return out
+}
+
+func (pp *PolicyPlanner) policy(carrierCode string) SchedulePolicy {
+	if p, ok := pp.carriers[carrierCode]; ok {
+		return p
+	}
+	return pp.def
+}
+
+func (pp *PolicyPlanner) PlanNextCheck(in PlanInput) time.Duration {
+	p := pp.policy(in.CarrierCode)
+	r, ok := p.Statuses[in.Status]
+	if !ok {
+		r = p.Statuses[models.TrackingStatusUnknown]
+	}
+	if p.Jitter != JitterUniform || r.Max <= r.Min {
+		return r.Min
+	}
+	secMin := int(r.Min.Seconds())
+	secMax := int(r.Max.Seconds())
+	return time.Duration(secMin+pp.r.Intn(secMax-secMin+1)) * time.Second
+}
+
+func (pp *PolicyPlanner) PlanRetry(in PlanInput) time.Duration {
+	p := pp.policy(in.CarrierCode)
+	b := p.Backoff
+	n := in.FailCount
+	if n < 1 {
+		n = 1
+	}
+	if b.MaxAttempts != nil && *b.MaxAttempts > 0 && n > *b.MaxAttempts {
+		return b.GiveUpDelay
+	}
+
+	d := b.Max
+	if f := float64(b.Initial) * math.Pow(b.Multiplier, float64(n-1)); f < float64(b.Max) {
+		d = time.Duration(f)
+	}
+	if p.Jitter != JitterUniform {
+		return d
+	}
+	half := int(d.Seconds()) / 2
+	if half <= 0 {
+		return d
+	}
+	return d - time.Duration(pp.r.Intn(half+1))*time.Second
+}
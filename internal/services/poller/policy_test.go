@@ -0,0 +1,117 @@
+package poller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	pollermocks "github.com/BearBump/TrackBox/internal/services/poller/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PolicyPlannerSuite struct {
+	suite.Suite
+}
+
+func (s *PolicyPlannerSuite) policies() SchedulingPolicies {
+	return SchedulingPolicies{
+		Default: SchedulePolicy{
+			Statuses: map[string]DelayRange{
+				models.TrackingStatusInTransit: {Min: 30 * time.Minute, Max: 2 * time.Hour},
+				models.TrackingStatusUnknown:   {Min: 90 * time.Minute},
+			},
+			Jitter: JitterNone,
+			Backoff: BackoffPolicy{
+				Initial:     5 * time.Minute,
+				Multiplier:  3,
+				Max:         time.Hour,
+				MaxAttempts: attempts(5),
+				GiveUpDelay: 12 * time.Hour,
+			},
+		},
+		Carriers: map[string]SchedulePolicy{
+			"POST_RU": {
+				Statuses: map[string]DelayRange{
+					models.TrackingStatusInTransit: {Min: 2 * time.Hour, Max: 4 * time.Hour},
+				},
+				Jitter:  JitterUniform,
+				Backoff: BackoffPolicy{Initial: 10 * time.Minute},
+			},
+		},
+	}
+}
+
+func (s *PolicyPlannerSuite) TestNextCheck_DefaultPolicy() {
+	pp := NewPolicyPlanner(s.policies(), nil)
+	s.Equal(30*time.Minute, pp.PlanNextCheck(PlanInput{CarrierCode: "CDEK", Status: models.TrackingStatusInTransit}))
+	s.Equal(90*time.Minute, pp.PlanNextCheck(PlanInput{CarrierCode: "CDEK", Status: models.TrackingStatusUnknown}))
+	s.Equal(90*time.Minute, pp.PlanNextCheck(PlanInput{CarrierCode: "CDEK", Status: "SOMETHING_NEW"}))
+	// Нигде не настроено: встроенное значение.
+	s.Equal(365*24*time.Hour, pp.PlanNextCheck(PlanInput{CarrierCode: "CDEK", Status: models.TrackingStatusDelivered}))
+}
+
+func (s *PolicyPlannerSuite) TestNextCheck_CarrierOverrideWithJitter() {
+	m := &pollermocks.Rand{}
+	m.On("Intn", int((2*time.Hour).Seconds())+1).Return(600).Once()
+	pp := NewPolicyPlanner(s.policies(), m)
+
+	d := pp.PlanNextCheck(PlanInput{CarrierCode: "POST_RU", Status: models.TrackingStatusInTransit})
+	s.Equal(2*time.Hour+10*time.Minute, d)
+	// Статус, не переопределённый перевозчиком, наследуется из default.
+	s.Equal(90*time.Minute, pp.PlanNextCheck(PlanInput{CarrierCode: "POST_RU", Status: models.TrackingStatusUnknown}))
+	m.AssertExpectations(s.T())
+}
+
+func (s *PolicyPlannerSuite) TestRetry_ExponentialCapAndGiveUp() {
+	pp := NewPolicyPlanner(s.policies(), nil)
+	in := PlanInput{CarrierCode: "CDEK"}
+
+	want := []time.Duration{5 * time.Minute, 15 * time.Minute, 45 * time.Minute, time.Hour, time.Hour, 12 * time.Hour}
+	for i, w := range want {
+		in.FailCount = int32(i + 1)
+		s.Equal(w, pp.PlanRetry(in), "fail count %d", in.FailCount)
+	}
+}
+
+func (s *PolicyPlannerSuite) TestRetry_CarrierInheritsAndJitters() {
+	m := &pollermocks.Rand{}
+	m.On("Intn", mock.Anything).Return(0)
+	pp := NewPolicyPlanner(s.policies(), m)
+
+	// initial от перевозчика (10m), multiplier/max/attempts из default.
+	s.Equal(10*time.Minute, pp.PlanRetry(PlanInput{CarrierCode: "POST_RU", FailCount: 1}))
+	s.Equal(30*time.Minute, pp.PlanRetry(PlanInput{CarrierCode: "POST_RU", FailCount: 2}))
+	s.Equal(12*time.Hour, pp.PlanRetry(PlanInput{CarrierCode: "POST_RU", FailCount: 6}))
+
+	m2 := &pollermocks.Rand{}
+	m2.On("Intn", 301).Return(300)
+	pp = NewPolicyPlanner(s.policies(), m2)
+	s.Equal(5*time.Minute, pp.PlanRetry(PlanInput{CarrierCode: "POST_RU", FailCount: 1}))
+}
+
+func (s *PolicyPlannerSuite) TestEmptyPolicies_UseBuiltins() {
+	m := &pollermocks.Rand{}
+	m.On("Intn", mock.Anything).Return(0)
+	pp := NewPolicyPlanner(SchedulingPolicies{}, m)
+	s.Equal(1*time.Minute, pp.PlanNextCheck(PlanInput{Status: models.TrackingStatusInTransit}))
+	s.Equal(5*time.Minute, pp.PlanRetry(PlanInput{FailCount: 1}))
+	s.Equal(10*time.Minute, pp.PlanRetry(PlanInput{FailCount: 2}))
+	s.Equal(60*time.Minute, pp.PlanRetry(PlanInput{FailCount: 100}))
+}
+
+func (s *PolicyPlannerSuite) TestRetry_CarrierZeroAttemptsIsUnlimited() {
+	p := s.policies()
+	p.Carriers["CDEK"] = SchedulePolicy{Backoff: BackoffPolicy{MaxAttempts: attempts(0)}}
+	pp := NewPolicyPlanner(p, nil)
+
+	// у default лимит 5, явный 0 у перевозчика его снимает
+	s.Equal(time.Hour, pp.PlanRetry(PlanInput{CarrierCode: "CDEK", FailCount: 100}))
+	s.Equal(12*time.Hour, pp.PlanRetry(PlanInput{CarrierCode: "SOME", FailCount: 100}))
+}
+
+func attempts(n int32) *int32 { return &n }
+
+func TestPolicyPlannerSuite(t *testing.T) {
+	suite.Run(t, new(PolicyPlannerSuite))
+}
@@ -27,23 +27,41 @@ type RateLimiter interface {
 	Allow(ctx context.Context, key string, limit int64, window time.Duration) (bool, int64, error)
 }
 
+// Geocoder дополняет место события (например, geo.Dataset): text — место свободным текстом от перевозчика.
+// TimeZone — часовой пояс места (nil — неизвестен), для местного времени событий.
+type Geocoder interface {
+	Resolve(loc *models.Location, text string) *models.Location
+	TimeZone(loc *models.Location) *time.Location
+}
+
+// Archiver сохраняет сырой ответ перевозчика и возвращает ссылку на него (archive.Archive).
+type Archiver interface {
+	Save(ctx context.Context, backend string, body []byte) (string, error)
+}
+
 type Poller struct {
 	repo Repository
 	carrier carrier.Client
 	producer Producer
 	rl RateLimiter
+	geocoder Geocoder // nil — места событий как отдал перевозчик
+	instance string   // имя экземпляра worker'а для журнала проверок
+	archive Archiver  // nil — ответы перевозчика не сохраняются
 
 	topic string
 
-	planner *Planner
+	// liveMu защищает настройки, которые меняются на лету (Reload): planner, batchSize, rate limits.
+	liveMu sync.RWMutex
+
+	planner Strategy
 
 	pollInterval time.Duration
 	batchSize int
 	concurrency int
 	lease time.Duration
 	rateLimitPerMinute int64
-	rateLimitCDEKPerMinute int64
-	rateLimitPostRuPerMinute int64
+	carrierRateLimits map[string]int64 // код перевозчика -> лимит в минуту; нет/0 — общий лимит
+	zoneRules map[string]ZoneRule // код перевозчика -> пояс местного времени; нет — DefaultZoneRule
 
 	triggerCh chan struct{}
 
@@ -100,6 +118,33 @@ func (p *Poller) WithPlanner(cfg PlannerConfig) *Poller {
 	return p
 }
 
+// WithGeocoder включает дополнение мест событий по справочнику.
+func (p *Poller) WithGeocoder(g Geocoder) *Poller {
+	p.geocoder = g
+	return p
+}
+
+// WithInstance задаёт имя экземпляра worker'а, которое попадает в журнал проверок (messages.CheckInfo.Worker).
+func (p *Poller) WithInstance(name string) *Poller {
+	p.instance = name
+	return p
+}
+
+// WithArchive включает сохранение сырых ответов перевозчика; ссылка попадает в журнал проверок
+// (messages.CheckInfo.ResponseRef).
+func (p *Poller) WithArchive(a Archiver) *Poller {
+	p.archive = a
+	return p
+}
+
+// WithStrategy заменяет алгоритм планирования проверок (например, на HistoryPlanner).
+func (p *Poller) WithStrategy(s Strategy) *Poller {
+	if s != nil {
+		p.planner = s
+	}
+	return p
+}
+
 // Trigger forces an immediate poll cycle (best-effort, non-blocking).
 func (p *Poller) Trigger() {
 	p.lastTriggerUnixNano.Store(time.Now().UTC().UnixNano())
@@ -142,14 +187,58 @@ func (p *Poller) Stats() Stats {
 	return st
 }
 
-func (p *Poller) WithCarrierRateLimits(cdekPerMin, postRuPerMin int) *Poller {
-	if cdekPerMin > 0 {
-		p.rateLimitCDEKPerMinute = int64(cdekPerMin)
+// WithCarrierRateLimits задаёт лимиты запросов в минуту по кодам перевозчиков.
+func (p *Poller) WithCarrierRateLimits(perMin map[string]int64) *Poller {
+	p.carrierRateLimits = copyRateLimits(perMin)
+	return p
+}
+
+func copyRateLimits(in map[string]int64) map[string]int64 {
+	out := make(map[string]int64, len(in))
+	for code, n := range in {
+		if n > 0 {
+			out[code] = n
+		}
 	}
-	if postRuPerMin > 0 {
-		p.rateLimitPostRuPerMinute = int64(postRuPerMin)
+	return out
+}
+
+// LiveSettings — настройки, которые можно применить к работающему poller'у без рестарта (hot reload конфига).
+type LiveSettings struct {
+	BatchSize          int
+	RateLimitPerMinute int64
+	CarrierRateLimits  map[string]int64
+	Strategy           Strategy
+	ZoneRules          map[string]ZoneRule
+}
+
+// Reload применяет новые настройки; следующий цикл и следующие проверки увидят их.
+// Нулевой BatchSize и nil Strategy оставляют текущие значения; rate limit 0 означает «без лимита»/«общий лимит».
+func (p *Poller) Reload(s LiveSettings) {
+	p.liveMu.Lock()
+	defer p.liveMu.Unlock()
+	if s.BatchSize > 0 {
+		p.batchSize = s.BatchSize
+	}
+	p.rateLimitPerMinute = s.RateLimitPerMinute
+	p.carrierRateLimits = copyRateLimits(s.CarrierRateLimits)
+	p.zoneRules = s.ZoneRules
+	if s.Strategy != nil {
+		p.planner = s.Strategy
+	}
+}
+
+// Live возвращает текущие «живые» настройки.
+func (p *Poller) Live() LiveSettings {
+	p.liveMu.RLock()
+	defer p.liveMu.RUnlock()
+	return LiveSettings{
+		BatchSize:          p.batchSize,
+		RateLimitPerMinute: p.rateLimitPerMinute,
+		CarrierRateLimits:  p.carrierRateLimits,
+		Strategy:           p.planner,
+		ZoneRules:          p.zoneRules,
 	}
-	return p
 }
 
 func (p *Poller) Run(ctx context.Context) error {
@@ -172,7 +261,7 @@ func (p *Poller) runOnce(ctx context.Context) {
 	now := time.Now().UTC()
 	p.lastCycleUnixNano.Store(now.UnixNano())
 
-	items, err := p.repo.ClaimDueTrackings(ctx, now, p.batchSize, p.lease)
+	items, err := p.repo.ClaimDueTrackings(ctx, now, p.Live().BatchSize, p.lease)
 	if err != nil {
 		slog.Error("claim due trackings", "error", err.Error())
 		p.lastErrorMu.Lock()
@@ -208,66 +297,192 @@ func (p *Poller) runOnce(ctx context.Context) {
 	wg.Wait()
 }
 
+// ErrRateLimited возвращается CheckNow, если лимит запросов к перевозчику исчерпан.
+var ErrRateLimited = errors.New("carrier rate limit exceeded")
+
 func (p *Poller) processOne(ctx context.Context, tr *models.Tracking) error {
 	now := time.Now().UTC()
 
-	if p.rl != nil && p.rateLimitPerMinute > 0 {
-		limit := p.rateLimitPerMinute
-		switch tr.CarrierCode {
-		case "CDEK":
-			if p.rateLimitCDEKPerMinute > 0 {
-				limit = p.rateLimitCDEKPerMinute
-			}
-		case "POST_RU":
-			if p.rateLimitPostRuPerMinute > 0 {
-				limit = p.rateLimitPostRuPerMinute
-			}
-		}
+	allowed, err := p.allow(ctx, tr, now)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		// Слишком много запросов в минуту: подождём немного, чтобы разгрузить источник.
+		time.Sleep(500 * time.Millisecond)
+	}
 
-		minuteKey := fmt.Sprintf("rl:carrier:%s:%s", tr.CarrierCode, now.Format("200601021504"))
-		allowed, n, err := p.rl.Allow(ctx, minuteKey, limit, 70*time.Second)
-		if err != nil {
-			return err
-		}
-		if !allowed {
-			// Слишком много запросов в минуту: подождём немного, чтобы разгрузить источник.
-			slog.Warn("rate limit exceeded", "carrier", tr.CarrierCode, "count", n)
-			time.Sleep(500 * time.Millisecond)
-		}
+	return p.publish(ctx, tr, p.check(ctx, tr, now))
+}
+
+// CheckNow синхронно проверяет трек у перевозчика (вне очереди) и публикует результат в Kafka.
+// В отличие от обычного цикла, при превышении rate limit запрос не выполняется: возвращается ErrRateLimited.
+func (p *Poller) CheckNow(ctx context.Context, tr *models.Tracking) (messages.TrackingUpdated, error) {
+	now := time.Now().UTC()
+
+	allowed, err := p.allow(ctx, tr, now)
+	if err != nil {
+		return messages.TrackingUpdated{}, err
+	}
+	if !allowed {
+		return messages.TrackingUpdated{}, ErrRateLimited
 	}
 
+	msg := p.check(ctx, tr, now)
+	if err := p.publish(ctx, tr, msg); err != nil {
+		return messages.TrackingUpdated{}, err
+	}
+	return msg, nil
+}
+
+// allow учитывает запрос в минутном окне rate limit перевозчика.
+func (p *Poller) allow(ctx context.Context, tr *models.Tracking, now time.Time) (bool, error) {
+	live := p.Live()
+	if p.rl == nil || live.RateLimitPerMinute <= 0 {
+		return true, nil
+	}
+
+	limit := live.RateLimitPerMinute
+	if n := live.CarrierRateLimits[tr.CarrierCode]; n > 0 {
+		limit = n
+	}
+
+	minuteKey := fmt.Sprintf("rl:carrier:%s:%s", tr.CarrierCode, now.Format("200601021504"))
+	allowed, n, err := p.rl.Allow(ctx, minuteKey, limit, 70*time.Second)
+	if err != nil {
+		return false, err
+	}
+	if !allowed {
+		slog.Warn("rate limit exceeded", "carrier", tr.CarrierCode, "count", n)
+	}
+	return allowed, nil
+}
+
+// check ходит к перевозчику и собирает сообщение для Kafka (ошибка перевозчика попадает в msg.Error).
+// Вид сообщения и отпечаток ответа — см. classify.
+func (p *Poller) check(ctx context.Context, tr *models.Tracking, now time.Time) messages.TrackingUpdated {
+	started := time.Now()
 	res, err := p.carrier.GetTracking(ctx, tr.CarrierCode, tr.TrackNumber)
+	info := &messages.CheckInfo{
+		Worker:       p.instance,
+		Backend:      carrier.BackendOf(p.carrier, tr.CarrierCode),
+		LatencyMS:    time.Since(started).Milliseconds(),
+		HTTPStatus:   res.HTTPStatus,
+		ResponseHash: responseHash(res.Raw),
+	}
+	if err != nil {
+		info.ErrorClass, info.HTTPStatus = carrier.Classify(err)
+	} else {
+		info.EventsCount = len(res.Events)
+	}
+	info.ResponseRef = p.archiveResponse(ctx, tr, info.Backend, res.Raw)
+
+	msg := p.buildMessage(tr, res, err, now)
+	msg.Check = info
+	return msg
+}
+
+// Rebuild собирает сообщение по ответу перевозчика, полученному раньше (trackbox reparse по архиву ответов):
+// те же нормализация событий, план следующей проверки и сравнение с сохранённым треком, что у check,
+// но без запроса к перевозчику и без сведений о проверке (msg.Check).
+func (p *Poller) Rebuild(tr *models.Tracking, res carrier.TrackingResult, checkedAt time.Time) messages.TrackingUpdated {
+	return p.buildMessage(tr, res, nil, checkedAt)
+}
+
+// archiveResponse сохраняет тело ответа в архив и возвращает ссылку; ошибка архива проверку не портит.
+func (p *Poller) archiveResponse(ctx context.Context, tr *models.Tracking, backend string, raw []byte) string {
+	if p.archive == nil || len(raw) == 0 {
+		return ""
+	}
+	ref, err := p.archive.Save(ctx, backend, raw)
+	if err != nil {
+		slog.Warn("archive carrier response", "tracking_id", tr.ID, "error", err.Error())
+		return ""
+	}
+	return ref
+}
+
+func (p *Poller) buildMessage(tr *models.Tracking, res carrier.TrackingResult, err error, now time.Time) messages.TrackingUpdated {
 	msg := messages.TrackingUpdated{
 		TrackingID: tr.ID,
 		CheckedAt:  now,
+		ExternalID: tr.ExternalID,
+		Tags:       tr.Tags,
+	}
+	if tr.Metadata != nil {
+		msg.Metadata = json.RawMessage(*tr.Metadata)
+	}
+
+	planner := p.Live().Strategy
+	in := PlanInput{
+		CarrierCode: tr.CarrierCode,
+		Status:      tr.Status,
+		Now:         now,
 	}
 
 	if err != nil {
 		e := err.Error()
 		msg.Error = &e
-		nextFail := tr.CheckFailCount + 1
-		msg.NextCheckAt = now.Add(p.planner.BackoffDelay(nextFail))
+		in.FailCount = tr.CheckFailCount + 1
+		msg.NextCheckAt = now.Add(planner.PlanRetry(in))
 	} else {
 		msg.Status = res.Status
 		msg.StatusRaw = res.StatusRaw
 		msg.StatusAt = res.StatusAt
-		msg.NextCheckAt = now.Add(p.planner.NextCheckDelay(res.Status))
-		for _, e := range res.Events {
+		events := p.prepareEvents(tr, res.Events, now)
+		in.Status = res.Status
+		in.Events = events
+		if res.Shipment != nil {
+			in.ETA = res.Shipment.EstimatedDelivery
+		}
+		msg.Shipment = messages.ShipmentFromModel(res.Shipment)
+		msg.NextCheckAt = now.Add(planner.PlanNextCheck(in))
+		for _, e := range events {
 			var payload json.RawMessage
 			if e.PayloadJSON != nil && *e.PayloadJSON != "" {
 				payload = json.RawMessage(*e.PayloadJSON)
 			}
 			msg.Events = append(msg.Events, messages.TrackingEvent{
-				Status:    e.Status,
-				StatusRaw: e.StatusRaw,
-				EventTime: e.EventTime,
-				Location:  e.Location,
-				Message:   e.Message,
-				Payload:   payload,
+				Status:       e.Status,
+				StatusRaw:    e.StatusRaw,
+				EventTime:    e.EventTime,
+				Location:     e.Location,
+				Message:      e.Message,
+				Payload:      payload,
+				Place:        messages.PlaceFromModel(e.Place),
+				EventTimeRaw: e.EventTimeRaw,
+				TimeInferred: e.TimeInferred,
 			})
 		}
 	}
+	classify(tr, &msg)
+	return msg
+}
+
+// prepareEvents — копии событий перевозчика с дополненным местом (Geocoder) и временем в UTC
+// (см. normalizeEventTimes); события без времени получают время создания трека, а если оно неизвестно — now.
+func (p *Poller) prepareEvents(tr *models.Tracking, in []*models.TrackingEvent, now time.Time) []*models.TrackingEvent {
+	out := make([]*models.TrackingEvent, 0, len(in))
+	for _, e := range in {
+		ev := *e
+		if p.geocoder != nil {
+			text := ""
+			if ev.Location != nil {
+				text = *ev.Location
+			}
+			ev.Place = p.geocoder.Resolve(ev.Place, text)
+		}
+		out = append(out, &ev)
+	}
+	fallback := tr.CreatedAt
+	if fallback.IsZero() {
+		fallback = now
+	}
+	p.normalizeEventTimes(tr.CarrierCode, out, fallback)
+	return out
+}
 
+func (p *Poller) publish(ctx context.Context, tr *models.Tracking, msg messages.TrackingUpdated) error {
 	b, err := json.Marshal(msg)
 	if err != nil {
 		return errors.Wrap(err, "marshal kafka msg")
@@ -291,5 +506,3 @@ func (p *Poller) processOne(ctx context.Context, tr *models.Tracking) error {
 	}
 	return nil
 }
-
-
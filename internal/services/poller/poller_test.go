@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/geo"
 	"github.com/BearBump/TrackBox/internal/integrations/carrier"
 	"github.com/BearBump/TrackBox/internal/models"
 	"github.com/stretchr/testify/require"
@@ -87,9 +89,180 @@ func TestPoller_WithSettings(t *testing.T) {
 func TestPoller_WithCarrierRateLimits(t *testing.T) {
 	fp := &fakeProducer{}
 	p := New(nil, fakeCarrier{}, fp, nil, "t").
-		WithCarrierRateLimits(60, 20)
-	require.Equal(t, int64(60), p.rateLimitCDEKPerMinute)
-	require.Equal(t, int64(20), p.rateLimitPostRuPerMinute)
+		WithCarrierRateLimits(map[string]int64{"CDEK": 60, "POST_RU": 20, "DHL": 0})
+	require.Equal(t, map[string]int64{"CDEK": 60, "POST_RU": 20}, p.carrierRateLimits)
 }
 
 
+
+func TestPoller_CheckNow_rateLimited(t *testing.T) {
+	fp := &fakeProducer{}
+	p := New(nil, fakeCarrier{}, fp, fakeRL{allowed: false, count: 121}, "tracking.updated")
+	tr := &models.Tracking{ID: 1, CarrierCode: "CDEK", TrackNumber: "N"}
+
+	_, err := p.CheckNow(context.Background(), tr)
+	require.ErrorIs(t, err, ErrRateLimited)
+	require.Equal(t, 0, fp.calls)
+}
+
+func TestPoller_CheckNow_okReturnsPublishedMsg(t *testing.T) {
+	now := time.Now().UTC()
+	eta := now.Add(48 * time.Hour)
+	fp := &fakeProducer{}
+	p := New(nil, fakeCarrier{
+		res: carrier.TrackingResult{
+			Status:    "DELIVERED",
+			StatusRaw: "RAW",
+			StatusAt:  &now,
+			Events:    []*models.TrackingEvent{{Status: "DELIVERED", StatusRaw: "RAW", EventTime: now}},
+			Shipment:  &models.ShipmentDetails{EstimatedDelivery: &eta, WeightGrams: 500},
+		},
+	}, fp, fakeRL{allowed: true}, "tracking.updated")
+	tr := &models.Tracking{ID: 5, CarrierCode: "CDEK", TrackNumber: "N"}
+
+	msg, err := p.CheckNow(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), msg.TrackingID)
+	require.Equal(t, "DELIVERED", msg.Status)
+	require.Len(t, msg.Events, 1)
+	require.Nil(t, msg.Error)
+	require.Equal(t, &messages.Shipment{EstimatedDelivery: &eta, WeightGrams: 500}, msg.Shipment)
+	require.Equal(t, 1, fp.calls)
+	require.Equal(t, []byte("5"), fp.key)
+}
+
+func TestPoller_CheckNow_geocodesEvents(t *testing.T) {
+	now := time.Now().UTC()
+	loc := "Казань МСЦ"
+	p := New(nil, fakeCarrier{
+		res: carrier.TrackingResult{
+			Status: "IN_TRANSIT",
+			Events: []*models.TrackingEvent{
+				{StatusRaw: "a", EventTime: now, Place: &models.Location{PostalCode: "101000"}},
+				{StatusRaw: "b", EventTime: now, Location: &loc},
+				{StatusRaw: "c", EventTime: now},
+			},
+		},
+	}, &fakeProducer{}, fakeRL{allowed: true}, "tracking.updated").WithGeocoder(geo.Default())
+
+	msg, err := p.CheckNow(context.Background(), &models.Tracking{ID: 1, CarrierCode: "CDEK"})
+	require.NoError(t, err)
+	require.Len(t, msg.Events, 3)
+	require.Equal(t, "Москва", msg.Events[0].Place.Region)
+	require.Equal(t, "101000", msg.Events[0].Place.PostalCode)
+	require.Equal(t, "Республика Татарстан", msg.Events[1].Place.Region)
+	require.Equal(t, "Казань", msg.Events[1].Place.City)
+	require.NotNil(t, msg.Events[1].Place.Lat)
+	require.Nil(t, msg.Events[2].Place)
+}
+
+type fixedStrategy struct{ d time.Duration }
+
+func (s fixedStrategy) PlanNextCheck(PlanInput) time.Duration { return s.d }
+func (s fixedStrategy) PlanRetry(PlanInput) time.Duration     { return s.d }
+
+type limitRL struct{ limits []int64 }
+
+func (r *limitRL) Allow(ctx context.Context, key string, limit int64, window time.Duration) (bool, int64, error) {
+	r.limits = append(r.limits, limit)
+	return true, 1, nil
+}
+
+func TestPoller_Reload(t *testing.T) {
+	now := time.Now().UTC()
+	fp := &fakeProducer{}
+	rl := &limitRL{}
+	p := New(nil, fakeCarrier{res: carrier.TrackingResult{Status: "IN_TRANSIT", StatusAt: &now}}, fp, rl, "t").
+		WithSettings(time.Second, 10, 1, time.Second, 100).
+		WithCarrierRateLimits(map[string]int64{"CDEK": 60, "POST_RU": 20})
+	tr := &models.Tracking{ID: 1, CarrierCode: "CDEK", TrackNumber: "N"}
+
+	p.Reload(LiveSettings{BatchSize: 25, RateLimitPerMinute: 50, CarrierRateLimits: map[string]int64{"CDEK": 30}, Strategy: fixedStrategy{d: 42 * time.Minute}})
+
+	live := p.Live()
+	require.Equal(t, 25, live.BatchSize)
+	require.Equal(t, map[string]int64{"CDEK": 30}, live.CarrierRateLimits)
+
+	msg, err := p.CheckNow(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, []int64{30}, rl.limits)
+	require.WithinDuration(t, msg.CheckedAt.Add(42*time.Minute), msg.NextCheckAt, time.Second)
+
+	// Нулевой размер пачки и nil-стратегия оставляют текущие значения.
+	p.Reload(LiveSettings{RateLimitPerMinute: 50})
+	require.Equal(t, 25, p.Live().BatchSize)
+	require.Equal(t, fixedStrategy{d: 42 * time.Minute}, p.Live().Strategy)
+}
+
+type fakeArchiver struct {
+	saved map[string]string // ref -> body
+	err   error
+}
+
+func (a *fakeArchiver) Save(_ context.Context, backend string, body []byte) (string, error) {
+	if a.err != nil {
+		return "", a.err
+	}
+	ref := backend + "/" + string(body)
+	a.saved[ref] = string(body)
+	return ref, nil
+}
+
+func TestPoller_CheckNow_archivesResponse(t *testing.T) {
+	now := time.Now().UTC()
+	arch := &fakeArchiver{saved: map[string]string{}}
+	p := New(nil, fakeCarrier{res: carrier.TrackingResult{Status: "IN_TRANSIT", StatusAt: &now, Raw: []byte("ok"), HTTPStatus: 200}},
+		&fakeProducer{}, nil, "t").WithArchive(arch)
+	tr := &models.Tracking{ID: 1, CarrierCode: "CDEK", TrackNumber: "N"}
+
+	msg, err := p.CheckNow(context.Background(), tr)
+	require.NoError(t, err)
+	require.Equal(t, "/ok", msg.Check.ResponseRef)
+	require.Equal(t, map[string]string{"/ok": "ok"}, arch.saved)
+
+	// Ответ, который не разобрался, тоже сохраняется: его можно разобрать заново.
+	p.carrier = fakeCarrier{
+		res: carrier.TrackingResult{Raw: []byte("{bad"), HTTPStatus: 200},
+		err: carrier.NewError(carrier.ErrorClassDecode, 200, errors.New("decode")),
+	}
+	msg, err = p.CheckNow(context.Background(), tr)
+	require.NoError(t, err)
+	require.NotNil(t, msg.Error)
+	require.Equal(t, carrier.ErrorClassDecode, msg.Check.ErrorClass)
+	require.Equal(t, "/{bad", msg.Check.ResponseRef)
+	require.NotEmpty(t, msg.Check.ResponseHash)
+
+	// Ошибка архива проверку не ломает.
+	arch.err = errors.New("disk full")
+	msg, err = p.CheckNow(context.Background(), tr)
+	require.NoError(t, err)
+	require.Empty(t, msg.Check.ResponseRef)
+}
+
+func TestPoller_Rebuild(t *testing.T) {
+	now := time.Now().UTC()
+	checkedAt := now.Add(-time.Hour)
+	p := New(nil, fakeCarrier{}, &fakeProducer{}, nil, "t")
+	tr := &models.Tracking{ID: 1, CarrierCode: "CDEK", TrackNumber: "N", Status: models.TrackingStatusInTransit}
+
+	msg := p.Rebuild(tr, carrier.TrackingResult{
+		Status:   models.TrackingStatusDelivered,
+		StatusAt: &now,
+		Events:   []*models.TrackingEvent{{Status: models.TrackingStatusDelivered, StatusRaw: "RAW"}},
+	}, checkedAt)
+	require.Equal(t, checkedAt, msg.CheckedAt)
+	require.Equal(t, messages.KindStatusChanged, msg.Kind)
+	require.Nil(t, msg.Check)
+	require.Len(t, msg.Events, 1)
+	require.True(t, msg.Events[0].TimeInferred)
+}
+
+func TestCarrierStrategy(t *testing.T) {
+	s := CarrierStrategy{
+		Default:  fixedStrategy{d: time.Hour},
+		Carriers: map[string]Strategy{"DHL": fixedStrategy{d: time.Minute}},
+	}
+	require.Equal(t, time.Minute, s.PlanNextCheck(PlanInput{CarrierCode: "DHL"}))
+	require.Equal(t, time.Minute, s.PlanRetry(PlanInput{CarrierCode: "DHL"}))
+	require.Equal(t, time.Hour, s.PlanNextCheck(PlanInput{CarrierCode: "CDEK"}))
+}
@@ -0,0 +1,58 @@
+package poller
+
+import (
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+)
+
+// PlanInput — всё, что стратегия планирования знает о треке в момент проверки.
+type PlanInput struct {
+	CarrierCode string
+	Status      string
+	// FailCount — число неудачных проверок подряд, включая текущую.
+	FailCount int32
+	Now       time.Time
+	// Events — история, которую вернул перевозчик (пусто при ошибке).
+	Events []*models.TrackingEvent
+	// ETA — ожидаемое время доставки, если перевозчик его отдаёт.
+	ETA *time.Time
+}
+
+// Strategy решает, когда проверять трек в следующий раз.
+type Strategy interface {
+	PlanNextCheck(in PlanInput) time.Duration
+	PlanRetry(in PlanInput) time.Duration
+}
+
+// PlanNextCheck реализует Strategy только по нормализованному статусу.
+func (p *Planner) PlanNextCheck(in PlanInput) time.Duration {
+	return p.NextCheckDelay(in.Status)
+}
+
+// PlanRetry реализует Strategy по фиксированным шагам backoff.
+func (p *Planner) PlanRetry(in PlanInput) time.Duration {
+	return p.BackoffDelay(in.FailCount)
+}
+
+// CarrierStrategy отдаёт планирование стратегии перевозчика (например, политике из таблицы carriers),
+// остальные треки — стратегии Default.
+type CarrierStrategy struct {
+	Default  Strategy
+	Carriers map[string]Strategy
+}
+
+func (c CarrierStrategy) pick(carrierCode string) Strategy {
+	if s, ok := c.Carriers[carrierCode]; ok && s != nil {
+		return s
+	}
+	return c.Default
+}
+
+func (c CarrierStrategy) PlanNextCheck(in PlanInput) time.Duration {
+	return c.pick(in.CarrierCode).PlanNextCheck(in)
+}
+
+func (c CarrierStrategy) PlanRetry(in PlanInput) time.Duration {
+	return c.pick(in.CarrierCode).PlanRetry(in)
+}
@@ -0,0 +1,398 @@
+// Package sla — SLA доставки: правила по перевозчику/маршруту, периодическая оценка недоставленных
+// треков, жизненный цикл алертов (OPEN → RESOLVED) и уведомления tracking.alert в Kafka.
+package sla
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/tracknumber"
+	"github.com/pkg/errors"
+)
+
+type Repository interface {
+	// TryLockSLA — межпроцессная блокировка прохода оценки: проход идёт только в одном воркере,
+	// иначе алерты открывались бы и публиковались в tracking.alert по разу на воркер.
+	TryLockSLA(ctx context.Context) (unlock func(), ok bool, err error)
+	ListSLASubjects(ctx context.Context, afterID uint64, limit int) ([]*models.SLASubject, error)
+	OpenAlert(ctx context.Context, a models.Alert) (bool, error)
+	ResolveAlerts(ctx context.Context, trackingID uint64, kinds []string, at time.Time) error
+	ResolveDeliveredAlerts(ctx context.Context, at time.Time) (int64, error)
+	ListAlerts(ctx context.Context, f models.AlertFilter, beforeID uint64, limit int) ([]*models.Alert, error)
+	ListUnpublishedAlerts(ctx context.Context, limit int) ([]*models.Alert, error)
+	MarkAlertPublished(ctx context.Context, id uint64, state string) error
+}
+
+type Producer interface {
+	Publish(ctx context.Context, topic string, key, value []byte) error
+}
+
+// ErrInvalidArgument — запрос не прошёл проверку (текст ошибки — что именно не так).
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// Rule — правило SLA. Пустые Carrier/Origin/Destination — «любой»; нулевой лимит — проверка выключена.
+type Rule struct {
+	Name        string
+	Carrier     string
+	Origin      string
+	Destination string
+	MaxSilence  time.Duration
+	MaxTransit  time.Duration
+	MaxETADelay time.Duration
+}
+
+// RulesFromConfig — правила из блока trackbox.sla (конфиг уже провалидирован); nil для nil.
+func RulesFromConfig(c *config.SLAConfig) []Rule {
+	if c == nil {
+		return nil
+	}
+	sec := func(n int) time.Duration { return time.Duration(n) * time.Second }
+	out := make([]Rule, 0, len(c.Rules))
+	for _, r := range c.Rules {
+		out = append(out, Rule{
+			Name:        r.Name,
+			Carrier:     tracknumber.CanonicalCarrier(r.Carrier),
+			Origin:      strings.TrimSpace(r.Origin),
+			Destination: strings.TrimSpace(r.Destination),
+			MaxSilence:  sec(r.MaxSilenceSeconds),
+			MaxTransit:  sec(r.MaxTransitSeconds),
+			MaxETADelay: sec(r.MaxETADelaySeconds),
+		})
+	}
+	return out
+}
+
+func (r *Rule) specificity() int {
+	n := 0
+	for _, v := range []string{r.Carrier, r.Origin, r.Destination} {
+		if v != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *Rule) matches(t *models.Tracking) bool {
+	if r.Carrier != "" && r.Carrier != t.CarrierCode {
+		return false
+	}
+	var sd models.ShipmentDetails
+	if t.Shipment != nil {
+		sd = *t.Shipment
+	}
+	if r.Origin != "" && !strings.EqualFold(r.Origin, strings.TrimSpace(sd.Origin)) {
+		return false
+	}
+	if r.Destination != "" && !strings.EqualFold(r.Destination, strings.TrimSpace(sd.Destination)) &&
+		!strings.EqualFold(r.Destination, strings.TrimSpace(sd.RecipientCity)) {
+		return false
+	}
+	return true
+}
+
+// matchRule — самое конкретное подходящее правило; при равенстве — первое по порядку в конфиге.
+func matchRule(rules []Rule, t *models.Tracking) *Rule {
+	var best *Rule
+	for i := range rules {
+		r := &rules[i]
+		if r.matches(t) && (best == nil || r.specificity() > best.specificity()) {
+			best = r
+		}
+	}
+	return best
+}
+
+// violations — нарушения трека по правилу на момент now: вид алерта → описание.
+func violations(r *Rule, sub *models.SLASubject, now time.Time) map[string]string {
+	out := map[string]string{}
+	t := sub.Tracking
+	if r.MaxSilence > 0 {
+		since, what := t.CreatedAt, "since creation"
+		if sub.LastEventAt != nil {
+			since, what = *sub.LastEventAt, "since last event"
+		}
+		if d := now.Sub(since); d > r.MaxSilence {
+			out[models.AlertKindNoEvents] = fmt.Sprintf("no events for %s %s (limit %s)", human(d), what, human(r.MaxSilence))
+		}
+	}
+	if r.MaxTransit > 0 {
+		since := t.CreatedAt
+		if sub.FirstEventAt != nil {
+			since = *sub.FirstEventAt
+		}
+		if d := now.Sub(since); d > r.MaxTransit {
+			out[models.AlertKindTransitTime] = fmt.Sprintf("in transit for %s (limit %s)", human(d), human(r.MaxTransit))
+		}
+	}
+	if r.MaxETADelay > 0 && t.Shipment != nil && t.Shipment.EstimatedDelivery != nil {
+		eta := *t.Shipment.EstimatedDelivery
+		if d := now.Sub(eta); d > r.MaxETADelay {
+			out[models.AlertKindETAMissed] = fmt.Sprintf("estimated delivery %s missed by %s (limit %s)",
+				eta.UTC().Format(time.RFC3339), human(d), human(r.MaxETADelay))
+		}
+	}
+	return out
+}
+
+// human — длительность с точностью до часа: "5d3h", "7h", "<1h".
+func human(d time.Duration) string {
+	h := int64(d / time.Hour)
+	switch {
+	case h <= 0:
+		return "<1h"
+	case h < 24:
+		return fmt.Sprintf("%dh", h)
+	case h%24 == 0:
+		return fmt.Sprintf("%dd", h/24)
+	default:
+		return fmt.Sprintf("%dd%dh", h/24, h%24)
+	}
+}
+
+const (
+	evaluateBatch = 500
+	publishBatch  = 500
+)
+
+type Service struct {
+	repo     Repository
+	producer Producer
+	topic    string
+
+	mu    sync.RWMutex
+	rules []Rule
+}
+
+func New(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// WithPublisher включает уведомления об открытии/закрытии алертов в topic.
+func (s *Service) WithPublisher(p Producer, topic string) *Service {
+	s.producer = p
+	s.topic = topic
+	return s
+}
+
+// SetRules заменяет правила (hot reload); следующая оценка идёт уже по ним.
+func (s *Service) SetRules(rules []Rule) {
+	s.mu.Lock()
+	s.rules = slices.Clone(rules)
+	s.mu.Unlock()
+}
+
+func (s *Service) currentRules() []Rule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rules
+}
+
+// Stats — итог одного прохода Evaluate.
+type Stats struct {
+	Checked   int
+	Opened    int
+	Resolved  int
+	Published int
+	// Skipped — проход не выполнялся: его держит другой воркер.
+	Skipped bool
+}
+
+// Evaluate проверяет все недоставленные треки по правилам: открывает алерты на новые нарушения,
+// закрывает пропавшие (и все алерты доставленных треков), затем публикует неотправленные изменения.
+// Проход идёт под блокировкой репозитория; если её держит другой воркер, возвращается Stats{Skipped: true}.
+func (s *Service) Evaluate(ctx context.Context, now time.Time) (Stats, error) {
+	var st Stats
+	unlock, ok, err := s.repo.TryLockSLA(ctx)
+	if err != nil {
+		return st, err
+	}
+	if !ok {
+		st.Skipped = true
+		return st, nil
+	}
+	defer unlock()
+
+	n, err := s.repo.ResolveDeliveredAlerts(ctx, now)
+	if err != nil {
+		return st, err
+	}
+	st.Resolved += int(n)
+
+	rules := s.currentRules()
+	var after uint64
+	for {
+		subs, err := s.repo.ListSLASubjects(ctx, after, evaluateBatch)
+		if err != nil {
+			return st, err
+		}
+		for _, sub := range subs {
+			opened, resolved, err := s.evaluateOne(ctx, rules, sub, now)
+			if err != nil {
+				return st, err
+			}
+			st.Checked++
+			st.Opened += opened
+			st.Resolved += resolved
+		}
+		if len(subs) < evaluateBatch {
+			break
+		}
+		after = subs[len(subs)-1].Tracking.ID
+	}
+
+	published, err := s.publishPending(ctx)
+	st.Published = published
+	return st, err
+}
+
+func (s *Service) evaluateOne(ctx context.Context, rules []Rule, sub *models.SLASubject, now time.Time) (opened, resolved int, err error) {
+	var found map[string]string
+	rule := matchRule(rules, sub.Tracking)
+	if rule != nil {
+		found = violations(rule, sub, now)
+	}
+
+	var gone []string
+	for _, kind := range sub.OpenKinds {
+		if _, ok := found[kind]; !ok {
+			gone = append(gone, kind)
+		}
+	}
+	if err := s.repo.ResolveAlerts(ctx, sub.Tracking.ID, gone, now); err != nil {
+		return 0, 0, err
+	}
+
+	kinds := make([]string, 0, len(found))
+	for kind := range found {
+		if !slices.Contains(sub.OpenKinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	slices.Sort(kinds)
+	for _, kind := range kinds {
+		ok, err := s.repo.OpenAlert(ctx, models.Alert{
+			TrackingID:  sub.Tracking.ID,
+			CarrierCode: sub.Tracking.CarrierCode,
+			TrackNumber: sub.Tracking.TrackNumber,
+			Kind:        kind,
+			Rule:        rule.Name,
+			Details:     found[kind],
+			OpenedAt:    now,
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+		if ok {
+			opened++
+		}
+	}
+	return opened, len(gone), nil
+}
+
+// publishPending отправляет в Kafka алерты, чьё текущее состояние ещё не опубликовано.
+// Ошибка публикации прерывает проход: оставшиеся уйдут в следующий раз.
+func (s *Service) publishPending(ctx context.Context) (int, error) {
+	if s.producer == nil {
+		return 0, nil
+	}
+	published := 0
+	for {
+		pending, err := s.repo.ListUnpublishedAlerts(ctx, publishBatch)
+		if err != nil {
+			return published, err
+		}
+		for _, a := range pending {
+			b, err := json.Marshal(messages.TrackingAlertFromModel(a))
+			if err != nil {
+				return published, errors.Wrap(err, "marshal alert msg")
+			}
+			key := []byte(strconv.FormatUint(a.TrackingID, 10))
+			if err := s.producer.Publish(ctx, s.topic, key, b); err != nil {
+				return published, errors.Wrap(err, "publish alert")
+			}
+			if err := s.repo.MarkAlertPublished(ctx, a.ID, a.State()); err != nil {
+				return published, err
+			}
+			published++
+		}
+		if len(pending) < publishBatch {
+			return published, nil
+		}
+	}
+}
+
+// Run вызывает Evaluate каждые interval до отмены ctx.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			st, err := s.Evaluate(ctx, time.Now().UTC())
+			if err != nil {
+				slog.Error("sla evaluate", "error", err.Error())
+				continue
+			}
+			if st.Opened > 0 || st.Resolved > 0 || st.Published > 0 {
+				slog.Info("sla evaluated", "checked", st.Checked, "opened", st.Opened, "resolved", st.Resolved, "published", st.Published)
+			}
+		}
+	}
+}
+
+// ListAlertsPageSize — размер страницы ListAlerts по умолчанию (и максимальный — ×10).
+const ListAlertsPageSize = 100
+
+// ListAlerts отдаёт страницу алертов по фильтру от новых к старым. pageToken — из предыдущего ответа;
+// пустой nextPageToken — страниц больше нет.
+func (s *Service) ListAlerts(ctx context.Context, f models.AlertFilter, pageToken string, pageSize int) ([]*models.Alert, string, error) {
+	if pageSize <= 0 {
+		pageSize = ListAlertsPageSize
+	}
+	if pageSize > 10*ListAlertsPageSize {
+		pageSize = 10 * ListAlertsPageSize
+	}
+	var before uint64
+	if pageToken != "" {
+		v, err := strconv.ParseUint(pageToken, 10, 64)
+		if err != nil || v == 0 {
+			return nil, "", errors.Wrap(ErrInvalidArgument, "bad pageToken")
+		}
+		before = v
+	}
+	f.State = strings.ToUpper(strings.TrimSpace(f.State))
+	switch f.State {
+	case "", models.AlertStateOpen, models.AlertStateResolved:
+	default:
+		return nil, "", errors.Wrapf(ErrInvalidArgument, "unknown state %q (want OPEN|RESOLVED)", f.State)
+	}
+	f.Kind = strings.ToUpper(strings.TrimSpace(f.Kind))
+	switch f.Kind {
+	case "", models.AlertKindNoEvents, models.AlertKindTransitTime, models.AlertKindETAMissed:
+	default:
+		return nil, "", errors.Wrapf(ErrInvalidArgument, "unknown kind %q", f.Kind)
+	}
+	f.CarrierCode = tracknumber.CanonicalCarrier(f.CarrierCode)
+
+	out, err := s.repo.ListAlerts(ctx, f, before, pageSize)
+	if err != nil {
+		return nil, "", err
+	}
+	next := ""
+	if len(out) == pageSize {
+		next = strconv.FormatUint(out[len(out)-1].ID, 10)
+	}
+	return out, next, nil
+}
@@ -0,0 +1,241 @@
+package sla
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/config"
+	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo хранит алерты в памяти и повторяет семантику pgtracking (один открытый алерт вида на трек).
+type fakeRepo struct {
+	subjects  []*models.SLASubject
+	alerts    []*models.Alert
+	published map[uint64]string
+	delivered map[uint64]bool
+	locked    bool
+}
+
+func (f *fakeRepo) TryLockSLA(context.Context) (func(), bool, error) {
+	if f.locked {
+		return nil, false, nil
+	}
+	f.locked = true
+	return func() { f.locked = false }, true, nil
+}
+
+func (f *fakeRepo) ListSLASubjects(_ context.Context, afterID uint64, limit int) ([]*models.SLASubject, error) {
+	var out []*models.SLASubject
+	for _, s := range f.subjects {
+		if s.Tracking.ID <= afterID || f.delivered[s.Tracking.ID] {
+			continue
+		}
+		c := *s
+		c.OpenKinds = nil
+		for _, a := range f.alerts {
+			if a.TrackingID == s.Tracking.ID && a.ResolvedAt == nil {
+				c.OpenKinds = append(c.OpenKinds, a.Kind)
+			}
+		}
+		out = append(out, &c)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) OpenAlert(_ context.Context, a models.Alert) (bool, error) {
+	for _, x := range f.alerts {
+		if x.TrackingID == a.TrackingID && x.Kind == a.Kind && x.ResolvedAt == nil {
+			return false, nil
+		}
+	}
+	a.ID = uint64(len(f.alerts) + 1)
+	f.alerts = append(f.alerts, &a)
+	return true, nil
+}
+
+func (f *fakeRepo) ResolveAlerts(_ context.Context, trackingID uint64, kinds []string, at time.Time) error {
+	for _, a := range f.alerts {
+		for _, k := range kinds {
+			if a.TrackingID == trackingID && a.Kind == k && a.ResolvedAt == nil {
+				a.ResolvedAt = &at
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepo) ResolveDeliveredAlerts(_ context.Context, at time.Time) (int64, error) {
+	var n int64
+	for _, a := range f.alerts {
+		if f.delivered[a.TrackingID] && a.ResolvedAt == nil {
+			a.ResolvedAt = &at
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeRepo) ListAlerts(_ context.Context, fl models.AlertFilter, beforeID uint64, limit int) ([]*models.Alert, error) {
+	var out []*models.Alert
+	for i := len(f.alerts) - 1; i >= 0 && len(out) < limit; i-- {
+		a := f.alerts[i]
+		if (beforeID == 0 || a.ID < beforeID) && (fl.State == "" || a.State() == fl.State) {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) ListUnpublishedAlerts(_ context.Context, limit int) ([]*models.Alert, error) {
+	var out []*models.Alert
+	for _, a := range f.alerts {
+		if f.published[a.ID] != a.State() && len(out) < limit {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) MarkAlertPublished(_ context.Context, id uint64, state string) error {
+	f.published[id] = state
+	return nil
+}
+
+type fakeProducer struct {
+	msgs []messages.TrackingAlert
+}
+
+func (p *fakeProducer) Publish(_ context.Context, topic string, key, value []byte) error {
+	var m messages.TrackingAlert
+	if err := json.Unmarshal(value, &m); err != nil {
+		return err
+	}
+	p.msgs = append(p.msgs, m)
+	return nil
+}
+
+func TestMatchRule(t *testing.T) {
+	rules := RulesFromConfig(&config.SLAConfig{Rules: []config.SLARuleConfig{
+		{Name: "default", MaxSilenceSeconds: 100},
+		{Name: "post", Carrier: "post-ru", MaxSilenceSeconds: 200},
+		{Name: "post-kzn", Carrier: "POST_RU", Destination: "Казань", MaxSilenceSeconds: 300},
+	}})
+	require.Equal(t, "POST_RU", rules[1].Carrier)
+
+	tr := &models.Tracking{CarrierCode: "CDEK"}
+	require.Equal(t, "default", matchRule(rules, tr).Name)
+	tr.CarrierCode = "POST_RU"
+	require.Equal(t, "post", matchRule(rules, tr).Name)
+	tr.Shipment = &models.ShipmentDetails{RecipientCity: "казань"}
+	require.Equal(t, "post-kzn", matchRule(rules, tr).Name)
+	require.Nil(t, matchRule(rules[1:], &models.Tracking{CarrierCode: "CDEK"}))
+}
+
+func TestEvaluate_Lifecycle(t *testing.T) {
+	t0 := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(h int) *time.Time { v := t0.Add(time.Duration(h) * time.Hour); return &v }
+	stuck := &models.SLASubject{
+		Tracking:     &models.Tracking{ID: 1, CarrierCode: "CDEK", TrackNumber: "A1", CreatedAt: t0},
+		FirstEventAt: at(0),
+		LastEventAt:  at(1),
+	}
+	late := &models.SLASubject{
+		Tracking: &models.Tracking{ID: 2, CarrierCode: "CDEK", TrackNumber: "B2", CreatedAt: t0,
+			Shipment: &models.ShipmentDetails{EstimatedDelivery: at(10)}},
+		FirstEventAt: at(0),
+		LastEventAt:  at(70),
+	}
+	repo := &fakeRepo{subjects: []*models.SLASubject{stuck, late}, published: map[uint64]string{}, delivered: map[uint64]bool{}}
+	prod := &fakeProducer{}
+	s := New(repo).WithPublisher(prod, "tracking.alert")
+	s.SetRules([]Rule{{Name: "default", MaxSilence: 48 * time.Hour, MaxTransit: 30 * 24 * time.Hour, MaxETADelay: 24 * time.Hour}})
+
+	st, err := s.Evaluate(context.Background(), *at(72))
+	require.NoError(t, err)
+	require.Equal(t, Stats{Checked: 2, Opened: 2, Published: 2}, st)
+	require.Equal(t, models.AlertKindNoEvents, repo.alerts[0].Kind)
+	require.Equal(t, "no events for 2d23h since last event (limit 2d)", repo.alerts[0].Details)
+	require.Equal(t, models.AlertKindETAMissed, repo.alerts[1].Kind)
+	require.Equal(t, "OPEN", prod.msgs[0].State)
+	require.Equal(t, "A1", prod.msgs[0].TrackNumber)
+
+	// Повторная оценка ничего не дублирует.
+	st, err = s.Evaluate(context.Background(), *at(73))
+	require.NoError(t, err)
+	require.Equal(t, Stats{Checked: 2}, st)
+
+	// Пришло событие — NO_EVENTS закрывается; трек 2 доставлен — его алерт тоже.
+	stuck.LastEventAt = at(73)
+	repo.delivered[2] = true
+	st, err = s.Evaluate(context.Background(), *at(74))
+	require.NoError(t, err)
+	require.Equal(t, Stats{Checked: 1, Resolved: 2, Published: 2}, st)
+	require.Len(t, prod.msgs, 4)
+	require.Equal(t, "RESOLVED", prod.msgs[2].State)
+	require.NotNil(t, prod.msgs[2].ResolvedAt)
+
+	// Нарушение вернулось — открывается новый алерт, старый остаётся закрытым.
+	_, err = s.Evaluate(context.Background(), *at(200))
+	require.NoError(t, err)
+	require.Len(t, repo.alerts, 3)
+	require.Equal(t, models.AlertStateResolved, repo.alerts[0].State())
+	require.Equal(t, models.AlertStateOpen, repo.alerts[2].State())
+}
+
+func TestEvaluate_SkipsWhenLockedByOtherWorker(t *testing.T) {
+	t0 := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	stuck := &models.SLASubject{Tracking: &models.Tracking{ID: 1, CarrierCode: "CDEK", TrackNumber: "A1", CreatedAt: t0}}
+	repo := &fakeRepo{subjects: []*models.SLASubject{stuck}, published: map[uint64]string{}, delivered: map[uint64]bool{}}
+	prod := &fakeProducer{}
+	s := New(repo).WithPublisher(prod, "tracking.alert")
+	s.SetRules([]Rule{{Name: "default", MaxSilence: time.Hour}})
+
+	// Проход держит другой воркер — ни алертов, ни сообщений.
+	repo.locked = true
+	st, err := s.Evaluate(context.Background(), t0.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, Stats{Skipped: true}, st)
+	require.Empty(t, repo.alerts)
+	require.Empty(t, prod.msgs)
+
+	// Блокировка освободилась — проход идёт и снимает её за собой.
+	repo.locked = false
+	st, err = s.Evaluate(context.Background(), t0.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, Stats{Checked: 1, Opened: 1, Published: 1}, st)
+	require.False(t, repo.locked)
+}
+
+func TestListAlerts(t *testing.T) {
+	repo := &fakeRepo{published: map[uint64]string{}}
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		_, _ = repo.OpenAlert(context.Background(), models.Alert{TrackingID: uint64(i + 1), Kind: models.AlertKindNoEvents})
+	}
+	_ = repo.ResolveAlerts(context.Background(), 2, []string{models.AlertKindNoEvents}, now)
+	s := New(repo)
+
+	out, next, err := s.ListAlerts(context.Background(), models.AlertFilter{State: "open"}, "", 1)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, uint64(3), out[0].ID)
+	require.Equal(t, "3", next)
+
+	out, _, err = s.ListAlerts(context.Background(), models.AlertFilter{State: "OPEN"}, next, 10)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, uint64(1), out[0].ID)
+
+	_, _, err = s.ListAlerts(context.Background(), models.AlertFilter{State: "closed"}, "", 0)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+	_, _, err = s.ListAlerts(context.Background(), models.AlertFilter{}, "x", 0)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
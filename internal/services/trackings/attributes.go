@@ -0,0 +1,83 @@
+package trackings
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Ограничения пользовательских атрибутов трека.
+const (
+	MaxMetadataBytes = 16 << 10
+	MaxTags          = 32
+	MaxTagLen        = 64
+	MaxExternalIDLen = 128
+)
+
+// NormalizeMetadata проверяет, что metadata — JSON-объект не больше MaxMetadataBytes, и сжимает его.
+// nil и пустая строка — метаданных нет (nil).
+func NormalizeMetadata(metadata *string) (*string, error) {
+	if metadata == nil || strings.TrimSpace(*metadata) == "" {
+		return nil, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(*metadata), &obj); err != nil || obj == nil {
+		return nil, errors.New("metadata must be a JSON object")
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(*metadata)); err != nil {
+		return nil, errors.Wrap(err, "metadata")
+	}
+	if buf.Len() > MaxMetadataBytes {
+		return nil, errors.Errorf("metadata is too large (max %d bytes)", MaxMetadataBytes)
+	}
+	out := buf.String()
+	return &out, nil
+}
+
+// NormalizeTags убирает пробелы по краям и повторы, сохраняя порядок.
+// Тег не может быть пустым или содержать ';' (разделитель тегов в CSV).
+func NormalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		switch {
+		case t == "":
+			return nil, errors.New("tag must not be empty")
+		case len(t) > MaxTagLen:
+			return nil, errors.Errorf("tag %q is too long (max %d)", t, MaxTagLen)
+		case strings.ContainsRune(t, ';'):
+			return nil, errors.Errorf("tag %q must not contain ';'", t)
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+	if len(out) > MaxTags {
+		return nil, errors.Errorf("too many tags (max %d)", MaxTags)
+	}
+	return out, nil
+}
+
+// NormalizeExternalID убирает пробелы по краям; пустой id — nil.
+func NormalizeExternalID(id *string) (*string, error) {
+	if id == nil {
+		return nil, nil
+	}
+	v := strings.TrimSpace(*id)
+	if v == "" {
+		return nil, nil
+	}
+	if len(v) > MaxExternalIDLen {
+		return nil, errors.Errorf("externalId is too long (max %d)", MaxExternalIDLen)
+	}
+	return &v, nil
+}
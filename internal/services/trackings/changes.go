@@ -0,0 +1,77 @@
+package trackings
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"github.com/pkg/errors"
+)
+
+// ListChangesPageSize — размер страницы ленты изменений по умолчанию (и максимальный — ×10).
+const ListChangesPageSize = 500
+
+// ChangesPage — страница ленты изменений.
+type ChangesPage struct {
+	Changes []*models.Change
+	// NextCursor — курсор для следующего запроса; без новых изменений — тот же, что в запросе.
+	NextCursor string
+	// HasMore — изменения после NextCursor уже есть, можно запрашивать сразу.
+	HasMore bool
+}
+
+// ListChanges отдаёт изменения треков и событий и удалённые треки после курсора, в порядке их фиксации,
+// чтобы внешние системы могли держать копию данных без доступа к Kafka. Пустой курсор — с начала: сначала
+// идёт всё, что было до появления ленты. Запись отдаёт текущее состояние трека или события, поэтому
+// повторная выдача (например, после сбоя на стороне потребителя) безопасна.
+func (s *Service) ListChanges(ctx context.Context, cursor string, limit int) (*ChangesPage, error) {
+	after, err := parseChangeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 0 {
+		return nil, errors.Wrap(ErrInvalidArgument, "limit must be >= 0")
+	}
+	if limit == 0 {
+		limit = ListChangesPageSize
+	}
+	if limit > 10*ListChangesPageSize {
+		limit = 10 * ListChangesPageSize
+	}
+
+	changes, err := s.repo.ListChanges(ctx, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &ChangesPage{NextCursor: cursor}
+	if len(changes) > limit {
+		changes = changes[:limit]
+		page.HasMore = true
+	}
+	if n := len(changes); n > 0 {
+		last := changes[n-1]
+		page.NextCursor = strconv.FormatInt(last.Seq, 10) + "." + strconv.Itoa(last.Kind) + "." + strconv.FormatUint(last.ID(), 10)
+	}
+	page.Changes = changes
+	return page, nil
+}
+
+// parseChangeCursor разбирает курсор "<change_seq>.<вид>.<id>"; пустой — с начала ленты.
+func parseChangeCursor(cursor string) (pgtracking.ChangeCursor, error) {
+	if cursor == "" {
+		return pgtracking.ChangeCursor{}, nil
+	}
+	parts := strings.Split(cursor, ".")
+	if len(parts) != 3 {
+		return pgtracking.ChangeCursor{}, errors.Wrap(ErrInvalidArgument, "bad cursor")
+	}
+	seq, err1 := strconv.ParseInt(parts[0], 10, 64)
+	kind, err2 := strconv.Atoi(parts[1])
+	id, err3 := strconv.ParseUint(parts[2], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil || seq < 0 || kind < models.ChangeTracking || kind > models.ChangeDeleted {
+		return pgtracking.ChangeCursor{}, errors.Wrap(ErrInvalidArgument, "bad cursor")
+	}
+	return pgtracking.ChangeCursor{Seq: seq, Kind: kind, ID: id}, nil
+}
@@ -0,0 +1,408 @@
+package trackings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/cache"
+	"github.com/BearBump/TrackBox/internal/models"
+)
+
+// Кэш текущего состояния треков (read-through): GetTrackingsByIDs читает все ключи одним MGET, промахи
+// догружает из БД одним запросом и записывает одним пайплайном. Одинаковые одновременные промахи
+// (несколько запросов одного и того же трека) склеиваются через singleflight — в БД идёт один запрос.
+// Про id, которых в БД нет, кэш тоже помнит (negative TTL), чтобы перебор несуществующих id не доходил до БД.
+//
+// Значение — [версия формата][вид записи][тело]. Тело — JSON отдельной структуры cachedTracking с явными
+// ключами, а не models.Tracking: переименование полей модели не ломает уже лежащие в Redis записи. При
+// несовместимой смене формата поднимаем currentCacheVersion — записи старой версии считаются промахом и
+// перезаписываются (записи до версионирования — голый JSON, начинаются с '{' и тоже не совпадут).
+//
+// Перед Redis может стоять кэш в памяти каждой реплики (tiered.Cache). Тогда об изменённых треках
+// (создание, изменение, refresh, удаление, обновление из Kafka) остальным репликам сообщает Invalidator;
+// дозагрузка промахов в кэш изменением не считается и ничего не публикует.
+//
+// Дозагрузка не должна затереть значение, записанное, пока она читала БД (например, ApplyKafkaUpdate): такие
+// ключи отмечает loadGuard, и дозагрузка их не пишет, а если изменение пришлось уже на её запись — удаляет.
+// Изменения с других реплик guard узнаёт из той же подписки, что сбрасывает кэш в памяти (CacheChanged).
+
+// DefaultNegativeTTL — сколько кэш помнит об отсутствующем треке, если не задано WithNegativeTTL.
+const DefaultNegativeTTL = 30 * time.Second
+
+const (
+	currentCacheVersion byte = 1
+
+	cachedFound   byte = 't'
+	cachedMissing byte = '-'
+)
+
+func currentKey(id uint64) string {
+	return fmt.Sprintf("tracking:%d:current", id)
+}
+
+// Invalidator сообщает другим репликам, что ключи кэша изменились (rediscache.Invalidations).
+type Invalidator interface {
+	Publish(ctx context.Context, keys ...string) error
+}
+
+// WithInvalidator включает рассылку изменений кэша другим репликам; нужен, если у реплик есть кэш в памяти.
+func (s *Service) WithInvalidator(inv Invalidator) *Service {
+	s.invalidator = inv
+	return s
+}
+
+// WithNegativeTTL задаёт, сколько помнить об отсутствующих треках; 0 — не кэшировать отсутствие.
+func (s *Service) WithNegativeTTL(ttl time.Duration) *Service {
+	s.negativeTTL = ttl
+	return s
+}
+
+func (s *Service) cacheEnabled() bool {
+	return s.cache != nil && s.currentTTL > 0
+}
+
+// readCurrent — попадания кэша в got; возвращает id, которых в кэше нет (без повторов).
+// Отсутствующие треки (отрицательные записи) не попадают ни в got, ни в промахи.
+func (s *Service) readCurrent(ctx context.Context, ids []uint64, got map[uint64]*models.Tracking) []uint64 {
+	ids = uniqueIDs(ids)
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = currentKey(id)
+	}
+	vals, err := s.cache.MGet(ctx, keys)
+	if err != nil || len(vals) != len(ids) {
+		// кэш — лучшее усилие: недоступен — идём в БД за всем
+		return ids
+	}
+	miss := make([]uint64, 0, len(ids))
+	for i, id := range ids {
+		t, found, ok := decodeCurrent(vals[i])
+		switch {
+		case !ok:
+			miss = append(miss, id)
+		case found:
+			got[id] = t
+		}
+	}
+	return miss
+}
+
+// loadCurrent читает промахи из БД и кладёт их в кэш; одинаковые одновременные загрузки склеиваются.
+// Треки в результате общие для всех склеенных вызовов — их нельзя менять.
+func (s *Service) loadCurrent(ctx context.Context, ids []uint64) (map[uint64]*models.Tracking, error) {
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	var key strings.Builder
+	for i, id := range sorted {
+		if i > 0 {
+			key.WriteByte(',')
+		}
+		key.WriteString(strconv.FormatUint(id, 10))
+	}
+
+	// Загрузка не должна обрываться из-за отмены запроса, который её начал: результата ждут и другие.
+	// Каждый вызов при этом ждёт не дольше своего ctx.
+	loadCtx := context.WithoutCancel(ctx)
+	ch := s.loads.DoChan(key.String(), func() (any, error) {
+		keys := make([]string, len(sorted))
+		for i, id := range sorted {
+			keys[i] = currentKey(id)
+		}
+		snap := s.inflight.begin(keys)
+		defer s.inflight.end(keys)
+
+		ts, err := s.repo.GetTrackingsByIDs(loadCtx, sorted)
+		if err != nil {
+			return nil, err
+		}
+		got := make(map[uint64]*models.Tracking, len(ts))
+		for _, t := range ts {
+			got[t.ID] = t
+		}
+		changed := s.inflight.changed(keys, snap)
+		items := make([]cache.Item, 0, len(sorted))
+		for i, id := range sorted {
+			if changed[i] {
+				continue
+			}
+			if t, ok := got[id]; ok {
+				items = append(items, cache.Item{Key: keys[i], Value: encodeCurrent(t), TTL: s.currentTTL})
+			} else if s.negativeTTL > 0 {
+				items = append(items, cache.Item{Key: keys[i], Value: encodeMissing(), TTL: s.negativeTTL})
+			}
+		}
+		if len(items) == 0 {
+			return got, nil
+		}
+		_ = s.cache.MSet(loadCtx, items)
+
+		// изменение между проверкой и записью: прочитанное могло лечь поверх него — пусть читают из БД
+		var stale []string
+		for i, moved := range s.inflight.changed(keys, snap) {
+			if moved && !changed[i] {
+				stale = append(stale, keys[i])
+			}
+		}
+		if len(stale) > 0 {
+			_ = s.cache.Delete(loadCtx, stale...)
+			s.publishChanged(loadCtx, stale)
+		}
+		return got, nil
+	})
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(map[uint64]*models.Tracking), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// storeCurrent записывает актуальное состояние треков в кэш (заодно затирая отрицательные записи).
+func (s *Service) storeCurrent(ctx context.Context, ts ...*models.Tracking) {
+	if !s.cacheEnabled() || len(ts) == 0 {
+		return
+	}
+	items := make([]cache.Item, 0, len(ts))
+	keys := make([]string, 0, len(ts))
+	for _, t := range ts {
+		if t != nil {
+			items = append(items, cache.Item{Key: currentKey(t.ID), Value: encodeCurrent(t), TTL: s.currentTTL})
+			keys = append(keys, currentKey(t.ID))
+		}
+	}
+	s.inflight.touch(keys...)
+	_ = s.cache.MSet(ctx, items)
+	s.publishChanged(ctx, keys)
+}
+
+// dropCurrent убирает треки из кэша; следующее чтение возьмёт их из БД.
+func (s *Service) dropCurrent(ctx context.Context, ids ...uint64) {
+	if s.cache == nil || len(ids) == 0 {
+		return
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = currentKey(id)
+	}
+	s.inflight.touch(keys...)
+	_ = s.cache.Delete(ctx, keys...)
+	s.publishChanged(ctx, keys)
+}
+
+// CacheChanged сообщает, что ключи кэша изменились на другой реплике (подписка на Invalidator); без ключей —
+// неизвестно какие (подписка оборвалась). Начатые до этого дозагрузки не запишут эти ключи.
+func (s *Service) CacheChanged(keys ...string) {
+	if len(keys) == 0 {
+		s.inflight.touchAll()
+		return
+	}
+	s.inflight.touch(keys...)
+}
+
+// publishChanged — лучшее усилие, как и сам кэш: потерянное сообщение ограничено TTL кэша в памяти.
+func (s *Service) publishChanged(ctx context.Context, keys []string) {
+	if s.invalidator == nil || len(keys) == 0 {
+		return
+	}
+	if err := s.invalidator.Publish(ctx, keys...); err != nil {
+		slog.Warn("cache invalidation publish failed", "keys", len(keys), "error", err.Error())
+	}
+}
+
+type cachedShipment struct {
+	EstimatedDelivery *time.Time `json:"eta,omitempty"`
+	WeightGrams       int32      `json:"weight_grams,omitempty"`
+	Origin            string     `json:"origin,omitempty"`
+	Destination       string     `json:"destination,omitempty"`
+	RecipientCity     string     `json:"recipient_city,omitempty"`
+	ServiceType       string     `json:"service_type,omitempty"`
+}
+
+type cachedTracking struct {
+	ID             uint64          `json:"id"`
+	CarrierCode    string          `json:"carrier"`
+	TrackNumber    string          `json:"track"`
+	Status         string          `json:"status"`
+	StatusRaw      string          `json:"status_raw,omitempty"`
+	StatusAt       *time.Time      `json:"status_at,omitempty"`
+	LastCheckedAt  *time.Time      `json:"last_checked_at,omitempty"`
+	NextCheckAt    time.Time       `json:"next_check_at"`
+	CheckFailCount int32           `json:"check_fail_count,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	Metadata       *string         `json:"metadata,omitempty"`
+	Tags           []string        `json:"tags,omitempty"`
+	ExternalID     *string         `json:"external_id,omitempty"`
+	Shipment       *cachedShipment `json:"shipment,omitempty"`
+	Fingerprint    string          `json:"fingerprint,omitempty"`
+}
+
+func encodeCurrent(t *models.Tracking) []byte {
+	c := cachedTracking{
+		ID:             t.ID,
+		CarrierCode:    t.CarrierCode,
+		TrackNumber:    t.TrackNumber,
+		Status:         t.Status,
+		StatusRaw:      t.StatusRaw,
+		StatusAt:       t.StatusAt,
+		LastCheckedAt:  t.LastCheckedAt,
+		NextCheckAt:    t.NextCheckAt,
+		CheckFailCount: t.CheckFailCount,
+		LastError:      t.LastError,
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+		Metadata:       t.Metadata,
+		Tags:           t.Tags,
+		ExternalID:     t.ExternalID,
+		Fingerprint:    t.Fingerprint,
+	}
+	if d := t.Shipment; d != nil {
+		c.Shipment = &cachedShipment{
+			EstimatedDelivery: d.EstimatedDelivery,
+			WeightGrams:       d.WeightGrams,
+			Origin:            d.Origin,
+			Destination:       d.Destination,
+			RecipientCity:     d.RecipientCity,
+			ServiceType:       d.ServiceType,
+		}
+	}
+	b, _ := json.Marshal(c)
+	return append([]byte{currentCacheVersion, cachedFound}, b...)
+}
+
+func encodeMissing() []byte {
+	return []byte{currentCacheVersion, cachedMissing}
+}
+
+// decodeCurrent разбирает значение кэша: ok=false — промах (нет ключа, чужая версия, битая запись),
+// found=false — трека нет в БД.
+func decodeCurrent(b []byte) (t *models.Tracking, found, ok bool) {
+	if len(b) < 2 || b[0] != currentCacheVersion {
+		return nil, false, false
+	}
+	switch b[1] {
+	case cachedMissing:
+		return nil, false, true
+	case cachedFound:
+	default:
+		return nil, false, false
+	}
+	var c cachedTracking
+	if json.Unmarshal(b[2:], &c) != nil {
+		return nil, false, false
+	}
+	t = &models.Tracking{
+		ID:             c.ID,
+		CarrierCode:    c.CarrierCode,
+		TrackNumber:    c.TrackNumber,
+		Status:         c.Status,
+		StatusRaw:      c.StatusRaw,
+		StatusAt:       c.StatusAt,
+		LastCheckedAt:  c.LastCheckedAt,
+		NextCheckAt:    c.NextCheckAt,
+		CheckFailCount: c.CheckFailCount,
+		LastError:      c.LastError,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+		Metadata:       c.Metadata,
+		Tags:           c.Tags,
+		ExternalID:     c.ExternalID,
+		Fingerprint:    c.Fingerprint,
+	}
+	if d := c.Shipment; d != nil {
+		t.Shipment = &models.ShipmentDetails{
+			EstimatedDelivery: d.EstimatedDelivery,
+			WeightGrams:       d.WeightGrams,
+			Origin:            d.Origin,
+			Destination:       d.Destination,
+			RecipientCity:     d.RecipientCity,
+			ServiceType:       d.ServiceType,
+		}
+	}
+	return t, true, true
+}
+
+// loadGuard отмечает изменения ключей, дозагрузка которых из БД сейчас в пути. Запись об изменении
+// делается до записи в кэш, поэтому дозагрузка, проверившая ключ после своей записи, изменение не пропустит.
+type loadGuard struct {
+	mu   sync.Mutex
+	keys map[string]*loadingKey
+}
+
+type loadingKey struct {
+	loads int    // сколько дозагрузок держат ключ
+	gen   uint64 // растёт при каждом изменении ключа
+}
+
+// begin регистрирует ключи дозагрузки и возвращает их поколения.
+func (g *loadGuard) begin(keys []string) []uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.keys == nil {
+		g.keys = make(map[string]*loadingKey)
+	}
+	snap := make([]uint64, len(keys))
+	for i, k := range keys {
+		lk := g.keys[k]
+		if lk == nil {
+			lk = &loadingKey{}
+			g.keys[k] = lk
+		}
+		lk.loads++
+		snap[i] = lk.gen
+	}
+	return snap
+}
+
+// changed — какие из ключей изменились с момента begin.
+func (g *loadGuard) changed(keys []string, snap []uint64) []bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	out := make([]bool, len(keys))
+	for i, k := range keys {
+		out[i] = g.keys[k].gen != snap[i]
+	}
+	return out
+}
+
+func (g *loadGuard) end(keys []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, k := range keys {
+		lk := g.keys[k]
+		lk.loads--
+		if lk.loads == 0 {
+			delete(g.keys, k)
+		}
+	}
+}
+
+// touch отмечает изменение ключей; ключи, которые никто не дозагружает, не хранятся.
+func (g *loadGuard) touch(keys ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, k := range keys {
+		if lk := g.keys[k]; lk != nil {
+			lk.gen++
+		}
+	}
+}
+
+func (g *loadGuard) touchAll() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, lk := range g.keys {
+		lk.gen++
+	}
+}
@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	pgtracking "github.com/BearBump/TrackBox/internal/storage/pgtracking"
+
+	time "time"
 )
 
 // MockRepository is an autogenerated mock type for the Repository type
@@ -71,6 +73,123 @@ func (_c *MockRepository_ApplyTrackingUpdate_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// BulkCreateTrackings provides a mock function with given fields: ctx, items
+func (_m *MockRepository) BulkCreateTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]pgtracking.CreateResult, error) {
+	ret := _m.Called(ctx, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkCreateTrackings")
+	}
+
+	var r0 []pgtracking.CreateResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.TrackingCreateInput) ([]pgtracking.CreateResult, error)); ok {
+		return rf(ctx, items)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.TrackingCreateInput) []pgtracking.CreateResult); ok {
+		r0 = rf(ctx, items)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pgtracking.CreateResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.TrackingCreateInput) error); ok {
+		r1 = rf(ctx, items)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRepository_BulkCreateTrackings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BulkCreateTrackings'
+type MockRepository_BulkCreateTrackings_Call struct {
+	*mock.Call
+}
+
+// BulkCreateTrackings is a helper method to define mock.On call
+//   - ctx context.Context
+//   - items []models.TrackingCreateInput
+func (_e *MockRepository_Expecter) BulkCreateTrackings(ctx interface{}, items interface{}) *MockRepository_BulkCreateTrackings_Call {
+	return &MockRepository_BulkCreateTrackings_Call{Call: _e.mock.On("BulkCreateTrackings", ctx, items)}
+}
+
+func (_c *MockRepository_BulkCreateTrackings_Call) Run(run func(ctx context.Context, items []models.TrackingCreateInput)) *MockRepository_BulkCreateTrackings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.TrackingCreateInput))
+	})
+	return _c
+}
+
+func (_c *MockRepository_BulkCreateTrackings_Call) Return(_a0 []pgtracking.CreateResult, _a1 error) *MockRepository_BulkCreateTrackings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_BulkCreateTrackings_Call) RunAndReturn(run func(context.Context, []models.TrackingCreateInput) ([]pgtracking.CreateResult, error)) *MockRepository_BulkCreateTrackings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountTrackingEvents provides a mock function with given fields: ctx, trackingID, f
+func (_m *MockRepository) CountTrackingEvents(ctx context.Context, trackingID uint64, f pgtracking.EventFilter) (int64, error) {
+	ret := _m.Called(ctx, trackingID, f)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountTrackingEvents")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgtracking.EventFilter) (int64, error)); ok {
+		return rf(ctx, trackingID, f)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgtracking.EventFilter) int64); ok {
+		r0 = rf(ctx, trackingID, f)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgtracking.EventFilter) error); ok {
+		r1 = rf(ctx, trackingID, f)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRepository_CountTrackingEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountTrackingEvents'
+type MockRepository_CountTrackingEvents_Call struct {
+	*mock.Call
+}
+
+// CountTrackingEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uint64
+//   - f pgtracking.EventFilter
+func (_e *MockRepository_Expecter) CountTrackingEvents(ctx interface{}, trackingID interface{}, f interface{}) *MockRepository_CountTrackingEvents_Call {
+	return &MockRepository_CountTrackingEvents_Call{Call: _e.mock.On("CountTrackingEvents", ctx, trackingID, f)}
+}
+
+func (_c *MockRepository_CountTrackingEvents_Call) Run(run func(ctx context.Context, trackingID uint64, f pgtracking.EventFilter)) *MockRepository_CountTrackingEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(pgtracking.EventFilter))
+	})
+	return _c
+}
+
+func (_c *MockRepository_CountTrackingEvents_Call) Return(_a0 int64, _a1 error) *MockRepository_CountTrackingEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_CountTrackingEvents_Call) RunAndReturn(run func(context.Context, uint64, pgtracking.EventFilter) (int64, error)) *MockRepository_CountTrackingEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateOrGetTrackings provides a mock function with given fields: ctx, items
 func (_m *MockRepository) CreateOrGetTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]*models.Tracking, error) {
 	ret := _m.Called(ctx, items)
@@ -130,6 +249,53 @@ func (_c *MockRepository_CreateOrGetTrackings_Call) RunAndReturn(run func(contex
 	return _c
 }
 
+// DeleteTracking provides a mock function with given fields: ctx, id
+func (_m *MockRepository) DeleteTracking(ctx context.Context, id uint64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTracking")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRepository_DeleteTracking_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTracking'
+type MockRepository_DeleteTracking_Call struct {
+	*mock.Call
+}
+
+// DeleteTracking is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint64
+func (_e *MockRepository_Expecter) DeleteTracking(ctx interface{}, id interface{}) *MockRepository_DeleteTracking_Call {
+	return &MockRepository_DeleteTracking_Call{Call: _e.mock.On("DeleteTracking", ctx, id)}
+}
+
+func (_c *MockRepository_DeleteTracking_Call) Run(run func(ctx context.Context, id uint64)) *MockRepository_DeleteTracking_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64))
+	})
+	return _c
+}
+
+func (_c *MockRepository_DeleteTracking_Call) Return(_a0 error) *MockRepository_DeleteTracking_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRepository_DeleteTracking_Call) RunAndReturn(run func(context.Context, uint64) error) *MockRepository_DeleteTracking_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTrackingsByIDs provides a mock function with given fields: ctx, ids
 func (_m *MockRepository) GetTrackingsByIDs(ctx context.Context, ids []uint64) ([]*models.Tracking, error) {
 	ret := _m.Called(ctx, ids)
@@ -189,9 +355,371 @@ func (_c *MockRepository_GetTrackingsByIDs_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
-// ListTrackingEvents provides a mock function with given fields: ctx, trackingID, limit, offset
-func (_m *MockRepository) ListTrackingEvents(ctx context.Context, trackingID uint64, limit int, offset int) ([]*models.TrackingEvent, error) {
-	ret := _m.Called(ctx, trackingID, limit, offset)
+// ListChanges provides a mock function with given fields: ctx, after, limit
+func (_m *MockRepository) ListChanges(ctx context.Context, after pgtracking.ChangeCursor, limit int) ([]*models.Change, error) {
+	ret := _m.Called(ctx, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListChanges")
+	}
+
+	var r0 []*models.Change
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgtracking.ChangeCursor, int) ([]*models.Change, error)); ok {
+		return rf(ctx, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgtracking.ChangeCursor, int) []*models.Change); ok {
+		r0 = rf(ctx, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Change)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgtracking.ChangeCursor, int) error); ok {
+		r1 = rf(ctx, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRepository_ListChanges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListChanges'
+type MockRepository_ListChanges_Call struct {
+	*mock.Call
+}
+
+// ListChanges is a helper method to define mock.On call
+//   - ctx context.Context
+//   - after pgtracking.ChangeCursor
+//   - limit int
+func (_e *MockRepository_Expecter) ListChanges(ctx interface{}, after interface{}, limit interface{}) *MockRepository_ListChanges_Call {
+	return &MockRepository_ListChanges_Call{Call: _e.mock.On("ListChanges", ctx, after, limit)}
+}
+
+func (_c *MockRepository_ListChanges_Call) Run(run func(ctx context.Context, after pgtracking.ChangeCursor, limit int)) *MockRepository_ListChanges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(pgtracking.ChangeCursor), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockRepository_ListChanges_Call) Return(_a0 []*models.Change, _a1 error) *MockRepository_ListChanges_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_ListChanges_Call) RunAndReturn(run func(context.Context, pgtracking.ChangeCursor, int) ([]*models.Change, error)) *MockRepository_ListChanges_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListETAHistory provides a mock function with given fields: ctx, trackingID, limit
+func (_m *MockRepository) ListETAHistory(ctx context.Context, trackingID uint64, limit int) ([]*models.ETAChange, error) {
+	ret := _m.Called(ctx, trackingID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListETAHistory")
+	}
+
+	var r0 []*models.ETAChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int) ([]*models.ETAChange, error)); ok {
+		return rf(ctx, trackingID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int) []*models.ETAChange); ok {
+		r0 = rf(ctx, trackingID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ETAChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, int) error); ok {
+		r1 = rf(ctx, trackingID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRepository_ListETAHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListETAHistory'
+type MockRepository_ListETAHistory_Call struct {
+	*mock.Call
+}
+
+// ListETAHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uint64
+//   - limit int
+func (_e *MockRepository_Expecter) ListETAHistory(ctx interface{}, trackingID interface{}, limit interface{}) *MockRepository_ListETAHistory_Call {
+	return &MockRepository_ListETAHistory_Call{Call: _e.mock.On("ListETAHistory", ctx, trackingID, limit)}
+}
+
+func (_c *MockRepository_ListETAHistory_Call) Run(run func(ctx context.Context, trackingID uint64, limit int)) *MockRepository_ListETAHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockRepository_ListETAHistory_Call) Return(_a0 []*models.ETAChange, _a1 error) *MockRepository_ListETAHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_ListETAHistory_Call) RunAndReturn(run func(context.Context, uint64, int) ([]*models.ETAChange, error)) *MockRepository_ListETAHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListEventKeys provides a mock function with given fields: ctx, trackingID
+func (_m *MockRepository) ListEventKeys(ctx context.Context, trackingID uint64) ([]string, error) {
+	ret := _m.Called(ctx, trackingID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEventKeys")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]string, error)); ok {
+		return rf(ctx, trackingID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []string); ok {
+		r0 = rf(ctx, trackingID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, trackingID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRepository_ListEventKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListEventKeys'
+type MockRepository_ListEventKeys_Call struct {
+	*mock.Call
+}
+
+// ListEventKeys is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uint64
+func (_e *MockRepository_Expecter) ListEventKeys(ctx interface{}, trackingID interface{}) *MockRepository_ListEventKeys_Call {
+	return &MockRepository_ListEventKeys_Call{Call: _e.mock.On("ListEventKeys", ctx, trackingID)}
+}
+
+func (_c *MockRepository_ListEventKeys_Call) Run(run func(ctx context.Context, trackingID uint64)) *MockRepository_ListEventKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64))
+	})
+	return _c
+}
+
+func (_c *MockRepository_ListEventKeys_Call) Return(_a0 []string, _a1 error) *MockRepository_ListEventKeys_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_ListEventKeys_Call) RunAndReturn(run func(context.Context, uint64) ([]string, error)) *MockRepository_ListEventKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListEventRevisions provides a mock function with given fields: ctx, trackingID, limit
+func (_m *MockRepository) ListEventRevisions(ctx context.Context, trackingID uint64, limit int) ([]*models.EventRevision, error) {
+	ret := _m.Called(ctx, trackingID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEventRevisions")
+	}
+
+	var r0 []*models.EventRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int) ([]*models.EventRevision, error)); ok {
+		return rf(ctx, trackingID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, int) []*models.EventRevision); ok {
+		r0 = rf(ctx, trackingID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.EventRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, int) error); ok {
+		r1 = rf(ctx, trackingID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRepository_ListEventRevisions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListEventRevisions'
+type MockRepository_ListEventRevisions_Call struct {
+	*mock.Call
+}
+
+// ListEventRevisions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uint64
+//   - limit int
+func (_e *MockRepository_Expecter) ListEventRevisions(ctx interface{}, trackingID interface{}, limit interface{}) *MockRepository_ListEventRevisions_Call {
+	return &MockRepository_ListEventRevisions_Call{Call: _e.mock.On("ListEventRevisions", ctx, trackingID, limit)}
+}
+
+func (_c *MockRepository_ListEventRevisions_Call) Run(run func(ctx context.Context, trackingID uint64, limit int)) *MockRepository_ListEventRevisions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockRepository_ListEventRevisions_Call) Return(_a0 []*models.EventRevision, _a1 error) *MockRepository_ListEventRevisions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_ListEventRevisions_Call) RunAndReturn(run func(context.Context, uint64, int) ([]*models.EventRevision, error)) *MockRepository_ListEventRevisions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListLatestEvents provides a mock function with given fields: ctx, trackingIDs, limit, since
+func (_m *MockRepository) ListLatestEvents(ctx context.Context, trackingIDs []uint64, limit int, since time.Time) (map[uint64][]*models.TrackingEvent, error) {
+	ret := _m.Called(ctx, trackingIDs, limit, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLatestEvents")
+	}
+
+	var r0 map[uint64][]*models.TrackingEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64, int, time.Time) (map[uint64][]*models.TrackingEvent, error)); ok {
+		return rf(ctx, trackingIDs, limit, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64, int, time.Time) map[uint64][]*models.TrackingEvent); ok {
+		r0 = rf(ctx, trackingIDs, limit, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint64][]*models.TrackingEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint64, int, time.Time) error); ok {
+		r1 = rf(ctx, trackingIDs, limit, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRepository_ListLatestEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListLatestEvents'
+type MockRepository_ListLatestEvents_Call struct {
+	*mock.Call
+}
+
+// ListLatestEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingIDs []uint64
+//   - limit int
+//   - since time.Time
+func (_e *MockRepository_Expecter) ListLatestEvents(ctx interface{}, trackingIDs interface{}, limit interface{}, since interface{}) *MockRepository_ListLatestEvents_Call {
+	return &MockRepository_ListLatestEvents_Call{Call: _e.mock.On("ListLatestEvents", ctx, trackingIDs, limit, since)}
+}
+
+func (_c *MockRepository_ListLatestEvents_Call) Run(run func(ctx context.Context, trackingIDs []uint64, limit int, since time.Time)) *MockRepository_ListLatestEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]uint64), args[2].(int), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockRepository_ListLatestEvents_Call) Return(_a0 map[uint64][]*models.TrackingEvent, _a1 error) *MockRepository_ListLatestEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_ListLatestEvents_Call) RunAndReturn(run func(context.Context, []uint64, int, time.Time) (map[uint64][]*models.TrackingEvent, error)) *MockRepository_ListLatestEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTrackingChecks provides a mock function with given fields: ctx, trackingID, failedOnly, before, limit
+func (_m *MockRepository) ListTrackingChecks(ctx context.Context, trackingID uint64, failedOnly bool, before time.Time, limit int) ([]*models.TrackingCheck, error) {
+	ret := _m.Called(ctx, trackingID, failedOnly, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTrackingChecks")
+	}
+
+	var r0 []*models.TrackingCheck
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, bool, time.Time, int) ([]*models.TrackingCheck, error)); ok {
+		return rf(ctx, trackingID, failedOnly, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, bool, time.Time, int) []*models.TrackingCheck); ok {
+		r0 = rf(ctx, trackingID, failedOnly, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.TrackingCheck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, bool, time.Time, int) error); ok {
+		r1 = rf(ctx, trackingID, failedOnly, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRepository_ListTrackingChecks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTrackingChecks'
+type MockRepository_ListTrackingChecks_Call struct {
+	*mock.Call
+}
+
+// ListTrackingChecks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uint64
+//   - failedOnly bool
+//   - before time.Time
+//   - limit int
+func (_e *MockRepository_Expecter) ListTrackingChecks(ctx interface{}, trackingID interface{}, failedOnly interface{}, before interface{}, limit interface{}) *MockRepository_ListTrackingChecks_Call {
+	return &MockRepository_ListTrackingChecks_Call{Call: _e.mock.On("ListTrackingChecks", ctx, trackingID, failedOnly, before, limit)}
+}
+
+func (_c *MockRepository_ListTrackingChecks_Call) Run(run func(ctx context.Context, trackingID uint64, failedOnly bool, before time.Time, limit int)) *MockRepository_ListTrackingChecks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(bool), args[3].(time.Time), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockRepository_ListTrackingChecks_Call) Return(_a0 []*models.TrackingCheck, _a1 error) *MockRepository_ListTrackingChecks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_ListTrackingChecks_Call) RunAndReturn(run func(context.Context, uint64, bool, time.Time, int) ([]*models.TrackingCheck, error)) *MockRepository_ListTrackingChecks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTrackingEvents provides a mock function with given fields: ctx, trackingID, f, limit, offset
+func (_m *MockRepository) ListTrackingEvents(ctx context.Context, trackingID uint64, f pgtracking.EventFilter, limit int, offset int) ([]*models.TrackingEvent, error) {
+	ret := _m.Called(ctx, trackingID, f, limit, offset)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListTrackingEvents")
@@ -199,19 +727,19 @@ func (_m *MockRepository) ListTrackingEvents(ctx context.Context, trackingID uin
 
 	var r0 []*models.TrackingEvent
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, int, int) ([]*models.TrackingEvent, error)); ok {
-		return rf(ctx, trackingID, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgtracking.EventFilter, int, int) ([]*models.TrackingEvent, error)); ok {
+		return rf(ctx, trackingID, f, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, int, int) []*models.TrackingEvent); ok {
-		r0 = rf(ctx, trackingID, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgtracking.EventFilter, int, int) []*models.TrackingEvent); ok {
+		r0 = rf(ctx, trackingID, f, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*models.TrackingEvent)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uint64, int, int) error); ok {
-		r1 = rf(ctx, trackingID, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgtracking.EventFilter, int, int) error); ok {
+		r1 = rf(ctx, trackingID, f, limit, offset)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -227,15 +755,16 @@ type MockRepository_ListTrackingEvents_Call struct {
 // ListTrackingEvents is a helper method to define mock.On call
 //   - ctx context.Context
 //   - trackingID uint64
+//   - f pgtracking.EventFilter
 //   - limit int
 //   - offset int
-func (_e *MockRepository_Expecter) ListTrackingEvents(ctx interface{}, trackingID interface{}, limit interface{}, offset interface{}) *MockRepository_ListTrackingEvents_Call {
-	return &MockRepository_ListTrackingEvents_Call{Call: _e.mock.On("ListTrackingEvents", ctx, trackingID, limit, offset)}
+func (_e *MockRepository_Expecter) ListTrackingEvents(ctx interface{}, trackingID interface{}, f interface{}, limit interface{}, offset interface{}) *MockRepository_ListTrackingEvents_Call {
+	return &MockRepository_ListTrackingEvents_Call{Call: _e.mock.On("ListTrackingEvents", ctx, trackingID, f, limit, offset)}
 }
 
-func (_c *MockRepository_ListTrackingEvents_Call) Run(run func(ctx context.Context, trackingID uint64, limit int, offset int)) *MockRepository_ListTrackingEvents_Call {
+func (_c *MockRepository_ListTrackingEvents_Call) Run(run func(ctx context.Context, trackingID uint64, f pgtracking.EventFilter, limit int, offset int)) *MockRepository_ListTrackingEvents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint64), args[2].(int), args[3].(int))
+		run(args[0].(context.Context), args[1].(uint64), args[2].(pgtracking.EventFilter), args[3].(int), args[4].(int))
 	})
 	return _c
 }
@@ -245,7 +774,70 @@ func (_c *MockRepository_ListTrackingEvents_Call) Return(_a0 []*models.TrackingE
 	return _c
 }
 
-func (_c *MockRepository_ListTrackingEvents_Call) RunAndReturn(run func(context.Context, uint64, int, int) ([]*models.TrackingEvent, error)) *MockRepository_ListTrackingEvents_Call {
+func (_c *MockRepository_ListTrackingEvents_Call) RunAndReturn(run func(context.Context, uint64, pgtracking.EventFilter, int, int) ([]*models.TrackingEvent, error)) *MockRepository_ListTrackingEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListTrackingEventsPage provides a mock function with given fields: ctx, trackingID, f, asc, after, limit
+func (_m *MockRepository) ListTrackingEventsPage(ctx context.Context, trackingID uint64, f pgtracking.EventFilter, asc bool, after *pgtracking.EventCursor, limit int) ([]*models.TrackingEvent, error) {
+	ret := _m.Called(ctx, trackingID, f, asc, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTrackingEventsPage")
+	}
+
+	var r0 []*models.TrackingEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgtracking.EventFilter, bool, *pgtracking.EventCursor, int) ([]*models.TrackingEvent, error)); ok {
+		return rf(ctx, trackingID, f, asc, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgtracking.EventFilter, bool, *pgtracking.EventCursor, int) []*models.TrackingEvent); ok {
+		r0 = rf(ctx, trackingID, f, asc, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.TrackingEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgtracking.EventFilter, bool, *pgtracking.EventCursor, int) error); ok {
+		r1 = rf(ctx, trackingID, f, asc, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRepository_ListTrackingEventsPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTrackingEventsPage'
+type MockRepository_ListTrackingEventsPage_Call struct {
+	*mock.Call
+}
+
+// ListTrackingEventsPage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackingID uint64
+//   - f pgtracking.EventFilter
+//   - asc bool
+//   - after *pgtracking.EventCursor
+//   - limit int
+func (_e *MockRepository_Expecter) ListTrackingEventsPage(ctx interface{}, trackingID interface{}, f interface{}, asc interface{}, after interface{}, limit interface{}) *MockRepository_ListTrackingEventsPage_Call {
+	return &MockRepository_ListTrackingEventsPage_Call{Call: _e.mock.On("ListTrackingEventsPage", ctx, trackingID, f, asc, after, limit)}
+}
+
+func (_c *MockRepository_ListTrackingEventsPage_Call) Run(run func(ctx context.Context, trackingID uint64, f pgtracking.EventFilter, asc bool, after *pgtracking.EventCursor, limit int)) *MockRepository_ListTrackingEventsPage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(pgtracking.EventFilter), args[3].(bool), args[4].(*pgtracking.EventCursor), args[5].(int))
+	})
+	return _c
+}
+
+func (_c *MockRepository_ListTrackingEventsPage_Call) Return(_a0 []*models.TrackingEvent, _a1 error) *MockRepository_ListTrackingEventsPage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_ListTrackingEventsPage_Call) RunAndReturn(run func(context.Context, uint64, pgtracking.EventFilter, bool, *pgtracking.EventCursor, int) ([]*models.TrackingEvent, error)) *MockRepository_ListTrackingEventsPage_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -297,6 +889,127 @@ func (_c *MockRepository_RefreshTracking_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// ScanTrackings provides a mock function with given fields: ctx, f, afterID, limit
+func (_m *MockRepository) ScanTrackings(ctx context.Context, f pgtracking.TrackingFilter, afterID uint64, limit int) ([]*models.Tracking, error) {
+	ret := _m.Called(ctx, f, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScanTrackings")
+	}
+
+	var r0 []*models.Tracking
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgtracking.TrackingFilter, uint64, int) ([]*models.Tracking, error)); ok {
+		return rf(ctx, f, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgtracking.TrackingFilter, uint64, int) []*models.Tracking); ok {
+		r0 = rf(ctx, f, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Tracking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgtracking.TrackingFilter, uint64, int) error); ok {
+		r1 = rf(ctx, f, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRepository_ScanTrackings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScanTrackings'
+type MockRepository_ScanTrackings_Call struct {
+	*mock.Call
+}
+
+// ScanTrackings is a helper method to define mock.On call
+//   - ctx context.Context
+//   - f pgtracking.TrackingFilter
+//   - afterID uint64
+//   - limit int
+func (_e *MockRepository_Expecter) ScanTrackings(ctx interface{}, f interface{}, afterID interface{}, limit interface{}) *MockRepository_ScanTrackings_Call {
+	return &MockRepository_ScanTrackings_Call{Call: _e.mock.On("ScanTrackings", ctx, f, afterID, limit)}
+}
+
+func (_c *MockRepository_ScanTrackings_Call) Run(run func(ctx context.Context, f pgtracking.TrackingFilter, afterID uint64, limit int)) *MockRepository_ScanTrackings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(pgtracking.TrackingFilter), args[2].(uint64), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockRepository_ScanTrackings_Call) Return(_a0 []*models.Tracking, _a1 error) *MockRepository_ScanTrackings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_ScanTrackings_Call) RunAndReturn(run func(context.Context, pgtracking.TrackingFilter, uint64, int) ([]*models.Tracking, error)) *MockRepository_ScanTrackings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateTracking provides a mock function with given fields: ctx, id, p
+func (_m *MockRepository) UpdateTracking(ctx context.Context, id uint64, p models.TrackingPatch) (*models.Tracking, error) {
+	ret := _m.Called(ctx, id, p)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTracking")
+	}
+
+	var r0 *models.Tracking
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, models.TrackingPatch) (*models.Tracking, error)); ok {
+		return rf(ctx, id, p)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, models.TrackingPatch) *models.Tracking); ok {
+		r0 = rf(ctx, id, p)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Tracking)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, models.TrackingPatch) error); ok {
+		r1 = rf(ctx, id, p)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRepository_UpdateTracking_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateTracking'
+type MockRepository_UpdateTracking_Call struct {
+	*mock.Call
+}
+
+// UpdateTracking is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint64
+//   - p models.TrackingPatch
+func (_e *MockRepository_Expecter) UpdateTracking(ctx interface{}, id interface{}, p interface{}) *MockRepository_UpdateTracking_Call {
+	return &MockRepository_UpdateTracking_Call{Call: _e.mock.On("UpdateTracking", ctx, id, p)}
+}
+
+func (_c *MockRepository_UpdateTracking_Call) Run(run func(ctx context.Context, id uint64, p models.TrackingPatch)) *MockRepository_UpdateTracking_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(models.TrackingPatch))
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateTracking_Call) Return(_a0 *models.Tracking, _a1 error) *MockRepository_UpdateTracking_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRepository_UpdateTracking_Call) RunAndReturn(run func(context.Context, uint64, models.TrackingPatch) (*models.Tracking, error)) *MockRepository_UpdateTracking_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockRepository creates a new instance of MockRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockRepository(t interface {
@@ -2,23 +2,50 @@ package trackings
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/BearBump/TrackBox/internal/broker/messages"
 	"github.com/BearBump/TrackBox/internal/cache"
 	"github.com/BearBump/TrackBox/internal/models"
 	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"github.com/BearBump/TrackBox/internal/tracknumber"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/singleflight"
 )
 
 type Repository interface {
 	CreateOrGetTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]*models.Tracking, error)
+	BulkCreateTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]pgtracking.CreateResult, error)
 	GetTrackingsByIDs(ctx context.Context, ids []uint64) ([]*models.Tracking, error)
-	ListTrackingEvents(ctx context.Context, trackingID uint64, limit, offset int) ([]*models.TrackingEvent, error)
+	ListTrackingEvents(ctx context.Context, trackingID uint64, f pgtracking.EventFilter, limit, offset int) ([]*models.TrackingEvent, error)
+	ListTrackingEventsPage(ctx context.Context, trackingID uint64, f pgtracking.EventFilter, asc bool, after *pgtracking.EventCursor, limit int) ([]*models.TrackingEvent, error)
+	ListEventKeys(ctx context.Context, trackingID uint64) ([]string, error)
+	CountTrackingEvents(ctx context.Context, trackingID uint64, f pgtracking.EventFilter) (int64, error)
+	ListLatestEvents(ctx context.Context, trackingIDs []uint64, limit int, since time.Time) (map[uint64][]*models.TrackingEvent, error)
 	RefreshTracking(ctx context.Context, trackingID uint64) error
 	ApplyTrackingUpdate(ctx context.Context, upd pgtracking.TrackingUpdate) error
+	UpdateTracking(ctx context.Context, id uint64, p models.TrackingPatch) (*models.Tracking, error)
+	DeleteTracking(ctx context.Context, id uint64) error
+	ListChanges(ctx context.Context, after pgtracking.ChangeCursor, limit int) ([]*models.Change, error)
+	ScanTrackings(ctx context.Context, f pgtracking.TrackingFilter, afterID uint64, limit int) ([]*models.Tracking, error)
+	ListETAHistory(ctx context.Context, trackingID uint64, limit int) ([]*models.ETAChange, error)
+	ListEventRevisions(ctx context.Context, trackingID uint64, limit int) ([]*models.EventRevision, error)
+	ListTrackingChecks(ctx context.Context, trackingID uint64, failedOnly bool, before time.Time, limit int) ([]*models.TrackingCheck, error)
+}
+
+var (
+	// ErrInvalidArgument — запрос не прошёл проверку (текст ошибки — что именно не так).
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrNotFound        = pgtracking.ErrTrackingNotFound
+)
+
+// Checker выполняет немедленную проверку трека у перевозчика (через track-worker).
+type Checker interface {
+	CheckTracking(ctx context.Context, t *models.Tracking) (messages.TrackingUpdated, error)
 }
 
 //go:generate mockery
@@ -27,10 +54,36 @@ type Service struct {
 	repo Repository
 	cache cache.BytesCache
 	currentTTL time.Duration
+	negativeTTL time.Duration
+	invalidator Invalidator
+	loads singleflight.Group
+	inflight loadGuard
+
+	checker      Checker
+	checkTimeout time.Duration
+
+	carriers func() *tracknumber.Registry
 }
 
 func New(repo Repository, c cache.BytesCache, currentTTL time.Duration) *Service {
-	return &Service{repo: repo, cache: c, currentTTL: currentTTL}
+	return &Service{repo: repo, cache: c, currentTTL: currentTTL, negativeTTL: DefaultNegativeTTL, checkTimeout: 10 * time.Second, carriers: tracknumber.Default}
+}
+
+// WithCarriers задаёт источник актуального справочника перевозчиков (по умолчанию tracknumber.Default);
+// вызывается на каждый запрос, поэтому справочник может обновляться на лету.
+func (s *Service) WithCarriers(carriers func() *tracknumber.Registry) *Service {
+	if carriers != nil {
+		s.carriers = carriers
+	}
+	return s
+}
+
+func (s *Service) WithChecker(c Checker, timeout time.Duration) *Service {
+	s.checker = c
+	if timeout > 0 {
+		s.checkTimeout = timeout
+	}
+	return s
 }
 
 func (s *Service) CreateTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]*models.Tracking, error) {
@@ -43,12 +96,10 @@ func (s *Service) CreateTrackings(ctx context.Context, items []models.TrackingCr
 
 	clean := make([]models.TrackingCreateInput, 0, len(items))
 	seen := make(map[string]struct{}, len(items))
-	for _, it := range items {
-		if it.CarrierCode == "" {
-			return nil, errors.New("carrierCode is required")
-		}
-		if it.TrackNumber == "" {
-			return nil, errors.New("trackNumber is required")
+	for i, it := range items {
+		it, err := s.normalize(it)
+		if err != nil {
+			return nil, errors.Wrapf(err, "items[%d]", i)
 		}
 		k := fmt.Sprintf("%s|%s", it.CarrierCode, it.TrackNumber)
 		if _, ok := seen[k]; ok {
@@ -58,51 +109,268 @@ func (s *Service) CreateTrackings(ctx context.Context, items []models.TrackingCr
 		clean = append(clean, it)
 	}
 
-	return s.repo.CreateOrGetTrackings(ctx, clean)
+	ts, err := s.repo.CreateOrGetTrackings(ctx, clean)
+	if err != nil {
+		return nil, err
+	}
+	// новый трек мог быть запомнен кэшем как отсутствующий
+	s.storeCurrent(ctx, ts...)
+	return ts, nil
+}
+
+// BulkChunkSize — по столько элементов потокового создания уходит в БД за раз.
+const BulkChunkSize = 1000
+
+// Статусы элемента в CreateTrackingsBulk.
+const (
+	ItemCreated = "CREATED"
+	ItemExisted = "EXISTED"
+	ItemInvalid = "INVALID"
+)
+
+// ItemResult — итог создания одного элемента.
+type ItemResult struct {
+	Status   string
+	Tracking *models.Tracking // nil для ItemInvalid
+	Reason   string           // только для ItemInvalid
+}
+
+// CreateTrackingsBulk создаёт треки, проверяя каждый элемент отдельно: невалидные не мешают остальным.
+// Результаты — в порядке items. Ошибка возвращается только при сбое БД.
+func (s *Service) CreateTrackingsBulk(ctx context.Context, items []models.TrackingCreateInput) ([]ItemResult, error) {
+	out := make([]ItemResult, len(items))
+	valid := make([]models.TrackingCreateInput, 0, len(items))
+	pos := make([]int, 0, len(items))
+	for i, it := range items {
+		it, err := s.normalize(it)
+		if err != nil {
+			out[i] = ItemResult{Status: ItemInvalid, Reason: err.Error()}
+			continue
+		}
+		valid = append(valid, it)
+		pos = append(pos, i)
+	}
+	if len(valid) == 0 {
+		return out, nil
+	}
+
+	res, err := s.repo.BulkCreateTrackings(ctx, valid)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != len(valid) {
+		return nil, errors.Errorf("bulk create: got %d results for %d items", len(res), len(valid))
+	}
+	created := make([]uint64, 0, len(res))
+	for j, r := range res {
+		st := ItemExisted
+		if r.Created {
+			st = ItemCreated
+			created = append(created, r.Tracking.ID)
+		}
+		out[pos[j]] = ItemResult{Status: st, Tracking: r.Tracking}
+	}
+	// новые треки могли быть запомнены кэшем как отсутствующие; прогревать кэш импортом не нужно
+	s.dropCurrent(ctx, created...)
+	return out, nil
+}
+
+// normalize приводит элемент к канонической форме (см. tracknumber.Registry.Normalize);
+// без carrierCode перевозчик определяется по номеру.
+func (s *Service) normalize(it models.TrackingCreateInput) (models.TrackingCreateInput, error) {
+	if strings.TrimSpace(it.TrackNumber) == "" {
+		return it, errors.New("trackNumber is required")
+	}
+	carrier, number, err := s.carriers().Normalize(it.CarrierCode, it.TrackNumber)
+	if err != nil {
+		return it, err
+	}
+	out := models.TrackingCreateInput{CarrierCode: carrier, TrackNumber: number}
+	if out.Metadata, err = NormalizeMetadata(it.Metadata); err != nil {
+		return it, err
+	}
+	if out.Tags, err = NormalizeTags(it.Tags); err != nil {
+		return it, err
+	}
+	if out.ExternalID, err = NormalizeExternalID(it.ExternalID); err != nil {
+		return it, err
+	}
+	return out, nil
+}
+
+// UpdateTracking меняет пользовательские атрибуты трека (metadata, теги, externalId).
+func (s *Service) UpdateTracking(ctx context.Context, id uint64, p models.TrackingPatch) (*models.Tracking, error) {
+	if id == 0 {
+		return nil, errors.Wrap(ErrInvalidArgument, "trackingId is required")
+	}
+	patch, err := normalizePatch(p)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidArgument, err.Error())
+	}
+
+	if len(patch.AddTags) > 0 {
+		// Лимит тегов проверяем по текущему состоянию (конкурентные изменения могут его слегка превысить).
+		ts, err := s.repo.GetTrackingsByIDs(ctx, []uint64{id})
+		if err != nil {
+			return nil, err
+		}
+		if len(ts) == 0 {
+			return nil, ErrNotFound
+		}
+		if n := len(mergeTags(ts[0].Tags, patch)); n > MaxTags {
+			return nil, errors.Wrapf(ErrInvalidArgument, "too many tags (%d, max %d)", n, MaxTags)
+		}
+	}
+
+	t, err := s.repo.UpdateTracking(ctx, id, patch)
+	if err != nil {
+		return nil, err
+	}
+	s.storeCurrent(ctx, t)
+	return t, nil
+}
+
+// DeleteTracking удаляет трек со всей историей; в ленте изменений остаётся отметка об удалении.
+func (s *Service) DeleteTracking(ctx context.Context, id uint64) error {
+	if id == 0 {
+		return errors.Wrap(ErrInvalidArgument, "trackingId is required")
+	}
+	if err := s.repo.DeleteTracking(ctx, id); err != nil {
+		return err
+	}
+	s.dropCurrent(ctx, id)
+	return nil
+}
+
+func normalizePatch(p models.TrackingPatch) (models.TrackingPatch, error) {
+	out := models.TrackingPatch{ClearTags: p.ClearTags}
+	if p.Metadata != nil {
+		m, err := NormalizeMetadata(p.Metadata)
+		if err != nil {
+			return out, err
+		}
+		if m == nil {
+			m = new(string) // "" — удалить metadata
+		}
+		out.Metadata = m
+	}
+	if p.ExternalID != nil {
+		id, err := NormalizeExternalID(p.ExternalID)
+		if err != nil {
+			return out, err
+		}
+		if id == nil {
+			id = new(string)
+		}
+		out.ExternalID = id
+	}
+	var err error
+	if out.AddTags, err = NormalizeTags(p.AddTags); err != nil {
+		return out, err
+	}
+	for _, t := range p.RemoveTags {
+		if t = strings.TrimSpace(t); t != "" {
+			out.RemoveTags = append(out.RemoveTags, t)
+		}
+	}
+	return out, nil
+}
+
+// mergeTags повторяет то, как UpdateTracking применяет теги в БД.
+func mergeTags(cur []string, p models.TrackingPatch) []string {
+	if p.ClearTags {
+		cur = nil
+	}
+	removed := make(map[string]struct{}, len(p.RemoveTags))
+	for _, t := range p.RemoveTags {
+		removed[t] = struct{}{}
+	}
+	out := make([]string, 0, len(cur)+len(p.AddTags))
+	seen := make(map[string]struct{}, cap(out))
+	for _, t := range append(append([]string{}, cur...), p.AddTags...) {
+		if _, ok := removed[t]; ok {
+			continue
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+	return out
+}
+
+// ListTrackingsPageSize — размер страницы ListTrackings по умолчанию (и максимальный — ×10).
+const ListTrackingsPageSize = 100
+
+// ListTrackings отдаёт страницу треков по фильтру в порядке id. pageToken — из предыдущего ответа;
+// пустой nextPageToken — страниц больше нет.
+func (s *Service) ListTrackings(ctx context.Context, f pgtracking.TrackingFilter, pageToken string, pageSize int) ([]*models.Tracking, string, error) {
+	if pageSize <= 0 {
+		pageSize = ListTrackingsPageSize
+	}
+	if pageSize > 10*ListTrackingsPageSize {
+		pageSize = 10 * ListTrackingsPageSize
+	}
+	var after uint64
+	if pageToken != "" {
+		v, err := strconv.ParseUint(pageToken, 10, 64)
+		if err != nil {
+			return nil, "", errors.Wrap(ErrInvalidArgument, "bad pageToken")
+		}
+		after = v
+	}
+	if f.Metadata != "" {
+		m, err := NormalizeMetadata(&f.Metadata)
+		if err != nil {
+			return nil, "", errors.Wrap(ErrInvalidArgument, "metadata filter must be a JSON object")
+		}
+		f.Metadata = *m
+	}
+	tags, err := NormalizeTags(f.Tags)
+	if err != nil {
+		return nil, "", errors.Wrap(ErrInvalidArgument, err.Error())
+	}
+	f.Tags = tags
+	f.CarrierCode = tracknumber.CanonicalCarrier(f.CarrierCode)
+	if c, ok := s.carriers().Lookup(f.CarrierCode); ok {
+		f.CarrierCode = c.Code
+	}
+	f.ExternalID = strings.TrimSpace(f.ExternalID)
+
+	ts, err := s.repo.ScanTrackings(ctx, f, after, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(ts) <= pageSize {
+		return ts, "", nil
+	}
+	ts = ts[:pageSize]
+	return ts, strconv.FormatUint(ts[pageSize-1].ID, 10), nil
 }
 
 func (s *Service) GetTrackingsByIDs(ctx context.Context, ids []uint64) ([]*models.Tracking, error) {
 	if len(ids) == 0 {
 		return []*models.Tracking{}, nil
 	}
-	// MVP: кэшируем "текущее состояние" целиком как JSON трекинга.
-	// Для простоты делаем "лучшее усилие": кэш не обязан быть всегда.
-	miss := make([]uint64, 0, len(ids))
+	// Кэш текущего состояния — лучшее усилие: при недоступном Redis всё читается из БД (см. current_cache.go).
 	got := make(map[uint64]*models.Tracking, len(ids))
-
-	if s.cache != nil && s.currentTTL > 0 {
-		for _, id := range ids {
-			key := currentKey(id)
-			b, ok, err := s.cache.Get(ctx, key)
-			if err != nil || !ok {
-				miss = append(miss, id)
-				continue
+	if s.cacheEnabled() {
+		if miss := s.readCurrent(ctx, ids, got); len(miss) > 0 {
+			loaded, err := s.loadCurrent(ctx, miss)
+			if err != nil {
+				return nil, err
 			}
-			var t models.Tracking
-			if json.Unmarshal(b, &t) != nil {
-				miss = append(miss, id)
-				continue
+			for id, t := range loaded {
+				got[id] = t
 			}
-			got[id] = &t
 		}
 	} else {
-		miss = ids
-	}
-
-	var fromDB []*models.Tracking
-	var err error
-	if len(miss) > 0 {
-		fromDB, err = s.repo.GetTrackingsByIDs(ctx, miss)
+		ts, err := s.repo.GetTrackingsByIDs(ctx, ids)
 		if err != nil {
 			return nil, err
 		}
-		if s.cache != nil && s.currentTTL > 0 {
-			for _, t := range fromDB {
-				b, _ := json.Marshal(t)
-				_ = s.cache.Set(ctx, currentKey(t.ID), b, s.currentTTL)
-			}
-		}
-		for _, t := range fromDB {
+		for _, t := range ts {
 			got[t.ID] = t
 		}
 	}
@@ -117,15 +385,322 @@ func (s *Service) GetTrackingsByIDs(ctx context.Context, ids []uint64) ([]*model
 	return out, nil
 }
 
-func (s *Service) ListTrackingEvents(ctx context.Context, trackingID uint64, limit, offset int) ([]*models.TrackingEvent, error) {
-	return s.repo.ListTrackingEvents(ctx, trackingID, limit, offset)
+// ListTrackingEvents — события трека, новые первыми; f ограничивает их по стране / региону.
+func (s *Service) ListTrackingEvents(ctx context.Context, trackingID uint64, f pgtracking.EventFilter, limit, offset int) ([]*models.TrackingEvent, error) {
+	f.Country = strings.TrimSpace(f.Country)
+	f.Region = strings.TrimSpace(f.Region)
+	return s.repo.ListTrackingEvents(ctx, trackingID, f, limit, offset)
+}
+
+// ListTrackingEventsPageSize — размер страницы истории событий по умолчанию (и максимальный — ×10).
+const ListTrackingEventsPageSize = 100
+
+// EventsQuery — какую страницу истории событий отдать.
+type EventsQuery struct {
+	Filter pgtracking.EventFilter
+	// Ascending — от старых к новым; по умолчанию новые первыми.
+	Ascending bool
+	PageSize  int
+	// PageToken — next_page_token предыдущего ответа; действует с теми же порядком и фильтрами.
+	PageToken string
+	// Offset — устаревшая постраничность LIMIT/OFFSET (только новые первыми, не больше 500 за раз);
+	// 0 — по курсору.
+	Offset int
+	// WithTotal — посчитать и число событий под фильтром (отдельный запрос).
+	WithTotal bool
+}
+
+// EventsPage — страница истории событий.
+type EventsPage struct {
+	Events []*models.TrackingEvent
+	// NextPageToken пуст, если страниц больше нет (и в режиме Offset).
+	NextPageToken string
+	// TotalCount — только с EventsQuery.WithTotal.
+	TotalCount *int64
+}
+
+// ListTrackingEventsPage отдаёт страницу событий трека по курсору (event_time, id): токен — время и id
+// последнего события страницы, поэтому новые события не сдвигают уже выданные страницы.
+func (s *Service) ListTrackingEventsPage(ctx context.Context, trackingID uint64, q EventsQuery) (*EventsPage, error) {
+	if trackingID == 0 {
+		return nil, errors.Wrap(ErrInvalidArgument, "trackingId is required")
+	}
+	f := q.Filter
+	f.Country = strings.TrimSpace(f.Country)
+	f.Region = strings.TrimSpace(f.Region)
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return nil, errors.Wrap(ErrInvalidArgument, "from must be before to")
+	}
+	if q.Offset < 0 || q.PageSize < 0 {
+		return nil, errors.Wrap(ErrInvalidArgument, "pageSize and offset must be >= 0")
+	}
+	if q.Offset > 0 && (q.PageToken != "" || q.Ascending) {
+		return nil, errors.Wrap(ErrInvalidArgument, "offset works only in descending order without pageToken")
+	}
+	after, err := parseEventsPageToken(q.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &EventsPage{}
+	if q.WithTotal {
+		n, err := s.repo.CountTrackingEvents(ctx, trackingID, f)
+		if err != nil {
+			return nil, err
+		}
+		page.TotalCount = &n
+	}
+	if q.Offset > 0 {
+		page.Events, err = s.ListTrackingEvents(ctx, trackingID, f, q.PageSize, q.Offset)
+		return page, err
+	}
+
+	size := q.PageSize
+	if size <= 0 {
+		size = ListTrackingEventsPageSize
+	}
+	if size > 10*ListTrackingEventsPageSize {
+		size = 10 * ListTrackingEventsPageSize
+	}
+	evs, err := s.repo.ListTrackingEventsPage(ctx, trackingID, f, q.Ascending, after, size+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(evs) > size {
+		evs = evs[:size]
+		last := evs[size-1]
+		page.NextPageToken = strconv.FormatInt(last.EventTime.UnixMicro(), 10) + "." + strconv.FormatUint(last.ID, 10)
+	}
+	page.Events = evs
+	return page, nil
+}
+
+// parseEventsPageToken разбирает токен "<event_time в микросекундах>.<id>"; пустой — с начала.
+func parseEventsPageToken(token string) (*pgtracking.EventCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	ts, id, ok := strings.Cut(token, ".")
+	micros, err := strconv.ParseInt(ts, 10, 64)
+	if !ok || err != nil {
+		return nil, errors.Wrap(ErrInvalidArgument, "bad pageToken")
+	}
+	eventID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidArgument, "bad pageToken")
+	}
+	return &pgtracking.EventCursor{EventTime: time.UnixMicro(micros).UTC(), ID: eventID}, nil
+}
+
+const (
+	// BatchListEventsMaxIDs — сколько треков можно запросить в BatchListTrackingEvents за раз.
+	BatchListEventsMaxIDs = 500
+	// BatchListEventsLimit — событий на трек по умолчанию (и максимальное — ×10).
+	BatchListEventsLimit = 10
+)
+
+// TrackingEvents — последние события одного трека.
+type TrackingEvents struct {
+	TrackingID uint64
+	Events     []*models.TrackingEvent
+}
+
+// BatchListTrackingEvents — до limit последних событий (новые первыми) каждого трека одним запросом, например для
+// списка заказов. Ответ — в порядке ids (повторы убираются), по записи на каждый id, даже без событий.
+// since — только события, записанные позже (created_at), для инкрементальной синхронизации.
+func (s *Service) BatchListTrackingEvents(ctx context.Context, ids []uint64, limit int, since time.Time) ([]TrackingEvents, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return nil, errors.Wrap(ErrInvalidArgument, "trackingIds is required")
+	}
+	if len(ids) > BatchListEventsMaxIDs {
+		return nil, errors.Wrapf(ErrInvalidArgument, "too many trackingIds (%d, max %d)", len(ids), BatchListEventsMaxIDs)
+	}
+	if limit < 0 {
+		return nil, errors.Wrap(ErrInvalidArgument, "limitPerTracking must be >= 0")
+	}
+	if limit == 0 {
+		limit = BatchListEventsLimit
+	}
+	if limit > 10*BatchListEventsLimit {
+		limit = 10 * BatchListEventsLimit
+	}
+
+	byID, err := s.repo.ListLatestEvents(ctx, ids, limit, since)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]TrackingEvents, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, TrackingEvents{TrackingID: id, Events: byID[id]})
+	}
+	return out, nil
+}
+
+// uniqueIDs — ids без нулей и повторов, в исходном порядке.
+func uniqueIDs(ids []uint64) []uint64 {
+	seen := make(map[uint64]bool, len(ids))
+	out := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
+
+// ListETAHistory — смены ожидаемой даты доставки, от старых к новым (не больше 500).
+func (s *Service) ListETAHistory(ctx context.Context, trackingID uint64) ([]*models.ETAChange, error) {
+	if trackingID == 0 {
+		return nil, errors.Wrap(ErrInvalidArgument, "trackingId is required")
+	}
+	return s.repo.ListETAHistory(ctx, trackingID, 500)
+}
+
+// ListTrackingChecksPageSize — размер страницы ListTrackingChecks по умолчанию (и максимальный — ×10).
+const ListTrackingChecksPageSize = 100
+
+// ListTrackingChecks отдаёт страницу журнала проверок трека, новые первыми. pageToken — из предыдущего ответа
+// (время последней проверки страницы); пустой nextPageToken — страниц больше нет.
+func (s *Service) ListTrackingChecks(ctx context.Context, trackingID uint64, failedOnly bool, pageToken string, pageSize int) ([]*models.TrackingCheck, string, error) {
+	if trackingID == 0 {
+		return nil, "", errors.Wrap(ErrInvalidArgument, "trackingId is required")
+	}
+	if pageSize <= 0 {
+		pageSize = ListTrackingChecksPageSize
+	}
+	if pageSize > 10*ListTrackingChecksPageSize {
+		pageSize = 10 * ListTrackingChecksPageSize
+	}
+	var before time.Time
+	if pageToken != "" {
+		v, err := strconv.ParseInt(pageToken, 10, 64)
+		if err != nil || v <= 0 {
+			return nil, "", errors.Wrap(ErrInvalidArgument, "bad pageToken")
+		}
+		before = time.UnixMicro(v).UTC()
+	}
+
+	checks, err := s.repo.ListTrackingChecks(ctx, trackingID, failedOnly, before, pageSize+1)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(checks) <= pageSize {
+		return checks, "", nil
+	}
+	checks = checks[:pageSize]
+	return checks, strconv.FormatInt(checks[pageSize-1].CheckedAt.UnixMicro(), 10), nil
+}
+
+// ListEventRevisions — исправления и удаления событий у перевозчика, от старых к новым (не больше 500).
+func (s *Service) ListEventRevisions(ctx context.Context, trackingID uint64) ([]*models.EventRevision, error) {
+	if trackingID == 0 {
+		return nil, errors.Wrap(ErrInvalidArgument, "trackingId is required")
+	}
+	return s.repo.ListEventRevisions(ctx, trackingID, 500)
 }
 
 func (s *Service) RefreshTracking(ctx context.Context, trackingID uint64) error {
 	if trackingID == 0 {
 		return errors.New("trackingId is required")
 	}
-	return s.repo.RefreshTracking(ctx, trackingID)
+	if err := s.repo.RefreshTracking(ctx, trackingID); err != nil {
+		return err
+	}
+	// в кэше осталось бы старое next_check_at
+	s.dropCurrent(ctx, trackingID)
+	return nil
+}
+
+// CheckResult — результат CheckTrackingNow.
+type CheckResult struct {
+	Tracking  *models.Tracking
+	NewEvents []*models.TrackingEvent
+	// Queued=true: воркер не ответил вовремя (или недоступен/упёрся в rate limit),
+	// поэтому трек просто поставлен в очередь, как в RefreshTracking.
+	Queued bool
+}
+
+// CheckTrackingNow просит воркер сходить к перевозчику прямо сейчас и ждёт ответа не дольше checkTimeout.
+// Сохранение результата идёт обычным путём через Kafka, здесь мы лишь накладываем его на текущее состояние.
+func (s *Service) CheckTrackingNow(ctx context.Context, trackingID uint64) (*CheckResult, error) {
+	if trackingID == 0 {
+		return nil, errors.Wrap(ErrInvalidArgument, "trackingId is required")
+	}
+	ts, err := s.repo.GetTrackingsByIDs(ctx, []uint64{trackingID})
+	if err != nil {
+		return nil, err
+	}
+	if len(ts) == 0 {
+		return nil, ErrNotFound
+	}
+	t := ts[0]
+
+	if s.checker == nil {
+		return s.queueCheck(ctx, t)
+	}
+
+	// Известные события снимаются до проверки: воркер публикует результат в Kafka раньше, чем отвечает,
+	// и консьюмер может успеть его сохранить — тогда все новые события выглядели бы уже известными.
+	keys, err := s.repo.ListEventKeys(ctx, trackingID)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		known[k] = struct{}{}
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, s.checkTimeout)
+	defer cancel()
+	msg, err := s.checker.CheckTracking(checkCtx, t)
+	if err != nil {
+		slog.Warn("check now failed, falling back to refresh", "tracking_id", trackingID, "error", err.Error())
+		return s.queueCheck(ctx, t)
+	}
+
+	var fresh []*models.TrackingEvent
+	for _, e := range eventsFromMessage(msg) {
+		if _, ok := known[e.DedupKey()]; ok {
+			continue
+		}
+		e.TrackingID = trackingID
+		fresh = append(fresh, e)
+	}
+
+	return &CheckResult{Tracking: applyCheck(t, msg), NewEvents: fresh}, nil
+}
+
+func (s *Service) queueCheck(ctx context.Context, t *models.Tracking) (*CheckResult, error) {
+	if err := s.repo.RefreshTracking(ctx, t.ID); err != nil {
+		return nil, err
+	}
+	return &CheckResult{Tracking: t, Queued: true}, nil
+}
+
+// applyCheck накладывает результат проверки на трек так же, как это сделает ApplyTrackingUpdate.
+func applyCheck(t *models.Tracking, msg messages.TrackingUpdated) *models.Tracking {
+	out := *t
+	checkedAt := msg.CheckedAt
+	out.LastCheckedAt = &checkedAt
+	out.NextCheckAt = msg.NextCheckAt
+	if msg.Error != nil && *msg.Error != "" {
+		out.CheckFailCount++
+		out.LastError = msg.Error
+		return &out
+	}
+	out.Status = msg.Status
+	out.StatusRaw = msg.StatusRaw
+	out.StatusAt = msg.StatusAt
+	out.CheckFailCount = 0
+	out.LastError = nil
+	out.Fingerprint = msg.Fingerprint
+	if msg.Shipment != nil {
+		out.Shipment = out.Shipment.Merge(msg.Shipment.Model())
+	}
+	return &out
 }
 
 func (s *Service) ApplyKafkaUpdate(ctx context.Context, msg messages.TrackingUpdated) error {
@@ -140,22 +715,7 @@ func (s *Service) ApplyKafkaUpdate(ctx context.Context, msg messages.TrackingUpd
 		msg.NextCheckAt = msg.CheckedAt.Add(60 * time.Minute)
 	}
 
-	var events []*models.TrackingEvent
-	for _, e := range msg.Events {
-		var payloadStr *string
-		if len(e.Payload) > 0 {
-			s := string(e.Payload)
-			payloadStr = &s
-		}
-		events = append(events, &models.TrackingEvent{
-			Status:     e.Status,
-			StatusRaw:  e.StatusRaw,
-			EventTime:  e.EventTime,
-			Location:   e.Location,
-			Message:    e.Message,
-			PayloadJSON: payloadStr,
-		})
-	}
+	events := eventsFromMessage(msg)
 
 	err := s.repo.ApplyTrackingUpdate(ctx, pgtracking.TrackingUpdate{
 		TrackingID:  msg.TrackingID,
@@ -165,27 +725,76 @@ func (s *Service) ApplyKafkaUpdate(ctx context.Context, msg messages.TrackingUpd
 		StatusAt:    msg.StatusAt,
 		NextCheckAt: msg.NextCheckAt,
 		Events:      events,
+		Shipment:    msg.Shipment.Model(),
 		Error:       msg.Error,
+		Fingerprint: msg.Fingerprint,
+		Check:       checkFromMessage(msg),
 	})
 	if err != nil {
 		return err
 	}
 
 	// Инвалидируем/обновляем кэш текущего статуса.
-	if s.cache != nil && s.currentTTL > 0 {
+	if s.cacheEnabled() {
 		// Просто перезагрузим из БД одну запись.
 		ts, err := s.repo.GetTrackingsByIDs(ctx, []uint64{msg.TrackingID})
 		if err == nil && len(ts) == 1 {
-			b, _ := json.Marshal(ts[0])
-			_ = s.cache.Set(ctx, currentKey(msg.TrackingID), b, s.currentTTL)
+			s.storeCurrent(ctx, ts[0])
+		} else {
+			s.dropCurrent(ctx, msg.TrackingID)
 		}
 	}
 
 	return nil
 }
 
-func currentKey(id uint64) string {
-	return fmt.Sprintf("tracking:%d:current", id)
+// checkFromMessage — запись журнала проверок; у сообщений старых воркеров (без check) — то, что есть в самом сообщении.
+func checkFromMessage(msg messages.TrackingUpdated) *models.TrackingCheck {
+	c := &models.TrackingCheck{
+		TrackingID:  msg.TrackingID,
+		CheckedAt:   msg.CheckedAt,
+		Kind:        msg.Kind,
+		Status:      msg.Status,
+		EventsCount: len(msg.Events),
+	}
+	if msg.Error != nil && *msg.Error != "" {
+		c.Error = msg.Error
+		c.Status = ""
+	}
+	if ci := msg.Check; ci != nil {
+		c.ErrorClass = ci.ErrorClass
+		c.Worker = ci.Worker
+		c.Backend = ci.Backend
+		c.LatencyMS = ci.LatencyMS
+		c.HTTPStatus = ci.HTTPStatus
+		c.EventsCount = ci.EventsCount
+		c.ResponseHash = ci.ResponseHash
+		c.ResponseRef = ci.ResponseRef
+	}
+	return c
+}
+
+func eventsFromMessage(msg messages.TrackingUpdated) []*models.TrackingEvent {
+	var events []*models.TrackingEvent
+	for _, e := range msg.Events {
+		var payloadStr *string
+		if len(e.Payload) > 0 {
+			s := string(e.Payload)
+			payloadStr = &s
+		}
+		events = append(events, &models.TrackingEvent{
+			Status:     e.Status,
+			StatusRaw:  e.StatusRaw,
+			EventTime:  e.EventTime,
+			Location:   e.Location,
+			Message:    e.Message,
+			PayloadJSON: payloadStr,
+			Place:      e.Place.Model(),
+			EventTimeRaw: e.EventTimeRaw,
+			TimeInferred: e.TimeInferred,
+		})
+	}
+	return events
 }
 
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/BearBump/TrackBox/internal/cache"
 	cachemocks "github.com/BearBump/TrackBox/internal/cache/mocks"
 	"github.com/BearBump/TrackBox/internal/broker/messages"
 	"github.com/BearBump/TrackBox/internal/models"
@@ -33,22 +34,27 @@ func (s *ServiceSuite) SetupTest() {
 
 func (s *ServiceSuite) TestCreateTrackings_DedupAndCallsRepo() {
 	in := []models.TrackingCreateInput{
-		{CarrierCode: "CDEK", TrackNumber: "A"},
-		{CarrierCode: "CDEK", TrackNumber: "A"},
-		{CarrierCode: "POST_RU", TrackNumber: "B"},
+		{CarrierCode: "CDEK", TrackNumber: "1234567890"},
+		{CarrierCode: "cdek ", TrackNumber: "1234567890"},
+		{CarrierCode: "", TrackNumber: "ra123456785ru"},
 	}
 	wantRepoIn := []models.TrackingCreateInput{
-		{CarrierCode: "CDEK", TrackNumber: "A"},
-		{CarrierCode: "POST_RU", TrackNumber: "B"},
+		{CarrierCode: "CDEK", TrackNumber: "1234567890"},
+		{CarrierCode: "POST_RU", TrackNumber: "RA123456785RU"},
 	}
 	s.repo.On("CreateOrGetTrackings", mock.Anything, wantRepoIn).
 		Return([]*models.Tracking{{ID: 1}, {ID: 2}}, nil).
 		Once()
+	// созданные треки сразу попадают в кэш
+	s.cache.On("MSet", mock.Anything, mock.MatchedBy(func(items []cache.Item) bool {
+		return len(items) == 2 && items[0].Key == "tracking:1:current" && items[1].Key == "tracking:2:current"
+	})).Return(nil).Once()
 
 	out, err := s.svc.CreateTrackings(context.Background(), in)
 	s.Require().NoError(err)
 	s.Require().Len(out, 2)
 	s.repo.AssertExpectations(s.T())
+	s.cache.AssertExpectations(s.T())
 }
 
 func (s *ServiceSuite) TestCreateTrackings_ValidateErrors() {
@@ -74,10 +80,8 @@ func (s *ServiceSuite) TestCreateTrackings_ValidateErrors() {
 
 func (s *ServiceSuite) TestGetTrackingsByIDs_CacheHit_NoDB() {
 	t := &models.Tracking{ID: 7, CarrierCode: "C", TrackNumber: "N", Status: models.TrackingStatusUnknown}
-	b, _ := json.Marshal(t)
-
-	s.cache.On("Get", mock.Anything, "tracking:7:current").
-		Return(b, true, nil).
+	s.cache.On("MGet", mock.Anything, []string{"tracking:7:current"}).
+		Return([][]byte{encodeCurrent(t)}, nil).
 		Once()
 
 	out, err := s.svc.GetTrackingsByIDs(context.Background(), []uint64{7})
@@ -119,30 +123,27 @@ func (s *ServiceSuite) TestGetTrackingsByIDs_CachePresentButTTLZero_TreatedAsDis
 	out, err := svc.GetTrackingsByIDs(context.Background(), []uint64{1})
 	s.Require().NoError(err)
 	s.Require().Len(out, 1)
-	s.cache.AssertNotCalled(s.T(), "Get", mock.Anything, mock.Anything)
-	s.cache.AssertNotCalled(s.T(), "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	s.cache.AssertNotCalled(s.T(), "MGet", mock.Anything, mock.Anything)
+	s.cache.AssertNotCalled(s.T(), "MSet", mock.Anything, mock.Anything)
 	s.repo.AssertExpectations(s.T())
 }
 
 func (s *ServiceSuite) TestGetTrackingsByIDs_CacheMiss_AndSetEvenIfSetFails_OrderPreserved() {
 	ids := []uint64{2, 1}
-	s.cache.On("Get", mock.Anything, "tracking:2:current").
-		Return([]byte(nil), false, nil).
-		Once()
-	s.cache.On("Get", mock.Anything, "tracking:1:current").
-		Return([]byte(nil), false, nil).
+	s.cache.On("MGet", mock.Anything, []string{"tracking:2:current", "tracking:1:current"}).
+		Return([][]byte{nil, nil}, nil).
 		Once()
 
-	// DB вернёт в другом порядке — сервис должен вернуть в порядке ids
-	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(2), uint64(1)}).
+	// промахи грузятся по возрастанию id — сервис должен вернуть в порядке ids
+	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(1), uint64(2)}).
 		Return([]*models.Tracking{{ID: 1}, {ID: 2}}, nil).
 		Once()
 
-	// Set ошибки игнорируются
-	s.cache.On("Set", mock.Anything, "tracking:1:current", mock.Anything, 10*time.Minute).
-		Return(errors.New("set failed")).
-		Once()
-	s.cache.On("Set", mock.Anything, "tracking:2:current", mock.Anything, 10*time.Minute).
+	// ошибки записи в кэш игнорируются
+	s.cache.On("MSet", mock.Anything, mock.MatchedBy(func(items []cache.Item) bool {
+		return len(items) == 2 && items[0].Key == "tracking:1:current" && items[1].Key == "tracking:2:current" &&
+			items[0].TTL == 10*time.Minute
+	})).
 		Return(errors.New("set failed")).
 		Once()
 
@@ -155,39 +156,35 @@ func (s *ServiceSuite) TestGetTrackingsByIDs_CacheMiss_AndSetEvenIfSetFails_Orde
 	s.cache.AssertExpectations(s.T())
 }
 
-func (s *ServiceSuite) TestGetTrackingsByIDs_CacheHitButEmptyBytes_IsMiss() {
-	// cache ok=true, но bytes пустые -> json.Unmarshal падает -> идём в БД
-	s.cache.On("Get", mock.Anything, "tracking:1:current").
-		Return([]byte(nil), true, nil).
+func (s *ServiceSuite) TestGetTrackingsByIDs_NotInDB_CachedAsMissing() {
+	s.cache.On("MGet", mock.Anything, []string{"tracking:1:current", "tracking:2:current"}).
+		Return([][]byte{nil, nil}, nil).
 		Once()
 
-	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(1)}).
+	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(1), uint64(2)}).
 		Return([]*models.Tracking{{ID: 1}}, nil).
 		Once()
-	s.cache.On("Set", mock.Anything, "tracking:1:current", mock.Anything, 10*time.Minute).Return(nil).Once()
+	s.cache.On("MSet", mock.Anything, mock.MatchedBy(func(items []cache.Item) bool {
+		return len(items) == 2 && items[1].Key == "tracking:2:current" &&
+			string(items[1].Value) == string(encodeMissing()) && items[1].TTL == DefaultNegativeTTL
+	})).Return(nil).Once()
 
-	out, err := s.svc.GetTrackingsByIDs(context.Background(), []uint64{1})
+	out, err := s.svc.GetTrackingsByIDs(context.Background(), []uint64{1, 2})
 	s.Require().NoError(err)
 	s.Require().Len(out, 1)
 	s.repo.AssertExpectations(s.T())
 	s.cache.AssertExpectations(s.T())
 }
 
-func (s *ServiceSuite) TestGetTrackingsByIDs_CacheGetError_AndCacheBadJSON_BothMiss() {
-	// 1) cache get error -> miss
-	s.cache.On("Get", mock.Anything, "tracking:1:current").
-		Return([]byte(nil), false, errors.New("redis down")).
-		Once()
-	// 2) cache ok but bad json -> miss
-	s.cache.On("Get", mock.Anything, "tracking:2:current").
-		Return([]byte("not-json"), true, nil).
+func (s *ServiceSuite) TestGetTrackingsByIDs_CacheMGetError_AllMiss() {
+	s.cache.On("MGet", mock.Anything, []string{"tracking:1:current", "tracking:2:current"}).
+		Return([][]byte(nil), errors.New("redis down")).
 		Once()
 
 	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(1), uint64(2)}).
 		Return([]*models.Tracking{{ID: 1}, {ID: 2}}, nil).
 		Once()
-	s.cache.On("Set", mock.Anything, "tracking:1:current", mock.Anything, 10*time.Minute).Return(nil).Once()
-	s.cache.On("Set", mock.Anything, "tracking:2:current", mock.Anything, 10*time.Minute).Return(nil).Once()
+	s.cache.On("MSet", mock.Anything, mock.Anything).Return(errors.New("redis down")).Once()
 
 	out, err := s.svc.GetTrackingsByIDs(context.Background(), []uint64{1, 2})
 	s.Require().NoError(err)
@@ -196,9 +193,28 @@ func (s *ServiceSuite) TestGetTrackingsByIDs_CacheGetError_AndCacheBadJSON_BothM
 	s.cache.AssertExpectations(s.T())
 }
 
+func (s *ServiceSuite) TestGetTrackingsByIDs_BadCacheValues_AreMiss() {
+	// пустое значение, старый формат (голый JSON) и мусор — промахи
+	legacy, _ := json.Marshal(&models.Tracking{ID: 2})
+	s.cache.On("MGet", mock.Anything, []string{"tracking:1:current", "tracking:2:current", "tracking:3:current"}).
+		Return([][]byte{{}, legacy, []byte("not-json")}, nil).
+		Once()
+
+	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(1), uint64(2), uint64(3)}).
+		Return([]*models.Tracking{{ID: 1}, {ID: 2}, {ID: 3}}, nil).
+		Once()
+	s.cache.On("MSet", mock.Anything, mock.Anything).Return(nil).Once()
+
+	out, err := s.svc.GetTrackingsByIDs(context.Background(), []uint64{1, 2, 3})
+	s.Require().NoError(err)
+	s.Require().Len(out, 3)
+	s.repo.AssertExpectations(s.T())
+	s.cache.AssertExpectations(s.T())
+}
+
 func (s *ServiceSuite) TestGetTrackingsByIDs_DBError() {
-	s.cache.On("Get", mock.Anything, "tracking:1:current").
-		Return([]byte(nil), false, nil).
+	s.cache.On("MGet", mock.Anything, []string{"tracking:1:current"}).
+		Return([][]byte{nil}, nil).
 		Once()
 	want := errors.New("db error")
 	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(1)}).
@@ -210,8 +226,8 @@ func (s *ServiceSuite) TestGetTrackingsByIDs_DBError() {
 
 func (s *ServiceSuite) TestListTrackingEvents_Passthrough() {
 	evs := []*models.TrackingEvent{{ID: 1, TrackingID: 9}}
-	s.repo.On("ListTrackingEvents", mock.Anything, uint64(9), 50, 10).Return(evs, nil).Once()
-	out, err := s.svc.ListTrackingEvents(context.Background(), 9, 50, 10)
+	s.repo.On("ListTrackingEvents", mock.Anything, uint64(9), pgtracking.EventFilter{Region: "Москва"}, 50, 10).Return(evs, nil).Once()
+	out, err := s.svc.ListTrackingEvents(context.Background(), 9, pgtracking.EventFilter{Region: " Москва "}, 50, 10)
 	s.Require().NoError(err)
 	s.Require().Len(out, 1)
 	s.repo.AssertExpectations(s.T())
@@ -221,8 +237,10 @@ func (s *ServiceSuite) TestRefreshTracking_ValidateAndPass() {
 	s.Require().Error(s.svc.RefreshTracking(context.Background(), 0))
 
 	s.repo.On("RefreshTracking", mock.Anything, uint64(12)).Return(nil).Once()
+	s.cache.On("Delete", mock.Anything, "tracking:12:current").Return(nil).Once()
 	s.Require().NoError(s.svc.RefreshTracking(context.Background(), 12))
 	s.repo.AssertExpectations(s.T())
+	s.cache.AssertExpectations(s.T())
 }
 
 func (s *ServiceSuite) TestApplyKafkaUpdate_CallsRepoAndUpdatesCache() {
@@ -233,15 +251,21 @@ func (s *ServiceSuite) TestApplyKafkaUpdate_CallsRepoAndUpdatesCache() {
 		Status:      models.TrackingStatusInTransit,
 		StatusRaw:   "RAW",
 		NextCheckAt: now.Add(5 * time.Minute),
+		Kind:        messages.KindCheckedNoChange,
+		Fingerprint: "fp1",
 	}
 
-	s.repo.On("ApplyTrackingUpdate", mock.Anything, mock.AnythingOfType("pgtracking.TrackingUpdate")).
+	s.repo.On("ApplyTrackingUpdate", mock.Anything, mock.MatchedBy(func(upd pgtracking.TrackingUpdate) bool {
+		return upd.Fingerprint == "fp1" && len(upd.Events) == 0
+	})).
 		Return(nil).
 		Once()
 	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(10)}).
 		Return([]*models.Tracking{{ID: 10, CarrierCode: "C", TrackNumber: "N", Status: models.TrackingStatusInTransit}}, nil).
 		Once()
-	s.cache.On("Set", mock.Anything, "tracking:10:current", mock.Anything, 10*time.Minute).
+	s.cache.On("MSet", mock.Anything, mock.MatchedBy(func(items []cache.Item) bool {
+		return len(items) == 1 && items[0].Key == "tracking:10:current" && items[0].TTL == 10*time.Minute
+	})).
 		Return(nil).
 		Once()
 
@@ -293,15 +317,17 @@ func (s *ServiceSuite) TestApplyKafkaUpdate_DefaultTimes_EventsPayload_AndCacheR
 	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(1)}).
 		Return([]*models.Tracking(nil), errors.New("reload fail")).
 		Once()
-	s.cache.AssertNotCalled(s.T(), "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	// перечитать не вышло — старое состояние из кэша убираем
+	s.cache.On("Delete", mock.Anything, "tracking:1:current").Return(nil).Once()
 	s.Require().NoError(s.svc.ApplyKafkaUpdate(context.Background(), msg))
 	s.repo.AssertExpectations(s.T())
 
-	// 2) second: reload returns len != 1 -> Set не вызывается
+	// 2) second: reload returns len != 1 -> MSet не вызывается, ключ удаляется
 	s.repo.On("ApplyTrackingUpdate", mock.Anything, mock.AnythingOfType("pgtracking.TrackingUpdate")).Return(nil).Once()
 	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(2)}).
 		Return([]*models.Tracking{{ID: 2}, {ID: 3}}, nil).
 		Once()
+	s.cache.On("Delete", mock.Anything, "tracking:2:current").Return(nil).Once()
 	s.Require().NoError(s.svc.ApplyKafkaUpdate(context.Background(), messages.TrackingUpdated{
 		TrackingID:  2,
 		CheckedAt:   time.Now().UTC(),
@@ -333,14 +359,19 @@ func (s *ServiceSuite) TestApplyKafkaUpdate_EmptyPayload_AndLocationMessageBranc
 		if !upd.Events[0].EventTime.Equal(evTime) {
 			return false
 		}
+		if p := upd.Events[0].Place; p == nil || p.Region != "Москва" || p.PostalCode != "101000" {
+			return false
+		}
 		return true
 	})).Return(nil).Once()
 
-	// cache reload ok => Set вызывается
+	// cache reload ok => MSet вызывается
 	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(3)}).
 		Return([]*models.Tracking{{ID: 3}}, nil).
 		Once()
-	s.cache.On("Set", mock.Anything, "tracking:3:current", mock.Anything, 10*time.Minute).Return(nil).Once()
+	s.cache.On("MSet", mock.Anything, mock.MatchedBy(func(items []cache.Item) bool {
+		return len(items) == 1 && items[0].Key == "tracking:3:current"
+	})).Return(nil).Once()
 
 	s.Require().NoError(s.svc.ApplyKafkaUpdate(context.Background(), messages.TrackingUpdated{
 		TrackingID:  3,
@@ -349,7 +380,8 @@ func (s *ServiceSuite) TestApplyKafkaUpdate_EmptyPayload_AndLocationMessageBranc
 		StatusRaw:   "RAW",
 		NextCheckAt: time.Now().UTC().Add(1 * time.Minute),
 		Events: []messages.TrackingEvent{
-			{Status: models.TrackingStatusInTransit, StatusRaw: "CDEK: accepted", EventTime: evTime, Location: &loc, Message: &msgText},
+			{Status: models.TrackingStatusInTransit, StatusRaw: "CDEK: accepted", EventTime: evTime, Location: &loc, Message: &msgText,
+				Place: &messages.Place{Country: "RU", Region: "Москва", PostalCode: "101000"}},
 		},
 	}))
 
@@ -384,6 +416,135 @@ func (s *ServiceSuite) TestApplyKafkaUpdate_NoCache_NoReload() {
 	s.repo.AssertNotCalled(s.T(), "GetTrackingsByIDs", mock.Anything, []uint64{uint64(5)})
 }
 
+type fakeChecker struct {
+	msg messages.TrackingUpdated
+	err error
+	got *models.Tracking
+	// published вызывается там, где воркер публикует результат в Kafka, — до ответа.
+	published func()
+}
+
+func (c *fakeChecker) CheckTracking(ctx context.Context, t *models.Tracking) (messages.TrackingUpdated, error) {
+	c.got = t
+	if c.published != nil {
+		c.published()
+	}
+	return c.msg, c.err
+}
+
+func (s *ServiceSuite) TestCheckTrackingNow_ReturnsCheckedTrackingAndOnlyNewEvents() {
+	now := time.Now().UTC()
+	loc := "Moscow"
+	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(4)}).
+		Return([]*models.Tracking{{ID: 4, CarrierCode: "CDEK", TrackNumber: "A", Status: models.TrackingStatusUnknown, CheckFailCount: 2,
+			Shipment: &models.ShipmentDetails{WeightGrams: 300, Origin: "Moscow"}}}, nil).
+		Once()
+	known := &models.TrackingEvent{StatusRaw: "accepted", EventTime: now.Add(-time.Hour), Location: &loc, Message: new(string)}
+	s.repo.On("ListEventKeys", mock.Anything, uint64(4)).Return([]string{known.DedupKey()}, nil).Once()
+
+	ch := &fakeChecker{msg: messages.TrackingUpdated{
+		TrackingID:  4,
+		CheckedAt:   now,
+		Status:      models.TrackingStatusInTransit,
+		StatusRaw:   "in transit",
+		NextCheckAt: now.Add(time.Hour),
+		Events: []messages.TrackingEvent{
+			{Status: models.TrackingStatusInTransit, StatusRaw: "accepted", EventTime: now.Add(-time.Hour), Location: &loc},
+			{Status: models.TrackingStatusInTransit, StatusRaw: "in transit", EventTime: now},
+		},
+		Shipment: &messages.Shipment{EstimatedDelivery: &now, Origin: "Moscow, RU"},
+	}}
+	s.svc.WithChecker(ch, time.Second)
+
+	res, err := s.svc.CheckTrackingNow(context.Background(), 4)
+	s.Require().NoError(err)
+	s.Require().False(res.Queued)
+	s.Require().Equal(models.TrackingStatusInTransit, res.Tracking.Status)
+	s.Require().Equal(int32(0), res.Tracking.CheckFailCount)
+	s.Require().NotNil(res.Tracking.LastCheckedAt)
+	// сведения об отправлении накладываются на сохранённые, пустые поля их не затирают
+	s.Require().Equal(&models.ShipmentDetails{EstimatedDelivery: &now, WeightGrams: 300, Origin: "Moscow, RU"}, res.Tracking.Shipment)
+	s.Require().Len(res.NewEvents, 1)
+	s.Require().Equal("in transit", res.NewEvents[0].StatusRaw)
+	s.Require().Equal(uint64(4), res.NewEvents[0].TrackingID)
+	s.Require().Equal(uint64(4), ch.got.ID)
+	s.repo.AssertNotCalled(s.T(), "RefreshTracking", mock.Anything, mock.Anything)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *ServiceSuite) TestCheckTrackingNow_NewEventsSurviveUpdateAppliedFirst() {
+	now := time.Now().UTC()
+	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(4)}).
+		Return([]*models.Tracking{{ID: 4, Status: models.TrackingStatusUnknown}}, nil).
+		Once()
+	ev := messages.TrackingEvent{Status: models.TrackingStatusInTransit, StatusRaw: "in transit", EventTime: now}
+	s.repo.On("ListEventKeys", mock.Anything, uint64(4)).Return([]string{}, nil).Once()
+
+	ch := &fakeChecker{msg: messages.TrackingUpdated{
+		TrackingID: 4, CheckedAt: now, Status: models.TrackingStatusInTransit, StatusRaw: "in transit",
+		NextCheckAt: now.Add(time.Hour), Events: []messages.TrackingEvent{ev},
+	}}
+	// консьюмер этой реплики сохранил опубликованный воркером результат раньше, чем пришёл ответ
+	ch.published = func() {
+		stored := &models.TrackingEvent{StatusRaw: ev.StatusRaw, EventTime: ev.EventTime, Location: new(string), Message: new(string)}
+		s.repo.On("ListEventKeys", mock.Anything, uint64(4)).Return([]string{stored.DedupKey()}, nil)
+	}
+	s.svc.WithChecker(ch, time.Second)
+
+	res, err := s.svc.CheckTrackingNow(context.Background(), 4)
+	s.Require().NoError(err)
+	s.Require().Len(res.NewEvents, 1)
+	s.Require().Equal("in transit", res.NewEvents[0].StatusRaw)
+}
+
+func (s *ServiceSuite) TestCheckTrackingNow_CarrierErrorCountsFailure() {
+	now := time.Now().UTC()
+	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(4)}).
+		Return([]*models.Tracking{{ID: 4, Status: models.TrackingStatusInTransit, CheckFailCount: 1}}, nil).
+		Once()
+	s.repo.On("ListEventKeys", mock.Anything, uint64(4)).Return([]string{}, nil).Once()
+	e := "carrier emulator http 503"
+	s.svc.WithChecker(&fakeChecker{msg: messages.TrackingUpdated{TrackingID: 4, CheckedAt: now, NextCheckAt: now.Add(time.Minute), Error: &e}}, time.Second)
+
+	res, err := s.svc.CheckTrackingNow(context.Background(), 4)
+	s.Require().NoError(err)
+	s.Require().Equal(models.TrackingStatusInTransit, res.Tracking.Status)
+	s.Require().Equal(int32(2), res.Tracking.CheckFailCount)
+	s.Require().Equal(e, *res.Tracking.LastError)
+	s.Require().Empty(res.NewEvents)
+}
+
+func (s *ServiceSuite) TestCheckTrackingNow_WorkerErrorFallsBackToRefresh() {
+	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(4)}).
+		Return([]*models.Tracking{{ID: 4}}, nil).
+		Once()
+	s.repo.On("ListEventKeys", mock.Anything, uint64(4)).Return([]string{}, nil).Once()
+	s.repo.On("RefreshTracking", mock.Anything, uint64(4)).Return(nil).Once()
+	s.svc.WithChecker(&fakeChecker{err: context.DeadlineExceeded}, time.Second)
+
+	res, err := s.svc.CheckTrackingNow(context.Background(), 4)
+	s.Require().NoError(err)
+	s.Require().True(res.Queued)
+	s.Require().Equal(uint64(4), res.Tracking.ID)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *ServiceSuite) TestCheckTrackingNow_NoCheckerQueues_AndValidates() {
+	_, err := s.svc.CheckTrackingNow(context.Background(), 0)
+	s.Require().ErrorIs(err, ErrInvalidArgument)
+
+	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(8)}).Return([]*models.Tracking{}, nil).Once()
+	_, err = s.svc.CheckTrackingNow(context.Background(), 8)
+	s.Require().ErrorIs(err, ErrNotFound)
+
+	s.repo.On("GetTrackingsByIDs", mock.Anything, []uint64{uint64(9)}).Return([]*models.Tracking{{ID: 9}}, nil).Once()
+	s.repo.On("RefreshTracking", mock.Anything, uint64(9)).Return(nil).Once()
+	res, err := s.svc.CheckTrackingNow(context.Background(), 9)
+	s.Require().NoError(err)
+	s.Require().True(res.Queued)
+	s.repo.AssertExpectations(s.T())
+}
+
 func TestServiceSuite(t *testing.T) {
 	suite.Run(t, new(ServiceSuite))
 }
@@ -3,10 +3,15 @@ package trackings
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/BearBump/TrackBox/internal/broker/messages"
+	"github.com/BearBump/TrackBox/internal/cache"
 	"github.com/BearBump/TrackBox/internal/models"
 	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
 	"github.com/stretchr/testify/require"
@@ -17,6 +22,10 @@ type fakeRepo struct {
 	createOut []*models.Tracking
 	createErr error
 
+	bulkIn  []models.TrackingCreateInput
+	bulkOut []pgtracking.CreateResult
+	bulkErr error
+
 	refreshID uint64
 	refreshErr error
 
@@ -26,19 +35,87 @@ type fakeRepo struct {
 
 	applyUpd pgtracking.TrackingUpdate
 	applyErr error
+
+	updateID    uint64
+	updatePatch models.TrackingPatch
+	updateOut   *models.Tracking
+	updateErr   error
+
+	scanFilter pgtracking.TrackingFilter
+	scanAfter  uint64
+	scanLimit  int
+	scanOut    []*models.Tracking
+
+	etaOut []*models.ETAChange
+
+	revisionsOut []*models.EventRevision
+	eventsOut    []*models.TrackingEvent
+
+	checksBefore time.Time
+	checksLimit  int
+	checksOut    []*models.TrackingCheck
+
+	pageFilter pgtracking.EventFilter
+	pageAsc    bool
+	pageAfter  *pgtracking.EventCursor
+	pageLimit  int
+
+	latestIDs   []uint64
+	latestLimit int
+	latestSince time.Time
+	latestOut   map[uint64][]*models.TrackingEvent
+
+	deleteID  uint64
+	deleteErr error
+
+	changesAfter pgtracking.ChangeCursor
+	changesLimit int
+	changesOut   []*models.Change
 }
 
 func (f *fakeRepo) CreateOrGetTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]*models.Tracking, error) {
 	f.createIn = items
 	return f.createOut, f.createErr
 }
+func (f *fakeRepo) BulkCreateTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]pgtracking.CreateResult, error) {
+	f.bulkIn = items
+	return f.bulkOut, f.bulkErr
+}
 func (f *fakeRepo) GetTrackingsByIDs(ctx context.Context, ids []uint64) ([]*models.Tracking, error) {
 	f.getIn = ids
 	return f.getOut, f.getErr
 }
-func (f *fakeRepo) ListTrackingEvents(ctx context.Context, trackingID uint64, limit, offset int) ([]*models.TrackingEvent, error) {
+func (f *fakeRepo) ListTrackingEvents(ctx context.Context, trackingID uint64, filter pgtracking.EventFilter, limit, offset int) ([]*models.TrackingEvent, error) {
+	return f.eventsOut, nil
+}
+func (f *fakeRepo) ListEventKeys(ctx context.Context, trackingID uint64) ([]string, error) {
 	return nil, nil
 }
+func (f *fakeRepo) ListTrackingEventsPage(ctx context.Context, trackingID uint64, filter pgtracking.EventFilter, asc bool, after *pgtracking.EventCursor, limit int) ([]*models.TrackingEvent, error) {
+	f.pageFilter, f.pageAsc, f.pageAfter, f.pageLimit = filter, asc, after, limit
+	if limit < len(f.eventsOut) {
+		return f.eventsOut[:limit], nil
+	}
+	return f.eventsOut, nil
+}
+func (f *fakeRepo) DeleteTracking(ctx context.Context, id uint64) error {
+	f.deleteID = id
+	return f.deleteErr
+}
+func (f *fakeRepo) ListChanges(ctx context.Context, after pgtracking.ChangeCursor, limit int) ([]*models.Change, error) {
+	f.changesAfter, f.changesLimit = after, limit
+	if limit < len(f.changesOut) {
+		return f.changesOut[:limit], nil
+	}
+	return f.changesOut, nil
+}
+func (f *fakeRepo) ListLatestEvents(ctx context.Context, trackingIDs []uint64, limit int, since time.Time) (map[uint64][]*models.TrackingEvent, error) {
+	f.latestIDs, f.latestLimit, f.latestSince = trackingIDs, limit, since
+	return f.latestOut, nil
+}
+func (f *fakeRepo) CountTrackingEvents(ctx context.Context, trackingID uint64, filter pgtracking.EventFilter) (int64, error) {
+	return int64(len(f.eventsOut)), nil
+}
 func (f *fakeRepo) RefreshTracking(ctx context.Context, trackingID uint64) error {
 	f.refreshID = trackingID
 	return f.refreshErr
@@ -47,19 +124,75 @@ func (f *fakeRepo) ApplyTrackingUpdate(ctx context.Context, upd pgtracking.Track
 	f.applyUpd = upd
 	return f.applyErr
 }
+func (f *fakeRepo) UpdateTracking(ctx context.Context, id uint64, p models.TrackingPatch) (*models.Tracking, error) {
+	f.updateID, f.updatePatch = id, p
+	return f.updateOut, f.updateErr
+}
+func (f *fakeRepo) ScanTrackings(ctx context.Context, fl pgtracking.TrackingFilter, afterID uint64, limit int) ([]*models.Tracking, error) {
+	f.scanFilter, f.scanAfter, f.scanLimit = fl, afterID, limit
+	return f.scanOut, nil
+}
+func (f *fakeRepo) ListETAHistory(ctx context.Context, trackingID uint64, limit int) ([]*models.ETAChange, error) {
+	return f.etaOut, nil
+}
+
+func (f *fakeRepo) ListEventRevisions(ctx context.Context, trackingID uint64, limit int) ([]*models.EventRevision, error) {
+	return f.revisionsOut, nil
+}
+func (f *fakeRepo) ListTrackingChecks(ctx context.Context, trackingID uint64, failedOnly bool, before time.Time, limit int) ([]*models.TrackingCheck, error) {
+	f.checksBefore, f.checksLimit = before, limit
+	return f.checksOut, nil
+}
 
 type fakeCache struct {
-	m map[string][]byte
+	mu sync.Mutex
+	m  map[string][]byte
+	// afterMSet вызывается после записи (без блокировки кэша).
+	afterMSet func()
 }
 
 func (c *fakeCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	b, ok := c.m[key]
 	return b, ok, nil
 }
+func (c *fakeCache) MGet(ctx context.Context, keys []string) ([][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([][]byte, len(keys))
+	for i, k := range keys {
+		out[i] = c.m[k]
+	}
+	return out, nil
+}
 func (c *fakeCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.m[key] = value
 	return nil
 }
+func (c *fakeCache) MSet(ctx context.Context, items []cache.Item) error {
+	c.mu.Lock()
+	for _, it := range items {
+		c.m[it.Key] = it.Value
+	}
+	hook := c.afterMSet
+	c.afterMSet = nil
+	c.mu.Unlock()
+	if hook != nil {
+		hook()
+	}
+	return nil
+}
+func (c *fakeCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range keys {
+		delete(c.m, k)
+	}
+	return nil
+}
 
 func TestService_CreateTrackings_validate(t *testing.T) {
 	s := New(&fakeRepo{}, nil, 0)
@@ -71,6 +204,15 @@ func TestService_CreateTrackings_validate(t *testing.T) {
 
 	_, err = s.CreateTrackings(context.Background(), []models.TrackingCreateInput{{CarrierCode: "C", TrackNumber: ""}})
 	require.Error(t, err)
+
+	_, err = s.CreateTrackings(context.Background(), []models.TrackingCreateInput{
+		{CarrierCode: "CDEK", TrackNumber: "1234567890"},
+		{CarrierCode: "POST_RU", TrackNumber: "RA123456789RU"},
+	})
+	require.ErrorContains(t, err, "items[1]: invalid POST_RU track number: bad S10 check digit")
+
+	_, err = s.CreateTrackings(context.Background(), []models.TrackingCreateInput{{CarrierCode: "DHL", TrackNumber: "1234567890"}})
+	require.ErrorContains(t, err, "unknown carrier")
 }
 
 func TestService_CreateTrackings_dedup(t *testing.T) {
@@ -78,14 +220,49 @@ func TestService_CreateTrackings_dedup(t *testing.T) {
 	s := New(r, nil, 0)
 
 	_, err := s.CreateTrackings(context.Background(), []models.TrackingCreateInput{
-		{CarrierCode: "C", TrackNumber: "A"},
-		{CarrierCode: "C", TrackNumber: "A"},
-		{CarrierCode: "C", TrackNumber: "B"},
+		{CarrierCode: "CDEK", TrackNumber: "1234567890"},
+		{CarrierCode: "CDEK", TrackNumber: "1234567890"},
+		{CarrierCode: "CDEK", TrackNumber: "1234567891"},
 	})
 	require.NoError(t, err)
 	require.Len(t, r.createIn, 2)
 }
 
+func TestService_CreateTrackingsBulk_perItem(t *testing.T) {
+	r := &fakeRepo{bulkOut: []pgtracking.CreateResult{
+		{Tracking: &models.Tracking{ID: 1}, Created: true},
+		{Tracking: &models.Tracking{ID: 2}},
+	}}
+	s := New(r, nil, 0)
+
+	out, err := s.CreateTrackingsBulk(context.Background(), []models.TrackingCreateInput{
+		{CarrierCode: "cdek", TrackNumber: "1234567890"},
+		{CarrierCode: "", TrackNumber: "X"},
+		{CarrierCode: "", TrackNumber: "RA123456785RU"},
+		{CarrierCode: "CDEK", TrackNumber: ""},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []models.TrackingCreateInput{{CarrierCode: "CDEK", TrackNumber: "1234567890"}, {CarrierCode: "POST_RU", TrackNumber: "RA123456785RU"}}, r.bulkIn)
+	require.Len(t, out, 4)
+	require.Equal(t, ItemCreated, out[0].Status)
+	require.Equal(t, uint64(1), out[0].Tracking.ID)
+	require.Equal(t, ItemResult{Status: ItemInvalid, Reason: "carrier cannot be detected from track number"}, out[1])
+	require.Equal(t, ItemExisted, out[2].Status)
+	require.Equal(t, uint64(2), out[2].Tracking.ID)
+	require.Equal(t, ItemResult{Status: ItemInvalid, Reason: "trackNumber is required"}, out[3])
+
+	// все невалидные — в БД не ходим
+	r.bulkIn = nil
+	out, err = s.CreateTrackingsBulk(context.Background(), []models.TrackingCreateInput{{}})
+	require.NoError(t, err)
+	require.Equal(t, ItemInvalid, out[0].Status)
+	require.Nil(t, r.bulkIn)
+
+	r.bulkErr = errors.New("db down")
+	_, err = s.CreateTrackingsBulk(context.Background(), []models.TrackingCreateInput{{CarrierCode: "CDEK", TrackNumber: "1234567890"}})
+	require.Error(t, err)
+}
+
 func TestService_RefreshTracking_validate(t *testing.T) {
 	r := &fakeRepo{}
 	s := New(r, nil, 0)
@@ -95,14 +272,82 @@ func TestService_RefreshTracking_validate(t *testing.T) {
 	require.Equal(t, uint64(10), r.refreshID)
 }
 
+func TestService_RefreshTracking_dropsCache(t *testing.T) {
+	c := &fakeCache{m: map[string][]byte{currentKey(10): encodeCurrent(&models.Tracking{ID: 10})}}
+	s := New(&fakeRepo{}, c, time.Minute)
+
+	require.NoError(t, s.RefreshTracking(context.Background(), 10))
+	require.NotContains(t, c.m, currentKey(10))
+}
+
+// slowRepo отвечает на GetTrackingsByIDs только после release и считает запросы.
+type slowRepo struct {
+	fakeRepo
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (r *slowRepo) GetTrackingsByIDs(ctx context.Context, ids []uint64) ([]*models.Tracking, error) {
+	r.calls.Add(1)
+	<-r.release
+	out := make([]*models.Tracking, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, &models.Tracking{ID: id})
+	}
+	return out, nil
+}
+
+func TestService_GetTrackingsByIDs_coalescesConcurrentMisses(t *testing.T) {
+	r := &slowRepo{release: make(chan struct{})}
+	s := New(r, &fakeCache{m: map[string][]byte{}}, time.Minute)
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// тот же набор в другом порядке — та же загрузка
+			ids := []uint64{1, 2}
+			if i%2 == 1 {
+				ids = []uint64{2, 1}
+			}
+			out, err := s.GetTrackingsByIDs(context.Background(), ids)
+			if err == nil && len(out) != 2 {
+				err = errors.New("unexpected result size")
+			}
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(r.release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(1), r.calls.Load())
+}
+
+func TestService_GetTrackingsByIDs_waiterRespectsOwnContext(t *testing.T) {
+	r := &slowRepo{release: make(chan struct{})}
+	defer close(r.release)
+	s := New(r, &fakeCache{m: map[string][]byte{}}, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := s.GetTrackingsByIDs(ctx, []uint64{1})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func TestService_GetTrackingsByIDs_cacheHit(t *testing.T) {
 	r := &fakeRepo{}
 	c := &fakeCache{m: map[string][]byte{}}
 	s := New(r, c, 10*time.Minute)
 
 	want := &models.Tracking{ID: 7, CarrierCode: "C", TrackNumber: "N", Status: "UNKNOWN"}
-	b, _ := json.Marshal(want)
-	c.m["tracking:7:current"] = b
+	c.m["tracking:7:current"] = encodeCurrent(want)
 
 	out, err := s.GetTrackingsByIDs(context.Background(), []uint64{7})
 	require.NoError(t, err)
@@ -111,6 +356,136 @@ func TestService_GetTrackingsByIDs_cacheHit(t *testing.T) {
 	require.Nil(t, r.getIn) // БД не трогали
 }
 
+func TestService_GetTrackingsByIDs_legacyCacheValueIsMiss(t *testing.T) {
+	r := &fakeRepo{getOut: []*models.Tracking{{ID: 7, Status: "DELIVERED"}}}
+	c := &fakeCache{m: map[string][]byte{}}
+	s := New(r, c, 10*time.Minute)
+
+	// запись до версионирования — голый JSON models.Tracking
+	b, _ := json.Marshal(&models.Tracking{ID: 7, Status: "UNKNOWN"})
+	c.m[currentKey(7)] = b
+
+	out, err := s.GetTrackingsByIDs(context.Background(), []uint64{7})
+	require.NoError(t, err)
+	require.Equal(t, "DELIVERED", out[0].Status)
+	require.Equal(t, []uint64{7}, r.getIn)
+	require.Equal(t, currentCacheVersion, c.m[currentKey(7)][0])
+}
+
+func TestService_GetTrackingsByIDs_negativeCache(t *testing.T) {
+	r := &fakeRepo{getOut: []*models.Tracking{{ID: 1}}}
+	c := &fakeCache{m: map[string][]byte{}}
+	s := New(r, c, 10*time.Minute)
+
+	out, err := s.GetTrackingsByIDs(context.Background(), []uint64{1, 404, 1})
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	require.Equal(t, []uint64{1, 404}, r.getIn)
+	require.Equal(t, encodeMissing(), c.m[currentKey(404)])
+
+	// второй раз отсутствующий трек в БД не ищем
+	r.getIn = nil
+	out, err = s.GetTrackingsByIDs(context.Background(), []uint64{404, 1})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Nil(t, r.getIn)
+
+	// созданный трек затирает отрицательную запись
+	r.createOut = []*models.Tracking{{ID: 404, CarrierCode: "CDEK", TrackNumber: "1234567890"}}
+	_, err = s.CreateTrackings(context.Background(), []models.TrackingCreateInput{{CarrierCode: "CDEK", TrackNumber: "1234567890"}})
+	require.NoError(t, err)
+	out, err = s.GetTrackingsByIDs(context.Background(), []uint64{404})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Nil(t, r.getIn)
+
+	s.WithNegativeTTL(0)
+	c.m = map[string][]byte{}
+	_, err = s.GetTrackingsByIDs(context.Background(), []uint64{405})
+	require.NoError(t, err)
+	require.Empty(t, c.m)
+}
+
+// racingRepo отдаёт getOut, но перед этим один раз вызывает onGet — как если бы трек изменился, пока шло чтение.
+type racingRepo struct {
+	fakeRepo
+	onGet func()
+}
+
+func (r *racingRepo) GetTrackingsByIDs(ctx context.Context, ids []uint64) ([]*models.Tracking, error) {
+	if r.onGet != nil {
+		r.onGet()
+		r.onGet = nil
+	}
+	return r.fakeRepo.GetTrackingsByIDs(ctx, ids)
+}
+
+func TestService_GetTrackingsByIDs_loadDoesNotOverwriteNewerValue(t *testing.T) {
+	stale := &models.Tracking{ID: 1, Status: models.TrackingStatusInTransit}
+	fresh := &models.Tracking{ID: 1, Status: models.TrackingStatusDelivered}
+	r := &racingRepo{fakeRepo: fakeRepo{getOut: []*models.Tracking{stale, {ID: 2}}}}
+	c := &fakeCache{m: map[string][]byte{}}
+	s := New(r, c, 10*time.Minute)
+	// ApplyKafkaUpdate сохранил новое состояние между чтением из БД и записью дозагрузки
+	r.onGet = func() { s.storeCurrent(context.Background(), fresh) }
+
+	out, err := s.GetTrackingsByIDs(context.Background(), []uint64{1, 2})
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	got, found, ok := decodeCurrent(c.m[currentKey(1)])
+	require.True(t, ok && found)
+	require.Equal(t, models.TrackingStatusDelivered, got.Status)
+	// соседний ключ той же дозагрузки записан как обычно
+	require.Contains(t, c.m, currentKey(2))
+}
+
+func TestService_GetTrackingsByIDs_changeDuringLoadWriteDropsKey(t *testing.T) {
+	r := &fakeRepo{getOut: []*models.Tracking{{ID: 1}}}
+	c := &fakeCache{m: map[string][]byte{}}
+	s := New(r, c, 10*time.Minute)
+	inv := &fakeInvalidator{}
+	s.WithInvalidator(inv)
+	// другая реплика изменила трек, когда дозагрузка уже писала прочитанное (отрицательная запись 404 — тоже)
+	c.afterMSet = func() { s.CacheChanged(currentKey(1), currentKey(404)) }
+
+	_, err := s.GetTrackingsByIDs(context.Background(), []uint64{1, 404})
+	require.NoError(t, err)
+	require.Empty(t, c.m)
+	require.ElementsMatch(t, []string{currentKey(1), currentKey(404)}, inv.keys)
+
+	// без изменений дозагрузка пишет как обычно, а отметки изменений не копятся
+	_, err = s.GetTrackingsByIDs(context.Background(), []uint64{1})
+	require.NoError(t, err)
+	require.Contains(t, c.m, currentKey(1))
+	require.Empty(t, s.inflight.keys)
+}
+
+func TestCurrentCacheEncoding_roundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	eta := at.Add(48 * time.Hour)
+	md, ext, lastErr := `{"a":1}`, "order-1", "timeout"
+	want := &models.Tracking{
+		ID: 3, CarrierCode: "CDEK", TrackNumber: "1234567890", Status: "IN_TRANSIT", StatusRaw: "В пути",
+		StatusAt: &at, LastCheckedAt: &at, NextCheckAt: at.Add(time.Hour), CheckFailCount: 2, LastError: &lastErr,
+		CreatedAt: at, UpdatedAt: at, Metadata: &md, Tags: []string{"a", "b"}, ExternalID: &ext,
+		Shipment:    &models.ShipmentDetails{EstimatedDelivery: &eta, WeightGrams: 500, Origin: "Москва", ServiceType: "express"},
+		Fingerprint: "fp",
+	}
+	got, found, ok := decodeCurrent(encodeCurrent(want))
+	require.True(t, ok)
+	require.True(t, found)
+	require.Equal(t, want, got)
+
+	_, found, ok = decodeCurrent(encodeMissing())
+	require.True(t, ok)
+	require.False(t, found)
+
+	for _, b := range [][]byte{nil, {currentCacheVersion}, {currentCacheVersion + 1, cachedMissing}, {currentCacheVersion, cachedFound, '{'}} {
+		_, _, ok = decodeCurrent(b)
+		require.False(t, ok)
+	}
+}
+
 func TestService_GetTrackingsByIDs_cacheMissHitsDBAndSetsCache(t *testing.T) {
 	r := &fakeRepo{
 		getOut: []*models.Tracking{{ID: 1, CarrierCode: "C", TrackNumber: "N", Status: "UNKNOWN"}},
@@ -140,17 +515,384 @@ func TestService_ApplyKafkaUpdate_buildsUpdate(t *testing.T) {
 		Events: []messages.TrackingEvent{
 			{Status: "IN_TRANSIT", StatusRaw: "RAW", EventTime: now},
 		},
+		Shipment: &messages.Shipment{EstimatedDelivery: &now, ServiceType: "express"},
 	}
 	require.NoError(t, s.ApplyKafkaUpdate(context.Background(), msg))
 	require.Equal(t, uint64(1), r.applyUpd.TrackingID)
 	require.Equal(t, "IN_TRANSIT", r.applyUpd.Status)
 	require.Len(t, r.applyUpd.Events, 1)
+	require.Equal(t, &models.ShipmentDetails{EstimatedDelivery: &now, ServiceType: "express"}, r.applyUpd.Shipment)
+	require.Equal(t, &models.TrackingCheck{TrackingID: 1, CheckedAt: now, Status: "IN_TRANSIT", EventsCount: 1}, r.applyUpd.Check)
+
+	// проверка без изменений: события не пересылаются, но в журнал — сколько их прислал перевозчик
+	boom := "carrier emulator http 502"
+	msg = messages.TrackingUpdated{
+		TrackingID: 1, CheckedAt: now, Kind: messages.KindCheckFailed, Error: &boom, NextCheckAt: now.Add(time.Minute),
+		Check: &messages.CheckInfo{Worker: "w1", Backend: models.CarrierBackendEmulatorV1, LatencyMS: 120, HTTPStatus: 502, ErrorClass: "HTTP", ResponseRef: "emulator_v1/ab/ab.json.gz"},
+	}
+	require.NoError(t, s.ApplyKafkaUpdate(context.Background(), msg))
+	require.Equal(t, &models.TrackingCheck{
+		TrackingID: 1, CheckedAt: now, Kind: messages.KindCheckFailed, Error: &boom, ErrorClass: "HTTP",
+		Worker: "w1", Backend: models.CarrierBackendEmulatorV1, LatencyMS: 120, HTTPStatus: 502, ResponseRef: "emulator_v1/ab/ab.json.gz",
+	}, r.applyUpd.Check)
+}
+
+type fakeInvalidator struct {
+	keys []string
+}
+
+func (f *fakeInvalidator) Publish(ctx context.Context, keys ...string) error {
+	f.keys = append(f.keys, keys...)
+	return nil
+}
+
+func TestService_publishesCacheInvalidations(t *testing.T) {
+	r := &fakeRepo{getOut: []*models.Tracking{{ID: 1}}}
+	inv := &fakeInvalidator{}
+	c := &fakeCache{m: map[string][]byte{}}
+	s := New(r, c, time.Minute).WithInvalidator(inv)
+	ctx := context.Background()
+
+	// дозагрузка промаха — не изменение
+	_, err := s.GetTrackingsByIDs(ctx, []uint64{1, 2})
+	require.NoError(t, err)
+	require.Empty(t, inv.keys)
+
+	require.NoError(t, s.ApplyKafkaUpdate(ctx, messages.TrackingUpdated{TrackingID: 1, CheckedAt: time.Now().UTC()}))
+	require.Equal(t, []string{currentKey(1)}, inv.keys)
+
+	inv.keys = nil
+	require.NoError(t, s.RefreshTracking(ctx, 2))
+	require.NoError(t, s.DeleteTracking(ctx, 3))
+	require.Equal(t, []string{currentKey(2), currentKey(3)}, inv.keys)
+}
+
+func TestService_ListTrackingChecks(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 123000, time.UTC)
+	r := &fakeRepo{checksOut: []*models.TrackingCheck{
+		{TrackingID: 1, CheckedAt: t0.Add(time.Minute)},
+		{TrackingID: 1, CheckedAt: t0},
+		{TrackingID: 1, CheckedAt: t0.Add(-time.Minute)},
+	}}
+	s := New(r, nil, 0)
+
+	out, next, err := s.ListTrackingChecks(context.Background(), 1, false, "", 2)
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	require.Equal(t, 3, r.checksLimit)
+	require.True(t, r.checksBefore.IsZero())
+
+	r.checksOut = r.checksOut[2:]
+	out, next2, err := s.ListTrackingChecks(context.Background(), 1, false, next, 2)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Empty(t, next2)
+	require.Equal(t, t0, r.checksBefore)
+
+	_, _, err = s.ListTrackingChecks(context.Background(), 1, false, "x", 0)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+	_, _, err = s.ListTrackingChecks(context.Background(), 0, false, "", 0)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestService_ListETAHistory(t *testing.T) {
+	prev := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	r := &fakeRepo{etaOut: []*models.ETAChange{{TrackingID: 1, EstimatedDelivery: prev.Add(36 * time.Hour), Previous: &prev}}}
+	s := New(r, nil, 0)
+
+	out, err := s.ListETAHistory(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, 36*time.Hour, out[0].Slip())
+
+	_, err = s.ListETAHistory(context.Background(), 0)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestService_ListEventRevisions(t *testing.T) {
+	loc := "Москва"
+	r := &fakeRepo{revisionsOut: []*models.EventRevision{{TrackingID: 1, EventID: 5, Kind: models.EventRevisionEdited, Previous: &models.TrackingEvent{StatusRaw: "SORTED", Location: &loc}}}}
+	s := New(r, nil, 0)
+
+	out, err := s.ListEventRevisions(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, models.EventRevisionEdited, out[0].Kind)
+
+	_, err = s.ListEventRevisions(context.Background(), 0)
+	require.ErrorIs(t, err, ErrInvalidArgument)
 }
 
 func TestService_ListTrackingEvents_passthrough(t *testing.T) {
 	r := &fakeRepo{}
 	s := New(r, nil, 0)
-	_, _ = s.ListTrackingEvents(context.Background(), 1, 10, 0)
+	_, _ = s.ListTrackingEvents(context.Background(), 1, pgtracking.EventFilter{}, 10, 0)
+}
+
+
+
+func TestService_CreateTrackings_attributes(t *testing.T) {
+	r := &fakeRepo{createOut: []*models.Tracking{{ID: 1}}}
+	s := New(r, nil, 0)
+
+	meta, ext := `{ "shop": "x" }`, " ORD-1 "
+	_, err := s.CreateTrackings(context.Background(), []models.TrackingCreateInput{
+		{CarrierCode: "CDEK", TrackNumber: "1234567890", Metadata: &meta, Tags: []string{" vip", "vip", "b2b"}, ExternalID: &ext},
+	})
+	require.NoError(t, err)
+	require.Len(t, r.createIn, 1)
+	require.Equal(t, `{"shop":"x"}`, *r.createIn[0].Metadata)
+	require.Equal(t, []string{"vip", "b2b"}, r.createIn[0].Tags)
+	require.Equal(t, "ORD-1", *r.createIn[0].ExternalID)
+
+	bad := "[1]"
+	_, err = s.CreateTrackings(context.Background(), []models.TrackingCreateInput{{CarrierCode: "CDEK", TrackNumber: "1234567890", Metadata: &bad}})
+	require.ErrorContains(t, err, "metadata must be a JSON object")
+}
+
+func TestService_UpdateTracking(t *testing.T) {
+	r := &fakeRepo{
+		getOut:    []*models.Tracking{{ID: 5, Tags: []string{"a"}}},
+		updateOut: &models.Tracking{ID: 5, Tags: []string{"a", "b"}},
+	}
+	c := &fakeCache{m: map[string][]byte{}}
+	s := New(r, c, time.Minute)
+
+	empty := ""
+	out, err := s.UpdateTracking(context.Background(), 5, models.TrackingPatch{Metadata: &empty, AddTags: []string{" b "}, RemoveTags: []string{" "}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, out.Tags)
+	require.Equal(t, uint64(5), r.updateID)
+	require.Equal(t, models.TrackingPatch{Metadata: &empty, AddTags: []string{"b"}}, r.updatePatch)
+	_, ok := c.m["tracking:5:current"]
+	require.True(t, ok)
+
+	_, err = s.UpdateTracking(context.Background(), 0, models.TrackingPatch{})
+	require.ErrorIs(t, err, ErrInvalidArgument)
+
+	bad := "nope"
+	_, err = s.UpdateTracking(context.Background(), 5, models.TrackingPatch{ExternalID: &bad, Metadata: &bad})
+	require.ErrorIs(t, err, ErrInvalidArgument)
+
+	many := make([]string, MaxTags)
+	for i := range many {
+		many[i] = strconv.Itoa(i)
+	}
+	_, err = s.UpdateTracking(context.Background(), 5, models.TrackingPatch{AddTags: many})
+	require.ErrorIs(t, err, ErrInvalidArgument)
+	require.ErrorContains(t, err, "too many tags")
+	// с clear_tags старые теги не считаются
+	_, err = s.UpdateTracking(context.Background(), 5, models.TrackingPatch{ClearTags: true, AddTags: many})
+	require.NoError(t, err)
+
+	r.getOut = nil
+	_, err = s.UpdateTracking(context.Background(), 6, models.TrackingPatch{AddTags: []string{"x"}})
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestService_ListTrackings(t *testing.T) {
+	r := &fakeRepo{scanOut: []*models.Tracking{{ID: 3}, {ID: 4}, {ID: 7}}}
+	s := New(r, nil, 0)
+
+	out, next, err := s.ListTrackings(context.Background(), pgtracking.TrackingFilter{CarrierCode: "сдэк", Tags: []string{" vip "}, Metadata: `{ "a": 1 }`}, "2", 2)
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	require.Equal(t, "4", next)
+	require.Equal(t, uint64(2), r.scanAfter)
+	require.Equal(t, 3, r.scanLimit)
+	require.Equal(t, pgtracking.TrackingFilter{CarrierCode: "CDEK", Tags: []string{"vip"}, Metadata: `{"a":1}`}, r.scanFilter)
+
+	r.scanOut = r.scanOut[:1]
+	_, next, err = s.ListTrackings(context.Background(), pgtracking.TrackingFilter{}, "", 0)
+	require.NoError(t, err)
+	require.Empty(t, next)
+	require.Equal(t, ListTrackingsPageSize+1, r.scanLimit)
+
+	_, _, err = s.ListTrackings(context.Background(), pgtracking.TrackingFilter{}, "x", 0)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+	_, _, err = s.ListTrackings(context.Background(), pgtracking.TrackingFilter{Metadata: "[]"}, "", 0)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestService_ListTrackingEventsPage(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	f := &fakeRepo{eventsOut: []*models.TrackingEvent{
+		{ID: 7, EventTime: t0.Add(2 * time.Hour)},
+		{ID: 5, EventTime: t0.Add(time.Hour)},
+		{ID: 3, EventTime: t0},
+	}}
+	s := New(f, nil, time.Minute)
+
+	page, err := s.ListTrackingEventsPage(context.Background(), 1, EventsQuery{
+		Filter:    pgtracking.EventFilter{Country: " RU ", From: t0},
+		PageSize:  2,
+		WithTotal: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Events, 2)
+	require.Equal(t, 3, f.pageLimit) // на одно больше — узнать, есть ли следующая страница
+	require.Equal(t, "RU", f.pageFilter.Country)
+	require.Equal(t, t0, f.pageFilter.From)
+	require.Nil(t, f.pageAfter)
+	require.EqualValues(t, 3, *page.TotalCount)
+	require.Equal(t, strconv.FormatInt(t0.Add(time.Hour).UnixMicro(), 10)+".5", page.NextPageToken)
+
+	page, err = s.ListTrackingEventsPage(context.Background(), 1, EventsQuery{Ascending: true, PageToken: page.NextPageToken})
+	require.NoError(t, err)
+	require.True(t, f.pageAsc)
+	require.Equal(t, &pgtracking.EventCursor{EventTime: t0.Add(time.Hour), ID: 5}, f.pageAfter)
+	require.Equal(t, ListTrackingEventsPageSize+1, f.pageLimit)
+	require.Empty(t, page.NextPageToken)
+	require.Nil(t, page.TotalCount)
+
+	for _, q := range []EventsQuery{
+		{PageToken: "abc"},
+		{PageToken: "123"},
+		{PageToken: "123.x"},
+		{Offset: 10, Ascending: true},
+		{PageSize: -1},
+		{Filter: pgtracking.EventFilter{From: t0, To: t0}},
+	} {
+		_, err := s.ListTrackingEventsPage(context.Background(), 1, q)
+		require.ErrorIs(t, err, ErrInvalidArgument, "%+v", q)
+	}
+	_, err = s.ListTrackingEventsPage(context.Background(), 0, EventsQuery{})
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestService_BatchListTrackingEvents(t *testing.T) {
+	since := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	f := &fakeRepo{latestOut: map[uint64][]*models.TrackingEvent{
+		3: {{ID: 30, TrackingID: 3}},
+		1: {{ID: 11, TrackingID: 1}, {ID: 10, TrackingID: 1}},
+	}}
+	s := New(f, nil, time.Minute)
+
+	res, err := s.BatchListTrackingEvents(context.Background(), []uint64{3, 2, 3, 1, 0}, 0, since)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3, 2, 1}, f.latestIDs)
+	require.Equal(t, BatchListEventsLimit, f.latestLimit)
+	require.Equal(t, since, f.latestSince)
+	require.Len(t, res, 3)
+	require.Equal(t, uint64(3), res[0].TrackingID)
+	require.Len(t, res[0].Events, 1)
+	require.Equal(t, uint64(2), res[1].TrackingID)
+	require.Empty(t, res[1].Events)
+	require.Len(t, res[2].Events, 2)
+
+	_, err = s.BatchListTrackingEvents(context.Background(), []uint64{1}, 1000, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, 10*BatchListEventsLimit, f.latestLimit)
+
+	tooMany := make([]uint64, BatchListEventsMaxIDs+1)
+	for i := range tooMany {
+		tooMany[i] = uint64(i + 1)
+	}
+	for _, ids := range [][]uint64{nil, {0}, tooMany} {
+		_, err := s.BatchListTrackingEvents(context.Background(), ids, 0, time.Time{})
+		require.ErrorIs(t, err, ErrInvalidArgument)
+	}
+	_, err = s.BatchListTrackingEvents(context.Background(), []uint64{1}, -1, time.Time{})
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestService_DeleteTracking(t *testing.T) {
+	f := &fakeRepo{}
+	c := &fakeCache{m: map[string][]byte{currentKey(7): []byte("{}")}}
+	s := New(f, c, time.Minute)
+
+	require.NoError(t, s.DeleteTracking(context.Background(), 7))
+	require.Equal(t, uint64(7), f.deleteID)
+	require.NotContains(t, c.m, currentKey(7))
+
+	f.deleteErr = ErrNotFound
+	require.ErrorIs(t, s.DeleteTracking(context.Background(), 8), ErrNotFound)
+	require.ErrorIs(t, s.DeleteTracking(context.Background(), 0), ErrInvalidArgument)
 }
 
+func TestService_ListChanges(t *testing.T) {
+	f := &fakeRepo{changesOut: []*models.Change{
+		{Seq: 3, Kind: models.ChangeTracking, Tracking: &models.Tracking{ID: 1}},
+		{Seq: 3, Kind: models.ChangeEvent, Event: &models.TrackingEvent{ID: 10, TrackingID: 1}},
+		{Seq: 4, Kind: models.ChangeDeleted, Deleted: &models.TrackingTombstone{TrackingID: 2}},
+	}}
+	s := New(f, nil, time.Minute)
 
+	page, err := s.ListChanges(context.Background(), "", 2)
+	require.NoError(t, err)
+	require.Equal(t, pgtracking.ChangeCursor{}, f.changesAfter)
+	require.Equal(t, 3, f.changesLimit) // на одну больше — узнать, есть ли ещё
+	require.Len(t, page.Changes, 2)
+	require.True(t, page.HasMore)
+	require.Equal(t, "3.2.10", page.NextCursor)
+
+	page, err = s.ListChanges(context.Background(), page.NextCursor, 0)
+	require.NoError(t, err)
+	require.Equal(t, pgtracking.ChangeCursor{Seq: 3, Kind: models.ChangeEvent, ID: 10}, f.changesAfter)
+	require.Equal(t, ListChangesPageSize+1, f.changesLimit)
+	require.False(t, page.HasMore)
+	require.Equal(t, "4.3.2", page.NextCursor)
+
+	// без новых изменений курсор не двигается
+	f.changesOut = nil
+	page, err = s.ListChanges(context.Background(), "4.3.2", 0)
+	require.NoError(t, err)
+	require.Empty(t, page.Changes)
+	require.Equal(t, "4.3.2", page.NextCursor)
+
+	for _, cursor := range []string{"abc", "1.2", "1.9.1", "-1.1.1", "1.1.x"} {
+		_, err := s.ListChanges(context.Background(), cursor, 0)
+		require.ErrorIs(t, err, ErrInvalidArgument, cursor)
+	}
+	_, err = s.ListChanges(context.Background(), "", -1)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+// nopCache ничего не хранит: каждое чтение — промах.
+type nopCache struct{}
+
+func (nopCache) Get(ctx context.Context, key string) ([]byte, bool, error) { return nil, false, nil }
+func (nopCache) MGet(ctx context.Context, keys []string) ([][]byte, error) {
+	return make([][]byte, len(keys)), nil
+}
+func (nopCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+func (nopCache) MSet(ctx context.Context, items []cache.Item) error { return nil }
+func (nopCache) Delete(ctx context.Context, keys ...string) error   { return nil }
+
+// countingRepo отвечает на GetTrackingsByIDs с задержкой запроса к БД и считает запросы.
+type countingRepo struct {
+	fakeRepo
+	calls atomic.Int64
+}
+
+func (r *countingRepo) GetTrackingsByIDs(ctx context.Context, ids []uint64) ([]*models.Tracking, error) {
+	r.calls.Add(1)
+	time.Sleep(time.Millisecond)
+	out := make([]*models.Tracking, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, &models.Tracking{ID: id})
+	}
+	return out, nil
+}
+
+// Промахи по одному «горячему» треку из многих горутин: одновременные промахи склеиваются,
+// db-calls/op показывает, какая доля запросов дошла до БД (без склейки было бы 1).
+func BenchmarkService_GetTrackingsByIDs_hotMiss(b *testing.B) {
+	r := &countingRepo{}
+	s := New(r, nopCache{}, time.Minute)
+	ctx := context.Background()
+
+	b.SetParallelism(16)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := s.GetTrackingsByIDs(ctx, []uint64{42}); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+	b.ReportMetric(float64(r.calls.Load())/float64(b.N), "db-calls/op")
+}
@@ -0,0 +1,219 @@
+package trackings
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/BearBump/TrackBox/internal/storage/pgtracking"
+	"github.com/pkg/errors"
+)
+
+// timelineEventsLimit — сколько последних событий трека учитывает таймлайн.
+const timelineEventsLimit = 500
+
+// Подсказки для определения этапа по тексту события (status_raw и message, в нижнем регистре).
+// Нормализованный статус у перевозчиков грубый (IN_TRANSIT / DELIVERED), поэтому этапы между ними — по тексту.
+// Подсказка совпадает только целыми словами («undelivered» — не «delivered») и не считается,
+// если перед ней стоит отрицание («не вручено», «not delivered»).
+var (
+	outForDeliveryHints = []string{"out for delivery", "передано курьеру", "передан курьеру", "выдано курьеру", "courier"}
+	arrivedHints        = []string{"прибыло в место вручения", "arrived at destination", "ожидает вручения", "готово к выдаче", "ready for pickup"}
+	deliveredHints      = []string{"вручено", "вручен", "delivered", "получено адресатом"}
+	acceptedHints       = []string{"принято", "принят", "прием", "accepted"}
+
+	negationWords = []string{"не", "not"}
+	// Начала слов, с которыми событие о вручении говорит о неудачной попытке («неудачная попытка вручения»).
+	failedDeliveryPrefixes = []string{"неудачн", "unsuccessful", "failed", "undeliver"}
+)
+
+// GetTrackingTimeline — этапы доставки трека и его события без повторов.
+func (s *Service) GetTrackingTimeline(ctx context.Context, trackingID uint64) (*models.Timeline, error) {
+	if trackingID == 0 {
+		return nil, errors.Wrap(ErrInvalidArgument, "trackingId is required")
+	}
+	ts, err := s.repo.GetTrackingsByIDs(ctx, []uint64{trackingID})
+	if err != nil {
+		return nil, err
+	}
+	if len(ts) == 0 {
+		return nil, ErrNotFound
+	}
+	evs, err := s.repo.ListTrackingEvents(ctx, trackingID, pgtracking.EventFilter{}, timelineEventsLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+	return buildTimeline(ts[0], evs, time.Now().UTC()), nil
+}
+
+// buildTimeline считает таймлайн по событиям трека (в любом порядке).
+//
+// Этап события определяется по статусу, тексту и месту (город назначения — из сведений об отправлении);
+// этапы только растут: событие «в пути» после прибытия в город назначения этап не откатывает.
+// Первое событие без явного этапа считается приёмом (ACCEPTED). Подряд идущие события с теми же status_raw, location и message склеиваются.
+func buildTimeline(t *models.Tracking, evs []*models.TrackingEvent, now time.Time) *models.Timeline {
+	sorted := slices.Clone(evs)
+	slices.SortStableFunc(sorted, func(a, b *models.TrackingEvent) int {
+		if c := a.EventTime.Compare(b.EventTime); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	tl := &models.Timeline{TrackingID: t.ID}
+	reached := make(map[string]*models.TrackingEvent, len(models.Milestones))
+	stage := -1
+	dest := destinationCity(t)
+	for _, e := range sorted {
+		st := stageIndex(classifyEvent(e, dest))
+		if stage < 0 && st == stageIndex(models.MilestoneInTransit) {
+			st = 0 // первое событие без явного этапа — приём отправления перевозчиком
+		}
+		stage = max(stage, st)
+		name := models.Milestones[stage]
+		if _, ok := reached[name]; !ok {
+			reached[name] = e
+		}
+
+		if n := len(tl.Entries); n > 0 && sameEntry(&tl.Entries[n-1], e) {
+			last := &tl.Entries[n-1]
+			last.LastAt = e.EventTime
+			last.EventIDs = append(last.EventIDs, e.ID)
+			last.Stage = name
+			continue
+		}
+		tl.Entries = append(tl.Entries, models.TimelineEntry{
+			Stage:     name,
+			Status:    e.Status,
+			StatusRaw: e.StatusRaw,
+			Location:  e.Location,
+			Message:   e.Message,
+			Place:     e.Place,
+			FirstAt:   e.EventTime,
+			LastAt:    e.EventTime,
+			EventIDs:  []uint64{e.ID},
+		})
+	}
+	if stage < 0 {
+		for _, name := range models.Milestones {
+			tl.Milestones = append(tl.Milestones, models.Milestone{Stage: name})
+		}
+		return tl
+	}
+	tl.CurrentStage = models.Milestones[stage]
+
+	var prev *time.Time
+	for i, name := range models.Milestones {
+		m := models.Milestone{Stage: name, Reached: i <= stage}
+		if e, ok := reached[name]; ok {
+			at := e.EventTime
+			m.ReachedAt, m.EventID = &at, e.ID
+			if prev != nil {
+				m.SincePrevious = at.Sub(*prev)
+			}
+			prev = &at
+		}
+		tl.Milestones = append(tl.Milestones, m)
+	}
+
+	end := now
+	if d, ok := reached[models.MilestoneDelivered]; ok {
+		end = d.EventTime
+	}
+	tl.Elapsed = max(end.Sub(sorted[0].EventTime), 0)
+	return tl
+}
+
+// classifyEvent — этап, о котором говорит само событие (без учёта предыдущих).
+func classifyEvent(e *models.TrackingEvent, destCity string) string {
+	if e.Status == models.TrackingStatusDelivered {
+		return models.MilestoneDelivered
+	}
+	text := eventWords(e.StatusRaw + " " + deref(e.Message))
+	switch {
+	case containsAny(text, outForDeliveryHints):
+		return models.MilestoneOutForDelivery
+	case containsAny(text, arrivedHints):
+		return models.MilestoneArrived
+	case containsAny(text, deliveredHints) && !hasWordPrefix(text, failedDeliveryPrefixes):
+		return models.MilestoneDelivered
+	case destCity != "" && e.Place != nil && normText(e.Place.City) == destCity:
+		return models.MilestoneArrived
+	case containsAny(text, acceptedHints):
+		return models.MilestoneAccepted
+	default:
+		return models.MilestoneInTransit
+	}
+}
+
+// destinationCity — город получателя (нормализованный); "" — неизвестен.
+func destinationCity(t *models.Tracking) string {
+	if t.Shipment == nil {
+		return ""
+	}
+	if t.Shipment.RecipientCity != "" {
+		return normText(t.Shipment.RecipientCity)
+	}
+	city, _, _ := strings.Cut(t.Shipment.Destination, ",")
+	return normText(city)
+}
+
+func sameEntry(last *models.TimelineEntry, e *models.TrackingEvent) bool {
+	return last.StatusRaw == e.StatusRaw && deref(last.Location) == deref(e.Location) && deref(last.Message) == deref(e.Message)
+}
+
+func stageIndex(name string) int {
+	return slices.Index(models.Milestones, name)
+}
+
+// containsAny — есть ли в тексте (из eventWords) одна из подсказок целыми словами и без отрицания перед ней.
+func containsAny(text string, hints []string) bool {
+	for _, h := range hints {
+		h = " " + h + " "
+		for from := 0; ; {
+			i := strings.Index(text[from:], h)
+			if i < 0 {
+				break
+			}
+			i += from
+			prev := text[strings.LastIndexByte(text[:i], ' ')+1 : i]
+			if !slices.Contains(negationWords, prev) {
+				return true
+			}
+			from = i + 1
+		}
+	}
+	return false
+}
+
+func hasWordPrefix(text string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.Contains(text, " "+p) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventWords — текст события словами через пробел (без знаков препинания), с пробелом в начале и в конце.
+func eventWords(s string) string {
+	s = strings.ReplaceAll(strings.ToLower(s), "ё", "е")
+	words := strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	return " " + strings.Join(words, " ") + " "
+}
+
+func normText(s string) string {
+	s = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "ё", "е")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
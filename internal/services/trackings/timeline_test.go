@@ -0,0 +1,121 @@
+package trackings
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+	ev := func(id uint64, at time.Duration, status, raw, loc string) *models.TrackingEvent {
+		return &models.TrackingEvent{ID: id, Status: status, StatusRaw: raw, EventTime: t0.Add(at), Location: str(loc), Message: str(raw)}
+	}
+	kzn := ev(4, 30*time.Hour, models.TrackingStatusInTransit, "Прибыло в сортировочный центр", "Казань МСЦ")
+	kzn.Place = &models.Location{City: "казань"}
+	evs := []*models.TrackingEvent{
+		ev(7, 50*time.Hour, models.TrackingStatusDelivered, "Вручено", "Отделение 420021"),
+		ev(6, 40*time.Hour, models.TrackingStatusInTransit, "Передано курьеру", "Казань"),
+		ev(5, 32*time.Hour, models.TrackingStatusInTransit, "Покинуло сортировочный центр", "Казань МСЦ"),
+		kzn,
+		ev(3, 12*time.Hour, models.TrackingStatusInTransit, "Покинуло сортировочный центр", "Москва МСЦ"),
+		ev(2, 11*time.Hour, models.TrackingStatusInTransit, "Покинуло сортировочный центр", "Москва МСЦ"),
+		ev(1, 0, models.TrackingStatusInTransit, "Приём", "Москва 101000"),
+	}
+	tr := &models.Tracking{ID: 9, Shipment: &models.ShipmentDetails{Destination: "Казань, RU"}}
+
+	tl := buildTimeline(tr, evs, t0.Add(100*time.Hour))
+	require.Equal(t, uint64(9), tl.TrackingID)
+	require.Equal(t, models.MilestoneDelivered, tl.CurrentStage)
+	require.Equal(t, 50*time.Hour, tl.Elapsed)
+
+	require.Len(t, tl.Milestones, 5)
+	got := make(map[string]uint64)
+	for _, m := range tl.Milestones {
+		require.True(t, m.Reached, m.Stage)
+		got[m.Stage] = m.EventID
+	}
+	require.Equal(t, map[string]uint64{
+		models.MilestoneAccepted:       1,
+		models.MilestoneInTransit:      2,
+		models.MilestoneArrived:        4,
+		models.MilestoneOutForDelivery: 6,
+		models.MilestoneDelivered:      7,
+	}, got)
+	require.Equal(t, 11*time.Hour, tl.Milestones[1].SincePrevious)
+	require.Equal(t, 19*time.Hour, tl.Milestones[2].SincePrevious)
+
+	// повтор «покинуло Москву» склеен; «в пути» после прибытия этап не откатывает
+	require.Len(t, tl.Entries, 6)
+	require.Equal(t, []uint64{2, 3}, tl.Entries[1].EventIDs)
+	require.Equal(t, t0.Add(11*time.Hour), tl.Entries[1].FirstAt)
+	require.Equal(t, t0.Add(12*time.Hour), tl.Entries[1].LastAt)
+	require.Equal(t, models.MilestoneArrived, tl.Entries[3].Stage)
+
+	// вручение распознаётся целыми словами и без отрицаний
+	for raw, want := range map[string]string{
+		"Undelivered":                     models.MilestoneInTransit,
+		"Not delivered: recipient absent": models.MilestoneInTransit,
+		"Не вручено, адресат отсутствует":        models.MilestoneInTransit,
+		"Неудачная попытка вручения":             models.MilestoneInTransit,
+		"Delivery failed, parcel delivered back": models.MilestoneInTransit,
+		"Вручено.":                               models.MilestoneDelivered,
+		"Вручен адресату":                        models.MilestoneDelivered,
+		"Delivered to recipient":                 models.MilestoneDelivered,
+	} {
+		tl := buildTimeline(tr, []*models.TrackingEvent{
+			ev(1, 0, models.TrackingStatusInTransit, "Приём", "Москва 101000"),
+			ev(2, time.Hour, models.TrackingStatusInTransit, "Покинуло сортировочный центр", "Москва МСЦ"),
+			ev(3, 2*time.Hour, models.TrackingStatusInTransit, raw, "Москва"),
+		}, t0.Add(3*time.Hour))
+		require.Equal(t, want, tl.CurrentStage, raw)
+	}
+	require.Equal(t, models.MilestoneAccepted, classifyEvent(&models.TrackingEvent{StatusRaw: "Принят в отделении"}, ""))
+	require.Equal(t, models.MilestoneInTransit, classifyEvent(&models.TrackingEvent{StatusRaw: "Не принято: неверный адрес"}, ""))
+}
+
+func TestBuildTimeline_SkippedAndInProgress(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	evs := []*models.TrackingEvent{
+		{ID: 1, StatusRaw: "CDEK: accepted", EventTime: t0},
+		{ID: 2, StatusRaw: "Ready for pickup", EventTime: t0.Add(24 * time.Hour)},
+	}
+	tl := buildTimeline(&models.Tracking{ID: 1}, evs, t0.Add(30*time.Hour))
+	require.Equal(t, models.MilestoneArrived, tl.CurrentStage)
+	require.Equal(t, 30*time.Hour, tl.Elapsed)
+	transit := tl.Milestones[1]
+	require.True(t, transit.Reached)
+	require.Nil(t, transit.ReachedAt)
+	require.Equal(t, 24*time.Hour, tl.Milestones[2].SincePrevious)
+	require.False(t, tl.Milestones[3].Reached)
+
+	empty := buildTimeline(&models.Tracking{ID: 1}, nil, t0)
+	require.Empty(t, empty.CurrentStage)
+	require.Len(t, empty.Milestones, 5)
+	require.Empty(t, empty.Entries)
+}
+
+func TestService_GetTrackingTimeline(t *testing.T) {
+	r := &fakeRepo{
+		getOut: []*models.Tracking{{ID: 3}},
+		eventsOut: []*models.TrackingEvent{
+			{ID: 1, Status: models.TrackingStatusInTransit, StatusRaw: "accepted", EventTime: time.Now().Add(-time.Hour)},
+		},
+	}
+	s := New(r, nil, 0)
+
+	tl, err := s.GetTrackingTimeline(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, models.MilestoneAccepted, tl.CurrentStage)
+
+	_, err = s.GetTrackingTimeline(context.Background(), 0)
+	require.ErrorIs(t, err, ErrInvalidArgument)
+
+	r.getOut = nil
+	_, err = s.GetTrackingTimeline(context.Background(), 3)
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,102 @@
+// Package archive — архив сырых ответов перевозчиков: тела ответов сжимаются gzip и кладутся в локальный
+// каталог (LocalFS) или S3-совместимое хранилище (S3, в docker compose — MinIO). Ключ считается по содержимому,
+// поэтому одинаковые ответы хранятся один раз, а ссылка из журнала проверок (tracking_checks.response_ref)
+// не зависит от бэкенда хранения.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Store — хранилище сжатых ответов.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	// Get возвращает ErrNotFound, если ключа нет.
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+var ErrNotFound = errors.New("archived response not found")
+
+// maxUncompressedBytes — больше этого ответ при чтении не распаковывается (ответы пишутся не больше
+// carrier.MaxResponseBytes, запас — на случай чужих объектов в бакете).
+const maxUncompressedBytes = 16 << 20
+
+type Archive struct {
+	store Store
+}
+
+func New(store Store) *Archive {
+	return &Archive{store: store}
+}
+
+// Key — ключ ответа: бэкенд перевозчика (models.CarrierBackend*), первые два символа хэша (чтобы не держать
+// все файлы в одном каталоге) и sha256 тела.
+func Key(backend string, body []byte) string {
+	if backend == "" {
+		backend = "unknown"
+	}
+	sum := sha256.Sum256(body)
+	h := hex.EncodeToString(sum[:])
+	return backend + "/" + h[:2] + "/" + h + ".json.gz"
+}
+
+// Save сжимает и сохраняет тело ответа, возвращает ссылку для журнала проверок.
+func (a *Archive) Save(ctx context.Context, backend string, body []byte) (string, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return "", errors.Wrap(err, "gzip response")
+	}
+	if err := zw.Close(); err != nil {
+		return "", errors.Wrap(err, "gzip response")
+	}
+	key := Key(backend, body)
+	if err := a.store.Put(ctx, key, buf.Bytes()); err != nil {
+		return "", errors.Wrapf(err, "archive response %s", key)
+	}
+	return key, nil
+}
+
+// Load читает и распаковывает ответ по ссылке из журнала проверок.
+func (a *Archive) Load(ctx context.Context, ref string) ([]byte, error) {
+	if err := validKey(ref); err != nil {
+		return nil, err
+	}
+	data, err := a.store.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "gunzip response %s", ref)
+	}
+	body, err := io.ReadAll(io.LimitReader(zr, maxUncompressedBytes+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "gunzip response %s", ref)
+	}
+	if len(body) > maxUncompressedBytes {
+		return nil, errors.Errorf("archived response %s is larger than %d bytes", ref, maxUncompressedBytes)
+	}
+	return body, nil
+}
+
+// validKey не пускает ключи, которые вывели бы за пределы каталога или бакета.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return errors.Errorf("invalid archive key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return errors.Errorf("invalid archive key %q", key)
+		}
+	}
+	return nil
+}
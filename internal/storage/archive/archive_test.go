@@ -0,0 +1,51 @@
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive_LocalFS(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalFS(dir)
+	require.NoError(t, err)
+	a := New(store)
+	ctx := context.Background()
+
+	body := []byte(`{"status":"IN_TRANSIT","events":[]}`)
+	ref, err := a.Save(ctx, "emulator_v1", body)
+	require.NoError(t, err)
+	require.Equal(t, Key("emulator_v1", body), ref)
+	require.True(t, strings.HasPrefix(ref, "emulator_v1/"))
+	require.True(t, strings.HasSuffix(ref, ".json.gz"))
+
+	// Тот же ответ — тот же ключ, повторная запись не ошибка.
+	again, err := a.Save(ctx, "emulator_v1", body)
+	require.NoError(t, err)
+	require.Equal(t, ref, again)
+
+	stored, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(ref)))
+	require.NoError(t, err)
+	require.NotEqual(t, body, stored) // сжато
+
+	got, err := a.Load(ctx, ref)
+	require.NoError(t, err)
+	require.Equal(t, body, got)
+
+	_, err = a.Load(ctx, Key("emulator_v1", []byte("other")))
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestValidKey(t *testing.T) {
+	require.NoError(t, validKey("track24/ab/abcdef.json.gz"))
+	for _, k := range []string{"", "/etc/passwd", "../x", "a/../../x", "a//b", `a\b`, "a/./b"} {
+		require.Error(t, validKey(k), k)
+	}
+	_, err := New(&LocalFS{dir: t.TempDir()}).Load(context.Background(), "../secret")
+	require.Error(t, err)
+}
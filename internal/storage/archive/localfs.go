@@ -0,0 +1,60 @@
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// LocalFS хранит ответы файлами в каталоге (один узел или общий том).
+type LocalFS struct {
+	dir string
+}
+
+func NewLocalFS(dir string) (*LocalFS, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "create archive dir")
+	}
+	return &LocalFS{dir: dir}, nil
+}
+
+// Put пишет через временный файл и rename, так что читатель не увидит недописанный ответ.
+// Ключ — по содержимому, поэтому существующий файл не перезаписывается.
+func (s *LocalFS) Put(_ context.Context, key string, data []byte) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.Wrap(err, "create archive dir")
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "create archive file")
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "write archive file")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "write archive file")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), path), "rename archive file")
+}
+
+func (s *LocalFS) Get(_ context.Context, key string) ([]byte, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.Wrap(ErrNotFound, key)
+	}
+	return b, errors.Wrap(err, "read archive file")
+}
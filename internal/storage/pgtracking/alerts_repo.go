@@ -0,0 +1,190 @@
+package pgtracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+// alertStateExpr — состояние алерта (OPEN | RESOLVED) в SQL; то же выражение в частичном индексе.
+const alertStateExpr = `(CASE WHEN resolved_at IS NULL THEN 'OPEN' ELSE 'RESOLVED' END)`
+
+const alertColumns = `
+  a.id, a.tracking_id, t.carrier_code, t.track_number, a.kind, a.rule, a.details, a.opened_at, a.resolved_at`
+
+func scanAlert(row pgx.Row) (*models.Alert, error) {
+	var a models.Alert
+	if err := row.Scan(&a.ID, &a.TrackingID, &a.CarrierCode, &a.TrackNumber, &a.Kind, &a.Rule, &a.Details, &a.OpenedAt, &a.ResolvedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// slaLockKey — ключ advisory-блокировки прохода оценки SLA (см. TryLockSLA).
+const slaLockKey = "sla:evaluate"
+
+// TryLockSLA берёт сессионную advisory-блокировку прохода SLA на отдельном соединении, не дожидаясь её:
+// false — проход уже идёт в другом воркере. unlock снимает блокировку и возвращает соединение в пул.
+func (s *Storage) TryLockSLA(ctx context.Context) (unlock func(), ok bool, err error) {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "acquire conn")
+	}
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, slaLockKey).Scan(&ok); err != nil {
+		conn.Release()
+		return nil, false, errors.Wrap(err, "lock sla")
+	}
+	if !ok {
+		conn.Release()
+		return nil, false, nil
+	}
+	return func() {
+		// ctx прохода к этому моменту может быть отменён; если снять блокировку не вышло,
+		// соединение закрывается — сессионная блокировка уходит вместе с ним.
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, slaLockKey); err != nil {
+			_ = conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}, true, nil
+}
+
+// ListSLASubjects отдаёт недоставленные треки по возрастанию id, начиная после afterID, со сводкой
+// по активным событиям и видами уже открытых алертов.
+func (s *Storage) ListSLASubjects(ctx context.Context, afterID uint64, limit int) ([]*models.SLASubject, error) {
+	rows, err := s.db.Query(ctx, `
+SELECT ev.first_at, ev.last_at, COALESCE(al.kinds, '{}'),`+trackingColumns+`
+FROM trackings
+LEFT JOIN LATERAL (
+  SELECT min(e.event_time) AS first_at, max(e.event_time) AS last_at
+  FROM tracking_events e
+  WHERE e.tracking_id = trackings.id AND e.removed_at IS NULL
+    AND e.event_time BETWEEN trackings.event_time_min AND trackings.event_time_max
+) ev ON true
+LEFT JOIN LATERAL (
+  SELECT array_agg(a.kind ORDER BY a.kind) AS kinds
+  FROM alerts a
+  WHERE a.tracking_id = trackings.id AND a.resolved_at IS NULL
+) al ON true
+WHERE trackings.id > $1 AND trackings.status <> $3
+ORDER BY trackings.id ASC
+LIMIT $2
+`, afterID, limit, models.TrackingStatusDelivered)
+	if err != nil {
+		return nil, errors.Wrap(err, "select sla subjects")
+	}
+	defer rows.Close()
+
+	var out []*models.SLASubject
+	for rows.Next() {
+		var sub models.SLASubject
+		t, err := scanTracking(rows, &sub.FirstEventAt, &sub.LastEventAt, &sub.OpenKinds)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan sla subject")
+		}
+		sub.Tracking = t
+		out = append(out, &sub)
+	}
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows")
+	}
+	return out, nil
+}
+
+// OpenAlert открывает алерт; false — открытый алерт этого вида у трека уже есть.
+func (s *Storage) OpenAlert(ctx context.Context, a models.Alert) (bool, error) {
+	tag, err := s.db.Exec(ctx, `
+INSERT INTO alerts (tracking_id, kind, rule, details, opened_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (tracking_id, kind) WHERE resolved_at IS NULL DO NOTHING
+`, a.TrackingID, a.Kind, a.Rule, a.Details, a.OpenedAt.UTC())
+	if err != nil {
+		return false, errors.Wrap(err, "insert alert")
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ResolveAlerts закрывает открытые алерты трека перечисленных видов.
+func (s *Storage) ResolveAlerts(ctx context.Context, trackingID uint64, kinds []string, at time.Time) error {
+	if len(kinds) == 0 {
+		return nil
+	}
+	_, err := s.db.Exec(ctx, `
+UPDATE alerts SET resolved_at = $3
+WHERE tracking_id = $1 AND kind = ANY($2) AND resolved_at IS NULL
+`, trackingID, kinds, at.UTC())
+	return errors.Wrap(err, "resolve alerts")
+}
+
+// ResolveDeliveredAlerts закрывает открытые алерты доставленных треков и возвращает их число.
+func (s *Storage) ResolveDeliveredAlerts(ctx context.Context, at time.Time) (int64, error) {
+	tag, err := s.db.Exec(ctx, `
+UPDATE alerts a SET resolved_at = $1
+FROM trackings t
+WHERE t.id = a.tracking_id AND t.status = $2 AND a.resolved_at IS NULL
+`, at.UTC(), models.TrackingStatusDelivered)
+	if err != nil {
+		return 0, errors.Wrap(err, "resolve delivered alerts")
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ListAlerts отдаёт алерты по фильтру от новых к старым, начиная с id < beforeID (0 — с самого нового).
+func (s *Storage) ListAlerts(ctx context.Context, f models.AlertFilter, beforeID uint64, limit int) ([]*models.Alert, error) {
+	rows, err := s.db.Query(ctx, `
+SELECT`+alertColumns+`
+FROM alerts a
+JOIN trackings t ON t.id = a.tracking_id
+WHERE ($1 = 0 OR a.id < $1)
+  AND ($2 = '' OR `+alertStateExpr+` = $2)
+  AND ($3 = 0 OR a.tracking_id = $3)
+  AND ($4 = '' OR a.kind = $4)
+  AND ($5 = '' OR t.carrier_code = $5)
+ORDER BY a.id DESC
+LIMIT $6
+`, beforeID, f.State, f.TrackingID, f.Kind, f.CarrierCode, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "select alerts")
+	}
+	return collectAlerts(rows)
+}
+
+// ListUnpublishedAlerts — алерты, чьё текущее состояние ещё не ушло в Kafka, по возрастанию id.
+func (s *Storage) ListUnpublishedAlerts(ctx context.Context, limit int) ([]*models.Alert, error) {
+	rows, err := s.db.Query(ctx, `
+SELECT`+alertColumns+`
+FROM alerts a
+JOIN trackings t ON t.id = a.tracking_id
+WHERE a.published_state IS DISTINCT FROM `+alertStateExpr+`
+ORDER BY a.id ASC
+LIMIT $1
+`, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "select unpublished alerts")
+	}
+	return collectAlerts(rows)
+}
+
+// MarkAlertPublished запоминает, что состояние state алерта опубликовано.
+func (s *Storage) MarkAlertPublished(ctx context.Context, id uint64, state string) error {
+	_, err := s.db.Exec(ctx, `UPDATE alerts SET published_state = $2 WHERE id = $1`, id, state)
+	return errors.Wrap(err, "mark alert published")
+}
+
+func collectAlerts(rows pgx.Rows) ([]*models.Alert, error) {
+	defer rows.Close()
+	var out []*models.Alert
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan alert")
+		}
+		out = append(out, a)
+	}
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows")
+	}
+	return out, nil
+}
@@ -0,0 +1,276 @@
+package pgtracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+// Агрегаты аналитики:
+//   - carrier_status_counts — сколько треков перевозчика сейчас в каждом статусе;
+//   - carrier_daily_stats — проверки, ошибки, смены статуса и доставки по дням;
+//   - carrier_status_daily — переходы в статус по дням;
+//   - carrier_transit_histogram — гистограмма времени доставки (корзины models.TransitBucketBounds) по дню доставки.
+//
+// Строки агрегатов блокируются всегда в одном порядке (status_counts → daily_stats → status_daily → histogram,
+// внутри таблицы — по ключу), чтобы конкурентные транзакции не ловили deadlock.
+
+// trackingBefore — состояние трека до применения проверки.
+type trackingBefore struct {
+	carrierCode   string
+	status        string
+	lastCheckedAt *time.Time
+	createdAt     time.Time
+}
+
+// lockTrackingBefore блокирует строку трека и читает её до UPDATE; nil — трека нет.
+func lockTrackingBefore(ctx context.Context, tx pgx.Tx, id uint64) (*trackingBefore, error) {
+	var b trackingBefore
+	err := tx.QueryRow(ctx, `
+SELECT carrier_code, status, last_checked_at, created_at FROM trackings WHERE id = $1 FOR UPDATE
+`, id).Scan(&b.carrierCode, &b.status, &b.lastCheckedAt, &b.createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "lock tracking")
+	}
+	return &b, nil
+}
+
+// applyRollups учитывает проверку в агрегатах. Вызывается в транзакции ApplyTrackingUpdate после
+// обновления трека и событий. Текущие статусы (carrier_status_counts) всегда следуют за строкой трека;
+// дневные агрегаты не считают повтор той же проверки (Kafka at-least-once, CheckTrackingNow + сообщение
+// воркера) — он узнаётся по last_checked_at.
+func applyRollups(ctx context.Context, tx pgx.Tx, prev *trackingBefore, upd TrackingUpdate) error {
+	if prev == nil {
+		return nil
+	}
+	failed := upd.Error != nil && *upd.Error != ""
+	changed := !failed && upd.Status != prev.status
+	if changed {
+		_, err := tx.Exec(ctx, `
+INSERT INTO carrier_status_counts (carrier_code, status, trackings)
+SELECT $1, st, d FROM unnest($2::text[], $3::bigint[]) AS u(st, d)
+ORDER BY st
+ON CONFLICT (carrier_code, status) DO UPDATE SET trackings = carrier_status_counts.trackings + EXCLUDED.trackings
+`, prev.carrierCode, []string{prev.status, upd.Status}, []int64{-1, 1})
+		if err != nil {
+			return errors.Wrap(err, "upsert carrier status counts")
+		}
+	}
+	if prev.lastCheckedAt != nil && !upd.CheckedAt.After(*prev.lastCheckedAt) {
+		return nil
+	}
+
+	delivered := changed && upd.Status == models.TrackingStatusDelivered
+	day := utcDay(upd.CheckedAt)
+	_, err := tx.Exec(ctx, `
+INSERT INTO carrier_daily_stats (day, carrier_code, checks, failed_checks, status_changes, delivered)
+VALUES ($1, $2, 1, $3, $4, $5)
+ON CONFLICT (day, carrier_code) DO UPDATE SET
+  checks = carrier_daily_stats.checks + 1,
+  failed_checks = carrier_daily_stats.failed_checks + EXCLUDED.failed_checks,
+  status_changes = carrier_daily_stats.status_changes + EXCLUDED.status_changes,
+  delivered = carrier_daily_stats.delivered + EXCLUDED.delivered
+`, day, prev.carrierCode, b2i(failed), b2i(changed), b2i(delivered))
+	if err != nil {
+		return errors.Wrap(err, "upsert carrier daily stats")
+	}
+	if !changed {
+		return nil
+	}
+	_, err = tx.Exec(ctx, `
+INSERT INTO carrier_status_daily (day, carrier_code, status, entered)
+VALUES ($1, $2, $3, 1)
+ON CONFLICT (day, carrier_code, status) DO UPDATE SET entered = carrier_status_daily.entered + 1
+`, day, prev.carrierCode, upd.Status)
+	if err != nil {
+		return errors.Wrap(err, "upsert carrier status daily")
+	}
+	if !delivered {
+		return nil
+	}
+
+	// Время доставки — от первого события (без событий — от создания трека) до статуса «доставлен».
+	var start time.Time
+	err = tx.QueryRow(ctx, `
+SELECT COALESCE(min(event_time), $2) FROM tracking_events WHERE tracking_id = $1 AND `+eventSpan+` AND removed_at IS NULL
+`, upd.TrackingID, prev.createdAt).Scan(&start)
+	if err != nil {
+		return errors.Wrap(err, "select first event time")
+	}
+	deliveredAt := upd.CheckedAt
+	if upd.StatusAt != nil {
+		deliveredAt = *upd.StatusAt
+	}
+	_, err = tx.Exec(ctx, `
+INSERT INTO carrier_transit_histogram (day, carrier_code, bucket, deliveries)
+VALUES ($1, $2, $3, 1)
+ON CONFLICT (day, carrier_code, bucket) DO UPDATE SET deliveries = carrier_transit_histogram.deliveries + 1
+`, day, prev.carrierCode, models.TransitBucketIndex(deliveredAt.Sub(start)))
+	return errors.Wrap(err, "upsert carrier transit histogram")
+}
+
+func b2i(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// backfillRollups заполняет агрегаты по уже существующим трекам при первом запуске с аналитикой
+// (пока carrier_status_counts пуста): текущие статусы и доставки с гистограммой времени доставки.
+// Проверки и ошибки в прошлом не восстановить — они считаются с момента обновления.
+func (s *Storage) backfillRollups(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+INSERT INTO carrier_status_counts (carrier_code, status, trackings)
+SELECT carrier_code, status, count(*) FROM trackings
+WHERE NOT EXISTS (SELECT 1 FROM carrier_status_counts)
+GROUP BY carrier_code, status
+ORDER BY carrier_code, status
+ON CONFLICT (carrier_code, status) DO NOTHING
+`)
+	if err != nil {
+		return errors.Wrap(err, "backfill carrier status counts")
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+WITH d AS (
+  SELECT t.carrier_code,
+         COALESCE(t.status_at, t.last_checked_at, t.updated_at) AS delivered_at,
+         COALESCE((SELECT min(e.event_time) FROM tracking_events e
+                   WHERE e.tracking_id = t.id AND e.event_time BETWEEN t.event_time_min AND t.event_time_max AND e.removed_at IS NULL), t.created_at) AS started_at
+  FROM trackings t
+  WHERE t.status = $1
+), h AS (
+  INSERT INTO carrier_transit_histogram (day, carrier_code, bucket, deliveries)
+  SELECT (delivered_at AT TIME ZONE 'UTC')::date, carrier_code,
+         GREATEST(width_bucket((EXTRACT(EPOCH FROM delivered_at - started_at) / 3600)::float8, $2::float8[]) - 1, 0),
+         count(*)
+  FROM d
+  GROUP BY 1, 2, 3
+  ON CONFLICT (day, carrier_code, bucket) DO NOTHING
+)
+INSERT INTO carrier_daily_stats (day, carrier_code, delivered)
+SELECT (delivered_at AT TIME ZONE 'UTC')::date, carrier_code, count(*)
+FROM d
+GROUP BY 1, 2
+ON CONFLICT (day, carrier_code) DO NOTHING
+`, models.TrackingStatusDelivered, models.TransitBucketBounds)
+	if err != nil {
+		return errors.Wrap(err, "backfill delivered rollups")
+	}
+	return errors.Wrap(tx.Commit(ctx), "commit tx")
+}
+
+// StatusCounts — сколько треков сейчас в каждом статусе, по перевозчикам; пустой carrierCode — все.
+func (s *Storage) StatusCounts(ctx context.Context, carrierCode string) ([]models.StatusCount, error) {
+	rows, err := s.db.Query(ctx, `
+SELECT carrier_code, status, trackings FROM carrier_status_counts
+WHERE ($1 = '' OR carrier_code = $1) AND trackings > 0
+ORDER BY carrier_code, status
+`, carrierCode)
+	if err != nil {
+		return nil, errors.Wrap(err, "select carrier status counts")
+	}
+	defer rows.Close()
+
+	var out []models.StatusCount
+	for rows.Next() {
+		var c models.StatusCount
+		if err := rows.Scan(&c.CarrierCode, &c.Status, &c.Trackings); err != nil {
+			return nil, errors.Wrap(err, "scan status count")
+		}
+		out = append(out, c)
+	}
+	return out, errors.Wrap(rows.Err(), "rows")
+}
+
+// DailyStats — дневные срезы за дни [from, to], по дню и перевозчику.
+func (s *Storage) DailyStats(ctx context.Context, carrierCode string, from, to time.Time) ([]models.CarrierDayStats, error) {
+	rows, err := s.db.Query(ctx, `
+SELECT day, carrier_code, checks, failed_checks, status_changes, delivered FROM carrier_daily_stats
+WHERE day BETWEEN $2 AND $3 AND ($1 = '' OR carrier_code = $1)
+ORDER BY day, carrier_code
+`, carrierCode, utcDay(from), utcDay(to))
+	if err != nil {
+		return nil, errors.Wrap(err, "select carrier daily stats")
+	}
+	defer rows.Close()
+
+	var out []models.CarrierDayStats
+	for rows.Next() {
+		var d models.CarrierDayStats
+		if err := rows.Scan(&d.Day, &d.CarrierCode, &d.Checks, &d.FailedChecks, &d.StatusChanges, &d.Delivered); err != nil {
+			return nil, errors.Wrap(err, "scan carrier daily stats")
+		}
+		out = append(out, d)
+	}
+	return out, errors.Wrap(rows.Err(), "rows")
+}
+
+// StatusEntries — переходы в статусы за дни [from, to], по дню, перевозчику и статусу.
+func (s *Storage) StatusEntries(ctx context.Context, carrierCode string, from, to time.Time) ([]models.StatusEntries, error) {
+	rows, err := s.db.Query(ctx, `
+SELECT day, carrier_code, status, entered FROM carrier_status_daily
+WHERE day BETWEEN $2 AND $3 AND ($1 = '' OR carrier_code = $1)
+ORDER BY day, carrier_code, status
+`, carrierCode, utcDay(from), utcDay(to))
+	if err != nil {
+		return nil, errors.Wrap(err, "select carrier status daily")
+	}
+	defer rows.Close()
+
+	var out []models.StatusEntries
+	for rows.Next() {
+		var e models.StatusEntries
+		if err := rows.Scan(&e.Day, &e.CarrierCode, &e.Status, &e.Entered); err != nil {
+			return nil, errors.Wrap(err, "scan status entries")
+		}
+		out = append(out, e)
+	}
+	return out, errors.Wrap(rows.Err(), "rows")
+}
+
+// TransitHistogram — гистограмма времени доставки за дни доставки [from, to].
+func (s *Storage) TransitHistogram(ctx context.Context, carrierCode string, from, to time.Time) ([]models.TransitHistogramRow, error) {
+	rows, err := s.db.Query(ctx, `
+SELECT day, carrier_code, bucket, deliveries FROM carrier_transit_histogram
+WHERE day BETWEEN $2 AND $3 AND ($1 = '' OR carrier_code = $1)
+ORDER BY day, carrier_code, bucket
+`, carrierCode, utcDay(from), utcDay(to))
+	if err != nil {
+		return nil, errors.Wrap(err, "select carrier transit histogram")
+	}
+	defer rows.Close()
+
+	var out []models.TransitHistogramRow
+	for rows.Next() {
+		var h models.TransitHistogramRow
+		var bucket int16
+		if err := rows.Scan(&h.Day, &h.CarrierCode, &bucket, &h.Deliveries); err != nil {
+			return nil, errors.Wrap(err, "scan transit histogram")
+		}
+		h.Bucket = int(bucket)
+		out = append(out, h)
+	}
+	return out, errors.Wrap(rows.Err(), "rows")
+}
+
+// utcDay — дата (UTC) как time.Time в полночь; pgx передаёт её в DATE.
+func utcDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
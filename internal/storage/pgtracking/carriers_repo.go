@@ -0,0 +1,105 @@
+package pgtracking
+
+import (
+	"context"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrCarrierNotFound — перевозчика с таким кодом нет.
+	ErrCarrierNotFound = errors.New("carrier not found")
+	// ErrCarrierExists — перевозчик с таким кодом уже заведён.
+	ErrCarrierExists = errors.New("carrier already exists")
+)
+
+const carrierColumns = `
+  code, display_name, backend, credentials_ref, rate_limit_per_minute,
+  scheduling::text, enabled, created_at, updated_at`
+
+func scanCarrier(row pgx.Row) (*models.Carrier, error) {
+	var c models.Carrier
+	err := row.Scan(
+		&c.Code, &c.DisplayName, &c.Backend, &c.CredentialsRef, &c.RateLimitPerMinute,
+		&c.SchedulingJSON, &c.Enabled, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListCarriers — весь справочник перевозчиков (включая отключённых), по коду.
+func (s *Storage) ListCarriers(ctx context.Context) ([]*models.Carrier, error) {
+	rows, err := s.db.Query(ctx, `SELECT`+carrierColumns+` FROM carriers ORDER BY code`)
+	if err != nil {
+		return nil, errors.Wrap(err, "select carriers")
+	}
+	defer rows.Close()
+
+	out := make([]*models.Carrier, 0)
+	for rows.Next() {
+		c, err := scanCarrier(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan carrier")
+		}
+		out = append(out, c)
+	}
+	return out, errors.Wrap(rows.Err(), "rows")
+}
+
+func (s *Storage) GetCarrier(ctx context.Context, code string) (*models.Carrier, error) {
+	c, err := scanCarrier(s.db.QueryRow(ctx, `SELECT`+carrierColumns+` FROM carriers WHERE code = $1`, code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCarrierNotFound
+	}
+	return c, errors.Wrap(err, "select carrier")
+}
+
+func (s *Storage) CreateCarrier(ctx context.Context, c models.Carrier) (*models.Carrier, error) {
+	out, err := scanCarrier(s.db.QueryRow(ctx, `
+INSERT INTO carriers (code, display_name, backend, credentials_ref, rate_limit_per_minute, scheduling, enabled, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7, now(), now())
+RETURNING`+carrierColumns,
+		c.Code, c.DisplayName, c.Backend, c.CredentialsRef, c.RateLimitPerMinute, c.SchedulingJSON, c.Enabled))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return nil, ErrCarrierExists
+	}
+	return out, errors.Wrap(err, "insert carrier")
+}
+
+// UpdateCarrier меняет только перечисленные поля перевозчика (models.CarrierField*), одним UPDATE —
+// параллельные изменения других полей не затираются.
+func (s *Storage) UpdateCarrier(ctx context.Context, c models.Carrier, fields []string) (*models.Carrier, error) {
+	out, err := scanCarrier(s.db.QueryRow(ctx, `
+UPDATE carriers
+SET display_name          = CASE WHEN 'display_name' = ANY($8::text[]) THEN $2 ELSE display_name END,
+    backend               = CASE WHEN 'backend' = ANY($8::text[]) THEN $3 ELSE backend END,
+    credentials_ref       = CASE WHEN 'credentials_ref' = ANY($8::text[]) THEN $4 ELSE credentials_ref END,
+    rate_limit_per_minute = CASE WHEN 'rate_limit_per_minute' = ANY($8::text[]) THEN $5 ELSE rate_limit_per_minute END,
+    scheduling            = CASE WHEN 'scheduling_json' = ANY($8::text[]) THEN $6::jsonb ELSE scheduling END,
+    enabled               = CASE WHEN 'enabled' = ANY($8::text[]) THEN $7 ELSE enabled END,
+    updated_at            = now()
+WHERE code = $1
+RETURNING`+carrierColumns,
+		c.Code, c.DisplayName, c.Backend, c.CredentialsRef, c.RateLimitPerMinute, c.SchedulingJSON, c.Enabled, fields))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCarrierNotFound
+	}
+	return out, errors.Wrap(err, "update carrier")
+}
+
+func (s *Storage) SetCarrierEnabled(ctx context.Context, code string, enabled bool) (*models.Carrier, error) {
+	out, err := scanCarrier(s.db.QueryRow(ctx, `
+UPDATE carriers SET enabled = $2, updated_at = now()
+WHERE code = $1
+RETURNING`+carrierColumns, code, enabled))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCarrierNotFound
+	}
+	return out, errors.Wrap(err, "set carrier enabled")
+}
@@ -0,0 +1,201 @@
+package pgtracking
+
+import (
+	"context"
+	"sort"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+// Лента изменений (ListChanges) для зеркал TrackBox: треки, события и удалённые треки отдаются по возрастанию
+// номера изменения change_seq. Номер берётся из счётчика tracking_change_counter последним запросом
+// транзакции: строка счётчика остаётся заблокированной до COMMIT, поэтому номера фиксируются строго по порядку
+// и читатель не увидит изменение N+1 раньше N — курсор ленты ничего не пропускает. Цена — записи с номером
+// изменения фиксируются по одной (блокировка держится только на время COMMIT).
+//
+// Номер получают ApplyTrackingUpdate, создание и изменение атрибутов трека и DeleteTracking. Смена расписания
+// проверок (next_check_at: RefreshTracking, аренда воркером) и удаление событий по срокам хранения в ленту
+// не попадают.
+
+// bumpChangeSeq — CTE со следующим номером изменения.
+const bumpChangeSeq = `bump AS (UPDATE tracking_change_counter SET seq = seq + 1 RETURNING seq)`
+
+// markChanged проставляет следующий номер изменения треку и его событиям, изменённым в транзакции
+// (change_seq IS NULL). Вызывается последним перед COMMIT: счётчик блокируется после строк трека и агрегатов,
+// в одном порядке во всех транзакциях — иначе возможны взаимоблокировки.
+func markChanged(ctx context.Context, tx pgx.Tx, trackingID uint64) error {
+	_, err := tx.Exec(ctx, `
+WITH `+bumpChangeSeq+`, events AS (
+  UPDATE tracking_events SET change_seq = (SELECT seq FROM bump)
+  WHERE tracking_id = $1 AND `+eventSpan+` AND change_seq IS NULL
+)
+UPDATE trackings SET change_seq = (SELECT seq FROM bump) WHERE id = $1
+`, trackingID)
+	return errors.Wrap(err, "mark tracking changed")
+}
+
+// DeleteTracking удаляет трек со всеми событиями и историей и оставляет отметку об удалении для ленты изменений.
+func (s *Storage) DeleteTracking(ctx context.Context, id uint64) error {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	prev, err := lockTrackingBefore(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if prev == nil {
+		return ErrTrackingNotFound
+	}
+	// События — по границам трека, чтобы не обходить все секции; остальное удалит ON DELETE CASCADE.
+	if _, err := tx.Exec(ctx, `DELETE FROM tracking_events WHERE tracking_id = $1 AND `+eventSpan, id); err != nil {
+		return errors.Wrap(err, "delete tracking events")
+	}
+	var ts models.TrackingTombstone
+	err = tx.QueryRow(ctx, `
+DELETE FROM trackings WHERE id = $1
+RETURNING id, carrier_code, track_number, external_id, now()
+`, id).Scan(&ts.TrackingID, &ts.CarrierCode, &ts.TrackNumber, &ts.ExternalID, &ts.DeletedAt)
+	if err != nil {
+		return errors.Wrap(err, "delete tracking")
+	}
+	// агрегат аналитики: трек больше не числится в своём статусе (см. analytics_repo.go)
+	_, err = tx.Exec(ctx, `
+UPDATE carrier_status_counts SET trackings = trackings - 1 WHERE carrier_code = $1 AND status = $2
+`, prev.carrierCode, prev.status)
+	if err != nil {
+		return errors.Wrap(err, "update carrier status counts")
+	}
+	_, err = tx.Exec(ctx, `
+WITH `+bumpChangeSeq+`
+INSERT INTO tracking_tombstones (tracking_id, carrier_code, track_number, external_id, deleted_at, change_seq)
+SELECT $1, $2, $3, $4, $5, seq FROM bump
+`, ts.TrackingID, ts.CarrierCode, ts.TrackNumber, ts.ExternalID, ts.DeletedAt)
+	if err != nil {
+		return errors.Wrap(err, "insert tracking tombstone")
+	}
+	return errors.Wrap(tx.Commit(ctx), "commit tx")
+}
+
+// ChangeCursor — позиция в ленте изменений: ключ (change_seq, вид, id) последней выданной записи; нулевой — с начала.
+type ChangeCursor struct {
+	Seq  int64
+	Kind int
+	ID   uint64
+}
+
+// after — ключ (change_seq, id), после которого идут ещё не выданные записи вида kind.
+func (c ChangeCursor) after(kind int) (int64, uint64) {
+	switch {
+	case c.Kind < kind:
+		return c.Seq, 0
+	case c.Kind == kind:
+		return c.Seq, c.ID
+	default:
+		return c.Seq + 1, 0
+	}
+}
+
+// ListChanges — до limit записей ленты изменений после курсора, по (change_seq, вид, id). Три вида записей
+// читаются отдельными запросами из одного снимка и сливаются.
+func (s *Storage) ListChanges(ctx context.Context, after ChangeCursor, limit int) ([]*models.Change, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var out []*models.Change
+
+	seq, id := after.after(models.ChangeTracking)
+	rows, err := tx.Query(ctx, `
+SELECT change_seq,`+trackingColumns+`
+FROM trackings
+WHERE (change_seq, id) > ($1, $2)
+ORDER BY change_seq, id
+LIMIT $3
+`, seq, id, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "select changed trackings")
+	}
+	for rows.Next() {
+		c := &models.Change{Kind: models.ChangeTracking}
+		if c.Tracking, err = scanTracking(rows, &c.Seq); err != nil {
+			rows.Close()
+			return nil, errors.Wrap(err, "scan tracking")
+		}
+		out = append(out, c)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows")
+	}
+
+	seq, id = after.after(models.ChangeEvent)
+	rows, err = tx.Query(ctx, `
+SELECT change_seq,`+eventColumns+`
+FROM tracking_events
+WHERE (change_seq, id) > ($1, $2)
+ORDER BY change_seq, id
+LIMIT $3
+`, seq, id, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "select changed events")
+	}
+	for rows.Next() {
+		c := &models.Change{Kind: models.ChangeEvent}
+		if c.Event, err = scanEvent(rows, &c.Seq); err != nil {
+			rows.Close()
+			return nil, errors.Wrap(err, "scan event")
+		}
+		out = append(out, c)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows")
+	}
+
+	seq, id = after.after(models.ChangeDeleted)
+	rows, err = tx.Query(ctx, `
+SELECT change_seq, tracking_id, carrier_code, track_number, external_id, deleted_at
+FROM tracking_tombstones
+WHERE (change_seq, tracking_id) > ($1, $2)
+ORDER BY change_seq, tracking_id
+LIMIT $3
+`, seq, id, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "select tombstones")
+	}
+	for rows.Next() {
+		var ts models.TrackingTombstone
+		c := &models.Change{Kind: models.ChangeDeleted, Deleted: &ts}
+		if err := rows.Scan(&c.Seq, &ts.TrackingID, &ts.CarrierCode, &ts.TrackNumber, &ts.ExternalID, &ts.DeletedAt); err != nil {
+			rows.Close()
+			return nil, errors.Wrap(err, "scan tombstone")
+		}
+		out = append(out, c)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows")
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Seq != b.Seq {
+			return a.Seq < b.Seq
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.ID() < b.ID()
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
@@ -0,0 +1,74 @@
+package pgtracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+// insertCheck пишет проверку в журнал (tracking_checks) в транзакции ApplyTrackingUpdate.
+// Повтор того же сообщения (та же проверка — тот же checked_at) второй записи не даёт.
+func insertCheck(ctx context.Context, tx pgx.Tx, c *models.TrackingCheck) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO tracking_checks (
+  tracking_id, checked_at, kind, status, error, error_class,
+  worker, backend, latency_ms, http_status, events_count, response_hash, response_ref
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+ON CONFLICT (tracking_id, checked_at) DO NOTHING
+`, c.TrackingID, c.CheckedAt.UTC(), c.Kind, c.Status, c.Error, c.ErrorClass,
+		c.Worker, c.Backend, c.LatencyMS, c.HTTPStatus, c.EventsCount, c.ResponseHash, c.ResponseRef)
+	return errors.Wrap(err, "insert tracking check")
+}
+
+// ListTrackingChecks — проверки трека, новые первыми, строго раньше before (нулевой — с самой новой).
+// failedOnly — только неудачные.
+func (s *Storage) ListTrackingChecks(ctx context.Context, trackingID uint64, failedOnly bool, before time.Time, limit int) ([]*models.TrackingCheck, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	var beforeArg *time.Time
+	if !before.IsZero() {
+		b := before.UTC()
+		beforeArg = &b
+	}
+	rows, err := s.db.Query(ctx, `
+SELECT tracking_id, checked_at, kind, status, error, error_class,
+       worker, backend, latency_ms, http_status, events_count, response_hash, response_ref
+FROM tracking_checks
+WHERE tracking_id = $1
+  AND ($2::timestamptz IS NULL OR checked_at < $2)
+  AND (NOT $3 OR error IS NOT NULL)
+ORDER BY checked_at DESC
+LIMIT $4
+`, trackingID, beforeArg, failedOnly, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "select tracking checks")
+	}
+	defer rows.Close()
+
+	var out []*models.TrackingCheck
+	for rows.Next() {
+		var c models.TrackingCheck
+		if err := rows.Scan(
+			&c.TrackingID, &c.CheckedAt, &c.Kind, &c.Status, &c.Error, &c.ErrorClass,
+			&c.Worker, &c.Backend, &c.LatencyMS, &c.HTTPStatus, &c.EventsCount, &c.ResponseHash, &c.ResponseRef,
+		); err != nil {
+			return nil, errors.Wrap(err, "scan tracking check")
+		}
+		out = append(out, &c)
+	}
+	return out, errors.Wrap(rows.Err(), "rows")
+}
+
+// MaintainCheckLog готовит секции журнала проверок на partitionsAhead дней вперёд и удаляет записи
+// старше retention (целыми днями). Возвращает число удалённых секций.
+func (s *Storage) MaintainCheckLog(ctx context.Context, now time.Time, retention time.Duration) (int, error) {
+	if err := s.ensurePartitions(ctx, checksTable, now, partitionsAhead); err != nil {
+		return 0, err
+	}
+	return s.dropPartitionsBefore(ctx, checksTable, now.Add(-retention), false)
+}
@@ -0,0 +1,287 @@
+package pgtracking
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+// eventEdit — сохранённое событие prev, которое перевозчик теперь отдаёт как next.
+type eventEdit struct {
+	prev *models.TrackingEvent
+	next *models.TrackingEvent
+}
+
+type eventChanges struct {
+	inserts  []*models.TrackingEvent
+	edits    []eventEdit
+	removed  []*models.TrackingEvent
+	restored []*models.TrackingEvent
+}
+
+// diffEvents сравнивает события из ответа перевозчика (полная история) с сохранёнными.
+//
+// Событие с тем же ключом дедупликации — то же самое (пропавшее раньше — вернулось).
+// Новое событие, у которого тот же status_raw и либо то же время, либо те же location и message,
+// что у сохранённого и пропавшего из ответа, — исправление этого события; остальные новые — вставки.
+// Сохранённые события, которых нет в ответе, считаются удалёнными, только если они не старше
+// самого раннего события ответа: перевозчик может отдавать не всю историю.
+func diffEvents(stored, incoming []*models.TrackingEvent) eventChanges {
+	var ch eventChanges
+	if len(incoming) == 0 {
+		return ch
+	}
+
+	byKey := make(map[string]*models.TrackingEvent, len(stored))
+	for _, e := range stored {
+		byKey[e.DedupKey()] = e
+	}
+	matched := make(map[*models.TrackingEvent]bool, len(stored))
+	seen := make(map[string]bool, len(incoming))
+	oldest := incoming[0].EventTime
+	var unmatched []*models.TrackingEvent
+	for _, e := range incoming {
+		if e.EventTime.Before(oldest) {
+			oldest = e.EventTime
+		}
+		key := e.DedupKey()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if s, ok := byKey[key]; ok {
+			matched[s] = true
+			if s.RemovedAt != nil {
+				ch.restored = append(ch.restored, s)
+			}
+			continue
+		}
+		unmatched = append(unmatched, e)
+	}
+
+	var gone []*models.TrackingEvent
+	for _, s := range stored {
+		if !matched[s] && s.RemovedAt == nil {
+			gone = append(gone, s)
+		}
+	}
+	for _, e := range unmatched {
+		i := findEdited(gone, e)
+		if i < 0 {
+			ch.inserts = append(ch.inserts, e)
+			continue
+		}
+		ch.edits = append(ch.edits, eventEdit{prev: gone[i], next: e})
+		gone = append(gone[:i], gone[i+1:]...)
+	}
+	for _, s := range gone {
+		if !s.EventTime.Before(oldest) {
+			ch.removed = append(ch.removed, s)
+		}
+	}
+	return ch
+}
+
+// findEdited — индекс сохранённого события, исправлением которого может быть e; -1 — нет такого.
+// Сначала ищем по времени (исправили место или текст), потом по месту и тексту (исправили время).
+func findEdited(gone []*models.TrackingEvent, e *models.TrackingEvent) int {
+	for i, s := range gone {
+		if s.StatusRaw == e.StatusRaw && s.EventTime.Equal(e.EventTime) {
+			return i
+		}
+	}
+	for i, s := range gone {
+		if s.StatusRaw == e.StatusRaw && deref(s.Location) == deref(e.Location) && deref(s.Message) == deref(e.Message) {
+			return i
+		}
+	}
+	return -1
+}
+
+// revisionSnapshot — событие до изменения (tracking_event_revisions.previous).
+type revisionSnapshot struct {
+	Status    string    `json:"status"`
+	StatusRaw string    `json:"status_raw"`
+	EventTime time.Time `json:"event_time"`
+	Location  string    `json:"location"`
+	Message   string    `json:"message"`
+}
+
+func (r revisionSnapshot) event() *models.TrackingEvent {
+	loc, msg := r.Location, r.Message
+	return &models.TrackingEvent{Status: r.Status, StatusRaw: r.StatusRaw, EventTime: r.EventTime, Location: &loc, Message: &msg}
+}
+
+// loadEventsForDiff — все события трека, включая удалённые, с полями ключа дедупликации.
+func loadEventsForDiff(ctx context.Context, tx pgx.Tx, trackingID uint64) ([]*models.TrackingEvent, error) {
+	rows, err := tx.Query(ctx, `
+SELECT id, status, status_raw, event_time, location, message, removed_at
+FROM tracking_events
+WHERE tracking_id = $1 AND `+eventSpan+`
+ORDER BY event_time, id
+`, trackingID)
+	if err != nil {
+		return nil, errors.Wrap(err, "select events for diff")
+	}
+	defer rows.Close()
+
+	var out []*models.TrackingEvent
+	for rows.Next() {
+		e := &models.TrackingEvent{TrackingID: trackingID}
+		var loc, msg string
+		if err := rows.Scan(&e.ID, &e.Status, &e.StatusRaw, &e.EventTime, &loc, &msg, &e.RemovedAt); err != nil {
+			return nil, errors.Wrap(err, "scan event for diff")
+		}
+		e.Location, e.Message = &loc, &msg
+		out = append(out, e)
+	}
+	return out, errors.Wrap(rows.Err(), "rows")
+}
+
+// applyEvents сохраняет события из ответа перевозчика: новые вставляет, исправленные обновляет на месте,
+// пропавшие помечает removed_at; каждое изменение сохранённого события пишется в tracking_event_revisions.
+// У вставленных и изменённых событий change_seq сбрасывается в NULL — номер изменения проставит markChanged.
+func applyEvents(ctx context.Context, tx pgx.Tx, upd TrackingUpdate) error {
+	if len(upd.Events) == 0 {
+		return nil
+	}
+	stored, err := loadEventsForDiff(ctx, tx, upd.TrackingID)
+	if err != nil {
+		return err
+	}
+	ch := diffEvents(stored, upd.Events)
+
+	for _, e := range ch.restored {
+		if _, err := tx.Exec(ctx, `UPDATE tracking_events SET removed_at = NULL, change_seq = NULL WHERE id = $1 AND event_time = $2`, e.ID, e.EventTime); err != nil {
+			return errors.Wrap(err, "restore tracking event")
+		}
+		if err := insertRevision(ctx, tx, upd, e, models.EventRevisionRestored); err != nil {
+			return err
+		}
+	}
+	for _, e := range ch.removed {
+		if _, err := tx.Exec(ctx, `UPDATE tracking_events SET removed_at = $2, change_seq = NULL WHERE id = $1 AND event_time = $3`, e.ID, upd.CheckedAt.UTC(), e.EventTime); err != nil {
+			return errors.Wrap(err, "remove tracking event")
+		}
+		if err := insertRevision(ctx, tx, upd, e, models.EventRevisionRemoved); err != nil {
+			return err
+		}
+	}
+	for _, ed := range ch.edits {
+		e := ed.next
+		var place models.Location
+		if e.Place != nil {
+			place = *e.Place
+		}
+		_, err := tx.Exec(ctx, `
+UPDATE tracking_events
+SET
+  status = $2, status_raw = $3, event_time = $4, location = $5, message = $6, payload = $7,
+  country = NULLIF($8, ''), region = NULLIF($9, ''), city = NULLIF($10, ''), postal_code = NULLIF($11, ''),
+  lat = $12, lon = $13,
+  event_time_raw = $14, time_inferred = $15,
+  revision = revision + 1,
+  change_seq = NULL
+WHERE id = $1 AND event_time = $16
+`, ed.prev.ID, e.Status, e.StatusRaw, e.EventTime.UTC(), deref(e.Location), deref(e.Message), eventPayload(e),
+			place.Country, place.Region, place.City, place.PostalCode, place.Lat, place.Lon,
+			e.EventTimeRaw, e.TimeInferred, ed.prev.EventTime)
+		if err != nil {
+			return errors.Wrap(err, "update tracking event")
+		}
+		if err := insertRevision(ctx, tx, upd, ed.prev, models.EventRevisionEdited); err != nil {
+			return err
+		}
+	}
+	for _, e := range ch.inserts {
+		if err := insertEvent(ctx, tx, upd.TrackingID, e); err != nil {
+			return err
+		}
+	}
+	return extendEventSpan(ctx, tx, upd.TrackingID, ch)
+}
+
+// extendEventSpan расширяет границы событий трека (trackings.event_time_min/max) до новых и исправленных событий.
+// Границы только расширяются: удалённые у перевозчика и перенесённые события остаются в таблице.
+func extendEventSpan(ctx context.Context, tx pgx.Tx, trackingID uint64, ch eventChanges) error {
+	var minTime, maxTime time.Time
+	extend := func(t time.Time) {
+		if minTime.IsZero() || t.Before(minTime) {
+			minTime = t
+		}
+		if maxTime.IsZero() || t.After(maxTime) {
+			maxTime = t
+		}
+	}
+	for _, e := range ch.inserts {
+		extend(e.EventTime)
+	}
+	for _, ed := range ch.edits {
+		extend(ed.next.EventTime)
+	}
+	if minTime.IsZero() {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `
+UPDATE trackings
+SET event_time_min = LEAST(event_time_min, $2), event_time_max = GREATEST(event_time_max, $3)
+WHERE id = $1
+`, trackingID, minTime.UTC(), maxTime.UTC())
+	return errors.Wrap(err, "update tracking event span")
+}
+
+func insertRevision(ctx context.Context, tx pgx.Tx, upd TrackingUpdate, prev *models.TrackingEvent, kind string) error {
+	b, err := json.Marshal(revisionSnapshot{
+		Status:    prev.Status,
+		StatusRaw: prev.StatusRaw,
+		EventTime: prev.EventTime.UTC(),
+		Location:  deref(prev.Location),
+		Message:   deref(prev.Message),
+	})
+	if err != nil {
+		return errors.Wrap(err, "marshal revision")
+	}
+	_, err = tx.Exec(ctx, `
+INSERT INTO tracking_event_revisions (tracking_id, event_id, kind, previous, observed_at)
+VALUES ($1, $2, $3, $4, $5)
+`, upd.TrackingID, prev.ID, kind, string(b), upd.CheckedAt.UTC())
+	return errors.Wrap(err, "insert event revision")
+}
+
+// ListEventRevisions — ревизии событий трека, от старых к новым.
+func (s *Storage) ListEventRevisions(ctx context.Context, trackingID uint64, limit int) ([]*models.EventRevision, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	rows, err := s.db.Query(ctx, `
+SELECT id, tracking_id, event_id, kind, previous::text, observed_at
+FROM tracking_event_revisions
+WHERE tracking_id = $1
+ORDER BY observed_at, id
+LIMIT $2
+`, trackingID, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "select event revisions")
+	}
+	defer rows.Close()
+
+	out := make([]*models.EventRevision, 0)
+	for rows.Next() {
+		var r models.EventRevision
+		var prev string
+		if err := rows.Scan(&r.ID, &r.TrackingID, &r.EventID, &r.Kind, &prev, &r.ObservedAt); err != nil {
+			return nil, errors.Wrap(err, "scan event revision")
+		}
+		var snap revisionSnapshot
+		if err := json.Unmarshal([]byte(prev), &snap); err != nil {
+			return nil, errors.Wrap(err, "decode event revision")
+		}
+		r.Previous = snap.event()
+		out = append(out, &r)
+	}
+	return out, errors.Wrap(rows.Err(), "rows")
+}
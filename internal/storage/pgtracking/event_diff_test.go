@@ -0,0 +1,65 @@
+package pgtracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffEvents(t *testing.T) {
+	t0 := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	ev := func(id uint64, raw string, at time.Time, loc string) *models.TrackingEvent {
+		msg := ""
+		return &models.TrackingEvent{ID: id, StatusRaw: raw, EventTime: at, Location: &loc, Message: &msg}
+	}
+	removedAt := t0.Add(time.Hour)
+	gone := ev(4, "RETURNED", t0.Add(3*time.Hour), "Казань")
+	gone.RemovedAt = &removedAt
+
+	stored := []*models.TrackingEvent{
+		ev(1, "ACCEPTED", t0, "Москва"),
+		ev(2, "SORTED", t0.Add(time.Hour), "Москва"),
+		ev(3, "ARRIVED", t0.Add(2*time.Hour), "Казань"),
+		gone,
+		ev(5, "OLD", t0.Add(-time.Hour), ""),
+	}
+	incoming := []*models.TrackingEvent{
+		ev(0, "ACCEPTED", t0, "Москва"),                   // без изменений
+		ev(0, "SORTED", t0.Add(time.Hour), "Москва МСЦ"),  // исправили место
+		ev(0, "RETURNED", t0.Add(3*time.Hour), "Казань"),  // вернулось
+		ev(0, "DELIVERED", t0.Add(4*time.Hour), "Казань"), // новое
+		ev(0, "DELIVERED", t0.Add(4*time.Hour), "Казань"), // повтор в ответе
+	}
+
+	ch := diffEvents(stored, incoming)
+	require.Len(t, ch.edits, 1)
+	require.Equal(t, uint64(2), ch.edits[0].prev.ID)
+	require.Equal(t, "Москва МСЦ", *ch.edits[0].next.Location)
+	require.Len(t, ch.inserts, 1)
+	require.Equal(t, "DELIVERED", ch.inserts[0].StatusRaw)
+	require.Len(t, ch.restored, 1)
+	require.Equal(t, uint64(4), ch.restored[0].ID)
+	// ARRIVED пропало; OLD старше самого раннего события ответа — перевозчик мог его просто не отдать.
+	require.Len(t, ch.removed, 1)
+	require.Equal(t, uint64(3), ch.removed[0].ID)
+}
+
+func TestDiffEvents_TimeCorrected(t *testing.T) {
+	t0 := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	loc, msg := "Казань", "Прибыло"
+	stored := []*models.TrackingEvent{{ID: 7, StatusRaw: "ARRIVED", EventTime: t0, Location: &loc, Message: &msg}}
+	incoming := []*models.TrackingEvent{{StatusRaw: "ARRIVED", EventTime: t0.Add(-3 * time.Hour), Location: &loc, Message: &msg}}
+
+	ch := diffEvents(stored, incoming)
+	require.Len(t, ch.edits, 1)
+	require.Equal(t, uint64(7), ch.edits[0].prev.ID)
+	require.Empty(t, ch.inserts)
+	require.Empty(t, ch.removed)
+}
+
+func TestDiffEvents_EmptyResponse(t *testing.T) {
+	stored := []*models.TrackingEvent{{ID: 1, StatusRaw: "ACCEPTED", EventTime: time.Now()}}
+	require.Equal(t, eventChanges{}, diffEvents(stored, nil))
+}
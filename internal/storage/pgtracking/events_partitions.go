@@ -0,0 +1,290 @@
+package pgtracking
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+// События трека (tracking_events) секционированы по месяцам event_time (см. partitions.go). Ключ секционирования
+// входит и в первичный ключ (id, event_time), и в ключ дедупликации uq_tracking_events_dedup.
+//
+// Чтобы запросы по одному треку не обходили все секции, у трека хранятся границы его событий
+// (trackings.event_time_min/max, включая удалённые у перевозчика): запросы добавляют условие на event_time
+// по ним, и Postgres отсекает лишние секции при выполнении. Границы расширяются в applyEvents; у трека
+// без событий они NULL — такие запросы не читают ни одной секции.
+
+// eventSpan — условие отсечения секций для событий трека $1.
+const eventSpan = `event_time BETWEEN (SELECT event_time_min FROM trackings WHERE id = $1)
+  AND (SELECT event_time_max FROM trackings WHERE id = $1)`
+
+// eventSpanAny — то же для событий треков из массива $1.
+const eventSpanAny = `event_time BETWEEN (SELECT min(event_time_min) FROM trackings WHERE id = ANY($1))
+  AND (SELECT max(event_time_max) FROM trackings WHERE id = ANY($1))`
+
+var eventsSchema = []string{
+	// Последовательность — отдельно от таблицы: в несекционированной схеме её создавал BIGSERIAL,
+	// при переходе на секции она переезжает к новой таблице вместе со значением.
+	`CREATE SEQUENCE IF NOT EXISTS tracking_events_id_seq`,
+	`
+CREATE TABLE IF NOT EXISTS tracking_events (
+  id BIGINT NOT NULL DEFAULT nextval('tracking_events_id_seq'),
+  tracking_id BIGINT NOT NULL REFERENCES trackings(id) ON DELETE CASCADE,
+  status TEXT NOT NULL,
+  status_raw TEXT NOT NULL,
+  event_time TIMESTAMPTZ NOT NULL,
+  location TEXT NOT NULL DEFAULT '',
+  message TEXT NOT NULL DEFAULT '',
+  payload JSONB NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  country TEXT NULL,
+  region TEXT NULL,
+  city TEXT NULL,
+  postal_code TEXT NULL,
+  lat DOUBLE PRECISION NULL,
+  lon DOUBLE PRECISION NULL,
+  event_time_raw TEXT NULL,
+  time_inferred BOOLEAN NOT NULL DEFAULT false,
+  revision INT NOT NULL DEFAULT 0,
+  removed_at TIMESTAMPTZ NULL,
+  change_seq BIGINT NULL,
+  PRIMARY KEY (id, event_time)
+) PARTITION BY RANGE (event_time)`,
+	// change_seq — номер изменения для ленты (см. changes_repo.go); NULL — событие изменено в текущей транзакции
+	// и номер ещё не проставлен. События, сохранённые до появления ленты, получают 0 (значение по умолчанию
+	// при добавлении колонки не переписывает таблицу).
+	`ALTER TABLE tracking_events ADD COLUMN IF NOT EXISTS change_seq BIGINT NULL DEFAULT 0`,
+	`ALTER TABLE tracking_events ALTER COLUMN change_seq DROP DEFAULT`,
+	`ALTER SEQUENCE tracking_events_id_seq OWNED BY tracking_events.id`,
+	`CREATE TABLE IF NOT EXISTS tracking_events_default PARTITION OF tracking_events DEFAULT`,
+	`CREATE INDEX IF NOT EXISTS idx_tracking_events_tracking_id_event_time ON tracking_events(tracking_id, event_time DESC)`,
+	// Enforce de-duplication of events for a tracking.
+	`CREATE UNIQUE INDEX IF NOT EXISTS uq_tracking_events_dedup ON tracking_events(tracking_id, status_raw, event_time, location, message)`,
+	`CREATE INDEX IF NOT EXISTS idx_tracking_events_change_seq ON tracking_events(change_seq, id)`,
+}
+
+// legacyEventsMigrations доводят несекционированную таблицу старой схемы до текущего набора колонок перед переносом.
+var legacyEventsMigrations = []string{
+	`ALTER TABLE tracking_events ADD COLUMN IF NOT EXISTS country TEXT NULL`,
+	`ALTER TABLE tracking_events ADD COLUMN IF NOT EXISTS region TEXT NULL`,
+	`ALTER TABLE tracking_events ADD COLUMN IF NOT EXISTS city TEXT NULL`,
+	`ALTER TABLE tracking_events ADD COLUMN IF NOT EXISTS postal_code TEXT NULL`,
+	`ALTER TABLE tracking_events ADD COLUMN IF NOT EXISTS lat DOUBLE PRECISION NULL`,
+	`ALTER TABLE tracking_events ADD COLUMN IF NOT EXISTS lon DOUBLE PRECISION NULL`,
+	`ALTER TABLE tracking_events ADD COLUMN IF NOT EXISTS event_time_raw TEXT NULL`,
+	`ALTER TABLE tracking_events ADD COLUMN IF NOT EXISTS time_inferred BOOLEAN NOT NULL DEFAULT false`,
+	`ALTER TABLE tracking_events ADD COLUMN IF NOT EXISTS revision INT NOT NULL DEFAULT 0`,
+	`ALTER TABLE tracking_events ADD COLUMN IF NOT EXISTS removed_at TIMESTAMPTZ NULL`,
+	`ALTER TABLE tracking_events RENAME TO tracking_events_legacy`,
+	`ALTER TABLE tracking_events_legacy RENAME CONSTRAINT tracking_events_pkey TO tracking_events_legacy_pkey`,
+	`DROP INDEX IF EXISTS uq_tracking_events_dedup`,
+	`DROP INDEX IF EXISTS idx_tracking_events_tracking_id_event_time`,
+	`ALTER SEQUENCE tracking_events_id_seq OWNED BY NONE`,
+}
+
+// legacyEventsMonths — за сколько месяцев назад при переносе старой таблицы создаются секции;
+// более старые события попадают в секцию по умолчанию.
+const legacyEventsMonths = 60
+
+// initEventsSchema создаёт секционированную tracking_events, а несекционированную таблицу старой схемы
+// переносит в неё (один раз, в одной транзакции: на больших таблицах старт будет долгим).
+func (s *Storage) initEventsSchema(ctx context.Context, now time.Time) error {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, "schema:tracking_events"); err != nil {
+		return errors.Wrap(err, "lock events schema")
+	}
+	var kind string
+	err = tx.QueryRow(ctx, `SELECT COALESCE((SELECT relkind::text FROM pg_class WHERE oid = to_regclass('tracking_events')), '')`).Scan(&kind)
+	if err != nil {
+		return errors.Wrap(err, "select events table kind")
+	}
+	legacy := kind == "r"
+	if legacy {
+		for _, q := range legacyEventsMigrations {
+			if _, err := tx.Exec(ctx, q); err != nil {
+				return errors.Wrap(err, "migrate legacy events")
+			}
+		}
+	}
+	for _, q := range eventsSchema {
+		if _, err := tx.Exec(ctx, q); err != nil {
+			return errors.Wrap(err, "init events schema")
+		}
+	}
+	if legacy {
+		if err := moveLegacyEvents(ctx, tx, now); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "commit tx")
+	}
+	return s.ensureEventPartitions(ctx, now)
+}
+
+// moveLegacyEvents переносит события из tracking_events_legacy в секции и заполняет границы событий треков.
+func moveLegacyEvents(ctx context.Context, tx pgx.Tx, now time.Time) error {
+	var minTime, maxTime *time.Time
+	if err := tx.QueryRow(ctx, `SELECT min(event_time), max(event_time) FROM tracking_events_legacy`).Scan(&minTime, &maxTime); err != nil {
+		return errors.Wrap(err, "select legacy events range")
+	}
+	if minTime != nil {
+		from := eventsTable.start(*minTime)
+		if floor := eventsTable.start(now).AddDate(0, -legacyEventsMonths, 0); from.Before(floor) {
+			from = floor
+		}
+		// Будущие секции создаст ensureEventPartitions; события из далёкого будущего остаются в секции по умолчанию.
+		to := eventsTable.start(*maxTime)
+		if ceil := eventsTable.start(now); to.After(ceil) {
+			to = ceil
+		}
+		for start := from; !start.After(to); start = eventsTable.next(start) {
+			if err := createPartition(ctx, tx, eventsTable, start); err != nil {
+				return err
+			}
+		}
+	}
+
+	stmts := []string{
+		`
+INSERT INTO tracking_events (
+  id, tracking_id, status, status_raw, event_time, location, message, payload, created_at,
+  country, region, city, postal_code, lat, lon, event_time_raw, time_inferred, revision, removed_at, change_seq
+)
+SELECT
+  id, tracking_id, status, status_raw, event_time, COALESCE(location, ''), COALESCE(message, ''), payload, created_at,
+  country, region, city, postal_code, lat, lon, event_time_raw, time_inferred, revision, removed_at, 0
+FROM tracking_events_legacy
+ON CONFLICT DO NOTHING
+`,
+		`
+UPDATE trackings t
+SET event_time_min = e.min_time, event_time_max = e.max_time
+FROM (
+  SELECT tracking_id, min(event_time) AS min_time, max(event_time) AS max_time
+  FROM tracking_events
+  GROUP BY tracking_id
+) e
+WHERE t.id = e.tracking_id
+`,
+		`DROP TABLE tracking_events_legacy`,
+	}
+	for _, q := range stmts {
+		if _, err := tx.Exec(ctx, q); err != nil {
+			return errors.Wrap(err, "move legacy events")
+		}
+	}
+	return nil
+}
+
+// ensureEventPartitions держит секции событий с прошлого месяца (перевозчики отдают и недавние события)
+// на eventPartitionsAhead месяцев вперёд.
+func (s *Storage) ensureEventPartitions(ctx context.Context, now time.Time) error {
+	return s.ensurePartitions(ctx, eventsTable, eventsTable.start(now).AddDate(0, -1, 0), eventPartitionsAhead+1)
+}
+
+// EventRetention — сроки хранения событий (trackbox.event_retention).
+type EventRetention struct {
+	// MaxAge — события старше удаляются целыми месячными секциями; 0 — без ограничения.
+	MaxAge time.Duration
+	// ByStatus — через сколько после перехода трека в статус (status_at, иначе updated_at) удаляются все его события
+	// и их ревизии, например DELIVERED — через год.
+	ByStatus map[string]time.Duration
+	// DetachOnly — старые секции не удаляются, а отсоединяются от tracking_events.
+	DetachOnly bool
+}
+
+// EventMaintenance — итог обслуживания событий.
+type EventMaintenance struct {
+	Partitions int   // удалено (отсоединено) секций
+	Trackings  int64 // треков, чьи события удалены по статусу
+	Events     int64 // событий, удалённых по статусу
+}
+
+// eventPurgeBatch — сколько треков обрабатывается одним запросом при удалении событий по статусу.
+const eventPurgeBatch = 500
+
+// MaintainEvents готовит месячные секции событий вперёд и применяет сроки хранения: секции старше MaxAge
+// удаляются (отсоединяются), события треков в статусах из ByStatus удаляются пачками.
+func (s *Storage) MaintainEvents(ctx context.Context, now time.Time, r EventRetention) (EventMaintenance, error) {
+	var res EventMaintenance
+	if err := s.ensureEventPartitions(ctx, now); err != nil {
+		return res, err
+	}
+	if r.MaxAge > 0 {
+		before := now.Add(-r.MaxAge)
+		n, err := s.dropPartitionsBefore(ctx, eventsTable, before, r.DetachOnly)
+		res.Partitions = n
+		if err != nil {
+			return res, err
+		}
+		if !r.DetachOnly {
+			// Ревизии наблюдаются не раньше самих событий, так что ревизии старше before относятся к удалённым.
+			if _, err := s.db.Exec(ctx, `DELETE FROM tracking_event_revisions WHERE observed_at < $1`, before.UTC()); err != nil {
+				return res, errors.Wrap(err, "delete old event revisions")
+			}
+		}
+	}
+
+	statuses := make([]string, 0, len(r.ByStatus))
+	for status := range r.ByStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		if r.ByStatus[status] <= 0 {
+			continue
+		}
+		cutoff := now.Add(-r.ByStatus[status])
+		for {
+			trackings, events, err := s.purgeEvents(ctx, status, cutoff, eventPurgeBatch)
+			res.Trackings += trackings
+			res.Events += events
+			if err != nil {
+				return res, errors.Wrapf(err, "purge events of %s trackings", status)
+			}
+			if trackings < eventPurgeBatch {
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// purgeEvents удаляет события и ревизии до limit треков в статусе status, перешедших в него раньше cutoff,
+// и сбрасывает их границы событий. Заблокированные треки (их сейчас обновляет ApplyTrackingUpdate) пропускаются.
+func (s *Storage) purgeEvents(ctx context.Context, status string, cutoff time.Time, limit int) (int64, int64, error) {
+	var trackings, events int64
+	err := s.db.QueryRow(ctx, fmt.Sprintf(`
+WITH t AS (
+  SELECT id, event_time_min, event_time_max
+  FROM trackings
+  WHERE status = $1 AND COALESCE(status_at, updated_at) < $2 AND event_time_max IS NOT NULL
+  ORDER BY id
+  LIMIT %d
+  FOR UPDATE SKIP LOCKED
+), cleared AS (
+  UPDATE trackings SET event_time_min = NULL, event_time_max = NULL
+  FROM t
+  WHERE trackings.id = t.id
+  RETURNING trackings.id
+), revisions AS (
+  DELETE FROM tracking_event_revisions r USING t WHERE r.tracking_id = t.id
+), events AS (
+  DELETE FROM tracking_events e USING t
+  WHERE e.tracking_id = t.id AND e.event_time BETWEEN t.event_time_min AND t.event_time_max
+  RETURNING 1
+)
+SELECT (SELECT count(*) FROM cleared), (SELECT count(*) FROM events)
+`, limit), status, cutoff.UTC()).Scan(&trackings, &events)
+	return trackings, events, err
+}
@@ -23,10 +23,95 @@ type TrackingUpdate struct {
 
 	Events []*models.TrackingEvent
 
+	// Shipment — сведения об отправлении из ответа перевозчика; пустые поля не затирают сохранённые.
+	Shipment *models.ShipmentDetails
+
 	Error *string
+
+	// Fingerprint — отпечаток ответа перевозчика (messages.TrackingUpdated.Fingerprint); "" — сбросить.
+	Fingerprint string
+
+	// Check — запись журнала проверок; nil — проверка в журнал не пишется.
+	Check *models.TrackingCheck
+}
+
+// EventFilter — фильтр событий трека по месту и времени; пустое (нулевое) поле — без ограничения.
+// Место сравнивается без учёта регистра, время события — в полуинтервале [From, To).
+type EventFilter struct {
+	Country string
+	Region  string
+	From    time.Time
+	To      time.Time
+}
+
+// eventsWhere — события трека $1 по фильтру $2..$5 (EventFilter.args). Границы времени заданы выражениями,
+// а не «$4 IS NULL OR ...», чтобы Postgres мог отсечь по ним и секции.
+const eventsWhere = `
+WHERE tracking_id = $1
+  AND ` + eventSpan + `
+  AND removed_at IS NULL
+  AND ($2 = '' OR upper(country) = upper($2))
+  AND ($3 = '' OR lower(region) = lower($3))
+  AND event_time >= COALESCE($4::timestamptz, '-infinity')
+  AND event_time < COALESCE($5::timestamptz, 'infinity')`
+
+func (f EventFilter) args(trackingID uint64) []any {
+	return []any{trackingID, f.Country, f.Region, nullTime(f.From), nullTime(f.To)}
+}
+
+// nullTime — нулевое время как NULL.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
 }
 
-func (s *Storage) ListTrackingEvents(ctx context.Context, trackingID uint64, limit, offset int) ([]*models.TrackingEvent, error) {
+// EventCursor — позиция в истории событий трека: ключ (event_time, id) последнего события предыдущей страницы.
+type EventCursor struct {
+	EventTime time.Time
+	ID        uint64
+}
+
+const eventColumns = `
+  id, tracking_id, status, status_raw,
+  event_time, location, message, payload, created_at,
+  country, region, city, postal_code, lat, lon,
+  event_time_raw, time_inferred, revision, removed_at`
+
+func scanEvent(row pgx.Row, extra ...any) (*models.TrackingEvent, error) {
+	var e models.TrackingEvent
+	var payload any
+	var country, region, city, postalCode *string
+	var place models.Location
+	dest := append(extra,
+		&e.ID, &e.TrackingID, &e.Status, &e.StatusRaw,
+		&e.EventTime, &e.Location, &e.Message, &payload, &e.CreatedAt,
+		&country, &region, &city, &postalCode, &place.Lat, &place.Lon,
+		&e.EventTimeRaw, &e.TimeInferred, &e.Revision, &e.RemovedAt,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if payload != nil {
+		b, _ := json.Marshal(payload)
+		s := string(b)
+		e.PayloadJSON = &s
+	}
+	place.Country = deref(country)
+	place.Region = deref(region)
+	place.City = deref(city)
+	place.PostalCode = deref(postalCode)
+	if !place.IsEmpty() {
+		e.Place = &place
+	}
+	return &e, nil
+}
+
+// ListTrackingEvents — события трека, новые первыми, постранично через LIMIT/OFFSET (не больше 500 за раз).
+// Для глубоких страниц — ListTrackingEventsPage.
+func (s *Storage) ListTrackingEvents(ctx context.Context, trackingID uint64, f EventFilter, limit, offset int) ([]*models.TrackingEvent, error) {
 	if limit <= 0 || limit > 500 {
 		limit = 100
 	}
@@ -35,42 +120,117 @@ func (s *Storage) ListTrackingEvents(ctx context.Context, trackingID uint64, lim
 	}
 
 	rows, err := s.db.Query(ctx, `
-SELECT
-  id, tracking_id, status, status_raw,
-  event_time, location, message, payload, created_at
-FROM tracking_events
-WHERE tracking_id = $1
-ORDER BY event_time DESC
-LIMIT $2 OFFSET $3
-`, trackingID, limit, offset)
+SELECT`+eventColumns+`
+FROM tracking_events`+eventsWhere+`
+ORDER BY event_time DESC, id DESC
+LIMIT $6 OFFSET $7
+`, append(f.args(trackingID), limit, offset)...)
 	if err != nil {
 		return nil, errors.Wrap(err, "select events")
 	}
+	return collectEvents(rows)
+}
+
+// ListEventKeys — ключи дедупликации (TrackingEvent.DedupKey) всех активных событий трека, без ограничения
+// по числу: по ним CheckTrackingNow отличает новые события ответа от уже сохранённых.
+func (s *Storage) ListEventKeys(ctx context.Context, trackingID uint64) ([]string, error) {
+	rows, err := s.db.Query(ctx, `
+SELECT status_raw, event_time, location, message
+FROM tracking_events
+WHERE tracking_id = $1 AND `+eventSpan+` AND removed_at IS NULL
+`, trackingID)
+	if err != nil {
+		return nil, errors.Wrap(err, "select event keys")
+	}
 	defer rows.Close()
 
-	var out []*models.TrackingEvent
+	var out []string
 	for rows.Next() {
 		var e models.TrackingEvent
-		var location *string
-		var message *string
-		var payload any
-		if err := rows.Scan(
-			&e.ID, &e.TrackingID, &e.Status, &e.StatusRaw,
-			&e.EventTime, &location, &message, &payload, &e.CreatedAt,
-		); err != nil {
-			return nil, errors.Wrap(err, "scan event")
+		var loc, msg string
+		if err := rows.Scan(&e.StatusRaw, &e.EventTime, &loc, &msg); err != nil {
+			return nil, errors.Wrap(err, "scan event key")
 		}
+		e.Location, e.Message = &loc, &msg
+		out = append(out, e.DedupKey())
+	}
+	return out, errors.Wrap(rows.Err(), "rows")
+}
+
+// ListTrackingEventsPage — до limit событий трека в порядке (event_time, id): asc — от старых к новым, иначе
+// новые первыми; after — курсор последнего события предыдущей страницы (nil — с начала). Страницы по курсору
+// не сдвигаются от вставки новых событий и не дорожают с глубиной, в отличие от OFFSET.
+func (s *Storage) ListTrackingEventsPage(ctx context.Context, trackingID uint64, f EventFilter, asc bool, after *EventCursor, limit int) ([]*models.TrackingEvent, error) {
+	cmp, order := "<", "DESC"
+	if asc {
+		cmp, order = ">", "ASC"
+	}
+	var afterTime *time.Time
+	var afterID uint64
+	if after != nil {
+		afterTime, afterID = nullTime(after.EventTime), after.ID
+	}
+
+	rows, err := s.db.Query(ctx, `
+SELECT`+eventColumns+`
+FROM tracking_events`+eventsWhere+`
+  AND ($6::timestamptz IS NULL OR (event_time, id) `+cmp+` ($6, $7))
+ORDER BY event_time `+order+`, id `+order+`
+LIMIT $8
+`, append(f.args(trackingID), afterTime, afterID, limit)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "select events page")
+	}
+	return collectEvents(rows)
+}
 
-		e.Location = location
-		e.Message = message
+// CountTrackingEvents — число событий трека под фильтром.
+func (s *Storage) CountTrackingEvents(ctx context.Context, trackingID uint64, f EventFilter) (int64, error) {
+	var n int64
+	err := s.db.QueryRow(ctx, `SELECT count(*) FROM tracking_events`+eventsWhere, f.args(trackingID)...).Scan(&n)
+	return n, errors.Wrap(err, "count events")
+}
 
-		if payload != nil {
-			b, _ := json.Marshal(payload)
-			s := string(b)
-			e.PayloadJSON = &s
+func collectEvents(rows pgx.Rows) ([]*models.TrackingEvent, error) {
+	defer rows.Close()
+	var out []*models.TrackingEvent
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan event")
 		}
+		out = append(out, e)
+	}
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows")
+	}
+	return out, nil
+}
 
-		out = append(out, &e)
+// ListEventsForTrackings — все события нескольких треков одним запросом, по времени (для export).
+func (s *Storage) ListEventsForTrackings(ctx context.Context, trackingIDs []uint64) (map[uint64][]*models.TrackingEvent, error) {
+	out := make(map[uint64][]*models.TrackingEvent, len(trackingIDs))
+	if len(trackingIDs) == 0 {
+		return out, nil
+	}
+
+	rows, err := s.db.Query(ctx, `
+SELECT`+eventColumns+`
+FROM tracking_events
+WHERE tracking_id = ANY($1) AND `+eventSpanAny+` AND removed_at IS NULL
+ORDER BY tracking_id, event_time ASC, id ASC
+`, trackingIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "select events")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan event")
+		}
+		out[e.TrackingID] = append(out[e.TrackingID], e)
 	}
 	if rows.Err() != nil {
 		return nil, errors.Wrap(rows.Err(), "rows")
@@ -78,6 +238,44 @@ LIMIT $2 OFFSET $3
 	return out, nil
 }
 
+// ListLatestEvents — до limit последних событий (по event_time, новые первыми) каждого из треков одним запросом:
+// LATERAL-подзапрос на трек идёт по индексу (tracking_id, event_time) и только по секциям в границах событий трека.
+// since — только события, записанные позже (created_at), для инкрементальной синхронизации; нулевой — все.
+func (s *Storage) ListLatestEvents(ctx context.Context, trackingIDs []uint64, limit int, since time.Time) (map[uint64][]*models.TrackingEvent, error) {
+	out := make(map[uint64][]*models.TrackingEvent, len(trackingIDs))
+	if len(trackingIDs) == 0 {
+		return out, nil
+	}
+
+	rows, err := s.db.Query(ctx, `
+SELECT e.*
+FROM trackings t
+CROSS JOIN LATERAL (
+  SELECT`+eventColumns+`
+  FROM tracking_events
+  WHERE tracking_id = t.id
+    AND event_time BETWEEN t.event_time_min AND t.event_time_max
+    AND removed_at IS NULL
+    AND created_at > COALESCE($3::timestamptz, '-infinity')
+  ORDER BY event_time DESC, id DESC
+  LIMIT $2
+) e
+WHERE t.id = ANY($1)
+ORDER BY e.tracking_id, e.event_time DESC, e.id DESC
+`, trackingIDs, limit, nullTime(since))
+	if err != nil {
+		return nil, errors.Wrap(err, "select latest events")
+	}
+	evs, err := collectEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range evs {
+		out[e.TrackingID] = append(out[e.TrackingID], e)
+	}
+	return out, nil
+}
+
 func (s *Storage) ApplyTrackingUpdate(ctx context.Context, upd TrackingUpdate) error {
 	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
@@ -85,6 +283,11 @@ func (s *Storage) ApplyTrackingUpdate(ctx context.Context, upd TrackingUpdate) e
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
+	prev, err := lockTrackingBefore(ctx, tx, upd.TrackingID)
+	if err != nil {
+		return err
+	}
+
 	if upd.Error != nil && *upd.Error != "" {
 		_, err := tx.Exec(ctx, `
 UPDATE trackings
@@ -110,48 +313,129 @@ SET
   check_fail_count = 0,
   last_error = NULL,
   next_check_at = $6,
+  fingerprint = NULLIF($7, ''),
   updated_at = now()
 WHERE id = $1
-`, upd.TrackingID, upd.CheckedAt.UTC(), upd.Status, upd.StatusRaw, upd.StatusAt, upd.NextCheckAt.UTC())
+`, upd.TrackingID, upd.CheckedAt.UTC(), upd.Status, upd.StatusRaw, upd.StatusAt, upd.NextCheckAt.UTC(), upd.Fingerprint)
 		if err != nil {
 			return errors.Wrap(err, "update tracking (ok)")
 		}
+		if err := applyShipment(ctx, tx, upd); err != nil {
+			return err
+		}
+		if err := applyEvents(ctx, tx, upd); err != nil {
+			return err
+		}
+	}
+	if err := applyRollups(ctx, tx, prev, upd); err != nil {
+		return err
+	}
+	if prev != nil && upd.Check != nil {
+		if err := insertCheck(ctx, tx, upd.Check); err != nil {
+			return err
+		}
+	}
+	if prev != nil {
+		if err := markChanged(ctx, tx, upd.TrackingID); err != nil {
+			return err
+		}
+	}
 
-		for _, e := range upd.Events {
-			var payload any
-			if e.PayloadJSON != nil && *e.PayloadJSON != "" {
-				var m any
-				if json.Unmarshal([]byte(*e.PayloadJSON), &m) == nil {
-					payload = m
-				}
-			}
-
-			loc := ""
-			if e.Location != nil {
-				loc = *e.Location
-			}
-			msgText := ""
-			if e.Message != nil {
-				msgText = *e.Message
-			}
-
-			_, err := tx.Exec(ctx, `
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "commit tx")
+	}
+	return nil
+}
+
+// eventPayload — payload события для JSONB; невалидный JSON не сохраняется.
+func eventPayload(e *models.TrackingEvent) any {
+	if e.PayloadJSON == nil || *e.PayloadJSON == "" {
+		return nil
+	}
+	var m any
+	if json.Unmarshal([]byte(*e.PayloadJSON), &m) != nil {
+		return nil
+	}
+	return m
+}
+
+func insertEvent(ctx context.Context, tx pgx.Tx, trackingID uint64, e *models.TrackingEvent) error {
+	var place models.Location
+	if e.Place != nil {
+		place = *e.Place
+	}
+	_, err := tx.Exec(ctx, `
 INSERT INTO tracking_events (
-  tracking_id, status, status_raw, event_time, location, message, payload, created_at
+  tracking_id, status, status_raw, event_time, location, message, payload, created_at,
+  country, region, city, postal_code, lat, lon,
+  event_time_raw, time_inferred
 )
-VALUES ($1,$2,$3,$4,$5,$6,$7, now())
+VALUES ($1,$2,$3,$4,$5,$6,$7, now(), NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, ''), NULLIF($11, ''), $12, $13, $14, $15)
 ON CONFLICT (tracking_id, status_raw, event_time, location, message) DO NOTHING
-`, upd.TrackingID, e.Status, e.StatusRaw, e.EventTime.UTC(), loc, msgText, payload)
-			if err != nil {
-				return errors.Wrap(err, "insert tracking event")
-			}
+`, trackingID, e.Status, e.StatusRaw, e.EventTime.UTC(), deref(e.Location), deref(e.Message), eventPayload(e),
+		place.Country, place.Region, place.City, place.PostalCode, place.Lat, place.Lon,
+		e.EventTimeRaw, e.TimeInferred)
+	return errors.Wrap(err, "insert tracking event")
+}
+
+// applyShipment сохраняет сведения об отправлении и, если ETA сменилась, пишет её в tracking_eta_history.
+// Вызывается после UPDATE trackings в той же транзакции, так что строка уже заблокирована.
+func applyShipment(ctx context.Context, tx pgx.Tx, upd TrackingUpdate) error {
+	sd := upd.Shipment
+	if sd.IsEmpty() {
+		return nil
+	}
+	if sd.EstimatedDelivery != nil {
+		_, err := tx.Exec(ctx, `
+INSERT INTO tracking_eta_history (tracking_id, estimated_delivery, previous_estimated_delivery, observed_at)
+SELECT id, $2, estimated_delivery, $3
+FROM trackings
+WHERE id = $1 AND estimated_delivery IS DISTINCT FROM $2
+`, upd.TrackingID, sd.EstimatedDelivery.UTC(), upd.CheckedAt.UTC())
+		if err != nil {
+			return errors.Wrap(err, "insert eta history")
 		}
 	}
+	_, err := tx.Exec(ctx, `
+UPDATE trackings
+SET
+  estimated_delivery = COALESCE($2, estimated_delivery),
+  weight_grams = COALESCE(NULLIF($3, 0), weight_grams),
+  origin = COALESCE(NULLIF($4, ''), origin),
+  destination = COALESCE(NULLIF($5, ''), destination),
+  recipient_city = COALESCE(NULLIF($6, ''), recipient_city),
+  service_type = COALESCE(NULLIF($7, ''), service_type)
+WHERE id = $1
+`, upd.TrackingID, sd.EstimatedDelivery, sd.WeightGrams, sd.Origin, sd.Destination, sd.RecipientCity, sd.ServiceType)
+	return errors.Wrap(err, "update shipment details")
+}
+
+// ListETAHistory — смены ETA трека, от старых к новым.
+func (s *Storage) ListETAHistory(ctx context.Context, trackingID uint64, limit int) ([]*models.ETAChange, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	rows, err := s.db.Query(ctx, `
+SELECT tracking_id, estimated_delivery, previous_estimated_delivery, observed_at
+FROM tracking_eta_history
+WHERE tracking_id = $1
+ORDER BY observed_at, id
+LIMIT $2
+`, trackingID, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "select eta history")
+	}
+	defer rows.Close()
 
-	if err := tx.Commit(ctx); err != nil {
-		return errors.Wrap(err, "commit tx")
+	out := make([]*models.ETAChange, 0)
+	for rows.Next() {
+		var c models.ETAChange
+		if err := rows.Scan(&c.TrackingID, &c.EstimatedDelivery, &c.Previous, &c.ObservedAt); err != nil {
+			return nil, errors.Wrap(err, "scan eta change")
+		}
+		out = append(out, &c)
 	}
-	return nil
+	return out, errors.Wrap(rows.Err(), "rows")
 }
 
 
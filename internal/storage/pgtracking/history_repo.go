@@ -0,0 +1,126 @@
+package pgtracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/pkg/errors"
+)
+
+// CarrierEventCadence returns the median interval between consecutive events per carrier
+// (events with event_time >= since).
+func (s *Storage) CarrierEventCadence(ctx context.Context, since time.Time) ([]models.CarrierCadence, error) {
+	rows, err := s.db.Query(ctx, `
+WITH gaps AS (
+  SELECT
+    t.carrier_code,
+    e.event_time - lag(e.event_time) OVER (PARTITION BY e.tracking_id ORDER BY e.event_time) AS gap
+  FROM tracking_events e
+  JOIN trackings t ON t.id = e.tracking_id
+  WHERE e.event_time >= $1 AND e.removed_at IS NULL
+)
+SELECT
+  carrier_code,
+  EXTRACT(EPOCH FROM percentile_cont(0.5) WITHIN GROUP (ORDER BY gap))::float8 AS median_sec,
+  count(*) AS samples
+FROM gaps
+WHERE gap > interval '0'
+GROUP BY carrier_code
+`, since.UTC())
+	if err != nil {
+		return nil, errors.Wrap(err, "select carrier cadence")
+	}
+	defer rows.Close()
+
+	var out []models.CarrierCadence
+	for rows.Next() {
+		var c models.CarrierCadence
+		var medianSec float64
+		if err := rows.Scan(&c.CarrierCode, &medianSec, &c.Samples); err != nil {
+			return nil, errors.Wrap(err, "scan carrier cadence")
+		}
+		c.MedianGap = time.Duration(medianSec * float64(time.Second))
+		out = append(out, c)
+	}
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows")
+	}
+	return out, nil
+}
+
+// LoadTrackingHistories returns up to limit trackings created since the given time together with all their events.
+// Empty carrierCode means all carriers.
+func (s *Storage) LoadTrackingHistories(ctx context.Context, carrierCode string, since time.Time, limit int) ([]*models.TrackingHistory, error) {
+	if limit <= 0 || limit > 5000 {
+		limit = 500
+	}
+
+	rows, err := s.db.Query(ctx, `
+SELECT id
+FROM trackings
+WHERE created_at >= $1
+  AND ($2 = '' OR carrier_code = $2)
+ORDER BY id DESC
+LIMIT $3
+`, since.UTC(), carrierCode, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "select history trackings")
+	}
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, errors.Wrap(err, "scan tracking id")
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows")
+	}
+	if len(ids) == 0 {
+		return []*models.TrackingHistory{}, nil
+	}
+
+	trs, err := s.GetTrackingsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint64]*models.TrackingHistory, len(trs))
+	out := make([]*models.TrackingHistory, 0, len(trs))
+	for _, t := range trs {
+		h := &models.TrackingHistory{Tracking: t}
+		byID[t.ID] = h
+		out = append(out, h)
+	}
+
+	evRows, err := s.db.Query(ctx, `
+SELECT tracking_id, status, status_raw, event_time, location, message
+FROM tracking_events
+WHERE tracking_id = ANY($1) AND `+eventSpanAny+` AND removed_at IS NULL
+ORDER BY tracking_id, event_time
+`, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "select history events")
+	}
+	defer evRows.Close()
+
+	for evRows.Next() {
+		var e models.TrackingEvent
+		var location, message string
+		if err := evRows.Scan(&e.TrackingID, &e.Status, &e.StatusRaw, &e.EventTime, &location, &message); err != nil {
+			return nil, errors.Wrap(err, "scan history event")
+		}
+		e.Location = &location
+		e.Message = &message
+		if h, ok := byID[e.TrackingID]; ok {
+			h.Events = append(h.Events, &e)
+		}
+	}
+	if evRows.Err() != nil {
+		return nil, errors.Wrap(evRows.Err(), "rows")
+	}
+	return out, nil
+}
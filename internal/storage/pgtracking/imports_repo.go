@@ -0,0 +1,235 @@
+package pgtracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/BearBump/TrackBox/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+// ErrImportJobNotFound — задачи импорта с таким id нет.
+var ErrImportJobNotFound = errors.New("import job not found")
+
+// staleUploadAfter — загрузка, не получавшая строк столько времени, считается оборванной.
+const staleUploadAfter = time.Hour
+
+const importJobColumns = `
+  id, status, format, source,
+  total_rows, invalid_rows, processed_rows, imported_rows,
+  cursor_line, attempts, last_error,
+  created_at, updated_at, finished_at`
+
+func scanImportJob(row pgx.Row) (*models.ImportJob, error) {
+	var j models.ImportJob
+	err := row.Scan(
+		&j.ID, &j.Status, &j.Format, &j.Source,
+		&j.TotalRows, &j.InvalidRows, &j.ProcessedRows, &j.ImportedRows,
+		&j.Cursor, &j.Attempts, &j.LastError,
+		&j.CreatedAt, &j.UpdatedAt, &j.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (s *Storage) CreateImportJob(ctx context.Context, format, source string) (*models.ImportJob, error) {
+	j, err := scanImportJob(s.db.QueryRow(ctx, `
+INSERT INTO import_jobs (status, format, source, created_at, updated_at)
+VALUES ($1, $2, $3, now(), now())
+RETURNING`+importJobColumns, models.ImportJobUploading, format, source))
+	return j, errors.Wrap(err, "insert import job")
+}
+
+// AddImportRows дописывает строки загружаемой задачи через COPY.
+func (s *Storage) AddImportRows(ctx context.Context, jobID uint64, rows []models.ImportRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	src := make([][]any, 0, len(rows))
+	for _, r := range rows {
+		src = append(src, []any{jobID, r.Line, r.CarrierCode, r.TrackNumber, r.Metadata, nonNilStrings(r.Tags), r.ExternalID, r.Error})
+	}
+	if _, err := s.db.CopyFrom(ctx,
+		pgx.Identifier{"import_job_rows"},
+		[]string{"job_id", "line", "carrier_code", "track_number", "metadata", "tags", "external_id", "error"},
+		pgx.CopyFromRows(src),
+	); err != nil {
+		return errors.Wrap(err, "copy import rows")
+	}
+	_, err := s.db.Exec(ctx, `UPDATE import_jobs SET updated_at = now() WHERE id = $1`, jobID)
+	return errors.Wrap(err, "touch import job")
+}
+
+// FinishImportUpload закрывает приём строк и ставит задачу в очередь.
+func (s *Storage) FinishImportUpload(ctx context.Context, jobID uint64, total, invalid int64) error {
+	_, err := s.db.Exec(ctx, `
+UPDATE import_jobs
+SET status = $2, total_rows = $3, invalid_rows = $4, updated_at = now()
+WHERE id = $1 AND status = $5
+`, jobID, models.ImportJobQueued, total, invalid, models.ImportJobUploading)
+	return errors.Wrap(err, "finish import upload")
+}
+
+func (s *Storage) FailImportJob(ctx context.Context, jobID uint64, reason string) error {
+	_, err := s.db.Exec(ctx, `
+UPDATE import_jobs
+SET status = $2, last_error = $3, lease_until = NULL, updated_at = now(), finished_at = now()
+WHERE id = $1
+`, jobID, models.ImportJobFailed, reason)
+	return errors.Wrap(err, "fail import job")
+}
+
+// ClaimImportJob берёт одну задачу в работу (QUEUED или RUNNING с истёкшей арендой — упавший обработчик)
+// и продлевает аренду. Заодно помечает FAILED оборванные загрузки. Нет задач — (nil, nil).
+func (s *Storage) ClaimImportJob(ctx context.Context, now time.Time, lease time.Duration) (*models.ImportJob, error) {
+	if _, err := s.db.Exec(ctx, `
+UPDATE import_jobs
+SET status = $1, last_error = 'upload interrupted', updated_at = now(), finished_at = now()
+WHERE status = $2 AND updated_at < $3
+`, models.ImportJobFailed, models.ImportJobUploading, now.UTC().Add(-staleUploadAfter)); err != nil {
+		return nil, errors.Wrap(err, "fail stale uploads")
+	}
+
+	j, err := scanImportJob(s.db.QueryRow(ctx, `
+UPDATE import_jobs
+SET status = $1, attempts = attempts + 1, lease_until = $2, updated_at = now()
+WHERE id = (
+  SELECT id FROM import_jobs
+  WHERE status = $3 OR (status = $1 AND lease_until < $4)
+  ORDER BY id
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING`+importJobColumns, models.ImportJobRunning, now.UTC().Add(lease), models.ImportJobQueued, now.UTC()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return j, errors.Wrap(err, "claim import job")
+}
+
+// NextImportRows — строки задачи после строки afterLine (включая невалидные, чтобы курсор шёл по файлу).
+func (s *Storage) NextImportRows(ctx context.Context, jobID uint64, afterLine int64, limit int) ([]models.ImportRow, error) {
+	rows, err := s.db.Query(ctx, `
+SELECT line, carrier_code, track_number, metadata::text, tags, external_id, error
+FROM import_job_rows
+WHERE job_id = $1 AND line > $2
+ORDER BY line
+LIMIT $3
+`, jobID, afterLine, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "select import rows")
+	}
+	defer rows.Close()
+
+	var out []models.ImportRow
+	for rows.Next() {
+		var r models.ImportRow
+		if err := rows.Scan(&r.Line, &r.CarrierCode, &r.TrackNumber, &r.Metadata, &r.Tags, &r.ExternalID, &r.Error); err != nil {
+			return nil, errors.Wrap(err, "scan import row")
+		}
+		out = append(out, r)
+	}
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows")
+	}
+	return out, nil
+}
+
+// AdvanceImportJob фиксирует прогресс после пачки и продлевает аренду; attempts считает захваты без прогресса.
+func (s *Storage) AdvanceImportJob(ctx context.Context, jobID uint64, cursor, processed, imported int64, leaseUntil time.Time) error {
+	_, err := s.db.Exec(ctx, `
+UPDATE import_jobs
+SET cursor_line = $2,
+    processed_rows = processed_rows + $3,
+    imported_rows = imported_rows + $4,
+    attempts = 0,
+    last_error = NULL,
+    lease_until = $5,
+    updated_at = now()
+WHERE id = $1
+`, jobID, cursor, processed, imported, leaseUntil.UTC())
+	return errors.Wrap(err, "advance import job")
+}
+
+// ReleaseImportJob возвращает задачу в очередь после временной ошибки (БД, сеть).
+func (s *Storage) ReleaseImportJob(ctx context.Context, jobID uint64, reason string) error {
+	_, err := s.db.Exec(ctx, `
+UPDATE import_jobs
+SET status = $2, last_error = $3, lease_until = NULL, updated_at = now()
+WHERE id = $1
+`, jobID, models.ImportJobQueued, reason)
+	return errors.Wrap(err, "release import job")
+}
+
+// CompleteImportJob завершает задачу; валидные строки больше не нужны, невалидные остаются для отчёта.
+func (s *Storage) CompleteImportJob(ctx context.Context, jobID uint64) error {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM import_job_rows WHERE job_id = $1 AND error IS NULL`, jobID); err != nil {
+		return errors.Wrap(err, "delete import rows")
+	}
+	if _, err := tx.Exec(ctx, `
+UPDATE import_jobs
+SET status = $2, lease_until = NULL, updated_at = now(), finished_at = now()
+WHERE id = $1
+`, jobID, models.ImportJobDone); err != nil {
+		return errors.Wrap(err, "complete import job")
+	}
+	return errors.Wrap(tx.Commit(ctx), "commit tx")
+}
+
+// RetryImportJob ставит упавшую (FAILED) задачу обратно в очередь; обработка продолжится с курсора.
+func (s *Storage) RetryImportJob(ctx context.Context, jobID uint64) (*models.ImportJob, error) {
+	j, err := scanImportJob(s.db.QueryRow(ctx, `
+UPDATE import_jobs
+SET status = $2, attempts = 0, last_error = NULL, finished_at = NULL, updated_at = now()
+WHERE id = $1 AND status = $3 AND total_rows > 0
+RETURNING`+importJobColumns, jobID, models.ImportJobQueued, models.ImportJobFailed))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrImportJobNotFound
+	}
+	return j, errors.Wrap(err, "retry import job")
+}
+
+func (s *Storage) GetImportJob(ctx context.Context, jobID uint64) (*models.ImportJob, error) {
+	j, err := scanImportJob(s.db.QueryRow(ctx, `SELECT`+importJobColumns+` FROM import_jobs WHERE id = $1`, jobID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrImportJobNotFound
+	}
+	return j, errors.Wrap(err, "select import job")
+}
+
+// ListImportErrors — отчёт по невалидным строкам задачи.
+func (s *Storage) ListImportErrors(ctx context.Context, jobID uint64, limit, offset int) ([]models.ImportRow, error) {
+	rows, err := s.db.Query(ctx, `
+SELECT line, carrier_code, track_number, metadata::text, tags, external_id, error
+FROM import_job_rows
+WHERE job_id = $1 AND error IS NOT NULL
+ORDER BY line
+LIMIT $2 OFFSET $3
+`, jobID, limit, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "select import errors")
+	}
+	defer rows.Close()
+
+	var out []models.ImportRow
+	for rows.Next() {
+		var r models.ImportRow
+		if err := rows.Scan(&r.Line, &r.CarrierCode, &r.TrackNumber, &r.Metadata, &r.Tags, &r.ExternalID, &r.Error); err != nil {
+			return nil, errors.Wrap(err, "scan import row")
+		}
+		out = append(out, r)
+	}
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows")
+	}
+	return out, nil
+}
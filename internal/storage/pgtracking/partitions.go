@@ -0,0 +1,183 @@
+package pgtracking
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+)
+
+// Журнальные таблицы секционированы по периодам (UTC): по дням — секция <table>_pYYYYMMDD хранит [день, день+1),
+// по месяцам — <table>_pYYYYMM хранит [месяц, месяц+1). Секции создаются заранее (при старте и при обслуживании),
+// старые удаляются (или отсоединяются) целиком по сроку хранения — без DELETE по большой таблице.
+// Секция <table>_default ловит строки за периоды без своей секции (например, из далёкого прошлого);
+// из неё старые строки удаляются DELETE.
+
+// partitionsAhead — на сколько дней вперёд держать готовые секции журнала проверок.
+const partitionsAhead = 7
+
+// eventPartitionsAhead — на сколько месяцев вперёд (считая текущий) держать готовые секции событий.
+const eventPartitionsAhead = 3
+
+type partitionedTable struct {
+	name    string
+	column  string // ключ секционирования (TIMESTAMPTZ)
+	monthly bool   // секции по месяцам, иначе — по дням
+}
+
+var (
+	checksTable = partitionedTable{name: "tracking_checks", column: "checked_at"}
+	eventsTable = partitionedTable{name: "tracking_events", column: "event_time", monthly: true}
+)
+
+func (t partitionedTable) layout() string {
+	if t.monthly {
+		return "200601"
+	}
+	return "20060102"
+}
+
+// start — начало периода, в который попадает ts.
+func (t partitionedTable) start(ts time.Time) time.Time {
+	if t.monthly {
+		y, m, _ := ts.UTC().Date()
+		return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return utcDay(ts)
+}
+
+// next — начало следующего периода (start — начало периода).
+func (t partitionedTable) next(start time.Time) time.Time {
+	if t.monthly {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+func (t partitionedTable) partition(start time.Time) string {
+	return t.name + "_p" + start.Format(t.layout())
+}
+
+// partitionStart — начало периода секции по её имени; false — это не секция периода (например, _default).
+func (t partitionedTable) partitionStart(name string) (time.Time, bool) {
+	suffix, ok := strings.CutPrefix(name, t.name+"_p")
+	if !ok {
+		return time.Time{}, false
+	}
+	start, err := time.Parse(t.layout(), suffix)
+	return start, err == nil
+}
+
+// ensurePartitions создаёт секции на n периодов, начиная с периода from. Конкурентные вызовы (track-api и track-worker
+// поднимают схему одновременно) сериализуются advisory-блокировкой.
+func (s *Storage) ensurePartitions(ctx context.Context, t partitionedTable, from time.Time, n int) error {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, "partitions:"+t.name); err != nil {
+		return errors.Wrap(err, "lock partitions")
+	}
+	start := t.start(from)
+	for i := 0; i < n; i++ {
+		if err := createPartition(ctx, tx, t, start); err != nil {
+			return err
+		}
+		start = t.next(start)
+	}
+	return errors.Wrap(tx.Commit(ctx), "commit tx")
+}
+
+// createPartition создаёт секцию периода start, если её ещё нет. Если в секции по умолчанию уже лежат строки
+// за этот период, Postgres не даст создать секцию поверх них: тогда таблица создаётся отдельно, строки
+// переносятся в неё из секции по умолчанию и она присоединяется к родительской.
+func createPartition(ctx context.Context, tx pgx.Tx, t partitionedTable, start time.Time) error {
+	name := t.partition(start)
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`, name).Scan(&exists); err != nil {
+		return errors.Wrapf(err, "check partition %s", name)
+	}
+	if exists {
+		return nil
+	}
+	from, to := start.Format(time.RFC3339), t.next(start).Format(time.RFC3339)
+
+	var inDefault bool
+	err := tx.QueryRow(ctx, fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s_default WHERE %s >= $1 AND %s < $2)`, t.name, t.column, t.column),
+		start, t.next(start)).Scan(&inDefault)
+	if err != nil {
+		return errors.Wrapf(err, "check default partition of %s", t.name)
+	}
+	if !inDefault {
+		_, err := tx.Exec(ctx, fmt.Sprintf(`CREATE TABLE %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')`, name, t.name, from, to))
+		return errors.Wrapf(err, "create partition %s", name)
+	}
+
+	stmts := []string{
+		fmt.Sprintf(`CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`, name, t.name),
+		fmt.Sprintf(`WITH moved AS (DELETE FROM %s_default WHERE %s >= '%s' AND %s < '%s' RETURNING *) INSERT INTO %s SELECT * FROM moved`,
+			t.name, t.column, from, t.column, to, name),
+		fmt.Sprintf(`ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')`, t.name, name, from, to),
+	}
+	for _, q := range stmts {
+		if _, err := tx.Exec(ctx, q); err != nil {
+			return errors.Wrapf(err, "create partition %s from default partition", name)
+		}
+	}
+	return nil
+}
+
+// dropPartitionsBefore удаляет секции, целиком лежащие раньше before, и строки старше before из секции по умолчанию.
+// С detachOnly секции только отсоединяются от родительской таблицы (остаются отдельными таблицами, например
+// для выгрузки в архив), а секция по умолчанию не трогается. Возвращает число удалённых (отсоединённых) секций.
+func (s *Storage) dropPartitionsBefore(ctx context.Context, t partitionedTable, before time.Time, detachOnly bool) (int, error) {
+	rows, err := s.db.Query(ctx, `
+SELECT c.relname
+FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+JOIN pg_class p ON p.oid = i.inhparent
+WHERE p.relname = $1
+`, t.name)
+	if err != nil {
+		return 0, errors.Wrap(err, "select partitions")
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return 0, errors.Wrap(err, "scan partition")
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, errors.Wrap(err, "rows")
+	}
+
+	dropped := 0
+	for _, name := range names {
+		start, ok := t.partitionStart(name)
+		if !ok || t.next(start).After(before) {
+			continue
+		}
+		q := `DROP TABLE IF EXISTS ` + name
+		if detachOnly {
+			q = fmt.Sprintf(`ALTER TABLE %s DETACH PARTITION %s`, t.name, name)
+		}
+		if _, err := s.db.Exec(ctx, q); err != nil {
+			return dropped, errors.Wrapf(err, "drop partition %s", name)
+		}
+		dropped++
+	}
+	if detachOnly {
+		return dropped, nil
+	}
+	_, err = s.db.Exec(ctx, fmt.Sprintf(`DELETE FROM %s_default WHERE %s < $1`, t.name, t.column), before.UTC())
+	return dropped, errors.Wrap(err, "delete from default partition")
+}
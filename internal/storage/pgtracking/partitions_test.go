@@ -0,0 +1,30 @@
+package pgtracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionedTable_Periods(t *testing.T) {
+	ts := time.Date(2026, 1, 31, 23, 30, 0, 0, time.FixedZone("MSK", 3*3600)) // 2026-01-31 20:30 UTC
+
+	start := eventsTable.start(ts)
+	require.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), eventsTable.next(start))
+	require.Equal(t, "tracking_events_p202601", eventsTable.partition(start))
+
+	day := checksTable.start(ts)
+	require.Equal(t, time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), day)
+	require.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), checksTable.next(day))
+	require.Equal(t, "tracking_checks_p20260131", checksTable.partition(day))
+
+	got, ok := eventsTable.partitionStart("tracking_events_p202601")
+	require.True(t, ok)
+	require.Equal(t, start, got)
+	_, ok = eventsTable.partitionStart("tracking_events_default")
+	require.False(t, ok)
+	_, ok = eventsTable.partitionStart("tracking_checks_p20260131")
+	require.False(t, ok)
+}
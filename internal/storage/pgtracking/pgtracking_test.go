@@ -49,6 +49,23 @@ func TestPGTracking_RepoFlow(t *testing.T) {
 	require.Len(t, created, 2)
 	require.NotZero(t, created[0].ID)
 
+	// пачка: существующий, новый, повтор нового — порядок и признак Created по каждому элементу
+	bulk, err := st.BulkCreateTrackings(ctx, []models.TrackingCreateInput{
+		{CarrierCode: "POST_RU", TrackNumber: "B2"},
+		{CarrierCode: "CDEK", TrackNumber: "C3"},
+		{CarrierCode: "CDEK", TrackNumber: "C3"},
+	})
+	require.NoError(t, err)
+	require.Len(t, bulk, 3)
+	require.False(t, bulk[0].Created)
+	require.Equal(t, created[1].ID, bulk[0].Tracking.ID)
+	require.True(t, bulk[1].Created)
+	require.Equal(t, models.TrackingStatusUnknown, bulk[1].Tracking.Status)
+	require.False(t, bulk[2].Created)
+	require.Equal(t, bulk[1].Tracking.ID, bulk[2].Tracking.ID)
+	_, err = st.db.Exec(ctx, `DELETE FROM trackings WHERE id = $1`, bulk[1].Tracking.ID)
+	require.NoError(t, err)
+
 	// Делаем ровно один трек "due" и проверяем ClaimDueTrackings + lease
 	_, err = st.db.Exec(ctx, `UPDATE trackings SET next_check_at = now() - interval '1 minute' WHERE id = $1`, created[0].ID)
 	require.NoError(t, err)
@@ -78,13 +95,435 @@ func TestPGTracking_RepoFlow(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	evs, err := st.ListTrackingEvents(ctx, created[0].ID, 10, 0)
+	evs, err := st.ListTrackingEvents(ctx, created[0].ID, EventFilter{}, 10, 0)
 	require.NoError(t, err)
 	require.Len(t, evs, 1)
 	require.WithinDuration(t, evTime, evs[0].EventTime, time.Second)
+	keys, err := st.ListEventKeys(ctx, created[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{evs[0].DedupKey()}, keys)
+
+	// история для планировщика: второе событие через час
+	err = st.ApplyTrackingUpdate(ctx, TrackingUpdate{
+		TrackingID:  created[0].ID,
+		CheckedAt:   now,
+		Status:      models.TrackingStatusInTransit,
+		StatusRaw:   "RAW2",
+		StatusAt:    &now,
+		NextCheckAt: now.Add(30 * time.Minute),
+		Events: []*models.TrackingEvent{
+			{Status: models.TrackingStatusInTransit, StatusRaw: "RAW2", EventTime: evTime.Add(time.Hour)},
+		},
+	})
+	require.NoError(t, err)
+
+	cadence, err := st.CarrierEventCadence(ctx, evTime.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, cadence, 1)
+	require.Equal(t, created[0].CarrierCode, cadence[0].CarrierCode)
+	require.InDelta(t, time.Hour.Seconds(), cadence[0].MedianGap.Seconds(), 1)
+
+	histories, err := st.LoadTrackingHistories(ctx, "", now.Add(-time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, histories, 1)
+	require.Len(t, histories[0].Events, 2)
+
+	// страницы по курсору (event_time, id) в обе стороны, фильтр по времени, число событий
+	evPage, err := st.ListTrackingEventsPage(ctx, created[0].ID, EventFilter{}, false, nil, 1)
+	require.NoError(t, err)
+	require.Len(t, evPage, 1)
+	require.Equal(t, "RAW2", evPage[0].StatusRaw)
+	evPage, err = st.ListTrackingEventsPage(ctx, created[0].ID, EventFilter{}, false, &EventCursor{EventTime: evPage[0].EventTime, ID: evPage[0].ID}, 10)
+	require.NoError(t, err)
+	require.Len(t, evPage, 1)
+	require.Equal(t, "RAW", evPage[0].StatusRaw)
+	evPage, err = st.ListTrackingEventsPage(ctx, created[0].ID, EventFilter{}, true, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, evPage, 2)
+	require.Equal(t, "RAW", evPage[0].StatusRaw)
+	n, err := st.CountTrackingEvents(ctx, created[0].ID, EventFilter{From: evTime.Add(time.Minute)})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, n)
+
+	// последние события нескольких треков одним запросом
+	latest, err := st.ListLatestEvents(ctx, []uint64{created[0].ID, created[1].ID}, 1, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, latest[created[0].ID], 1)
+	require.Equal(t, "RAW2", latest[created[0].ID][0].StatusRaw)
+	require.Empty(t, latest[created[1].ID])
+	latest, err = st.ListLatestEvents(ctx, []uint64{created[0].ID}, 10, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, latest[created[0].ID])
+
+	// scan (export/replay в CLI)
+	all, err := st.ScanTrackings(ctx, TrackingFilter{}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	require.Less(t, all[0].ID, all[1].ID)
+	page, err := st.ScanTrackings(ctx, TrackingFilter{Status: models.TrackingStatusInTransit}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, created[0].ID, page[0].ID)
+	page, err = st.ScanTrackings(ctx, TrackingFilter{CarrierCode: "POST_RU"}, created[1].ID, 10)
+	require.NoError(t, err)
+	require.Empty(t, page)
+
+	byTracking, err := st.ListEventsForTrackings(ctx, []uint64{created[0].ID, created[1].ID})
+	require.NoError(t, err)
+	require.Len(t, byTracking[created[0].ID], 2)
+	require.True(t, byTracking[created[0].ID][0].EventTime.Before(byTracking[created[0].ID][1].EventTime))
+	require.Empty(t, byTracking[created[1].ID])
+
+	// задачи импорта: загрузка, захват, курсор, завершение
+	job, err := st.CreateImportJob(ctx, "csv", "test.csv")
+	require.NoError(t, err)
+	require.Equal(t, models.ImportJobUploading, job.Status)
+	bad := "carrier_code is required"
+	meta := `{"shop":"x"}`
+	require.NoError(t, st.AddImportRows(ctx, job.ID, []models.ImportRow{
+		{Line: 2, CarrierCode: "CDEK", TrackNumber: "C3", Metadata: &meta},
+		{Line: 3, TrackNumber: "D4", Error: &bad},
+	}))
+	claimed, err := st.ClaimImportJob(ctx, time.Now(), time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, claimed) // ещё загружается
+	require.NoError(t, st.FinishImportUpload(ctx, job.ID, 2, 1))
+
+	claimed, err = st.ClaimImportJob(ctx, time.Now(), time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, job.ID, claimed.ID)
+	require.EqualValues(t, 1, claimed.Attempts)
+	again, err := st.ClaimImportJob(ctx, time.Now(), time.Minute)
+	require.NoError(t, err)
+	require.Nil(t, again) // аренда держится
+
+	rows, err := st.NextImportRows(ctx, job.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.JSONEq(t, meta, *rows[0].Metadata)
+	require.NoError(t, st.AdvanceImportJob(ctx, job.ID, 3, 2, 1, time.Now().Add(time.Minute)))
+	require.NoError(t, st.CompleteImportJob(ctx, job.ID))
+
+	job, err = st.GetImportJob(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.ImportJobDone, job.Status)
+	require.EqualValues(t, 3, job.Cursor)
+	importErrs, err := st.ListImportErrors(ctx, job.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, importErrs, 1)
+	require.EqualValues(t, 3, importErrs[0].Line)
+	_, err = st.RetryImportJob(ctx, job.ID)
+	require.ErrorIs(t, err, ErrImportJobNotFound)
+
+	// справочник перевозчиков: встроенные засеяны схемой
+	carriers, err := st.ListCarriers(ctx)
+	require.NoError(t, err)
+	require.Len(t, carriers, 2)
+	policy := `{"jitter": "none"}`
+	dhl, err := st.CreateCarrier(ctx, models.Carrier{Code: "DHL", DisplayName: "DHL", Backend: models.CarrierBackendFake, SchedulingJSON: &policy, Enabled: true})
+	require.NoError(t, err)
+	require.JSONEq(t, policy, *dhl.SchedulingJSON)
+	_, err = st.CreateCarrier(ctx, models.Carrier{Code: "DHL", DisplayName: "DHL"})
+	require.ErrorIs(t, err, ErrCarrierExists)
+	// меняются только поля из списка: enabled=false и пустое имя в запросе не применяются
+	dhl, err = st.UpdateCarrier(ctx, models.Carrier{Code: "DHL", RateLimitPerMinute: 30},
+		[]string{models.CarrierFieldRateLimit, models.CarrierFieldScheduling})
+	require.NoError(t, err)
+	require.EqualValues(t, 30, dhl.RateLimitPerMinute)
+	require.Nil(t, dhl.SchedulingJSON)
+	require.Equal(t, "DHL", dhl.DisplayName)
+	require.True(t, dhl.Enabled)
+	_, err = st.SetCarrierEnabled(ctx, "UPS", false)
+	require.ErrorIs(t, err, ErrCarrierNotFound)
+
+	// треки отключённого перевозчика не выбираются на проверку
+	_, err = st.SetCarrierEnabled(ctx, created[0].CarrierCode, false)
+	require.NoError(t, err)
+	require.NoError(t, st.RefreshTracking(ctx, created[0].ID))
+	due, err = st.ClaimDueTrackings(ctx, time.Now().Add(time.Minute), 100, lease)
+	require.NoError(t, err)
+	for _, tr := range due {
+		require.NotEqual(t, created[0].CarrierCode, tr.CarrierCode)
+	}
+	_, err = st.SetCarrierEnabled(ctx, created[0].CarrierCode, true)
+	require.NoError(t, err)
+
+	// пользовательские атрибуты: создание, частичное изменение, фильтры
+	attrMeta, ext := `{"shop": "x", "n": 1}`, "ORD-1"
+	withAttrs, err := st.BulkCreateTrackings(ctx, []models.TrackingCreateInput{
+		{CarrierCode: "CDEK", TrackNumber: "E5", Metadata: &attrMeta, Tags: []string{"vip", "b2b"}, ExternalID: &ext},
+		{CarrierCode: "CDEK", TrackNumber: "E5", Tags: []string{"ignored"}},
+	})
+	require.NoError(t, err)
+	tr := withAttrs[0].Tracking
+	require.Equal(t, tr.ID, withAttrs[1].Tracking.ID)
+	require.Equal(t, []string{"vip", "b2b"}, tr.Tags)
+	require.JSONEq(t, attrMeta, *tr.Metadata)
+	require.Equal(t, ext, *tr.ExternalID)
+	require.Empty(t, created[0].Tags)
+
+	page, err = st.ScanTrackings(ctx, TrackingFilter{Tags: []string{"vip"}, ExternalID: ext, Metadata: `{"shop":"x"}`}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, tr.ID, page[0].ID)
+	page, err = st.ScanTrackings(ctx, TrackingFilter{Tags: []string{"vip", "other"}}, 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, page)
+
+	empty := ""
+	tr, err = st.UpdateTracking(ctx, tr.ID, models.TrackingPatch{ExternalID: &empty, AddTags: []string{"vip", "late"}, RemoveTags: []string{"b2b"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"vip", "late"}, tr.Tags)
+	require.Nil(t, tr.ExternalID)
+	require.JSONEq(t, attrMeta, *tr.Metadata) // не задано — не меняется
+	tr, err = st.UpdateTracking(ctx, tr.ID, models.TrackingPatch{Metadata: &empty, ClearTags: true})
+	require.NoError(t, err)
+	require.Empty(t, tr.Tags)
+	require.Nil(t, tr.Metadata)
+	_, err = st.UpdateTracking(ctx, 0, models.TrackingPatch{})
+	require.ErrorIs(t, err, ErrTrackingNotFound)
+
+	// сведения об отправлении: пустые поля не затирают сохранённые, смена ETA попадает в историю
+	eta := now.Add(48 * time.Hour).Truncate(time.Second)
+	for _, sd := range []*models.ShipmentDetails{
+		{EstimatedDelivery: &eta, WeightGrams: 350, Origin: "Москва"},
+		{EstimatedDelivery: &eta, Destination: "Казань"}, // та же ETA — без записи в историю
+		{EstimatedDelivery: ptrTime(eta.Add(24 * time.Hour))},
+	} {
+		require.NoError(t, st.ApplyTrackingUpdate(ctx, TrackingUpdate{
+			TrackingID: created[1].ID, CheckedAt: now, Status: models.TrackingStatusInTransit, StatusRaw: "RAW",
+			NextCheckAt: now.Add(time.Hour), Shipment: sd,
+		}))
+	}
+	got, err := st.GetTrackingsByIDs(ctx, []uint64{created[1].ID})
+	require.NoError(t, err)
+	require.NotNil(t, got[0].Shipment)
+	require.WithinDuration(t, eta.Add(24*time.Hour), *got[0].Shipment.EstimatedDelivery, time.Second)
+	require.EqualValues(t, 350, got[0].Shipment.WeightGrams)
+	require.Equal(t, "Москва", got[0].Shipment.Origin)
+	require.Equal(t, "Казань", got[0].Shipment.Destination)
+	history, err := st.ListETAHistory(ctx, created[1].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Nil(t, history[0].Previous)
+	require.Equal(t, 24*time.Hour, history[1].Slip())
+
+	// структурированное место события и фильтр по региону
+	lat, lon := 55.79, 49.1
+	raw := "01.01.2025 10:00"
+	require.NoError(t, st.ApplyTrackingUpdate(ctx, TrackingUpdate{
+		TrackingID: created[1].ID, CheckedAt: now, Status: models.TrackingStatusInTransit, StatusRaw: "RAW",
+		NextCheckAt: now.Add(time.Hour),
+		Events: []*models.TrackingEvent{
+			{StatusRaw: "kzn", EventTime: now, Place: &models.Location{Country: "RU", Region: "Республика Татарстан", City: "Казань", PostalCode: "420300", Lat: &lat, Lon: &lon}},
+			{StatusRaw: "msk", EventTime: now.Add(-time.Hour), Place: &models.Location{PostalCode: "101000"}},
+			{StatusRaw: "none", EventTime: now.Add(-2 * time.Hour), EventTimeRaw: &raw, TimeInferred: true},
+		},
+	}))
+	evs, err = st.ListTrackingEvents(ctx, created[1].ID, EventFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, evs, 3)
+	require.Equal(t, &models.Location{Country: "RU", Region: "Республика Татарстан", City: "Казань", PostalCode: "420300", Lat: &lat, Lon: &lon}, evs[0].Place)
+	require.Equal(t, &models.Location{PostalCode: "101000"}, evs[1].Place)
+	require.Nil(t, evs[2].Place)
+	require.Nil(t, evs[0].EventTimeRaw)
+	require.False(t, evs[0].TimeInferred)
+	require.Equal(t, raw, *evs[2].EventTimeRaw)
+	require.True(t, evs[2].TimeInferred)
+	evs, err = st.ListTrackingEvents(ctx, created[1].ID, EventFilter{Country: "ru", Region: "республика татарстан"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, evs, 1)
+	require.Equal(t, "kzn", evs[0].StatusRaw)
+
+	// перевозчик исправил место события и убрал другое: ревизии вместо дублей, отпечаток сохраняется
+	fixed := "Казань МСЦ"
+	require.NoError(t, st.ApplyTrackingUpdate(ctx, TrackingUpdate{
+		TrackingID: created[1].ID, CheckedAt: now.Add(time.Minute), Status: models.TrackingStatusInTransit, StatusRaw: "RAW",
+		NextCheckAt: now.Add(time.Hour), Fingerprint: "fp1",
+		Events: []*models.TrackingEvent{
+			{StatusRaw: "kzn", EventTime: now, Location: &fixed},
+			{StatusRaw: "none", EventTime: now.Add(-2 * time.Hour), EventTimeRaw: &raw, TimeInferred: true},
+		},
+	}))
+	evs, err = st.ListTrackingEvents(ctx, created[1].ID, EventFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, evs, 2)
+	require.Equal(t, fixed, *evs[0].Location)
+	require.EqualValues(t, 1, evs[0].Revision)
+	revs, err := st.ListEventRevisions(ctx, created[1].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, revs, 2)
+	kinds := map[string]string{revs[0].Kind: revs[0].Previous.StatusRaw, revs[1].Kind: revs[1].Previous.StatusRaw}
+	require.Equal(t, map[string]string{models.EventRevisionEdited: "kzn", models.EventRevisionRemoved: "msk"}, kinds)
+	got, err = st.GetTrackingsByIDs(ctx, []uint64{created[1].ID})
+	require.NoError(t, err)
+	require.Equal(t, "fp1", got[0].Fingerprint)
+
+	// алерты SLA: один открытый алерт вида на трек, закрытие и отметка публикации
+	subs, err := st.ListSLASubjects(ctx, 0, 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, subs)
+	ok, err := st.OpenAlert(ctx, models.Alert{TrackingID: created[1].ID, Kind: models.AlertKindNoEvents, Rule: "default", OpenedAt: now})
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = st.OpenAlert(ctx, models.Alert{TrackingID: created[1].ID, Kind: models.AlertKindNoEvents, Rule: "default", OpenedAt: now})
+	require.NoError(t, err)
+	require.False(t, ok)
+	subs, err = st.ListSLASubjects(ctx, created[1].ID-1, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{models.AlertKindNoEvents}, subs[0].OpenKinds)
+	require.NotNil(t, subs[0].LastEventAt)
+	pending, err := st.ListUnpublishedAlerts(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.NoError(t, st.MarkAlertPublished(ctx, pending[0].ID, models.AlertStateOpen))
+	require.NoError(t, st.ResolveAlerts(ctx, created[1].ID, []string{models.AlertKindNoEvents}, now.Add(time.Hour)))
+	alerts, err := st.ListAlerts(ctx, models.AlertFilter{State: models.AlertStateResolved, TrackingID: created[1].ID}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	require.Equal(t, created[1].TrackNumber, alerts[0].TrackNumber)
+	pending, err = st.ListUnpublishedAlerts(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	// блокировка прохода SLA: второй воркер её не получает, после unlock — снова свободна
+	unlock, ok, err := st.TryLockSLA(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, ok, err = st.TryLockSLA(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+	unlock()
+	unlock, ok, err = st.TryLockSLA(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	unlock()
+
+	// агрегаты аналитики: повторы проверки с тем же checked_at не считаются, доставка попадает в гистограмму
+	deliveredAt := now.Add(22 * time.Hour)
+	require.NoError(t, st.ApplyTrackingUpdate(ctx, TrackingUpdate{
+		TrackingID: created[1].ID, CheckedAt: now.Add(2 * time.Minute), Status: models.TrackingStatusDelivered, StatusRaw: "DONE",
+		StatusAt: &deliveredAt, NextCheckAt: now.Add(time.Hour),
+	}))
+	counts, err := st.StatusCounts(ctx, "POST_RU")
+	require.NoError(t, err)
+	byStatus := map[string]int64{}
+	for _, c := range counts {
+		byStatus[c.Status] = c.Trackings
+	}
+	require.Equal(t, map[string]int64{models.TrackingStatusDelivered: 1}, byStatus) // нулевые строки не отдаются
+	daily, err := st.DailyStats(ctx, "POST_RU", now.Add(-24*time.Hour), now.Add(24*time.Hour))
+	require.NoError(t, err)
+	var sum models.CarrierDayStats
+	for _, d := range daily {
+		sum.Checks += d.Checks
+		sum.StatusChanges += d.StatusChanges
+		sum.Delivered += d.Delivered
+	}
+	require.Equal(t, models.CarrierDayStats{Checks: 3, StatusChanges: 2, Delivered: 1}, sum)
+	entries, err := st.StatusEntries(ctx, "POST_RU", now.Add(-24*time.Hour), now.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	hist, err := st.TransitHistogram(ctx, "", now.Add(-24*time.Hour), now.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, hist, 1)
+	require.Equal(t, models.TransitBucketIndex(24*time.Hour), hist[0].Bucket) // от первого события (now-2h)
+	require.EqualValues(t, 1, hist[0].Deliveries)
+
+	// журнал проверок: повтор сообщения не дублирует запись, страницы от новых к старым, срок хранения
+	old := now.AddDate(0, 0, -40)
+	require.NoError(t, st.ensurePartitions(ctx, checksTable, old, 1))
+	boom := "carrier emulator http 502"
+	for _, upd := range []TrackingUpdate{
+		{TrackingID: created[0].ID, CheckedAt: old, Error: &boom, NextCheckAt: now,
+			Check: &models.TrackingCheck{TrackingID: created[0].ID, CheckedAt: old, Error: &boom, ErrorClass: "HTTP", HTTPStatus: 502}},
+		{TrackingID: created[0].ID, CheckedAt: now.Add(time.Minute), Error: &boom, NextCheckAt: now,
+			Check: &models.TrackingCheck{TrackingID: created[0].ID, CheckedAt: now.Add(time.Minute), Error: &boom, ErrorClass: "TIMEOUT", Worker: "w1", LatencyMS: 10000}},
+		{TrackingID: created[0].ID, CheckedAt: now.Add(time.Minute), Error: &boom, NextCheckAt: now,
+			Check: &models.TrackingCheck{TrackingID: created[0].ID, CheckedAt: now.Add(time.Minute), Error: &boom, ErrorClass: "TIMEOUT", Worker: "w1", LatencyMS: 10000}},
+		{TrackingID: created[0].ID, CheckedAt: now.Add(2 * time.Minute), Status: models.TrackingStatusInTransit, StatusRaw: "RAW2", NextCheckAt: now,
+			Check: &models.TrackingCheck{TrackingID: created[0].ID, CheckedAt: now.Add(2 * time.Minute), Status: models.TrackingStatusInTransit, Backend: "fake", EventsCount: 2, ResponseHash: "abc", ResponseRef: "fake/ab/abc.json.gz"}},
+	} {
+		require.NoError(t, st.ApplyTrackingUpdate(ctx, upd))
+	}
+	checks, err := st.ListTrackingChecks(ctx, created[0].ID, false, time.Time{}, 10)
+	require.NoError(t, err)
+	require.Len(t, checks, 3)
+	require.Equal(t, "abc", checks[0].ResponseHash)
+	require.Equal(t, "fake/ab/abc.json.gz", checks[0].ResponseRef)
+	require.Equal(t, 2, checks[0].EventsCount)
+	require.Nil(t, checks[0].Error)
+	require.Equal(t, "TIMEOUT", checks[1].ErrorClass)
+	require.EqualValues(t, 10000, checks[1].LatencyMS)
+	checks, err = st.ListTrackingChecks(ctx, created[0].ID, true, checks[1].CheckedAt, 10)
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	require.Equal(t, 502, checks[0].HTTPStatus)
+	dropped, err := st.MaintainCheckLog(ctx, now, 30*24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 1, dropped)
+	checks, err = st.ListTrackingChecks(ctx, created[0].ID, false, time.Time{}, 10)
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+
+	// события: секция создаётся и из строк секции по умолчанию; события доставленных треков удаляются по сроку
+	future := now.AddDate(1, 0, 0)
+	require.NoError(t, st.ApplyTrackingUpdate(ctx, TrackingUpdate{
+		TrackingID: created[0].ID, CheckedAt: now.Add(3 * time.Minute), Status: models.TrackingStatusInTransit, StatusRaw: "RAW3", NextCheckAt: now,
+		Events: []*models.TrackingEvent{{Status: models.TrackingStatusInTransit, StatusRaw: "RAW3", EventTime: future}},
+	}))
+	require.NoError(t, st.ensurePartitions(ctx, eventsTable, future, 1))
+	evs, err = st.ListTrackingEvents(ctx, created[0].ID, EventFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, evs, 3)
+	require.WithinDuration(t, future, evs[0].EventTime, time.Second)
+	res, err := st.MaintainEvents(ctx, now.Add(48*time.Hour), EventRetention{
+		ByStatus: map[string]time.Duration{models.TrackingStatusDelivered: 24 * time.Hour},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, res.Trackings)
+	require.Positive(t, res.Events)
+	evs, err = st.ListTrackingEvents(ctx, created[1].ID, EventFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Empty(t, evs)
+	evs, err = st.ListTrackingEvents(ctx, created[0].ID, EventFilter{}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, evs, 3)
+
+	// лента изменений: всё по (change_seq, вид, id), после курсора — только новые изменения, удаление — отметкой
+	changes, err := st.ListChanges(ctx, ChangeCursor{}, 1000)
+	require.NoError(t, err)
+	require.NotEmpty(t, changes)
+	seen := map[int]int{}
+	for i, c := range changes {
+		seen[c.Kind]++
+		if i > 0 {
+			p := changes[i-1]
+			require.True(t, p.Seq < c.Seq || p.Seq == c.Seq && (p.Kind < c.Kind || p.Kind == c.Kind && p.ID() < c.ID()), "order at %d", i)
+		}
+	}
+	require.Equal(t, 2, seen[models.ChangeTracking])
+	require.Positive(t, seen[models.ChangeEvent])
+	last := changes[len(changes)-1]
+	cursor := ChangeCursor{Seq: last.Seq, Kind: last.Kind, ID: last.ID()}
+	_, err = st.UpdateTracking(ctx, created[0].ID, models.TrackingPatch{AddTags: []string{"vip"}})
+	require.NoError(t, err)
+	require.NoError(t, st.DeleteTracking(ctx, created[1].ID))
+	require.ErrorIs(t, st.DeleteTracking(ctx, created[1].ID), ErrTrackingNotFound)
+	changes, err = st.ListChanges(ctx, cursor, 1000)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, []string{"vip"}, changes[0].Tracking.Tags)
+	require.Equal(t, created[1].ID, changes[1].Deleted.TrackingID)
+	require.Less(t, changes[0].Seq, changes[1].Seq)
+	counts, err = st.StatusCounts(ctx, "POST_RU")
+	require.NoError(t, err)
+	require.Empty(t, counts)
 
 	// refresh
 	require.NoError(t, st.RefreshTracking(ctx, created[0].ID))
 }
 
+func ptrTime(t time.Time) *time.Time { return &t }
+
 
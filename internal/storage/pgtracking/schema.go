@@ -2,6 +2,7 @@ package pgtracking
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -25,41 +26,187 @@ CREATE TABLE IF NOT EXISTS trackings (
   UNIQUE (carrier_code, track_number)
 )`,
 		`CREATE INDEX IF NOT EXISTS idx_trackings_next_check_at ON trackings(next_check_at)`,
+		// Асинхронный импорт (CSV/JSONL): задача + её строки; обработанные валидные строки удаляются по завершении.
 		`
-CREATE TABLE IF NOT EXISTS tracking_events (
+CREATE TABLE IF NOT EXISTS import_jobs (
   id BIGSERIAL PRIMARY KEY,
-  tracking_id BIGINT NOT NULL REFERENCES trackings(id) ON DELETE CASCADE,
   status TEXT NOT NULL,
-  status_raw TEXT NOT NULL,
-  event_time TIMESTAMPTZ NOT NULL,
-  location TEXT NOT NULL DEFAULT '',
-  message TEXT NOT NULL DEFAULT '',
-  payload JSONB NULL,
-  created_at TIMESTAMPTZ NOT NULL
+  format TEXT NOT NULL,
+  source TEXT NOT NULL DEFAULT '',
+  total_rows BIGINT NOT NULL DEFAULT 0,
+  invalid_rows BIGINT NOT NULL DEFAULT 0,
+  processed_rows BIGINT NOT NULL DEFAULT 0,
+  imported_rows BIGINT NOT NULL DEFAULT 0,
+  cursor_line BIGINT NOT NULL DEFAULT 0,
+  attempts INT NOT NULL DEFAULT 0,
+  last_error TEXT NULL,
+  lease_until TIMESTAMPTZ NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL,
+  finished_at TIMESTAMPTZ NULL
 )`,
-		`CREATE INDEX IF NOT EXISTS idx_tracking_events_tracking_id_event_time ON tracking_events(tracking_id, event_time DESC)`,
-		// Migrations for older schemas (when location/message were nullable).
-		`UPDATE tracking_events SET location = '' WHERE location IS NULL`,
-		`UPDATE tracking_events SET message = '' WHERE message IS NULL`,
-		`ALTER TABLE tracking_events ALTER COLUMN location SET DEFAULT ''`,
-		`ALTER TABLE tracking_events ALTER COLUMN message SET DEFAULT ''`,
-		`ALTER TABLE tracking_events ALTER COLUMN location SET NOT NULL`,
-		`ALTER TABLE tracking_events ALTER COLUMN message SET NOT NULL`,
-		// Remove existing duplicates (so we can create unique index).
+		`CREATE INDEX IF NOT EXISTS idx_import_jobs_status ON import_jobs(status) WHERE status IN ('QUEUED', 'RUNNING', 'UPLOADING')`,
 		`
-WITH ranked AS (
-  SELECT id,
-         ROW_NUMBER() OVER (
-           PARTITION BY tracking_id, status_raw, event_time, location, message
-           ORDER BY id
-         ) AS rn
-  FROM tracking_events
-)
-DELETE FROM tracking_events
-WHERE id IN (SELECT id FROM ranked WHERE rn > 1)
+CREATE TABLE IF NOT EXISTS import_job_rows (
+  job_id BIGINT NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+  line BIGINT NOT NULL,
+  carrier_code TEXT NOT NULL DEFAULT '',
+  track_number TEXT NOT NULL DEFAULT '',
+  metadata JSONB NULL,
+  error TEXT NULL,
+  PRIMARY KEY (job_id, line)
+)`,
+		`CREATE INDEX IF NOT EXISTS idx_trackings_updated_at ON trackings(updated_at)`,
+		// Справочник перевозчиков (админский API); встроенные перевозчики добавляются при первом запуске.
+		`
+CREATE TABLE IF NOT EXISTS carriers (
+  code TEXT PRIMARY KEY,
+  display_name TEXT NOT NULL,
+  backend TEXT NOT NULL DEFAULT '',
+  credentials_ref TEXT NOT NULL DEFAULT '',
+  rate_limit_per_minute INT NOT NULL DEFAULT 0,
+  scheduling JSONB NULL,
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL
+)`,
+		`
+INSERT INTO carriers (code, display_name, created_at, updated_at)
+VALUES ('CDEK', 'СДЭК', now(), now()), ('POST_RU', 'Почта России', now(), now())
+ON CONFLICT (code) DO NOTHING
 `,
-		// Enforce de-duplication of events for a tracking.
-		`CREATE UNIQUE INDEX IF NOT EXISTS uq_tracking_events_dedup ON tracking_events(tracking_id, status_raw, event_time, location, message)`,
+		// Пользовательские атрибуты треков: метаданные, теги, внешний id.
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS metadata JSONB NULL`,
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}'`,
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS external_id TEXT NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_trackings_tags ON trackings USING GIN (tags)`,
+		`CREATE INDEX IF NOT EXISTS idx_trackings_metadata ON trackings USING GIN (metadata jsonb_path_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_trackings_external_id ON trackings(external_id) WHERE external_id IS NOT NULL`,
+		`ALTER TABLE import_job_rows ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}'`,
+		`ALTER TABLE import_job_rows ADD COLUMN IF NOT EXISTS external_id TEXT NULL`,
+		// Сведения об отправлении от перевозчика и история ETA.
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS estimated_delivery TIMESTAMPTZ NULL`,
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS weight_grams INT NULL`,
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS origin TEXT NULL`,
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS destination TEXT NULL`,
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS recipient_city TEXT NULL`,
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS service_type TEXT NULL`,
+		`
+CREATE TABLE IF NOT EXISTS tracking_eta_history (
+  id BIGSERIAL PRIMARY KEY,
+  tracking_id BIGINT NOT NULL REFERENCES trackings(id) ON DELETE CASCADE,
+  estimated_delivery TIMESTAMPTZ NOT NULL,
+  previous_estimated_delivery TIMESTAMPTZ NULL,
+  observed_at TIMESTAMPTZ NOT NULL
+)`,
+		`CREATE INDEX IF NOT EXISTS idx_tracking_eta_history_tracking ON tracking_eta_history(tracking_id, observed_at)`,
+		// Обнаружение изменений: отпечаток последнего ответа перевозчика и ревизии событий
+		// (исправленные и пропавшие у перевозчика события не дублируются, а записываются как ревизии).
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS fingerprint TEXT NULL`,
+		`
+CREATE TABLE IF NOT EXISTS tracking_event_revisions (
+  id BIGSERIAL PRIMARY KEY,
+  tracking_id BIGINT NOT NULL REFERENCES trackings(id) ON DELETE CASCADE,
+  event_id BIGINT NOT NULL,
+  kind TEXT NOT NULL,
+  previous JSONB NOT NULL,
+  observed_at TIMESTAMPTZ NOT NULL
+)`,
+		`CREATE INDEX IF NOT EXISTS idx_tracking_event_revisions_tracking ON tracking_event_revisions(tracking_id, observed_at)`,
+		// Алерты SLA: открытый алерт одного вида у трека один; published_state — последнее состояние,
+		// ушедшее в Kafka (tracking.alert), чтобы не терять уведомления при сбоях публикации.
+		`
+CREATE TABLE IF NOT EXISTS alerts (
+  id BIGSERIAL PRIMARY KEY,
+  tracking_id BIGINT NOT NULL REFERENCES trackings(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL,
+  rule TEXT NOT NULL,
+  details TEXT NOT NULL DEFAULT '',
+  opened_at TIMESTAMPTZ NOT NULL,
+  resolved_at TIMESTAMPTZ NULL,
+  published_state TEXT NULL
+)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS uq_alerts_open ON alerts(tracking_id, kind) WHERE resolved_at IS NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_alerts_tracking ON alerts(tracking_id, id)`,
+		`CREATE INDEX IF NOT EXISTS idx_alerts_unpublished ON alerts(id) WHERE published_state IS DISTINCT FROM ` + alertStateExpr,
+		// Агрегаты для аналитики (см. analytics_repo.go): ведутся инкрементально при создании треков
+		// и в ApplyTrackingUpdate, дни — UTC.
+		`
+CREATE TABLE IF NOT EXISTS carrier_status_counts (
+  carrier_code TEXT NOT NULL,
+  status TEXT NOT NULL,
+  trackings BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (carrier_code, status)
+)`,
+		`
+CREATE TABLE IF NOT EXISTS carrier_daily_stats (
+  day DATE NOT NULL,
+  carrier_code TEXT NOT NULL,
+  checks BIGINT NOT NULL DEFAULT 0,
+  failed_checks BIGINT NOT NULL DEFAULT 0,
+  status_changes BIGINT NOT NULL DEFAULT 0,
+  delivered BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (day, carrier_code)
+)`,
+		`
+CREATE TABLE IF NOT EXISTS carrier_status_daily (
+  day DATE NOT NULL,
+  carrier_code TEXT NOT NULL,
+  status TEXT NOT NULL,
+  entered BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (day, carrier_code, status)
+)`,
+		`
+CREATE TABLE IF NOT EXISTS carrier_transit_histogram (
+  day DATE NOT NULL,
+  carrier_code TEXT NOT NULL,
+  bucket SMALLINT NOT NULL,
+  deliveries BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (day, carrier_code, bucket)
+)`,
+		// Журнал проверок (см. checks_repo.go, partitions.go): секции по дням, срок хранения —
+		// trackbox.check_log_retention_days.
+		`
+CREATE TABLE IF NOT EXISTS tracking_checks (
+  tracking_id BIGINT NOT NULL REFERENCES trackings(id) ON DELETE CASCADE,
+  checked_at TIMESTAMPTZ NOT NULL,
+  kind TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT '',
+  error TEXT NULL,
+  error_class TEXT NOT NULL DEFAULT '',
+  worker TEXT NOT NULL DEFAULT '',
+  backend TEXT NOT NULL DEFAULT '',
+  latency_ms BIGINT NOT NULL DEFAULT 0,
+  http_status INT NOT NULL DEFAULT 0,
+  events_count INT NOT NULL DEFAULT 0,
+  response_hash TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY (tracking_id, checked_at)
+) PARTITION BY RANGE (checked_at)`,
+		`CREATE TABLE IF NOT EXISTS tracking_checks_default PARTITION OF tracking_checks DEFAULT`,
+		`ALTER TABLE tracking_checks ADD COLUMN IF NOT EXISTS response_ref TEXT NOT NULL DEFAULT ''`,
+		// Границы событий трека для отсечения секций tracking_events (см. events_partitions.go).
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS event_time_min TIMESTAMPTZ NULL`,
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS event_time_max TIMESTAMPTZ NULL`,
+		// Лента изменений (см. changes_repo.go): номер последнего изменения трека и отметки об удалённых треках.
+		// Уже существующие треки получают номер 0 и отдаются в начале ленты.
+		`
+CREATE TABLE IF NOT EXISTS tracking_change_counter (
+  id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+  seq BIGINT NOT NULL
+)`,
+		`INSERT INTO tracking_change_counter (id, seq) VALUES (true, 0) ON CONFLICT (id) DO NOTHING`,
+		`ALTER TABLE trackings ADD COLUMN IF NOT EXISTS change_seq BIGINT NOT NULL DEFAULT 0`,
+		`CREATE INDEX IF NOT EXISTS idx_trackings_change_seq ON trackings(change_seq, id)`,
+		`
+CREATE TABLE IF NOT EXISTS tracking_tombstones (
+  tracking_id BIGINT PRIMARY KEY,
+  carrier_code TEXT NOT NULL,
+  track_number TEXT NOT NULL,
+  external_id TEXT NULL,
+  deleted_at TIMESTAMPTZ NOT NULL,
+  change_seq BIGINT NOT NULL
+)`,
+		`CREATE INDEX IF NOT EXISTS idx_tracking_tombstones_change_seq ON tracking_tombstones(change_seq, tracking_id)`,
 	}
 
 	for _, q := range stmts {
@@ -67,7 +214,14 @@ WHERE id IN (SELECT id FROM ranked WHERE rn > 1)
 			return errors.Wrap(err, "init schema")
 		}
 	}
-	return nil
+	// События — после trackings (внешний ключ, границы событий): секции по месяцам, см. events_partitions.go.
+	if err := s.initEventsSchema(ctx, time.Now()); err != nil {
+		return err
+	}
+	if err := s.ensurePartitions(ctx, checksTable, time.Now(), partitionsAhead); err != nil {
+		return err
+	}
+	return s.backfillRollups(ctx)
 }
 
 
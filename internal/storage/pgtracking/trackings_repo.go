@@ -2,6 +2,7 @@ package pgtracking
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/BearBump/TrackBox/internal/models"
@@ -14,8 +15,96 @@ const (
 	defaultInitialStatusRaw = "UNKNOWN"
 )
 
+// ErrTrackingNotFound — трека с таким id нет.
+var ErrTrackingNotFound = errors.New("tracking not found")
+
+const trackingColumns = `
+  id, carrier_code, track_number,
+  status, status_raw,
+  status_at, last_checked_at, next_check_at,
+  check_fail_count, last_error,
+  created_at, updated_at,
+  metadata::text, tags, external_id,
+  estimated_delivery, weight_grams, origin, destination, recipient_city, service_type,
+  fingerprint`
+
+func scanTracking(row pgx.Row, extra ...any) (*models.Tracking, error) {
+	var t models.Tracking
+	var sd models.ShipmentDetails
+	var weight *int32
+	var origin, destination, recipientCity, serviceType, fingerprint *string
+	dest := append(extra,
+		&t.ID, &t.CarrierCode, &t.TrackNumber,
+		&t.Status, &t.StatusRaw,
+		&t.StatusAt, &t.LastCheckedAt, &t.NextCheckAt,
+		&t.CheckFailCount, &t.LastError,
+		&t.CreatedAt, &t.UpdatedAt,
+		&t.Metadata, &t.Tags, &t.ExternalID,
+		&sd.EstimatedDelivery, &weight, &origin, &destination, &recipientCity, &serviceType,
+		&fingerprint,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if weight != nil {
+		sd.WeightGrams = *weight
+	}
+	sd.Origin, sd.Destination = deref(origin), deref(destination)
+	sd.RecipientCity, sd.ServiceType = deref(recipientCity), deref(serviceType)
+	t.Fingerprint = deref(fingerprint)
+	if !sd.IsEmpty() {
+		t.Shipment = &sd
+	}
+	return &t, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// CreateOrGetTrackings создаёт недостающие треки и возвращает все треки из items в том же порядке.
 func (s *Storage) CreateOrGetTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]*models.Tracking, error) {
-	now := time.Now().UTC()
+	res, err := s.BulkCreateTrackings(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*models.Tracking, 0, len(res))
+	for _, r := range res {
+		out = append(out, r.Tracking)
+	}
+	return out, nil
+}
+
+// CreateResult — итог BulkCreateTrackings по одному элементу.
+type CreateResult struct {
+	Tracking *models.Tracking
+	Created  bool // false — трек уже существовал (или повторяет более ранний элемент items)
+}
+
+// BulkCreateTrackings создаёт треки одним multi-row INSERT ... ON CONFLICT DO NOTHING
+// и возвращает результат по каждому элементу items в том же порядке. Созданные треки получают общий
+// номер изменения (см. changes_repo.go).
+func (s *Storage) BulkCreateTrackings(ctx context.Context, items []models.TrackingCreateInput) ([]CreateResult, error) {
+	if len(items) == 0 {
+		return []CreateResult{}, nil
+	}
+
+	type key struct{ carrier, number string }
+	found := make(map[key]CreateResult, len(items))
+	attrs := make(map[key]models.TrackingCreateInput, len(items)) // атрибуты — из первого вхождения ключа
+	pending := make([]key, 0, len(items))
+	for _, it := range items {
+		k := key{it.CarrierCode, it.TrackNumber}
+		if _, ok := found[k]; ok {
+			continue
+		}
+		found[k] = CreateResult{}
+		attrs[k] = it
+		pending = append(pending, k)
+	}
 
 	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
@@ -23,29 +112,119 @@ func (s *Storage) CreateOrGetTrackings(ctx context.Context, items []models.Track
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
-	ids := make([]uint64, 0, len(items))
-	for _, it := range items {
-		var id uint64
-		err := tx.QueryRow(ctx, `
-INSERT INTO trackings (
-  carrier_code, track_number, status, status_raw, next_check_at, created_at, updated_at
+	// Строки, вставленные конкурентной транзакцией после снимка запроса, не видны ни в ins, ни в trackings —
+	// такие ключи добираем повторным запросом (уже с новым снимком: транзакция READ COMMITTED).
+	var createdIDs []uint64
+	for attempt := 0; len(pending) > 0; attempt++ {
+		if attempt == 3 {
+			return nil, errors.Errorf("insert trackings: %d items not resolved", len(pending))
+		}
+		carriers := make([]string, 0, len(pending))
+		numbers := make([]string, 0, len(pending))
+		metadata := make([]*string, 0, len(pending))
+		tags := make([]*string, 0, len(pending))
+		externalIDs := make([]*string, 0, len(pending))
+		for _, k := range pending {
+			it := attrs[k]
+			carriers = append(carriers, k.carrier)
+			numbers = append(numbers, k.number)
+			metadata = append(metadata, it.Metadata)
+			externalIDs = append(externalIDs, it.ExternalID)
+			var tg *string
+			if len(it.Tags) > 0 {
+				b, err := json.Marshal(it.Tags)
+				if err != nil {
+					return nil, errors.Wrap(err, "marshal tags")
+				}
+				v := string(b)
+				tg = &v
+			}
+			tags = append(tags, tg)
+		}
+
+		rows, err := tx.Query(ctx, `
+WITH input AS (
+  SELECT DISTINCT ON (c, n) c, n, m, tg, x, ord
+  FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[]) WITH ORDINALITY AS t(c, n, m, tg, x, ord)
+  ORDER BY c, n, ord
+), ins AS (
+  INSERT INTO trackings (
+    carrier_code, track_number, status, status_raw, next_check_at, created_at, updated_at,
+    metadata, tags, external_id
+  )
+  SELECT c, n, $6::text, $7::text, $8::timestamptz, $8::timestamptz, $8::timestamptz,
+         m::jsonb, COALESCE(ARRAY(SELECT jsonb_array_elements_text(tg::jsonb)), '{}'), x
+  FROM input
+  ORDER BY ord
+  ON CONFLICT (carrier_code, track_number) DO NOTHING
+  RETURNING`+trackingColumns+`
+), counts AS (
+  -- агрегат аналитики: новые треки в начальном статусе (см. analytics_repo.go)
+  INSERT INTO carrier_status_counts (carrier_code, status, trackings)
+  SELECT carrier_code, status, count(*) FROM ins
+  GROUP BY carrier_code, status
+  ORDER BY carrier_code, status
+  ON CONFLICT (carrier_code, status) DO UPDATE SET trackings = carrier_status_counts.trackings + EXCLUDED.trackings
 )
-VALUES ($1,$2,$3,$4,$5,$6,$6)
-ON CONFLICT (carrier_code, track_number)
-DO UPDATE SET updated_at = trackings.updated_at
-RETURNING id
-`, it.CarrierCode, it.TrackNumber, defaultInitialStatus, defaultInitialStatusRaw, now, now).Scan(&id)
+SELECT true, ins.* FROM ins
+UNION ALL
+SELECT false,`+trackingColumns+`
+FROM trackings t
+JOIN input i ON i.c = t.carrier_code AND i.n = t.track_number
+`, carriers, numbers, metadata, tags, externalIDs, defaultInitialStatus, defaultInitialStatusRaw, time.Now().UTC())
 		if err != nil {
-			return nil, errors.Wrap(err, "insert tracking")
+			return nil, errors.Wrap(err, "insert trackings")
+		}
+		for rows.Next() {
+			var created bool
+			t, err := scanTracking(rows, &created)
+			if err != nil {
+				rows.Close()
+				return nil, errors.Wrap(err, "scan tracking")
+			}
+			found[key{t.CarrierCode, t.TrackNumber}] = CreateResult{Tracking: t, Created: created}
+			if created {
+				createdIDs = append(createdIDs, t.ID)
+			}
+		}
+		rows.Close()
+		if rows.Err() != nil {
+			return nil, errors.Wrap(rows.Err(), "rows")
 		}
-		ids = append(ids, id)
-	}
 
+		next := pending[:0]
+		for _, k := range pending {
+			if found[k].Tracking == nil {
+				next = append(next, k)
+			}
+		}
+		pending = next
+	}
+	if len(createdIDs) > 0 {
+		_, err := tx.Exec(ctx, `
+WITH `+bumpChangeSeq+`
+UPDATE trackings SET change_seq = (SELECT seq FROM bump) WHERE id = ANY($1)
+`, createdIDs)
+		if err != nil {
+			return nil, errors.Wrap(err, "mark trackings created")
+		}
+	}
 	if err := tx.Commit(ctx); err != nil {
 		return nil, errors.Wrap(err, "commit tx")
 	}
 
-	return s.GetTrackingsByIDs(ctx, ids)
+	out := make([]CreateResult, 0, len(items))
+	seen := make(map[key]struct{}, len(found))
+	for _, it := range items {
+		k := key{it.CarrierCode, it.TrackNumber}
+		r := found[k]
+		if _, dup := seen[k]; dup {
+			r.Created = false
+		}
+		seen[k] = struct{}{}
+		out = append(out, r)
+	}
+	return out, nil
 }
 
 func (s *Storage) GetTrackingsByIDs(ctx context.Context, ids []uint64) ([]*models.Tracking, error) {
@@ -53,16 +232,7 @@ func (s *Storage) GetTrackingsByIDs(ctx context.Context, ids []uint64) ([]*model
 		return []*models.Tracking{}, nil
 	}
 
-	rows, err := s.db.Query(ctx, `
-SELECT
-  id, carrier_code, track_number,
-  status, status_raw,
-  status_at, last_checked_at, next_check_at,
-  check_fail_count, last_error,
-  created_at, updated_at
-FROM trackings
-WHERE id = ANY($1)
-`, ids)
+	rows, err := s.db.Query(ctx, `SELECT`+trackingColumns+` FROM trackings WHERE id = ANY($1)`, ids)
 	if err != nil {
 		return nil, errors.Wrap(err, "select trackings")
 	}
@@ -70,23 +240,11 @@ WHERE id = ANY($1)
 
 	out := make([]*models.Tracking, 0, len(ids))
 	for rows.Next() {
-		var t models.Tracking
-		var statusAt *time.Time
-		var lastCheckedAt *time.Time
-		var lastError *string
-		if err := rows.Scan(
-			&t.ID, &t.CarrierCode, &t.TrackNumber,
-			&t.Status, &t.StatusRaw,
-			&statusAt, &lastCheckedAt, &t.NextCheckAt,
-			&t.CheckFailCount, &lastError,
-			&t.CreatedAt, &t.UpdatedAt,
-		); err != nil {
+		t, err := scanTracking(rows)
+		if err != nil {
 			return nil, errors.Wrap(err, "scan tracking")
 		}
-		t.StatusAt = statusAt
-		t.LastCheckedAt = lastCheckedAt
-		t.LastError = lastError
-		out = append(out, &t)
+		out = append(out, t)
 	}
 
 	if rows.Err() != nil {
@@ -111,15 +269,11 @@ func (s *Storage) ClaimDueTrackings(ctx context.Context, now time.Time, limit in
 	defer func() { _ = tx.Rollback(ctx) }()
 
 	rows, err := tx.Query(ctx, `
-SELECT
-  id, carrier_code, track_number,
-  status, status_raw,
-  status_at, last_checked_at, next_check_at,
-  check_fail_count, last_error,
-  created_at, updated_at
+SELECT`+trackingColumns+`
 FROM trackings
 WHERE next_check_at <= $1
   AND status <> $2
+  AND NOT EXISTS (SELECT 1 FROM carriers c WHERE c.code = trackings.carrier_code AND NOT c.enabled)
 ORDER BY next_check_at ASC
 LIMIT $3
 FOR UPDATE SKIP LOCKED
@@ -131,23 +285,11 @@ FOR UPDATE SKIP LOCKED
 
 	var picked []*models.Tracking
 	for rows.Next() {
-		var t models.Tracking
-		var statusAt *time.Time
-		var lastCheckedAt *time.Time
-		var lastError *string
-		if err := rows.Scan(
-			&t.ID, &t.CarrierCode, &t.TrackNumber,
-			&t.Status, &t.StatusRaw,
-			&statusAt, &lastCheckedAt, &t.NextCheckAt,
-			&t.CheckFailCount, &lastError,
-			&t.CreatedAt, &t.UpdatedAt,
-		); err != nil {
+		t, err := scanTracking(rows)
+		if err != nil {
 			return nil, errors.Wrap(err, "scan due tracking")
 		}
-		t.StatusAt = statusAt
-		t.LastCheckedAt = lastCheckedAt
-		t.LastError = lastError
-		picked = append(picked, &t)
+		picked = append(picked, t)
 	}
 	if rows.Err() != nil {
 		return nil, errors.Wrap(rows.Err(), "rows")
@@ -169,3 +311,102 @@ FOR UPDATE SKIP LOCKED
 }
 
 
+
+// TrackingFilter — фильтр для ScanTrackings; пустые поля не фильтруют.
+type TrackingFilter struct {
+	CarrierCode  string
+	Status       string
+	UpdatedSince *time.Time
+	Tags         []string // трек должен иметь все перечисленные теги
+	ExternalID   string
+	Metadata     string // JSON-объект: metadata трека должна его содержать (jsonb @>)
+}
+
+// ScanTrackings отдаёт треки по возрастанию id, начиная после afterID (export, replay, ListTrackings).
+func (s *Storage) ScanTrackings(ctx context.Context, f TrackingFilter, afterID uint64, limit int) ([]*models.Tracking, error) {
+	var tags []string
+	if len(f.Tags) > 0 {
+		tags = f.Tags
+	}
+	rows, err := s.db.Query(ctx, `
+SELECT`+trackingColumns+`
+FROM trackings
+WHERE id > $1
+  AND ($2 = '' OR carrier_code = $2)
+  AND ($3 = '' OR status = $3)
+  AND ($4::timestamptz IS NULL OR updated_at >= $4)
+  AND ($6::text[] IS NULL OR tags @> $6::text[])
+  AND ($7 = '' OR external_id = $7)
+  AND ($8::text = '' OR metadata @> NULLIF($8::text, '')::jsonb)
+ORDER BY id ASC
+LIMIT $5
+`, afterID, f.CarrierCode, f.Status, f.UpdatedSince, limit, tags, f.ExternalID, f.Metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "select trackings")
+	}
+	defer rows.Close()
+
+	var out []*models.Tracking
+	for rows.Next() {
+		t, err := scanTracking(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan tracking")
+		}
+		out = append(out, t)
+	}
+	if rows.Err() != nil {
+		return nil, errors.Wrap(rows.Err(), "rows")
+	}
+	return out, nil
+}
+
+// UpdateTracking атомарно применяет изменение пользовательских атрибутов и возвращает трек.
+func (s *Storage) UpdateTracking(ctx context.Context, id uint64, p models.TrackingPatch) (*models.Tracking, error) {
+	var metadata, externalID string
+	if p.Metadata != nil {
+		metadata = *p.Metadata
+	}
+	if p.ExternalID != nil {
+		externalID = *p.ExternalID
+	}
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	t, err := scanTracking(tx.QueryRow(ctx, `
+UPDATE trackings SET
+  metadata = CASE WHEN $2::boolean THEN NULLIF($3::text, '')::jsonb ELSE metadata END,
+  external_id = CASE WHEN $4::boolean THEN NULLIF($5::text, '') ELSE external_id END,
+  tags = ARRAY(
+    SELECT tag
+    FROM unnest(CASE WHEN $6::boolean THEN '{}'::text[] ELSE tags END || $7::text[]) WITH ORDINALITY AS x(tag, ord)
+    WHERE tag <> ALL($8::text[])
+    GROUP BY tag
+    ORDER BY min(ord)
+  ),
+  updated_at = now()
+WHERE id = $1
+RETURNING`+trackingColumns,
+		id, p.Metadata != nil, metadata, p.ExternalID != nil, externalID,
+		p.ClearTags, nonNilStrings(p.AddTags), nonNilStrings(p.RemoveTags)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTrackingNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "update tracking")
+	}
+	if err := markChanged(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	return t, errors.Wrap(tx.Commit(ctx), "commit tx")
+}
+
+// nonNilStrings — пустой массив вместо NULL (с NULL операции над массивами дают NULL).
+func nonNilStrings(v []string) []string {
+	if v == nil {
+		return []string{}
+	}
+	return v
+}
@@ -0,0 +1,253 @@
+// Package tracknumber — справочник перевозчиков, проверка формата трек-номеров,
+// приведение к канонической форме и автоопределение перевозчика по номеру.
+package tracknumber
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrUnknownCarrier = errors.New("unknown carrier")
+	ErrNotDetected    = errors.New("carrier cannot be detected from track number")
+	ErrAmbiguous      = errors.New("track number matches several carriers")
+	ErrDisabled       = errors.New("carrier is disabled")
+)
+
+// Carrier — описание перевозчика в справочнике.
+type Carrier struct {
+	Code    string
+	Name    string
+	Aliases []string // альтернативные написания кода (регистр и пробелы по краям не важны)
+
+	// Validate проверяет канонический номер. nil — формат не проверяется,
+	// и перевозчик не участвует в автоопределении.
+	Validate func(number string) error
+
+	// Disabled — перевозчик отключён: новые номера не принимаются, в автоопределении не участвует.
+	Disabled bool
+}
+
+// Registry — неизменяемый набор перевозчиков; безопасен для конкурентного использования.
+type Registry struct {
+	carriers []Carrier
+	byCode   map[string]int // код и алиасы в верхнем регистре -> индекс в carriers
+}
+
+// NewRegistry строит справочник; коды и алиасы не должны пересекаться.
+func NewRegistry(carriers ...Carrier) (*Registry, error) {
+	r := &Registry{carriers: carriers, byCode: make(map[string]int)}
+	for i, c := range carriers {
+		if CanonicalCarrier(c.Code) == "" {
+			return nil, errors.Errorf("carrier #%d: empty code", i)
+		}
+		for _, name := range append([]string{c.Code}, c.Aliases...) {
+			k := CanonicalCarrier(name)
+			if j, ok := r.byCode[k]; ok {
+				return nil, errors.Errorf("carrier %s: code %q already used by %s", c.Code, name, carriers[j].Code)
+			}
+			r.byCode[k] = i
+		}
+	}
+	return r, nil
+}
+
+var defaultRegistry *Registry
+
+func init() {
+	r, err := NewRegistry(Builtin()...)
+	if err != nil {
+		panic(err)
+	}
+	defaultRegistry = r
+}
+
+// Default — справочник встроенных перевозчиков.
+func Default() *Registry { return defaultRegistry }
+
+// Builtin — перевозчики, с которыми TrackBox умеет работать из коробки.
+func Builtin() []Carrier {
+	return []Carrier{
+		{
+			Code:     "CDEK",
+			Name:     "СДЭК",
+			Aliases:  []string{"СДЭК", "SDEK"},
+			Validate: ValidateCDEK,
+		},
+		{
+			Code:     "POST_RU",
+			Name:     "Почта России",
+			Aliases:  []string{"RUSSIAN_POST", "POCHTA", "ПОЧТА_РОССИИ"},
+			Validate: ValidatePostRU,
+		},
+	}
+}
+
+// Carriers — все перевозчики справочника в порядке регистрации.
+func (r *Registry) Carriers() []Carrier {
+	return append([]Carrier(nil), r.carriers...)
+}
+
+// Lookup ищет перевозчика по коду или алиасу.
+func (r *Registry) Lookup(code string) (Carrier, bool) {
+	i, ok := r.byCode[CanonicalCarrier(code)]
+	if !ok {
+		return Carrier{}, false
+	}
+	return r.carriers[i], true
+}
+
+// Detect определяет перевозчика по каноническому номеру: номер должен подходить ровно одному.
+func (r *Registry) Detect(number string) (Carrier, error) {
+	var found []Carrier
+	for _, c := range r.carriers {
+		if !c.Disabled && c.Validate != nil && c.Validate(number) == nil {
+			found = append(found, c)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Carrier{}, ErrNotDetected
+	case 1:
+		return found[0], nil
+	default:
+		codes := make([]string, 0, len(found))
+		for _, c := range found {
+			codes = append(codes, c.Code)
+		}
+		return Carrier{}, errors.Wrap(ErrAmbiguous, strings.Join(codes, ", "))
+	}
+}
+
+// Normalize приводит пару (перевозчик, номер) к канонической форме и проверяет формат номера.
+// Пустой carrierCode — перевозчик определяется по номеру.
+func (r *Registry) Normalize(carrierCode, number string) (string, string, error) {
+	number = Canonical(number)
+	if number == "" {
+		return "", "", errors.New("track number is required")
+	}
+
+	if strings.TrimSpace(carrierCode) == "" {
+		c, err := r.Detect(number)
+		if err != nil {
+			return "", "", err
+		}
+		return c.Code, number, nil
+	}
+
+	c, ok := r.Lookup(carrierCode)
+	if !ok {
+		return "", "", errors.Wrapf(ErrUnknownCarrier, "%q", strings.TrimSpace(carrierCode))
+	}
+	if c.Disabled {
+		return "", "", errors.Wrap(ErrDisabled, c.Code)
+	}
+	if c.Validate != nil {
+		if err := c.Validate(number); err != nil {
+			return "", "", errors.Wrapf(err, "invalid %s track number", c.Code)
+		}
+	}
+	return c.Code, number, nil
+}
+
+// Canonical — номер без пробельных символов в верхнем регистре ("ra 1234 5678 9ru" -> "RA123456789RU").
+func Canonical(number string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, number)
+}
+
+// CanonicalCarrier — код перевозчика для сравнения: без пробелов по краям, в верхнем регистре, '-' и ' ' -> '_'.
+func CanonicalCarrier(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return '_'
+		}
+		return unicode.ToUpper(r)
+	}, strings.TrimSpace(code))
+}
+
+// ValidateCDEK — номер заказа СДЭК: только цифры, 9–12 знаков.
+func ValidateCDEK(number string) error {
+	if len(number) < 9 || len(number) > 12 || !allDigits(number) {
+		return errors.New("want 9-12 digits")
+	}
+	return nil
+}
+
+// ValidatePostRU — международный номер UPU S10 (RA123456789RU) или внутренний 14-значный ШПИ Почты России.
+func ValidatePostRU(number string) error {
+	if len(number) == 14 && allDigits(number) {
+		return ValidateRPO(number)
+	}
+	return ValidateS10(number)
+}
+
+// s10Weights — веса цифр серийного номера UPU S10.
+var s10Weights = [8]int{8, 6, 4, 2, 3, 5, 9, 7}
+
+// ValidateS10 проверяет формат UPU S10: 2 буквы, 8 цифр серийного номера, контрольная цифра, 2 буквы страны.
+func ValidateS10(number string) error {
+	if len(number) != 13 || !allLetters(number[:2]) || !allDigits(number[2:11]) || !allLetters(number[11:]) {
+		return errors.New("want UPU S10 format AA123456789AA")
+	}
+	sum := 0
+	for i, w := range s10Weights {
+		sum += int(number[2+i]-'0') * w
+	}
+	check := 11 - sum%11
+	switch check {
+	case 10:
+		check = 0
+	case 11:
+		check = 5
+	}
+	if got := int(number[10] - '0'); got != check {
+		return errors.Errorf("bad S10 check digit %d (want %d)", got, check)
+	}
+	return nil
+}
+
+// ValidateRPO проверяет внутренний ШПИ Почты России: 14 цифр, последняя — контрольная
+// (цифры на нечётных позициях с весом 3, на чётных — 1, дополнение суммы до кратного 10).
+func ValidateRPO(number string) error {
+	if len(number) != 14 || !allDigits(number) {
+		return errors.New("want 14 digits")
+	}
+	sum := 0
+	for i := 0; i < 13; i++ {
+		d := int(number[i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	check := (10 - sum%10) % 10
+	if got := int(number[13] - '0'); got != check {
+		return errors.Errorf("bad check digit %d (want %d)", got, check)
+	}
+	return nil
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func allLetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return s != ""
+}
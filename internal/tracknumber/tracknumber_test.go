@@ -0,0 +1,101 @@
+package tracknumber
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateS10(t *testing.T) {
+	require.NoError(t, ValidateS10("RA123456785RU"))
+	require.NoError(t, ValidateS10("LP876543216CN"))
+
+	require.ErrorContains(t, ValidateS10("RA123456789RU"), "check digit")
+	require.Error(t, ValidateS10("RA12345678RU"))
+	require.Error(t, ValidateS10("1A123456785RU"))
+	require.Error(t, ValidateS10("RA123456785R1"))
+}
+
+func TestValidatePostRU_Domestic(t *testing.T) {
+	require.NoError(t, ValidatePostRU("10100100000010"))
+	require.ErrorContains(t, ValidatePostRU("10100100000011"), "check digit")
+}
+
+func TestValidateCDEK(t *testing.T) {
+	require.NoError(t, ValidateCDEK("1234567890"))
+	require.Error(t, ValidateCDEK("12345678"))
+	require.Error(t, ValidateCDEK("12345A7890"))
+}
+
+func TestNormalize(t *testing.T) {
+	r := Default()
+
+	tests := []struct {
+		name            string
+		carrier, number string
+		wantCarrier     string
+		wantNumber      string
+		err             string
+	}{
+		{name: "canonical", carrier: "CDEK", number: "1234567890", wantCarrier: "CDEK", wantNumber: "1234567890"},
+		{name: "case and spaces", carrier: " cdek ", number: " 1234 567 890 ", wantCarrier: "CDEK", wantNumber: "1234567890"},
+		{name: "alias", carrier: "сдэк", number: "1234567890", wantCarrier: "CDEK", wantNumber: "1234567890"},
+		{name: "dash alias", carrier: "russian-post", number: "ra123456785ru", wantCarrier: "POST_RU", wantNumber: "RA123456785RU"},
+		{name: "detect S10", number: "RA123456785RU", wantCarrier: "POST_RU", wantNumber: "RA123456785RU"},
+		{name: "detect CDEK", number: "1234567890", wantCarrier: "CDEK", wantNumber: "1234567890"},
+		{name: "detect fails", number: "XYZ", err: "cannot be detected"},
+		{name: "unknown carrier", carrier: "DHL", number: "1234567890", err: `"DHL": unknown carrier`},
+		{name: "bad number", carrier: "POST_RU", number: "RA123456789RU", err: "invalid POST_RU track number: bad S10 check digit"},
+		{name: "empty number", carrier: "CDEK", number: "  ", err: "track number is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, n, err := r.Normalize(tt.carrier, tt.number)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCarrier, c)
+			require.Equal(t, tt.wantNumber, n)
+		})
+	}
+}
+
+func TestNewRegistry(t *testing.T) {
+	_, err := NewRegistry(Carrier{Code: "A"}, Carrier{Code: "B", Aliases: []string{"a"}})
+	require.ErrorContains(t, err, "already used by A")
+
+	_, err = NewRegistry(Carrier{Code: " "})
+	require.Error(t, err)
+
+	accept := func(string) error { return nil }
+	r, err := NewRegistry(Carrier{Code: "A", Validate: accept}, Carrier{Code: "B", Validate: accept}, Carrier{Code: "C"})
+	require.NoError(t, err)
+	_, err = r.Detect("X")
+	require.ErrorIs(t, err, ErrAmbiguous)
+	require.ErrorContains(t, err, "A, B")
+
+	// без валидатора номер не проверяется
+	c, n, err := r.Normalize("c", "anything")
+	require.NoError(t, err)
+	require.Equal(t, "C", c)
+	require.Equal(t, "ANYTHING", n)
+}
+
+func TestNormalize_Disabled(t *testing.T) {
+	carriers := Builtin()
+	carriers[0].Disabled = true // CDEK
+	r, err := NewRegistry(carriers...)
+	require.NoError(t, err)
+
+	_, _, err = r.Normalize("CDEK", "1234567890")
+	require.ErrorIs(t, err, ErrDisabled)
+
+	_, _, err = r.Normalize("", "1234567890")
+	require.ErrorIs(t, err, ErrNotDetected)
+
+	c, _, err := r.Normalize("", "RA123456785RU")
+	require.NoError(t, err)
+	require.Equal(t, "POST_RU", c)
+}
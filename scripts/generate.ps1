@@ -25,7 +25,7 @@ protoc -I ./api `
   -I ./api/google/api `
   --go_out=./internal/pb --go_opt=paths=source_relative `
   --go-grpc_out=./internal/pb --go-grpc_opt=paths=source_relative `
-  ./api/trackings_api/trackings.proto ./api/models/tracking_model.proto
+  ./api/trackings_api/trackings.proto ./api/models/tracking_model.proto ./api/worker_api/worker.proto ./api/carriers_api/carriers.proto ./api/alerts_api/alerts.proto ./api/analytics_api/analytics.proto
 
 # grpc-gateway
 Write-Host "[generate] grpc-gateway..."
@@ -34,7 +34,7 @@ protoc -I ./api `
   --grpc-gateway_out=./internal/pb `
   --grpc-gateway_opt paths=source_relative `
   --grpc-gateway_opt logtostderr=true `
-  ./api/trackings_api/trackings.proto
+  ./api/trackings_api/trackings.proto ./api/carriers_api/carriers.proto ./api/alerts_api/alerts.proto ./api/analytics_api/analytics.proto
 
 # openapi v2 (swagger)
 Write-Host "[generate] openapi (swagger)..."
@@ -42,7 +42,7 @@ protoc -I ./api `
   -I ./api/google/api `
   --openapiv2_out=./internal/pb/swagger `
   --openapiv2_opt logtostderr=true `
-  ./api/trackings_api/trackings.proto
+  ./api/trackings_api/trackings.proto ./api/carriers_api/carriers.proto ./api/alerts_api/alerts.proto ./api/analytics_api/analytics.proto
 
 # patch swagger for better Swagger UI UX (no body for /refresh, numeric ids for get-by-ids)
 Write-Host "[generate] patch swagger..."